@@ -16,949 +16,960 @@
 
 package taygete
 
-func kind(n int) schar {
-	if n > 0 && n < MAX_BOXES && teg.globals.bx[n] != nil {
-		return teg.globals.bx[n].kind
+func (e *Engine) kind(n int) schar {
+	if n > 0 && n < MAX_BOXES && e.globals.bx.get(n) != nil {
+		return e.globals.bx.get(n).kind
 	}
 	return T_deleted
 }
 
-func subkind(n int) schar {
-	if teg.globals.bx[n] != nil {
-		return teg.globals.bx[n].skind
+func (e *Engine) subkind(n int) schar {
+	if e.globals.bx.get(n) != nil {
+		return e.globals.bx.get(n).skind
 	}
 	return 0
 }
 
-func valid_box(n int) bool {
-	return kind(n) != T_deleted
+func (e *Engine) valid_box(n int) bool {
+	return e.kind(n) != T_deleted
 }
 
-func kind_first(n int) int {
-	return teg.globals.box_head[n]
+func (e *Engine) kind_first(n int) int {
+	return e.globals.box_head[n]
 }
 
-func kind_next(n int) int {
-	return teg.globals.bx[n].x_next_kind
+func (e *Engine) kind_next(n int) int {
+	return e.globals.bx.get(n).x_next_kind
 }
 
-func sub_first(n int) int {
-	return teg.globals.sub_head[n]
+func (e *Engine) sub_first(n int) int {
+	return e.globals.sub_head[n]
 }
 
-func sub_next(n int) int {
-	return teg.globals.bx[n].x_next_sub
+func (e *Engine) sub_next(n int) int {
+	return e.globals.bx.get(n).x_next_sub
 }
 
-func rp_loc_info(n int) *loc_info {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) rp_loc_info(n int) *loc_info {
+	if e.globals.bx.get(n) == nil {
 		return nil
 	}
-	return &teg.globals.bx[n].x_loc_info
+	return &e.globals.bx.get(n).x_loc_info
 }
 
-func rp_char(n int) *entity_char {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) rp_char(n int) *entity_char {
+	if e.globals.bx.get(n) == nil {
 		return nil
 	}
-	return teg.globals.bx[n].x_char
+	return e.globals.bx.get(n).x_char
 }
 
-func rp_loc(n int) *entity_loc {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) rp_loc(n int) *entity_loc {
+	if e.globals.bx.get(n) == nil {
 		return nil
 	}
-	return teg.globals.bx[n].x_loc
+	return e.globals.bx.get(n).x_loc
 }
 
-func rp_subloc(n int) *entity_subloc {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) rp_subloc(n int) *entity_subloc {
+	if e.globals.bx.get(n) == nil {
 		return nil
 	}
-	return teg.globals.bx[n].x_subloc
+	return e.globals.bx.get(n).x_subloc
 }
 
-func rp_item(n int) *entity_item {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) rp_item(n int) *entity_item {
+	if e.globals.bx.get(n) == nil {
 		return nil
 	}
-	return teg.globals.bx[n].x_item
+	return e.globals.bx.get(n).x_item
 }
 
-func rp_player(n int) *entity_player {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) rp_player(n int) *entity_player {
+	if e.globals.bx.get(n) == nil {
 		return nil
 	}
-	return teg.globals.bx[n].x_player
+	return e.globals.bx.get(n).x_player
 }
 
-func rp_skill(n int) *entity_skill {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) rp_skill(n int) *entity_skill {
+	if e.globals.bx.get(n) == nil {
 		return nil
 	}
-	return teg.globals.bx[n].x_skill
+	return e.globals.bx.get(n).x_skill
 }
 
-func rp_gate(n int) *entity_gate {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) rp_gate(n int) *entity_gate {
+	if e.globals.bx.get(n) == nil {
 		return nil
 	}
-	return teg.globals.bx[n].x_gate
+	return e.globals.bx.get(n).x_gate
 }
 
-func rp_misc(n int) *entity_misc {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) rp_misc(n int) *entity_misc {
+	if e.globals.bx.get(n) == nil {
 		return nil
 	}
-	return teg.globals.bx[n].x_misc
+	return e.globals.bx.get(n).x_misc
 }
 
-func rp_disp(n int) *att_ent {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) rp_disp(n int) *att_ent {
+	if e.globals.bx.get(n) == nil {
 		return nil
 	}
-	return teg.globals.bx[n].x_disp
+	return e.globals.bx.get(n).x_disp
 }
 
-func rp_command(n int) *command {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) rp_command(n int) *command {
+	if e.globals.bx.get(n) == nil {
 		return nil
 	}
-	return teg.globals.bx[n].cmd
+	return e.globals.bx.get(n).cmd
 }
 
-func rp_magic(n int) *char_magic {
-	c := rp_char(n)
+func (e *Engine) rp_magic(n int) *char_magic {
+	c := e.rp_char(n)
 	if c == nil {
 		return nil
 	}
 	return c.x_char_magic
 }
 
-func rp_item_magic(n int) *item_magic {
-	it := rp_item(n)
+func (e *Engine) rp_item_magic(n int) *item_magic {
+	it := e.rp_item(n)
 	if it == nil {
 		return nil
 	}
 	return it.x_item_magic
 }
 
-func p_loc_info(n int) *loc_info {
-	if teg.globals.bx[n] == nil {
-		teg.globals.bx[n] = &box{}
+func (e *Engine) p_loc_info(n int) *loc_info {
+	e.markDirty(n)
+	if e.globals.bx.get(n) == nil {
+		e.setBox(n, &box{})
 	}
-	return &teg.globals.bx[n].x_loc_info
+	return &e.globals.bx.get(n).x_loc_info
 }
 
-func p_char(n int) *entity_char {
-	if teg.globals.bx[n] == nil {
-		teg.globals.bx[n] = &box{}
+func (e *Engine) p_char(n int) *entity_char {
+	e.markDirty(n)
+	if e.globals.bx.get(n) == nil {
+		e.setBox(n, &box{})
 	}
-	if teg.globals.bx[n].x_char == nil {
-		teg.globals.bx[n].x_char = &entity_char{}
+	if e.globals.bx.get(n).x_char == nil {
+		e.globals.bx.get(n).x_char = &entity_char{}
 	}
-	return teg.globals.bx[n].x_char
+	return e.globals.bx.get(n).x_char
 }
 
-func p_loc(n int) *entity_loc {
-	if teg.globals.bx[n] == nil {
-		teg.globals.bx[n] = &box{}
+func (e *Engine) p_loc(n int) *entity_loc {
+	e.markDirty(n)
+	if e.globals.bx.get(n) == nil {
+		e.setBox(n, &box{})
 	}
-	if teg.globals.bx[n].x_loc == nil {
-		teg.globals.bx[n].x_loc = &entity_loc{}
+	if e.globals.bx.get(n).x_loc == nil {
+		e.globals.bx.get(n).x_loc = &entity_loc{}
 	}
-	return teg.globals.bx[n].x_loc
+	return e.globals.bx.get(n).x_loc
 }
 
-func p_subloc(n int) *entity_subloc {
-	if teg.globals.bx[n] == nil {
-		teg.globals.bx[n] = &box{}
+func (e *Engine) p_subloc(n int) *entity_subloc {
+	e.markDirty(n)
+	if e.globals.bx.get(n) == nil {
+		e.setBox(n, &box{})
 	}
-	if teg.globals.bx[n].x_subloc == nil {
-		teg.globals.bx[n].x_subloc = &entity_subloc{}
+	if e.globals.bx.get(n).x_subloc == nil {
+		e.globals.bx.get(n).x_subloc = &entity_subloc{}
 	}
-	return teg.globals.bx[n].x_subloc
+	return e.globals.bx.get(n).x_subloc
 }
 
-func p_item(n int) *entity_item {
-	if teg.globals.bx[n] == nil {
-		teg.globals.bx[n] = &box{}
+func (e *Engine) p_item(n int) *entity_item {
+	e.markDirty(n)
+	if e.globals.bx.get(n) == nil {
+		e.setBox(n, &box{})
 	}
-	if teg.globals.bx[n].x_item == nil {
-		teg.globals.bx[n].x_item = &entity_item{}
+	if e.globals.bx.get(n).x_item == nil {
+		e.globals.bx.get(n).x_item = &entity_item{}
 	}
-	return teg.globals.bx[n].x_item
+	return e.globals.bx.get(n).x_item
 }
 
-func p_player(n int) *entity_player {
-	if teg.globals.bx[n] == nil {
-		teg.globals.bx[n] = &box{}
+func (e *Engine) p_player(n int) *entity_player {
+	e.markDirty(n)
+	if e.globals.bx.get(n) == nil {
+		e.setBox(n, &box{})
 	}
-	if teg.globals.bx[n].x_player == nil {
-		teg.globals.bx[n].x_player = &entity_player{}
+	if e.globals.bx.get(n).x_player == nil {
+		e.globals.bx.get(n).x_player = &entity_player{}
 	}
-	return teg.globals.bx[n].x_player
+	return e.globals.bx.get(n).x_player
 }
 
-func p_skill(n int) *entity_skill {
-	if teg.globals.bx[n] == nil {
-		teg.globals.bx[n] = &box{}
+func (e *Engine) p_skill(n int) *entity_skill {
+	e.markDirty(n)
+	if e.globals.bx.get(n) == nil {
+		e.setBox(n, &box{})
 	}
-	if teg.globals.bx[n].x_skill == nil {
-		teg.globals.bx[n].x_skill = &entity_skill{}
+	if e.globals.bx.get(n).x_skill == nil {
+		e.globals.bx.get(n).x_skill = &entity_skill{}
 	}
-	return teg.globals.bx[n].x_skill
+	return e.globals.bx.get(n).x_skill
 }
 
-func p_gate(n int) *entity_gate {
-	if teg.globals.bx[n] == nil {
-		teg.globals.bx[n] = &box{}
+func (e *Engine) p_gate(n int) *entity_gate {
+	e.markDirty(n)
+	if e.globals.bx.get(n) == nil {
+		e.setBox(n, &box{})
 	}
-	if teg.globals.bx[n].x_gate == nil {
-		teg.globals.bx[n].x_gate = &entity_gate{}
+	if e.globals.bx.get(n).x_gate == nil {
+		e.globals.bx.get(n).x_gate = &entity_gate{}
 	}
-	return teg.globals.bx[n].x_gate
+	return e.globals.bx.get(n).x_gate
 }
 
-func p_misc(n int) *entity_misc {
-	if teg.globals.bx[n] == nil {
-		teg.globals.bx[n] = &box{}
+func (e *Engine) p_misc(n int) *entity_misc {
+	e.markDirty(n)
+	if e.globals.bx.get(n) == nil {
+		e.setBox(n, &box{})
 	}
-	if teg.globals.bx[n].x_misc == nil {
-		teg.globals.bx[n].x_misc = &entity_misc{}
+	if e.globals.bx.get(n).x_misc == nil {
+		e.globals.bx.get(n).x_misc = &entity_misc{}
 	}
-	return teg.globals.bx[n].x_misc
+	return e.globals.bx.get(n).x_misc
 }
 
-func p_disp(n int) *att_ent {
-	if teg.globals.bx[n] == nil {
-		teg.globals.bx[n] = &box{}
+func (e *Engine) p_disp(n int) *att_ent {
+	e.markDirty(n)
+	if e.globals.bx.get(n) == nil {
+		e.setBox(n, &box{})
 	}
-	if teg.globals.bx[n].x_disp == nil {
-		teg.globals.bx[n].x_disp = &att_ent{}
+	if e.globals.bx.get(n).x_disp == nil {
+		e.globals.bx.get(n).x_disp = &att_ent{}
 	}
-	return teg.globals.bx[n].x_disp
+	return e.globals.bx.get(n).x_disp
 }
 
-func p_command(n int) *command {
-	if teg.globals.bx[n] == nil {
-		teg.globals.bx[n] = &box{}
+func (e *Engine) p_command(n int) *command {
+	e.markDirty(n)
+	if e.globals.bx.get(n) == nil {
+		e.setBox(n, &box{})
 	}
-	if teg.globals.bx[n].cmd == nil {
-		teg.globals.bx[n].cmd = &command{}
+	if e.globals.bx.get(n).cmd == nil {
+		e.globals.bx.get(n).cmd = &command{who: n}
 	}
-	return teg.globals.bx[n].cmd
+	return e.globals.bx.get(n).cmd
 }
 
-func p_magic(n int) *char_magic {
-	c := p_char(n)
+func (e *Engine) p_magic(n int) *char_magic {
+	c := e.p_char(n)
 	if c.x_char_magic == nil {
 		c.x_char_magic = &char_magic{}
 	}
 	return c.x_char_magic
 }
 
-func p_item_magic(n int) *item_magic {
-	it := p_item(n)
+func (e *Engine) p_item_magic(n int) *item_magic {
+	it := e.p_item(n)
 	if it.x_item_magic == nil {
 		it.x_item_magic = &item_magic{}
 	}
 	return it.x_item_magic
 }
 
-func loc(n int) int {
-	li := rp_loc_info(n)
+func (e *Engine) loc(n int) int {
+	li := e.rp_loc_info(n)
 	if li == nil {
 		return 0
 	}
 	return li.where
 }
 
-func is_loc_or_ship(n int) bool {
-	k := kind(n)
+func (e *Engine) is_loc_or_ship(n int) bool {
+	k := e.kind(n)
 	return k == T_loc || k == T_ship
 }
 
-func is_ship(n int) bool {
-	sk := subkind(n)
+func (e *Engine) is_ship(n int) bool {
+	sk := e.subkind(n)
 	return sk == sub_galley || sk == sub_roundship || sk == sub_raft
 }
 
-func is_ship_notdone(n int) bool {
-	sk := subkind(n)
+func (e *Engine) is_ship_notdone(n int) bool {
+	sk := e.subkind(n)
 	return sk == sub_galley_notdone || sk == sub_roundship_notdone || sk == sub_raft_notdone
 }
 
-func is_ship_either(n int) bool {
-	return is_ship(n) || is_ship_notdone(n)
+func (e *Engine) is_ship_either(n int) bool {
+	return e.is_ship(n) || e.is_ship_notdone(n)
 }
 
-func char_health(n int) schar {
-	c := rp_char(n)
+func (e *Engine) char_health(n int) schar {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.health
 }
 
-func char_sick(n int) schar {
-	c := rp_char(n)
+func (e *Engine) char_sick(n int) schar {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.sick
 }
 
-func char_guard(n int) schar {
-	c := rp_char(n)
+func (e *Engine) char_guard(n int) schar {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.guard
 }
 
-func loyal_kind(n int) schar {
-	c := rp_char(n)
+func (e *Engine) loyal_kind(n int) schar {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.loy_kind
 }
 
-func loyal_rate(n int) int {
-	c := rp_char(n)
+func (e *Engine) loyal_rate(n int) int {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.loy_rate
 }
 
-func noble_item(n int) schar {
-	c := rp_char(n)
+func (e *Engine) noble_item(n int) schar {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.unit_item
 }
 
-func char_behind(n int) schar {
-	c := rp_char(n)
+func (e *Engine) char_behind(n int) schar {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.behind
 }
 
-func npc_program(n int) schar {
-	c := rp_char(n)
+func (e *Engine) npc_program(n int) schar {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.npc_prog
 }
 
-func char_studied(n int) schar {
-	c := rp_char(n)
+func (e *Engine) char_studied(n int) schar {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.studied
 }
 
-func char_moving(n int) int {
-	c := rp_char(n)
+func (e *Engine) char_moving(n int) int {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.moving
 }
 
-func char_attack(n int) short {
-	c := rp_char(n)
+func (e *Engine) char_attack(n int) short {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.attack
 }
 
-func char_defense(n int) short {
-	c := rp_char(n)
+func (e *Engine) char_defense(n int) short {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.defense
 }
 
-func char_missile(n int) short {
-	c := rp_char(n)
+func (e *Engine) char_missile(n int) short {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.missile
 }
 
-func char_rank(n int) schar {
-	c := rp_char(n)
+func (e *Engine) char_rank(n int) schar {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.rank
 }
 
-func char_break(n int) schar {
-	c := rp_char(n)
+func (e *Engine) char_break(n int) schar {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.break_point
 }
 
-func char_pray(n int) schar {
-	m := rp_magic(n)
+func (e *Engine) char_pray(n int) schar {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.pray
 }
 
-func char_hidden(n int) schar {
-	m := rp_magic(n)
+func (e *Engine) char_hidden(n int) schar {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.hide_self
 }
 
-func vision_protect(n int) schar {
-	m := rp_magic(n)
+func (e *Engine) vision_protect(n int) schar {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.vis_protect
 }
 
-func default_garrison(n int) schar {
-	m := rp_magic(n)
+func (e *Engine) default_garrison(n int) schar {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.default_garr
 }
 
-func char_hide_mage(n int) schar {
-	m := rp_magic(n)
+func (e *Engine) char_hide_mage(n int) schar {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.hide_mage
 }
 
-func char_cur_aura(n int) int {
-	m := rp_magic(n)
+func (e *Engine) char_cur_aura(n int) int {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.cur_aura
 }
 
-func char_max_aura(n int) int {
-	m := rp_magic(n)
+func (e *Engine) char_max_aura(n int) int {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.max_aura
 }
 
-func reflect_blast(n int) schar {
-	m := rp_magic(n)
+func (e *Engine) reflect_blast(n int) schar {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.aura_reflect
 }
 
-func char_pledge(n int) int {
-	m := rp_magic(n)
+func (e *Engine) char_pledge(n int) int {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.pledge
 }
 
-func char_auraculum(n int) int {
-	m := rp_magic(n)
+func (e *Engine) char_auraculum(n int) int {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.auraculum
 }
 
-func char_abil_shroud(n int) short {
-	m := rp_magic(n)
+func (e *Engine) char_abil_shroud(n int) short {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.ability_shroud
 }
 
-func board_fee(n int) int {
-	m := rp_magic(n)
+func (e *Engine) board_fee(n int) int {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.fee
 }
 
-func ferry_horn(n int) schar {
-	m := rp_magic(n)
+func (e *Engine) ferry_horn(n int) schar {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.ferry_flag
 }
 
-func char_proj_cast(n int) int {
-	m := rp_magic(n)
+func (e *Engine) char_proj_cast(n int) int {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.project_cast
 }
 
-func char_quick_cast(n int) short {
-	m := rp_magic(n)
+func (e *Engine) char_quick_cast(n int) short {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.quick_cast
 }
 
-func is_magician(n int) schar {
-	m := rp_magic(n)
+func (e *Engine) is_magician(n int) schar {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.magician
 }
 
-func weather_mage(n int) schar {
-	m := rp_magic(n)
+func (e *Engine) weather_mage(n int) schar {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.knows_weather
 }
 
-func loc_barrier(n int) short {
-	l := rp_loc(n)
+func (e *Engine) loc_barrier(n int) int {
+	l := e.rp_loc(n)
 	if l == nil {
 		return 0
 	}
 	return l.barrier
 }
 
-func loc_shroud(n int) short {
-	l := rp_loc(n)
+func (e *Engine) loc_shroud(n int) short {
+	l := e.rp_loc(n)
 	if l == nil {
 		return 0
 	}
 	return l.shroud
 }
 
-func loc_civ(n int) schar {
-	l := rp_loc(n)
+func (e *Engine) loc_civ(n int) schar {
+	l := e.rp_loc(n)
 	if l == nil {
 		return 0
 	}
 	return l.civ
 }
 
-func loc_sea_lane(n int) schar {
-	l := rp_loc(n)
+func (e *Engine) loc_sea_lane(n int) schar {
+	l := e.rp_loc(n)
 	if l == nil {
 		return 0
 	}
 	return l.sea_lane
 }
 
-func gate_dist(n int) schar {
-	l := rp_loc(n)
+func (e *Engine) gate_dist(n int) schar {
+	l := e.rp_loc(n)
 	if l == nil {
 		return 0
 	}
 	return l.dist_from_gate
 }
 
-func loc_prominence(n int) schar {
-	sl := rp_subloc(n)
+func (e *Engine) loc_prominence(n int) schar {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.prominence
 }
 
-func loc_opium(n int) int {
-	sl := rp_subloc(n)
+func (e *Engine) loc_opium(n int) int {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.opium_econ
 }
 
-func ship_cap_raw(n int) int {
-	sl := rp_subloc(n)
+func (e *Engine) ship_cap_raw(n int) int {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.capacity
 }
 
-func ship_moving(n int) int {
-	sl := rp_subloc(n)
+func (e *Engine) ship_moving(n int) int {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.moving
 }
 
-func loc_damage(n int) uchar {
-	sl := rp_subloc(n)
+func (e *Engine) loc_damage(n int) uchar {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.damage
 }
 
-func loc_defense(n int) int {
-	sl := rp_subloc(n)
+func (e *Engine) loc_defense(n int) int {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.defense
 }
 
-func loc_pillage(n int) schar {
-	sl := rp_subloc(n)
+func (e *Engine) loc_pillage(n int) schar {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.loot
 }
 
-func recent_pillage(n int) schar {
-	sl := rp_subloc(n)
+func (e *Engine) recent_pillage(n int) schar {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.recent_loot
 }
 
-func safe_haven(n int) schar {
-	sl := rp_subloc(n)
+func (e *Engine) safe_haven(n int) schar {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.safe
 }
 
-func major_city(n int) schar {
-	sl := rp_subloc(n)
+func (e *Engine) major_city(n int) schar {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.major
 }
 
-func uldim(n int) schar {
-	sl := rp_subloc(n)
+func (e *Engine) uldim(n int) schar {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.uldim_flag
 }
 
-func summerbridge(n int) schar {
-	sl := rp_subloc(n)
+func (e *Engine) summerbridge(n int) schar {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.summer_flag
 }
 
-func subloc_quest(n int) schar {
-	sl := rp_subloc(n)
+func (e *Engine) subloc_quest(n int) schar {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.quest_late
 }
 
-func tunnel_depth(n int) schar {
-	sl := rp_subloc(n)
+func (e *Engine) tunnel_depth(n int) schar {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.tunnel_level
 }
 
-func mine_depth(n int) short {
-	sl := rp_subloc(n)
+func (e *Engine) mine_depth(n int) short {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.shaft_depth / 3
 }
 
-func castle_level(n int) schar {
-	sl := rp_subloc(n)
+func (e *Engine) castle_level(n int) schar {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.castle_lev
 }
 
-func ship_has_ram(n int) schar {
-	sl := rp_subloc(n)
+func (e *Engine) ship_has_ram(n int) schar {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.galley_ram
 }
 
-func loc_link_open(n int) schar {
-	sl := rp_subloc(n)
+func (e *Engine) loc_link_open(n int) schar {
+	sl := e.rp_subloc(n)
 	if sl == nil {
 		return 0
 	}
 	return sl.link_open
 }
 
-func road_dest(n int) int {
-	g := rp_gate(n)
+func (e *Engine) road_dest(n int) int {
+	g := e.rp_gate(n)
 	if g == nil {
 		return 0
 	}
 	return g.to_loc
 }
 
-func road_hidden(n int) schar {
-	g := rp_gate(n)
+func (e *Engine) road_hidden(n int) schar {
+	g := e.rp_gate(n)
 	if g == nil {
 		return 0
 	}
 	return g.road_hidden
 }
 
-func gate_seal(n int) short {
-	g := rp_gate(n)
+func (e *Engine) gate_seal(n int) short {
+	g := e.rp_gate(n)
 	if g == nil {
 		return 0
 	}
 	return g.seal_key
 }
 
-func gate_dest(n int) int {
-	g := rp_gate(n)
+func (e *Engine) gate_dest(n int) int {
+	g := e.rp_gate(n)
 	if g == nil {
 		return 0
 	}
 	return g.to_loc
 }
 
-func item_animal(n int) schar {
-	it := rp_item(n)
+func (e *Engine) item_animal(n int) schar {
+	it := e.rp_item(n)
 	if it == nil {
 		return 0
 	}
 	return it.animal
 }
 
-func item_prominent(n int) schar {
-	it := rp_item(n)
+func (e *Engine) item_prominent(n int) schar {
+	it := e.rp_item(n)
 	if it == nil {
 		return 0
 	}
 	return it.prominent
 }
 
-func item_weight(n int) short {
-	it := rp_item(n)
+func (e *Engine) item_weight(n int) short {
+	it := e.rp_item(n)
 	if it == nil {
 		return 0
 	}
 	return it.weight
 }
 
-func item_price(n int) int {
-	it := rp_item(n)
+func (e *Engine) item_price(n int) int {
+	it := e.rp_item(n)
 	if it == nil {
 		return 0
 	}
 	return it.base_price
 }
 
-func item_unique(n int) int {
-	it := rp_item(n)
+func (e *Engine) item_unique(n int) int {
+	it := e.rp_item(n)
 	if it == nil {
 		return 0
 	}
 	return it.who_has
 }
 
-func item_land_cap(n int) short {
-	it := rp_item(n)
+func (e *Engine) item_land_cap(n int) short {
+	it := e.rp_item(n)
 	if it == nil {
 		return 0
 	}
 	return it.land_cap
 }
 
-func item_ride_cap(n int) short {
-	it := rp_item(n)
+func (e *Engine) item_ride_cap(n int) short {
+	it := e.rp_item(n)
 	if it == nil {
 		return 0
 	}
 	return it.ride_cap
 }
 
-func item_fly_cap(n int) short {
-	it := rp_item(n)
+func (e *Engine) item_fly_cap(n int) short {
+	it := e.rp_item(n)
 	if it == nil {
 		return 0
 	}
 	return it.fly_cap
 }
 
-func item_attack(n int) short {
-	it := rp_item(n)
+func (e *Engine) item_attack(n int) short {
+	it := e.rp_item(n)
 	if it == nil {
 		return 0
 	}
 	return it.attack
 }
 
-func item_defense(n int) short {
-	it := rp_item(n)
+func (e *Engine) item_defense(n int) short {
+	it := e.rp_item(n)
 	if it == nil {
 		return 0
 	}
 	return it.defense
 }
 
-func item_missile(n int) short {
-	it := rp_item(n)
+func (e *Engine) item_missile(n int) short {
+	it := e.rp_item(n)
 	if it == nil {
 		return 0
 	}
 	return it.missile
 }
 
-func man_item(n int) schar {
-	it := rp_item(n)
+func (e *Engine) man_item(n int) schar {
+	it := e.rp_item(n)
 	if it == nil {
 		return 0
 	}
 	return it.is_man_item
 }
 
-func item_capturable(n int) schar {
-	it := rp_item(n)
+func (e *Engine) item_capturable(n int) schar {
+	it := e.rp_item(n)
 	if it == nil {
 		return 0
 	}
 	return it.capturable
 }
 
-func player_np(n int) short {
-	p := rp_player(n)
+func (e *Engine) player_np(n int) short {
+	p := e.rp_player(n)
 	if p == nil {
 		return 0
 	}
 	return p.noble_points
 }
 
-func player_fast_study(n int) short {
-	p := rp_player(n)
+func (e *Engine) player_fast_study(n int) short {
+	p := e.rp_player(n)
 	if p == nil {
 		return 0
 	}
 	return p.fast_study
 }
 
-func player_email(n int) *char {
-	p := rp_player(n)
+func (e *Engine) player_email(n int) string {
+	p := e.rp_player(n)
 	if p == nil {
-		return nil
+		return ""
 	}
 	return p.email
 }
 
-func times_paid(n int) char {
-	p := rp_player(n)
+func (e *Engine) times_paid(n int) char {
+	p := e.rp_player(n)
 	if p == nil {
 		return 0
 	}
 	return p.times_paid
 }
 
-func player_public_turn(n int) char {
-	p := rp_player(n)
+func (e *Engine) player_public_turn(n int) char {
+	p := e.rp_player(n)
 	if p == nil {
 		return 0
 	}
 	return p.public_turn
 }
 
-func player_format(n int) schar {
-	p := rp_player(n)
+func (e *Engine) player_format(n int) schar {
+	p := e.rp_player(n)
 	if p == nil {
 		return 0
 	}
 	return p.format
 }
 
-func player_notab(n int) schar {
-	p := rp_player(n)
+func (e *Engine) player_notab(n int) schar {
+	p := e.rp_player(n)
 	if p == nil {
 		return 0
 	}
 	return p.notab
 }
 
-func req_skill(n int) int {
-	sk := rp_skill(n)
+func (e *Engine) req_skill(n int) int {
+	sk := e.rp_skill(n)
 	if sk == nil {
 		return 0
 	}
 	return sk.required_skill
 }
 
-func skill_produce(n int) int {
-	sk := rp_skill(n)
+func (e *Engine) skill_produce(n int) int {
+	sk := e.rp_skill(n)
 	if sk == nil {
 		return 0
 	}
 	return sk.produced
 }
 
-func skill_no_exp(n int) int {
-	sk := rp_skill(n)
+func (e *Engine) skill_no_exp(n int) int {
+	sk := e.rp_skill(n)
 	if sk == nil {
 		return 0
 	}
 	return sk.no_exp
 }
 
-func skill_np_req(n int) int {
-	sk := rp_skill(n)
+func (e *Engine) skill_np_req(n int) int {
+	sk := e.rp_skill(n)
 	if sk == nil {
 		return 0
 	}
 	return sk.np_req
 }
 
-func learn_time(n int) int {
-	sk := rp_skill(n)
+func (e *Engine) learn_time(n int) int {
+	sk := e.rp_skill(n)
 	if sk == nil {
 		return 0
 	}
@@ -968,9 +979,9 @@ func learn_time(n int) int {
 // skill_school returns the root school/category for a skill.
 // If the skill has no parent (req_skill), it returns itself.
 // Ported from src/use.c skill_school().
-func skill_school(sk int) int {
+func (e *Engine) skill_school(sk int) int {
 	for count := 0; count < 1000; count++ {
-		n := req_skill(sk)
+		n := e.req_skill(sk)
 		if n == 0 {
 			return sk
 		}
@@ -981,236 +992,236 @@ func skill_school(sk int) int {
 
 // magic_skill returns true if the skill belongs to a magic school.
 // Ported from src/oly.h macro: #define magic_skill(n) (subkind(skill_school(n)) == sub_magic)
-func magic_skill(n int) bool {
-	return subkind(skill_school(n)) == sub_magic
+func (e *Engine) magic_skill(n int) bool {
+	return e.subkind(e.skill_school(n)) == sub_magic
 }
 
-func banner(n int) *char {
-	m := rp_misc(n)
+func (e *Engine) banner(n int) *char {
+	m := e.rp_misc(n)
 	if m == nil {
 		return nil
 	}
 	return m.display
 }
 
-func storm_bind(n int) int {
-	m := rp_misc(n)
+func (e *Engine) storm_bind(n int) int {
+	m := e.rp_misc(n)
 	if m == nil {
 		return 0
 	}
 	return m.bind_storm
 }
 
-func storm_strength(n int) short {
-	m := rp_misc(n)
+func (e *Engine) storm_strength(n int) short {
+	m := e.rp_misc(n)
 	if m == nil {
 		return 0
 	}
 	return m.storm_str
 }
 
-func npc_summoner(n int) int {
-	m := rp_misc(n)
+func (e *Engine) npc_summoner(n int) int {
+	m := e.rp_misc(n)
 	if m == nil {
 		return 0
 	}
 	return m.summoned_by
 }
 
-func garrison_castle(n int) int {
-	m := rp_misc(n)
+func (e *Engine) garrison_castle(n int) int {
+	m := e.rp_misc(n)
 	if m == nil {
 		return 0
 	}
 	return m.garr_castle
 }
 
-func npc_last_dir(n int) schar {
-	m := rp_misc(n)
+func (e *Engine) npc_last_dir(n int) schar {
+	m := e.rp_misc(n)
 	if m == nil {
 		return 0
 	}
 	return m.npc_dir
 }
 
-func restricted_control(n int) char {
-	m := rp_misc(n)
+func (e *Engine) restricted_control(n int) char {
+	m := e.rp_misc(n)
 	if m == nil {
 		return 0
 	}
 	return m.cmd_allow
 }
 
-func body_old_lord(n int) int {
-	m := rp_misc(n)
+func (e *Engine) body_old_lord(n int) int {
+	m := e.rp_misc(n)
 	if m == nil {
 		return 0
 	}
 	return m.old_lord
 }
 
-func only_defeatable(n int) int {
-	m := rp_misc(n)
+func (e *Engine) only_defeatable(n int) int {
+	m := e.rp_misc(n)
 	if m == nil {
 		return 0
 	}
 	return m.only_vuln
 }
 
-func item_lore(n int) int {
-	im := rp_item_magic(n)
+func (e *Engine) item_lore(n int) int {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.lore
 }
 
-func item_use_key(n int) schar {
-	im := rp_item_magic(n)
+func (e *Engine) item_use_key(n int) schar {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.use_key
 }
 
-func item_creator(n int) int {
-	im := rp_item_magic(n)
+func (e *Engine) item_creator(n int) int {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.creator
 }
 
-func item_aura(n int) short {
-	im := rp_item_magic(n)
+func (e *Engine) item_aura(n int) short {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.aura
 }
 
-func item_creat_cloak(n int) schar {
-	im := rp_item_magic(n)
+func (e *Engine) item_creat_cloak(n int) schar {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.cloak_creator
 }
 
-func item_reg_cloak(n int) schar {
-	im := rp_item_magic(n)
+func (e *Engine) item_reg_cloak(n int) schar {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.cloak_region
 }
 
-func item_creat_loc(n int) int {
-	im := rp_item_magic(n)
+func (e *Engine) item_creat_loc(n int) int {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.region_created
 }
 
-func item_curse_non(n int) schar {
-	im := rp_item_magic(n)
+func (e *Engine) item_curse_non(n int) schar {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.curse_loyalty
 }
 
-func item_attack_bonus(n int) schar {
-	im := rp_item_magic(n)
+func (e *Engine) item_attack_bonus(n int) schar {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.attack_bonus
 }
 
-func item_defense_bonus(n int) schar {
-	im := rp_item_magic(n)
+func (e *Engine) item_defense_bonus(n int) schar {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.defense_bonus
 }
 
-func item_missile_bonus(n int) schar {
-	im := rp_item_magic(n)
+func (e *Engine) item_missile_bonus(n int) schar {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.missile_bonus
 }
 
-func item_aura_bonus(n int) short {
-	im := rp_item_magic(n)
+func (e *Engine) item_aura_bonus(n int) short {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.aura_bonus
 }
 
-func item_relic_decay(n int) short {
-	im := rp_item_magic(n)
+func (e *Engine) item_relic_decay(n int) short {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.relic_decay
 }
 
-func item_token_num(n int) schar {
-	im := rp_item_magic(n)
+func (e *Engine) item_token_num(n int) schar {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.token_num
 }
 
-func item_token_ni(n int) int {
-	im := rp_item_magic(n)
+func (e *Engine) item_token_ni(n int) int {
+	im := e.rp_item_magic(n)
 	if im == nil {
 		return 0
 	}
 	return im.token_ni
 }
 
-func release_swear(n int) schar {
-	m := rp_magic(n)
+func (e *Engine) release_swear(n int) schar {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.swear_on_release
 }
 
-func our_token(n int) int {
-	m := rp_magic(n)
+func (e *Engine) our_token(n int) int {
+	m := e.rp_magic(n)
 	if m == nil {
 		return 0
 	}
 	return m.token
 }
 
-func is_fighter(n int) bool {
-	return item_attack(n) != 0 || item_defense(n) != 0 || item_missile(n) != 0 || n == item_ghost_warrior
+func (e *Engine) is_fighter(n int) bool {
+	return e.item_attack(n) != 0 || e.item_defense(n) != 0 || e.item_missile(n) != 0 || n == item_ghost_warrior
 }
 
-func alive(n int) bool {
-	return kind(n) == T_char
+func (e *Engine) alive(n int) bool {
+	return e.kind(n) == T_char
 }
 
-func wait_time(n int) int {
-	c := rp_command(n)
+func (e *Engine) wait_time(n int) int {
+	c := e.rp_command(n)
 	if c == nil {
 		return 0
 	}
 	return c.wait
 }
 
-func is_prisoner(n int) bool {
-	c := rp_char(n)
+func (e *Engine) is_prisoner(n int) bool {
+	c := e.rp_char(n)
 	if c == nil {
 		return false
 	}
@@ -1220,10 +1231,10 @@ func is_prisoner(n int) bool {
 // player returns the owning player for a character.
 // Walks up the unit_lord chain until it finds a player.
 // Ported from src/u.c.
-func player(n int) int {
+func (e *Engine) player(n int) int {
 	count := 0
-	for n > 0 && kind(n) != T_player {
-		c := rp_char(n)
+	for n > 0 && e.kind(n) != T_player {
+		c := e.rp_char(n)
 		if c == nil {
 			return 0
 		}
@@ -0,0 +1,332 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// account.go - accounts, login passwords and faction order passwords
+
+package taygete
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pbkdf2Iterations is the work factor for new password hashes. The
+// count is stored in each hash, so raising it doesn't invalidate old
+// passwords.
+var pbkdf2Iterations = 600_000
+
+// minPasswordLen is the shortest account password accepted.
+const minPasswordLen = 8
+
+var (
+	// ErrBadCredentials is returned when an email and password don't
+	// match an active account.
+	ErrBadCredentials = errors.New("account: bad email or password")
+
+	// ErrBadResetToken is returned for a reset token that is unknown,
+	// used or expired.
+	ErrBadResetToken = errors.New("account: invalid or expired reset token")
+
+	// ErrPasswordTooShort is returned for an account password shorter
+	// than minPasswordLen.
+	ErrPasswordTooShort = fmt.Errorf("account: password must be at least %d characters", minPasswordLen)
+)
+
+// hash_password returns a salted PBKDF2-SHA256 hash of password in the
+// form "$pbkdf2-sha256$<iterations>$<salt>$<key>".
+func hash_password(password string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, pbkdf2Iterations, 32)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf("$pbkdf2-sha256$%d$%s$%s", pbkdf2Iterations,
+		enc.EncodeToString(salt), enc.EncodeToString(key)), nil
+}
+
+// verify_password reports whether password matches a hash made by
+// hash_password.
+func verify_password(hash, password string) bool {
+	f := strings.Split(hash, "$")
+	if len(f) != 5 || f[0] != "" || f[1] != "pbkdf2-sha256" {
+		return false
+	}
+
+	iter, err := strconv.Atoi(f[2])
+	if err != nil || iter <= 0 {
+		return false
+	}
+	enc := base64.RawStdEncoding
+	salt, err := enc.DecodeString(f[3])
+	if err != nil {
+		return false
+	}
+	want, err := enc.DecodeString(f[4])
+	if err != nil || len(want) == 0 {
+		return false
+	}
+
+	got, err := pbkdf2.Key(sha256.New, password, salt, iter, len(want))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// is_password_hash reports whether s is a hash made by hash_password
+// rather than a password saved in plain text by an older version.
+func is_password_hash(s string) bool {
+	return strings.HasPrefix(s, "$pbkdf2-sha256$")
+}
+
+// set_order_password sets the password a faction gives on the BEGIN
+// line of its orders; an empty password clears it. Order passwords
+// are matched without regard to case, as the C scanner did.
+func (e *Engine) set_order_password(pl int, password string) error {
+	p := e.p_player(pl)
+	if password == "" {
+		p.password = ""
+		return nil
+	}
+
+	hash, err := hash_password(strings.ToLower(password))
+	if err != nil {
+		return err
+	}
+	p.password = hash
+	return nil
+}
+
+// check_order_password reports whether password is the faction's
+// order password. Passwords saved in plain text before hashing was
+// added are still accepted, and are hashed once they match.
+func (e *Engine) check_order_password(pl int, password string) bool {
+	p := e.rp_player(pl)
+	if p == nil || p.password == "" {
+		return false
+	}
+
+	if !is_password_hash(p.password) {
+		if i_strcmp(p.password, password) != 0 {
+			return false
+		}
+		// Keeps the plain text if hashing fails; it still matches.
+		_ = e.set_order_password(pl, password)
+		return true
+	}
+	return verify_password(p.password, strings.ToLower(password))
+}
+
+// SetOrderPassword sets or, given an empty password, clears the order
+// password of the faction with the given entity code. The change is
+// saved by the next SaveWorld.
+func (e *Engine) SetOrderPassword(faction, password string) error {
+	pl := scode(faction)
+	if pl <= 0 || e.Kind(pl) != T_player {
+		return fmt.Errorf("account: no faction %q", faction)
+	}
+	return e.set_order_password(pl, password)
+}
+
+// CheckOrderPassword reports whether password is the order password of
+// the faction with the given entity code. A faction without a password
+// accepts any.
+func (e *Engine) CheckOrderPassword(faction, password string) bool {
+	pl := scode(faction)
+	if pl <= 0 || e.Kind(pl) != T_player {
+		return false
+	}
+	if p := e.rp_player(pl); p == nil || p.password == "" {
+		return true
+	}
+	return e.check_order_password(pl, password)
+}
+
+// CreateAccount adds an account and returns its id.
+func (e *Engine) CreateAccount(email, fullName, password string) (int, error) {
+	if len(password) < minPasswordLen {
+		return 0, ErrPasswordTooShort
+	}
+	hash, err := hash_password(password)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := e.db.Exec(`
+		INSERT INTO accounts (email, password_hash, full_name) VALUES (?, ?, ?)
+	`, strings.ToLower(email), hash, nullString(fullName))
+	if err != nil {
+		return 0, fmt.Errorf("account: create %q: %w", email, err)
+	}
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+// Authenticate checks an email and password against the accounts
+// table, records the login and returns the account id.
+func (e *Engine) Authenticate(email, password string) (int, error) {
+	var id int
+	var hash, status string
+	err := e.db.QueryRow(`
+		SELECT id, password_hash, status FROM accounts WHERE email = ?
+	`, strings.ToLower(email)).Scan(&id, &hash, &status)
+	if err == sql.ErrNoRows {
+		return 0, ErrBadCredentials
+	} else if err != nil {
+		return 0, fmt.Errorf("account: lookup %q: %w", email, err)
+	}
+
+	if status != "active" || !verify_password(hash, password) {
+		return 0, ErrBadCredentials
+	}
+
+	if _, err := e.db.Exec(`UPDATE accounts SET last_login_at = CURRENT_TIMESTAMP WHERE id = ?`, id); err != nil {
+		return 0, fmt.Errorf("account: record login %d: %w", id, err)
+	}
+	return id, nil
+}
+
+// SetAccountPassword replaces an account's password.
+func (e *Engine) SetAccountPassword(id int, password string) error {
+	if len(password) < minPasswordLen {
+		return ErrPasswordTooShort
+	}
+	hash, err := hash_password(password)
+	if err != nil {
+		return err
+	}
+
+	res, err := e.db.Exec(`UPDATE accounts SET password_hash = ? WHERE id = ?`, hash, id)
+	if err != nil {
+		return fmt.Errorf("account: set password %d: %w", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("account: no account %d", id)
+	}
+	return nil
+}
+
+// ChangeAccountPassword replaces the password of the account with the
+// given email once the old password has been checked.
+func (e *Engine) ChangeAccountPassword(email, oldPassword, newPassword string) error {
+	id, err := e.Authenticate(email, oldPassword)
+	if err != nil {
+		return err
+	}
+	return e.SetAccountPassword(id, newPassword)
+}
+
+// reset_token_hash is the form a reset token is stored in.
+func reset_token_hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateResetToken returns a one-time token that lets the owner of the
+// account with the given email set a new password within ttl.
+func (e *Engine) CreateResetToken(email string, ttl time.Duration) (string, error) {
+	var id int
+	err := e.db.QueryRow(`SELECT id FROM accounts WHERE email = ?`, strings.ToLower(email)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("account: no account for %q", email)
+	} else if err != nil {
+		return "", fmt.Errorf("account: lookup %q: %w", email, err)
+	}
+
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	expires := time.Now().UTC().Add(ttl).Format(time.DateTime)
+	if _, err := e.db.Exec(`
+		INSERT INTO password_resets (token_hash, account_id, expires_at) VALUES (?, ?, ?)
+	`, reset_token_hash(token), id, expires); err != nil {
+		return "", fmt.Errorf("account: save reset token: %w", err)
+	}
+	return token, nil
+}
+
+// ResetPassword sets a new password for the account a reset token was
+// issued to. Each token works once: it is used up in the same
+// transaction that sets the password, so two resets can't share it and
+// a failed reset leaves it unused.
+func (e *Engine) ResetPassword(token, newPassword string) error {
+	if len(newPassword) < minPasswordLen {
+		return ErrPasswordTooShort
+	}
+	hash, err := hash_password(newPassword)
+	if err != nil {
+		return err
+	}
+
+	tx, err := e.db.Begin()
+	if err != nil {
+		return fmt.Errorf("account: begin reset: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC().Format(time.DateTime)
+	res, err := tx.Exec(`
+		UPDATE password_resets SET used_at = ?
+		WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?
+	`, now, reset_token_hash(token), now)
+	if err != nil {
+		return fmt.Errorf("account: use reset token: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("account: use reset token: %w", err)
+	} else if n != 1 {
+		return ErrBadResetToken
+	}
+
+	var id int
+	if err := tx.QueryRow(`
+		SELECT account_id FROM password_resets WHERE token_hash = ?
+	`, reset_token_hash(token)).Scan(&id); err != nil {
+		return fmt.Errorf("account: lookup reset token: %w", err)
+	}
+
+	res, err = tx.Exec(`UPDATE accounts SET password_hash = ? WHERE id = ?`, hash, id)
+	if err != nil {
+		return fmt.Errorf("account: set password %d: %w", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("account: no account %d", id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("account: commit reset: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package taygete
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func init() {
+	// Keep password hashing cheap in tests.
+	pbkdf2Iterations = 1_000
+}
+
+func TestHashPassword(t *testing.T) {
+	hash, err := hash_password("correct horse")
+	if err != nil {
+		t.Fatalf("hash_password: %v", err)
+	}
+	if !is_password_hash(hash) {
+		t.Errorf("hash %q not recognized", hash)
+	}
+	if !verify_password(hash, "correct horse") {
+		t.Error("right password rejected")
+	}
+	if verify_password(hash, "correct horsE") {
+		t.Error("wrong password accepted")
+	}
+	for _, bad := range []string{"", "correct horse", "$pbkdf2-sha256$x$y$z", "$md5$1$aa$bb"} {
+		if verify_password(bad, "correct horse") {
+			t.Errorf("verify_password(%q) accepted", bad)
+		}
+	}
+
+	// Salted: the same password hashes differently each time
+	if again, _ := hash_password("correct horse"); again == hash {
+		t.Error("hash_password is not salted")
+	}
+}
+
+func TestOrderPassword(t *testing.T) {
+	pl, _, code, whoCode := setupEatTest(t)
+
+	if !teg.CheckOrderPassword(code, "anything") {
+		t.Error("faction without a password rejected orders")
+	}
+
+	if err := teg.SetOrderPassword(code, "Swordfish"); err != nil {
+		t.Fatalf("SetOrderPassword: %v", err)
+	}
+	if p := teg.rp_player(pl); !is_password_hash(p.password) {
+		t.Errorf("order password stored as %q", p.password)
+	}
+	if !teg.CheckOrderPassword(code, "SWORDFISH") {
+		t.Error("order password should match without regard to case")
+	}
+	if teg.CheckOrderPassword(code, "tuna") {
+		t.Error("wrong order password accepted")
+	}
+
+	// Passwords saved before hashing still work
+	teg.p_player(pl).password = "Legacy"
+	if !teg.CheckOrderPassword(code, "legacy") || teg.CheckOrderPassword(code, "tuna") {
+		t.Error("plain text order password not checked")
+	}
+	if p := teg.rp_player(pl); !is_password_hash(p.password) || !teg.CheckOrderPassword(code, "Legacy") {
+		t.Errorf("plain text order password not rehashed once checked: %q", p.password)
+	}
+
+	if err := teg.SetOrderPassword(code, ""); err != nil || teg.rp_player(pl).password != "" {
+		t.Errorf("clearing password: %v, %q", err, teg.rp_player(pl).password)
+	}
+	if err := teg.SetOrderPassword(whoCode, "x"); err == nil {
+		t.Error("SetOrderPassword accepted a character")
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	e := newTestEngine(t)
+
+	if _, err := e.CreateAccount("ann@example.com", "Ann", "short"); !errors.Is(err, ErrPasswordTooShort) {
+		t.Errorf("short password: err = %v", err)
+	}
+
+	id, err := e.CreateAccount("Ann@Example.com", "Ann", "correct horse")
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	if _, err := e.CreateAccount("ann@example.com", "Ann", "correct horse"); err == nil {
+		t.Error("duplicate email accepted")
+	}
+
+	if got, err := e.Authenticate("ann@example.com", "correct horse"); err != nil || got != id {
+		t.Errorf("Authenticate = %d, %v; want %d", got, err, id)
+	}
+	for _, tt := range []struct{ email, password string }{
+		{"ann@example.com", "wrong horse"},
+		{"bob@example.com", "correct horse"},
+	} {
+		if _, err := e.Authenticate(tt.email, tt.password); !errors.Is(err, ErrBadCredentials) {
+			t.Errorf("Authenticate(%q, %q): err = %v", tt.email, tt.password, err)
+		}
+	}
+
+	var lastLogin *string
+	if err := e.db.QueryRow(`SELECT last_login_at FROM accounts WHERE id = ?`, id).Scan(&lastLogin); err != nil || lastLogin == nil {
+		t.Errorf("last_login_at not recorded: %v", err)
+	}
+
+	if err := e.ChangeAccountPassword("ann@example.com", "wrong horse", "battery staple"); !errors.Is(err, ErrBadCredentials) {
+		t.Errorf("change with wrong password: err = %v", err)
+	}
+	if err := e.ChangeAccountPassword("ann@example.com", "correct horse", "battery staple"); err != nil {
+		t.Fatalf("ChangeAccountPassword: %v", err)
+	}
+	if _, err := e.Authenticate("ann@example.com", "battery staple"); err != nil {
+		t.Errorf("new password rejected: %v", err)
+	}
+
+	if _, err := e.db.Exec(`UPDATE accounts SET status = 'suspended' WHERE id = ?`, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Authenticate("ann@example.com", "battery staple"); !errors.Is(err, ErrBadCredentials) {
+		t.Errorf("suspended account: err = %v", err)
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	e := newTestEngine(t)
+	if _, err := e.CreateAccount("ann@example.com", "Ann", "correct horse"); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	if _, err := e.CreateResetToken("bob@example.com", time.Hour); err == nil {
+		t.Error("reset token issued for unknown account")
+	}
+
+	token, err := e.CreateResetToken("ann@example.com", time.Hour)
+	if err != nil {
+		t.Fatalf("CreateResetToken: %v", err)
+	}
+	if err := e.ResetPassword("not-a-token", "battery staple"); !errors.Is(err, ErrBadResetToken) {
+		t.Errorf("unknown token: err = %v", err)
+	}
+	if err := e.ResetPassword(token, "battery staple"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if _, err := e.Authenticate("ann@example.com", "battery staple"); err != nil {
+		t.Errorf("reset password rejected: %v", err)
+	}
+	if err := e.ResetPassword(token, "another horse"); !errors.Is(err, ErrBadResetToken) {
+		t.Errorf("reused token: err = %v", err)
+	}
+
+	expired, err := e.CreateResetToken("ann@example.com", -time.Minute)
+	if err != nil {
+		t.Fatalf("CreateResetToken: %v", err)
+	}
+	if err := e.ResetPassword(expired, "another horse"); !errors.Is(err, ErrBadResetToken) {
+		t.Errorf("expired token: err = %v", err)
+	}
+
+	// A reset that fails to set the password doesn't use up the token.
+	token, err = e.CreateResetToken("ann@example.com", time.Hour)
+	if err != nil {
+		t.Fatalf("CreateResetToken: %v", err)
+	}
+	if _, err := e.db.Exec(`
+		CREATE TRIGGER fail_reset BEFORE UPDATE ON accounts
+		BEGIN SELECT RAISE(ABORT, 'disk full'); END
+	`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+	if err := e.ResetPassword(token, "another horse"); err == nil {
+		t.Fatal("ResetPassword succeeded through a failing update")
+	}
+	if _, err := e.db.Exec(`DROP TRIGGER fail_reset`); err != nil {
+		t.Fatalf("drop trigger: %v", err)
+	}
+	if err := e.ResetPassword(token, "another horse"); err != nil {
+		t.Errorf("token used up by a failed reset: %v", err)
+	}
+}
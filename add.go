@@ -0,0 +1,280 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// add.go - New player onboarding ported from src/add.c
+
+package taygete
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// NewPlayer describes a faction joining the game. The fields follow
+// the C join form read by oly -a.
+type NewPlayer struct {
+	Faction   string // faction name
+	Character string // name of the faction's first noble
+	StartCity string // start city code or name, "empty", or "" for any
+	FullName  string // player's full name
+	Email     string // player's email address
+	AccountID int    // accounts row the faction belongs to, 0 for none
+	Password  string // order password, or "" for a random one
+}
+
+// ErrNoStartCity is returned by AddPlayer when there is nowhere to
+// place a new faction's first noble.
+var ErrNoStartCity = errors.New("no start city available")
+
+// ErrUnknownStartCity is returned by AddPlayer when the start city
+// asked for is not one of the start cities.
+var ErrUnknownStartCity = errors.New("unknown start city")
+
+// AddPlayer creates a new faction with its first noble in a start city,
+// granting the starting gold, claim items, noble points and instant
+// study days. It returns the new player's entity number and order
+// password, which the player needs on the BEGIN line of their orders.
+// Port of C add_new_player() from add.c.
+func (e *Engine) AddPlayer(np NewPlayer) (int, string, error) {
+	if np.Faction == "" || np.Character == "" {
+		return 0, "", errors.New("faction and character names are required")
+	}
+
+	for _, item := range []int{item_peasant, item_gold, item_lumber, item_stone, item_riding_horse} {
+		if e.Kind(item) != T_item {
+			return 0, "", fmt.Errorf("starting item %d is not in the world", item)
+		}
+	}
+
+	city, err := e.pickStartingCity(np.StartCity)
+	if err != nil {
+		return 0, "", err
+	}
+
+	pl := e.new_ent(T_player, sub_pl_regular)
+	if pl < 0 {
+		return 0, "", errors.New("no player numbers left")
+	}
+
+	who := e.new_ent(T_char, 0)
+	if who < 0 {
+		e.delete_box(pl)
+		return 0, "", errors.New("no character numbers left")
+	}
+
+	t := int(e.globals.sysclock.turn)
+
+	e.set_name(pl, np.Faction)
+	e.set_name(who, np.Character)
+
+	password := np.Password
+	if password == "" {
+		password = new_password()
+	}
+	if err := e.set_order_password(pl, password); err != nil {
+		e.delete_box(who)
+		e.delete_box(pl)
+		return 0, "", err
+	}
+
+	pp := e.p_player(pl)
+	cp := e.p_char(who)
+
+	pp.full_name = np.FullName
+	pp.email = np.Email
+	pp.account_id = np.AccountID
+
+	pp.noble_points = short(18 + t/8)
+	pp.first_turn = t + 1
+	pp.last_order_turn = t
+
+	if t < 101 {
+		pp.fast_study = short(198 + t*2)
+	} else {
+		pp.fast_study = 400
+	}
+
+	cp.health = 100
+	cp.break_point = 50
+	cp.attack = 80
+	cp.defense = 80
+
+	e.set_where(who, city)
+	garrison := e.garrison_here(city)
+	e.promote(who, 0)
+	// If there is a garrison in the city then they need to still
+	// be at the top of the list, newcomer advantage notwithstanding.
+	if garrison != 0 {
+		e.promote(garrison, 0)
+	}
+	e.set_lord(who, pl, LOY_oath, 2)
+	e.addUnit(pl, who)
+
+	e.gen_item(who, item_peasant, 25)
+	e.gen_item(who, item_gold, 200)
+
+	e.gen_item(pl, item_gold, 5000)      // CLAIM item
+	e.gen_item(pl, item_lumber, 50)      // CLAIM item
+	e.gen_item(pl, item_stone, 100)      // CLAIM item
+	e.gen_item(pl, item_riding_horse, 5) // CLAIM item
+
+	e.add_unformed_sup(pl)
+
+	return pl, password, nil
+}
+
+// new_password returns a random eight character password, the initial
+// order password for a new faction. It comes from crypto/rand rather
+// than a game stream, so it can't be reproduced from the game seed and
+// doesn't shift the draws of the game.
+func new_password() string {
+	const symbols = "abcdefghijklmnopqrstuvwxyz" +
+		"ABCDEFGHIJKLMNOPQRSTUVWXYZ" +
+		"1234567890"
+
+	var b strings.Builder
+	var buf [1]byte
+	for b.Len() < 8 {
+		// crypto/rand.Read never fails
+		_, _ = rand.Read(buf[:])
+		// Reject the bytes past the last whole run of symbols, so every
+		// symbol is equally likely
+		if int(buf[0]) < 256/len(symbols)*len(symbols) {
+			b.WriteByte(symbols[int(buf[0])%len(symbols)])
+		}
+	}
+	return b.String()
+}
+
+// pickStartingCity chooses where a new faction begins. "empty" picks a
+// city with no player nobles nearby, preferring garrisoned ones, and
+// "" any start city at random. Any other choice must match a start
+// city by code or name.
+// Port of C pick_starting_city() and get_city_id() from add.c, except
+// that C gave an unmatched choice a random start city.
+func (e *Engine) pickStartingCity(choice string) (int, error) {
+	if i_strcmp(choice, "empty") == 0 {
+		if city := e.pickEmptyCity(); city != 0 {
+			return city, nil
+		}
+		choice = ""
+	}
+
+	starts := e.StartCities()
+	if len(starts) == 0 {
+		return 0, ErrNoStartCity
+	}
+	if choice == "" {
+		return starts[e.rndFrom(streamSeeding, 0, len(starts)-1)], nil
+	}
+
+	for _, city := range starts {
+		if i_strcmp(choice, box_code_less(city)) == 0 ||
+			i_strcmp(choice, e.just_name(city)) == 0 {
+			return city, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownStartCity, choice)
+}
+
+// pickEmptyCity returns a random city with no player nobles in it or
+// its province, preferring cities held by a garrison.
+// Ported from src/add.c lines 103-160.
+func (e *Engine) pickEmptyCity() int {
+	var garrisoned, ungarrisoned IList
+
+	for _, city := range e.Cities() {
+		if e.safe_haven(city) != 0 || e.greater_region(city) != 0 {
+			continue
+		}
+
+		prov := e.province(city)
+		if e.garrison_here(prov) != 0 {
+			continue
+		}
+
+		empty, garrison := true, false
+
+		var l []int
+		e.all_here(city, &l)
+		for _, here := range l {
+			if e.kind(here) == T_char {
+				if e.default_garrison(here) != 0 {
+					garrison = true
+				} else {
+					empty = false
+				}
+			}
+		}
+
+		e.all_char_here(prov, &l)
+		for _, here := range l {
+			if !e.is_npc(here) {
+				empty = false
+			}
+		}
+
+		if empty {
+			if garrison {
+				garrisoned.Append(city)
+			} else {
+				ungarrisoned.Append(city)
+			}
+		}
+	}
+
+	for _, l := range []*IList{&garrisoned, &ungarrisoned} {
+		if l.Len() > 0 {
+			return l.Values()[e.rndFrom(streamSeeding, 0, l.Len()-1)]
+		}
+	}
+
+	return 0
+}
+
+// StartCities returns the locations new factions may start in, in
+// ascending order.
+func (e *Engine) StartCities() []int {
+	var l []int
+	for n, ok := range e.globals.startLocs {
+		if ok && e.Kind(n) == T_loc {
+			l = append(l, n)
+		}
+	}
+	slices.Sort(l)
+	return l
+}
+
+// SetStartCity marks or unmarks n as a location new factions may
+// start in.
+func (e *Engine) SetStartCity(n int, start bool) error {
+	if e.Kind(n) != T_loc {
+		return fmt.Errorf("%d is not a location", n)
+	}
+	if e.globals.startLocs == nil {
+		e.globals.startLocs = make(map[int]bool)
+	}
+	if start {
+		e.globals.startLocs[n] = true
+	} else {
+		delete(e.globals.startLocs, n)
+	}
+	e.markDirty(n)
+	return nil
+}
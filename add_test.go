@@ -0,0 +1,158 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package taygete
+
+import (
+	"errors"
+	"testing"
+)
+
+// setupAddTest builds a province with two start cities and the
+// starting items.
+func setupAddTest(t *testing.T) (e *Engine, prov, city1, city2 int) {
+	t.Helper()
+	e = newTestEngine(t)
+	e.globals.sysclock.turn = 16
+
+	for _, item := range []int{item_peasant, item_gold, item_lumber, item_stone, item_riding_horse} {
+		teg.alloc_box(item, T_item, 0)
+	}
+
+	prov, city1, city2 = 10_101, 56_760, 56_761
+	teg.alloc_box(prov, T_loc, sub_plain)
+	teg.alloc_box(city1, T_loc, sub_city)
+	teg.alloc_box(city2, T_loc, sub_city)
+	teg.set_name(city1, "Drassa")
+	teg.set_name(city2, "Pen")
+	teg.set_where(city1, prov)
+	teg.set_where(city2, prov)
+
+	for _, city := range []int{city1, city2} {
+		if err := e.SetStartCity(city, true); err != nil {
+			t.Fatalf("SetStartCity: %v", err)
+		}
+	}
+
+	return e, prov, city1, city2
+}
+
+func TestAddPlayer(t *testing.T) {
+	e, _, _, city2 := setupAddTest(t)
+
+	pl, password, err := e.AddPlayer(NewPlayer{
+		Faction:   "The Wanderers",
+		Character: "Osswid",
+		StartCity: "pen",
+		FullName:  "Ann Player",
+		Email:     "ann@example.com",
+		AccountID: 7,
+	})
+	if err != nil {
+		t.Fatalf("AddPlayer: %v", err)
+	}
+
+	if teg.kind(pl) != T_player || teg.subkind(pl) != sub_pl_regular {
+		t.Fatalf("player %d: kind %d subkind %d", pl, teg.kind(pl), teg.subkind(pl))
+	}
+	if teg.just_name(pl) != "The Wanderers" {
+		t.Errorf("faction name = %q", teg.just_name(pl))
+	}
+
+	p := teg.rp_player(pl)
+	if p.email != "ann@example.com" || p.full_name != "Ann Player" || p.account_id != 7 {
+		t.Errorf("player = %q %q %d", p.email, p.full_name, p.account_id)
+	}
+	if p.noble_points != 20 || p.fast_study != 230 || p.first_turn != 17 {
+		t.Errorf("np %d, fast study %d, first turn %d, want 20, 230, 17",
+			p.noble_points, p.fast_study, p.first_turn)
+	}
+	if len(password) != 8 || !is_password_hash(p.password) || !teg.check_order_password(pl, password) {
+		t.Errorf("password = %q, saved as %q, want 8 characters saved hashed", password, p.password)
+	}
+	if got := len(teg.getPlayerUnformed(pl)); got != 5 {
+		t.Errorf("unformed nobles = %d, want 5", got)
+	}
+
+	units := teg.loop_units(pl)
+	if len(units) != 1 {
+		t.Fatalf("units = %v, want one noble", units)
+	}
+	who := units[0]
+	if teg.just_name(who) != "Osswid" || teg.subloc(who) != city2 {
+		t.Errorf("noble %q in %d, want Osswid in %d", teg.just_name(who), teg.subloc(who), city2)
+	}
+	if teg.loyal_kind(who) != LOY_oath || teg.loyal_rate(who) != 2 {
+		t.Errorf("loyalty = %d/%d, want oath-2", teg.loyal_kind(who), teg.loyal_rate(who))
+	}
+	if teg.has_item(who, item_gold) != 200 || teg.has_item(who, item_peasant) != 25 {
+		t.Error("noble missing starting gold or peasants")
+	}
+	if teg.has_item(pl, item_gold) != 5000 || teg.has_item(pl, item_riding_horse) != 5 {
+		t.Error("faction missing claim gold or horses")
+	}
+}
+
+func TestAddPlayerRandomCity(t *testing.T) {
+	e, _, city1, city2 := setupAddTest(t)
+
+	pl, _, err := e.AddPlayer(NewPlayer{Faction: "f", Character: "c"})
+	if err != nil {
+		t.Fatalf("AddPlayer: %v", err)
+	}
+	if where := teg.subloc(teg.loop_units(pl)[0]); where != city1 && where != city2 {
+		t.Errorf("noble started in %d, want a start city", where)
+	}
+
+	if _, _, err := e.AddPlayer(NewPlayer{Faction: "g", Character: "d", StartCity: "nowhere"}); !errors.Is(err, ErrUnknownStartCity) {
+		t.Errorf("AddPlayer error = %v, want ErrUnknownStartCity", err)
+	}
+}
+
+func TestAddPlayerEmptyCity(t *testing.T) {
+	e, _, city1, city2 := setupAddTest(t)
+
+	first, _, err := e.AddPlayer(NewPlayer{Faction: "f", Character: "c", StartCity: "empty"})
+	if err != nil {
+		t.Fatalf("AddPlayer: %v", err)
+	}
+	taken := teg.subloc(teg.loop_units(first)[0])
+	if taken != city1 && taken != city2 {
+		t.Fatalf("noble started in %d, want an empty city", taken)
+	}
+
+	if got := e.pickEmptyCity(); got == taken || got == 0 {
+		t.Errorf("pickEmptyCity = %d, want the city other than %d", got, taken)
+	}
+
+	second, _, err := e.AddPlayer(NewPlayer{Faction: "g", Character: "d", StartCity: "empty"})
+	if err != nil {
+		t.Fatalf("AddPlayer: %v", err)
+	}
+	if e.pickEmptyCity() != 0 {
+		t.Errorf("pickEmptyCity found a city after both were taken by %d and %d", first, second)
+	}
+}
+
+func TestAddPlayerNoStartCity(t *testing.T) {
+	e, _, city1, city2 := setupAddTest(t)
+	e.SetStartCity(city1, false)
+	e.SetStartCity(city2, false)
+
+	if _, _, err := e.AddPlayer(NewPlayer{Faction: "f", Character: "c"}); !errors.Is(err, ErrNoStartCity) {
+		t.Errorf("AddPlayer error = %v, want ErrNoStartCity", err)
+	}
+}
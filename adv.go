@@ -0,0 +1,122 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// adv.go - Advanced sorcery ported from src/adv.c
+
+package taygete
+
+// v_trance starts a trance.
+// Ported from src/adv.c lines 11-22.
+func (e *Engine) v_trance(c *command) int {
+	if !e.has_skill(c.who, sk_trance) {
+		wout(c.who, "Requires knowledge of %s.", e.box_name(sk_trance))
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_trance raises current aura to two-thirds of the maximum and fully
+// heals the mage.
+// Ported from src/adv.c lines 25-45.
+func (e *Engine) d_trance(c *command) int {
+	p := e.p_magic(c.who)
+
+	p.cur_aura = max(p.cur_aura, e.max_eff_aura(c.who)*2/3)
+
+	wout(c.who, "Current aura is now %d.", p.cur_aura)
+
+	if e.char_health(c.who) < 100 || e.char_sick(c.who) != 0 {
+		pc := e.p_char(c.who)
+		pc.sick = FALSE
+		pc.health = 100
+
+		wout(c.who, "%s is fully healed.", e.box_name(c.who))
+	}
+
+	return TRUE
+}
+
+// v_teleport_item starts teleporting items; everything is checked
+// when the spell is cast.
+// Ported from src/adv.c lines 48-53.
+func (e *Engine) v_teleport_item(c *command) int {
+	return TRUE
+}
+
+// d_teleport_item gives items to a character anywhere in the region,
+// for three aura plus one per 50 weight sent. The arguments are those
+// of GIVE: <who> <what> [qty] [have-left].
+// Ported from src/adv.c lines 60-129.
+func (e *Engine) d_teleport_item(c *command) int {
+	target := c.a
+	item := c.b
+	qty := c.c
+	have_left := c.d
+
+	if e.kind(target) != T_char {
+		wout(c.who, "%s is not a character.", e.box_code(target))
+		return FALSE
+	}
+
+	if e.is_prisoner(target) {
+		wout(c.who, "Prisoners may not be given anything.")
+		return FALSE
+	}
+
+	if e.kind(item) != T_item {
+		wout(c.who, "%s is not an item.", e.box_code(item))
+		return FALSE
+	}
+
+	if e.diff_region(c.who, target) {
+		wout(c.who, "%s is too far away to teleport items to.", e.box_code(target))
+		return FALSE
+	}
+
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s does not have any %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	qty = e.how_many(c.who, c.who, item, qty, have_left)
+
+	if qty <= 0 {
+		return FALSE
+	}
+
+	aura := 3 + int(e.item_weight(item))*qty/50
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	if !e.will_accept(target, item, c.who, qty) {
+		return FALSE
+	}
+
+	e.charge_aura(c.who, aura)
+
+	if !e.move_item(c.who, target, item, qty) {
+		panic("d_teleport_item: move_item failed")
+	}
+
+	wout(c.who, "Teleported %s to %s.", e.just_name_qty(item, qty), e.box_name(target))
+
+	wout(target, "%s teleported %s to us.", e.box_name(c.who), e.just_name_qty(item, qty))
+
+	return TRUE
+}
@@ -0,0 +1,67 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// adv_test.go - Tests for advanced sorcery
+
+package taygete
+
+import "testing"
+
+func TestTrance(t *testing.T) {
+	who := setupBasicTest(t, 30, 5)
+	teg.alloc_box(sk_trance, T_skill, 0)
+	teg.p_skill_ent(who, sk_trance).know = SKILL_know
+	pc := teg.p_char(who)
+	pc.health = 40
+	pc.sick = TRUE
+
+	c := &command{who: who}
+	if teg.v_trance(c) != TRUE || teg.d_trance(c) != TRUE {
+		t.Fatal("trance failed")
+	}
+	if got := teg.char_cur_aura(who); got != 20 {
+		t.Errorf("aura = %d, want 20", got)
+	}
+	if teg.char_health(who) != 100 || teg.char_sick(who) != 0 {
+		t.Errorf("health = %d, sick = %d; want fully healed", teg.char_health(who), teg.char_sick(who))
+	}
+}
+
+func TestTeleportItem(t *testing.T) {
+	who := setupBasicTest(t, 20, 20)
+
+	far := 10_102
+	teg.alloc_box(far, T_loc, sub_plain)
+	target := 1002
+	teg.alloc_box(target, T_char, 0)
+	teg.set_where(target, far)
+
+	item := item_iron
+	teg.alloc_box(item, T_item, 0)
+	teg.p_item(item).weight = 100
+	teg.gen_item(who, item, 5)
+
+	c := &command{who: who, a: target, b: item, c: 2}
+	if teg.v_teleport_item(c) != TRUE || teg.d_teleport_item(c) != TRUE {
+		t.Fatal("teleport item failed")
+	}
+	if got := teg.has_item(target, item); got != 2 {
+		t.Errorf("target holds %d, want 2", got)
+	}
+	if got := teg.char_cur_aura(who); got != 13 {
+		t.Errorf("aura = %d, want 13 after paying 3 plus 4 for 200 weight", got)
+	}
+}
@@ -0,0 +1,234 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// alchem.go - Potions and alchemy ported from src/alchem.c
+//
+// Brewed potions are unique items whose item_magic use_key selects
+// the effect when the potion is quaffed with USE.
+
+package taygete
+
+// new_potion creates an unnamed potion in who's inventory.
+// Returns the new item, or -1 if no entity could be allocated.
+// Ported from src/alchem.c lines 8-41.
+func (e *Engine) new_potion(who int) int {
+	newItem := e.create_unique_item(who, 0)
+	if newItem < 0 {
+		return -1
+	}
+
+	var s string
+	switch e.rndFrom(streamMagic, 1, 2) {
+	case 1:
+		s = "Magic potion"
+	case 2:
+		s = "Strange potion"
+	}
+
+	e.set_name(newItem, s)
+	p := e.p_item_magic(newItem)
+	p.creator = who
+	p.region_created = e.province(who)
+	e.p_item(newItem).weight = 1
+
+	wout(who, "Produced one %s", e.box_name(newItem))
+
+	return newItem
+}
+
+// brew_potion finishes brewing a potion with the given use key.
+func (e *Engine) brew_potion(c *command, useKey int) int {
+	newItem := e.new_potion(c.who)
+	if newItem < 0 {
+		wout(c.who, "Attempt to brew potion failed.")
+		return FALSE
+	}
+
+	e.p_item_magic(newItem).use_key = schar(useKey)
+
+	return TRUE
+}
+
+// v_brew starts brewing a potion.
+// Ported from src/alchem.c lines 78-83.
+func (e *Engine) v_brew(c *command) int {
+	return TRUE
+}
+
+// d_brew_slave finishes brewing a potion of slavery.
+// Ported from src/alchem.c lines 44-59.
+func (e *Engine) d_brew_slave(c *command) int {
+	return e.brew_potion(c, use_slave_potion)
+}
+
+// d_brew_death finishes brewing a potion of death.
+// Ported from src/alchem.c lines 62-75.
+func (e *Engine) d_brew_death(c *command) int {
+	return e.brew_potion(c, use_death_potion)
+}
+
+// d_brew_heal finishes brewing a potion of healing.
+// Ported from src/alchem.c lines 86-101.
+func (e *Engine) d_brew_heal(c *command) int {
+	return e.brew_potion(c, use_heal_potion)
+}
+
+// v_use_heal quaffs a healing potion: cures illness and restores
+// up to 30 points of health.
+// Ported from src/alchem.c lines 104-139.
+func (e *Engine) v_use_heal(c *command) int {
+	item := c.a
+
+	wout(c.who, "%s drinks the potion...", e.just_name(c.who))
+
+	if e.char_health(c.who) == 100 && e.char_sick(c.who) == 0 {
+		wout(c.who, "Nothing happens.")
+		e.destroy_unique_item(c.who, item)
+		return TRUE
+	}
+
+	if e.char_sick(c.who) != 0 {
+		e.p_char(c.who).sick = FALSE
+		wout(c.who, "%s has been cured of illness.", e.just_name(c.who))
+	}
+
+	if e.char_health(c.who) < 100 {
+		// computed in int: health is an int8 and 99+30 would wrap
+		health := min(int(e.char_health(c.who))+e.rndFrom(streamMagic, 0, 3)*10, 100)
+		e.p_char(c.who).health = schar(health)
+		wout(c.who, "Health is now %d.", e.char_health(c.who))
+	}
+
+	e.destroy_unique_item(c.who, item)
+
+	return TRUE
+}
+
+// v_use_death quaffs a potion of death.
+// Ported from src/alchem.c lines 142-162.
+func (e *Engine) v_use_death(c *command) int {
+	item := c.a
+
+	wout(c.who, "%s drinks the potion...", e.just_name(c.who))
+	e.destroy_unique_item(c.who, item)
+
+	wout(c.who, "It's poison!")
+
+	e.p_char(c.who).sick = TRUE
+
+	e.add_char_damage(c.who, 100, MATES)
+
+	return TRUE
+}
+
+// v_use_slave quaffs a potion of slavery. The drinker may die, or
+// may desert to the potion's creator if the creator's faction has
+// enough noble points to absorb them.
+// Ported from src/alchem.c lines 165-221.
+func (e *Engine) v_use_slave(c *command) int {
+	item := c.a
+	creator := e.item_creator(item)
+
+	log_write(LOG_SPECIAL, "%s drinks a slavery potion to %s", e.box_name(c.who), e.box_name(creator))
+
+	wout(c.who, "%s drinks the potion...", e.just_name(c.who))
+
+	e.destroy_unique_item(c.who, item)
+
+	if e.rndFrom(streamMagic, 1, 100) <= 33 {
+		e.kill_char(c.who, MATES)
+		return TRUE
+	}
+
+	nps := e.char_np_total(c.who)
+
+	if !e.valid_box(creator) ||
+		e.kind(creator) != T_char ||
+		!e.valid_box(e.player(creator)) ||
+		int(e.player_np(e.player(creator))) < nps ||
+		c.who == creator || e.player(c.who) == e.player(creator) {
+		wout(c.who, "Nothing happens.")
+		return TRUE
+	}
+
+	wout(c.who, "%s is suddenly overcome with an irresistible desire to serve %s.",
+		e.just_name(c.who), e.box_name(creator))
+
+	e.unit_deserts(c.who, creator, true, LOY_contract, 250)
+	return TRUE
+}
+
+// v_lead_to_gold starts transmuting lead into gold.
+// Ported from src/alchem.c lines 224-253.
+func (e *Engine) v_lead_to_gold(c *command) int {
+	amount := c.a
+
+	if e.has_item(c.who, item_farrenstone) < 1 {
+		wout(c.who, "Requires %s.", e.box_name_qty(item_farrenstone, 1))
+		return FALSE
+	}
+
+	qty := e.has_item(c.who, item_lead)
+
+	if amount == 0 {
+		amount = qty
+	}
+	if amount > qty {
+		amount = qty
+	}
+
+	qty = min(qty, 20)
+
+	if qty == 0 {
+		wout(c.who, "Don't have any %s.", e.box_name(item_lead))
+		return FALSE
+	}
+
+	c.d = qty
+
+	return TRUE
+}
+
+// d_lead_to_gold turns up to twenty lead into ten gold each.
+// The farrenstone is a catalyst and is never consumed.
+// Ported from src/alchem.c lines 256-292.
+func (e *Engine) d_lead_to_gold(c *command) int {
+	qty := c.d
+	has := e.has_item(c.who, item_lead)
+
+	if e.has_item(c.who, item_farrenstone) < 1 {
+		wout(c.who, "Requires %s.", e.box_name_qty(item_farrenstone, 1))
+		return FALSE
+	}
+
+	if has < qty {
+		qty = has
+	}
+
+	if qty == 0 {
+		wout(c.who, "Don't have any %s.", e.box_name(item_lead))
+		return FALSE
+	}
+
+	wout(c.who, "Turned %s into %s.", e.just_name_qty(item_lead, qty), e.just_name_qty(item_gold, qty*10))
+
+	e.consume_item(c.who, item_lead, qty)
+
+	e.gen_item(c.who, item_gold, qty*10)
+	e.globals.gold_lead_to_gold += qty * 10
+
+	return TRUE
+}
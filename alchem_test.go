@@ -0,0 +1,111 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// alchem_test.go - Tests for potions and alchemy
+
+package taygete
+
+import "testing"
+
+func TestBrewHealPotion(t *testing.T) {
+	_, who := setupUseTest(t)
+
+	c := &command{who: who}
+	if got := teg.d_brew_heal(c); got != TRUE {
+		t.Fatalf("d_brew_heal = %d, want TRUE", got)
+	}
+
+	var potion int
+	for _, e := range teg.globals.inventories[who] {
+		if teg.kind(e.item) == T_item && teg.item_unique(e.item) != 0 {
+			potion = e.item
+		}
+	}
+	if potion == 0 {
+		t.Fatal("no potion in inventory after brewing")
+	}
+	if got := teg.item_use_key(potion); got != use_heal_potion {
+		t.Errorf("use_key = %d, want %d", got, use_heal_potion)
+	}
+	if got := teg.item_creator(potion); got != who {
+		t.Errorf("creator = %d, want %d", got, who)
+	}
+	if got := teg.item_weight(potion); got != 1 {
+		t.Errorf("weight = %d, want 1", got)
+	}
+}
+
+func TestUseHealPotion(t *testing.T) {
+	_, who := setupUseTest(t)
+
+	teg.d_brew_heal(&command{who: who})
+	potion := teg.globals.inventories[who][0].item
+
+	teg.p_char(who).health = 95
+	teg.p_char(who).sick = TRUE
+
+	c := &command{who: who, a: potion}
+	if got := teg.v_use_heal(c); got != TRUE {
+		t.Fatalf("v_use_heal = %d, want TRUE", got)
+	}
+	if teg.char_sick(who) != 0 {
+		t.Error("still sick after healing potion")
+	}
+	if h := teg.char_health(who); h < 95 || h > 100 {
+		t.Errorf("health = %d, want 95..100", h)
+	}
+	if teg.has_item(who, potion) != 0 {
+		t.Error("potion was not consumed")
+	}
+	if teg.kind(potion) != T_deleted {
+		t.Errorf("potion kind = %d, want T_deleted", teg.kind(potion))
+	}
+}
+
+func TestLeadToGold(t *testing.T) {
+	_, who := setupUseTest(t)
+	teg.alloc_box(item_gold, T_item, 0)
+	teg.alloc_box(item_lead, T_item, 0)
+	teg.alloc_box(item_farrenstone, T_item, 0)
+
+	teg.gen_item(who, item_lead, 30)
+
+	c := &command{who: who}
+	if got := teg.v_lead_to_gold(c); got != FALSE {
+		t.Errorf("v_lead_to_gold without farrenstone = %d, want FALSE", got)
+	}
+
+	teg.gen_item(who, item_farrenstone, 1)
+	if got := teg.v_lead_to_gold(c); got != TRUE {
+		t.Fatalf("v_lead_to_gold = %d, want TRUE", got)
+	}
+	if c.d != 20 {
+		t.Errorf("batch size = %d, want 20", c.d)
+	}
+	if got := teg.d_lead_to_gold(c); got != TRUE {
+		t.Fatalf("d_lead_to_gold = %d, want TRUE", got)
+	}
+
+	if got := teg.has_item(who, item_lead); got != 10 {
+		t.Errorf("lead = %d, want 10", got)
+	}
+	if got := teg.has_item(who, item_gold); got != 200 {
+		t.Errorf("gold = %d, want 200", got)
+	}
+	if got := teg.has_item(who, item_farrenstone); got != 1 {
+		t.Errorf("farrenstone = %d, want 1 (catalyst is not consumed)", got)
+	}
+}
@@ -0,0 +1,1190 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// art.go - Artifacts, auraculums and aura limits ported from src/art.c
+
+package taygete
+
+// has_auraculum returns the auraculum item ID if who has their auraculum,
+// otherwise returns 0.
+// Ported from src/art.c lines 7-18.
+func (e *Engine) has_auraculum(who int) int {
+	ac := e.char_auraculum(who)
+	if ac != 0 && e.valid_box(ac) && e.has_item(who, ac) > 0 {
+		return ac
+	}
+	return 0
+}
+
+// max_eff_aura returns a mage's maximum aura: innate aura plus the
+// auraculum and any aura bonus items carried.
+// Ported from src/art.c lines 25-50.
+func (e *Engine) max_eff_aura(who int) int {
+	a := e.char_max_aura(who)
+	if a < 0 {
+		a = 0
+	}
+	if ac := e.has_auraculum(who); ac != 0 {
+		a += int(e.item_aura(ac))
+	}
+
+	for _, it := range e.globals.inventories[who] {
+		if n := e.item_aura_bonus(it.item); n != 0 {
+			a += int(n)
+		}
+	}
+
+	return a
+}
+
+// max_current_aura returns the most current aura a mage may hold.
+// Ported from src/art.c lines 53-67.
+func (e *Engine) max_current_aura(who int) int {
+	aura := e.max_eff_aura(who) * 5
+
+	if aura < 0 {
+		aura = 0
+	}
+
+	if aura == 0 && e.char_auraculum(who) != 0 {
+		aura = 1
+	}
+
+	return aura
+}
+
+// limit_cur_aura clamps current aura to max_current_aura.
+// Ported from src/art.c lines 70-75.
+func (e *Engine) limit_cur_aura(who int) {
+	if e.char_cur_aura(who) > e.max_current_aura(who) {
+		e.p_magic(who).cur_aura = e.max_current_aura(who)
+	}
+}
+
+// v_forge_palantir starts forging a palantir.
+// Ported from src/art.c lines 78-87.
+func (e *Engine) v_forge_palantir(c *command) int {
+	if !e.check_aura(c.who, 8) {
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to create a palantir.")
+	return TRUE
+}
+
+// d_forge_palantir creates a palantir, a scrying artifact usable
+// once a month.
+// Ported from src/art.c lines 90-123.
+func (e *Engine) d_forge_palantir(c *command) int {
+	if !e.charge_aura(c.who, 8) {
+		return FALSE
+	}
+
+	newItem := e.create_unique_item(c.who, sub_palantir)
+	if newItem < 0 {
+		wout(c.who, "Spell failed.")
+		return FALSE
+	}
+
+	e.set_name(newItem, "Palantir")
+	e.p_item(newItem).weight = 2
+
+	pm := e.p_item_magic(newItem)
+	pm.use_key = use_palantir
+	pm.creator = c.who
+	pm.region_created = e.province(c.who)
+
+	wout(c.who, "Created %s.", e.box_name(newItem))
+
+	log_write(LOG_SPECIAL, "%s created %s.", e.box_name(c.who), e.box_name(newItem))
+
+	return TRUE
+}
+
+// v_use_palantir starts viewing a location through a palantir.
+// Ported from src/art.c lines 126-153.
+func (e *Engine) v_use_palantir(c *command) int {
+	item := c.a
+	target := c.b
+
+	if !e.is_loc_or_ship(target) {
+		wout(c.who, "%s is not a location.", e.box_code(target))
+		return FALSE
+	}
+
+	if p := e.rp_item_magic(item); p != nil && p.one_turn_use != 0 {
+		wout(c.who, "The palantir may only be used once per month.")
+		return FALSE
+	}
+
+	wout(c.who, "Will attempt to view %s with the palantir.", e.box_code(target))
+
+	c.wait = 7
+
+	return TRUE
+}
+
+// d_use_palantir shows the target location, unless it is shrouded
+// or in another region.
+// Ported from src/art.c lines 156-189.
+func (e *Engine) d_use_palantir(c *command) int {
+	item := c.a
+	target := c.b
+
+	if !e.is_loc_or_ship(target) {
+		wout(c.who, "%s is not a location.", e.box_code(target))
+		return FALSE
+	}
+
+	if e.loc_shroud(target) != 0 || e.diff_region(c.who, target) {
+		log_write(LOG_CODE, "Murky palantir result, who=%s, targ=%s",
+			box_code_less(c.who), box_code_less(target))
+		wout(c.who, "Only murky, indistinct images are seen in the palantir.")
+		return FALSE
+	}
+
+	log_write(LOG_CODE, "Palantir scry, who=%s, targ=%s",
+		box_code_less(c.who), box_code_less(target))
+
+	e.p_item_magic(item).one_turn_use++
+
+	wout(c.who, "A vision of %s appears:", e.box_name(target))
+	out(c.who, "")
+	e.show_loc(c.who, target)
+
+	e.alert_palantir_scry(c.who, target)
+
+	return TRUE
+}
+
+// destroyable_item reports whether item is a forged artifact that
+// Destroy artifact may be cast on.
+// Ported from src/art.c lines 192-204.
+func (e *Engine) destroyable_item(item int) bool {
+	switch e.subkind(item) {
+	case sub_palantir, sub_auraculum:
+		return true
+	}
+
+	return false
+}
+
+// v_destroy_art starts destroying a palantir or auraculum.
+// Ported from src/art.c lines 207-232.
+func (e *Engine) v_destroy_art(c *command) int {
+	item := c.a
+
+	if !e.valid_box(item) || e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s does not have %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	if !e.destroyable_item(item) {
+		wout(c.who, "Cannot destroy %s with this spell.", e.box_name(item))
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 2) {
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to destroy %s.", e.box_name(item))
+	return TRUE
+}
+
+// destroy_palantir leaves a gate crystal behind.
+// Ported from src/art.c lines 235-247.
+func (e *Engine) destroy_palantir(c *command, item int) bool {
+	wout(c.who, "Destroyed %s.", e.box_name(item))
+
+	e.gen_item(c.who, item_gate_crystal, 1)
+
+	wout(c.who, "Received one %s from the shattered palantir.", e.box_name(item_gate_crystal))
+
+	return true
+}
+
+// destroy_auraculum destroys an auraculum, killing its creator. The
+// spell must be cast in the province where the auraculum was forged,
+// and a mage can't destroy their own.
+// Ported from src/art.c lines 250-294.
+func (e *Engine) destroy_auraculum(c *command, item int) bool {
+	if e.province(c.who) != e.item_creat_loc(item) {
+		wout(c.who, "%s was not created here.  The spell fails.", e.box_name(item))
+		return false
+	}
+
+	creator := e.item_creator(item)
+
+	if creator == c.who {
+		wout(c.who, "Can't destroy one's own auraculum.")
+		return false
+	}
+
+	if e.valid_box(creator) && e.alive(creator) {
+		wout(creator, "The auraculum %s has been destroyed!", e.box_name(item))
+
+		wout(c.who, "For a brief instant, a vision of %s being consumed by fire appears, then fades away.",
+			e.box_name(creator))
+
+		e.kill_char(creator, MATES)
+	}
+
+	return true
+}
+
+// destroy_item destroys a forged artifact. Any aura stored in it
+// goes to the caster.
+// Ported from src/art.c lines 297-338.
+func (e *Engine) destroy_item(c *command, item int) bool {
+	var ret bool
+
+	switch e.subkind(item) {
+	case sub_palantir:
+		ret = e.destroy_palantir(c, item)
+	case sub_auraculum:
+		ret = e.destroy_auraculum(c, item)
+	default:
+		panic("destroy_item: not a destroyable artifact")
+	}
+
+	if !ret {
+		return false
+	}
+
+	if aura := int(e.item_aura(item)); aura > 0 {
+		e.p_magic(c.who).cur_aura += aura
+		wout(c.who, "Gained %s current aura.", nice_num(aura))
+	}
+
+	log_write(LOG_SPECIAL, "%s destroyed %s (%s, creator=%s)",
+		e.box_name(c.who), e.box_name(item),
+		subkind_s[e.subkind(item)],
+		e.box_name(e.item_creator(item)))
+
+	e.destroy_unique_item(c.who, item)
+	return true
+}
+
+// d_destroy_art destroys a palantir or auraculum.
+// Ported from src/art.c lines 341-365.
+func (e *Engine) d_destroy_art(c *command) int {
+	item := c.a
+
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s does not have %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	if !e.destroyable_item(item) {
+		wout(c.who, "Cannot destroy %s with this spell.", e.box_name(item))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, 2) {
+		return FALSE
+	}
+
+	if !e.destroy_item(c, item) {
+		return FALSE
+	}
+	return TRUE
+}
+
+// v_show_art_creat starts learning who created an artifact.
+// Ported from src/art.c lines 368-391.
+func (e *Engine) v_show_art_creat(c *command) int {
+	item := c.a
+
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s has no %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	if c.b < 1 {
+		c.b = 1
+	}
+
+	if !e.check_aura(c.who, c.b) {
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to learn the creator of %s.", e.box_name(item))
+
+	return TRUE
+}
+
+// d_show_art_creat reveals an artifact's creator, unless more aura
+// has been spent cloaking it than on the inspection.
+// Ported from src/art.c lines 394-430.
+func (e *Engine) d_show_art_creat(c *command) int {
+	item := c.a
+	aura := c.b
+
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s has no %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	if aura <= int(e.item_creat_cloak(item)) {
+		wout(c.who, "A magical shroud hinders inspection of %s.", e.box_name(item))
+		return FALSE
+	}
+
+	n := e.item_creator(item)
+
+	if !e.valid_box(n) {
+		wout(c.who, "The imprint of the maker's presence has faded from %s.  It is not possible to learn who created it.",
+			e.box_name(item))
+		return FALSE
+	}
+
+	wout(c.who, "%s created %s.", e.box_name(n), e.box_name(item))
+	return TRUE
+}
+
+// v_show_art_reg starts learning where an artifact was created.
+// Ported from src/art.c lines 433-456.
+func (e *Engine) v_show_art_reg(c *command) int {
+	item := c.a
+
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s has no %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	if c.b < 1 {
+		c.b = 1
+	}
+
+	if !e.check_aura(c.who, c.b) {
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to learn where %s was created.", e.box_name(item))
+
+	return TRUE
+}
+
+// d_show_art_reg reveals the province an artifact was created in.
+// Ported from src/art.c lines 459-496.
+func (e *Engine) d_show_art_reg(c *command) int {
+	item := c.a
+	aura := c.b
+
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s has no %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	if aura <= int(e.item_creat_cloak(item)) {
+		wout(c.who, "A magical shroud hinders inspection of %s.", e.box_name(item))
+		return FALSE
+	}
+
+	n := e.item_creat_loc(item)
+
+	if !e.valid_box(n) {
+		wout(c.who, "The location of creation is not recorded in %s.", e.box_name(item))
+		return FALSE
+	}
+
+	wout(c.who, "%s was created in %s.", e.box_name(item), e.char_rep_location(n))
+	return TRUE
+}
+
+// v_rem_art_cloak starts removing cloaking spells from an artifact.
+// The C original returns FALSE here, so the spell never runs; that
+// behavior is kept.
+// Ported from src/art.c lines 499-518.
+func (e *Engine) v_rem_art_cloak(c *command) int {
+	item := c.a
+
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s does not have %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 8) {
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to remove all cloaking spells from %s.", e.box_name(item))
+	return FALSE
+}
+
+// d_rem_art_cloak clears creator and region cloaking from an artifact.
+// Ported from src/art.c lines 521-552.
+func (e *Engine) d_rem_art_cloak(c *command) int {
+	item := c.a
+
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s does not have %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	im := e.rp_item_magic(item)
+	if im == nil {
+		wout(c.who, "%s is not cloaked in any way.", e.box_name(item))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, 8) {
+		return FALSE
+	}
+
+	im.cloak_creator = 0
+	im.cloak_region = 0
+	e.markDirty(item)
+
+	wout(c.who, "Cloaking spells removed from %s.", e.box_name(item))
+
+	return TRUE
+}
+
+// v_cloak_creat starts concealing an artifact's creator.
+// Ported from src/art.c lines 555-577.
+func (e *Engine) v_cloak_creat(c *command) int {
+	item := c.a
+
+	if c.b < 1 {
+		c.b = 1
+	}
+
+	if !e.valid_box(item) || e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s does not have %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to conceal the identity of the creator of %s.", e.box_name(item))
+
+	return TRUE
+}
+
+// d_cloak_creat adds the aura spent to the artifact's creator cloak.
+// Ported from src/art.c lines 580-605.
+func (e *Engine) d_cloak_creat(c *command) int {
+	item := c.a
+	aura := c.b
+
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s does not have %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	im := e.p_item_magic(item)
+	im.cloak_creator += schar(aura)
+
+	wout(c.who, "Creator cloaking in %s now %d.", e.box_name(item), im.cloak_creator)
+
+	return TRUE
+}
+
+// v_cloak_reg starts concealing where an artifact was created.
+// Ported from src/art.c lines 608-630.
+func (e *Engine) v_cloak_reg(c *command) int {
+	item := c.a
+
+	if c.b < 1 {
+		c.b = 1
+	}
+
+	if !e.valid_box(item) || e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s does not have %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to conceal the region of creation for %s.", e.box_name(item))
+
+	return TRUE
+}
+
+// d_cloak_reg adds the aura spent to the artifact's region cloak.
+// Ported from src/art.c lines 633-658.
+func (e *Engine) d_cloak_reg(c *command) int {
+	item := c.a
+	aura := c.b
+
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s does not have %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	im := e.p_item_magic(item)
+	im.cloak_region += schar(aura)
+
+	wout(c.who, "Region cloaking in %s now %d.", e.box_name(item), im.cloak_region)
+
+	return TRUE
+}
+
+// v_curse_noncreat starts cursing a forged artifact against anyone
+// but its creator.
+// Ported from src/art.c lines 661-694.
+func (e *Engine) v_curse_noncreat(c *command) int {
+	item := c.a
+
+	if c.b < 1 {
+		c.b = 1
+	}
+
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s does not have %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	// Only let forged artifacts be cursed, not just anything
+	if !e.destroyable_item(item) {
+		wout(c.who, "The curse can not be applied to %s.", e.box_name(item))
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to cast a noncreator possession curse on %s.", e.box_name(item))
+
+	return TRUE
+}
+
+// d_curse_noncreat adds the aura spent to the noncreator curse.
+// Ported from src/art.c lines 697-722.
+func (e *Engine) d_curse_noncreat(c *command) int {
+	item := c.a
+	aura := c.b
+
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s does not have %s.", e.box_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	im := e.p_item_magic(item)
+	im.curse_loyalty += schar(aura)
+
+	wout(c.who, "Noncreator curse on %s now %d.", e.box_name(item), im.curse_loyalty)
+
+	return TRUE
+}
+
+// v_forge_aura starts forging an auraculum. A mage may only forge
+// one, and needs 500 gold and a piece of mithril.
+// Ported from src/art.c lines 725-767.
+func (e *Engine) v_forge_aura(c *command) int {
+	if e.char_auraculum(c.who) != 0 {
+		wout(c.who, "%s may only be used once.", e.box_name(c.use_skill))
+		return FALSE
+	}
+
+	if c.a < 1 {
+		wout(c.who, "No aura-level specified.")
+		return FALSE
+	}
+	aura := c.a
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	if aura > e.char_max_aura(c.who) {
+		wout(c.who, "The specified amount of aura exceeds the maximum aura level of %s.",
+			e.box_name(c.who))
+		return FALSE
+	}
+
+	if !e.can_pay(c.who, 500) {
+		wout(c.who, "Requires %s.", gold_s(500))
+		return FALSE
+	}
+	if e.has_item(c.who, item_mithril) < 1 {
+		wout(c.who, "Requires %s.", e.box_name_qty(item_mithril, 1))
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to forge an auraculum.")
+	return TRUE
+}
+
+// notify_others_auraculum tells every other mage holding an auraculum
+// that a new one exists.
+// Ported from src/art.c lines 771-787.
+func (e *Engine) notify_others_auraculum(who, item int) {
+	for _, n := range e.Characters() {
+		if n != who && e.is_magician(n) != 0 && e.has_auraculum(n) != 0 {
+			wout(n, "Another auraculum has come into existence.")
+		}
+	}
+
+	log_write(LOG_SPECIAL, "%s created %s, %s.",
+		e.box_name(who), e.box_name(item), subkind_s[e.subkind(item)])
+}
+
+// d_forge_aura forges the auraculum. The aura invested comes out of
+// the mage's maximum aura, and the auraculum holds twice as much.
+// Ported from src/art.c lines 790-874.
+func (e *Engine) d_forge_aura(c *command) int {
+	aura := c.a
+
+	if aura > e.char_max_aura(c.who) || !e.check_aura(c.who, aura) {
+		wout(c.who, "%s does not have enough aura to create an auraculum that powerful.",
+			e.box_name(c.who))
+		return FALSE
+	}
+
+	if e.has_item(c.who, item_mithril) < 1 {
+		wout(c.who, "Requires %s.", e.box_name_qty(item_mithril, 1))
+		return FALSE
+	}
+
+	if !e.can_pay(c.who, 500) {
+		wout(c.who, "Requires %s.", gold_s(500))
+		return FALSE
+	}
+
+	e.charge_aura(c.who, aura)
+	e.charge(c.who, 500)
+	e.consume_item(c.who, item_mithril, 1)
+
+	var newName string
+	if e.numargs(c) < 2 {
+		switch e.rndFrom(streamMagic, 1, 3) {
+		case 1:
+			newName = "Gold ring"
+		case 2:
+			newName = "Wooden staff"
+		case 3:
+			newName = "Jeweled crown"
+		}
+	} else {
+		newName = get_parse_arg(c, 2)
+	}
+
+	newItem := e.create_unique_item(c.who, sub_auraculum)
+	if newItem < 0 {
+		wout(c.who, "Spell failed.")
+		return FALSE
+	}
+
+	e.set_name(newItem, newName)
+	e.p_item(newItem).weight = short(e.rndFrom(streamMagic, 1, 3))
+
+	pm := e.p_item_magic(newItem)
+	pm.creator = c.who
+	pm.region_created = e.province(c.who)
+	pm.aura = short(aura * 2)
+
+	cm := e.p_magic(c.who)
+	cm.auraculum = newItem
+	cm.max_aura -= aura
+
+	wout(c.who, "Created %s.", e.box_name(newItem))
+	e.notify_others_auraculum(c.who, newItem)
+
+	e.learn_skill(c.who, sk_adv_sorcery)
+
+	return TRUE
+}
+
+// new_orb creates a crystal orb good for three to nine scryings.
+// Returns the new orb, or 0 on failure.
+// Ported from src/art.c lines 877-899.
+func (e *Engine) new_orb(who int) int {
+	newItem := e.create_unique_item(who, 0)
+	if newItem < 0 {
+		wout(who, "Orb creation failed.")
+		return 0
+	}
+
+	e.set_name(newItem, "Orb")
+
+	e.p_item(newItem).weight = 1
+	pm := e.p_item_magic(newItem)
+	pm.use_key = use_orb
+	pm.lore = lore_orb
+	pm.orb_use_count = schar(e.rndFrom(streamMagic, 1, 4)*2 + 1)
+
+	return newItem
+}
+
+// v_use_orb scries the province of a location, character or unique
+// item. The orb shatters when its uses run out.
+// Ported from src/art.c lines 905-995.
+func (e *Engine) v_use_orb(c *command) int {
+	item := c.a
+	target := c.b
+	where := 0
+
+	if e.globals.orb_used_this_month.Lookup(item) >= 0 {
+		wout(c.who, "The orb may only be used once per month.")
+		wout(c.who, "Only murky, indistinct images are seen in the orb.")
+		return FALSE
+	}
+
+	e.globals.orb_used_this_month.Append(item)
+
+	if e.rndFrom(streamMagic, 1, 3) == 1 {
+		wout(c.who, "Only murky, indistinct images are seen in the orb.")
+		return FALSE
+	}
+
+	switch e.kind(target) {
+	case T_loc, T_ship, T_char:
+		where = e.province(target)
+
+	case T_item:
+		if owner := e.item_unique(target); owner != 0 {
+			where = e.province(owner)
+		}
+	}
+
+	switch {
+	case where == 0:
+		wout(c.who, "The orb is unsure what location is meant to be scried.")
+	case e.diff_region(where, c.who):
+		wout(c.who, "Only murky, indistinct images are seen.")
+	case e.loc_shroud(where) != 0:
+		wout(c.who, "The orb is unable to penetrate a shroud over %s.", e.box_name(where))
+	default:
+		wout(c.who, "A vision of %s appears:", e.box_name(where))
+		e.show_loc(c.who, where)
+		e.alert_scry_generic(c.who, where)
+	}
+
+	p := e.p_item_magic(item)
+
+	p.orb_use_count--
+	if p.orb_use_count <= 0 {
+		wout(c.who, "After the vision fades, the orb grows dark, and shatters.  The orb is gone")
+		e.destroy_unique_item(c.who, item)
+	}
+
+	return TRUE
+}
+
+// Artifact: npc token (small npc group controller)
+//
+// Tokens are recognized by sub_npc_token. The units a token controls
+// are kept in the token's unit list, and each unit's char_magic token
+// points back at the token. Token units take restricted orders
+// (cmd_allow 'r') and are LOY_npc.
+//
+// item_magic token_ni is the noble item for the controlled units, and
+// token_num is how many units the token controls.
+
+// token_player returns the faction token units should be sworn to:
+// the owner's faction if it is a regular player, otherwise indep.
+// Ported from src/art.c lines 1002-1015.
+func (e *Engine) token_player(owner int) int {
+	if e.kind(owner) != T_char {
+		return indep_player
+	}
+
+	pl := e.player(owner)
+	if e.subkind(pl) != sub_pl_regular {
+		return indep_player
+	}
+
+	return pl
+}
+
+// swear_token_units swears the token's units to target.
+// Ported from src/art.c lines 1018-1037.
+func (e *Engine) swear_token_units(item, target int) {
+	log_write(LOG_MISC, "%s got npc token %s", e.box_name(target), e.box_name(item))
+
+	if e.subkind(item) != sub_npc_token {
+		panic("swear_token_units: not an npc token")
+	}
+
+	for _, i := range e.getPlayerUnits(item) {
+		if e.kind(i) == T_char {
+			log_write(LOG_MISC, "   swearing %s", e.box_name_kind(i))
+			e.set_lord(i, target, LOY_UNCHANGED, 0)
+		}
+	}
+}
+
+// melt_token_units removes the token's units from the world.
+// Ported from src/art.c lines 1040-1066.
+func (e *Engine) melt_token_units(item int) {
+	first := true
+
+	if e.subkind(item) != sub_npc_token {
+		panic("melt_token_units: not an npc token")
+	}
+
+	// kill_char takes each unit off the token's list, so walk a copy
+	for _, who := range append([]int(nil), e.getPlayerUnits(item)...) {
+		if e.kind(who) != T_char {
+			continue
+		}
+
+		if first {
+			first = false
+			log_write(LOG_MISC, "Melting token units for %s.", e.box_name(item))
+		}
+
+		wout(e.subloc(who), "%s melts into the ground and vanishes.", e.box_name(who))
+		e.char_reclaim(who)
+	}
+
+	if e.item_token_num(item) > 1 {
+		e.p_item_magic(item).token_num = 1
+	}
+}
+
+// add_token_unit_sup creates one unit for the token near its owner.
+// Ported from src/art.c lines 1069-1109.
+func (e *Engine) add_token_unit_sup(item int) {
+	owner := e.item_unique(item)
+	if owner == 0 {
+		panic("add_token_unit_sup: token has no owner")
+	}
+
+	where := e.province(owner)
+	if e.subkind(where) == sub_ocean {
+		where = e.subloc(owner)
+	}
+
+	newChar := e.new_char(sub_ni, e.item_token_ni(item), where, -1,
+		e.token_player(owner), LOY_npc, 0, "")
+	if newChar < 0 {
+		log_write(LOG_CODE, "  FAILed to add unit to token %s", box_code_less(item))
+		return
+	}
+
+	log_write(LOG_MISC, "  adding %s to %s", e.box_name(newChar), e.box_name(item))
+
+	if beast_capturable(newChar) {
+		e.p_char(newChar).break_point = 0
+	}
+	e.p_misc(newChar).cmd_allow = 'r'
+	e.p_magic(newChar).token = item
+
+	e.addUnit(item, newChar)
+
+	wout(where, "%s appears.", e.box_name(newChar))
+}
+
+// add_token_units tops the token's units up to token_num.
+// Ported from src/art.c lines 1112-1130.
+func (e *Engine) add_token_units(item int) {
+	log_write(LOG_MISC, "add_token_units(%s)", e.box_name(item))
+
+	if e.subkind(item) != sub_npc_token {
+		panic("add_token_units: not an npc token")
+	}
+
+	for l := len(e.getPlayerUnits(item)); l < int(e.item_token_num(item)); l++ {
+		e.add_token_unit_sup(item)
+	}
+}
+
+// move_token is called when an NPC token moves from one owner to
+// another. Units controlled by the token are sworn to the new owner
+// if the owner belongs to a different faction. If a player just
+// acquired the token (not from another player, but from an indep,
+// npc, or via explore), the token units are created on the spot
+// instead of waiting for the end of the turn.
+// Ported from src/art.c lines 1142-1183.
+func (e *Engine) move_token(item, from, to int) {
+	to_pl := e.token_player(to)
+
+	log_write(LOG_MISC, "Token %s moved from %s (%s) to %s (%s)",
+		e.box_name(item),
+		e.box_name(from), e.box_name(e.token_player(from)),
+		e.box_name(to), e.box_name(to_pl))
+
+	if e.token_player(from) == indep_player && len(e.getPlayerUnits(item)) == 0 {
+		log_write(LOG_SPECIAL, "token %s from %d to 1.",
+			box_code_less(item), e.item_token_num(item))
+		e.p_item_magic(item).token_num = 1
+	}
+
+	if e.token_player(from) != to_pl {
+		e.swear_token_units(item, to_pl)
+
+		// Units aren't melted when the token goes indep until the end
+		// of the turn, since we might be in the middle of a command,
+		// and one of the token units may have caused the move.
+		if to_pl != indep_player {
+			e.add_token_units(item)
+		}
+	}
+}
+
+// check_token_units runs at the start and end of each turn. Tokens
+// held by a real player replace units killed this turn; otherwise
+// the token's units melt away until a player holds it again.
+// Ported from src/art.c lines 1186-1240.
+func (e *Engine) check_token_units() {
+	for _, item := range e.NpcTokens() {
+		owner := e.item_unique(item)
+		if owner == 0 {
+			panic("check_token_units: token has no owner")
+		}
+
+		pl := e.token_player(owner)
+
+		if pl == indep_player {
+			e.melt_token_units(item)
+		} else {
+			e.add_token_units(item)
+		}
+
+		for _, unit := range e.getPlayerUnits(item) {
+			if e.kind(unit) != T_char {
+				log_write(LOG_CODE, "%s holds unit %s which is %s, player(unit) = %s, owner = %s",
+					e.box_code(item), e.box_code(unit), kind_s[e.kind(unit)],
+					box_code_less(e.player(unit)), box_code_less(owner))
+				continue
+			}
+
+			if e.player(unit) != pl && e.player_np(pl) >= e.char_np_total(unit) {
+				log_write(LOG_CODE, "fixing token owner for %s (%s to %s)",
+					e.box_name_kind(unit), box_code_less(e.player(unit)), box_code_less(pl))
+
+				if e.player(unit) > 0 {
+					wout(e.player(unit), "%s renounces loyalty.", e.box_name(unit))
+				}
+				wout(pl, "%s swears loyalty.", e.box_name(unit))
+				e.set_lord(unit, pl, LOY_UNCHANGED, 0)
+			}
+		}
+	}
+}
+
+// create_npc_token creates a random npc token controlling one unit.
+// Ported from src/art.c lines 1243-1300.
+func (e *Engine) create_npc_token(who int) int {
+	newItem := e.create_unique_item(who, sub_npc_token)
+	if newItem < 0 {
+		return -1
+	}
+
+	var ni, lore int
+	var name string
+
+	switch e.rndFrom(streamMagic, 1, 5) {
+	case 1:
+		ni, name, lore = item_barbarian, "Crown of the Barbarians", lore_barbarian_npc_token
+	case 2:
+		ni, name, lore = item_savage, "Horn of the Savages", lore_savage_npc_token
+	case 3:
+		ni, name, lore = item_corpse, "Crown of the Undead lord", lore_undead_npc_token
+	case 4:
+		ni, name, lore = item_orc, "Golden idol of the Orcs", lore_orc_npc_token
+	case 5:
+		ni, name, lore = item_skeleton, "Banner of the Skeletons", lore_skeleton_npc_token
+	}
+
+	e.set_name(newItem, name)
+
+	pm := e.p_item_magic(newItem)
+	pm.token_num = 1
+	pm.token_ni = ni
+	pm.lore = lore
+
+	return newItem
+}
+
+// v_forge_art_x starts forging an enchanted weapon, armor or bow.
+// The aura invested (1 to 20) sets the bonus.
+// Ported from src/art.c lines 1303-1346.
+func (e *Engine) v_forge_art_x(c *command) int {
+	aura := c.a
+
+	if aura < 1 {
+		aura = 1
+	}
+	if aura > 20 {
+		aura = 20
+	}
+	c.a = aura
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	if !e.can_pay(c.who, 500) {
+		wout(c.who, "Requires %s.", gold_s(500))
+		return FALSE
+	}
+
+	var rare_item int
+	switch c.use_skill {
+	case sk_forge_weapon, sk_forge_armor:
+		rare_item = item_mithril
+	case sk_forge_bow:
+		rare_item = item_mallorn_wood
+	default:
+		panic("v_forge_art_x: bad skill")
+	}
+	c.d = rare_item
+
+	if e.has_item(c.who, rare_item) < 1 {
+		wout(c.who, "Requires %s.", e.box_name_qty(rare_item, 1))
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_forge_art_x forges the artifact, giving it an attack, defense or
+// missile bonus of five per aura.
+// Ported from src/art.c lines 1349-1415.
+func (e *Engine) d_forge_art_x(c *command) int {
+	aura := c.a
+	rare_item := c.d
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	if !e.charge(c.who, 500) {
+		wout(c.who, "Requires %s.", gold_s(500))
+		return FALSE
+	}
+
+	if e.has_item(c.who, rare_item) < 1 {
+		wout(c.who, "Requires %s.", e.box_name_qty(rare_item, 1))
+		return FALSE
+	}
+
+	e.charge_aura(c.who, aura)
+	e.consume_item(c.who, rare_item, 1)
+
+	newItem := e.create_unique_item(c.who, 0)
+	pm := e.p_item_magic(newItem)
+
+	var newName string
+	switch c.use_skill {
+	case sk_forge_weapon:
+		pm.attack_bonus = schar(aura * 5)
+		newName = "enchanted sword"
+	case sk_forge_armor:
+		pm.defense_bonus = schar(aura * 5)
+		newName = "enchanted armor"
+	case sk_forge_bow:
+		pm.missile_bonus = schar(aura * 5)
+		newName = "enchanted bow"
+	default:
+		panic("d_forge_art_x: bad skill")
+	}
+
+	if e.numargs(c) >= 2 && get_parse_arg(c, 2) != "" {
+		newName = get_parse_arg(c, 2)
+	}
+
+	e.set_name(newItem, newName)
+	e.p_item(newItem).weight = 10
+	pm.creator = c.who
+	pm.region_created = e.province(c.who)
+
+	wout(c.who, "Created %s.", e.box_name(newItem))
+
+	return TRUE
+}
+
+// new_suffuse_ring creates a golden ring that, when used, destroys
+// one kind of npc in the province.
+// Ported from src/art.c lines 1418-1464.
+func (e *Engine) new_suffuse_ring(who int) int {
+	newItem := e.create_unique_item(who, sub_suffuse_ring)
+	if newItem < 0 {
+		return -1
+	}
+
+	var ni, lore int
+
+	switch e.rndFrom(streamMagic, 1, 5) {
+	case 1:
+		ni, lore = use_barbarian_kill, lore_barbarian_kill
+	case 2:
+		ni, lore = use_savage_kill, lore_savage_kill
+	case 3:
+		ni, lore = use_corpse_kill, lore_undead_kill
+	case 4:
+		ni, lore = use_orc_kill, lore_orc_kill
+	case 5:
+		ni, lore = use_skeleton_kill, lore_skeleton_kill
+	}
+
+	e.set_name(newItem, "Golden ring")
+
+	e.p_item(newItem).weight = 1
+	pm := e.p_item_magic(newItem)
+	pm.use_key = schar(ni)
+	pm.lore = lore
+
+	return newItem
+}
+
+// v_suffuse_ring uses a golden ring. Two times in three, every item
+// of kind in the province vanishes and units made of it die. The
+// ring is used up either way.
+// Ported from src/art.c lines 1467-1514.
+func (e *Engine) v_suffuse_ring(c *command, kind_ int) int {
+	item := c.use_skill
+	where := e.province(e.subloc(c.who))
+
+	log_write(LOG_SPECIAL, "Golden ring %s used by %s",
+		box_code_less(item), box_code_less(e.player(c.who)))
+
+	if e.rndFrom(streamMagic, 1, 3) == 1 {
+		wout(c.who, "Nothing happens.")
+	} else {
+		wout(c.who, "A golden glow suffuses the province.")
+		wout(where, "A golden glow suffuses the province.")
+
+		var l []int
+		e.all_here(where, &l)
+
+		for _, num := range l {
+			wout(num, "A golden glow suffuses the province.")
+
+			if qty := e.has_item(num, kind_); qty > 0 {
+				wout(num, "%s vanished!", e.box_name_qty(kind_, qty))
+				e.consume_item(num, kind_, qty)
+			}
+
+			if e.subkind(num) == sub_ni && int(e.noble_item(num)) == kind_ {
+				e.kill_char(num, MATES)
+			}
+		}
+	}
+
+	wout(c.who, "%s vanishes.", e.box_name(item))
+	e.destroy_unique_item(c.who, item)
+
+	return TRUE
+}
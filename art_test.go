@@ -0,0 +1,263 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// art_test.go - Tests for artifacts and aura
+
+package taygete
+
+import "testing"
+
+// setupArtTest gives the necro test's mage the gold and mithril
+// needed to forge artifacts.
+func setupArtTest(t *testing.T) (pl, who, prov int) {
+	t.Helper()
+	pl, who, prov, _ = setupNecroTest(t)
+
+	teg.alloc_box(item_gold, T_item, 0)
+	teg.alloc_box(item_mithril, T_item, 0)
+	teg.alloc_box(sk_adv_sorcery, T_skill, 0)
+	teg.gen_item(who, item_gold, 1000)
+	teg.gen_item(who, item_mithril, 2)
+
+	teg.p_magic(who).magician = TRUE
+
+	return pl, who, prov
+}
+
+func TestForgeAuraculum(t *testing.T) {
+	_, who, prov := setupArtTest(t)
+
+	c := &command{who: who, a: 25}
+	if got := teg.v_forge_aura(c); got != FALSE {
+		t.Errorf("v_forge_aura above max aura = %d, want FALSE", got)
+	}
+
+	c = &command{who: who, a: 6}
+	if got := teg.v_forge_aura(c); got != TRUE {
+		t.Fatalf("v_forge_aura = %d, want TRUE", got)
+	}
+	if got := teg.d_forge_aura(c); got != TRUE {
+		t.Fatalf("d_forge_aura = %d, want TRUE", got)
+	}
+
+	ac := teg.has_auraculum(who)
+	if ac == 0 {
+		t.Fatal("mage does not hold an auraculum")
+	}
+	if teg.subkind(ac) != sub_auraculum || teg.item_creator(ac) != who || teg.item_creat_loc(ac) != prov {
+		t.Errorf("auraculum subkind/creator/region = %d/%d/%d", teg.subkind(ac), teg.item_creator(ac), teg.item_creat_loc(ac))
+	}
+	if got := teg.item_aura(ac); got != 12 {
+		t.Errorf("auraculum aura = %d, want 12", got)
+	}
+	if got := teg.char_max_aura(who); got != 14 {
+		t.Errorf("max_aura = %d, want 14", got)
+	}
+	if got := teg.max_eff_aura(who); got != 26 {
+		t.Errorf("max_eff_aura = %d, want 26", got)
+	}
+	if got := teg.char_cur_aura(who); got != 14 {
+		t.Errorf("cur_aura = %d, want 14", got)
+	}
+	if teg.has_item(who, item_gold) != 500 || teg.has_item(who, item_mithril) != 1 {
+		t.Errorf("gold/mithril = %d/%d, want 500/1", teg.has_item(who, item_gold), teg.has_item(who, item_mithril))
+	}
+	if !teg.has_skill(who, sk_adv_sorcery) {
+		t.Error("forging did not teach advanced sorcery")
+	}
+
+	if got := teg.v_forge_aura(&command{who: who, a: 1, use_skill: sk_forge_aura}); got != FALSE {
+		t.Errorf("second v_forge_aura = %d, want FALSE", got)
+	}
+}
+
+func TestIncrementCurrentAura(t *testing.T) {
+	_, who, _ := setupArtTest(t)
+
+	teg.p_magic(who).max_aura = 10
+	teg.p_magic(who).cur_aura = 5
+
+	teg.incrementCurrentAura()
+	if got := teg.char_cur_aura(who); got != 7 {
+		t.Errorf("cur_aura = %d, want 7 after natural rise", got)
+	}
+
+	// an auraculum grants two more points, a bonus item one more
+	ac := teg.create_unique_item(who, sub_auraculum)
+	teg.p_item_magic(ac).aura = 4
+	teg.p_magic(who).auraculum = ac
+	bonus := teg.create_unique_item(who, sub_artifact)
+	teg.p_item_magic(bonus).aura_bonus = 2
+
+	teg.incrementCurrentAura()
+	if got := teg.char_cur_aura(who); got != 12 {
+		t.Errorf("cur_aura = %d, want 12", got)
+	}
+
+	// never above max_eff_aura
+	teg.incrementCurrentAura()
+	teg.incrementCurrentAura()
+	teg.incrementCurrentAura()
+	if got, want := teg.char_cur_aura(who), teg.max_eff_aura(who); got != want || want != 16 {
+		t.Errorf("cur_aura = %d, max_eff_aura = %d, want 16", got, want)
+	}
+
+	teg.p_magic(who).magician = FALSE
+	teg.p_magic(who).cur_aura = 0
+	teg.incrementCurrentAura()
+	if got := teg.char_cur_aura(who); got != 0 {
+		t.Errorf("non-magician cur_aura = %d, want 0", got)
+	}
+}
+
+func TestForgeWeaponAndWield(t *testing.T) {
+	_, who, _ := setupArtTest(t)
+
+	c := &command{who: who, a: 4, use_skill: sk_forge_weapon}
+	if got := teg.v_forge_art_x(c); got != TRUE {
+		t.Fatalf("v_forge_art_x = %d, want TRUE", got)
+	}
+	if got := teg.d_forge_art_x(c); got != TRUE {
+		t.Fatalf("d_forge_art_x = %d, want TRUE", got)
+	}
+	if teg.has_item(who, item_gold) != 500 {
+		t.Errorf("gold = %d, want 500", teg.has_item(who, item_gold))
+	}
+
+	c = &command{who: who, a: 2, use_skill: sk_forge_armor}
+	if got := teg.v_forge_art_x(c); got != TRUE {
+		t.Fatalf("v_forge_art_x = %d, want TRUE", got)
+	}
+	teg.d_forge_art_x(c)
+
+	// a weaker sword is carried but not wielded
+	weak := teg.create_unique_item(who, 0)
+	teg.p_item_magic(weak).attack_bonus = 5
+
+	var w wield
+	if !teg.find_wield(&w, who) {
+		t.Fatal("find_wield found nothing")
+	}
+	if w.attack == 0 || w.attack == weak || w.defense == 0 || w.missile != 0 {
+		t.Errorf("wield = %+v", w)
+	}
+	if teg.just_name(w.attack) != "enchanted sword" {
+		t.Errorf("attack item = %q, want enchanted sword", teg.just_name(w.attack))
+	}
+
+	attack, defense, missile := teg.wield_bonus(who)
+	if attack != 20 || defense != 10 || missile != 0 {
+		t.Errorf("bonus = %d/%d/%d, want 20/10/0", attack, defense, missile)
+	}
+}
+
+func TestArtifactCloaking(t *testing.T) {
+	_, who, _ := setupArtTest(t)
+
+	c := &command{who: who, a: 1, use_skill: sk_forge_bow}
+	teg.alloc_box(item_mallorn_wood, T_item, 0)
+	teg.gen_item(who, item_mallorn_wood, 1)
+	teg.v_forge_art_x(c)
+	teg.d_forge_art_x(c)
+	_, _, missile := teg.wield_bonus(who)
+	if missile != 5 {
+		t.Fatalf("missile bonus = %d, want 5", missile)
+	}
+
+	var bow int
+	for _, e := range teg.globals.inventories[who] {
+		if teg.item_missile_bonus(e.item) != 0 {
+			bow = e.item
+		}
+	}
+
+	if got := teg.d_cloak_creat(&command{who: who, a: bow, b: 3}); got != TRUE {
+		t.Fatalf("d_cloak_creat = %d, want TRUE", got)
+	}
+	if got := teg.d_show_art_creat(&command{who: who, a: bow, b: 3}); got != FALSE {
+		t.Errorf("d_show_art_creat through the cloak = %d, want FALSE", got)
+	}
+	if got := teg.d_show_art_creat(&command{who: who, a: bow, b: 4}); got != TRUE {
+		t.Errorf("d_show_art_creat = %d, want TRUE", got)
+	}
+
+	if got := teg.d_rem_art_cloak(&command{who: who, a: bow}); got != TRUE {
+		t.Fatalf("d_rem_art_cloak = %d, want TRUE", got)
+	}
+	if teg.item_creat_cloak(bow) != 0 {
+		t.Errorf("creator cloak = %d, want 0", teg.item_creat_cloak(bow))
+	}
+}
+
+func TestDestroyPalantir(t *testing.T) {
+	_, who, _ := setupArtTest(t)
+	teg.alloc_box(item_gate_crystal, T_item, 0)
+
+	if got := teg.d_forge_palantir(&command{who: who}); got != TRUE {
+		t.Fatalf("d_forge_palantir = %d, want TRUE", got)
+	}
+
+	var pal int
+	for _, e := range teg.globals.inventories[who] {
+		if teg.subkind(e.item) == sub_palantir {
+			pal = e.item
+		}
+	}
+	if pal == 0 || teg.item_use_key(pal) != use_palantir {
+		t.Fatal("no palantir created")
+	}
+
+	c := &command{who: who, a: pal}
+	if got := teg.v_destroy_art(c); got != TRUE {
+		t.Fatalf("v_destroy_art = %d, want TRUE", got)
+	}
+	if got := teg.d_destroy_art(c); got != TRUE {
+		t.Fatalf("d_destroy_art = %d, want TRUE", got)
+	}
+	if teg.has_item(who, pal) != 0 {
+		t.Error("palantir still held")
+	}
+	if teg.has_item(who, item_gate_crystal) != 1 {
+		t.Error("no gate crystal from the shattered palantir")
+	}
+}
+
+func TestMayDefeat(t *testing.T) {
+	_, who, _ := setupArtTest(t)
+
+	monster := 1002
+	teg.alloc_box(monster, T_char, 0)
+
+	if !teg.may_defeat(who, monster) {
+		t.Error("may_defeat = false for an ordinary monster")
+	}
+
+	relic := teg.create_unique_item(who, sub_artifact)
+	teg.move_item(who, monster, relic, 1)
+	teg.p_misc(monster).only_vuln = relic
+
+	if teg.may_defeat(who, monster) {
+		t.Error("may_defeat = true without the artifact")
+	}
+	if !teg.cannot_take_prisoners(monster) {
+		t.Error("artifact guardian may take prisoners")
+	}
+
+	teg.move_item(monster, who, relic, 1)
+	if !teg.may_defeat(who, monster) {
+		t.Error("may_defeat = false while holding the artifact")
+	}
+}
@@ -0,0 +1,780 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// basic.go - Basic magic spells ported from src/basic.c
+
+package taygete
+
+// v_heal starts casting Heal on a sick character. The caster may
+// spend one to three aura; more aura makes the spell less likely to fail.
+// Ported from src/basic.c lines 210-240.
+func (e *Engine) v_heal(c *command) int {
+	target := c.a
+
+	if c.b < 1 {
+		c.b = 1
+	}
+	if c.b > 3 {
+		c.b = 3
+	}
+	aura := c.b
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	where := e.reset_cast_where(c.who)
+	c.d = where
+
+	if !e.check_char_where(where, c.who, target) {
+		return FALSE
+	}
+
+	if e.char_sick(target) == 0 {
+		wout(c.who, "%s is not sick.", e.box_name(target))
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_heal cures the target of illness. The spell fails 30%, 15% or 5%
+// of the time for one, two or three aura.
+// Ported from src/basic.c lines 243-306.
+func (e *Engine) d_heal(c *command) int {
+	target := c.a
+	aura := c.b
+	where := c.d
+
+	if e.kind(target) != T_char {
+		wout(c.who, "%s is no longer a character.", e.box_code(target))
+		return FALSE
+	}
+
+	if !e.check_char_where(where, c.who, target) {
+		return FALSE
+	}
+
+	if e.char_sick(target) == 0 {
+		wout(c.who, "%s is not sick.", e.box_name(target))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	var chance int
+	switch aura {
+	case 1:
+		chance = 30
+	case 2:
+		chance = 15
+	case 3:
+		chance = 5
+	default:
+		panic("d_heal: aura out of range")
+	}
+
+	vector_clear()
+	vector_add(c.who)
+	vector_add(target)
+
+	wout(VECT, "%s casts Heal on %s:", e.box_name(c.who), e.box_name(target))
+
+	if e.rndFrom(streamSkills, 1, 100) <= chance {
+		wout(VECT, "Spell fails.")
+		return FALSE
+	}
+
+	e.p_char(target).sick = FALSE
+
+	wout(VECT, "%s has been cured, and should now recover.", e.box_name(target))
+
+	return TRUE
+}
+
+// v_meditate starts meditating to restore aura.
+// Ported from src/basic.c lines 12-18.
+func (e *Engine) v_meditate(c *command) int {
+	wout(c.who, "Meditate for %s.", weeks(c.wait))
+	return TRUE
+}
+
+// hinder_med_chance returns the percent chance that who's meditation
+// is ruined by Hinder meditation.
+// Ported from src/basic.c lines 21-42.
+func (e *Engine) hinder_med_chance(who int) int {
+	p := e.rp_magic(who)
+	if p == nil || p.hinder_meditation < 1 {
+		return 0
+	}
+
+	switch p.hinder_meditation {
+	case 1:
+		return 10
+	case 2:
+		return 25
+	case 3:
+		return 50
+	case 4:
+		return 75
+	case 5:
+		return 90
+	}
+	panic("hinder_med_chance: hinder_meditation out of range")
+}
+
+// d_meditate restores a twentieth of maximum aura, at least one,
+// up to one over the maximum. Clears any hindrance.
+// Ported from src/basic.c lines 45-73.
+func (e *Engine) d_meditate(c *command) int {
+	chance := e.hinder_med_chance(c.who)
+
+	p := e.p_magic(c.who)
+	p.hinder_meditation = 0
+
+	if e.rndFrom(streamMagic, 1, 100) <= chance {
+		wout(c.who, "Disturbing images and unquiet thoughts ruin the meditative trance.  Meditation fails.")
+		return FALSE
+	}
+
+	bonus := max(1, e.max_eff_aura(c.who)/20)
+
+	p.cur_aura += bonus
+	p.cur_aura = min(p.cur_aura, e.max_eff_aura(c.who)+1)
+
+	wout(c.who, "Current aura is now %d.", p.cur_aura)
+	return TRUE
+}
+
+// v_adv_med starts advanced meditation.
+// Ported from src/basic.c lines 76-82.
+func (e *Engine) v_adv_med(c *command) int {
+	wout(c.who, "Meditate for %s.", weeks(c.wait))
+	return TRUE
+}
+
+// d_adv_med restores a tenth of maximum aura, at least two, up to
+// two over the maximum. A hindered trance restores only one.
+// Ported from src/basic.c lines 85-115.
+func (e *Engine) d_adv_med(c *command) int {
+	chance := e.hinder_med_chance(c.who)
+
+	p := e.p_magic(c.who)
+	p.hinder_meditation = 0
+
+	bonus := max(2, e.max_eff_aura(c.who)/10)
+
+	if e.rndFrom(streamMagic, 1, 100) <= chance {
+		wout(c.who, "Disturbing images and unquiet thoughts hamper the meditative trance.")
+		bonus = 1
+	}
+
+	p.cur_aura += bonus
+	p.cur_aura = min(p.cur_aura, e.max_eff_aura(c.who)+2)
+
+	wout(c.who, "Current aura is now %d.", p.cur_aura)
+	return TRUE
+}
+
+// v_hinder_med starts casting Hinder meditation on a character, with
+// one to three aura.
+// Ported from src/basic.c lines 118-144.
+func (e *Engine) v_hinder_med(c *command) int {
+	target := c.a
+
+	c.b = min(max(c.b, 1), 3)
+	aura := c.b
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	where := e.reset_cast_where(c.who)
+	c.d = where
+
+	if !e.check_char_where(where, c.who, target) {
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to hinder attempts at meditation by %s.", e.box_name(c.who))
+
+	return TRUE
+}
+
+// hinder_med_omen sends who a troubling omen about other, or nothing.
+// Ported from src/basic.c lines 147-177.
+func (e *Engine) hinder_med_omen(who, other int) {
+	switch e.rndFrom(streamMagic, 1, 4) {
+	case 1:
+		wout(who, "A disturbing image of %s appeared last night in a dream.", e.box_name(other))
+	case 2:
+		wout(who, "As a cloud drifts across the moon, it seems for an instant that it takes the shape of a ghoulish face, looking straight at you.")
+	case 3:
+		wout(who, "You are shocked out of your slumber in the middle of the night by cold fingers touching your neck, but when you glance about, there is no one to be seen.")
+	}
+}
+
+// d_hinder_med adds the aura spent to the target's meditation
+// hindrance, to a maximum of five.
+// Ported from src/basic.c lines 180-207.
+func (e *Engine) d_hinder_med(c *command) int {
+	target := c.a
+	aura := c.b
+	where := c.d
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	if !e.check_char_where(where, c.who, target) {
+		return FALSE
+	}
+
+	wout(c.who, "Successfully cast %s on %s.", e.box_name(sk_hinder_med), e.box_name(target))
+
+	p := e.p_magic(target)
+	p.hinder_meditation = schar(min(int(p.hinder_meditation)+aura, 5))
+
+	e.hinder_med_omen(target, c.who)
+
+	return TRUE
+}
+
+// v_reveal_mage starts scrying the spells a character knows within
+// one magical category. A bad category is corrected rather than
+// rejected.
+// Ported from src/basic.c lines 309-353.
+func (e *Engine) v_reveal_mage(c *command) int {
+	target := c.a
+	category := c.b
+
+	c.c = max(c.c, 1)
+	aura := c.c
+
+	if !e.valid_box(category) {
+		wout(c.who, "%d is not a valid skill category.", category)
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	where := e.reset_cast_where(c.who)
+	c.d = where
+	if !e.check_char_where(where, c.who, target) {
+		return FALSE
+	}
+
+	if e.skill_school(category) != category || !e.magic_skill(category) {
+		wout(c.who, "%s is not a magical skill category.", e.box_code(category))
+		if !e.magic_skill(category) {
+			category = sk_basic
+		} else {
+			category = e.skill_school(category)
+		}
+		wout(c.who, "Assuming %s.", e.box_name(category))
+
+		c.b = category
+	}
+
+	wout(c.who, "Attempt to scry the magical abilities of %s within %s.", e.box_name(target), e.box_name(category))
+
+	return TRUE
+}
+
+// d_reveal_mage lists the target's spells in the category unless an
+// ability shroud at least as strong as the aura spent hides them.
+// Targets with Detect ability scry learn of the attempt.
+// Ported from src/basic.c lines 356-444.
+func (e *Engine) d_reveal_mage(c *command) int {
+	target := c.a
+	category := c.b
+	aura := c.c
+	where := c.d
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	if !e.check_char_where(where, c.who, target) {
+		return FALSE
+	}
+
+	if !e.valid_box(category) || e.skill_school(category) != category || !e.magic_skill(category) {
+		panic("d_reveal_mage: not a magical skill category")
+	}
+
+	hasDetect := e.has_skill_level(target, sk_detect_abil)
+
+	source := "Someone"
+	if hasDetect > exp_novice {
+		source = e.box_name(c.who)
+	}
+
+	if aura <= int(e.char_abil_shroud(target)) {
+		wout(c.who, "The abilities of %s are shrouded from your scry.", e.box_name(target))
+
+		if hasDetect != 0 {
+			wout(target, "%s cast %s on us, but failed to learn anything.", source, e.box_name(sk_reveal_mage))
+		}
+
+		if hasDetect > exp_teacher {
+			wout(target, "They sought to learn what we know of %s.", e.box_name(category))
+		}
+
+		return FALSE
+	}
+
+	first := true
+	for _, sk := range e.getCharSkills(target) {
+		if sk.know != SKILL_know || e.skill_school(sk.skill) != category || sk.skill == category {
+			continue
+		}
+
+		if first {
+			wout(c.who, "%s knows the following %s spells:", e.box_name(target), e.box_name(category))
+			e.globals.indent += 3
+			first = false
+		}
+
+		if c.use_exp > exp_journeyman {
+			e.list_skill_sup(c.who, sk)
+		} else {
+			wout(c.who, "%s", e.box_name(sk.skill))
+		}
+	}
+
+	if first {
+		wout(c.who, "%s knowns no %s spells.", e.box_name(target), e.box_name(category))
+	} else {
+		e.globals.indent -= 3
+	}
+
+	if hasDetect != 0 {
+		wout(target, "%s successfully cast %s on us.", source, e.box_name(sk_reveal_mage))
+
+		if hasDetect > exp_teacher {
+			wout(target, "Our knowledge of %s was revealed.", e.box_name(category))
+		}
+	}
+
+	return TRUE
+}
+
+// v_view_aura starts scrying the aura of the mages where the caster is.
+// Ported from src/basic.c lines 447-467.
+func (e *Engine) v_view_aura(c *command) int {
+	c.a = max(c.a, 1)
+	aura := c.a
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	where := e.reset_cast_where(c.who)
+	c.d = where
+
+	wout(c.who, "Will scry the current aura ratings of other mages in %s.", e.box_name(where))
+
+	return TRUE
+}
+
+// d_view_aura reports the current aura of each mage at the cast
+// location, except those shrouded by at least the aura spent.
+// Ported from src/basic.c lines 470-546.
+func (e *Engine) d_view_aura(c *command) int {
+	aura := c.a
+	where := c.d
+
+	if !e.is_loc_or_ship(where) {
+		wout(c.who, "%s is no longer a valid location.", e.box_code(where))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	first := true
+	var here []int
+	e.loop_char_here(where, &here)
+	for _, n := range here {
+		if e.is_magician(n) == 0 {
+			continue
+		}
+
+		s, learned := "???", false
+		if aura > int(e.char_abil_shroud(n)) {
+			s, learned = sout("%d", e.char_cur_aura(n)), true
+		}
+
+		wout(c.who, "%s, current aura: %s", e.box_name(n), s)
+		first = false
+
+		// Does the viewed magician have Detect ability scry?
+		hasDetect := e.has_skill_level(n, sk_detect_abil)
+
+		source := "Someone"
+		if hasDetect > exp_novice {
+			source = e.box_name(c.who)
+		}
+
+		if hasDetect != 0 {
+			wout(n, "%s cast View aura here.", source)
+		}
+
+		if hasDetect > exp_journeyman {
+			if learned {
+				wout(n, "Our current aura rating was learned.")
+			} else {
+				wout(n, "Our current aura rating was not revealed.")
+			}
+		}
+	}
+
+	if first {
+		wout(c.who, "No mages are seen here.")
+		log_write(LOG_CODE, "d_view_aura: not a mage?")
+	}
+
+	return TRUE
+}
+
+// v_shroud_abil starts casting an ability shroud on the caster.
+// Ported from src/basic.c lines 549-562.
+func (e *Engine) v_shroud_abil(c *command) int {
+	c.a = max(c.a, 1)
+
+	wout(c.who, "Attempt to create a magical shroud to conceal our abilities.")
+
+	return TRUE
+}
+
+// d_shroud_abil adds the aura spent to the caster's ability shroud.
+// Ported from src/basic.c lines 565-581.
+func (e *Engine) d_shroud_abil(c *command) int {
+	aura := c.a
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	p := e.p_magic(c.who)
+	p.ability_shroud += short(aura)
+
+	wout(c.who, "Now cloaked in an aura %s ability shroud.", nice_num(int(p.ability_shroud)))
+
+	return TRUE
+}
+
+// v_detect_abil starts practicing Detect ability scry.
+// Ported from src/basic.c lines 584-593.
+func (e *Engine) v_detect_abil(c *command) int {
+	if !e.check_aura(c.who, 1) {
+		return FALSE
+	}
+
+	wout(c.who, "Will practice ability scry detection.")
+	return TRUE
+}
+
+// d_detect_abil finishes practicing Detect ability scry. The skill
+// itself works passively.
+// Ported from src/basic.c lines 596-604.
+func (e *Engine) d_detect_abil(c *command) int {
+	if !e.charge_aura(c.who, 1) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// v_dispel_abil starts dispelling a character's ability shroud.
+// Ported from src/basic.c lines 607-626.
+func (e *Engine) v_dispel_abil(c *command) int {
+	target := c.a
+
+	if !e.check_aura(c.who, 3) {
+		return FALSE
+	}
+
+	where := e.reset_cast_where(c.who)
+	c.d = where
+
+	if !e.check_char_where(where, c.who, target) {
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to dispel any ability shroud from %s.", e.box_name(target))
+
+	return TRUE
+}
+
+// d_dispel_abil removes the target's ability shroud. Aura is only
+// charged if there was a shroud to dispel.
+// Ported from src/basic.c lines 629-658.
+func (e *Engine) d_dispel_abil(c *command) int {
+	target := c.a
+	where := c.d
+
+	if !e.check_char_where(where, c.who, target) {
+		return FALSE
+	}
+
+	p := e.rp_magic(target)
+	if p == nil || p.ability_shroud <= 0 {
+		wout(c.who, "%s had no ability shroud.", e.box_name(target))
+		return TRUE
+	}
+
+	if !e.charge_aura(c.who, 3) {
+		return FALSE
+	}
+
+	wout(c.who, "Dispeled an aura %s ability shroud from %s.", nice_num(int(p.ability_shroud)), e.box_name(target))
+	p.ability_shroud = 0
+	e.markDirty(target)
+	wout(target, "The magical ability shroud has dissipated.")
+
+	return TRUE
+}
+
+// v_quick_cast starts storing a speedup for the next spell cast.
+// Ported from src/basic.c lines 661-676.
+func (e *Engine) v_quick_cast(c *command) int {
+	c.a = max(c.a, 1)
+	aura := c.a
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to speed next spell cast.")
+
+	return TRUE
+}
+
+// d_quick_cast adds the aura spent to the caster's stored speedup.
+// Ported from src/basic.c lines 679-694.
+func (e *Engine) d_quick_cast(c *command) int {
+	aura := c.a
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	p := e.p_magic(c.who)
+	p.quick_cast += short(aura)
+
+	wout(c.who, "Spell cast speedup now %d.", p.quick_cast)
+
+	return TRUE
+}
+
+// v_save_quick starts saving the stored speedup into a potion.
+// Ported from src/basic.c lines 697-712.
+func (e *Engine) v_save_quick(c *command) int {
+	if e.char_quick_cast(c.who) < 1 {
+		wout(c.who, "No stored spell cast speedup.")
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 3) {
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to save speeded cast state.")
+	return TRUE
+}
+
+// d_save_quick moves the stored speedup into a new potion.
+// Ported from src/basic.c lines 715-748.
+func (e *Engine) d_save_quick(c *command) int {
+	if e.char_quick_cast(c.who) < 1 {
+		wout(c.who, "No stored spell cast speedup.")
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, 3) {
+		return FALSE
+	}
+
+	newItem := e.new_potion(c.who)
+	if newItem < 0 {
+		wout(c.who, "Spell failed.")
+		return FALSE
+	}
+
+	p := e.p_magic(c.who)
+	im := e.p_item_magic(newItem)
+
+	im.use_key = use_quick_cast
+	im.quick_cast = p.quick_cast
+
+	p.quick_cast = 0
+
+	return TRUE
+}
+
+// v_use_quick_cast drinks a potion of stored speedup.
+// Ported from src/basic.c lines 751-776.
+func (e *Engine) v_use_quick_cast(c *command) int {
+	item := c.a
+
+	if e.kind(item) != T_item {
+		panic("v_use_quick_cast: not an item")
+	}
+
+	wout(c.who, "%s drinks the potion...", e.just_name(c.who))
+
+	im := e.rp_item_magic(item)
+	if im == nil || im.quick_cast < 1 || e.is_magician(c.who) == 0 {
+		wout(c.who, "Nothing happens.")
+		e.destroy_unique_item(c.who, item)
+		return FALSE
+	}
+
+	e.p_magic(c.who).quick_cast += im.quick_cast
+
+	wout(c.who, "Spell cast speedup now %d.", e.char_quick_cast(c.who))
+	e.destroy_unique_item(c.who, item)
+
+	return TRUE
+}
+
+// v_write_spell starts scribing a known skill onto a scroll. Magical
+// scribing skills only write spells of their own school.
+// Ported from src/basic.c lines 779-820.
+func (e *Engine) v_write_spell(c *command) int {
+	spell := c.a
+
+	if !e.has_skill(c.who, spell) {
+		wout(c.who, "%s does not know %s.", e.box_name(c.who), e.box_code(spell))
+		return FALSE
+	}
+
+	if !e.magic_skill(c.use_skill) && e.magic_skill(spell) {
+		wout(c.who, "Magical skills may not be scribed with %s.", e.box_name(c.use_skill))
+		return FALSE
+	}
+
+	if e.magic_skill(c.use_skill) && e.skill_school(spell) != e.skill_school(c.use_skill) {
+		wout(c.who, "%s only allows %s spells to be scribed.", e.box_code(c.use_skill), e.box_name(e.skill_school(c.use_skill)))
+		return FALSE
+	}
+
+	if e.magic_skill(c.use_skill) && !e.check_aura(c.who, 2) {
+		return FALSE
+	}
+
+	c.wait = max(7, e.learn_time(spell))
+
+	wout(c.who, "Spend %s writing %s onto a scroll.", weeks(c.wait), e.box_name(spell))
+
+	return TRUE
+}
+
+// new_scroll creates a blank scroll in who's inventory.
+// Returns the new item, or 0 if no entity could be allocated.
+// Ported from src/basic.c lines 823-847.
+func (e *Engine) new_scroll(who int) int {
+	newItem := e.create_unique_item(who, sub_scroll)
+	if newItem < 0 {
+		wout(who, "Scroll creation failed.")
+		return 0
+	}
+
+	e.set_name(newItem, "Scroll")
+
+	p := e.p_item_magic(newItem)
+	p.creator = who
+	p.region_created = e.province(who)
+	e.p_item(newItem).weight = 1
+
+	wout(who, "Produced %s.", e.box_name(newItem))
+
+	return newItem
+}
+
+// d_write_spell produces a scroll from which the spell may be studied.
+// Ported from src/basic.c lines 850-872.
+func (e *Engine) d_write_spell(c *command) int {
+	spell := c.a
+
+	if !e.has_skill(c.who, spell) {
+		wout(c.who, "%s does not know %s.", e.box_name(c.who), e.box_code(spell))
+		return FALSE
+	}
+
+	if e.magic_skill(c.use_skill) && !e.charge_aura(c.who, 2) {
+		return FALSE
+	}
+
+	newItem := e.new_scroll(c.who)
+	if newItem == 0 {
+		return FALSE
+	}
+	e.p_item_magic(newItem).may_study.Append(spell)
+
+	return TRUE
+}
+
+// v_appear_common hides the caster's magician status for a number
+// of turns equal to the aura spent.
+// Ported from src/basic.c lines 875-896.
+func (e *Engine) v_appear_common(c *command) int {
+	aura := max(c.a, 1)
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	p := e.p_magic(c.who)
+	if p.hide_mage == 0 {
+		p.hide_mage = 1
+	}
+	p.hide_mage += schar(aura)
+
+	wout(c.who, "Will appear common until the end of turn %d.", int(e.globals.sysclock.turn)+int(p.hide_mage)-1)
+
+	return TRUE
+}
+
+// v_tap_health starts converting health into aura.
+// Ported from src/basic.c lines 899-904.
+func (e *Engine) v_tap_health(c *command) int {
+	return TRUE
+}
+
+// d_tap_health converts up to a fifth of the caster's health into
+// aura, at five points of damage for each point of aura.
+// Ported from src/basic.c lines 907-926.
+func (e *Engine) d_tap_health(c *command) int {
+	amount := min(c.a, int(e.char_health(c.who))/5)
+
+	pm := e.p_magic(c.who)
+	pm.cur_aura += amount
+
+	e.limit_cur_aura(c.who)
+
+	wout(c.who, "Current aura is now %d.", pm.cur_aura)
+	e.add_char_damage(c.who, amount*5, MATES)
+
+	return TRUE
+}
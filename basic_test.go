@@ -0,0 +1,180 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// basic_test.go - Tests for basic magic spells
+
+package taygete
+
+import "testing"
+
+// setupBasicTest builds a magician with the given aura ratings.
+func setupBasicTest(t *testing.T, maxAura, curAura int) (who int) {
+	t.Helper()
+	e := newTestEngine(t)
+
+	where := 10_101
+	e.alloc_box(where, T_loc, sub_plain)
+
+	who = 1001
+	e.alloc_box(who, T_char, 0)
+	e.set_where(who, where)
+	e.p_char(who).health = 100
+	p := e.p_magic(who)
+	p.magician = TRUE
+	p.max_aura = maxAura
+	p.cur_aura = curAura
+	return who
+}
+
+func TestMeditate(t *testing.T) {
+	who := setupBasicTest(t, 40, 10)
+
+	c := &command{who: who}
+	if got := teg.d_meditate(c); got != TRUE {
+		t.Fatalf("d_meditate = %d, want TRUE", got)
+	}
+	if got := teg.char_cur_aura(who); got != 12 {
+		t.Errorf("aura after meditating = %d, want 12", got)
+	}
+
+	// meditation tops out one over the maximum
+	teg.p_magic(who).cur_aura = 40
+	teg.d_meditate(c)
+	teg.d_meditate(c)
+	if got := teg.char_cur_aura(who); got != 41 {
+		t.Errorf("aura after meditating at maximum = %d, want 41", got)
+	}
+
+	// a fully hindered trance fails nine times in ten, and the
+	// hindrance is used up either way
+	fails := 0
+	for range 20 {
+		teg.p_magic(who).hinder_meditation = 5
+		if teg.d_meditate(c) == FALSE {
+			fails++
+		}
+		if teg.rp_magic(who).hinder_meditation != 0 {
+			t.Fatal("hinder_meditation was not cleared")
+		}
+	}
+	if fails < 10 {
+		t.Errorf("hindered meditation failed %d of 20 times, want most", fails)
+	}
+}
+
+func TestQuickCastPotion(t *testing.T) {
+	who := setupBasicTest(t, 20, 20)
+	teg.alloc_box(sk_save_quick, T_skill, 0)
+
+	c := &command{who: who}
+	if got := teg.v_save_quick(c); got != FALSE {
+		t.Errorf("v_save_quick without a speedup = %d, want FALSE", got)
+	}
+
+	c.a = 4
+	if teg.v_quick_cast(c) != TRUE || teg.d_quick_cast(c) != TRUE {
+		t.Fatal("quick cast failed")
+	}
+	if got := teg.char_quick_cast(who); got != 4 {
+		t.Fatalf("quick_cast = %d, want 4", got)
+	}
+
+	if teg.v_save_quick(c) != TRUE || teg.d_save_quick(c) != TRUE {
+		t.Fatal("save quick failed")
+	}
+	if got := teg.char_quick_cast(who); got != 0 {
+		t.Errorf("quick_cast after saving = %d, want 0", got)
+	}
+	if got := teg.char_cur_aura(who); got != 13 {
+		t.Errorf("aura = %d, want 13 after spending 4 and 3", got)
+	}
+
+	var potion int
+	for _, it := range teg.globals.inventories[who] {
+		if teg.item_use_key(it.item) == use_quick_cast {
+			potion = it.item
+		}
+	}
+	if potion == 0 {
+		t.Fatal("no quick cast potion was made")
+	}
+
+	c = &command{who: who, a: potion}
+	if got := teg.v_use_item(c); got != TRUE {
+		t.Fatalf("v_use_item(potion) = %d, want TRUE", got)
+	}
+	if got := teg.char_quick_cast(who); got != 4 {
+		t.Errorf("quick_cast after drinking = %d, want 4", got)
+	}
+	if teg.has_item(who, potion) != 0 {
+		t.Error("potion was not used up")
+	}
+}
+
+func TestWriteSpell(t *testing.T) {
+	who := setupBasicTest(t, 10, 10)
+	teg.alloc_box(sk_basic, T_skill, sub_magic)
+	teg.alloc_box(sk_meditate, T_skill, 0)
+	teg.alloc_box(sk_write_basic, T_skill, 0)
+	teg.p_skill(sk_meditate).required_skill = sk_basic
+	teg.p_skill(sk_write_basic).required_skill = sk_basic
+	teg.p_skill_ent(who, sk_write_basic).know = SKILL_know
+	teg.p_skill_ent(who, sk_meditate).know = SKILL_dont
+
+	c := &command{who: who, a: sk_meditate, use_skill: sk_write_basic}
+	if got := teg.v_write_spell(c); got != FALSE {
+		t.Errorf("v_write_spell of an unknown spell = %d, want FALSE", got)
+	}
+
+	teg.p_skill_ent(who, sk_meditate).know = SKILL_know
+	if got := teg.v_write_spell(c); got != TRUE {
+		t.Fatalf("v_write_spell = %d, want TRUE", got)
+	}
+	if got := teg.d_write_spell(c); got != TRUE {
+		t.Fatalf("d_write_spell = %d, want TRUE", got)
+	}
+	if got := teg.char_cur_aura(who); got != 8 {
+		t.Errorf("aura = %d, want 8", got)
+	}
+
+	scrolls := 0
+	for _, it := range teg.globals.inventories[who] {
+		if teg.subkind(it.item) == sub_scroll {
+			scrolls++
+			if got := teg.rp_item_magic(it.item).may_study.Values(); len(got) != 1 || got[0] != sk_meditate {
+				t.Errorf("scroll teaches %v, want [%d]", got, sk_meditate)
+			}
+		}
+	}
+	if scrolls != 1 {
+		t.Errorf("scrolls = %d, want 1", scrolls)
+	}
+}
+
+func TestTapHealth(t *testing.T) {
+	who := setupBasicTest(t, 10, 0)
+
+	c := &command{who: who, a: 6}
+	if got := teg.d_tap_health(c); got != TRUE {
+		t.Fatalf("d_tap_health = %d, want TRUE", got)
+	}
+	if got := teg.char_cur_aura(who); got != 6 {
+		t.Errorf("aura = %d, want 6", got)
+	}
+	if got := teg.char_health(who); got != 70 {
+		t.Errorf("health = %d, want 70", got)
+	}
+}
@@ -0,0 +1,306 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// beast.go - Beastmastery skills ported from src/beast.c
+
+package taygete
+
+// v_bird_spy starts sending a bird to spy on a sublocation of this
+// province or a neighboring location.
+// Ported from src/beast.c lines 8-58.
+func (e *Engine) v_bird_spy(c *command) int {
+	targ := c.a
+	where := e.subloc(c.who)
+
+	if e.is_ship(where) {
+		where = e.loc(where)
+	}
+
+	if e.numargs(c) < 1 {
+		wout(c.who, "Specify what location the bird should spy on.")
+		return FALSE
+	}
+
+	if !e.is_loc_or_ship(c.a) {
+		v := e.parse_exit_dir(c, where, sout("use %d", sk_bird_spy))
+		if v == nil {
+			return FALSE
+		}
+
+		targ = v.destination
+	}
+
+	if e.province(targ) != e.province(c.who) {
+		okay := false
+		for _, v := range e.exits_from_loc(c.who, e.province(c.who)) {
+			if v.destination == targ {
+				okay = true
+			}
+		}
+
+		if !okay {
+			wout(c.who, "The location to be spied upon must be a sublocation in the same province or a neighboring location.")
+			return FALSE
+		}
+	}
+
+	c.d = targ
+
+	return TRUE
+}
+
+// d_bird_spy shows the bird's report of the location.
+// Ported from src/beast.c lines 61-77.
+func (e *Engine) d_bird_spy(c *command) int {
+	targ := c.d
+
+	if !e.is_loc_or_ship(targ) {
+		wout(c.who, "%s is not a location.", e.box_code(targ))
+		return FALSE
+	}
+
+	wout(c.who, "The bird returns with a report:")
+	out(c.who, "")
+	e.show_loc(c.who, targ)
+
+	return TRUE
+}
+
+// breed is a pair of beasts and what they breed. A species breeds
+// with itself unless an explicit {self, self, 0} is given.
+type breed struct {
+	i1, i2 int
+	result int
+}
+
+// breed_tbl lists the crossbreeds.
+// Ported from src/beast.c lines 85-102.
+var breed_tbl = []breed{
+	{item_peasant, item_ox, item_minotaur},
+	{item_peasant, item_wild_horse, item_centaur},
+	{item_wild_horse, item_wild_horse, item_wild_horse},
+	{item_lion, item_lizard, item_chimera},
+	{item_peasant, item_lion, item_harpie},
+	{item_lizard, item_bird, item_dragon},
+	{item_wild_horse, item_bird, item_pegasus},
+	{item_peasant, item_lizard, item_gorgon},
+	{item_rat, item_spider, item_ratspider},
+	{item_pegasus, item_dragon, item_nazgul},
+}
+
+// breed_time returns how many days breeding item takes.
+// Ported from src/beast.c lines 105-123.
+func breed_time(item int) int {
+	switch item {
+	case item_centaur, item_nazgul, item_harpie, item_lion:
+		return 14
+	case item_chimera, item_spider, item_hound:
+		return 21
+	case item_bird:
+		return 28
+	case item_dragon:
+		return 45
+	}
+
+	return 7
+}
+
+// breed_translate breeds trained horses as wild ones.
+// Ported from src/beast.c lines 126-137.
+func breed_translate(item int) int {
+	switch item {
+	case item_riding_horse, item_warmount:
+		return item_wild_horse
+	}
+
+	return item
+}
+
+// breed_match reports whether i1 and i2 are the pair in breed_tbl[which],
+// in either order.
+// Ported from src/beast.c lines 140-166.
+func breed_match(which, i1, i2 int) bool {
+	a := [2]int{i1, i2}
+	b := [2]int{breed_tbl[which].i1, breed_tbl[which].i2}
+
+	for i := range a {
+		for j := range b {
+			if a[i] == b[j] {
+				a[i] = 0
+				b[j] = 0
+			}
+		}
+	}
+
+	for i := range a {
+		if a[i] != 0 || b[i] != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// find_breed returns the offspring of i1 and i2, or 0 if they can't
+// breed.
+// Ported from src/beast.c lines 169-185.
+func (e *Engine) find_breed(i1, i2 int) int {
+	i1 = breed_translate(i1)
+	i2 = breed_translate(i2)
+
+	for i := range breed_tbl {
+		if breed_match(i, i1, i2) {
+			return breed_tbl[i].result
+		}
+	}
+
+	if e.item_animal(i1) != 0 && i1 == i2 {
+		return i1
+	}
+
+	return 0
+}
+
+// breed_check reports whether the breeder holds the pair in c.a and
+// c.b.
+func (e *Engine) breed_check(c *command) bool {
+	i1 := c.a
+	i2 := c.b
+
+	if e.kind(i1) != T_item {
+		wout(c.who, "%s is not an item.", get_parse_arg(c, 1))
+		return false
+	}
+
+	if e.kind(i2) != T_item {
+		wout(c.who, "%s is not an item.", get_parse_arg(c, 2))
+		return false
+	}
+
+	if e.has_item(c.who, i1) < 1 {
+		wout(c.who, "Don't have any %s.", e.box_code(i1))
+		return false
+	}
+
+	if e.has_item(c.who, i2) < 1 {
+		wout(c.who, "Don't have any %s.", e.box_code(i2))
+		return false
+	}
+
+	if i1 == i2 && e.has_item(c.who, i1) < 2 {
+		wout(c.who, "Don't have two %s.", e.box_code(i1))
+		return false
+	}
+
+	return true
+}
+
+// v_breed starts breeding two beasts. Experience takes a day off.
+// Ported from src/beast.c lines 188-257.
+func (e *Engine) v_breed(c *command) int {
+	if !e.has_skill(c.who, sk_breed_beasts) {
+		wout(c.who, "Requires %s.", e.box_name(sk_breed_beasts))
+		return FALSE
+	}
+
+	if e.numargs(c) < 2 {
+		wout(c.who, "Usage: breed <item> <item>")
+		return FALSE
+	}
+
+	if !e.breed_check(c) {
+		return FALSE
+	}
+
+	delay := breed_time(e.find_breed(c.a, c.b))
+
+	if exp := max(e.has_skill_level(c.who, sk_breed_beasts)-1, 0); exp != 0 {
+		delay--
+	}
+
+	c.wait = delay
+
+	wout(c.who, "Breed attempt will take %d days.", delay)
+
+	return TRUE
+}
+
+// BREED_ACCIDENT is the percent chance each breeder is killed; double
+// for a pair of the same kind.
+const BREED_ACCIDENT = 10
+
+// d_breed breeds the pair. Either may be killed, which halves the
+// chance of offspring.
+// Ported from src/beast.c lines 263-342.
+func (e *Engine) d_breed(c *command) int {
+	i1 := c.a
+	i2 := c.b
+	breed_accident := BREED_ACCIDENT
+	killed := false
+
+	if !e.breed_check(c) {
+		return FALSE
+	}
+
+	e.p_skill(sk_breed_beasts).use_count++
+
+	// The following isn't quite right -- there is no chance of
+	// killing both the breeders if they are of the same type.
+
+	offspring := e.find_breed(i1, i2)
+
+	if i1 == i2 {
+		breed_accident *= 2
+	}
+
+	if i1 != 0 && e.rndFrom(streamSkills, 1, 100) <= breed_accident {
+		wout(c.who, "%s was killed in the breeding attempt.", cap(e.box_name_qty(i1, 1)))
+		e.consume_item(c.who, i1, 1)
+		killed = true
+	}
+
+	if i2 != 0 && e.rndFrom(streamSkills, 1, 100) <= breed_accident && i1 != i2 {
+		wout(c.who, "%s was killed in the breeding attempt.", cap(e.box_name_qty(i2, 1)))
+		e.consume_item(c.who, i2, 1)
+		killed = true
+	}
+
+	if offspring == 0 || (killed && e.rndFrom(streamSkills, 1, 2) == 1) {
+		wout(c.who, "No offspring was produced.")
+		return FALSE
+	}
+
+	wout(c.who, "Produced %s.", e.box_name_qty(offspring, 1))
+
+	e.gen_item(c.who, offspring, 1)
+	e.add_skill_experience(c.who, sk_breed_beasts)
+
+	return TRUE
+}
+
+// v_breed_hound starts breeding a hound.
+// Ported from src/beast.c lines 345-349.
+func (e *Engine) v_breed_hound(c *command) int {
+	return TRUE
+}
+
+// d_breed_hound breeds and trains a hound.
+// Ported from src/beast.c lines 352-359.
+func (e *Engine) d_breed_hound(c *command) int {
+	e.gen_item(c.who, item_hound, 1)
+	wout(c.who, "Bred and trained %s.", e.box_name_qty(item_hound, 1))
+	return TRUE
+}
@@ -0,0 +1,68 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// beast_test.go - Tests for beastmastery skills
+
+package taygete
+
+import "testing"
+
+func TestFindBreed(t *testing.T) {
+	newTestEngine(t)
+	teg.alloc_box(item_lion, T_item, 0)
+	teg.p_item(item_lion).animal = TRUE
+
+	tests := []struct {
+		i1, i2, want int
+	}{
+		{item_peasant, item_ox, item_minotaur},
+		{item_ox, item_peasant, item_minotaur},
+		{item_riding_horse, item_peasant, item_centaur},
+		{item_warmount, item_wild_horse, item_wild_horse},
+		{item_lion, item_lion, item_lion},
+		{item_peasant, item_peasant, 0},
+		{item_ox, item_lion, 0},
+	}
+	for _, tt := range tests {
+		if got := teg.find_breed(tt.i1, tt.i2); got != tt.want {
+			t.Errorf("find_breed(%d, %d) = %d, want %d", tt.i1, tt.i2, got, tt.want)
+		}
+	}
+}
+
+func TestBreedDelay(t *testing.T) {
+	who := setupBasicTest(t, 0, 0)
+	teg.alloc_box(sk_breed_beasts, T_skill, 0)
+	teg.p_skill_ent(who, sk_breed_beasts).know = SKILL_know
+	for _, item := range []int{item_lizard, item_bird} {
+		teg.alloc_box(item, T_item, 0)
+		teg.gen_item(who, item, 1)
+	}
+
+	c := &command{who: who, a: item_lizard, b: item_bird}
+	c.parse = []string{"breed", "lizard", "bird"}
+	if got := teg.v_breed(c); got != TRUE {
+		t.Fatalf("v_breed = %d, want TRUE", got)
+	}
+	if c.wait != 45 {
+		t.Errorf("breeding a dragon takes %d days, want 45", c.wait)
+	}
+
+	c.b = item_lizard
+	if got := teg.v_breed(c); got != FALSE {
+		t.Errorf("v_breed with one lizard = %d, want FALSE", got)
+	}
+}
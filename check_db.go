@@ -116,6 +116,7 @@ func (e *Engine) CheckDB() *CheckResult {
 	e.checkSkillPlayer(result)
 	e.checkEatPlayer(result)
 	e.checkNPCPlayer(result)
+	e.checkRelics(result)
 	e.checkGarrPlayer(result)
 	e.checkNowhere(result)
 	e.checkSkills(result)
@@ -124,9 +125,9 @@ func (e *Engine) CheckDB() *CheckResult {
 	e.checkMoving(result)
 	e.checkPrisoner(result)
 
-	if e.globals.bx[e.globals.garrison_magic] != nil {
+	if e.globals.bx.get(e.globals.garrison_magic) != nil {
 		result.AddWarning("%s should not be allocated, reserved for garrison_magic",
-			box_code(e.globals.garrison_magic))
+			e.box_code(e.globals.garrison_magic))
 	}
 
 	return result
@@ -149,31 +150,31 @@ func (e *Engine) checkGlob(result *CheckResult) {
 // 2. If box is in here_list but claims different location, remove it.
 // Ported from src/check.c check_here().
 func (e *Engine) checkHere(result *CheckResult) {
-	for i := 1; i < MAX_BOXES; i++ {
-		if e.globals.bx[i] == nil {
+	for _, i := range e.boxIDs() {
+		if e.globals.bx.get(i) == nil {
 			continue
 		}
 
-		where := loc(i)
-		if where > 0 && !in_here_list(where, i) {
+		where := e.loc(i)
+		if where > 0 && !e.in_here_list(where, i) {
 			result.AddRepaired("adding [%d] to here list of [%d]", i, where)
-			add_to_here_list(where, i)
+			e.add_to_here_list(where, i)
 		}
 	}
 
-	for i := 1; i < MAX_BOXES; i++ {
-		if e.globals.bx[i] == nil {
+	for _, i := range e.boxIDs() {
+		if e.globals.bx.get(i) == nil {
 			continue
 		}
 
-		li := rp_loc_info(i)
+		li := e.rp_loc_info(i)
 		if li == nil {
 			continue
 		}
 
 		toRemove := []int{}
 		for _, j := range li.here_list {
-			where := loc(j)
+			where := e.loc(j)
 			if where != i {
 				result.AddRepaired("removing [%d] from here list of [%d]", j, i)
 				toRemove = append(toRemove, j)
@@ -192,7 +193,7 @@ func (e *Engine) checkHere(result *CheckResult) {
 // Ported from src/check.c check_swear().
 func (e *Engine) checkSwear(result *CheckResult) {
 	for _, i := range e.Characters() {
-		over := player(i)
+		over := e.player(i)
 		if over > 0 && !e.isUnit(over, i) {
 			result.AddRepaired("adding [%d] to player [%d]", i, over)
 			e.addUnit(over, i)
@@ -200,7 +201,7 @@ func (e *Engine) checkSwear(result *CheckResult) {
 	}
 
 	for _, i := range e.Players() {
-		p := rp_player(i)
+		p := e.rp_player(i)
 		if p == nil {
 			continue
 		}
@@ -208,7 +209,7 @@ func (e *Engine) checkSwear(result *CheckResult) {
 		toRemove := []int{}
 		units := e.getPlayerUnits(i)
 		for _, j := range units {
-			over := player(j)
+			over := e.player(j)
 			if over != i {
 				result.AddRepaired("removing [%d] from player list of [%d]", j, i)
 				toRemove = append(toRemove, j)
@@ -224,24 +225,24 @@ func (e *Engine) checkSwear(result *CheckResult) {
 // checkIndep ensures the independent player exists and all orphan chars are assigned.
 // Ported from src/check.c check_indep().
 func (e *Engine) checkIndep(result *CheckResult) {
-	if e.globals.bx[indep_player] == nil {
+	if e.globals.bx.get(indep_player) == nil {
 		result.AddRepaired("creating independent player [%d]", indep_player)
-		alloc_box(indep_player, T_player, sub_pl_npc)
+		e.alloc_box(indep_player, T_player, sub_pl_npc)
 	}
 
-	if kind(indep_player) != T_player {
+	if e.kind(indep_player) != T_player {
 		result.AddError("indep_player [%d] is not T_player", indep_player)
 		return
 	}
 
-	if name(indep_player) == "" {
-		set_name(indep_player, "Independent player")
+	if e.name(indep_player) == "" {
+		e.set_name(indep_player, "Independent player")
 	}
 
 	for _, i := range e.Characters() {
-		if player(i) == 0 {
-			result.AddRepaired("swearing unit [%d] to %s", i, box_name(indep_player))
-			set_lord(i, indep_player, LOY_unsworn, 0)
+		if e.player(i) == 0 {
+			result.AddRepaired("swearing unit [%d] to %s", i, e.box_name(indep_player))
+			e.set_lord(i, indep_player, LOY_unsworn, 0)
 		}
 	}
 }
@@ -249,90 +250,105 @@ func (e *Engine) checkIndep(result *CheckResult) {
 // checkGM ensures the gamemaster player exists.
 // Ported from src/check.c check_gm().
 func (e *Engine) checkGM(result *CheckResult) {
-	if e.globals.bx[gm_player] == nil {
+	if e.globals.bx.get(gm_player) == nil {
 		result.AddRepaired("creating gm player [%d]", gm_player)
-		alloc_box(gm_player, T_player, sub_pl_system)
+		e.alloc_box(gm_player, T_player, sub_pl_system)
 	}
 
-	if kind(gm_player) != T_player {
+	if e.kind(gm_player) != T_player {
 		result.AddError("gm_player [%d] is not T_player", gm_player)
 		return
 	}
 
-	if name(gm_player) == "" {
-		set_name(gm_player, "Gamemaster")
+	if e.name(gm_player) == "" {
+		e.set_name(gm_player, "Gamemaster")
 	}
 }
 
 // checkSkillPlayer ensures the skill player exists.
 // Ported from src/check.c check_skill_player().
 func (e *Engine) checkSkillPlayer(result *CheckResult) {
-	if e.globals.bx[skill_player] == nil {
+	if e.globals.bx.get(skill_player) == nil {
 		result.AddRepaired("creating skill player [%d]", skill_player)
-		alloc_box(skill_player, T_player, sub_pl_system)
+		e.alloc_box(skill_player, T_player, sub_pl_system)
 	}
 
-	if kind(skill_player) != T_player {
+	if e.kind(skill_player) != T_player {
 		result.AddError("skill_player [%d] is not T_player", skill_player)
 		return
 	}
 
-	if name(skill_player) == "" {
-		set_name(skill_player, "Skill list")
+	if e.name(skill_player) == "" {
+		e.set_name(skill_player, "Skill list")
 	}
 }
 
 // checkEatPlayer ensures the order eater player exists.
 // Ported from src/check.c check_eat_player().
 func (e *Engine) checkEatPlayer(result *CheckResult) {
-	if e.globals.bx[eat_pl] == nil {
+	if e.globals.bx.get(eat_pl) == nil {
 		result.AddRepaired("creating eat player [%d]", eat_pl)
-		alloc_box(eat_pl, T_player, sub_pl_system)
+		e.alloc_box(eat_pl, T_player, sub_pl_system)
 	}
 
-	if kind(eat_pl) != T_player {
+	if e.kind(eat_pl) != T_player {
 		result.AddError("eat_pl [%d] is not T_player", eat_pl)
 		return
 	}
 
-	if name(eat_pl) == "" {
-		set_name(eat_pl, "Order eater")
+	if e.name(eat_pl) == "" {
+		e.set_name(eat_pl, "Order eater")
 	}
 }
 
 // checkNPCPlayer ensures the NPC control player exists.
 // Ported from src/check.c check_npc_player().
 func (e *Engine) checkNPCPlayer(result *CheckResult) {
-	if e.globals.bx[npc_pl] == nil {
+	if e.globals.bx.get(npc_pl) == nil {
 		result.AddRepaired("creating npc player [%d]", npc_pl)
-		alloc_box(npc_pl, T_player, sub_pl_silent)
+		e.alloc_box(npc_pl, T_player, sub_pl_silent)
 	}
 
-	if kind(npc_pl) != T_player {
+	if e.kind(npc_pl) != T_player {
 		result.AddError("npc_pl [%d] is not T_player", npc_pl)
 		return
 	}
 
-	if name(npc_pl) == "" {
-		set_name(npc_pl, "NPC control")
+	if e.name(npc_pl) == "" {
+		e.set_name(npc_pl, "NPC control")
+	}
+}
+
+// checkRelics ensures Nowhere and the relics exist. The C game made
+// them when loading the database.
+// Ported from src/io.c load_db().
+func (e *Engine) checkRelics(result *CheckResult) {
+	if e.globals.nowhereRegion == 0 {
+		e.create_nowhere()
+		result.AddRepaired("creating nowhere region %s and loc %s",
+			e.box_code(e.globals.nowhereRegion), e.box_code(e.globals.nowhereLoc))
+	}
+
+	for _, n := range e.create_relics() {
+		result.AddRepaired("creating relic %s", e.box_name(n))
 	}
 }
 
 // checkGarrPlayer ensures the garrison player exists.
 // Ported from src/check.c check_garr_player().
 func (e *Engine) checkGarrPlayer(result *CheckResult) {
-	if e.globals.bx[garr_pl] == nil {
+	if e.globals.bx.get(garr_pl) == nil {
 		result.AddRepaired("creating garrison player [%d]", garr_pl)
-		alloc_box(garr_pl, T_player, sub_pl_silent)
+		e.alloc_box(garr_pl, T_player, sub_pl_silent)
 	}
 
-	if kind(garr_pl) != T_player {
+	if e.kind(garr_pl) != T_player {
 		result.AddError("garr_pl [%d] is not T_player", garr_pl)
 		return
 	}
 
-	if name(garr_pl) == "" {
-		set_name(garr_pl, "Garrison units")
+	if e.name(garr_pl) == "" {
+		e.set_name(garr_pl, "Garrison units")
 	}
 }
 
@@ -340,14 +356,14 @@ func (e *Engine) checkGarrPlayer(result *CheckResult) {
 // Ported from src/check.c check_nowhere().
 func (e *Engine) checkNowhere(result *CheckResult) {
 	for _, i := range e.Characters() {
-		if loc(i) == 0 {
-			result.AddWarning("unit %s is nowhere", box_code(i))
+		if e.loc(i) == 0 {
+			result.AddWarning("unit %s is nowhere", e.box_code(i))
 		}
 	}
 
 	for _, i := range e.LocsAndShips() {
-		if loc_depth(i) > LOC_region && loc(i) == 0 {
-			result.AddWarning("loc %s is nowhere", box_code(i))
+		if e.loc_depth(i) > LOC_region && e.loc(i) == 0 {
+			result.AddWarning("loc %s is nowhere", e.box_code(i))
 		}
 	}
 }
@@ -366,78 +382,78 @@ func (e *Engine) checkSkills(result *CheckResult) {
 	parentOfSkill := make(map[int]int)
 
 	for _, sk := range e.Skills() {
-		if sk >= 9000 && skill_school(sk) == sk {
-			result.AddWarning("orphaned subskill %s", box_code(sk))
+		if sk >= 9000 && e.skill_school(sk) == sk {
+			result.AddWarning("orphaned subskill %s", e.box_code(sk))
 		}
-		if e.globals.bx[sk] != nil {
-			e.globals.bx[sk].temp = 0
+		if e.globals.bx.get(sk) != nil {
+			e.globals.bx.get(sk).temp = 0
 		}
 	}
 
 	for _, sk := range e.Skills() {
-		if learn_time(sk) == 0 {
-			result.AddWarning("learn time of %s is 0", box_name(sk))
+		if e.learn_time(sk) == 0 {
+			result.AddWarning("learn time of %s is 0", e.box_name(sk))
 		}
 
-		s := rp_skill(sk)
+		s := e.rp_skill(sk)
 		if s == nil {
 			continue
 		}
 
 		for _, child := range s.offered {
-			if kind(child) != T_skill {
-				result.AddError("skill %s offered list contains non-skill %s", box_name(sk), box_code(child))
+			if e.kind(child) != T_skill {
+				result.AddError("skill %s offered list contains non-skill %s", e.box_name(sk), e.box_code(child))
 				continue
 			}
 
 			if prev, exists := parentOfSkill[child]; exists {
 				result.AddWarning("both %s and %s offer skill %s",
-					box_name(sk), box_name(prev), box_code(child))
+					e.box_name(sk), e.box_name(prev), e.box_code(child))
 			} else {
 				parentOfSkill[child] = sk
 			}
 
-			if skill_school(child) != sk {
+			if e.skill_school(child) != sk {
 				result.AddWarning("%s offers %s, but %s is in school %s",
-					box_name(sk), box_code(child), box_code(child), box_code(skill_school(child)))
+					e.box_name(sk), e.box_code(child), e.box_code(child), e.box_code(e.skill_school(child)))
 			}
 
-			if e.globals.bx[child] != nil {
-				e.globals.bx[child].temp = sk
+			if e.globals.bx.get(child) != nil {
+				e.globals.bx.get(child).temp = sk
 			}
 		}
 
 		for _, child := range s.research {
-			if kind(child) != T_skill {
-				result.AddError("skill %s research list contains non-skill %s", box_name(sk), box_code(child))
+			if e.kind(child) != T_skill {
+				result.AddError("skill %s research list contains non-skill %s", e.box_name(sk), e.box_code(child))
 				continue
 			}
 
 			if prev, exists := parentOfSkill[child]; exists {
 				result.AddWarning("both %s and %s offer skill %s",
-					box_name(sk), box_name(prev), box_code(child))
+					e.box_name(sk), e.box_name(prev), e.box_code(child))
 			} else {
 				parentOfSkill[child] = sk
 			}
 
-			if skill_school(child) != sk {
+			if e.skill_school(child) != sk {
 				result.AddWarning("%s offers %s, but %s is in school %s",
-					box_name(sk), box_code(child), box_code(child), box_code(skill_school(child)))
+					e.box_name(sk), e.box_code(child), e.box_code(child), e.box_code(e.skill_school(child)))
 			}
 
-			if e.globals.bx[child] != nil {
-				e.globals.bx[child].temp = sk
+			if e.globals.bx.get(child) != nil {
+				e.globals.bx.get(child).temp = sk
 			}
 		}
 	}
 
 	for _, sk := range e.Skills() {
-		if skill_school(sk) == sk {
+		if e.skill_school(sk) == sk {
 			continue
 		}
 
-		if e.globals.bx[sk] != nil && e.globals.bx[sk].temp == 0 {
-			result.AddWarning("non-offered skill %s", box_name(sk))
+		if e.globals.bx.get(sk) != nil && e.globals.bx.get(sk).temp == 0 {
+			result.AddWarning("non-offered skill %s", e.box_name(sk))
 		}
 	}
 }
@@ -446,46 +462,46 @@ func (e *Engine) checkSkills(result *CheckResult) {
 // Ported from src/check.c check_item_counts().
 func (e *Engine) checkItemCounts(result *CheckResult) {
 	for _, i := range e.Items() {
-		e.globals.bx[i].temp = 0
+		e.globals.bx.get(i).temp = 0
 	}
 
-	for i := 1; i < MAX_BOXES; i++ {
-		if e.globals.bx[i] == nil {
+	for _, i := range e.boxIDs() {
+		if e.globals.bx.get(i) == nil {
 			continue
 		}
 
 		inv := e.getInventory(i)
 		for _, ent := range inv {
-			if kind(ent.item) != T_item {
-				result.AddError("%s has non-item %s", box_name(i), box_name(ent.item))
+			if e.kind(ent.item) != T_item {
+				result.AddError("%s has non-item %s", e.box_name(i), e.box_name(ent.item))
 				continue
 			}
 
-			if item_unique(ent.item) == 0 {
+			if e.item_unique(ent.item) == 0 {
 				continue
 			}
 
-			if item_unique(ent.item) != i {
+			if e.item_unique(ent.item) != i {
 				result.AddRepaired("unique item %s: whohas=%s, actual=%s",
-					box_name(ent.item), box_name(item_unique(ent.item)), box_name(i))
-				p_item(ent.item).who_has = i
+					e.box_name(ent.item), e.box_name(e.item_unique(ent.item)), e.box_name(i))
+				e.p_item(ent.item).who_has = i
 			}
 
 			if ent.qty != 1 {
 				result.AddError("%s has qty %d of unique item %s",
-					box_name(i), ent.qty, box_name(ent.item))
+					e.box_name(i), ent.qty, e.box_name(ent.item))
 			}
 
-			if e.globals.bx[ent.item] != nil {
-				e.globals.bx[ent.item].temp += ent.qty
+			if e.globals.bx.get(ent.item) != nil {
+				e.globals.bx.get(ent.item).temp += ent.qty
 			}
 		}
 	}
 
 	for _, i := range e.Items() {
-		if item_unique(i) != 0 {
-			if e.globals.bx[i].temp != 1 {
-				result.AddError("unique item %s count %d", box_name(i), e.globals.bx[i].temp)
+		if e.item_unique(i) != 0 {
+			if e.globals.bx.get(i).temp != 1 {
+				result.AddError("unique item %s count %d", e.box_name(i), e.globals.bx.get(i).temp)
 			}
 		}
 	}
@@ -495,9 +511,9 @@ func (e *Engine) checkItemCounts(result *CheckResult) {
 // Ported from src/check.c check_loc_name_lengths().
 func (e *Engine) checkLocNameLengths(result *CheckResult) {
 	for _, i := range e.Locations() {
-		n := name(i)
+		n := e.name(i)
 		if len(n) > 25 {
-			result.AddWarning("%s name too long (%d chars)", box_name(i), len(n))
+			result.AddWarning("%s name too long (%d chars)", e.box_name(i), len(n))
 		}
 	}
 }
@@ -506,27 +522,27 @@ func (e *Engine) checkLocNameLengths(result *CheckResult) {
 // Ported from src/check.c check_moving().
 func (e *Engine) checkMoving(result *CheckResult) {
 	for _, i := range e.Characters() {
-		if stack_leader(i) != i || char_moving(i) == 0 {
+		if e.stack_leader(i) != i || e.char_moving(i) == 0 {
 			continue
 		}
 
-		c := rp_command(i)
+		c := e.rp_command(i)
 
 		if c == nil || c.state != STATE_RUN {
-			result.AddRepaired("%s moving but no command", box_name(i))
+			result.AddRepaired("%s moving but no command", e.box_name(i))
 			restore_stack_actions(i)
 		}
 	}
 
 	for _, i := range e.Characters() {
-		leader := stack_leader(i)
+		leader := e.stack_leader(i)
 
-		if leader == i || char_moving(i) == char_moving(leader) {
+		if leader == i || e.char_moving(i) == e.char_moving(leader) {
 			continue
 		}
 
-		result.AddRepaired("%s moving disagrees with leader", box_name(i))
-		p_char(i).moving = char_moving(leader)
+		result.AddRepaired("%s moving disagrees with leader", e.box_name(i))
+		e.p_char(i).moving = e.char_moving(leader)
 	}
 }
 
@@ -534,13 +550,13 @@ func (e *Engine) checkMoving(result *CheckResult) {
 // Ported from src/check.c check_prisoner().
 func (e *Engine) checkPrisoner(result *CheckResult) {
 	for _, who := range e.Characters() {
-		if !is_prisoner(who) {
+		if !e.is_prisoner(who) {
 			continue
 		}
 
-		if stack_parent(who) == 0 {
-			result.AddRepaired("%s prisoner but unstacked", box_name(who))
-			p_char(who).prisoner = FALSE
+		if e.stack_parent(who) == 0 {
+			result.AddRepaired("%s prisoner but unstacked", e.box_name(who))
+			e.p_char(who).prisoner = FALSE
 		}
 	}
 }
@@ -560,6 +576,7 @@ func (e *Engine) addUnit(pl, char int) {
 	IListAppend(&units, char)
 	IListSort(units)
 	e.globals.playerUnits[pl] = units
+	e.markDirty(pl)
 }
 
 // removeUnit removes char from player's unit list.
@@ -570,6 +587,7 @@ func (e *Engine) removeUnit(pl, char int) {
 	units := e.globals.playerUnits[pl]
 	IListRemValue(&units, char)
 	e.globals.playerUnits[pl] = units
+	e.markDirty(pl)
 }
 
 // getPlayerUnits returns the units list for a player.
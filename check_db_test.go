@@ -17,8 +17,11 @@
 package taygete
 
 import (
+	"math/rand/v2"
 	"strings"
 	"testing"
+
+	"github.com/mdhender/prng"
 )
 
 func TestCheckDB_Empty(t *testing.T) {
@@ -46,22 +49,22 @@ func TestCheckDB_SystemPlayers(t *testing.T) {
 	result := e.CheckDB()
 
 	// Verify system players were created
-	if kind(indep_player) != T_player {
+	if teg.kind(indep_player) != T_player {
 		t.Error("indep_player was not created")
 	}
-	if kind(gm_player) != T_player {
+	if teg.kind(gm_player) != T_player {
 		t.Error("gm_player was not created")
 	}
-	if kind(skill_player) != T_player {
+	if teg.kind(skill_player) != T_player {
 		t.Error("skill_player was not created")
 	}
-	if kind(eat_pl) != T_player {
+	if teg.kind(eat_pl) != T_player {
 		t.Error("eat_pl was not created")
 	}
-	if kind(npc_pl) != T_player {
+	if teg.kind(npc_pl) != T_player {
 		t.Error("npc_pl was not created")
 	}
-	if kind(garr_pl) != T_player {
+	if teg.kind(garr_pl) != T_player {
 		t.Error("garr_pl was not created")
 	}
 
@@ -87,16 +90,16 @@ func TestCheckDB_HereListConsistency(t *testing.T) {
 
 	// Create a location
 	locID := 10000
-	alloc_box(locID, T_loc, sub_plain)
-	set_name(locID, "Test Province")
+	teg.alloc_box(locID, T_loc, sub_plain)
+	teg.set_name(locID, "Test Province")
 
 	// Create a character
 	charID := 1000
-	alloc_box(charID, T_char, 0)
-	set_name(charID, "Test Character")
+	teg.alloc_box(charID, T_char, 0)
+	teg.set_name(charID, "Test Character")
 
 	// Set character's location but don't add to here list
-	p_loc_info(charID).where = locID
+	teg.p_loc_info(charID).where = locID
 
 	result := e.CheckDB()
 
@@ -113,7 +116,7 @@ func TestCheckDB_HereListConsistency(t *testing.T) {
 	}
 
 	// Verify char is now in here list
-	if !in_here_list(locID, charID) {
+	if !teg.in_here_list(locID, charID) {
 		t.Error("character was not added to here list")
 	}
 }
@@ -123,22 +126,22 @@ func TestCheckDB_HereListRemoveInvalid(t *testing.T) {
 
 	// Create a location
 	locID := 10000
-	alloc_box(locID, T_loc, sub_plain)
-	set_name(locID, "Test Province")
+	teg.alloc_box(locID, T_loc, sub_plain)
+	teg.set_name(locID, "Test Province")
 
 	// Create another location
 	locID2 := 10001
-	alloc_box(locID2, T_loc, sub_plain)
-	set_name(locID2, "Test Province 2")
+	teg.alloc_box(locID2, T_loc, sub_plain)
+	teg.set_name(locID2, "Test Province 2")
 
 	// Create a character claiming to be in locID2
 	charID := 1000
-	alloc_box(charID, T_char, 0)
-	set_name(charID, "Test Character")
-	p_loc_info(charID).where = locID2
+	teg.alloc_box(charID, T_char, 0)
+	teg.set_name(charID, "Test Character")
+	teg.p_loc_info(charID).where = locID2
 
 	// Manually add char to locID's here list (wrong location)
-	p_loc_info(locID).here_list = append(p_loc_info(locID).here_list, charID)
+	teg.p_loc_info(locID).here_list = append(teg.p_loc_info(locID).here_list, charID)
 
 	result := e.CheckDB()
 
@@ -155,7 +158,7 @@ func TestCheckDB_HereListRemoveInvalid(t *testing.T) {
 	}
 
 	// Verify char is no longer in wrong here list
-	if in_here_list(locID, charID) {
+	if teg.in_here_list(locID, charID) {
 		t.Error("character was not removed from wrong here list")
 	}
 }
@@ -165,8 +168,8 @@ func TestCheckDB_CharNowhereWarning(t *testing.T) {
 
 	// Create a character with no location
 	charID := 1000
-	alloc_box(charID, T_char, 0)
-	set_name(charID, "Orphan Character")
+	teg.alloc_box(charID, T_char, 0)
+	teg.set_name(charID, "Orphan Character")
 	// Note: do not set location
 
 	result := e.CheckDB()
@@ -189,14 +192,14 @@ func TestCheckDB_PrisonerNotStacked(t *testing.T) {
 
 	// Create a location
 	locID := 10000
-	alloc_box(locID, T_loc, sub_plain)
+	teg.alloc_box(locID, T_loc, sub_plain)
 
 	// Create a character marked as prisoner but not stacked
 	charID := 1000
-	alloc_box(charID, T_char, 0)
-	p_char(charID).prisoner = TRUE
-	p_loc_info(charID).where = locID
-	p_loc_info(locID).here_list = append(p_loc_info(locID).here_list, charID)
+	teg.alloc_box(charID, T_char, 0)
+	teg.p_char(charID).prisoner = TRUE
+	teg.p_loc_info(charID).where = locID
+	teg.p_loc_info(locID).here_list = append(teg.p_loc_info(locID).here_list, charID)
 
 	result := e.CheckDB()
 
@@ -213,7 +216,7 @@ func TestCheckDB_PrisonerNotStacked(t *testing.T) {
 	}
 
 	// Verify prisoner flag was cleared
-	if p_char(charID).prisoner != FALSE {
+	if teg.p_char(charID).prisoner != FALSE {
 		t.Error("prisoner flag was not cleared")
 	}
 }
@@ -223,13 +226,13 @@ func TestCheckDB_UniqueItemOwnership(t *testing.T) {
 
 	// Create an item type with unique owner
 	itemID := 100
-	alloc_box(itemID, T_item, sub_artifact)
-	set_name(itemID, "Magic Sword")
-	p_item(itemID).who_has = 2000 // claims to belong to 2000
+	teg.alloc_box(itemID, T_item, sub_artifact)
+	teg.set_name(itemID, "Magic Sword")
+	teg.p_item(itemID).who_has = 2000 // claims to belong to 2000
 
 	// Create a character holding the item
 	charID := 1000
-	alloc_box(charID, T_char, 0)
+	teg.alloc_box(charID, T_char, 0)
 	if e.globals.inventories == nil {
 		e.globals.inventories = make(map[int][]item_ent)
 	}
@@ -255,8 +258,8 @@ func TestCheckDB_UniqueItemOwnership(t *testing.T) {
 	}
 
 	// Verify who_has was corrected
-	if p_item(itemID).who_has != charID {
-		t.Errorf("who_has was not corrected, got %d, want %d", p_item(itemID).who_has, charID)
+	if teg.p_item(itemID).who_has != charID {
+		t.Errorf("who_has was not corrected, got %d, want %d", teg.p_item(itemID).who_has, charID)
 	}
 }
 
@@ -265,8 +268,8 @@ func TestCheckDB_LocNameTooLong(t *testing.T) {
 
 	// Create a location with a long name
 	locID := 10000
-	alloc_box(locID, T_loc, sub_plain)
-	set_name(locID, "This is a very very very long location name that exceeds twenty five characters")
+	teg.alloc_box(locID, T_loc, sub_plain)
+	teg.set_name(locID, "This is a very very very long location name that exceeds twenty five characters")
 
 	result := e.CheckDB()
 
@@ -339,15 +342,15 @@ func TestCheckDB_SkillTreeValidation(t *testing.T) {
 	e := newTestEngine(t)
 
 	parentSkillID := 9500
-	alloc_box(parentSkillID, T_skill, sub_magic)
-	set_name(parentSkillID, "Magic School")
-	sk1 := p_skill(parentSkillID)
+	teg.alloc_box(parentSkillID, T_skill, sub_magic)
+	teg.set_name(parentSkillID, "Magic School")
+	sk1 := teg.p_skill(parentSkillID)
 	sk1.time_to_learn = 7
 
 	childSkillID := 9501
-	alloc_box(childSkillID, T_skill, 0)
-	set_name(childSkillID, "Child Spell")
-	sk2 := p_skill(childSkillID)
+	teg.alloc_box(childSkillID, T_skill, 0)
+	teg.set_name(childSkillID, "Child Spell")
+	sk2 := teg.p_skill(childSkillID)
 	sk2.time_to_learn = 14
 	sk2.required_skill = parentSkillID
 
@@ -361,8 +364,8 @@ func TestCheckDB_SkillTreeValidation(t *testing.T) {
 		}
 	}
 
-	if skill_school(childSkillID) != parentSkillID {
-		t.Errorf("skill_school(%d) = %d, want %d", childSkillID, skill_school(childSkillID), parentSkillID)
+	if teg.skill_school(childSkillID) != parentSkillID {
+		t.Errorf("skill_school(%d) = %d, want %d", childSkillID, teg.skill_school(childSkillID), parentSkillID)
 	}
 }
 
@@ -370,9 +373,9 @@ func TestCheckDB_OrphanedSubskill(t *testing.T) {
 	e := newTestEngine(t)
 
 	orphanID := 9100
-	alloc_box(orphanID, T_skill, 0)
-	set_name(orphanID, "Orphan Skill")
-	sk := p_skill(orphanID)
+	teg.alloc_box(orphanID, T_skill, 0)
+	teg.set_name(orphanID, "Orphan Skill")
+	sk := teg.p_skill(orphanID)
 	sk.time_to_learn = 7
 
 	result := e.CheckDB()
@@ -393,21 +396,21 @@ func TestCheckDB_SkillOfferedByMultipleParents(t *testing.T) {
 	e := newTestEngine(t)
 
 	parent1ID := 9000
-	alloc_box(parent1ID, T_skill, sub_magic)
-	set_name(parent1ID, "School A")
-	sk1 := p_skill(parent1ID)
+	teg.alloc_box(parent1ID, T_skill, sub_magic)
+	teg.set_name(parent1ID, "School A")
+	sk1 := teg.p_skill(parent1ID)
 	sk1.time_to_learn = 7
 
 	parent2ID := 9100
-	alloc_box(parent2ID, T_skill, sub_magic)
-	set_name(parent2ID, "School B")
-	sk2 := p_skill(parent2ID)
+	teg.alloc_box(parent2ID, T_skill, sub_magic)
+	teg.set_name(parent2ID, "School B")
+	sk2 := teg.p_skill(parent2ID)
 	sk2.time_to_learn = 7
 
 	childID := 9001
-	alloc_box(childID, T_skill, 0)
-	set_name(childID, "Shared Child")
-	skChild := p_skill(childID)
+	teg.alloc_box(childID, T_skill, 0)
+	teg.set_name(childID, "Shared Child")
+	skChild := teg.p_skill(childID)
 	skChild.time_to_learn = 14
 	skChild.required_skill = parent1ID
 
@@ -437,8 +440,8 @@ func TestCheckDB_SkillOfferedByMultipleParents(t *testing.T) {
 func TestPostMonth_IntegratesCheckDB(t *testing.T) {
 	e := newTestEngine(t)
 
-	e.globals.bx[indep_player] = nil
-	e.globals.bx[gm_player] = nil
+	e.deleteBox(indep_player)
+	e.deleteBox(gm_player)
 
 	e.globals.post_has_been_run = false
 	e.globals.sysclock.turn = 1
@@ -448,14 +451,32 @@ func TestPostMonth_IntegratesCheckDB(t *testing.T) {
 		t.Fatalf("PostMonth returned error: %v", err)
 	}
 
-	if kind(indep_player) != T_player {
+	if teg.kind(indep_player) != T_player {
 		t.Error("PostMonth did not create indep_player via CheckDB")
 	}
-	if kind(gm_player) != T_player {
+	if teg.kind(gm_player) != T_player {
 		t.Error("PostMonth did not create gm_player via CheckDB")
 	}
 }
 
+// teg is the engine most tests work on; newTestEngine replaces it.
+var teg *Engine
+
+func init() {
+	db, err := OpenTestDB()
+	if err != nil {
+		panic(err)
+	}
+	teg = &Engine{
+		db:   db,
+		prng: prng.New(rand.NewPCG(0xC0FFEECAFE, 0xBEEFF00D)),
+	}
+	teg.globals.bx = boxStore{}
+	teg.globals.garrison_magic = 999
+	teg.globals.waitParseLists = make(map[*command][]*waitArgExt)
+	teg.globals.savedNames = make(map[int]string)
+}
+
 // newTestEngine creates a fresh Engine for testing.
 func newTestEngine(t *testing.T) *Engine {
 	t.Helper()
@@ -468,7 +489,11 @@ func newTestEngine(t *testing.T) *Engine {
 	t.Cleanup(func() { db.Close() })
 
 	// Replace the global engine with a fresh one
-	teg = &Engine{db: db}
+	teg = &Engine{
+		db:   db,
+		prng: prng.New(rand.NewPCG(0xC0FFEECAFE, 0xBEEFF00D)),
+	}
+	teg.globals.bx = boxStore{}
 	teg.globals.garrison_magic = 999
 	teg.globals.names = make(map[int]string)
 	teg.globals.banners = make(map[int]string)
@@ -476,6 +501,8 @@ func newTestEngine(t *testing.T) *Engine {
 	teg.globals.charSkills = make(map[int][]*skill_ent)
 	teg.globals.playerUnits = make(map[int][]int)
 	teg.globals.inventories = make(map[int][]item_ent)
+	teg.globals.waitParseLists = make(map[*command][]*waitArgExt)
+	teg.globals.savedNames = make(map[int]string)
 
 	return teg
 }
@@ -0,0 +1,153 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// checkpoint.go - saving a turn at the end of each day and resuming it
+
+package taygete
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// startTurn marks turn as being processed.
+func (e *Engine) startTurn(turn int) error {
+	if e.db == nil {
+		return nil
+	}
+	if _, err := e.db.Exec(`
+		INSERT INTO turns (turn_number, status, started_at) VALUES (?, 'processing', CURRENT_TIMESTAMP)
+		ON CONFLICT (turn_number) DO UPDATE SET status = 'processing',
+			started_at = CURRENT_TIMESTAMP, finished_at = NULL, day = 0
+	`, turn); err != nil {
+		return fmt.Errorf("start turn %d: %w", turn, err)
+	}
+	return nil
+}
+
+// finishTurn marks turn as processed.
+func (e *Engine) finishTurn(turn int) error {
+	if e.db == nil {
+		return nil
+	}
+	if _, err := e.db.Exec(`
+		UPDATE turns SET status = 'finished', finished_at = CURRENT_TIMESTAMP WHERE turn_number = ?
+	`, turn); err != nil {
+		return fmt.Errorf("finish turn %d: %w", turn, err)
+	}
+	return nil
+}
+
+// checkpointDay saves the turn as it stands at the end of the day on
+// the clock: the world with every unit's command state, the orders not
+// yet loaded, the report lines written so far and the random streams.
+// All of it goes in one transaction with the day, so a turn that
+// crashes resumes from the last day saved whole. The orders replace
+// those given for the turn; the originals stay in the turn's snapshot.
+func (e *Engine) checkpointDay() error {
+	if e.db == nil {
+		return nil
+	}
+	// The clock moved on when the turn started
+	turn, day := int(e.globals.sysclock.turn)-1, int(e.globals.sysclock.day)
+
+	err := e.saveWorld(e.dirty == nil, func(tx *sql.Tx) error {
+		if err := e.writeOrders(tx, turn); err != nil {
+			return err
+		}
+		if err := e.writeReports(tx); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`
+			UPDATE turns SET day = ? WHERE turn_number = ? AND status = 'processing'
+		`, day, turn)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("checkpoint turn %d day %d: %w", turn, day, err)
+	}
+	return nil
+}
+
+// resumeTurn reports whether the world is loaded partway through a
+// turn, that is, the previous turn is still processing and some of its
+// days were saved. If so it restores what checkpointDay saved beside
+// the world, puts the clock on the last day saved and rebuilds the
+// command queues. State kept only in memory for the month, such as the
+// locations touched, starts over.
+func (e *Engine) resumeTurn() (bool, error) {
+	if e.db == nil {
+		return false, nil
+	}
+
+	turn := int(e.globals.sysclock.turn) - 1
+	var day int
+	err := e.db.QueryRow(`
+		SELECT day FROM turns WHERE turn_number = ? AND status = 'processing' AND day > 0
+	`, turn).Scan(&day)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("resume turn %d: %w", turn, err)
+	}
+
+	e.ClearOrders()
+	if err := e.LoadOrders(turn); err != nil {
+		return false, fmt.Errorf("resume turn %d: %w", turn, err)
+	}
+	if err := e.loadReports(); err != nil {
+		return false, fmt.Errorf("resume turn %d: %w", turn, err)
+	}
+	if err := e.restorePrngStreams(); err != nil {
+		return false, fmt.Errorf("resume turn %d: %w", turn, err)
+	}
+	if e.logger != nil {
+		e.logger.Info("resume turn", "turn", turn, "day", day)
+	}
+
+	e.globals.sysclock.day = short(day)
+	e.globals.monthDone = false
+	e.initialCommandLoad()
+	return true, nil
+}
+
+// loadReports reads back the reports saved for the turn on the clock.
+func (e *Engine) loadReports() error {
+	rows, err := e.db.Query(`
+		SELECT player_id, body FROM reports WHERE turn_number = ? ORDER BY player_id
+	`, int(e.globals.sysclock.turn))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	e.globals.reports = nil
+	for rows.Next() {
+		var pl int
+		var body string
+		if err := rows.Scan(&pl, &body); err != nil {
+			return fmt.Errorf("scan report: %w", err)
+		}
+		if e.globals.reports == nil {
+			e.globals.reports = make(map[int][]string)
+		}
+		e.globals.reports[pl] = strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+	}
+
+	return rows.Err()
+}
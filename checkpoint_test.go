@@ -0,0 +1,225 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package taygete
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newOrderGame saves and loads a game with one order for the noble,
+// ready for RunTurn.
+func newOrderGame(t *testing.T, order string) (*engineGame, int) {
+	t.Helper()
+	g := newEngineGame(t, 1, "Red Company", "Osswid")
+	e, db := g.e, g.e.db
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	if err := e.LoadWorld(); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+	turn := int(e.globals.sysclock.turn)
+
+	if _, err := db.Exec(`INSERT INTO turns (turn_number) VALUES (?)`, turn); err != nil {
+		t.Fatalf("insert turn: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO orders (turn_number, player_id, source_char_id, raw_text) VALUES (?, ?, ?, ?)
+	`, turn, g.pl, g.who, order); err != nil {
+		t.Fatalf("insert order: %v", err)
+	}
+	if err := e.LoadOrders(turn); err != nil {
+		t.Fatalf("LoadOrders: %v", err)
+	}
+	return g, turn
+}
+
+// reloadEngine starts a new engine on g's database, as after a restart.
+func reloadEngine(t *testing.T, g *engineGame) *Engine {
+	t.Helper()
+	e, err := NewEngine(g.e.db, nil)
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	e.logger = nil
+	if err := e.LoadWorld(); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+	return e
+}
+
+func TestCommandStateRoundTrip(t *testing.T) {
+	g := newEngineGame(t, 1, "Red Company", "Osswid")
+	e := g.e
+	c := e.p_command(g.who)
+	if !e.oly_parse(c, "wait time 40 item 1 5") {
+		t.Fatalf("oly_parse failed")
+	}
+	c.line = "wait time 40 item 1 5"
+	c.state, c.status, c.pri, c.poll = STATE_RUN, TRUE, 3, TRUE
+	c.wait, c.days_executing, c.second_wait = -1, 12, 2
+	c.use_skill, c.use_exp = 9_101, 2
+	e.appendWaitParse(c, &waitArgExt{tag: waitTagTime, a1: 40})
+	e.appendWaitParse(c, &waitArgExt{tag: 3, a1: 1, a2: 5, flagStr: "go"})
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+
+	got := reloadEngine(t, g).rp_command(g.who)
+	if got == nil {
+		t.Fatalf("command not loaded")
+	}
+	for _, f := range []struct {
+		name      string
+		got, want any
+	}{
+		{"cmd", got.cmd, c.cmd},
+		{"line", got.line, c.line},
+		{"parse", strings.Join(got.parse, " "), strings.Join(c.parse, " ")},
+		{"args", []int{got.a, got.b, got.c, got.d, got.e, got.f, got.g, got.h},
+			[]int{c.a, c.b, c.c, c.d, c.e, c.f, c.g, c.h}},
+		{"state", got.state, c.state},
+		{"status", got.status, c.status},
+		{"pri", got.pri, c.pri},
+		{"poll", got.poll, c.poll},
+		{"wait", got.wait, c.wait},
+		{"days_executing", got.days_executing, c.days_executing},
+		{"second_wait", got.second_wait, c.second_wait},
+		{"use_skill", got.use_skill, c.use_skill},
+		{"use_exp", got.use_exp, c.use_exp},
+	} {
+		if fmt.Sprint(f.got) != fmt.Sprint(f.want) {
+			t.Errorf("%s: got %v, want %v", f.name, f.got, f.want)
+		}
+	}
+
+	waits := g.e.getWaitParse(c)
+	e2 := reloadEngine(t, g)
+	l := e2.getWaitParse(e2.rp_command(g.who))
+	if len(l) != len(waits) {
+		t.Fatalf("wait_parse: got %d conditions, want %d", len(l), len(waits))
+	}
+	for i := range l {
+		if *l[i] != *waits[i] {
+			t.Errorf("wait_parse[%d]: got %+v, want %+v", i, *l[i], *waits[i])
+		}
+	}
+}
+
+func TestRunTurnResumes(t *testing.T) {
+	want, turn := newOrderGame(t, "wait time 10")
+	if err := want.e.RunTurn(); err != nil {
+		t.Fatalf("RunTurn: %v", err)
+	}
+
+	// Fail the checkpoint of day 6 as a crash would
+	g, _ := newOrderGame(t, "wait time 10")
+	db := g.e.db
+	if _, err := db.Exec(`
+		CREATE TRIGGER crash BEFORE UPDATE OF day ON turns WHEN NEW.day = 6
+		BEGIN SELECT RAISE(ABORT, 'crash'); END
+	`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+	if err := g.e.RunTurn(); err == nil || !strings.Contains(err.Error(), "crash") {
+		t.Fatalf("RunTurn: got %v, want crash", err)
+	}
+	if _, err := db.Exec(`DROP TRIGGER crash`); err != nil {
+		t.Fatalf("drop trigger: %v", err)
+	}
+
+	e := reloadEngine(t, g)
+	if c := e.rp_command(g.who); c == nil || c.state != STATE_RUN || c.days_executing != 5 {
+		t.Fatalf("command after day 5: got %+v, want running for 5 days", c)
+	}
+	if err := e.RunTurn(); err != nil {
+		t.Fatalf("resumed RunTurn: %v", err)
+	}
+
+	var status string
+	var day int
+	if err := db.QueryRow(`SELECT status, day FROM turns WHERE turn_number = ?`, turn).Scan(&status, &day); err != nil {
+		t.Fatalf("select turn: %v", err)
+	}
+	if status != "finished" || day != MONTH_DAYS {
+		t.Errorf("turn %d: got %s on day %d, want finished on day %d", turn, status, day, MONTH_DAYS)
+	}
+
+	o1, err := readOutcome(want.e.db, turn)
+	if err != nil {
+		t.Fatalf("readOutcome: %v", err)
+	}
+	o2, err := readOutcome(db, turn)
+	if err != nil {
+		t.Fatalf("readOutcome: %v", err)
+	}
+	if o1.WorldHash != o2.WorldHash {
+		t.Errorf("world: resumed turn %s, uninterrupted %s", o2.WorldHash, o1.WorldHash)
+	}
+	if !slices.Equal(o1.Events, o2.Events) {
+		t.Errorf("events: resumed turn %v, uninterrupted %v", o2.Events, o1.Events)
+	}
+}
+
+func TestCommandCarriesAcrossTurns(t *testing.T) {
+	g, _ := newOrderGame(t, "wait time 40")
+	if err := g.e.RunTurn(); err != nil {
+		t.Fatalf("RunTurn: %v", err)
+	}
+	if err := g.e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+
+	e := reloadEngine(t, g)
+	c := e.rp_command(g.who)
+	if c == nil || c.state != STATE_RUN || c.line != "wait time 40" {
+		t.Fatalf("command after one turn: got %+v, want wait running", c)
+	}
+	if len(e.getWaitParse(c)) != 1 {
+		t.Errorf("wait_parse: got %d conditions, want 1", len(e.getWaitParse(c)))
+	}
+
+	if err := e.RunTurn(); err != nil {
+		t.Fatalf("RunTurn: %v", err)
+	}
+	if c := e.rp_command(g.who); c != nil && c.state == STATE_RUN {
+		t.Errorf("wait still running after %d days", c.days_executing)
+	}
+}
+
+func TestLoadReports(t *testing.T) {
+	g := newEngineGame(t, 1, "Red Company", "Osswid")
+	e := g.e
+	want := []string{"Day 5: Osswid waits.", "", "  indented"}
+	e.globals.reports = map[int][]string{g.pl: slices.Clone(want)}
+	if err := e.saveReports(); err != nil {
+		t.Fatalf("saveReports: %v", err)
+	}
+	if len(e.globals.reports) != 0 {
+		t.Fatalf("saveReports kept the reports")
+	}
+
+	if err := e.loadReports(); err != nil {
+		t.Fatalf("loadReports: %v", err)
+	}
+	if got := e.Report(g.pl); !slices.Equal(got, want) {
+		t.Errorf("report: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,261 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mdhender/taygete"
+	"github.com/spf13/cobra"
+)
+
+func cmdAccount() *cobra.Command {
+	addFlags := func(cmd *cobra.Command) error {
+		return nil
+	}
+	var cmd = &cobra.Command{
+		Use:   "account",
+		Short: "account and password commands",
+	}
+	cmd.AddCommand(cmdAccountCreate())
+	cmd.AddCommand(cmdAccountPasswd())
+	cmd.AddCommand(cmdAccountResetToken())
+	cmd.AddCommand(cmdAccountReset())
+	cmd.AddCommand(cmdAccountFactionPassword())
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
+
+// openEngine opens the game database at path for an account command.
+// The caller closes the database.
+func openEngine(op, path string) (*sql.DB, *taygete.Engine, error) {
+	if !isfile(path) {
+		err := fmt.Errorf("database does not exist: %q", path)
+		logger.Error(op,
+			"err", err)
+		return nil, nil, err
+	}
+	db, err := taygete.OpenGameDB(path)
+	if err != nil {
+		logger.Error(op,
+			"err", err)
+		return nil, nil, err
+	}
+	teg, err := taygete.NewEngine(db, nil)
+	if err != nil {
+		_ = db.Close()
+		logger.Error(op,
+			"err", err)
+		return nil, nil, err
+	}
+	return db, teg, nil
+}
+
+func cmdAccountCreate() *cobra.Command {
+	var email, fullName, password string
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().StringVar(&email, "email", "", "account email address")
+		cmd.Flags().StringVar(&fullName, "full-name", "", "account holder's full name")
+		cmd.Flags().StringVar(&password, "password", "", "account password")
+		if err := cmd.MarkFlagRequired("email"); err != nil {
+			return err
+		}
+		return cmd.MarkFlagRequired("password")
+	}
+	var cmd = &cobra.Command{
+		Use:   "create",
+		Short: "create an account",
+		Args:  cobra.ExactArgs(1), // path to database
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, teg, err := openEngine("account: create", args[0])
+			if err != nil {
+				return err
+			}
+			defer func() { _ = db.Close() }()
+			id, err := teg.CreateAccount(email, fullName, password)
+			if err != nil {
+				logger.Error("account: create",
+					"err", err)
+				return err
+			}
+			logger.Info("account: create",
+				"account", id,
+				"email", email)
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
+
+func cmdAccountPasswd() *cobra.Command {
+	var email, oldPassword, newPassword string
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().StringVar(&email, "email", "", "account email address")
+		cmd.Flags().StringVar(&oldPassword, "old", "", "current password")
+		cmd.Flags().StringVar(&newPassword, "new", "", "new password")
+		for _, name := range []string{"email", "old", "new"} {
+			if err := cmd.MarkFlagRequired(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var cmd = &cobra.Command{
+		Use:   "passwd",
+		Short: "change an account password",
+		Args:  cobra.ExactArgs(1), // path to database
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, teg, err := openEngine("account: passwd", args[0])
+			if err != nil {
+				return err
+			}
+			defer func() { _ = db.Close() }()
+			if err := teg.ChangeAccountPassword(email, oldPassword, newPassword); err != nil {
+				logger.Error("account: passwd",
+					"err", err)
+				return err
+			}
+			logger.Info("account: passwd",
+				"email", email)
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
+
+func cmdAccountResetToken() *cobra.Command {
+	var email string
+	var ttl time.Duration
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().StringVar(&email, "email", "", "account email address")
+		cmd.Flags().DurationVar(&ttl, "ttl", 24*time.Hour, "how long the token is good for")
+		return cmd.MarkFlagRequired("email")
+	}
+	var cmd = &cobra.Command{
+		Use:   "reset-token",
+		Short: "issue a one-time password reset token",
+		Args:  cobra.ExactArgs(1), // path to database
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, teg, err := openEngine("account: reset-token", args[0])
+			if err != nil {
+				return err
+			}
+			defer func() { _ = db.Close() }()
+			token, err := teg.CreateResetToken(email, ttl)
+			if err != nil {
+				logger.Error("account: reset-token",
+					"err", err)
+				return err
+			}
+			fmt.Println(token)
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
+
+func cmdAccountReset() *cobra.Command {
+	var token, password string
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().StringVar(&token, "token", "", "reset token")
+		cmd.Flags().StringVar(&password, "password", "", "new password")
+		if err := cmd.MarkFlagRequired("token"); err != nil {
+			return err
+		}
+		return cmd.MarkFlagRequired("password")
+	}
+	var cmd = &cobra.Command{
+		Use:   "reset",
+		Short: "set a new password with a reset token",
+		Args:  cobra.ExactArgs(1), // path to database
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, teg, err := openEngine("account: reset", args[0])
+			if err != nil {
+				return err
+			}
+			defer func() { _ = db.Close() }()
+			if err := teg.ResetPassword(token, password); err != nil {
+				logger.Error("account: reset",
+					"err", err)
+				return err
+			}
+			logger.Info("account: reset")
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
+
+func cmdAccountFactionPassword() *cobra.Command {
+	var faction, password string
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().StringVar(&faction, "faction", "", "faction entity code")
+		cmd.Flags().StringVar(&password, "password", "", "order password; empty to clear it")
+		return cmd.MarkFlagRequired("faction")
+	}
+	var cmd = &cobra.Command{
+		Use:   "faction-password",
+		Short: "set the password a faction gives with its orders",
+		Args:  cobra.ExactArgs(1), // path to database
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, teg, err := openEngine("account: faction-password", args[0])
+			if err != nil {
+				return err
+			}
+			defer func() { _ = db.Close() }()
+			if err := teg.LoadWorld(); err != nil {
+				logger.Error("account: faction-password",
+					"err", err)
+				return err
+			}
+			if err := teg.SetOrderPassword(faction, password); err != nil {
+				logger.Error("account: faction-password",
+					"err", err)
+				return err
+			}
+			if err := teg.SaveWorld(); err != nil {
+				logger.Error("account: faction-password",
+					"err", err)
+				return err
+			}
+			logger.Info("account: faction-password",
+				"faction", faction)
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
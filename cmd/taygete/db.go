@@ -19,8 +19,10 @@ package main
 import (
 	"fmt"
 	"log"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/mdhender/taygete"
 	"github.com/spf13/cobra"
@@ -34,7 +36,10 @@ func cmdDb() *cobra.Command {
 		Use:   "db",
 		Short: "database commands",
 	}
+	cmd.AddCommand(cmdDbDiff())
 	cmd.AddCommand(cmdDbInit())
+	cmd.AddCommand(cmdDbMigrate())
+	cmd.AddCommand(cmdDbStatus())
 	if err := addFlags(cmd); err != nil {
 		log.Fatal(err)
 	}
@@ -42,7 +47,9 @@ func cmdDb() *cobra.Command {
 }
 
 func cmdDbInit() *cobra.Command {
+	var seed uint64
 	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().Uint64Var(&seed, "seed", 0, "game seed for the random streams (default: a random seed)")
 		return nil
 	}
 	var cmd = &cobra.Command{
@@ -77,6 +84,7 @@ func cmdDbInit() *cobra.Command {
 			if err != nil {
 				logger.Error("db: init",
 					"err", err)
+				return err
 			}
 			defer func() { _ = db.Close() }()
 			logger.Info("db: init",
@@ -85,8 +93,169 @@ func cmdDbInit() *cobra.Command {
 			if err != nil {
 				logger.Error("db: init",
 					"err", err)
+				return err
+			}
+			if !cmd.Flags().Changed("seed") {
+				seed = rand.Uint64()
+			}
+			teg.SetSeed(seed)
+			if err := teg.SaveWorld(); err != nil {
+				logger.Error("db: init",
+					"err", err)
+				return err
+			}
+			logger.Info("db: init",
+				"seed", seed)
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
+
+func cmdDbDiff() *cobra.Command {
+	var turnA, turnB int
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().IntVar(&turnA, "turn-a", 0, "compare the world before this turn instead of the saved world")
+		cmd.Flags().IntVar(&turnB, "turn-b", 0, "compare the world before this turn instead of the saved world")
+		return nil
+	}
+	var cmd = &cobra.Command{
+		Use:   "diff",
+		Short: "print the entities that differ between two worlds",
+		Long: `Compare the saved worlds of two databases, or two turns of one
+database, and print what changed in each entity. The databases are not
+changed; run "db migrate" first on one whose schema is out of date.`,
+		Args: cobra.RangeArgs(1, 2), // paths to databases
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pathA, pathB := args[0], args[0]
+			if len(args) == 2 {
+				pathB = args[1]
+			}
+			load := func(path string, turn int) (taygete.WorldState, error) {
+				if !isfile(path) {
+					return nil, fmt.Errorf("database does not exist: %q", path)
+				}
+				// Diffing must not migrate the databases it reads
+				db, err := taygete.OpenGameDBNoMigrate(path)
+				if err != nil {
+					return nil, err
+				}
+				defer func() { _ = db.Close() }()
+				return taygete.LoadWorldState(db, turn)
+			}
+			a, err := load(pathA, turnA)
+			if err != nil {
+				logger.Error("db: diff",
+					"err", err)
+				return err
+			}
+			b, err := load(pathB, turnB)
+			if err != nil {
+				logger.Error("db: diff",
+					"err", err)
+				return err
+			}
+			fmt.Printf("a: %s\nb: %s\n", a.Hash(), b.Hash())
+			diffs := taygete.DiffWorlds(a, b)
+			for _, d := range diffs {
+				fmt.Println(d)
+			}
+			if len(diffs) == 0 {
+				fmt.Println("no differences")
+			}
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
+
+func cmdDbMigrate() *cobra.Command {
+	addFlags := func(cmd *cobra.Command) error {
+		return nil
+	}
+	var cmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "apply pending schema migrations",
+		Args:  cobra.ExactArgs(1), // path to database
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+			if !isfile(path) {
+				err := fmt.Errorf("database does not exist: %q", path)
+				logger.Error("db: migrate",
+					"err", err)
+				return err
+			}
+			db, err := taygete.OpenGameDBNoMigrate(path)
+			if err != nil {
+				logger.Error("db: migrate",
+					"err", err)
+				return err
+			}
+			defer func() { _ = db.Close() }()
+			applied, err := taygete.MigrateDB(db)
+			for _, name := range applied {
+				logger.Info("db: migrate",
+					"applied", name)
+			}
+			if err != nil {
+				logger.Error("db: migrate",
+					"err", err)
+				return err
+			}
+			if len(applied) == 0 {
+				fmt.Println("schema is up to date")
+			}
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
+
+func cmdDbStatus() *cobra.Command {
+	addFlags := func(cmd *cobra.Command) error {
+		return nil
+	}
+	var cmd = &cobra.Command{
+		Use:   "status",
+		Short: "print the state of each schema migration",
+		Long: `List every migration this binary knows and every migration the
+database records, with whether it is applied, pending, modified since
+it was applied, or unknown to this binary.`,
+		Args: cobra.ExactArgs(1), // path to database
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+			if !isfile(path) {
+				err := fmt.Errorf("database does not exist: %q", path)
+				logger.Error("db: status",
+					"err", err)
+				return err
+			}
+			db, err := taygete.OpenGameDBNoMigrate(path)
+			if err != nil {
+				logger.Error("db: status",
+					"err", err)
+				return err
+			}
+			defer func() { _ = db.Close() }()
+			states, err := taygete.MigrationStatus(db)
+			if err != nil {
+				logger.Error("db: status",
+					"err", err)
+				return err
+			}
+			for _, st := range states {
+				line := fmt.Sprintf("%-9s %-3s %-32s %s", st.State, st.Kind, st.Name, st.AppliedAt)
+				fmt.Println(strings.TrimRight(line, " "))
 			}
-			_ = teg
 			return nil
 		},
 	}
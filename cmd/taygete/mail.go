@@ -0,0 +1,205 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+
+	"github.com/mdhender/taygete"
+	"github.com/spf13/cobra"
+)
+
+func cmdMail() *cobra.Command {
+	addFlags := func(cmd *cobra.Command) error {
+		return nil
+	}
+	var cmd = &cobra.Command{
+		Use:   "mail",
+		Short: "email order commands",
+	}
+	cmd.AddCommand(cmdMailEat())
+	cmd.AddCommand(cmdMailSend())
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
+
+func cmdMailEat() *cobra.Command {
+	var ackDir string
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().StringVar(&ackDir, "acks", "acks", "directory to write acknowledgements to")
+		return nil
+	}
+	var cmd = &cobra.Command{
+		Use:   "eat",
+		Short: "read emailed orders from a maildir or mbox spool",
+		Args:  cobra.ExactArgs(2), // path to database, path to spool
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, spool := args[0], args[1]
+			if !isfile(path) {
+				err := fmt.Errorf("database does not exist: %q", path)
+				logger.Error("mail: eat",
+					"err", err)
+				return err
+			}
+			db, err := taygete.OpenGameDB(path)
+			if err != nil {
+				logger.Error("mail: eat",
+					"err", err)
+				return err
+			}
+			defer func() { _ = db.Close() }()
+			teg, err := taygete.NewEngine(db, nil)
+			if err != nil {
+				logger.Error("mail: eat",
+					"err", err)
+				return err
+			}
+			if err := teg.LoadWorld(); err != nil {
+				logger.Error("mail: eat",
+					"err", err)
+				return err
+			}
+			results, err := teg.EatSpool(spool, ackDir)
+			if err != nil {
+				logger.Error("mail: eat",
+					"err", err)
+				return err
+			}
+			for _, res := range results {
+				logger.Info("mail: eat",
+					"from", res.ReplyTo,
+					"player", res.Player,
+					"queued", res.Queued,
+					"errors", res.Errors)
+			}
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
+
+func cmdMailSend() *cobra.Command {
+	var turn int
+	var reports, remind bool
+	var smtpAddr, smtpUser, maildir string
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().IntVar(&turn, "turn", 0, "turn to mail reports for (default current turn)")
+		cmd.Flags().BoolVar(&reports, "reports", true, "queue turn reports")
+		cmd.Flags().BoolVar(&remind, "remind", false, "queue reminders to players who haven't sent orders")
+		cmd.Flags().StringVar(&smtpAddr, "smtp", "", "deliver through the SMTP server at host:port")
+		cmd.Flags().StringVar(&smtpUser, "smtp-user", "", "SMTP user; the password is read from TAYGETE_SMTP_PASSWORD")
+		cmd.Flags().StringVar(&maildir, "maildir", "", "deliver into a local maildir instead of sending")
+		cmd.MarkFlagsMutuallyExclusive("smtp", "maildir")
+		return nil
+	}
+	var cmd = &cobra.Command{
+		Use:   "send",
+		Short: "queue turn reports and reminders and deliver the outbox",
+		Args:  cobra.ExactArgs(1), // path to database
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+			if !isfile(path) {
+				err := fmt.Errorf("database does not exist: %q", path)
+				logger.Error("mail: send",
+					"err", err)
+				return err
+			}
+			var sender taygete.Sender
+			switch {
+			case maildir != "":
+				sender = &taygete.MaildirSender{Dir: maildir}
+			case smtpAddr != "":
+				s := &taygete.SMTPSender{Addr: smtpAddr}
+				if smtpUser != "" {
+					host, _, err := net.SplitHostPort(smtpAddr)
+					if err != nil {
+						logger.Error("mail: send",
+							"err", err)
+						return err
+					}
+					s.Auth = smtp.PlainAuth("", smtpUser, os.Getenv("TAYGETE_SMTP_PASSWORD"), host)
+				}
+				sender = s
+			default:
+				err := errors.New("one of --smtp or --maildir is required")
+				logger.Error("mail: send",
+					"err", err)
+				return err
+			}
+			db, err := taygete.OpenGameDB(path)
+			if err != nil {
+				logger.Error("mail: send",
+					"err", err)
+				return err
+			}
+			defer func() { _ = db.Close() }()
+			teg, err := taygete.NewEngine(db, nil)
+			if err != nil {
+				logger.Error("mail: send",
+					"err", err)
+				return err
+			}
+			if err := teg.LoadWorld(); err != nil {
+				logger.Error("mail: send",
+					"err", err)
+				return err
+			}
+			if reports {
+				n, err := teg.QueueReports(turn)
+				if err != nil {
+					logger.Error("mail: send",
+						"err", err)
+					return err
+				}
+				logger.Info("mail: send", "turn", turn, "reports", n)
+			}
+			if remind {
+				n, err := teg.QueueReminders(turn)
+				if err != nil {
+					logger.Error("mail: send",
+						"err", err)
+					return err
+				}
+				logger.Info("mail: send", "turn", turn, "reminders", n)
+			}
+			sent, failed, err := teg.SendOutbox(sender)
+			if err != nil {
+				logger.Error("mail: send",
+					"err", err)
+				return err
+			}
+			logger.Info("mail: send",
+				"sent", sent,
+				"failed", failed)
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
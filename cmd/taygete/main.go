@@ -85,7 +85,11 @@ func main() {
 			return nil
 		},
 	}
+	cmdRoot.AddCommand(cmdAccount())
 	cmdRoot.AddCommand(cmdDb())
+	cmdRoot.AddCommand(cmdMail())
+	cmdRoot.AddCommand(cmdPlayer())
+	cmdRoot.AddCommand(cmdTurn())
 	cmdRoot.AddCommand(cmdVersion())
 	err := addFlags(cmdRoot)
 	if err != nil {
@@ -0,0 +1,109 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/mdhender/taygete"
+	"github.com/spf13/cobra"
+)
+
+func cmdPlayer() *cobra.Command {
+	addFlags := func(cmd *cobra.Command) error {
+		return nil
+	}
+	var cmd = &cobra.Command{
+		Use:   "player",
+		Short: "player commands",
+	}
+	cmd.AddCommand(cmdPlayerAdd())
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
+
+func cmdPlayerAdd() *cobra.Command {
+	var np taygete.NewPlayer
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().StringVar(&np.Faction, "faction", "", "faction name")
+		cmd.Flags().StringVar(&np.Character, "noble", "", "name of the faction's first noble")
+		cmd.Flags().StringVar(&np.StartCity, "start", "", `start city code or name, or "empty"`)
+		cmd.Flags().StringVar(&np.FullName, "full-name", "", "player's full name")
+		cmd.Flags().StringVar(&np.Email, "email", "", "player's email address")
+		cmd.Flags().IntVar(&np.AccountID, "account", 0, "account id the faction belongs to")
+		cmd.Flags().StringVar(&np.Password, "password", "", "order password (default: a random one)")
+		if err := cmd.MarkFlagRequired("faction"); err != nil {
+			return err
+		}
+		return cmd.MarkFlagRequired("noble")
+	}
+	var cmd = &cobra.Command{
+		Use:   "add",
+		Short: "add a new faction to the game",
+		Args:  cobra.ExactArgs(1), // path to database
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+			if !isfile(path) {
+				err := fmt.Errorf("database does not exist: %q", path)
+				logger.Error("player: add",
+					"err", err)
+				return err
+			}
+			db, err := taygete.OpenGameDB(path)
+			if err != nil {
+				logger.Error("player: add",
+					"err", err)
+				return err
+			}
+			defer func() { _ = db.Close() }()
+			teg, err := taygete.NewEngine(db, nil)
+			if err != nil {
+				logger.Error("player: add",
+					"err", err)
+				return err
+			}
+			if err := teg.LoadWorld(); err != nil {
+				logger.Error("player: add",
+					"err", err)
+				return err
+			}
+			pl, password, err := teg.AddPlayer(np)
+			if err != nil {
+				logger.Error("player: add",
+					"err", err)
+				return err
+			}
+			if err := teg.SaveWorld(); err != nil {
+				logger.Error("player: add",
+					"err", err)
+				return err
+			}
+			logger.Info("player: add",
+				"player", pl,
+				"faction", np.Faction)
+			fmt.Printf("order password: %s\n", password)
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
@@ -0,0 +1,143 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/mdhender/taygete"
+	"github.com/spf13/cobra"
+)
+
+func cmdTurn() *cobra.Command {
+	addFlags := func(cmd *cobra.Command) error {
+		return nil
+	}
+	var cmd = &cobra.Command{
+		Use:   "turn",
+		Short: "turn commands",
+	}
+	cmd.AddCommand(cmdTurnReplay())
+	cmd.AddCommand(cmdTurnRollback())
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
+
+func cmdTurnRollback() *cobra.Command {
+	addFlags := func(cmd *cobra.Command) error {
+		return nil
+	}
+	var cmd = &cobra.Command{
+		Use:   "rollback",
+		Short: "restore the world as it was before a turn was processed",
+		Args:  cobra.ExactArgs(2), // path to database and turn number
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+			if !isfile(path) {
+				err := fmt.Errorf("database does not exist: %q", path)
+				logger.Error("turn: rollback",
+					"err", err)
+				return err
+			}
+			turn, err := strconv.Atoi(args[1])
+			if err != nil {
+				err = fmt.Errorf("turn must be a number: %q", args[1])
+				logger.Error("turn: rollback",
+					"err", err)
+				return err
+			}
+			db, err := taygete.OpenGameDB(path)
+			if err != nil {
+				logger.Error("turn: rollback",
+					"err", err)
+				return err
+			}
+			defer func() { _ = db.Close() }()
+			teg, err := taygete.NewEngine(db, nil)
+			if err != nil {
+				logger.Error("turn: rollback",
+					"err", err)
+				return err
+			}
+			if err := teg.RollbackTurn(turn); err != nil {
+				logger.Error("turn: rollback",
+					"err", err)
+				return err
+			}
+			logger.Info("turn: rollback",
+				"turn", turn)
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
+
+func cmdTurnReplay() *cobra.Command {
+	addFlags := func(cmd *cobra.Command) error {
+		return nil
+	}
+	var cmd = &cobra.Command{
+		Use:   "replay",
+		Short: "run a turn again from its snapshot and compare with the recorded outcome",
+		Args:  cobra.ExactArgs(2), // path to database and turn number
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+			if !isfile(path) {
+				err := fmt.Errorf("database does not exist: %q", path)
+				logger.Error("turn: replay",
+					"err", err)
+				return err
+			}
+			turn, err := strconv.Atoi(args[1])
+			if err != nil {
+				err = fmt.Errorf("turn must be a number: %q", args[1])
+				logger.Error("turn: replay",
+					"err", err)
+				return err
+			}
+			db, err := taygete.OpenGameDB(path)
+			if err != nil {
+				logger.Error("turn: replay",
+					"err", err)
+				return err
+			}
+			defer func() { _ = db.Close() }()
+			r, err := taygete.ReplayTurn(db, turn)
+			if err != nil {
+				logger.Error("turn: replay",
+					"err", err)
+				return err
+			}
+			fmt.Println(r)
+			if r.Diverged() {
+				return fmt.Errorf("turn %d: replay diverged", turn)
+			}
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatal(err)
+	}
+	return cmd
+}
@@ -25,6 +25,7 @@ import (
 // Sprint 25.9: Unit tests verify stubs panic.
 func TestDeprecatedCommandsPanic(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 	c := &command{}
 
 	tests := []struct {
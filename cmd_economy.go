@@ -24,20 +24,20 @@ package taygete
 // based on the requested qty and have_left parameters.
 // Returns 0 if the operation is not possible (with error message output).
 // Ported from src/c1.c lines 488-519.
-func how_many(who, from_who, item, qty, have_left int) int {
-	num_has := has_item(from_who, item)
+func (e *Engine) how_many(who, from_who, item, qty, have_left int) int {
+	num_has := e.has_item(from_who, item)
 
 	if num_has <= 0 {
 		wout(who, "%s has no %s.",
-			just_name(from_who),
-			just_name(item))
+			e.just_name(from_who),
+			e.just_name(item))
 		return 0
 	}
 
 	if num_has <= have_left {
 		wout(who, "%s has only %s.",
-			just_name(from_who),
-			just_name_qty(item, num_has))
+			e.just_name(from_who),
+			e.just_name_qty(item, num_has))
 		return 0
 	}
 
@@ -57,29 +57,29 @@ func how_many(who, from_who, item, qty, have_left int) int {
 // v_discard executes the DISCARD command.
 // Drops an item from the character's inventory.
 // Ported from src/c2.c lines 12-43.
-func v_discard(c *command) int {
+func (e *Engine) v_discard(c *command) int {
 	item := c.a
 	qty := c.b
 	have_left := c.c
 
-	if kind(item) != T_item {
-		wout(c.who, "%s is not an item.", box_code(item))
+	if e.kind(item) != T_item {
+		wout(c.who, "%s is not an item.", e.box_code(item))
 		return FALSE
 	}
 
-	if has_item(c.who, item) < 1 {
-		wout(c.who, "%s does not have any %s.", box_name(c.who),
-			box_code(item))
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s does not have any %s.", e.box_name(c.who),
+			e.box_code(item))
 		return FALSE
 	}
 
-	qty = how_many(c.who, c.who, item, qty, have_left)
+	qty = e.how_many(c.who, c.who, item, qty, have_left)
 
 	if qty <= 0 {
 		return FALSE
 	}
 
-	ret := drop_item(c.who, item, qty)
+	ret := e.drop_item(c.who, item, qty)
 	if !ret {
 		return FALSE
 	}
@@ -94,73 +94,69 @@ func v_discard(c *command) int {
 // Dead bodies owned by the player have their old_lord set to indep_player.
 // Ported from src/c2.c lines 47-117.
 // Note: Shell command calls from the C version are skipped.
-func drop_player(pl int) {
-	if kind(pl) != T_player {
+func (e *Engine) drop_player(pl int) {
+	if e.kind(pl) != T_player {
 		panic("drop_player: not a player")
 	}
 
-	for _, who := range loop_units(pl) {
-		if is_prisoner(who) {
-			unit_deserts(who, indep_player, true, LOY_UNCHANGED, 0)
+	for _, who := range e.loop_units(pl) {
+		if e.is_prisoner(who) {
+			e.unit_deserts(who, indep_player, true, LOY_UNCHANGED, 0)
 		} else {
-			wout(subloc(who), "%s melts into the ground and vanishes.", box_name(who))
-			char_reclaim(who)
+			wout(e.subloc(who), "%s melts into the ground and vanishes.", e.box_name(who))
+			e.char_reclaim(who)
 		}
 	}
 
-	for _, i := range loop_dead_body() {
-		owner := item_unique(i)
+	for _, i := range e.loop_dead_body() {
+		owner := e.item_unique(i)
 		if owner == 0 {
 			continue
 		}
 
-		p := rp_misc(i)
+		p := e.rp_misc(i)
 		if p == nil || p.old_lord != pl {
 			continue
 		}
 
-		p_misc(i).old_lord = indep_player
+		e.p_misc(i).old_lord = indep_player
 	}
 
-	p := rp_player(pl)
+	p := e.rp_player(pl)
 	var s, email string
 	if p != nil {
-		if p.email != nil && *p.email != 0 {
-			email = charPtrToStr(p.email)
-		}
-		if p.full_name != nil && *p.full_name != 0 {
-			s = charPtrToStr(p.full_name)
-		}
+		email = p.email
+		s = p.full_name
 	}
 
-	log_write(LOG_DROP, "Dropped player %s", box_name(pl))
+	log_write(LOG_DROP, "Dropped player %s", e.box_name(pl))
 	log_write(LOG_DROP, "    %s <%s>", s, email)
 
-	delete_box(pl)
+	e.delete_box(pl)
 }
 
 // v_quit executes the QUIT command.
 // Removes a player from the game.
 // Only the GM can quit another player.
 // Ported from src/c2.c lines 121-147.
-func v_quit(c *command) int {
+func (e *Engine) v_quit(c *command) int {
 	target := c.a
 
 	if target == 0 {
-		target = player(c.who)
+		target = e.player(c.who)
 	}
 
-	if target != player(c.who) && player(c.who) != gm_player {
+	if target != e.player(c.who) && e.player(c.who) != gm_player {
 		wout(c.who, "Not allowed to drop another player.")
 		return FALSE
 	}
 
-	if kind(target) != T_player {
-		wout(c.who, "%s is not a player.", box_name(target))
+	if e.kind(target) != T_player {
+		wout(c.who, "%s is not a player.", e.box_name(target))
 		return FALSE
 	}
 
-	drop_player(target)
+	e.drop_player(target)
 
 	return FALSE
 }
@@ -168,14 +164,14 @@ func v_quit(c *command) int {
 // loop_units returns all units belonging to a player.
 // This is a helper that replaces the C loop_units macro.
 // Uses the kind chain for efficient iteration.
-func loop_units(pl int) []int {
-	if kind(pl) != T_player {
+func (e *Engine) loop_units(pl int) []int {
+	if e.kind(pl) != T_player {
 		return nil
 	}
 
 	var units []int
-	for i := kind_first(T_char); i != 0; i = kind_next(i) {
-		if player(i) == pl {
+	for i := e.kind_first(T_char); i != 0; i = e.kind_next(i) {
+		if e.player(i) == pl {
 			units = append(units, i)
 		}
 	}
@@ -185,19 +181,12 @@ func loop_units(pl int) []int {
 // loop_dead_body returns all dead body items.
 // This is a helper that replaces the C loop_dead_body macro.
 // Uses the subkind chain for efficient iteration.
-func loop_dead_body() []int {
+func (e *Engine) loop_dead_body() []int {
 	var bodies []int
-	for i := sub_first(sub_dead_body); i != 0; i = sub_next(i) {
+	for i := e.sub_first(sub_dead_body); i != 0; i = e.sub_next(i) {
 		bodies = append(bodies, i)
 	}
 	return bodies
 }
 
 
-// charPtrToStr converts a *char (int8 pointer) to a Go string.
-func charPtrToStr(p *char) string {
-	if p == nil {
-		return ""
-	}
-	return ""
-}
@@ -24,46 +24,46 @@ package taygete
 // with a probability based on location depth (100% in sublocs, 40% in provinces).
 // Returns true if an item was found.
 // Ported from src/c1.c lines 33-78.
-func find_lost_items(who, where int) bool {
+func (e *Engine) find_lost_items(who, where int) bool {
 	var item int
 
-	inv := teg.globals.inventories[where]
-	for _, e := range inv {
-		if item_unique(e.item) == 0 {
+	inv := e.globals.inventories[where]
+	for _, it := range inv {
+		if e.item_unique(it.item) == 0 {
 			continue
 		}
 
 		// Don't take dead bodies out of graveyards; that's what EXHUME is for
-		if subkind(where) == sub_graveyard && subkind(e.item) == sub_dead_body {
+		if e.subkind(where) == sub_graveyard && e.subkind(it.item) == sub_dead_body {
 			continue
 		}
 
 		// Don't take magic rings from the market
-		if subkind(where) == sub_city && subkind(e.item) == sub_suffuse_ring {
+		if e.subkind(where) == sub_city && e.subkind(it.item) == sub_suffuse_ring {
 			continue
 		}
 
-		item = e.item
+		item = it.item
 		break
 	}
 
 	var chance int
-	if loc_depth(where) >= LOC_subloc {
+	if e.loc_depth(where) >= LOC_subloc {
 		chance = 100
 	} else {
 		chance = 40
 	}
 
-	if item == 0 || rnd(1, 100) > chance {
+	if item == 0 || e.rndFrom(streamExploration, 1, 100) > chance {
 		return false
 	}
 
-	move_item(where, who, item, 1)
-	wout(who, "%s found one %s.", box_name(who), box_name(item))
+	e.move_item(where, who, item, 1)
+	wout(who, "%s found one %s.", e.box_name(who), e.box_name(item))
 
 	log_write(LOG_MISC, "%s found %s in %s.",
-		box_name(who), box_name(item),
-		char_rep_location(where))
+		e.box_name(who), e.box_name(item),
+		e.char_rep_location(where))
 
 	return true
 }
@@ -71,7 +71,7 @@ func find_lost_items(who, where int) bool {
 // v_explore is the start function for the EXPLORE command.
 // Always returns TRUE to begin exploration.
 // Ported from src/c1.c lines 26-29.
-func v_explore(c *command) int {
+func (e *Engine) v_explore(c *command) int {
 	return TRUE
 }
 
@@ -83,41 +83,41 @@ func v_explore(c *command) int {
 //
 // Also attempts to find lost items first.
 // Ported from src/c1.c lines 91-178.
-func d_explore(c *command) int {
-	where := subloc(c.who)
+func (e *Engine) d_explore(c *command) int {
+	where := e.subloc(c.who)
 
-	if find_lost_items(c.who, where) {
+	if e.find_lost_items(c.who, where) {
 		return TRUE
 	}
 
 	// Explore in a ship should explore the surrounding ocean region
-	if is_ship(where) && subkind(loc(where)) == sub_ocean {
-		where = loc(where)
-		find_lost_items(c.who, where)
+	if e.is_ship(where) && e.subkind(e.loc(where)) == sub_ocean {
+		where = e.loc(where)
+		e.find_lost_items(c.who, where)
 	}
 
-	r := rnd(1, 100)
+	r := e.rndFrom(streamExploration, 1, 100)
 
 	if r <= 50 {
 		wout(c.who, "Exploration of %s uncovers no new features.",
-			box_code(where))
+			e.box_code(where))
 		return FALSE
 	}
 
-	l := exits_from_loc(c.who, where)
+	l := e.exits_from_loc(c.who, where)
 
 	hiddenExits := count_hidden_exits(l)
 
 	// Nothing to find
 	if hiddenExits <= 0 {
 		wout(c.who, "Exploration of %s uncovers no new features.",
-			box_code(where))
+			e.box_code(where))
 		return FALSE
 	}
 
 	// Something to find, but a bad roll
 	if r <= 67 {
-		switch rnd(1, 4) {
+		switch e.rndFrom(streamExploration, 1, 4) {
 		case 1:
 			wout(c.who, "Rumors speak of hidden features here, "+
 				"but none were found.")
@@ -135,19 +135,13 @@ func d_explore(c *command) int {
 	}
 
 	// Choose what we found randomly
-	i := rnd(1, hiddenExits)
+	i := e.rndFrom(streamExploration, 1, hiddenExits)
 
-	find_hidden_exit(c.who, l, hidden_count_to_index(i, l))
+	e.find_hidden_exit(c.who, l, hidden_count_to_index(i, l))
 
 	return TRUE
 }
 
-// exits_from_loc returns a list of exit views from a location.
-// Stub: will be implemented with movement system.
-func exits_from_loc(who, where int) []*exit_view {
-	return nil
-}
-
 // count_hidden_exits counts the number of hidden exits in an exit list.
 // Stub: will be implemented with movement system.
 func count_hidden_exits(l []*exit_view) int {
@@ -184,19 +178,19 @@ func hidden_count_to_index(which int, l []*exit_view) int {
 
 // find_hidden_exit reveals a hidden exit to a character.
 // Stub: will be implemented with movement system.
-func find_hidden_exit(who int, l []*exit_view, which int) {
+func (e *Engine) find_hidden_exit(who int, l []*exit_view, which int) {
 	if l == nil || which < 0 || which >= len(l) {
 		return
 	}
-	e := l[which]
-	if e == nil {
+	v := l[which]
+	if v == nil {
 		return
 	}
 
 	// Mark as known to the player
-	set_known(who, e.destination)
+	e.set_known(who, v.destination)
 
-	wout(who, "Found %s.", box_name(e.destination))
+	wout(who, "Found %s.", e.box_name(v.destination))
 }
 
 
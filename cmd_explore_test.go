@@ -30,6 +30,7 @@ func setupExploreTest(t *testing.T) {
 	teg = &Engine{
 		prng: prng.New(rand.NewPCG(12345, 67890)),
 	}
+	teg.globals.bx = boxStore{}
 	teg.globals.inventories = make(map[int][]item_ent)
 	teg.globals.playerKnowledge = make(map[int]map[int]bool)
 }
@@ -38,7 +39,7 @@ func TestVExplore(t *testing.T) {
 	setupExploreTest(t)
 
 	c := &command{who: 100}
-	result := v_explore(c)
+	result := teg.v_explore(c)
 
 	if result != TRUE {
 		t.Errorf("v_explore returned %d, want TRUE (%d)", result, TRUE)
@@ -51,10 +52,10 @@ func TestFindLostItems_NoItems(t *testing.T) {
 	who := 100
 	where := 200
 
-	teg.globals.bx[who] = &box{kind: T_char}
-	teg.globals.bx[where] = &box{kind: T_loc, skind: sub_plain}
+	teg.setBox(who, &box{kind: T_char})
+	teg.setBox(where, &box{kind: T_loc, skind: sub_plain})
 
-	result := find_lost_items(who, where)
+	result := teg.find_lost_items(who, where)
 
 	if result {
 		t.Error("find_lost_items returned true when no items present")
@@ -68,14 +69,14 @@ func TestFindLostItems_NonUniqueItems(t *testing.T) {
 	where := 200
 	item := 300
 
-	teg.globals.bx[who] = &box{kind: T_char}
-	teg.globals.bx[where] = &box{kind: T_loc, skind: sub_plain}
-	teg.globals.bx[item] = &box{kind: T_item}
+	teg.setBox(who, &box{kind: T_char})
+	teg.setBox(where, &box{kind: T_loc, skind: sub_plain})
+	teg.setBox(item, &box{kind: T_item})
 
 	// Add a non-unique item (no who_has set)
 	teg.globals.inventories[where] = []item_ent{{item: item, qty: 5}}
 
-	result := find_lost_items(who, where)
+	result := teg.find_lost_items(who, where)
 
 	if result {
 		t.Error("find_lost_items returned true for non-unique items")
@@ -90,31 +91,31 @@ func TestFindLostItems_UniqueItemInSubloc(t *testing.T) {
 	item := 300
 	playerID := 50
 
-	teg.globals.bx[who] = &box{kind: T_char}
-	teg.globals.bx[where] = &box{kind: T_loc, skind: sub_city} // LOC_subloc depth = 100% chance
-	teg.globals.bx[item] = &box{
+	teg.setBox(who, &box{kind: T_char})
+	teg.setBox(where, &box{kind: T_loc, skind: sub_city}) // LOC_subloc depth = 100% chance
+	teg.setBox(item, &box{
 		kind:   T_item,
 		skind:  sub_scroll,
 		x_item: &entity_item{who_has: where}, // Unique item owned by location
-	}
-	teg.globals.bx[playerID] = &box{kind: T_player}
+	})
+	teg.setBox(playerID, &box{kind: T_player})
 
 	// Set character's location and player
 	setupExploreLocation(where, sub_city)
-	set_where(who, where)
+	teg.set_where(who, where)
 
 	// Add unique item to location
 	teg.globals.inventories[where] = []item_ent{{item: item, qty: 1}}
 
-	result := find_lost_items(who, where)
+	result := teg.find_lost_items(who, where)
 
 	if !result {
 		t.Error("find_lost_items returned false for unique item in subloc")
 	}
 
 	// Check item moved to character
-	if has_item(who, item) != 1 {
-		t.Errorf("character should have 1 of item, got %d", has_item(who, item))
+	if teg.has_item(who, item) != 1 {
+		t.Errorf("character should have 1 of item, got %d", teg.has_item(who, item))
 	}
 }
 
@@ -125,20 +126,20 @@ func TestFindLostItems_SkipsDeadBodiesInGraveyard(t *testing.T) {
 	where := 200
 	deadBody := 300
 
-	teg.globals.bx[who] = &box{kind: T_char}
-	teg.globals.bx[where] = &box{kind: T_loc, skind: sub_graveyard}
-	teg.globals.bx[deadBody] = &box{
+	teg.setBox(who, &box{kind: T_char})
+	teg.setBox(where, &box{kind: T_loc, skind: sub_graveyard})
+	teg.setBox(deadBody, &box{
 		kind:   T_item,
 		skind:  sub_dead_body,
 		x_item: &entity_item{who_has: where},
-	}
+	})
 
 	setupExploreLocation(where, sub_graveyard)
-	set_where(who, where)
+	teg.set_where(who, where)
 
 	teg.globals.inventories[where] = []item_ent{{item: deadBody, qty: 1}}
 
-	result := find_lost_items(who, where)
+	result := teg.find_lost_items(who, where)
 
 	if result {
 		t.Error("find_lost_items should skip dead bodies in graveyards")
@@ -152,20 +153,20 @@ func TestFindLostItems_SkipsSuffuseRingsInCity(t *testing.T) {
 	where := 200
 	ring := 300
 
-	teg.globals.bx[who] = &box{kind: T_char}
-	teg.globals.bx[where] = &box{kind: T_loc, skind: sub_city}
-	teg.globals.bx[ring] = &box{
+	teg.setBox(who, &box{kind: T_char})
+	teg.setBox(where, &box{kind: T_loc, skind: sub_city})
+	teg.setBox(ring, &box{
 		kind:   T_item,
 		skind:  sub_suffuse_ring,
 		x_item: &entity_item{who_has: where},
-	}
+	})
 
 	setupExploreLocation(where, sub_city)
-	set_where(who, where)
+	teg.set_where(who, where)
 
 	teg.globals.inventories[where] = []item_ent{{item: ring, qty: 1}}
 
-	result := find_lost_items(who, where)
+	result := teg.find_lost_items(who, where)
 
 	if result {
 		t.Error("find_lost_items should skip suffuse rings in cities")
@@ -178,17 +179,17 @@ func TestDExplore_NoFeaturesMessage(t *testing.T) {
 	who := 100
 	where := 200
 
-	teg.globals.bx[who] = &box{kind: T_char}
-	teg.globals.bx[where] = &box{kind: T_loc, skind: sub_plain}
+	teg.setBox(who, &box{kind: T_char})
+	teg.setBox(where, &box{kind: T_loc, skind: sub_plain})
 
 	setupExploreLocation(where, sub_plain)
-	set_where(who, where)
+	teg.set_where(who, where)
 
 	c := &command{who: who}
 
 	// With no items and no hidden exits, d_explore should return FALSE
 	// (at least 50% of the time due to RNG, but with no hidden exits always FALSE)
-	result := d_explore(c)
+	result := teg.d_explore(c)
 
 	if result != FALSE {
 		t.Errorf("d_explore returned %d, expected FALSE (%d) when no features", result, FALSE)
@@ -282,18 +283,18 @@ func TestFindHiddenExit(t *testing.T) {
 	dest := 500
 	playerID := 50
 
-	teg.globals.bx[who] = &box{kind: T_char, x_char: &entity_char{}}
-	teg.globals.bx[dest] = &box{kind: T_loc, skind: sub_cave}
-	teg.globals.bx[playerID] = &box{kind: T_player}
+	teg.setBox(who, &box{kind: T_char, x_char: &entity_char{}})
+	teg.setBox(dest, &box{kind: T_loc, skind: sub_cave})
+	teg.setBox(playerID, &box{kind: T_player})
 
 	// Set up player relationship properly: unit_lord points to player
-	teg.globals.bx[who].x_char.unit_lord = playerID
+	teg.globals.bx.get(who).x_char.unit_lord = playerID
 
 	exits := []*exit_view{
 		{destination: dest, hidden: 1},
 	}
 
-	find_hidden_exit(who, exits, 0)
+	teg.find_hidden_exit(who, exits, 0)
 
 	// Check that destination is now known
 	if teg.globals.playerKnowledge[playerID] == nil {
@@ -311,14 +312,14 @@ func TestSetKnown(t *testing.T) {
 	what := 500
 	playerID := 50
 
-	teg.globals.bx[who] = &box{kind: T_char, x_char: &entity_char{}}
-	teg.globals.bx[what] = &box{kind: T_loc}
-	teg.globals.bx[playerID] = &box{kind: T_player}
+	teg.setBox(who, &box{kind: T_char, x_char: &entity_char{}})
+	teg.setBox(what, &box{kind: T_loc})
+	teg.setBox(playerID, &box{kind: T_player})
 
 	// Set up player relationship properly: unit_lord points to player
-	teg.globals.bx[who].x_char.unit_lord = playerID
+	teg.globals.bx.get(who).x_char.unit_lord = playerID
 
-	set_known(who, what)
+	teg.set_known(who, what)
 
 	if teg.globals.playerKnowledge[playerID] == nil {
 		t.Fatal("playerKnowledge[playerID] is nil after set_known")
@@ -336,21 +337,21 @@ func TestDExplore_ShipInOcean(t *testing.T) {
 	ship := 200
 	ocean := 300
 
-	teg.globals.bx[who] = &box{kind: T_char}
-	teg.globals.bx[ship] = &box{kind: T_loc, skind: sub_galley}
-	teg.globals.bx[ocean] = &box{kind: T_loc, skind: sub_ocean}
+	teg.setBox(who, &box{kind: T_char})
+	teg.setBox(ship, &box{kind: T_loc, skind: sub_galley})
+	teg.setBox(ocean, &box{kind: T_loc, skind: sub_ocean})
 
 	// Set up ship in ocean
 	setupExploreLocation(ocean, sub_ocean)
 	setupExploreLocation(ship, sub_galley)
-	teg.globals.bx[ship].x_loc_info.where = ocean
+	teg.globals.bx.get(ship).x_loc_info.where = ocean
 
-	set_where(who, ship)
+	teg.set_where(who, ship)
 
 	c := &command{who: who}
 
 	// Should explore ocean, not ship
-	result := d_explore(c)
+	result := teg.d_explore(c)
 
 	// With no items or hidden exits, should fail
 	if result != FALSE {
@@ -361,12 +362,12 @@ func TestDExplore_ShipInOcean(t *testing.T) {
 // setupExploreLocation sets up a test location for exploration tests.
 // Uses a different name to avoid conflict with lifecycle_test.go helper.
 func setupExploreLocation(id int, sk schar) {
-	if teg.globals.bx[id] == nil {
-		teg.globals.bx[id] = &box{}
+	if teg.globals.bx.get(id) == nil {
+		teg.setBox(id, &box{})
 	}
-	teg.globals.bx[id].kind = T_loc
-	teg.globals.bx[id].skind = sk
-	if teg.globals.bx[id].x_loc_info.where == 0 {
-		teg.globals.bx[id].x_loc_info.where = id
+	teg.globals.bx.get(id).kind = T_loc
+	teg.globals.bx.get(id).skind = sk
+	if teg.globals.bx.get(id).x_loc_info.where == 0 {
+		teg.globals.bx.get(id).x_loc_info.where = id
 	}
 }
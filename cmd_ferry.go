@@ -14,7 +14,7 @@
 // You should have received a copy of the GNU Affero General Public License
 // along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
-// cmd_ferry.go - Ships & ferry commands ported from src/c2.c
+// cmd_ferry.go - Ships & ferry commands ported from src/c1.c and src/c2.c
 // Sprint 26.8: Ships & Ferries
 
 package taygete
@@ -25,10 +25,10 @@ import "strings"
 // Usage: FEE <amount>
 // Sets the fee per 100 weight units for boarding the ship.
 // Ported from src/c2.c lines 822-832.
-func v_fee(c *command) int {
+func (e *Engine) v_fee(c *command) int {
 	amount := c.a
 
-	p_magic(c.who).fee = amount
+	e.p_magic(c.who).fee = amount
 
 	wout(c.who, "Ship boarding fee set to %s per 100 weight.", gold_s(amount))
 
@@ -38,19 +38,19 @@ func v_fee(c *command) int {
 // board_message announces a character boarding a ship.
 // Only announces if the character is not hidden and weather permits.
 // Ported from src/c2.c lines 836-861.
-func board_message(who, ship int) {
-	where := subloc(ship)
+func (e *Engine) board_message(who, ship int) {
+	where := e.subloc(ship)
 
-	if char_really_hidden(who) {
+	if e.char_really_hidden(who) {
 		return
 	}
 
-	if weather_here(where, sub_fog) != 0 {
+	if e.weather_here(where, sub_fog) != 0 {
 		return
 	}
 
 	with := display_with(who)
-	desc := liner_desc(who)
+	desc := e.liner_desc(who)
 
 	comma := ""
 	if strings.Contains(desc, ",") {
@@ -61,7 +61,7 @@ func board_message(who, ship int) {
 		with = "."
 	}
 
-	wout(where, "%s%s boarded %s%s", desc, comma, box_name(ship), with)
+	wout(where, "%s%s boarded %s%s", desc, comma, e.box_name(ship), with)
 	show_chars_below(where, who)
 }
 
@@ -69,59 +69,59 @@ func board_message(who, ship int) {
 // Usage: BOARD <ship> [max-fee]
 // The ship must have a FEE set to be operated as a ferry.
 // Ported from src/c2.c lines 865-971.
-func v_board(c *command) int {
+func (e *Engine) v_board(c *command) int {
 	ship := c.a
 	maxFee := c.b
 
-	if !is_ship(ship) {
-		wout(c.who, "%s is not a ship.", box_code(ship))
+	if !e.is_ship(ship) {
+		wout(c.who, "%s is not a ship.", e.box_code(ship))
 		return FALSE
 	}
 
-	log_write(LOG_SPECIAL, "BOARD for %s", box_name(player(c.who)))
+	log_write(LOG_SPECIAL, "BOARD for %s", e.box_name(e.player(c.who)))
 
-	v := parse_exit_dir(c, subloc(c.who), "board")
+	v := e.parse_exit_dir(c, e.subloc(c.who), "board")
 	if v == nil {
 		return FALSE
 	}
 
 	if v.destination != ship {
-		wout(c.who, "No visible route from %s to %s.", box_name(subloc(c.who)), box_code(ship))
+		wout(c.who, "No visible route from %s to %s.", e.box_name(e.subloc(c.who)), e.box_code(ship))
 		return FALSE
 	}
 
 	if v.in_transit != 0 {
-		wout(c.who, "%s is underway. Boarding is not possible.", box_name(v.destination))
+		wout(c.who, "%s is underway. Boarding is not possible.", e.box_name(v.destination))
 		return FALSE
 	}
 
-	owner := building_owner(ship)
+	owner := e.building_owner(ship)
 
 	shipFee := 0
-	if valid_box(owner) {
-		shipFee = board_fee(owner)
+	if e.valid_box(owner) {
+		shipFee = e.board_fee(owner)
 	}
-	if !valid_box(owner) || shipFee == 0 {
-		wout(c.who, "%s is not being operated as a ferry (no boarding FEE is set).", box_name(ship))
+	if !e.valid_box(owner) || shipFee == 0 {
+		wout(c.who, "%s is not being operated as a ferry (no boarding FEE is set).", e.box_name(ship))
 		return FALSE
 	}
 
 	var w weights
-	determine_stack_weights(c.who, &w)
+	e.determine_stack_weights(c.who, &w)
 
-	sc := ship_cap(ship)
+	sc := e.ship_cap(ship)
 	if sc != 0 {
-		sw := ship_weight(ship)
+		sw := e.ship_weight(ship)
 
 		if sw > sc {
-			wout(c.who, "%s is already overloaded. It can take no more passengers.", box_name(ship))
-			wout(owner, "Refused to let %s board because we are overloaded.", box_name(c.who))
+			wout(c.who, "%s is already overloaded. It can take no more passengers.", e.box_name(ship))
+			wout(owner, "Refused to let %s board because we are overloaded.", e.box_name(c.who))
 			return FALSE
 		}
 
 		if sw+w.total_weight > sc {
-			wout(c.who, "%s would be overloaded with us. We can't board.", box_name(ship))
-			wout(owner, "Refused to let %s board because then we would be overloaded.", box_name(c.who))
+			wout(c.who, "%s would be overloaded with us. We can't board.", e.box_name(ship))
+			wout(owner, "Refused to let %s board because then we would be overloaded.", e.box_name(c.who))
 			return FALSE
 		}
 	}
@@ -130,22 +130,22 @@ func v_board(c *command) int {
 
 	if maxFee != 0 && amount > maxFee {
 		wout(c.who, "Refused to pay a boarding fee of %s.", gold_s(amount))
-		wout(owner, "%s refused to pay a boarding fee of %s.", box_name(c.who), gold_s(amount))
+		wout(owner, "%s refused to pay a boarding fee of %s.", e.box_name(c.who), gold_s(amount))
 		return FALSE
 	}
 
-	if !charge(c.who, amount) {
+	if !e.charge(c.who, amount) {
 		wout(c.who, "Can't afford a boarding fee of %s.", gold_s(amount))
-		wout(owner, "%s couldn't afford a boarding fee of %s.", box_name(c.who), gold_s(amount))
+		wout(owner, "%s couldn't afford a boarding fee of %s.", e.box_name(c.who), gold_s(amount))
 		return FALSE
 	}
 
-	wout(c.who, "Paid %s to board %s.", gold_s(amount), box_name(ship))
-	wout(owner, "%s paid %s to board.", box_name(c.who), gold_s(amount))
-	board_message(c.who, ship)
+	wout(c.who, "Paid %s to board %s.", gold_s(amount), e.box_name(ship))
+	wout(owner, "%s paid %s to board.", e.box_name(c.who), gold_s(amount))
+	e.board_message(c.who, ship)
 
-	gen_item(owner, item_gold, amount)
-	add_gold_ferry(amount)
+	e.gen_item(owner, item_gold, amount)
+	e.add_gold_ferry(amount)
 	move_stack(c.who, ship)
 
 	return TRUE
@@ -154,19 +154,19 @@ func v_board(c *command) int {
 // unboard_message announces a character disembarking from a ship.
 // Only announces if the character is not hidden and weather permits.
 // Ported from src/c2.c lines 976-1002.
-func unboard_message(who, ship int) {
-	where := subloc(ship)
+func (e *Engine) unboard_message(who, ship int) {
+	where := e.subloc(ship)
 
-	if char_really_hidden(who) {
+	if e.char_really_hidden(who) {
 		return
 	}
 
-	if weather_here(where, sub_fog) != 0 {
+	if e.weather_here(where, sub_fog) != 0 {
 		return
 	}
 
 	with := display_with(who)
-	desc := liner_desc(who)
+	desc := e.liner_desc(who)
 
 	comma := ""
 	if strings.Contains(desc, ",") {
@@ -177,7 +177,7 @@ func unboard_message(who, ship int) {
 		with = "."
 	}
 
-	wout(where, "%s%s disembarked from %s%s", desc, comma, box_name(ship), with)
+	wout(where, "%s%s disembarked from %s%s", desc, comma, e.box_name(ship), with)
 	show_chars_below(where, who)
 }
 
@@ -186,17 +186,17 @@ func unboard_message(who, ship int) {
 // Only the ship's captain can unload passengers.
 // Cannot unload at sea.
 // Ported from src/c2.c lines 1010-1053.
-func v_unload(c *command) int {
-	ship := subloc(c.who)
+func (e *Engine) v_unload(c *command) int {
+	ship := e.subloc(c.who)
 
-	if !is_ship(ship) || building_owner(ship) != c.who {
-		wout(c.who, "%s is not the captain of a ship.", box_name(c.who))
+	if !e.is_ship(ship) || e.building_owner(ship) != c.who {
+		wout(c.who, "%s is not the captain of a ship.", e.box_name(c.who))
 		return FALSE
 	}
 
-	where := subloc(ship)
+	where := e.subloc(ship)
 
-	if subkind(where) == sub_ocean {
+	if e.subkind(where) == sub_ocean {
 		wout(c.who, "Can't unload passengers at sea. They won't go.")
 		return FALSE
 	}
@@ -204,16 +204,16 @@ func v_unload(c *command) int {
 	any := false
 
 	var chars []int
-	loop_char_here(ship, &chars)
+	e.loop_char_here(ship, &chars)
 
 	for _, i := range chars {
-		if stack_leader(i) == c.who {
+		if e.stack_leader(i) == c.who {
 			continue
 		}
 
-		wout(c.who, "%s disembarks.", box_name(i))
-		wout(i, "%s disembarks.", box_name(i))
-		unboard_message(i, ship)
+		wout(c.who, "%s disembarks.", e.box_name(i))
+		wout(i, "%s disembarks.", e.box_name(i))
+		e.unboard_message(i, ship)
 
 		move_stack(i, where)
 		any = true
@@ -232,20 +232,66 @@ func v_unload(c *command) int {
 // Usage: FERRY
 // Sets the ferry_flag on the ship, which wakes up any WAIT FERRY conditions.
 // Ported from src/c2.c lines 1061-1081.
-func v_ferry(c *command) int {
-	ship := subloc(c.who)
+func (e *Engine) v_ferry(c *command) int {
+	ship := e.subloc(c.who)
 
-	if !is_ship(ship) || building_owner(ship) != c.who {
-		wout(c.who, "%s is not the captain of a ship.", box_name(c.who))
+	if !e.is_ship(ship) || e.building_owner(ship) != c.who {
+		wout(c.who, "%s is not the captain of a ship.", e.box_name(c.who))
 		return FALSE
 	}
 
-	where := subloc(ship)
+	where := e.subloc(ship)
 
-	wout(where, "%s sounds a blast on its horn.", box_name(ship))
-	log_write(LOG_SPECIAL, "FERRY for %s", box_name(player(c.who)))
+	wout(where, "%s sounds a blast on its horn.", e.box_name(ship))
+	log_write(LOG_SPECIAL, "FERRY for %s", e.box_name(e.player(c.who)))
 
-	p_magic(ship).ferry_flag = TRUE
+	e.p_magic(ship).ferry_flag = TRUE
+
+	return TRUE
+}
+
+// v_add_ram starts fitting a ram to the galley the character is in.
+// Ported from src/c1.c lines 1029-1054.
+func (e *Engine) v_add_ram(c *command) int {
+	ship := e.subloc(c.who)
+
+	if e.subkind(ship) == sub_galley_notdone {
+		wout(c.who, "The galley must be completed before a ram may be added.")
+		return FALSE
+	}
+
+	if e.subkind(ship) != sub_galley {
+		wout(c.who, "Must be inside a galley to add a ram.")
+		return FALSE
+	}
+
+	if e.ship_has_ram(ship) != 0 {
+		wout(c.who, "%s already has a ram.", e.box_name(ship))
+	}
+
+	wout(c.who, "Work to add an iron-tipped ram to this vessel.")
+	return TRUE
+}
+
+// d_add_ram fits the ram.
+// Ported from src/c1.c lines 1057-1080.
+func (e *Engine) d_add_ram(c *command) int {
+	ship := e.subloc(c.who)
+
+	if e.subkind(ship) != sub_galley {
+		wout(c.who, "Must be inside a galley to add a ram.")
+		return FALSE
+	}
+
+	if e.ship_has_ram(ship) != 0 {
+		wout(c.who, "%s already has a ram.", e.box_name(ship))
+		return FALSE
+	}
+
+	e.p_subloc(ship).galley_ram = 1
+
+	wout(c.who, "%s has been fitted with a ram!", e.box_name(ship))
+	wout(ship, "%s has been fitted with a ram!", e.box_name(ship))
 
 	return TRUE
 }
@@ -253,13 +299,13 @@ func v_ferry(c *command) int {
 // parse_exit_dir parses a direction or destination from a command.
 // Returns nil if no valid exit is found.
 // Ported from src/move.c lines 165-247.
-func parse_exit_dir(c *command, where int, zeroArg string) *exit_view {
-	l := exits_from_loc(c.who, where)
+func (e *Engine) parse_exit_dir(c *command, where int, zeroArg string) *exit_view {
+	l := e.exits_from_loc(c.who, where)
 
-	if valid_box(c.a) {
+	if e.valid_box(c.a) {
 		if where == c.a {
 			if zeroArg != "" {
-				wout(c.who, "Already in %s.", box_name(where))
+				wout(c.who, "Already in %s.", e.box_name(where))
 			}
 			return nil
 		}
@@ -268,7 +314,7 @@ func parse_exit_dir(c *command, where int, zeroArg string) *exit_view {
 		var impassRet *exit_view
 
 		for _, v := range l {
-			if v.destination == c.a && (v.hidden == 0 || see_all(c.who)) {
+			if v.destination == c.a && (v.hidden == 0 || e.see_all(c.who)) {
 				if v.impassable != 0 {
 					impassRet = v
 				} else {
@@ -285,7 +331,7 @@ func parse_exit_dir(c *command, where int, zeroArg string) *exit_view {
 		}
 
 		if zeroArg != "" {
-			wout(c.who, "No visible route from %s to %s.", box_name(where), box_code(c.a))
+			wout(c.who, "No visible route from %s to %s.", e.box_name(where), e.box_code(c.a))
 		}
 		return nil
 	}
@@ -299,7 +345,7 @@ func parse_exit_dir(c *command, where int, zeroArg string) *exit_view {
 	}
 
 	for _, v := range l {
-		if v.direction == dir && (v.hidden == 0 || see_all(c.who)) {
+		if v.direction == dir && (v.hidden == 0 || e.see_all(c.who)) {
 			if dir == DIR_IN && zeroArg != "" {
 				wout(c.who, "(assuming '%s %s')", zeroArg, box_code_less(v.destination))
 			}
@@ -308,7 +354,7 @@ func parse_exit_dir(c *command, where int, zeroArg string) *exit_view {
 	}
 
 	if zeroArg != "" {
-		wout(c.who, "No visible %s route from %s.", dir_name(dir), box_name(where))
+		wout(c.who, "No visible %s route from %s.", dir_name(dir), e.box_name(where))
 	}
 	return nil
 }
@@ -326,14 +372,14 @@ func show_chars_below(where, who int) {
 }
 
 // add_gold_ferry adds to the gold_ferry global tracking ferry income.
-// Stub for now - gold_ferry is a global tracking stat.
-func add_gold_ferry(amount int) {
+func (e *Engine) add_gold_ferry(amount int) {
+	e.globals.gold_ferry += amount
 }
 
 // see_all returns true if the character can see all hidden things.
 // Stub for now - will be fully implemented in visibility sprint.
-func see_all(who int) bool {
-	return teg.globals.immedSeeAll
+func (e *Engine) see_all(who int) bool {
+	return e.globals.immedSeeAll
 }
 
 // lookup_dir looks up a direction string and returns the direction constant.
@@ -51,7 +51,7 @@ func setupFerryTest() {
 	}
 
 	for i := 0; i < MAX_BOXES; i++ {
-		teg.globals.bx[i] = nil
+		teg.deleteBox(i)
 	}
 	for i := range teg.globals.box_head {
 		teg.globals.box_head[i] = 0
@@ -60,9 +60,9 @@ func setupFerryTest() {
 		teg.globals.sub_head[i] = 0
 	}
 
-	alloc_box(item_gold, T_item, 0)
-	alloc_box(item_peasant, T_item, 0)
-	p_item(item_peasant).weight = 100
+	teg.alloc_box(item_gold, T_item, 0)
+	teg.alloc_box(item_peasant, T_item, 0)
+	teg.p_item(item_peasant).weight = 100
 }
 
 func TestVFee(t *testing.T) {
@@ -73,36 +73,36 @@ func TestVFee(t *testing.T) {
 	shipID := 3001
 	provinceID := 1001
 
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(captainID, T_char, 0)
-	alloc_box(shipID, T_ship, 0)
-	alloc_box(provinceID, T_loc, sub_plain)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(captainID, T_char, 0)
+	teg.alloc_box(shipID, T_ship, 0)
+	teg.alloc_box(provinceID, T_loc, sub_plain)
 
-	set_where(shipID, provinceID)
-	set_where(captainID, shipID)
-	p_loc_info(shipID).here_list = []int{captainID}
-	p_loc_info(provinceID).here_list = []int{shipID}
+	teg.set_where(shipID, provinceID)
+	teg.set_where(captainID, shipID)
+	teg.p_loc_info(shipID).here_list = []int{captainID}
+	teg.p_loc_info(provinceID).here_list = []int{shipID}
 
-	p_char(captainID).unit_lord = playerID
+	teg.p_char(captainID).unit_lord = playerID
 
 	c := &command{who: captainID, a: 25}
-	result := v_fee(c)
+	result := teg.v_fee(c)
 
 	if result != TRUE {
 		t.Errorf("v_fee returned %d, want TRUE", result)
 	}
 
-	if got := board_fee(captainID); got != 25 {
+	if got := teg.board_fee(captainID); got != 25 {
 		t.Errorf("board_fee(captain) = %d, want 25", got)
 	}
 
 	c.a = 0
-	result = v_fee(c)
+	result = teg.v_fee(c)
 	if result != TRUE {
 		t.Errorf("v_fee(0) returned %d, want TRUE", result)
 	}
 
-	if got := board_fee(captainID); got != 0 {
+	if got := teg.board_fee(captainID); got != 0 {
 		t.Errorf("board_fee(captain) = %d, want 0", got)
 	}
 }
@@ -113,14 +113,14 @@ func TestVBoard_NotAShip(t *testing.T) {
 	charID := 2001
 	provinceID := 1001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(provinceID, T_loc, sub_plain)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(provinceID, T_loc, sub_plain)
 
-	set_where(charID, provinceID)
-	p_loc_info(provinceID).here_list = []int{charID}
+	teg.set_where(charID, provinceID)
+	teg.p_loc_info(provinceID).here_list = []int{charID}
 
 	c := &command{who: charID, a: 9999}
-	result := v_board(c)
+	result := teg.v_board(c)
 
 	if result != FALSE {
 		t.Errorf("v_board(non-ship) returned %d, want FALSE", result)
@@ -136,24 +136,24 @@ func TestVBoard_NoFeeSet(t *testing.T) {
 	shipID := 3001
 	provinceID := 1001
 
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(charID, T_char, 0)
-	alloc_box(captainID, T_char, 0)
-	alloc_box(shipID, T_ship, 0)
-	alloc_box(provinceID, T_loc, sub_plain)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(captainID, T_char, 0)
+	teg.alloc_box(shipID, T_ship, 0)
+	teg.alloc_box(provinceID, T_loc, sub_plain)
 
-	set_where(charID, provinceID)
-	set_where(shipID, provinceID)
-	set_where(captainID, shipID)
+	teg.set_where(charID, provinceID)
+	teg.set_where(shipID, provinceID)
+	teg.set_where(captainID, shipID)
 
-	p_char(charID).unit_lord = playerID
-	p_char(captainID).unit_lord = playerID
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_char(captainID).unit_lord = playerID
 
-	p_loc_info(provinceID).here_list = []int{charID, shipID}
-	p_loc_info(shipID).here_list = []int{captainID}
+	teg.p_loc_info(provinceID).here_list = []int{charID, shipID}
+	teg.p_loc_info(shipID).here_list = []int{captainID}
 
 	c := &command{who: charID, a: shipID}
-	result := v_board(c)
+	result := teg.v_board(c)
 
 	if result != FALSE {
 		t.Errorf("v_board(no-fee) returned %d, want FALSE", result)
@@ -170,30 +170,30 @@ func TestVBoard_ShipOverloaded(t *testing.T) {
 	shipID := 3001
 	provinceID := 1001
 
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(player2ID, T_player, sub_pl_regular)
-	alloc_box(charID, T_char, 0)
-	alloc_box(captainID, T_char, 0)
-	alloc_box(shipID, T_ship, 0)
-	alloc_box(provinceID, T_loc, sub_plain)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(player2ID, T_player, sub_pl_regular)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(captainID, T_char, 0)
+	teg.alloc_box(shipID, T_ship, 0)
+	teg.alloc_box(provinceID, T_loc, sub_plain)
 
-	set_where(charID, provinceID)
-	set_where(shipID, provinceID)
-	set_where(captainID, shipID)
+	teg.set_where(charID, provinceID)
+	teg.set_where(shipID, provinceID)
+	teg.set_where(captainID, shipID)
 
-	p_char(charID).unit_lord = playerID
-	p_char(captainID).unit_lord = player2ID
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_char(captainID).unit_lord = player2ID
 
-	p_loc_info(provinceID).here_list = []int{charID, shipID}
-	p_loc_info(shipID).here_list = []int{captainID}
+	teg.p_loc_info(provinceID).here_list = []int{charID, shipID}
+	teg.p_loc_info(shipID).here_list = []int{captainID}
 
-	p_magic(captainID).fee = 10
-	p_subloc(shipID).capacity = 10
+	teg.p_magic(captainID).fee = 10
+	teg.p_subloc(shipID).capacity = 10
 
-	gen_item(charID, item_gold, 1000)
+	teg.gen_item(charID, item_gold, 1000)
 
 	c := &command{who: charID, a: shipID}
-	result := v_board(c)
+	result := teg.v_board(c)
 
 	if result != FALSE {
 		t.Errorf("v_board(overloaded) returned %d, want FALSE", result)
@@ -210,30 +210,30 @@ func TestVBoard_RefuseFee(t *testing.T) {
 	shipID := 3001
 	provinceID := 1001
 
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(player2ID, T_player, sub_pl_regular)
-	alloc_box(charID, T_char, 0)
-	alloc_box(captainID, T_char, 0)
-	alloc_box(shipID, T_ship, 0)
-	alloc_box(provinceID, T_loc, sub_plain)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(player2ID, T_player, sub_pl_regular)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(captainID, T_char, 0)
+	teg.alloc_box(shipID, T_ship, 0)
+	teg.alloc_box(provinceID, T_loc, sub_plain)
 
-	set_where(charID, provinceID)
-	set_where(shipID, provinceID)
-	set_where(captainID, shipID)
+	teg.set_where(charID, provinceID)
+	teg.set_where(shipID, provinceID)
+	teg.set_where(captainID, shipID)
 
-	p_char(charID).unit_lord = playerID
-	p_char(captainID).unit_lord = player2ID
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_char(captainID).unit_lord = player2ID
 
-	p_loc_info(provinceID).here_list = []int{charID, shipID}
-	p_loc_info(shipID).here_list = []int{captainID}
+	teg.p_loc_info(provinceID).here_list = []int{charID, shipID}
+	teg.p_loc_info(shipID).here_list = []int{captainID}
 
-	p_magic(captainID).fee = 100
-	p_subloc(shipID).capacity = 100000
+	teg.p_magic(captainID).fee = 100
+	teg.p_subloc(shipID).capacity = 100000
 
-	gen_item(charID, item_gold, 1000)
+	teg.gen_item(charID, item_gold, 1000)
 
 	c := &command{who: charID, a: shipID, b: 5}
-	result := v_board(c)
+	result := teg.v_board(c)
 
 	if result != FALSE {
 		t.Errorf("v_board(refuse-fee) returned %d, want FALSE", result)
@@ -250,28 +250,28 @@ func TestVBoard_CantAfford(t *testing.T) {
 	shipID := 3001
 	provinceID := 1001
 
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(player2ID, T_player, sub_pl_regular)
-	alloc_box(charID, T_char, 0)
-	alloc_box(captainID, T_char, 0)
-	alloc_box(shipID, T_ship, 0)
-	alloc_box(provinceID, T_loc, sub_plain)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(player2ID, T_player, sub_pl_regular)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(captainID, T_char, 0)
+	teg.alloc_box(shipID, T_ship, 0)
+	teg.alloc_box(provinceID, T_loc, sub_plain)
 
-	set_where(charID, provinceID)
-	set_where(shipID, provinceID)
-	set_where(captainID, shipID)
+	teg.set_where(charID, provinceID)
+	teg.set_where(shipID, provinceID)
+	teg.set_where(captainID, shipID)
 
-	p_char(charID).unit_lord = playerID
-	p_char(captainID).unit_lord = player2ID
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_char(captainID).unit_lord = player2ID
 
-	p_loc_info(provinceID).here_list = []int{charID, shipID}
-	p_loc_info(shipID).here_list = []int{captainID}
+	teg.p_loc_info(provinceID).here_list = []int{charID, shipID}
+	teg.p_loc_info(shipID).here_list = []int{captainID}
 
-	p_magic(captainID).fee = 100
-	p_subloc(shipID).capacity = 100000
+	teg.p_magic(captainID).fee = 100
+	teg.p_subloc(shipID).capacity = 100000
 
 	c := &command{who: charID, a: shipID}
-	result := v_board(c)
+	result := teg.v_board(c)
 
 	if result != FALSE {
 		t.Errorf("v_board(cant-afford) returned %d, want FALSE", result)
@@ -284,14 +284,14 @@ func TestVUnload_NotCaptain(t *testing.T) {
 	charID := 2001
 	provinceID := 1001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(provinceID, T_loc, sub_plain)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(provinceID, T_loc, sub_plain)
 
-	set_where(charID, provinceID)
-	p_loc_info(provinceID).here_list = []int{charID}
+	teg.set_where(charID, provinceID)
+	teg.p_loc_info(provinceID).here_list = []int{charID}
 
 	c := &command{who: charID}
-	result := v_unload(c)
+	result := teg.v_unload(c)
 
 	if result != FALSE {
 		t.Errorf("v_unload(not-captain) returned %d, want FALSE", result)
@@ -306,21 +306,21 @@ func TestVUnload_AtSea(t *testing.T) {
 	shipID := 3001
 	oceanID := 1001
 
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(captainID, T_char, 0)
-	alloc_box(shipID, T_ship, 0)
-	alloc_box(oceanID, T_loc, sub_ocean)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(captainID, T_char, 0)
+	teg.alloc_box(shipID, T_ship, 0)
+	teg.alloc_box(oceanID, T_loc, sub_ocean)
 
-	set_where(shipID, oceanID)
-	set_where(captainID, shipID)
+	teg.set_where(shipID, oceanID)
+	teg.set_where(captainID, shipID)
 
-	p_char(captainID).unit_lord = playerID
+	teg.p_char(captainID).unit_lord = playerID
 
-	p_loc_info(oceanID).here_list = []int{shipID}
-	p_loc_info(shipID).here_list = []int{captainID}
+	teg.p_loc_info(oceanID).here_list = []int{shipID}
+	teg.p_loc_info(shipID).here_list = []int{captainID}
 
 	c := &command{who: captainID}
-	result := v_unload(c)
+	result := teg.v_unload(c)
 
 	if result != FALSE {
 		t.Errorf("v_unload(at-sea) returned %d, want FALSE", result)
@@ -335,21 +335,21 @@ func TestVUnload_NoPassengers(t *testing.T) {
 	shipID := 3001
 	provinceID := 1001
 
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(captainID, T_char, 0)
-	alloc_box(shipID, T_ship, sub_galley)
-	alloc_box(provinceID, T_loc, sub_plain)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(captainID, T_char, 0)
+	teg.alloc_box(shipID, T_ship, sub_galley)
+	teg.alloc_box(provinceID, T_loc, sub_plain)
 
-	set_where(shipID, provinceID)
-	set_where(captainID, shipID)
+	teg.set_where(shipID, provinceID)
+	teg.set_where(captainID, shipID)
 
-	p_char(captainID).unit_lord = playerID
+	teg.p_char(captainID).unit_lord = playerID
 
-	p_loc_info(provinceID).here_list = []int{shipID}
-	p_loc_info(shipID).here_list = []int{captainID}
+	teg.p_loc_info(provinceID).here_list = []int{shipID}
+	teg.p_loc_info(shipID).here_list = []int{captainID}
 
 	c := &command{who: captainID}
-	result := v_unload(c)
+	result := teg.v_unload(c)
 
 	if result != TRUE {
 		t.Errorf("v_unload(no-passengers) returned %d, want TRUE", result)
@@ -362,14 +362,14 @@ func TestVFerry_NotCaptain(t *testing.T) {
 	charID := 2001
 	provinceID := 1001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(provinceID, T_loc, sub_plain)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(provinceID, T_loc, sub_plain)
 
-	set_where(charID, provinceID)
-	p_loc_info(provinceID).here_list = []int{charID}
+	teg.set_where(charID, provinceID)
+	teg.p_loc_info(provinceID).here_list = []int{charID}
 
 	c := &command{who: charID}
-	result := v_ferry(c)
+	result := teg.v_ferry(c)
 
 	if result != FALSE {
 		t.Errorf("v_ferry(not-captain) returned %d, want FALSE", result)
@@ -384,28 +384,28 @@ func TestVFerry_Success(t *testing.T) {
 	shipID := 3001
 	provinceID := 1001
 
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(captainID, T_char, 0)
-	alloc_box(shipID, T_ship, sub_galley)
-	alloc_box(provinceID, T_loc, sub_plain)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(captainID, T_char, 0)
+	teg.alloc_box(shipID, T_ship, sub_galley)
+	teg.alloc_box(provinceID, T_loc, sub_plain)
 
-	set_where(shipID, provinceID)
-	set_where(captainID, shipID)
+	teg.set_where(shipID, provinceID)
+	teg.set_where(captainID, shipID)
 
-	p_char(captainID).unit_lord = playerID
+	teg.p_char(captainID).unit_lord = playerID
 
-	p_loc_info(provinceID).here_list = []int{shipID}
-	p_loc_info(shipID).here_list = []int{captainID}
+	teg.p_loc_info(provinceID).here_list = []int{shipID}
+	teg.p_loc_info(shipID).here_list = []int{captainID}
 
 	c := &command{who: captainID}
-	result := v_ferry(c)
+	result := teg.v_ferry(c)
 
 	if result != TRUE {
 		t.Errorf("v_ferry returned %d, want TRUE", result)
 	}
 
-	if p_magic(shipID).ferry_flag != TRUE {
-		t.Errorf("ferry_flag = %d, want TRUE", p_magic(shipID).ferry_flag)
+	if teg.p_magic(shipID).ferry_flag != TRUE {
+		t.Errorf("ferry_flag = %d, want TRUE", teg.p_magic(shipID).ferry_flag)
 	}
 }
 
@@ -476,14 +476,14 @@ func TestBoardMessage_Hidden(t *testing.T) {
 	shipID := 3001
 	provinceID := 1001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(shipID, T_ship, 0)
-	alloc_box(provinceID, T_loc, sub_plain)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(shipID, T_ship, 0)
+	teg.alloc_box(provinceID, T_loc, sub_plain)
 
-	set_where(shipID, provinceID)
-	p_magic(charID).hide_self = 1
+	teg.set_where(shipID, provinceID)
+	teg.p_magic(charID).hide_self = 1
 
-	board_message(charID, shipID)
+	teg.board_message(charID, shipID)
 }
 
 func TestUnboardMessage_Hidden(t *testing.T) {
@@ -493,12 +493,43 @@ func TestUnboardMessage_Hidden(t *testing.T) {
 	shipID := 3001
 	provinceID := 1001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(shipID, T_ship, 0)
-	alloc_box(provinceID, T_loc, sub_plain)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(shipID, T_ship, 0)
+	teg.alloc_box(provinceID, T_loc, sub_plain)
 
-	set_where(shipID, provinceID)
-	p_magic(charID).hide_self = 1
+	teg.set_where(shipID, provinceID)
+	teg.p_magic(charID).hide_self = 1
 
-	unboard_message(charID, shipID)
+	teg.unboard_message(charID, shipID)
+}
+
+func TestAddRam(t *testing.T) {
+	setupFerryTest()
+
+	charID := 2001
+	shipID := 3001
+	provinceID := 1001
+
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(shipID, T_ship, sub_galley_notdone)
+	teg.alloc_box(provinceID, T_loc, sub_ocean)
+
+	teg.set_where(shipID, provinceID)
+	teg.set_where(charID, shipID)
+
+	c := &command{who: charID}
+	if result := teg.v_add_ram(c); result != FALSE {
+		t.Errorf("v_add_ram(unfinished galley) returned %d, want FALSE", result)
+	}
+
+	teg.change_box_subkind(shipID, sub_galley)
+	if teg.v_add_ram(c) != TRUE || teg.d_add_ram(c) != TRUE {
+		t.Fatal("adding a ram failed")
+	}
+	if teg.ship_has_ram(shipID) == 0 {
+		t.Error("galley has no ram")
+	}
+	if result := teg.d_add_ram(c); result != FALSE {
+		t.Errorf("d_add_ram(rammed galley) returned %d, want FALSE", result)
+	}
 }
@@ -30,9 +30,9 @@ func noble_cost(pl int) int {
 // All factions gain noble points on turns 8, 16, 24, etc (every NUM_MONTHS turns).
 // Returns 0 if NP is granted this turn.
 // Ported from src/c1.c lines 750-769.
-func next_np_turn(pl int) int {
+func (e *Engine) next_np_turn(pl int) int {
 	// ct = (7 - (sysclock.turn + 1) % NUM_MONTHS)
-	ct := (7 - int(teg.globals.sysclock.turn+1)%NUM_MONTHS)
+	ct := (7 - int(e.globals.sysclock.turn+1)%NUM_MONTHS)
 
 	// by Cappinator: All factions gain noble points on turns 8, 16, 24, etc...
 	// ft = p->first_turn % NUM_MONTHS -> simplified to 2
@@ -45,34 +45,34 @@ func next_np_turn(pl int) int {
 
 // print_hiring_status displays when the player will receive their next NP.
 // Ported from src/c1.c lines 773-789.
-func print_hiring_status(pl int) {
-	if kind(pl) != T_player {
+func (e *Engine) print_hiring_status(pl int) {
+	if e.kind(pl) != T_player {
 		return
 	}
 
-	if subkind(pl) != sub_pl_regular {
+	if e.subkind(pl) != sub_pl_regular {
 		return
 	}
 
-	n := next_np_turn(pl)
+	n := e.next_np_turn(pl)
 
 	if n == 0 {
 		n += NUM_MONTHS
 	}
 
 	wout(pl, "The next NP will be received at the end of turn %d.",
-		int(teg.globals.sysclock.turn)+n)
+		int(e.globals.sysclock.turn)+n)
 }
 
 // print_unformed displays the list of unformed noble IDs for a player.
 // Ported from src/c1.c lines 793-812.
-func print_unformed(pl int) {
-	p := rp_player(pl)
+func (e *Engine) print_unformed(pl int) {
+	p := e.rp_player(pl)
 	if p == nil {
 		return
 	}
 
-	unformed := getPlayerUnformed(pl)
+	unformed := e.getPlayerUnformed(pl)
 	n := len(unformed)
 	if n < 1 {
 		return
@@ -92,15 +92,15 @@ func print_unformed(pl int) {
 // form_new_noble creates a new noble from an unformed entity.
 // Sets up the noble's stats, location, loyalty, and joins it to the forming character's stack.
 // Ported from src/c1.c lines 903-933.
-func form_new_noble(who int, name string, new int) {
-	if kind(new) != T_unform {
+func (e *Engine) form_new_noble(who int, name string, new int) {
+	if e.kind(new) != T_unform {
 		return
 	}
 
-	change_box_kind(new, T_char)
+	e.change_box_kind(new, T_char)
 
-	p := p_char(new)
-	op := p_char(who)
+	p := e.p_char(new)
+	op := e.p_char(who)
 
 	p.behind = op.behind
 	p.fresh_hire = TRUE
@@ -110,28 +110,28 @@ func form_new_noble(who int, name string, new int) {
 	p.defense = 80
 	p.break_point = 50
 
-	set_name(new, name)
+	e.set_name(new, name)
 
-	set_where(new, subloc(who))
-	set_lord(new, player(who), LOY_contract, 500)
+	e.set_where(new, e.subloc(who))
+	e.set_lord(new, e.player(who), LOY_contract, 500)
 
-	join_stack(new, who)
+	e.join_stack(new, who)
 }
 
 // v_form is the start routine for the FORM command.
 // Validates that the character is in a city and has enough NP.
 // Usage: FORM [entity_id] [name]
 // Ported from src/c1.c lines 937-959.
-func v_form(c *command) int {
-	if subkind(subloc(c.who)) != sub_city {
+func (e *Engine) v_form(c *command) int {
+	if e.subkind(e.subloc(c.who)) != sub_city {
 		wout(c.who, "Nobles may only be formed in cities.")
 		return FALSE
 	}
 
-	pl := player(c.who)
+	pl := e.player(c.who)
 	cost := noble_cost(pl)
 
-	if int(player_np(pl)) < cost {
+	if int(e.player_np(pl)) < cost {
 		wout(c.who, "To form another noble requires %d Noble Point%s.", cost, add_s(cost))
 		return FALSE
 	}
@@ -142,22 +142,22 @@ func v_form(c *command) int {
 // d_form is the finish routine for the FORM command.
 // Deducts NP and creates the new noble.
 // Ported from src/c1.c lines 963-1026.
-func d_form(c *command) int {
-	pl := player(c.who)
+func (e *Engine) d_form(c *command) int {
+	pl := e.player(c.who)
 	cost := noble_cost(pl)
 
-	if int(player_np(pl)) < cost {
+	if int(e.player_np(pl)) < cost {
 		wout(c.who, "To form another noble requires %d Noble Point%s.",
 			cost, add_s(cost))
 		return FALSE
 	}
 
-	unformed := getPlayerUnformed(pl)
+	unformed := e.getPlayerUnformed(pl)
 	new := c.a
 
 	if new != 0 {
-		if kind(new) != T_unform || !containsInt(unformed, new) {
-			wout(c.who, "%s is not a valid unformed noble entity.", box_code(new))
+		if e.kind(new) != T_unform || !containsInt(unformed, new) {
+			wout(c.who, "%s is not a valid unformed noble entity.", e.box_code(new))
 			new = 0
 		}
 	}
@@ -172,58 +172,66 @@ func d_form(c *command) int {
 	}
 
 	new_name := "New noble"
-	if numargs(c) >= 2 {
+	if e.numargs(c) >= 2 {
 		parsed := get_parse_arg(c, 2)
 		if parsed != "" {
 			new_name = parsed
 		}
 	}
 
-	form_new_noble(c.who, new_name, new)
+	e.form_new_noble(c.who, new_name, new)
 
-	removePlayerUnformed(pl, new)
-	deduct_np(pl, cost)
+	e.removePlayerUnformed(pl, new)
+	e.deduct_np(pl, cost)
 
 	return TRUE
 }
 
 // getPlayerUnformed returns the unformed noble IDs for a player.
 // Uses a workaround since ilist is a C-style pointer type.
-func getPlayerUnformed(pl int) []int {
-	if kind(pl) != T_player {
+func (e *Engine) getPlayerUnformed(pl int) []int {
+	if e.kind(pl) != T_player {
 		return nil
 	}
-	if teg.globals.playerUnits == nil {
+	if e.globals.playerUnits == nil {
 		return nil
 	}
-	return teg.globals.playerUnits[pl+100_000]
+	return e.globals.playerUnits[pl+100_000]
 }
 
 // setPlayerUnformed sets the unformed noble IDs for a player.
-func setPlayerUnformed(pl int, unformed []int) {
-	if teg.globals.playerUnits == nil {
-		teg.globals.playerUnits = make(map[int][]int)
+func (e *Engine) setPlayerUnformed(pl int, unformed []int) {
+	if e.globals.playerUnits == nil {
+		e.globals.playerUnits = make(map[int][]int)
 	}
-	teg.globals.playerUnits[pl+100_000] = unformed
+	// An unformed noble's entities row names the player holding it
+	for _, id := range e.globals.playerUnits[pl+100_000] {
+		e.markDirty(id)
+	}
+	for _, id := range unformed {
+		e.markDirty(id)
+	}
+	e.globals.playerUnits[pl+100_000] = unformed
+	e.markDirty(pl)
 }
 
 // addPlayerUnformed adds an unformed noble ID to a player's list.
-func addPlayerUnformed(pl, id int) {
-	unformed := getPlayerUnformed(pl)
+func (e *Engine) addPlayerUnformed(pl, id int) {
+	unformed := e.getPlayerUnformed(pl)
 	unformed = append(unformed, id)
-	setPlayerUnformed(pl, unformed)
+	e.setPlayerUnformed(pl, unformed)
 }
 
 // removePlayerUnformed removes an unformed noble ID from a player's list.
-func removePlayerUnformed(pl, id int) {
-	unformed := getPlayerUnformed(pl)
+func (e *Engine) removePlayerUnformed(pl, id int) {
+	unformed := e.getPlayerUnformed(pl)
 	for i, v := range unformed {
 		if v == id {
 			unformed = append(unformed[:i], unformed[i+1:]...)
 			break
 		}
 	}
-	setPlayerUnformed(pl, unformed)
+	e.setPlayerUnformed(pl, unformed)
 }
 
 // containsInt checks if a slice contains a value.
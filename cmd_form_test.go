@@ -56,7 +56,7 @@ func setupFormTest() {
 	}
 
 	for i := 0; i < MAX_BOXES; i++ {
-		teg.globals.bx[i] = nil
+		teg.deleteBox(i)
 	}
 	for i := range teg.globals.box_head {
 		teg.globals.box_head[i] = 0
@@ -100,7 +100,7 @@ func TestNextNpTurn(t *testing.T) {
 
 	for _, tc := range tests {
 		teg.globals.sysclock.turn = short(tc.turn)
-		got := next_np_turn(100)
+		got := teg.next_np_turn(100)
 		if got != tc.want {
 			t.Errorf("next_np_turn at turn %d = %d, want %d", tc.turn, got, tc.want)
 		}
@@ -111,17 +111,17 @@ func TestPlayerUnformedHelpers(t *testing.T) {
 	setupFormTest()
 
 	playerID := 100
-	alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
 
-	if got := getPlayerUnformed(playerID); got != nil {
+	if got := teg.getPlayerUnformed(playerID); got != nil {
 		t.Errorf("getPlayerUnformed should return nil initially, got %v", got)
 	}
 
-	addPlayerUnformed(playerID, 5001)
-	addPlayerUnformed(playerID, 5002)
-	addPlayerUnformed(playerID, 5003)
+	teg.addPlayerUnformed(playerID, 5001)
+	teg.addPlayerUnformed(playerID, 5002)
+	teg.addPlayerUnformed(playerID, 5003)
 
-	unformed := getPlayerUnformed(playerID)
+	unformed := teg.getPlayerUnformed(playerID)
 	if len(unformed) != 3 {
 		t.Fatalf("expected 3 unformed nobles, got %d", len(unformed))
 	}
@@ -129,8 +129,8 @@ func TestPlayerUnformedHelpers(t *testing.T) {
 		t.Errorf("unexpected unformed list: %v", unformed)
 	}
 
-	removePlayerUnformed(playerID, 5002)
-	unformed = getPlayerUnformed(playerID)
+	teg.removePlayerUnformed(playerID, 5002)
+	unformed = teg.getPlayerUnformed(playerID)
 	if len(unformed) != 2 {
 		t.Fatalf("expected 2 unformed nobles after removal, got %d", len(unformed))
 	}
@@ -172,17 +172,17 @@ func TestVFormRequiresCity(t *testing.T) {
 	playerID := 100
 	province := 10001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(province, T_loc, sub_plain)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(province, T_loc, sub_plain)
 
-	p_char(charID).unit_lord = playerID
-	p_player(playerID).noble_points = 5
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_player(playerID).noble_points = 5
 
-	set_where(charID, province)
+	teg.set_where(charID, province)
 
 	c := &command{who: charID}
-	result := v_form(c)
+	result := teg.v_form(c)
 	if result != FALSE {
 		t.Error("v_form should fail when not in a city")
 	}
@@ -195,17 +195,17 @@ func TestVFormInCity(t *testing.T) {
 	playerID := 100
 	cityID := 59001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(cityID, T_loc, sub_city)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(cityID, T_loc, sub_city)
 
-	p_char(charID).unit_lord = playerID
-	p_player(playerID).noble_points = 5
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_player(playerID).noble_points = 5
 
-	set_where(charID, cityID)
+	teg.set_where(charID, cityID)
 
 	c := &command{who: charID}
-	result := v_form(c)
+	result := teg.v_form(c)
 	if result != TRUE {
 		t.Error("v_form should succeed when in a city with NP")
 	}
@@ -218,17 +218,17 @@ func TestVFormInsufficientNP(t *testing.T) {
 	playerID := 100
 	cityID := 59001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(cityID, T_loc, sub_city)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(cityID, T_loc, sub_city)
 
-	p_char(charID).unit_lord = playerID
-	p_player(playerID).noble_points = 0
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_player(playerID).noble_points = 0
 
-	set_where(charID, cityID)
+	teg.set_where(charID, cityID)
 
 	c := &command{who: charID}
-	result := v_form(c)
+	result := teg.v_form(c)
 	if result != FALSE {
 		t.Error("v_form should fail with 0 NP")
 	}
@@ -242,28 +242,28 @@ func TestDFormCreatesNoble(t *testing.T) {
 	cityID := 59001
 	unformedID := 5001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(cityID, T_loc, sub_city)
-	alloc_box(unformedID, T_unform, 0)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(cityID, T_loc, sub_city)
+	teg.alloc_box(unformedID, T_unform, 0)
 
-	p_char(charID).unit_lord = playerID
-	p_player(playerID).noble_points = 5
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_player(playerID).noble_points = 5
 
-	set_where(charID, cityID)
-	addPlayerUnformed(playerID, unformedID)
+	teg.set_where(charID, cityID)
+	teg.addPlayerUnformed(playerID, unformedID)
 
 	c := &command{who: charID}
-	result := d_form(c)
+	result := teg.d_form(c)
 	if result != TRUE {
 		t.Error("d_form should succeed")
 	}
 
-	if kind(unformedID) != T_char {
-		t.Errorf("unformed noble should now be T_char, got %d", kind(unformedID))
+	if teg.kind(unformedID) != T_char {
+		t.Errorf("unformed noble should now be T_char, got %d", teg.kind(unformedID))
 	}
 
-	newChar := rp_char(unformedID)
+	newChar := teg.rp_char(unformedID)
 	if newChar == nil {
 		t.Fatal("new noble should have character data")
 	}
@@ -280,11 +280,11 @@ func TestDFormCreatesNoble(t *testing.T) {
 		t.Errorf("new noble break_point = %d, want 50", newChar.break_point)
 	}
 
-	if player_np(playerID) != 4 {
-		t.Errorf("NP after formation = %d, want 4", player_np(playerID))
+	if teg.player_np(playerID) != 4 {
+		t.Errorf("NP after formation = %d, want 4", teg.player_np(playerID))
 	}
 
-	unformed := getPlayerUnformed(playerID)
+	unformed := teg.getPlayerUnformed(playerID)
 	if len(unformed) != 0 {
 		t.Errorf("unformed list should be empty after formation, got %v", unformed)
 	}
@@ -300,39 +300,39 @@ func TestDFormWithSpecificID(t *testing.T) {
 	unformedID2 := 5002
 	unformedID3 := 5003
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(cityID, T_loc, sub_city)
-	alloc_box(unformedID1, T_unform, 0)
-	alloc_box(unformedID2, T_unform, 0)
-	alloc_box(unformedID3, T_unform, 0)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(cityID, T_loc, sub_city)
+	teg.alloc_box(unformedID1, T_unform, 0)
+	teg.alloc_box(unformedID2, T_unform, 0)
+	teg.alloc_box(unformedID3, T_unform, 0)
 
-	p_char(charID).unit_lord = playerID
-	p_player(playerID).noble_points = 5
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_player(playerID).noble_points = 5
 
-	set_where(charID, cityID)
-	addPlayerUnformed(playerID, unformedID1)
-	addPlayerUnformed(playerID, unformedID2)
-	addPlayerUnformed(playerID, unformedID3)
+	teg.set_where(charID, cityID)
+	teg.addPlayerUnformed(playerID, unformedID1)
+	teg.addPlayerUnformed(playerID, unformedID2)
+	teg.addPlayerUnformed(playerID, unformedID3)
 
 	c := &command{who: charID, a: unformedID2}
-	result := d_form(c)
+	result := teg.d_form(c)
 	if result != TRUE {
 		t.Error("d_form with specific ID should succeed")
 	}
 
-	if kind(unformedID2) != T_char {
-		t.Errorf("specified unformed noble should now be T_char, got %d", kind(unformedID2))
+	if teg.kind(unformedID2) != T_char {
+		t.Errorf("specified unformed noble should now be T_char, got %d", teg.kind(unformedID2))
 	}
 
-	if kind(unformedID1) != T_unform {
+	if teg.kind(unformedID1) != T_unform {
 		t.Error("other unformed nobles should remain T_unform")
 	}
-	if kind(unformedID3) != T_unform {
+	if teg.kind(unformedID3) != T_unform {
 		t.Error("other unformed nobles should remain T_unform")
 	}
 
-	unformed := getPlayerUnformed(playerID)
+	unformed := teg.getPlayerUnformed(playerID)
 	if len(unformed) != 2 {
 		t.Errorf("expected 2 remaining unformed, got %d", len(unformed))
 	}
@@ -345,17 +345,17 @@ func TestDFormNoUnformed(t *testing.T) {
 	playerID := 100
 	cityID := 59001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(cityID, T_loc, sub_city)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(cityID, T_loc, sub_city)
 
-	p_char(charID).unit_lord = playerID
-	p_player(playerID).noble_points = 5
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_player(playerID).noble_points = 5
 
-	set_where(charID, cityID)
+	teg.set_where(charID, cityID)
 
 	c := &command{who: charID}
-	result := d_form(c)
+	result := teg.d_form(c)
 	if result != FALSE {
 		t.Error("d_form should fail with no unformed nobles")
 	}
@@ -369,25 +369,25 @@ func TestDFormInvalidUnformedID(t *testing.T) {
 	cityID := 59001
 	unformedID := 5001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(cityID, T_loc, sub_city)
-	alloc_box(unformedID, T_unform, 0)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(cityID, T_loc, sub_city)
+	teg.alloc_box(unformedID, T_unform, 0)
 
-	p_char(charID).unit_lord = playerID
-	p_player(playerID).noble_points = 5
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_player(playerID).noble_points = 5
 
-	set_where(charID, cityID)
-	addPlayerUnformed(playerID, unformedID)
+	teg.set_where(charID, cityID)
+	teg.addPlayerUnformed(playerID, unformedID)
 
 	c := &command{who: charID, a: 9999}
-	result := d_form(c)
+	result := teg.d_form(c)
 	if result != TRUE {
 		t.Error("d_form with invalid ID should fall back to first unformed")
 	}
 
-	if kind(unformedID) != T_char {
-		t.Errorf("fallback to first unformed should work, got kind %d", kind(unformedID))
+	if teg.kind(unformedID) != T_char {
+		t.Errorf("fallback to first unformed should work, got kind %d", teg.kind(unformedID))
 	}
 }
 
@@ -399,23 +399,23 @@ func TestFormNewNobleStats(t *testing.T) {
 	cityID := 59001
 	newID := 5001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(cityID, T_loc, sub_city)
-	alloc_box(newID, T_unform, 0)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(cityID, T_loc, sub_city)
+	teg.alloc_box(newID, T_unform, 0)
 
-	p_char(charID).unit_lord = playerID
-	p_char(charID).behind = 1
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_char(charID).behind = 1
 
-	set_where(charID, cityID)
+	teg.set_where(charID, cityID)
 
-	form_new_noble(charID, "Sir Test", newID)
+	teg.form_new_noble(charID, "Sir Test", newID)
 
-	if kind(newID) != T_char {
-		t.Errorf("new noble kind = %d, want T_char", kind(newID))
+	if teg.kind(newID) != T_char {
+		t.Errorf("new noble kind = %d, want T_char", teg.kind(newID))
 	}
 
-	n := rp_char(newID)
+	n := teg.rp_char(newID)
 	if n.behind != 1 {
 		t.Errorf("new noble behind = %d, want 1 (inherited from forming char)", n.behind)
 	}
@@ -449,19 +449,19 @@ func TestPrintHiringStatus(t *testing.T) {
 	setupFormTest()
 
 	playerID := 100
-	alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
 
-	print_hiring_status(playerID)
+	teg.print_hiring_status(playerID)
 }
 
 func TestPrintUnformed(t *testing.T) {
 	setupFormTest()
 
 	playerID := 100
-	alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
 
-	addPlayerUnformed(playerID, 5001)
-	addPlayerUnformed(playerID, 5002)
+	teg.addPlayerUnformed(playerID, 5001)
+	teg.addPlayerUnformed(playerID, 5002)
 
-	print_unformed(playerID)
+	teg.print_unformed(playerID)
 }
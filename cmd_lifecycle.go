@@ -16,6 +16,8 @@
 
 package taygete
 
+import "strings"
+
 // cmd_lifecycle.go -- Command lifecycle and scheduling (Sprint 22)
 //
 // This file ports the command scheduling logic from input.c.
@@ -55,6 +57,7 @@ func (e *Engine) set_state(c *command, state int, newPri int) {
 	if c == nil {
 		return
 	}
+	e.markDirty(c.who)
 
 	queues := e.globals.cmdQueues
 	if queues == nil {
@@ -97,21 +100,6 @@ func ilistRemValue(l []int, val int) []int {
 	return l
 }
 
-// p_command returns the command structure for an entity, creating one if needed.
-// Port of C p_command().
-func (e *Engine) p_command(who int) *command {
-	if who <= 0 || who >= MAX_BOXES {
-		return nil
-	}
-	if e.globals.bx[who] == nil {
-		return nil
-	}
-	if e.globals.bx[who].cmd == nil {
-		e.globals.bx[who].cmd = &command{who: who, state: STATE_DONE}
-	}
-	return e.globals.bx[who].cmd
-}
-
 // get_command retrieves the next order from the unit's queue.
 // Returns the order text and true if an order exists, or empty string and false.
 // Port of C get_command().
@@ -143,6 +131,7 @@ func (e *Engine) load_command(c *command) bool {
 	if c == nil {
 		return false
 	}
+	e.markDirty(c.who)
 
 	line, ok := e.get_command(c.who)
 	if !ok {
@@ -167,43 +156,120 @@ func (e *Engine) load_command(c *command) bool {
 	return true
 }
 
-// oly_parse parses a command line and populates the command structure.
-// This is a stub that will be fully implemented when command parsing is ported.
-// For now, it does basic parsing to extract the command name.
-// Port of C oly_parse().
+// oly_parse looks up the command and tokenizes its arguments.
+// The first eight arguments are converted into c.a through c.h.
+// Port of C oly_parse() from input.c.
 func (e *Engine) oly_parse(c *command, line string) bool {
-	if c == nil || line == "" {
+	if c == nil {
 		return false
 	}
 
-	c.line = strToCharPtr(line)
+	c.a, c.b, c.c, c.d, c.e, c.f, c.g, c.h = 0, 0, 0, 0, 0, 0, 0, 0
 
-	// Parse the line into arguments
-	args := parse_line(line)
-	if len(args) == 0 {
+	if !e.oly_parse_cmd(c, line) {
 		return false
 	}
 
-	// Look up the command
-	cmdIndex := e.find_command(args[0])
-	if cmdIndex < 0 {
+	args := make([]int, 8)
+	for i := 1; i < len(c.parse) && i <= 8; i++ {
+		args[i-1] = e.parse_arg(c.who, c.parse[i])
+	}
+	c.a, c.b, c.c, c.d, c.e, c.f, c.g, c.h = args[0], args[1], args[2], args[3], args[4], args[5], args[6], args[7]
+
+	return true
+}
+
+// oly_parse_cmd strips comments and conditionals from the line,
+// splits it into arguments and looks up the command.
+// Port of C oly_parse_cmd() from input.c.
+func (e *Engine) oly_parse_cmd(c *command, line string) bool {
+	c.cmd = 0
+	c.fuzzy = FALSE
+	c.use_skill = 0
+
+	line = remove_ctrl_chars(remove_comment(trimWhitespace(line)))
+	c.line = line
+
+	switch {
+	case len(line) > 0 && line[0] == '&':
+		c.conditional = 1
+		line = line[1:]
+	case len(line) > 0 && line[0] == '?':
+		c.conditional = 2
+		line = line[1:]
+	default:
+		c.conditional = 0
+	}
+
+	c.parse = parse_line(line)
+	if len(c.parse) == 0 {
 		return false
 	}
 
-	c.cmd = cmdIndex
-	// Store parsed arguments - will be expanded in later sprints
+	i, fuzzy := e.find_command(c.parse[0])
+	if i <= 0 {
+		return false
+	}
+	c.cmd = i
+	if fuzzy {
+		c.fuzzy = TRUE
+	}
+
 	return true
 }
 
-// strToCharPtr converts a Go string to a *char (for compatibility with C port).
-// In Go, we just return a pointer to the first byte as schar.
-func strToCharPtr(s string) *char {
-	if s == "" {
-		return nil
+// parse_arg converts an order argument into an entity number.
+// "garrison" is accepted in place of the code of the garrison
+// in the unit's current location.
+// Port of C parse_arg() from input.c.
+func (e *Engine) parse_arg(who int, s string) int {
+	n := scode(s)
+	if n < 0 {
+		n = 0
+	}
+
+	if n == 0 && who != 0 && e.globals.bx.get(who) != nil && fuzzy_strcmp(s, "garrison") {
+		if where := e.subloc(who); where != 0 {
+			n = e.garrison_here(where)
+			if n == 0 {
+				n = e.globals.garrison_magic
+			}
+		}
+	}
+
+	return n
+}
+
+// remove_comment truncates the line at the first '#' that is not
+// inside a quoted string.
+// Port of C remove_comment() from input.c.
+func remove_comment(s string) string {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\'':
+			if j := strings.IndexByte(s[i+1:], s[i]); j >= 0 {
+				i += j + 1
+			} else {
+				return s
+			}
+		case '#':
+			return s[:i]
+		}
 	}
+	return s
+}
+
+// remove_ctrl_chars strips the high bit and replaces control characters with spaces.
+// Port of C remove_ctrl_chars() from input.c.
+func remove_ctrl_chars(s string) string {
 	b := []byte(s)
-	c := char(b[0])
-	return &c
+	for i := range b {
+		b[i] &= 0x7F
+		if b[i] < 32 {
+			b[i] = ' '
+		}
+	}
+	return string(b)
 }
 
 // parse_line splits a command line into arguments, respecting quotes.
@@ -277,42 +343,53 @@ func trimWhitespace(s string) string {
 	return s[start:end]
 }
 
-// find_command looks up a command by name and returns its index.
-// Returns -1 if not found.
-// This is a stub that will be fully implemented when the command table is ported.
-func (e *Engine) find_command(name string) int {
-	// For now, just return 0 for any non-empty command name
-	// The actual command table will be implemented in later sprints
+// find_command looks up a command by name and returns its index in cmd_tbl.
+// An exact match is preferred; otherwise the first fuzzy match is used
+// and fuzzy is set. Returns -1 if the name matches nothing.
+// Port of C find_command() from input.c.
+func (e *Engine) find_command(name string) (index int, fuzzy bool) {
 	if name == "" {
-		return -1
+		return -1, false
+	}
+
+	for i := 1; i < len(cmd_tbl); i++ {
+		if i_strcmp(cmd_tbl[i].name, name) == 0 {
+			return i, false
+		}
 	}
-	// Check for "stop" command which is special
-	if i_strcmp(name, "stop") == 0 {
-		return 0 // cmd_stop = 0
+
+	for i := 1; i < len(cmd_tbl); i++ {
+		if fuzzy_strcmp(cmd_tbl[i].name, name) {
+			return i, true
+		}
 	}
-	// Return a placeholder command index
-	return 1
+
+	return -1, false
 }
 
 // cmd_pri returns the priority for a command from the command table.
 // Priority 0 = highest, 4 = lowest.
-// This is a stub that will be fully implemented when the command table is ported.
 func (e *Engine) cmd_pri(cmdIndex int) int {
-	// Default priority is 2 (middle)
-	return 2
+	if cmdIndex < 0 || cmdIndex >= len(cmd_tbl) {
+		return 2
+	}
+	return cmd_tbl[cmdIndex].pri
 }
 
 // cmd_time returns the execution time for a command from the command table.
-// This is a stub that will be fully implemented when the command table is ported.
 func (e *Engine) cmd_time(cmdIndex int) int {
-	// Default execution time is 0 (immediate)
-	return 0
+	if cmdIndex < 0 || cmdIndex >= len(cmd_tbl) {
+		return 0
+	}
+	return cmd_tbl[cmdIndex].time
 }
 
 // cmd_poll returns whether a command should be polled each day.
-// This is a stub that will be fully implemented when the command table is ported.
 func (e *Engine) cmd_poll(cmdIndex int) int {
-	return 0
+	if cmdIndex < 0 || cmdIndex >= len(cmd_tbl) {
+		return 0
+	}
+	return cmd_tbl[cmdIndex].poll
 }
 
 // command_done marks a command as done and loads the next one.
@@ -413,7 +490,7 @@ func (e *Engine) min_pri_ready() int {
 			if e.is_prisoner(who) {
 				continue
 			}
-			if e.char_moving(who) {
+			if e.char_moving(who) != 0 {
 				continue
 			}
 			if c.second_wait != 0 {
@@ -426,19 +503,6 @@ func (e *Engine) min_pri_ready() int {
 	return 99
 }
 
-// is_prisoner returns true if the character is a prisoner.
-// This is a stub that will be fully implemented when combat/capture is ported.
-func (e *Engine) is_prisoner(who int) bool {
-	if e.globals.bx[who] == nil {
-		return false
-	}
-	ch := e.globals.bx[who].x_char
-	if ch == nil {
-		return false
-	}
-	return ch.prisoner != 0
-}
-
 // start_phase executes loaded commands in priority order.
 // Port of C start_phase() from input.c.
 func (e *Engine) start_phase() {
@@ -474,7 +538,7 @@ func (e *Engine) start_phase() {
 			if c.state == STATE_LOAD &&
 				int(c.pri) == pri &&
 				!e.is_prisoner(who) &&
-				!e.char_moving(who) &&
+				e.char_moving(who) == 0 &&
 				c.second_wait == 0 {
 				e.do_command(c)
 				e.checkAllWaits()
@@ -487,31 +551,86 @@ func (e *Engine) start_phase() {
 	}
 }
 
+// check_allow returns true if the unit is permitted to issue the command.
+// Port of C check_allow() from input.c.
+func (e *Engine) check_allow(c *command, allow string) bool {
+	if allow == "" {
+		return true
+	}
+
+	if e.globals.immediate && strings.IndexByte(allow, 'i') >= 0 {
+		return true
+	}
+
+	var t byte
+	switch e.Kind(c.who) {
+	case T_player:
+		t = 'p'
+	case T_char:
+		if m := e.globals.bx.get(c.who).x_misc; m != nil && m.cmd_allow != 0 {
+			t = byte(m.cmd_allow)
+		} else {
+			t = 'c'
+		}
+	default:
+		return false
+	}
+
+	if strings.IndexByte(allow, 'm') >= 0 && e.player(c.who) == gm_player {
+		return true
+	}
+
+	if strings.IndexByte(allow, t) < 0 {
+		e.wout(c.who, "%s may not issue that order.", e.box_name(c.who))
+		return false
+	}
+
+	return true
+}
+
 // do_command executes a single command.
 // Port of C do_command() from input.c.
-// This is a stub that will be fully implemented when command execution is ported.
 func (e *Engine) do_command(c *command) {
 	if c == nil {
 		return
 	}
+	e.markDirty(c.who)
+
+	if !e.globals.immediate {
+		e.out(c.who, "> %s", c.line)
+		if c.fuzzy != FALSE {
+			e.out(c.who, "(assuming you meant '%s')", cmd_tbl[c.cmd].name)
+		}
+	}
 
 	if c.state == STATE_ERROR {
-		// Report error to player
+		e.out(c.who, "Unrecognized command.")
 		c.status = FALSE
-		e.commandDone(c)
-		return
-	}
+	} else if !e.check_allow(c, cmd_tbl[c.cmd].allow) {
+		c.status = FALSE
+	} else if cmd_tbl[c.cmd].start == nil {
+		e.out(c.who, "Unimplemented command.")
+		c.status = FALSE
+	} else {
+		if p := e.rp_player(e.player(c.who)); p != nil {
+			p.cmd_count++
+		}
 
-	// Check if command is allowed - stub for now
-	// Transition to running state
-	e.set_state(c, STATE_RUN, 0)
-	c.days_executing = 0
-	c.inhibit_finish = 0
-	c.status = TRUE // Assume success for now
+		e.set_state(c, STATE_RUN, 0)
+		c.days_executing = 0
+		c.debug = 0
+		c.inhibit_finish = FALSE
+		c.status = schar(cmd_tbl[c.cmd].start(e, c))
+	}
 
-	// If command has no wait time, finish immediately
-	if c.wait == 0 && c.state == STATE_RUN {
-		e.finish_command(c)
+	if c.status == FALSE {
+		e.commandDone(c)
+	} else if c.wait == 0 && c.state == STATE_RUN {
+		if e.finish_command(c) {
+			c.status = TRUE
+		} else {
+			c.status = FALSE
+		}
 	}
 }
 
@@ -521,15 +640,16 @@ func (e *Engine) finish_command(c *command) bool {
 	if c == nil {
 		return false
 	}
+	e.markDirty(c.who)
 
 	if e.Kind(c.who) == T_deadchar {
 		e.commandDone(c)
 		return false
 	}
 
-	// Characters stacked under moving units have commands suspended
-	if e.char_gone(c.who) && e.stack_leader(c.who) != c.who {
-		// Suspend unless it's a wait command
+	// Characters stacked under moving units have commands suspended,
+	// except for wait completion checks.
+	if e.char_gone(c.who) != 0 && e.stack_leader(c.who) != c.who && !is_wait_command(c.cmd) {
 		return true
 	}
 
@@ -537,10 +657,12 @@ func (e *Engine) finish_command(c *command) bool {
 		c.wait--
 	}
 
-	// Call finish routine when done or if polling
+	// Call the finish routine once, when the command is done waiting,
+	// or every evening if the poll flag is set.
 	if c.wait <= 0 || c.poll != 0 {
-		// Finish routine would be called here
-		c.status = TRUE
+		if c.cmd >= 0 && c.cmd < len(cmd_tbl) && cmd_tbl[c.cmd].finish != nil && c.inhibit_finish == FALSE {
+			c.status = schar(cmd_tbl[c.cmd].finish(e, c))
+		}
 	}
 
 	if c.state == STATE_RUN && (c.status == FALSE || c.wait == 0) {
@@ -550,6 +672,24 @@ func (e *Engine) finish_command(c *command) bool {
 	return c.status != 0
 }
 
+// interrupt_order stops the unit's running command, calling its
+// interrupt routine if it has one.
+// Port of C interrupt_order() from input.c.
+func (e *Engine) interrupt_order(who int) {
+	c := e.rp_command(who)
+	if c == nil {
+		return
+	}
+	e.markDirty(who)
+
+	if c.state == STATE_RUN {
+		if c.cmd >= 0 && c.cmd < len(cmd_tbl) && cmd_tbl[c.cmd].interrupt != nil {
+			c.status = schar(cmd_tbl[c.cmd].interrupt(e, c))
+		}
+		e.commandDone(c)
+	}
+}
+
 // evening_phase processes running commands at end of day.
 // Port of C evening_phase() from input.c.
 func (e *Engine) evening_phase() {
@@ -597,20 +737,3 @@ func (e *Engine) checkAllWaits() {
 func (e *Engine) checkAllAutoAttacks() {
 	// Will be implemented with combat
 }
-
-// stack_leader returns the stack leader for a character.
-// This is a stub - the full implementation exists in stack.go.
-func (e *Engine) stack_leader(who int) int {
-	// Return self for now - full implementation in stack.go
-	return who
-}
-
-// char_moving returns the movement timestamp for a character (Engine method wrapper).
-func (e *Engine) char_moving(who int) bool {
-	return char_moving(who) != 0
-}
-
-// char_gone returns true if the character is currently moving (Engine method wrapper).
-func (e *Engine) char_gone(who int) bool {
-	return char_gone(who) != 0
-}
@@ -48,6 +48,7 @@ func TestMaxPriority(t *testing.T) {
 // TestInitCommandQueues tests queue initialization.
 func TestInitCommandQueues(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 
 	e.initCommandQueues()
 
@@ -71,11 +72,12 @@ func TestInitCommandQueues(t *testing.T) {
 // TestSetState tests state transitions.
 func TestSetState(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 	e.initCommandQueues()
 
 	// Create a test box and command
 	who := 1001
-	e.globals.bx[who] = &box{kind: T_char}
+	e.setBox(who, &box{kind: T_char})
 	c := e.p_command(who)
 	c.who = who
 	c.state = STATE_DONE
@@ -117,6 +119,7 @@ func TestSetState(t *testing.T) {
 // TestMinPriReady tests priority scheduling.
 func TestMinPriReady(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 	e.initCommandQueues()
 
 	// No commands = priority 99
@@ -126,8 +129,8 @@ func TestMinPriReady(t *testing.T) {
 
 	// Create two characters with commands at different priorities
 	who1, who2 := 1001, 1002
-	e.globals.bx[who1] = &box{kind: T_char, x_char: &entity_char{}}
-	e.globals.bx[who2] = &box{kind: T_char, x_char: &entity_char{}}
+	e.setBox(who1, &box{kind: T_char, x_char: &entity_char{}})
+	e.setBox(who2, &box{kind: T_char, x_char: &entity_char{}})
 
 	c1 := e.p_command(who1)
 	c1.who = who1
@@ -218,13 +221,14 @@ func TestParseLine(t *testing.T) {
 // TestLoadCommand tests command loading from order queue.
 func TestLoadCommand(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 	e.initCommandQueues()
 
 	// Create a player and character
 	playerID := 100
 	who := 1001
-	e.globals.bx[playerID] = &box{kind: T_player, x_player: &entity_player{}}
-	e.globals.bx[who] = &box{kind: T_char, x_char: &entity_char{unit_lord: playerID}}
+	e.setBox(playerID, &box{kind: T_player, x_player: &entity_player{}})
+	e.setBox(who, &box{kind: T_char, x_char: &entity_char{unit_lord: playerID}})
 
 	c := e.p_command(who)
 	c.who = who
@@ -252,12 +256,13 @@ func TestLoadCommand(t *testing.T) {
 // TestInitLoadSup tests initialization of command loading for a unit.
 func TestInitLoadSup(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 	e.initCommandQueues()
 
 	playerID := 100
 	who := 1001
-	e.globals.bx[playerID] = &box{kind: T_player, x_player: &entity_player{}}
-	e.globals.bx[who] = &box{kind: T_char, x_char: &entity_char{unit_lord: playerID}}
+	e.setBox(playerID, &box{kind: T_player, x_player: &entity_player{}})
+	e.setBox(who, &box{kind: T_char, x_char: &entity_char{unit_lord: playerID}})
 
 	// Set up player in kind list for Players() to work
 	e.globals.box_head[T_player] = playerID
@@ -281,12 +286,13 @@ func TestInitLoadSup(t *testing.T) {
 // TestCommandDone tests command completion.
 func TestCommandDone(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 	e.initCommandQueues()
 
 	playerID := 100
 	who := 1001
-	e.globals.bx[playerID] = &box{kind: T_player, x_player: &entity_player{}}
-	e.globals.bx[who] = &box{kind: T_char, x_char: &entity_char{unit_lord: playerID}}
+	e.setBox(playerID, &box{kind: T_player, x_player: &entity_player{}})
+	e.setBox(who, &box{kind: T_char, x_char: &entity_char{unit_lord: playerID}})
 
 	c := e.p_command(who)
 	c.who = who
@@ -315,10 +321,11 @@ func TestCommandDone(t *testing.T) {
 // TestEveningPhase tests the evening phase processing.
 func TestEveningPhase(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 	e.initCommandQueues()
 
 	who := 1001
-	e.globals.bx[who] = &box{kind: T_char, x_char: &entity_char{}}
+	e.setBox(who, &box{kind: T_char, x_char: &entity_char{}})
 
 	c := e.p_command(who)
 	c.who = who
@@ -349,10 +356,11 @@ func TestEveningPhase(t *testing.T) {
 // TestPrisonerCannotExecute tests that prisoners cannot execute commands.
 func TestPrisonerCannotExecute(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 	e.initCommandQueues()
 
 	who := 1001
-	e.globals.bx[who] = &box{kind: T_char, x_char: &entity_char{prisoner: 1}}
+	e.setBox(who, &box{kind: T_char, x_char: &entity_char{prisoner: 1}})
 
 	c := e.p_command(who)
 	c.who = who
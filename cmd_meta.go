@@ -23,30 +23,19 @@ import (
 	"strings"
 )
 
-// has_auraculum returns the auraculum item ID if who has their auraculum,
-// otherwise returns 0.
-// Ported from src/art.c lines 7-18.
-func has_auraculum(who int) int {
-	ac := char_auraculum(who)
-	if ac != 0 && valid_box(ac) && has_item(who, ac) > 0 {
-		return ac
-	}
-	return 0
-}
-
 // may_rule_here returns true if who may rule at location where.
 // If where is a garrison, checks via the garrison's castle owner.
 // If where is a location, checks via province_admin chain.
 // Ported from src/garr.c lines 143-165.
-func may_rule_here(who, where int) bool {
-	pl := player(who)
+func (e *Engine) may_rule_here(who, where int) bool {
+	pl := e.player(who)
 
-	admin := province_admin(where)
+	admin := e.province_admin(where)
 	for admin > 0 {
-		if player(admin) == pl {
+		if e.player(admin) == pl {
 			return true
 		}
-		admin = char_pledge(admin)
+		admin = e.char_pledge(admin)
 	}
 	return false
 }
@@ -55,11 +44,11 @@ func may_rule_here(who, where int) bool {
 // For a province, looks up the garrison and its castle owner.
 // For a garrison, looks up its castle owner.
 // Ported from src/garr.c lines 74-98.
-func province_admin(n int) int {
+func (e *Engine) province_admin(n int) int {
 	var garr int
 
-	if kind(n) == T_loc {
-		garr = garrison_here(n)
+	if e.kind(n) == T_loc {
+		garr = e.garrison_here(n)
 		if garr == 0 {
 			return 0
 		}
@@ -67,29 +56,29 @@ func province_admin(n int) int {
 		garr = n
 	}
 
-	castle := garrison_castle(garr)
-	if !valid_box(castle) {
+	castle := e.garrison_castle(garr)
+	if !e.valid_box(castle) {
 		return 0
 	}
 
-	return building_owner(castle)
+	return e.building_owner(castle)
 }
 
 // Note: char_pledge is defined in accessor.go
 
 // loc_owner_list returns a list of location owners for the given province.
 // This is a simplified implementation of loop_loc_owner from C.
-func loc_owner_list(where int) []int {
+func (e *Engine) loc_owner_list(where int) []int {
 	var owners []int
 
-	p := rp_loc_info(where)
+	p := e.rp_loc_info(where)
 	if p == nil {
 		return owners
 	}
 
 	for _, id := range p.here_list {
-		if kind(id) == T_loc && loc_depth(id) == LOC_build {
-			owner := building_owner(id)
+		if e.kind(id) == T_loc && e.loc_depth(id) == LOC_build {
+			owner := e.building_owner(id)
 			if owner > 0 {
 				owners = append(owners, owner)
 			}
@@ -100,30 +89,30 @@ func loc_owner_list(where int) []int {
 
 // may_name returns true if who may rename target.
 // Ported from src/c1.c lines 182-227.
-func may_name(who, target int) bool {
-	switch kind(target) {
+func (e *Engine) may_name(who, target int) bool {
+	switch e.kind(target) {
 	case T_char, T_player:
-		return player(who) == player(target)
+		return e.player(who) == e.player(target)
 
 	case T_loc:
-		if safe_haven(target) != 0 {
+		if e.safe_haven(target) != 0 {
 			return false
 		}
 		fallthrough
 	case T_ship:
-		if loc_depth(target) == LOC_build {
-			return player(who) == player(building_owner(target))
+		if e.loc_depth(target) == LOC_build {
+			return e.player(who) == e.player(e.building_owner(target))
 		}
-		return may_rule_here(who, target)
+		return e.may_rule_here(who, target)
 
 	case T_item:
-		if has_auraculum(who) == target {
+		if e.has_auraculum(who) == target {
 			return true
 		}
-		if item_creator(target) == who {
+		if e.item_creator(target) == who {
 			return true
 		}
-		switch item_use_key(target) {
+		switch e.item_use_key(target) {
 		case use_death_potion, use_heal_potion, use_slave_potion,
 			use_proj_cast, use_quick_cast:
 			return true
@@ -131,7 +120,7 @@ func may_name(who, target int) bool {
 		return false
 
 	case T_storm:
-		if npc_summoner(target) == who {
+		if e.npc_summoner(target) == who {
 			return true
 		}
 	}
@@ -141,7 +130,11 @@ func may_name(who, target int) bool {
 
 // cmd_shift shifts command arguments left by one position.
 // Ported from src/input.c lines 288-313.
-func cmd_shift(c *command) {
+func (e *Engine) cmd_shift(c *command) {
+	if len(c.parse) > 1 {
+		c.parse = append(c.parse[:1], c.parse[2:]...)
+	}
+
 	c.a = c.b
 	c.b = c.c
 	c.c = c.d
@@ -149,7 +142,11 @@ func cmd_shift(c *command) {
 	c.e = c.f
 	c.f = c.g
 	c.g = c.h
-	c.h = 0
+	if len(c.parse) > 8 {
+		c.h = e.parse_arg(c.who, c.parse[8])
+	} else {
+		c.h = 0
+	}
 }
 
 // rest_name returns all parsed arguments from position a onwards as a single string.
@@ -163,30 +160,16 @@ func rest_name(c *command, a int) string {
 }
 
 // cmd_parse_args returns the parsed arguments for a command.
-// This is a helper that extracts arguments from c.parse.
 func cmd_parse_args(c *command) []string {
-	if c == nil || c.parse == nil {
+	if c == nil {
 		return nil
 	}
-
-	var args []string
-	for i := 0; ; i++ {
-		ptr := c.parse
-		if ptr == nil {
-			break
-		}
-		// Walk the plist of char pointers
-		// For now, use a simplified implementation based on args a-h
-		break
-	}
-
-	// Fallback: build args from a-h fields (simplified implementation)
-	return args
+	return c.parse
 }
 
 // cmd_numargs_full returns the number of parsed arguments.
 // This is the full implementation that counts parse array elements.
-func cmd_numargs_full(c *command) int {
+func (e *Engine) cmd_numargs_full(c *command) int {
 	if c == nil {
 		return 0
 	}
@@ -222,35 +205,45 @@ func cmd_numargs_full(c *command) int {
 // v_look executes the LOOK command.
 // Shows the current location to the character.
 // Ported from src/c1.c lines 9-22.
-func v_look(c *command) int {
-	if kind(c.who) != T_char {
-		wout(c.who, "%s is not a character.", box_name(c.who))
+func (e *Engine) v_look(c *command) int {
+	if e.kind(c.who) != T_char {
+		wout(c.who, "%s is not a character.", e.box_name(c.who))
 		return FALSE
 	}
 
-	show_loc(c.who, subloc(c.who))
+	e.show_loc(c.who, e.subloc(c.who))
 	return TRUE
 }
 
 // show_loc displays location information to the viewer.
 // This is a stub that will be implemented in Sprint 26+ with display system.
-func show_loc(viewer, where int) {
+func (e *Engine) show_loc(viewer, where int) {
 	// TODO: Implement full location display in later sprint
-	wout(viewer, "Location: %s", box_name(where))
+	wout(viewer, "Location: %s", e.box_name(where))
+	e.list_exits(viewer, where)
+}
+
+// char_rep_sup displays a character report for num to who.
+// This is a stub that will be implemented in Sprint 26+ with display system.
+func (e *Engine) char_rep_sup(who, num int) {
+	// TODO: Implement full character report in later sprint
+	out(who, "Location:       %s", e.box_name(e.subloc(num)))
+	out(who, "Health:         %d", e.char_health(num))
+	out(who, "")
 }
 
 // v_name executes the NAME command.
 // Renames an entity with permission checks and length limits.
 // Ported from src/c1.c lines 230-287.
-func v_name(c *command) int {
+func (e *Engine) v_name(c *command) int {
 	target := c.who
 	var newName string
 	var maxLen int
 
 	// If first arg is an entity ID, use it as target and shift
-	if cmd_numargs_full(c) >= 2 && c.a > 0 {
+	if e.cmd_numargs_full(c) >= 2 && c.a > 0 {
 		target = c.a
-		cmd_shift(c)
+		e.cmd_shift(c)
 	}
 
 	newName = rest_name(c, 1)
@@ -260,12 +253,12 @@ func v_name(c *command) int {
 		return FALSE
 	}
 
-	if !may_name(c.who, target) {
-		wout(c.who, "Not allowed to change the name of %s.", box_code(target))
+	if !e.may_name(c.who, target) {
+		wout(c.who, "Not allowed to change the name of %s.", e.box_code(target))
 		return FALSE
 	}
 
-	switch kind(target) {
+	switch e.kind(target) {
 	case T_char:
 		maxLen = 35
 	default:
@@ -277,13 +270,13 @@ func v_name(c *command) int {
 		return FALSE
 	}
 
-	oldName := box_name(target)
-	set_name(target, newName)
+	oldName := e.box_name(target)
+	e.set_name(target, newName)
 
-	wout(c.who, "%s will now be known as %s.", oldName, box_name(target))
+	wout(c.who, "%s will now be known as %s.", oldName, e.box_name(target))
 
-	if target != c.who && (kind(target) == T_char || is_loc_or_ship(target)) {
-		wout(target, "%s will now be known as %s.", oldName, box_name(target))
+	if target != c.who && (e.kind(target) == T_char || e.is_loc_or_ship(target)) {
+		wout(target, "%s will now be known as %s.", oldName, e.box_name(target))
 	}
 
 	return TRUE
@@ -292,7 +285,7 @@ func v_name(c *command) int {
 // v_fullname executes the FULLNAME command.
 // Sets the player's full_name field.
 // Ported from src/c1.c lines 312-338.
-func v_fullname(c *command) int {
+func (e *Engine) v_fullname(c *command) int {
 	newName := rest_name(c, 1)
 
 	if newName == "" {
@@ -305,9 +298,8 @@ func v_fullname(c *command) int {
 		return FALSE
 	}
 
-	p := p_player(player(c.who))
-	// Note: In Go we use strings, not char pointers that need freeing
-	p.full_name = strToCharPtr(newName)
+	p := e.p_player(e.player(c.who))
+	p.full_name = newName
 
 	return TRUE
 }
@@ -315,21 +307,21 @@ func v_fullname(c *command) int {
 // v_banner executes the BANNER command.
 // Sets the display banner for an entity.
 // Ported from src/c1.c lines 341-394.
-func v_banner(c *command) int {
+func (e *Engine) v_banner(c *command) int {
 	target := c.who
 
 	// If first arg is an entity ID, use it as target and shift
-	if cmd_numargs_full(c) >= 2 && c.a > 0 {
+	if e.cmd_numargs_full(c) >= 2 && c.a > 0 {
 		target = c.a
-		cmd_shift(c)
+		e.cmd_shift(c)
 
-		if !valid_box(target) {
-			wout(c.who, "%s is not a valid entity.", box_code(target))
+		if !e.valid_box(target) {
+			wout(c.who, "%s is not a valid entity.", e.box_code(target))
 			return FALSE
 		}
 
-		if !may_name(c.who, target) {
-			wout(c.who, "You do not control %s.", box_code(target))
+		if !e.may_name(c.who, target) {
+			wout(c.who, "You do not control %s.", e.box_code(target))
 			return FALSE
 		}
 	}
@@ -341,7 +333,7 @@ func v_banner(c *command) int {
 		return FALSE
 	}
 
-	set_banner(target, newBanner)
+	e.set_banner(target, newBanner)
 
 	if newBanner != "" {
 		out(c.who, "Banner set.")
@@ -355,18 +347,18 @@ func v_banner(c *command) int {
 // v_public executes the PUBLIC command.
 // Makes the player's turn public and awards 100 gold.
 // Ported from src/c2.c lines 541-561.
-func v_public(c *command) int {
-	pl := player(c.who)
+func (e *Engine) v_public(c *command) int {
+	pl := e.player(c.who)
 
-	if player_public_turn(pl) != 0 {
+	if e.player_public_turn(pl) != 0 {
 		wout(c.who, "Already a public turn.")
 		return FALSE
 	}
 
-	p := p_player(pl)
+	p := e.p_player(pl)
 	p.public_turn = 1
 
-	gen_item(pl, item_gold, 100)
+	e.gen_item(pl, item_gold, 100)
 
 	wout(c.who, "Received 100 CLAIM gold.")
 
@@ -376,10 +368,10 @@ func v_public(c *command) int {
 // v_emote executes the EMOTE command.
 // Sends a formatted message to a target.
 // Ported from src/c1.c lines 1682-1696.
-func v_emote(c *command) int {
+func (e *Engine) v_emote(c *command) int {
 	target := c.a
 
-	if cmd_numargs_full(c) < 2 {
+	if e.cmd_numargs_full(c) < 2 {
 		wout(c.who, "Usage: EMOTE <target> <message>")
 		return FALSE
 	}
@@ -394,6 +386,6 @@ func v_emote(c *command) int {
 // v_stop executes the STOP command.
 // A no-op command that always succeeds.
 // Ported from src/c2.c lines 638-643.
-func v_stop(c *command) int {
+func (e *Engine) v_stop(c *command) int {
 	return TRUE
 }
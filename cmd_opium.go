@@ -22,10 +22,10 @@ package taygete
 // v_improve_opium starts opium improvement.
 // Must be in a poppy field. Training takes 7 days (see use.c skill table).
 // Ported from src/c2.c lines 565-576.
-func v_improve_opium(c *command) int {
-	where := subloc(c.who)
+func (e *Engine) v_improve_opium(c *command) int {
+	where := e.subloc(c.who)
 
-	if subkind(where) != sub_poppy_field {
+	if e.subkind(where) != sub_poppy_field {
 		wout(c.who, "Opium is produced only in poppy fields.")
 		return FALSE
 	}
@@ -36,22 +36,22 @@ func v_improve_opium(c *command) int {
 // d_improve_opium executes opium improvement, doubling production for one turn.
 // Sets the opium_double flag on the poppy field.
 // Ported from src/c2.c lines 580-593.
-func d_improve_opium(c *command) int {
-	where := subloc(c.who)
+func (e *Engine) d_improve_opium(c *command) int {
+	where := e.subloc(c.who)
 
-	if subkind(where) != sub_poppy_field {
+	if e.subkind(where) != sub_poppy_field {
 		wout(c.who, "Not in a poppy field anymore.")
 		return FALSE
 	}
 
-	p_misc(where).opium_double = TRUE
+	e.p_misc(where).opium_double = TRUE
 
 	return TRUE
 }
 
 // v_die is the suicide command. Calls kill_char to handle death.
 // Ported from src/c2.c lines 597-602.
-func v_die(c *command) int {
-	kill_char(c.who, MATES)
+func (e *Engine) v_die(c *command) int {
+	e.kill_char(c.who, MATES)
 	return TRUE
 }
@@ -57,7 +57,7 @@ func setupOpiumTest() {
 	}
 
 	for i := 0; i < MAX_BOXES; i++ {
-		teg.globals.bx[i] = nil
+		teg.deleteBox(i)
 	}
 	for i := range teg.globals.box_head {
 		teg.globals.box_head[i] = 0
@@ -72,14 +72,14 @@ func TestVImproveOpiumInPoppyField(t *testing.T) {
 	setupOpiumTest()
 
 	poppyField := 60001
-	alloc_box(poppyField, T_loc, sub_poppy_field)
+	teg.alloc_box(poppyField, T_loc, sub_poppy_field)
 
 	who := 1001
-	alloc_box(who, T_char, 0)
-	set_where(who, poppyField)
+	teg.alloc_box(who, T_char, 0)
+	teg.set_where(who, poppyField)
 
 	c := &command{who: who}
-	result := v_improve_opium(c)
+	result := teg.v_improve_opium(c)
 
 	if result != TRUE {
 		t.Errorf("v_improve_opium = %d, want TRUE (in poppy field)", result)
@@ -91,14 +91,14 @@ func TestVImproveOpiumNotInPoppyField(t *testing.T) {
 	setupOpiumTest()
 
 	province := 10000
-	alloc_box(province, T_loc, sub_plain)
+	teg.alloc_box(province, T_loc, sub_plain)
 
 	who := 1001
-	alloc_box(who, T_char, 0)
-	set_where(who, province)
+	teg.alloc_box(who, T_char, 0)
+	teg.set_where(who, province)
 
 	c := &command{who: who}
-	result := v_improve_opium(c)
+	result := teg.v_improve_opium(c)
 
 	if result != FALSE {
 		t.Errorf("v_improve_opium = %d, want FALSE (not in poppy field)", result)
@@ -110,24 +110,24 @@ func TestDImproveOpiumSetsFlag(t *testing.T) {
 	setupOpiumTest()
 
 	poppyField := 60001
-	alloc_box(poppyField, T_loc, sub_poppy_field)
+	teg.alloc_box(poppyField, T_loc, sub_poppy_field)
 
 	who := 1001
-	alloc_box(who, T_char, 0)
-	set_where(who, poppyField)
+	teg.alloc_box(who, T_char, 0)
+	teg.set_where(who, poppyField)
 
 	// Clear any previous flag
-	p_misc(poppyField).opium_double = FALSE
+	teg.p_misc(poppyField).opium_double = FALSE
 
 	c := &command{who: who}
-	result := d_improve_opium(c)
+	result := teg.d_improve_opium(c)
 
 	if result != TRUE {
 		t.Errorf("d_improve_opium = %d, want TRUE", result)
 	}
 
-	if p_misc(poppyField).opium_double != TRUE {
-		t.Errorf("opium_double = %d, want TRUE", p_misc(poppyField).opium_double)
+	if teg.p_misc(poppyField).opium_double != TRUE {
+		t.Errorf("opium_double = %d, want TRUE", teg.p_misc(poppyField).opium_double)
 	}
 }
 
@@ -136,14 +136,14 @@ func TestDImproveOpiumNotInPoppyField(t *testing.T) {
 	setupOpiumTest()
 
 	province := 10000
-	alloc_box(province, T_loc, sub_plain)
+	teg.alloc_box(province, T_loc, sub_plain)
 
 	who := 1001
-	alloc_box(who, T_char, 0)
-	set_where(who, province)
+	teg.alloc_box(who, T_char, 0)
+	teg.set_where(who, province)
 
 	c := &command{who: who}
-	result := d_improve_opium(c)
+	result := teg.d_improve_opium(c)
 
 	if result != FALSE {
 		t.Errorf("d_improve_opium = %d, want FALSE (not in poppy field anymore)", result)
@@ -158,24 +158,24 @@ func TestVDieTriggersDeath(t *testing.T) {
 	teg.globals.sysclock = olytime{day: 5, turn: 10}
 
 	loc := 10000
-	alloc_box(loc, T_loc, sub_plain)
+	teg.alloc_box(loc, T_loc, sub_plain)
 
 	who := 1001
-	alloc_box(who, T_char, 0)
-	set_where(who, loc)
-	p_char(who).health = 100
+	teg.alloc_box(who, T_char, 0)
+	teg.set_where(who, loc)
+	teg.p_char(who).health = 100
 
 	c := &command{who: who}
-	result := v_die(c)
+	result := teg.v_die(c)
 
 	if result != TRUE {
 		t.Errorf("v_die = %d, want TRUE", result)
 	}
 
 	// After death, the character's death_time should be set to sysclock
-	if p_char(who).death_time.day != 5 || p_char(who).death_time.turn != 10 {
+	if teg.p_char(who).death_time.day != 5 || teg.p_char(who).death_time.turn != 10 {
 		t.Errorf("v_die did not trigger death - death_time = {%d, %d}, want {5, 10}",
-			p_char(who).death_time.day, p_char(who).death_time.turn)
+			teg.p_char(who).death_time.day, teg.p_char(who).death_time.turn)
 	}
 }
 
@@ -184,12 +184,12 @@ func TestVDieWithSurviveFatal(t *testing.T) {
 	setupOpiumTest()
 
 	loc := 10000
-	alloc_box(loc, T_loc, sub_plain)
+	teg.alloc_box(loc, T_loc, sub_plain)
 
 	who := 1001
-	alloc_box(who, T_char, 0)
-	set_where(who, loc)
-	p_char(who).health = 100
+	teg.alloc_box(who, T_char, 0)
+	teg.set_where(who, loc)
+	teg.p_char(who).health = 100
 
 	// Give the character survive_fatal skill
 	teg.globals.charSkills[who] = []*skill_ent{
@@ -197,24 +197,24 @@ func TestVDieWithSurviveFatal(t *testing.T) {
 	}
 
 	c := &command{who: who}
-	result := v_die(c)
+	result := teg.v_die(c)
 
 	if result != TRUE {
 		t.Errorf("v_die = %d, want TRUE", result)
 	}
 
 	// With survive_fatal, the character should still be alive
-	if kind(who) != T_char {
-		t.Errorf("Character with survive_fatal should still be T_char, got kind=%d", kind(who))
+	if teg.kind(who) != T_char {
+		t.Errorf("Character with survive_fatal should still be T_char, got kind=%d", teg.kind(who))
 	}
 
 	// Health should be restored to 100
-	if p_char(who).health != 100 {
-		t.Errorf("Health after survive_fatal = %d, want 100", p_char(who).health)
+	if teg.p_char(who).health != 100 {
+		t.Errorf("Health after survive_fatal = %d, want 100", teg.p_char(who).health)
 	}
 
 	// The skill should be forgotten
-	if has_skill_check(who, sk_survive_fatal) {
+	if teg.has_skill_check(who, sk_survive_fatal) {
 		t.Errorf("survive_fatal skill should be forgotten after use")
 	}
 }
@@ -22,23 +22,23 @@ package taygete
 // v_archery starts archery training.
 // Training takes 7 days (see use.c skill table).
 // Ported from src/c2.c lines 647-651.
-func v_archery(c *command) int {
+func (e *Engine) v_archery(c *command) int {
 	return TRUE
 }
 
 // d_archery executes archery training, raising the missile rating.
 // 5% chance of +10, otherwise +3-5 if missile < 100, else +1-3.
 // Ported from src/c2.c lines 655-676.
-func d_archery(c *command) int {
-	p := p_char(c.who)
+func (e *Engine) d_archery(c *command) int {
+	p := e.p_char(c.who)
 
 	var amount int
-	if rnd(1, 100) <= 5 {
+	if e.rndFrom(streamSkills, 1, 100) <= 5 {
 		amount = 10
 	} else if p.missile < 100 {
-		amount = rnd(3, 5)
+		amount = e.rndFrom(streamSkills, 3, 5)
 	} else {
-		amount = rnd(1, 3)
+		amount = e.rndFrom(streamSkills, 1, 3)
 	}
 
 	p.missile += short(amount)
@@ -50,23 +50,23 @@ func d_archery(c *command) int {
 // v_defense starts defense training.
 // Training takes 7 days (see use.c skill table).
 // Ported from src/c2.c lines 680-684.
-func v_defense(c *command) int {
+func (e *Engine) v_defense(c *command) int {
 	return TRUE
 }
 
 // d_defense executes defense training, raising the defense rating.
 // 5% chance of +10, otherwise +3-5 if defense < 100, else +1-3.
 // Ported from src/c2.c lines 688-706.
-func d_defense(c *command) int {
-	p := p_char(c.who)
+func (e *Engine) d_defense(c *command) int {
+	p := e.p_char(c.who)
 
 	var amount int
-	if rnd(1, 100) <= 5 {
+	if e.rndFrom(streamSkills, 1, 100) <= 5 {
 		amount = 10
 	} else if p.defense < 100 {
-		amount = rnd(3, 5)
+		amount = e.rndFrom(streamSkills, 3, 5)
 	} else {
-		amount = rnd(1, 3)
+		amount = e.rndFrom(streamSkills, 1, 3)
 	}
 
 	p.defense += short(amount)
@@ -78,23 +78,23 @@ func d_defense(c *command) int {
 // v_swordplay starts swordplay training.
 // Training takes 7 days (see use.c skill table).
 // Ported from src/c2.c lines 710-714.
-func v_swordplay(c *command) int {
+func (e *Engine) v_swordplay(c *command) int {
 	return TRUE
 }
 
 // d_swordplay executes swordplay training, raising the attack rating.
 // 5% chance of +10, otherwise +3-5 if attack < 100, else +1-3.
 // Ported from src/c2.c lines 718-736.
-func d_swordplay(c *command) int {
-	p := p_char(c.who)
+func (e *Engine) d_swordplay(c *command) int {
+	p := e.p_char(c.who)
 
 	var amount int
-	if rnd(1, 100) <= 5 {
+	if e.rndFrom(streamSkills, 1, 100) <= 5 {
 		amount = 10
 	} else if p.attack < 100 {
-		amount = rnd(3, 5)
+		amount = e.rndFrom(streamSkills, 3, 5)
 	} else {
-		amount = rnd(1, 3)
+		amount = e.rndFrom(streamSkills, 1, 3)
 	}
 
 	p.attack += short(amount)
@@ -107,15 +107,15 @@ func d_swordplay(c *command) int {
 // FIGHT 0 - troops fight to the death (break_point = 0)
 // FIGHT 1 - troops break at 50% (break_point = 50)
 // Ported from src/c2.c lines 801-818.
-func v_fight_to_death(c *command) int {
+func (e *Engine) v_fight_to_death(c *command) int {
 	flag := c.a
 
 	if flag != 0 {
-		p_char(c.who).break_point = 50
-		wout(c.who, "Troops led by %s will break at 50%%.", box_name(c.who))
+		e.p_char(c.who).break_point = 50
+		wout(c.who, "Troops led by %s will break at 50%%.", e.box_name(c.who))
 	} else {
-		p_char(c.who).break_point = 0
-		wout(c.who, "Troops led by %s will fight to the death.", box_name(c.who))
+		e.p_char(c.who).break_point = 0
+		wout(c.who, "Troops led by %s will fight to the death.", e.box_name(c.who))
 	}
 
 	return TRUE
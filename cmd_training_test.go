@@ -51,7 +51,7 @@ func setupTrainingTest() {
 	}
 
 	for i := 0; i < MAX_BOXES; i++ {
-		teg.globals.bx[i] = nil
+		teg.deleteBox(i)
 	}
 	for i := range teg.globals.box_head {
 		teg.globals.box_head[i] = 0
@@ -63,7 +63,7 @@ func setupTrainingTest() {
 
 func TestVArchery(t *testing.T) {
 	c := &command{who: 1001}
-	result := v_archery(c)
+	result := teg.v_archery(c)
 	if result != TRUE {
 		t.Errorf("v_archery() = %d, want %d", result, TRUE)
 	}
@@ -73,24 +73,24 @@ func TestDArchery(t *testing.T) {
 	setupTrainingTest()
 
 	charID := 1001
-	alloc_box(charID, T_char, 0)
-	p_char(charID).missile = 50
+	teg.alloc_box(charID, T_char, 0)
+	teg.p_char(charID).missile = 50
 
 	c := &command{who: charID}
 
-	initialMissile := p_char(charID).missile
-	result := d_archery(c)
+	initialMissile := teg.p_char(charID).missile
+	result := teg.d_archery(c)
 
 	if result != TRUE {
 		t.Errorf("d_archery() = %d, want %d", result, TRUE)
 	}
 
-	if p_char(charID).missile <= initialMissile {
+	if teg.p_char(charID).missile <= initialMissile {
 		t.Errorf("d_archery() did not increase missile rating: was %d, now %d",
-			initialMissile, p_char(charID).missile)
+			initialMissile, teg.p_char(charID).missile)
 	}
 
-	increase := p_char(charID).missile - initialMissile
+	increase := teg.p_char(charID).missile - initialMissile
 	if increase < 1 || increase > 10 {
 		t.Errorf("d_archery() missile increase %d out of expected range [1,10]", increase)
 	}
@@ -100,25 +100,25 @@ func TestDArcheryHighSkill(t *testing.T) {
 	setupTrainingTest()
 
 	charID := 1002
-	alloc_box(charID, T_char, 0)
-	p_char(charID).missile = 120
+	teg.alloc_box(charID, T_char, 0)
+	teg.p_char(charID).missile = 120
 
 	c := &command{who: charID}
 
-	result := d_archery(c)
+	result := teg.d_archery(c)
 
 	if result != TRUE {
 		t.Errorf("d_archery() = %d, want %d", result, TRUE)
 	}
 
-	if p_char(charID).missile <= 120 {
+	if teg.p_char(charID).missile <= 120 {
 		t.Errorf("d_archery() should increase missile even at high skill")
 	}
 }
 
 func TestVDefense(t *testing.T) {
 	c := &command{who: 1001}
-	result := v_defense(c)
+	result := teg.v_defense(c)
 	if result != TRUE {
 		t.Errorf("v_defense() = %d, want %d", result, TRUE)
 	}
@@ -128,24 +128,24 @@ func TestDDefense(t *testing.T) {
 	setupTrainingTest()
 
 	charID := 1003
-	alloc_box(charID, T_char, 0)
-	p_char(charID).defense = 40
+	teg.alloc_box(charID, T_char, 0)
+	teg.p_char(charID).defense = 40
 
 	c := &command{who: charID}
 
-	initialDefense := p_char(charID).defense
-	result := d_defense(c)
+	initialDefense := teg.p_char(charID).defense
+	result := teg.d_defense(c)
 
 	if result != TRUE {
 		t.Errorf("d_defense() = %d, want %d", result, TRUE)
 	}
 
-	if p_char(charID).defense <= initialDefense {
+	if teg.p_char(charID).defense <= initialDefense {
 		t.Errorf("d_defense() did not increase defense rating: was %d, now %d",
-			initialDefense, p_char(charID).defense)
+			initialDefense, teg.p_char(charID).defense)
 	}
 
-	increase := p_char(charID).defense - initialDefense
+	increase := teg.p_char(charID).defense - initialDefense
 	if increase < 1 || increase > 10 {
 		t.Errorf("d_defense() defense increase %d out of expected range [1,10]", increase)
 	}
@@ -155,25 +155,25 @@ func TestDDefenseHighSkill(t *testing.T) {
 	setupTrainingTest()
 
 	charID := 1004
-	alloc_box(charID, T_char, 0)
-	p_char(charID).defense = 150
+	teg.alloc_box(charID, T_char, 0)
+	teg.p_char(charID).defense = 150
 
 	c := &command{who: charID}
 
-	result := d_defense(c)
+	result := teg.d_defense(c)
 
 	if result != TRUE {
 		t.Errorf("d_defense() = %d, want %d", result, TRUE)
 	}
 
-	if p_char(charID).defense <= 150 {
+	if teg.p_char(charID).defense <= 150 {
 		t.Errorf("d_defense() should increase defense even at high skill")
 	}
 }
 
 func TestVSwordplay(t *testing.T) {
 	c := &command{who: 1001}
-	result := v_swordplay(c)
+	result := teg.v_swordplay(c)
 	if result != TRUE {
 		t.Errorf("v_swordplay() = %d, want %d", result, TRUE)
 	}
@@ -183,24 +183,24 @@ func TestDSwordplay(t *testing.T) {
 	setupTrainingTest()
 
 	charID := 1005
-	alloc_box(charID, T_char, 0)
-	p_char(charID).attack = 60
+	teg.alloc_box(charID, T_char, 0)
+	teg.p_char(charID).attack = 60
 
 	c := &command{who: charID}
 
-	initialAttack := p_char(charID).attack
-	result := d_swordplay(c)
+	initialAttack := teg.p_char(charID).attack
+	result := teg.d_swordplay(c)
 
 	if result != TRUE {
 		t.Errorf("d_swordplay() = %d, want %d", result, TRUE)
 	}
 
-	if p_char(charID).attack <= initialAttack {
+	if teg.p_char(charID).attack <= initialAttack {
 		t.Errorf("d_swordplay() did not increase attack rating: was %d, now %d",
-			initialAttack, p_char(charID).attack)
+			initialAttack, teg.p_char(charID).attack)
 	}
 
-	increase := p_char(charID).attack - initialAttack
+	increase := teg.p_char(charID).attack - initialAttack
 	if increase < 1 || increase > 10 {
 		t.Errorf("d_swordplay() attack increase %d out of expected range [1,10]", increase)
 	}
@@ -210,18 +210,18 @@ func TestDSwordplayHighSkill(t *testing.T) {
 	setupTrainingTest()
 
 	charID := 1006
-	alloc_box(charID, T_char, 0)
-	p_char(charID).attack = 200
+	teg.alloc_box(charID, T_char, 0)
+	teg.p_char(charID).attack = 200
 
 	c := &command{who: charID}
 
-	result := d_swordplay(c)
+	result := teg.d_swordplay(c)
 
 	if result != TRUE {
 		t.Errorf("d_swordplay() = %d, want %d", result, TRUE)
 	}
 
-	if p_char(charID).attack <= 200 {
+	if teg.p_char(charID).attack <= 200 {
 		t.Errorf("d_swordplay() should increase attack even at high skill")
 	}
 }
@@ -230,20 +230,20 @@ func TestVFightToDeathDisabled(t *testing.T) {
 	setupTrainingTest()
 
 	charID := 1007
-	alloc_box(charID, T_char, 0)
-	p_char(charID).break_point = 50
+	teg.alloc_box(charID, T_char, 0)
+	teg.p_char(charID).break_point = 50
 
 	c := &command{who: charID, a: 0}
 
-	result := v_fight_to_death(c)
+	result := teg.v_fight_to_death(c)
 
 	if result != TRUE {
 		t.Errorf("v_fight_to_death() = %d, want %d", result, TRUE)
 	}
 
-	if p_char(charID).break_point != 0 {
+	if teg.p_char(charID).break_point != 0 {
 		t.Errorf("v_fight_to_death(0) break_point = %d, want 0",
-			p_char(charID).break_point)
+			teg.p_char(charID).break_point)
 	}
 }
 
@@ -251,20 +251,20 @@ func TestVFightToDeathEnabled(t *testing.T) {
 	setupTrainingTest()
 
 	charID := 1008
-	alloc_box(charID, T_char, 0)
-	p_char(charID).break_point = 0
+	teg.alloc_box(charID, T_char, 0)
+	teg.p_char(charID).break_point = 0
 
 	c := &command{who: charID, a: 1}
 
-	result := v_fight_to_death(c)
+	result := teg.v_fight_to_death(c)
 
 	if result != TRUE {
 		t.Errorf("v_fight_to_death() = %d, want %d", result, TRUE)
 	}
 
-	if p_char(charID).break_point != 50 {
+	if teg.p_char(charID).break_point != 50 {
 		t.Errorf("v_fight_to_death(1) break_point = %d, want 50",
-			p_char(charID).break_point)
+			teg.p_char(charID).break_point)
 	}
 }
 
@@ -272,26 +272,26 @@ func TestTrainingStatIncreasesAreAdditive(t *testing.T) {
 	setupTrainingTest()
 
 	charID := 1009
-	alloc_box(charID, T_char, 0)
-	p_char(charID).attack = 10
-	p_char(charID).defense = 10
-	p_char(charID).missile = 10
+	teg.alloc_box(charID, T_char, 0)
+	teg.p_char(charID).attack = 10
+	teg.p_char(charID).defense = 10
+	teg.p_char(charID).missile = 10
 
 	c := &command{who: charID}
 
 	for i := 0; i < 5; i++ {
-		d_archery(c)
-		d_defense(c)
-		d_swordplay(c)
+		teg.d_archery(c)
+		teg.d_defense(c)
+		teg.d_swordplay(c)
 	}
 
-	if p_char(charID).missile <= 10+5 {
-		t.Errorf("Multiple archery trainings should accumulate: missile = %d", p_char(charID).missile)
+	if teg.p_char(charID).missile <= 10+5 {
+		t.Errorf("Multiple archery trainings should accumulate: missile = %d", teg.p_char(charID).missile)
 	}
-	if p_char(charID).defense <= 10+5 {
-		t.Errorf("Multiple defense trainings should accumulate: defense = %d", p_char(charID).defense)
+	if teg.p_char(charID).defense <= 10+5 {
+		t.Errorf("Multiple defense trainings should accumulate: defense = %d", teg.p_char(charID).defense)
 	}
-	if p_char(charID).attack <= 10+5 {
-		t.Errorf("Multiple swordplay trainings should accumulate: attack = %d", p_char(charID).attack)
+	if teg.p_char(charID).attack <= 10+5 {
+		t.Errorf("Multiple swordplay trainings should accumulate: attack = %d", teg.p_char(charID).attack)
 	}
 }
@@ -23,12 +23,12 @@ package taygete
 // Usage: ACCEPT <from_who> <item> <qty>
 // All parameters can be 0 for wildcards.
 // Ported from src/c1.c lines 397-417.
-func v_accept(c *command) int {
+func (e *Engine) v_accept(c *command) int {
 	from_who := c.a
 	item := c.b
 	qty := c.c
 
-	p := p_char(c.who)
+	p := e.p_char(c.who)
 
 	newEnt := &accept_ent{
 		item:     item,
@@ -44,8 +44,8 @@ func v_accept(c *command) int {
 // will_accept_sup checks accept rules for a specific who/player.
 // Returns true if the accept list contains a matching rule.
 // Ported from src/c1.c lines 420-450.
-func will_accept_sup(who, item, from, qty int) bool {
-	p := rp_char(who)
+func (e *Engine) will_accept_sup(who, item, from, qty int) bool {
+	p := e.rp_char(who)
 	if p == nil {
 		return false
 	}
@@ -72,32 +72,32 @@ func will_accept_sup(who, item, from, qty int) bool {
 // Garrisons accept from rulers.
 // Otherwise, checks ACCEPT rules on character and player.
 // Ported from src/c1.c lines 453-484.
-func will_accept(who, item, from, qty int) bool {
+func (e *Engine) will_accept(who, item, from, qty int) bool {
 	if item == item_gold {
 		return true
 	}
 
-	if player(who) == player(from) {
+	if e.player(who) == e.player(from) {
 		return true
 	}
 
-	if subkind(who) == sub_garrison {
-		if may_rule_here(from, who) {
+	if e.subkind(who) == sub_garrison {
+		if e.may_rule_here(from, who) {
 			return true
 		}
-		wout(from, "%s is not under your control.", box_name(who))
+		wout(from, "%s is not under your control.", e.box_name(who))
 		return false
 	}
 
-	if will_accept_sup(who, item, from, qty) ||
-		will_accept_sup(player(who), item, from, qty) ||
-		will_accept_sup(who, item, player(from), qty) ||
-		will_accept_sup(player(who), item, player(from), qty) {
+	if e.will_accept_sup(who, item, from, qty) ||
+		e.will_accept_sup(e.player(who), item, from, qty) ||
+		e.will_accept_sup(who, item, e.player(from), qty) ||
+		e.will_accept_sup(e.player(who), item, e.player(from), qty) {
 		return true
 	}
 
-	wout(who, "Refused %s from %s.", just_name_qty(item, qty), box_name(from))
-	wout(from, "Refused by %s.", just_name(who))
+	wout(who, "Refused %s from %s.", e.just_name_qty(item, qty), e.box_name(from))
+	wout(from, "Refused by %s.", e.just_name(who))
 
 	return false
 }
@@ -108,20 +108,20 @@ func will_accept(who, item, from, qty int) bool {
 // Usage: GIVE <who> <what> [qty] [have-left]
 // Handles argument correction if target and item are swapped.
 // Ported from src/c1.c lines 526-628.
-func v_give(c *command) int {
+func (e *Engine) v_give(c *command) int {
 	target := c.a
 	item := c.b
 	qty := c.c
 	have_left := c.d
 
-	if numargs(c) >= 2 &&
-		(kind(target) == T_item || has_prisoner(c.who, target)) &&
-		(kind(item) == T_char && subloc(c.who) == subloc(item) && !has_prisoner(c.who, item)) {
+	if e.numargs(c) >= 2 &&
+		(e.kind(target) == T_item || e.has_prisoner(c.who, target)) &&
+		(e.kind(item) == T_char && e.subloc(c.who) == e.subloc(item) && !e.has_prisoner(c.who, item)) {
 		c.a, c.b = c.b, c.a
 		target = c.a
 		item = c.b
 
-		switch numargs(c) {
+		switch e.numargs(c) {
 		case 2:
 			wout(c.who, "(assuming you meant 'give %d %d')", target, item)
 		case 3:
@@ -131,59 +131,59 @@ func v_give(c *command) int {
 		}
 	}
 
-	if !check_char_here(c.who, target) {
+	if !e.check_char_here(c.who, target) {
 		return FALSE
 	}
-	if !check_char_gone(c.who, target) {
+	if !e.check_char_gone(c.who, target) {
 		return FALSE
 	}
 
-	if is_prisoner(target) {
+	if e.is_prisoner(target) {
 		wout(c.who, "Prisoners may not be given anything.")
 		return FALSE
 	}
 
-	if loyal_kind(target) == LOY_summon {
+	if e.loyal_kind(target) == LOY_summon {
 		wout(c.who, "Summoned entities may not be given anything.")
 		return FALSE
 	}
 
-	if has_prisoner(c.who, item) {
-		if !will_accept(target, item, c.who, 1) {
+	if e.has_prisoner(c.who, item) {
+		if !e.will_accept(target, item, c.who, 1) {
 			return FALSE
 		}
-		if give_prisoner(c.who, target, item) {
+		if e.give_prisoner(c.who, target, item) {
 			return TRUE
 		}
 		return FALSE
 	}
 
-	if kind(item) != T_item {
-		wout(c.who, "%s is not an item or a prisoner.", box_code(item))
+	if e.kind(item) != T_item {
+		wout(c.who, "%s is not an item or a prisoner.", e.box_code(item))
 		return FALSE
 	}
 
-	if has_item(c.who, item) < 1 {
-		wout(c.who, "%s does not have any %s.", box_name(c.who), box_code(item))
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s does not have any %s.", e.box_name(c.who), e.box_code(item))
 		return FALSE
 	}
 
-	qty = how_many(c.who, c.who, item, qty, have_left)
+	qty = e.how_many(c.who, c.who, item, qty, have_left)
 	if qty <= 0 {
 		return FALSE
 	}
 
-	if !will_accept(target, item, c.who, qty) {
+	if !e.will_accept(target, item, c.who, qty) {
 		return FALSE
 	}
 
-	ret := move_item(c.who, target, item, qty)
+	ret := e.move_item(c.who, target, item, qty)
 	if !ret {
 		panic("v_give: move_item failed")
 	}
 
-	wout(c.who, "Gave %s to %s.", just_name_qty(item, qty), box_name(target))
-	wout(target, "Received %s from %s.", box_name_qty(item, qty), box_name(c.who))
+	wout(c.who, "Gave %s to %s.", e.just_name_qty(item, qty), e.box_name(target))
+	wout(target, "Received %s from %s.", e.box_name_qty(item, qty), e.box_name(c.who))
 
 	return TRUE
 }
@@ -192,7 +192,7 @@ func v_give(c *command) int {
 // Usage: PAY <who> <qty> [have-left]
 // This is a wrapper around v_give that rewrites the command.
 // Ported from src/c1.c lines 631-643.
-func v_pay(c *command) int {
+func (e *Engine) v_pay(c *command) int {
 	target := c.a
 	qty := c.b
 	have_left := c.c
@@ -202,29 +202,29 @@ func v_pay(c *command) int {
 	c.c = qty
 	c.d = have_left
 
-	return v_give(c)
+	return e.v_give(c)
 }
 
 // may_take checks if who may take items from target.
 // Returns true if target is a controlled garrison, a prisoner, or same faction.
 // Ported from src/c1.c lines 646-674.
-func may_take(who, target int) bool {
-	if !check_char_here(who, target) {
+func (e *Engine) may_take(who, target int) bool {
+	if !e.check_char_here(who, target) {
 		return false
 	}
-	if !check_char_gone(who, target) {
+	if !e.check_char_gone(who, target) {
 		return false
 	}
 
-	if subkind(target) == sub_garrison {
-		if may_rule_here(who, target) {
+	if e.subkind(target) == sub_garrison {
+		if e.may_rule_here(who, target) {
 			return true
 		}
-		wout(who, "%s is not under your control.", box_name(target))
+		wout(who, "%s is not under your control.", e.box_name(target))
 		return false
 	}
 
-	if !my_prisoner(who, target) && player(target) != player(who) {
+	if !e.my_prisoner(who, target) && e.player(target) != e.player(who) {
 		wout(who, "May only take items from other units in your faction.")
 		return false
 	}
@@ -235,40 +235,40 @@ func may_take(who, target int) bool {
 // v_get takes items from a target character.
 // Usage: GET <who> <what> [qty] [have-left]
 // Ported from src/c1.c lines 681-742.
-func v_get(c *command) int {
+func (e *Engine) v_get(c *command) int {
 	target := c.a
 	item := c.b
 	qty := c.c
 	have_left := c.d
 
-	if !may_take(c.who, target) {
+	if !e.may_take(c.who, target) {
 		return FALSE
 	}
 
-	if has_prisoner(target, item) {
-		if give_prisoner(target, c.who, item) {
+	if e.has_prisoner(target, item) {
+		if e.give_prisoner(target, c.who, item) {
 			return TRUE
 		}
 		return FALSE
 	}
 
-	if kind(item) != T_item {
-		wout(c.who, "%s is not an item or a prisoner.", box_code(item))
+	if e.kind(item) != T_item {
+		wout(c.who, "%s is not an item or a prisoner.", e.box_code(item))
 		return FALSE
 	}
 
-	if has_item(target, item) < 1 {
-		wout(c.who, "%s does not have any %s.", box_name(target), box_code(item))
+	if e.has_item(target, item) < 1 {
+		wout(c.who, "%s does not have any %s.", e.box_name(target), e.box_code(item))
 		return FALSE
 	}
 
-	qty = how_many(c.who, target, item, qty, have_left)
+	qty = e.how_many(c.who, target, item, qty, have_left)
 	if qty <= 0 {
 		return FALSE
 	}
 
-	if subkind(target) == sub_garrison && man_item(item) != 0 {
-		garr_men := count_man_items(target)
+	if e.subkind(target) == sub_garrison && e.man_item(item) != 0 {
+		garr_men := e.count_man_items(target)
 		garr_men -= qty
 		if garr_men < 10 {
 			wout(c.who, "Garrisons must be left with a minimum of ten men.")
@@ -276,13 +276,13 @@ func v_get(c *command) int {
 		}
 	}
 
-	ret := move_item(target, c.who, item, qty)
+	ret := e.move_item(target, c.who, item, qty)
 	if !ret {
 		panic("v_get: move_item failed")
 	}
 
-	wout(c.who, "Took %s from %s.", just_name_qty(item, qty), box_name(target))
-	wout(target, "%s took %s from us.", box_name(c.who), box_name_qty(item, qty))
+	wout(c.who, "Took %s from %s.", e.just_name_qty(item, qty), e.box_name(target))
+	wout(target, "%s took %s from us.", e.box_name(c.who), e.box_name_qty(item, qty))
 
 	if item == item_sailor || item == item_pirate {
 		check_captain_loses_sailors(qty, target, c.who)
@@ -296,59 +296,59 @@ func v_get(c *command) int {
 // Common mistake: CLAIM 500 is corrected to CLAIM 1 500 (claim gold).
 // Cannot be used in Cloudlands, Hades, or Faery.
 // Ported from src/c2.c lines 739-797.
-func v_claim(c *command) int {
+func (e *Engine) v_claim(c *command) int {
 	item := c.a
 	qty := c.b
 	have_left := c.c
-	pl := player(c.who)
+	pl := e.player(c.who)
 
-	if region(c.who) == cloud_region ||
-		region(c.who) == hades_region ||
-		region(c.who) == faery_region {
+	if e.region(c.who) == e.globals.cloudRegion ||
+		e.region(c.who) == e.globals.hadesRegion ||
+		e.region(c.who) == e.globals.faeryRegion {
 		wout(c.who, "CLAIM may not be used in the Cloudlands, Hades or Faery.")
 		return FALSE
 	}
 
-	if numargs(c) < 2 &&
-		(kind(item) != T_item || has_item(pl, item) < 1) &&
+	if e.numargs(c) < 2 &&
+		(e.kind(item) != T_item || e.has_item(pl, item) < 1) &&
 		qty == 0 {
 		wout(c.who, "(assuming you meant CLAIM %d %d)", item_gold, item)
 		qty = item
 		item = item_gold
 	}
 
-	if kind(item) != T_item {
-		wout(c.who, "%s is not an item.", box_code(item))
+	if e.kind(item) != T_item {
+		wout(c.who, "%s is not an item.", e.box_code(item))
 		return FALSE
 	}
 
-	if has_item(pl, item) < 1 {
-		wout(c.who, "No %s for you to claim.", box_code(item))
+	if e.has_item(pl, item) < 1 {
+		wout(c.who, "No %s for you to claim.", e.box_code(item))
 		return FALSE
 	}
 
-	qty = how_many(c.who, pl, item, qty, have_left)
+	qty = e.how_many(c.who, pl, item, qty, have_left)
 	if qty <= 0 {
 		return FALSE
 	}
 
-	ret := move_item(pl, c.who, item, qty)
+	ret := e.move_item(pl, c.who, item, qty)
 	if !ret {
 		panic("v_claim: move_item failed")
 	}
 
-	wout(c.who, "Claimed %s.", just_name_qty(item, qty))
+	wout(c.who, "Claimed %s.", e.just_name_qty(item, qty))
 	return TRUE
 }
 
 // count_man_items counts the number of man-type items held by who.
 // Ported from src/u.c lines 1039-1056.
-func count_man_items(who int) int {
+func (e *Engine) count_man_items(who int) int {
 	count := 0
-	inv := teg.globals.inventories[who]
-	for _, e := range inv {
-		if man_item(e.item) != 0 {
-			count += e.qty
+	inv := e.globals.inventories[who]
+	for _, it := range inv {
+		if e.man_item(it.item) != 0 {
+			count += it.qty
 		}
 	}
 	return count
@@ -357,16 +357,16 @@ func count_man_items(who int) int {
 // my_prisoner checks if pris is a prisoner of who.
 // Simply checks if pris is a prisoner and their location is who.
 // Ported from src/u.c lines 2336-2349.
-func my_prisoner(who, pris int) bool {
-	if kind(pris) != T_char {
+func (e *Engine) my_prisoner(who, pris int) bool {
+	if e.kind(pris) != T_char {
 		return false
 	}
 
-	if !is_prisoner(pris) {
+	if !e.is_prisoner(pris) {
 		return false
 	}
 
-	if loc(pris) != who {
+	if e.loc(pris) != who {
 		return false
 	}
 
@@ -50,7 +50,7 @@ func setupTransferTest() {
 	}
 
 	for i := 0; i < MAX_BOXES; i++ {
-		teg.globals.bx[i] = nil
+		teg.deleteBox(i)
 	}
 	for i := range teg.globals.box_head {
 		teg.globals.box_head[i] = 0
@@ -59,16 +59,16 @@ func setupTransferTest() {
 		teg.globals.sub_head[i] = 0
 	}
 
-	alloc_box(item_gold, T_item, 0)
-	alloc_box(item_peasant, T_item, 0)
-	alloc_box(item_soldier, T_item, 0)
-	alloc_box(item_sailor, T_item, 0)
-	alloc_box(item_pirate, T_item, 0)
+	teg.alloc_box(item_gold, T_item, 0)
+	teg.alloc_box(item_peasant, T_item, 0)
+	teg.alloc_box(item_soldier, T_item, 0)
+	teg.alloc_box(item_sailor, T_item, 0)
+	teg.alloc_box(item_pirate, T_item, 0)
 
-	p_item(item_peasant).is_man_item = 1
-	p_item(item_soldier).is_man_item = 1
-	p_item(item_sailor).is_man_item = 1
-	p_item(item_pirate).is_man_item = 1
+	teg.p_item(item_peasant).is_man_item = 1
+	teg.p_item(item_soldier).is_man_item = 1
+	teg.p_item(item_sailor).is_man_item = 1
+	teg.p_item(item_pirate).is_man_item = 1
 }
 
 func TestVAccept(t *testing.T) {
@@ -77,9 +77,9 @@ func TestVAccept(t *testing.T) {
 	charID := 1001
 	playerID := 2001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(playerID, T_player, sub_pl_regular)
-	p_char(charID).unit_lord = playerID
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.p_char(charID).unit_lord = playerID
 
 	c := &command{
 		who: charID,
@@ -88,12 +88,12 @@ func TestVAccept(t *testing.T) {
 		c:   100,       // up to 100
 	}
 
-	result := v_accept(c)
+	result := teg.v_accept(c)
 	if result != TRUE {
 		t.Errorf("v_accept returned %d, want %d", result, TRUE)
 	}
 
-	p := rp_char(charID)
+	p := teg.rp_char(charID)
 	if p == nil {
 		t.Fatal("rp_char returned nil")
 	}
@@ -117,22 +117,22 @@ func TestWillAcceptSup(t *testing.T) {
 	charID := 1001
 	playerID := 2001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(playerID, T_player, sub_pl_regular)
-	p_char(charID).unit_lord = playerID
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.p_char(charID).unit_lord = playerID
 
-	p := p_char(charID)
+	p := teg.p_char(charID)
 	p.accept = append(p.accept, &accept_ent{
 		item:     item_gold,
 		from_who: 0,
 		qty:      0,
 	})
 
-	if !will_accept_sup(charID, item_gold, 999, 50) {
+	if !teg.will_accept_sup(charID, item_gold, 999, 50) {
 		t.Error("expected will_accept_sup to return true for matching item")
 	}
 
-	if will_accept_sup(charID, item_peasant, 999, 50) {
+	if teg.will_accept_sup(charID, item_peasant, 999, 50) {
 		t.Error("expected will_accept_sup to return false for non-matching item")
 	}
 }
@@ -145,42 +145,42 @@ func TestWillAccept(t *testing.T) {
 	player1 := 2001
 	player2 := 2002
 
-	alloc_box(char1, T_char, 0)
-	alloc_box(char2, T_char, 0)
-	alloc_box(player1, T_player, sub_pl_regular)
-	alloc_box(player2, T_player, sub_pl_regular)
+	teg.alloc_box(char1, T_char, 0)
+	teg.alloc_box(char2, T_char, 0)
+	teg.alloc_box(player1, T_player, sub_pl_regular)
+	teg.alloc_box(player2, T_player, sub_pl_regular)
 
-	p_char(char1).unit_lord = player1
-	p_char(char2).unit_lord = player2
+	teg.p_char(char1).unit_lord = player1
+	teg.p_char(char2).unit_lord = player2
 
 	t.Run("gold is always accepted", func(t *testing.T) {
-		if !will_accept(char1, item_gold, char2, 50) {
+		if !teg.will_accept(char1, item_gold, char2, 50) {
 			t.Error("gold should always be accepted")
 		}
 	})
 
 	t.Run("same faction always accepted", func(t *testing.T) {
-		p_char(char2).unit_lord = player1
-		if !will_accept(char1, item_peasant, char2, 10) {
+		teg.p_char(char2).unit_lord = player1
+		if !teg.will_accept(char1, item_peasant, char2, 10) {
 			t.Error("same faction should always be accepted")
 		}
-		p_char(char2).unit_lord = player2 // reset
+		teg.p_char(char2).unit_lord = player2 // reset
 	})
 
 	t.Run("different faction without accept rule refused", func(t *testing.T) {
-		if will_accept(char1, item_peasant, char2, 10) {
+		if teg.will_accept(char1, item_peasant, char2, 10) {
 			t.Error("different faction without accept rule should be refused")
 		}
 	})
 
 	t.Run("with accept rule", func(t *testing.T) {
-		p := p_char(char1)
+		p := teg.p_char(char1)
 		p.accept = append(p.accept, &accept_ent{
 			item:     item_peasant,
 			from_who: char2,
 			qty:      0,
 		})
-		if !will_accept(char1, item_peasant, char2, 10) {
+		if !teg.will_accept(char1, item_peasant, char2, 10) {
 			t.Error("should accept with matching accept rule")
 		}
 	})
@@ -194,18 +194,18 @@ func TestVGive(t *testing.T) {
 	player1 := 2001
 	locID := 3001
 
-	alloc_box(char1, T_char, 0)
-	alloc_box(char2, T_char, 0)
-	alloc_box(player1, T_player, sub_pl_regular)
-	alloc_box(locID, T_loc, sub_plain)
+	teg.alloc_box(char1, T_char, 0)
+	teg.alloc_box(char2, T_char, 0)
+	teg.alloc_box(player1, T_player, sub_pl_regular)
+	teg.alloc_box(locID, T_loc, sub_plain)
 
-	p_char(char1).unit_lord = player1
-	p_char(char2).unit_lord = player1
-	p_loc_info(char1).where = locID
-	p_loc_info(char2).where = locID
-	p_loc_info(locID).here_list = []int{char1, char2}
+	teg.p_char(char1).unit_lord = player1
+	teg.p_char(char2).unit_lord = player1
+	teg.p_loc_info(char1).where = locID
+	teg.p_loc_info(char2).where = locID
+	teg.p_loc_info(locID).here_list = []int{char1, char2}
 
-	add_item(char1, item_gold, 100)
+	teg.add_item(char1, item_gold, 100)
 
 	c := &command{
 		who: char1,
@@ -215,16 +215,16 @@ func TestVGive(t *testing.T) {
 		d:   0,
 	}
 
-	result := v_give(c)
+	result := teg.v_give(c)
 	if result != TRUE {
 		t.Errorf("v_give returned %d, want %d", result, TRUE)
 	}
 
-	if has_item(char1, item_gold) != 50 {
-		t.Errorf("char1 should have 50 gold, has %d", has_item(char1, item_gold))
+	if teg.has_item(char1, item_gold) != 50 {
+		t.Errorf("char1 should have 50 gold, has %d", teg.has_item(char1, item_gold))
 	}
-	if has_item(char2, item_gold) != 50 {
-		t.Errorf("char2 should have 50 gold, has %d", has_item(char2, item_gold))
+	if teg.has_item(char2, item_gold) != 50 {
+		t.Errorf("char2 should have 50 gold, has %d", teg.has_item(char2, item_gold))
 	}
 }
 
@@ -237,19 +237,19 @@ func TestVGiveDifferentFaction(t *testing.T) {
 	player2 := 2002
 	locID := 3001
 
-	alloc_box(char1, T_char, 0)
-	alloc_box(char2, T_char, 0)
-	alloc_box(player1, T_player, sub_pl_regular)
-	alloc_box(player2, T_player, sub_pl_regular)
-	alloc_box(locID, T_loc, sub_plain)
+	teg.alloc_box(char1, T_char, 0)
+	teg.alloc_box(char2, T_char, 0)
+	teg.alloc_box(player1, T_player, sub_pl_regular)
+	teg.alloc_box(player2, T_player, sub_pl_regular)
+	teg.alloc_box(locID, T_loc, sub_plain)
 
-	p_char(char1).unit_lord = player1
-	p_char(char2).unit_lord = player2
-	p_loc_info(char1).where = locID
-	p_loc_info(char2).where = locID
-	p_loc_info(locID).here_list = []int{char1, char2}
+	teg.p_char(char1).unit_lord = player1
+	teg.p_char(char2).unit_lord = player2
+	teg.p_loc_info(char1).where = locID
+	teg.p_loc_info(char2).where = locID
+	teg.p_loc_info(locID).here_list = []int{char1, char2}
 
-	add_item(char1, item_gold, 100)
+	teg.add_item(char1, item_gold, 100)
 
 	c := &command{
 		who: char1,
@@ -260,36 +260,36 @@ func TestVGiveDifferentFaction(t *testing.T) {
 	}
 
 	t.Run("gold to different faction succeeds", func(t *testing.T) {
-		result := v_give(c)
+		result := teg.v_give(c)
 		if result != TRUE {
 			t.Errorf("v_give gold to different faction should succeed, got %d", result)
 		}
 	})
 
 	t.Run("non-gold to different faction without accept fails", func(t *testing.T) {
-		add_item(char1, item_peasant, 10)
+		teg.add_item(char1, item_peasant, 10)
 		c.b = item_peasant
 		c.c = 5
 
-		result := v_give(c)
+		result := teg.v_give(c)
 		if result != FALSE {
 			t.Errorf("v_give non-gold to different faction without accept should fail, got %d", result)
 		}
 	})
 
 	t.Run("non-gold with accept rule succeeds", func(t *testing.T) {
-		p := p_char(char2)
+		p := teg.p_char(char2)
 		p.accept = append(p.accept, &accept_ent{
 			item:     item_peasant,
 			from_who: 0,
 			qty:      0,
 		})
 
-		add_item(char1, item_peasant, 10) // ensure we have some
+		teg.add_item(char1, item_peasant, 10) // ensure we have some
 		c.b = item_peasant
 		c.c = 5
 
-		result := v_give(c)
+		result := teg.v_give(c)
 		if result != TRUE {
 			t.Errorf("v_give with accept rule should succeed, got %d", result)
 		}
@@ -304,18 +304,18 @@ func TestVPay(t *testing.T) {
 	player1 := 2001
 	locID := 3001
 
-	alloc_box(char1, T_char, 0)
-	alloc_box(char2, T_char, 0)
-	alloc_box(player1, T_player, sub_pl_regular)
-	alloc_box(locID, T_loc, sub_plain)
+	teg.alloc_box(char1, T_char, 0)
+	teg.alloc_box(char2, T_char, 0)
+	teg.alloc_box(player1, T_player, sub_pl_regular)
+	teg.alloc_box(locID, T_loc, sub_plain)
 
-	p_char(char1).unit_lord = player1
-	p_char(char2).unit_lord = player1
-	p_loc_info(char1).where = locID
-	p_loc_info(char2).where = locID
-	p_loc_info(locID).here_list = []int{char1, char2}
+	teg.p_char(char1).unit_lord = player1
+	teg.p_char(char2).unit_lord = player1
+	teg.p_loc_info(char1).where = locID
+	teg.p_loc_info(char2).where = locID
+	teg.p_loc_info(locID).here_list = []int{char1, char2}
 
-	add_item(char1, item_gold, 100)
+	teg.add_item(char1, item_gold, 100)
 
 	c := &command{
 		who: char1,
@@ -324,16 +324,16 @@ func TestVPay(t *testing.T) {
 		c:   0,
 	}
 
-	result := v_pay(c)
+	result := teg.v_pay(c)
 	if result != TRUE {
 		t.Errorf("v_pay returned %d, want %d", result, TRUE)
 	}
 
-	if has_item(char1, item_gold) != 70 {
-		t.Errorf("char1 should have 70 gold, has %d", has_item(char1, item_gold))
+	if teg.has_item(char1, item_gold) != 70 {
+		t.Errorf("char1 should have 70 gold, has %d", teg.has_item(char1, item_gold))
 	}
-	if has_item(char2, item_gold) != 30 {
-		t.Errorf("char2 should have 30 gold, has %d", has_item(char2, item_gold))
+	if teg.has_item(char2, item_gold) != 30 {
+		t.Errorf("char2 should have 30 gold, has %d", teg.has_item(char2, item_gold))
 	}
 }
 
@@ -347,29 +347,29 @@ func TestMayTake(t *testing.T) {
 	player2 := 2002
 	locID := 3001
 
-	alloc_box(char1, T_char, 0)
-	alloc_box(char2, T_char, 0)
-	alloc_box(char3, T_char, 0)
-	alloc_box(player1, T_player, sub_pl_regular)
-	alloc_box(player2, T_player, sub_pl_regular)
-	alloc_box(locID, T_loc, sub_plain)
-
-	p_char(char1).unit_lord = player1
-	p_char(char2).unit_lord = player1
-	p_char(char3).unit_lord = player2
-	p_loc_info(char1).where = locID
-	p_loc_info(char2).where = locID
-	p_loc_info(char3).where = locID
-	p_loc_info(locID).here_list = []int{char1, char2, char3}
+	teg.alloc_box(char1, T_char, 0)
+	teg.alloc_box(char2, T_char, 0)
+	teg.alloc_box(char3, T_char, 0)
+	teg.alloc_box(player1, T_player, sub_pl_regular)
+	teg.alloc_box(player2, T_player, sub_pl_regular)
+	teg.alloc_box(locID, T_loc, sub_plain)
+
+	teg.p_char(char1).unit_lord = player1
+	teg.p_char(char2).unit_lord = player1
+	teg.p_char(char3).unit_lord = player2
+	teg.p_loc_info(char1).where = locID
+	teg.p_loc_info(char2).where = locID
+	teg.p_loc_info(char3).where = locID
+	teg.p_loc_info(locID).here_list = []int{char1, char2, char3}
 
 	t.Run("same faction allowed", func(t *testing.T) {
-		if !may_take(char1, char2) {
+		if !teg.may_take(char1, char2) {
 			t.Error("may_take should allow same faction")
 		}
 	})
 
 	t.Run("different faction denied", func(t *testing.T) {
-		if may_take(char1, char3) {
+		if teg.may_take(char1, char3) {
 			t.Error("may_take should deny different faction")
 		}
 	})
@@ -383,18 +383,18 @@ func TestVGet(t *testing.T) {
 	player1 := 2001
 	locID := 3001
 
-	alloc_box(char1, T_char, 0)
-	alloc_box(char2, T_char, 0)
-	alloc_box(player1, T_player, sub_pl_regular)
-	alloc_box(locID, T_loc, sub_plain)
+	teg.alloc_box(char1, T_char, 0)
+	teg.alloc_box(char2, T_char, 0)
+	teg.alloc_box(player1, T_player, sub_pl_regular)
+	teg.alloc_box(locID, T_loc, sub_plain)
 
-	p_char(char1).unit_lord = player1
-	p_char(char2).unit_lord = player1
-	p_loc_info(char1).where = locID
-	p_loc_info(char2).where = locID
-	p_loc_info(locID).here_list = []int{char1, char2}
+	teg.p_char(char1).unit_lord = player1
+	teg.p_char(char2).unit_lord = player1
+	teg.p_loc_info(char1).where = locID
+	teg.p_loc_info(char2).where = locID
+	teg.p_loc_info(locID).here_list = []int{char1, char2}
 
-	add_item(char2, item_gold, 100)
+	teg.add_item(char2, item_gold, 100)
 
 	c := &command{
 		who: char1,
@@ -404,16 +404,16 @@ func TestVGet(t *testing.T) {
 		d:   0,
 	}
 
-	result := v_get(c)
+	result := teg.v_get(c)
 	if result != TRUE {
 		t.Errorf("v_get returned %d, want %d", result, TRUE)
 	}
 
-	if has_item(char1, item_gold) != 40 {
-		t.Errorf("char1 should have 40 gold, has %d", has_item(char1, item_gold))
+	if teg.has_item(char1, item_gold) != 40 {
+		t.Errorf("char1 should have 40 gold, has %d", teg.has_item(char1, item_gold))
 	}
-	if has_item(char2, item_gold) != 60 {
-		t.Errorf("char2 should have 60 gold, has %d", has_item(char2, item_gold))
+	if teg.has_item(char2, item_gold) != 60 {
+		t.Errorf("char2 should have 60 gold, has %d", teg.has_item(char2, item_gold))
 	}
 }
 
@@ -425,21 +425,21 @@ func TestVClaim(t *testing.T) {
 	locID := 3001
 	regionID := 4001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(locID, T_loc, sub_plain)
-	alloc_box(regionID, T_loc, sub_region)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(locID, T_loc, sub_plain)
+	teg.alloc_box(regionID, T_loc, sub_region)
 
-	p_char(charID).unit_lord = playerID
-	p_loc_info(charID).where = locID
-	p_loc_info(locID).where = regionID
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_loc_info(charID).where = locID
+	teg.p_loc_info(locID).where = regionID
 
 	// Set special regions to unique IDs so they won't match our test region
-	cloud_region = 9001
-	hades_region = 9002
-	faery_region = 9003
+	teg.globals.cloudRegion = 9001
+	teg.globals.hadesRegion = 9002
+	teg.globals.faeryRegion = 9003
 
-	add_item(playerID, item_gold, 1000)
+	teg.add_item(playerID, item_gold, 1000)
 
 	c := &command{
 		who: charID,
@@ -448,16 +448,16 @@ func TestVClaim(t *testing.T) {
 		c:   0,
 	}
 
-	result := v_claim(c)
+	result := teg.v_claim(c)
 	if result != TRUE {
 		t.Errorf("v_claim returned %d, want %d", result, TRUE)
 	}
 
-	if has_item(charID, item_gold) != 100 {
-		t.Errorf("char should have 100 gold, has %d", has_item(charID, item_gold))
+	if teg.has_item(charID, item_gold) != 100 {
+		t.Errorf("char should have 100 gold, has %d", teg.has_item(charID, item_gold))
 	}
-	if has_item(playerID, item_gold) != 900 {
-		t.Errorf("player should have 900 gold, has %d", has_item(playerID, item_gold))
+	if teg.has_item(playerID, item_gold) != 900 {
+		t.Errorf("player should have 900 gold, has %d", teg.has_item(playerID, item_gold))
 	}
 }
 
@@ -469,21 +469,21 @@ func TestVClaimAutoCorrect(t *testing.T) {
 	locID := 3001
 	regionID := 4001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(locID, T_loc, sub_plain)
-	alloc_box(regionID, T_loc, sub_region)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(locID, T_loc, sub_plain)
+	teg.alloc_box(regionID, T_loc, sub_region)
 
-	p_char(charID).unit_lord = playerID
-	p_loc_info(charID).where = locID
-	p_loc_info(locID).where = regionID
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_loc_info(charID).where = locID
+	teg.p_loc_info(locID).where = regionID
 
 	// Set special regions to unique IDs so they won't match our test region
-	cloud_region = 9001
-	hades_region = 9002
-	faery_region = 9003
+	teg.globals.cloudRegion = 9001
+	teg.globals.hadesRegion = 9002
+	teg.globals.faeryRegion = 9003
 
-	add_item(playerID, item_gold, 1000)
+	teg.add_item(playerID, item_gold, 1000)
 
 	// CLAIM 500 should be auto-corrected to CLAIM 1 500 (claim 500 gold)
 	c := &command{
@@ -493,13 +493,13 @@ func TestVClaimAutoCorrect(t *testing.T) {
 		c:   0,
 	}
 
-	result := v_claim(c)
+	result := teg.v_claim(c)
 	if result != TRUE {
 		t.Errorf("v_claim with auto-correct returned %d, want %d", result, TRUE)
 	}
 
-	if has_item(charID, item_gold) != 500 {
-		t.Errorf("char should have 500 gold after auto-correct, has %d", has_item(charID, item_gold))
+	if teg.has_item(charID, item_gold) != 500 {
+		t.Errorf("char should have 500 gold after auto-correct, has %d", teg.has_item(charID, item_gold))
 	}
 }
 
@@ -507,13 +507,13 @@ func TestCountManItems(t *testing.T) {
 	setupTransferTest()
 
 	charID := 1001
-	alloc_box(charID, T_char, 0)
+	teg.alloc_box(charID, T_char, 0)
 
-	add_item(charID, item_peasant, 10)
-	add_item(charID, item_soldier, 5)
-	add_item(charID, item_gold, 100)
+	teg.add_item(charID, item_peasant, 10)
+	teg.add_item(charID, item_soldier, 5)
+	teg.add_item(charID, item_gold, 100)
 
-	count := count_man_items(charID)
+	count := teg.count_man_items(charID)
 	if count != 15 {
 		t.Errorf("count_man_items should be 15, got %d", count)
 	}
@@ -528,30 +528,30 @@ func TestMyPrisoner(t *testing.T) {
 	player1 := 2001
 	locID := 3001
 
-	alloc_box(char1, T_char, 0)
-	alloc_box(char2, T_char, 0)
-	alloc_box(char3, T_char, 0)
-	alloc_box(player1, T_player, sub_pl_regular)
-	alloc_box(locID, T_loc, sub_plain)
+	teg.alloc_box(char1, T_char, 0)
+	teg.alloc_box(char2, T_char, 0)
+	teg.alloc_box(char3, T_char, 0)
+	teg.alloc_box(player1, T_player, sub_pl_regular)
+	teg.alloc_box(locID, T_loc, sub_plain)
 
-	p_char(char1).unit_lord = player1
-	p_char(char2).unit_lord = player1
-	p_char(char3).unit_lord = player1
+	teg.p_char(char1).unit_lord = player1
+	teg.p_char(char2).unit_lord = player1
+	teg.p_char(char3).unit_lord = player1
 
-	p_loc_info(char1).where = locID
-	p_loc_info(char2).where = char1 // prisoner is located at the captor
-	p_char(char2).prisoner = 1
-	p_loc_info(char3).where = locID
-	p_loc_info(locID).here_list = []int{char1, char3}
+	teg.p_loc_info(char1).where = locID
+	teg.p_loc_info(char2).where = char1 // prisoner is located at the captor
+	teg.p_char(char2).prisoner = 1
+	teg.p_loc_info(char3).where = locID
+	teg.p_loc_info(locID).here_list = []int{char1, char3}
 
 	t.Run("prisoner under captor", func(t *testing.T) {
-		if !my_prisoner(char1, char2) {
+		if !teg.my_prisoner(char1, char2) {
 			t.Error("my_prisoner should return true for char2 under char1")
 		}
 	})
 
 	t.Run("non-prisoner", func(t *testing.T) {
-		if my_prisoner(char1, char3) {
+		if teg.my_prisoner(char1, char3) {
 			t.Error("my_prisoner should return false for non-prisoner")
 		}
 	})
@@ -567,29 +567,29 @@ func TestVGetGarrisonMinimum(t *testing.T) {
 	castleID := 3002
 	regionID := 4001
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(garrID, T_char, sub_garrison)
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(locID, T_loc, sub_plain)
-	alloc_box(castleID, T_loc, sub_castle)
-	alloc_box(regionID, T_loc, sub_region)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(garrID, T_char, sub_garrison)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(locID, T_loc, sub_plain)
+	teg.alloc_box(castleID, T_loc, sub_castle)
+	teg.alloc_box(regionID, T_loc, sub_region)
 
-	p_char(charID).unit_lord = playerID
-	p_char(garrID).unit_lord = playerID
+	teg.p_char(charID).unit_lord = playerID
+	teg.p_char(garrID).unit_lord = playerID
 
-	p_loc_info(charID).where = castleID
-	p_loc_info(garrID).where = castleID
-	p_loc_info(castleID).where = locID
-	p_loc_info(locID).where = regionID
+	teg.p_loc_info(charID).where = castleID
+	teg.p_loc_info(garrID).where = castleID
+	teg.p_loc_info(castleID).where = locID
+	teg.p_loc_info(locID).where = regionID
 	// charID must be first to be building owner (first_character)
-	p_loc_info(castleID).here_list = []int{charID, garrID}
-	p_loc_info(locID).here_list = []int{castleID}
+	teg.p_loc_info(castleID).here_list = []int{charID, garrID}
+	teg.p_loc_info(locID).here_list = []int{castleID}
 
 	// Set up the garrison-castle relationship
-	p_misc(garrID).garr_castle = castleID
+	teg.p_misc(garrID).garr_castle = castleID
 
 	// Give garrison 15 soldiers
-	add_item(garrID, item_soldier, 15)
+	teg.add_item(garrID, item_soldier, 15)
 
 	t.Run("cannot take leaving less than 10 men", func(t *testing.T) {
 		c := &command{
@@ -600,7 +600,7 @@ func TestVGetGarrisonMinimum(t *testing.T) {
 			d:   0,
 		}
 
-		result := v_get(c)
+		result := teg.v_get(c)
 		if result != FALSE {
 			t.Errorf("v_get from garrison leaving < 10 men should fail, got %d", result)
 		}
@@ -618,7 +618,7 @@ func TestVGetGarrisonMinimum(t *testing.T) {
 			d:   0,
 		}
 
-		result := v_get(c)
+		result := teg.v_get(c)
 		if result != TRUE {
 			t.Errorf("v_get from garrison leaving 10 men should succeed, got %d", result)
 		}
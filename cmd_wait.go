@@ -31,16 +31,6 @@ type flag_ent struct {
 	flag string // the flag message
 }
 
-// flags is the global list of raised flags for the current turn.
-// This list is cleared at the end of each turn.
-// Ported from src/c1.c line 1088.
-var flags []*flag_ent
-
-// wait_list is the global list of units currently waiting.
-// Used to check wait conditions each day.
-// Ported from src/c1.c line 1579.
-var wait_list []int
-
 // waitArgExt extends wait_arg with string flag support for Go.
 // Uses the existing wait_arg type from types.go but stores flag as string.
 type waitArgExt struct {
@@ -109,9 +99,9 @@ var wait_tags = []string{
 // flag_raised checks if a flag has been raised by the given who (or anyone if who=0).
 // Returns the index into the flags slice, or -1 if not found.
 // Ported from src/c1.c lines 1091-1108.
-func flag_raised(who int, flag string) int {
-	for i, f := range flags {
-		if who != 0 && player(f.who) != who && f.who != who {
+func (e *Engine) flag_raised(who int, flag string) int {
+	for i, f := range e.globals.flags {
+		if who != 0 && e.player(f.who) != who && f.who != who {
 			continue
 		}
 		if i_strcmp(f.flag, flag) == 0 {
@@ -125,16 +115,16 @@ func flag_raised(who int, flag string) int {
 // Raises a flag signal that other units can wait for.
 // Usage: FLAG <message>
 // Ported from src/c1.c lines 1111-1138.
-func v_flag(c *command) int {
-	if numargs(c) < 1 {
+func (e *Engine) v_flag(c *command) int {
+	if e.numargs(c) < 1 {
 		wout(c.who, "Must specify what message to signal.")
 		return FALSE
 	}
 
 	flag := get_wait_parse_arg(c, 1)
 
-	if flag_raised(c.who, flag) >= 0 {
-		wout(c.who, "%s has already given that signal this month.", box_name(c.who))
+	if e.flag_raised(c.who, flag) >= 0 {
+		wout(c.who, "%s has already given that signal this month.", e.box_name(c.who))
 		return FALSE
 	}
 
@@ -143,14 +133,14 @@ func v_flag(c *command) int {
 		flag: flag,
 	}
 
-	flags = append(flags, newFlag)
+	e.globals.flags = append(e.globals.flags, newFlag)
 
 	return TRUE
 }
 
 // clear_flags clears all raised flags (called at end of turn).
-func clear_flags() {
-	flags = nil
+func (e *Engine) clear_flags() {
+	e.globals.flags = nil
 }
 
 // clear_wait_parse clears the parsed wait arguments from a command.
@@ -162,9 +152,9 @@ func clear_wait_parse(c *command) {
 // parse_wait_args parses the WAIT command arguments into wait_arg structures.
 // Returns an error message string if parsing fails, or empty string on success.
 // Ported from src/c1.c lines 1187-1283.
-func parse_wait_args(c *command) string {
+func (e *Engine) parse_wait_args(c *command) string {
 	// Clear any existing parsed args
-	clearWaitParseList(c)
+	e.clearWaitParseList(c)
 
 	args := getCommandParseArgs(c)
 	i := 1 // skip command name at index 0
@@ -229,7 +219,7 @@ func parse_wait_args(c *command) string {
 				return "Flag missing."
 			}
 
-			newArg.a1 = player(c.who) // default to own faction
+			newArg.a1 = e.player(c.who) // default to own faction
 
 			if i < len(args) {
 				// Check if next arg is numeric or a valid ID
@@ -244,7 +234,7 @@ func parse_wait_args(c *command) string {
 			// No arguments needed
 		}
 
-		appendWaitParse(c, newArg)
+		e.appendWaitParse(c, newArg)
 	}
 
 	return ""
@@ -253,19 +243,19 @@ func parse_wait_args(c *command) string {
 // check_wait_conditions evaluates all WAIT conditions for a command.
 // Returns a status message if a condition is met, or empty string if still waiting.
 // Ported from src/c1.c lines 1286-1576.
-func check_wait_conditions(c *command) string {
-	whereShip := subloc(c.who)
-	if is_ship_either(whereShip) {
-		whereShip = subloc(whereShip)
+func (e *Engine) check_wait_conditions(c *command) string {
+	whereShip := e.subloc(c.who)
+	if e.is_ship_either(whereShip) {
+		whereShip = e.subloc(whereShip)
 	}
 
-	waitArgs := getWaitParse(c)
+	waitArgs := e.getWaitParse(c)
 	if len(waitArgs) < 1 {
-		ret := parse_wait_args(c)
+		ret := e.parse_wait_args(c)
 		if ret != "" {
 			return ret
 		}
-		waitArgs = getWaitParse(c)
+		waitArgs = e.getWaitParse(c)
 		if len(waitArgs) == 0 {
 			return "No wait conditions specified."
 		}
@@ -285,7 +275,7 @@ func check_wait_conditions(c *command) string {
 
 		switch p.tag {
 		case waitTagTime: // time n - wait n days
-			cond = (command_days(c) >= p.a1)
+			cond = (e.command_days(c) >= p.a1)
 			cond = evalNotCond(not, cond)
 			if cond {
 				if not {
@@ -295,61 +285,61 @@ func check_wait_conditions(c *command) string {
 			}
 
 		case waitTagDay: // day n - wait until day n
-			cond = (int(teg.globals.sysclock.day) >= p.a1)
+			cond = (int(e.globals.sysclock.day) >= p.a1)
 			cond = evalNotCond(not, cond)
 			if cond {
 				if not {
 					return fmt.Sprintf("today is not day %d.", p.a1)
 				}
-				return fmt.Sprintf("today is day %d.", int(teg.globals.sysclock.day))
+				return fmt.Sprintf("today is day %d.", int(e.globals.sysclock.day))
 			}
 
 		case waitTagMonth, waitTagTurn: // month/turn n - wait until turn n
-			cond = (int(teg.globals.sysclock.turn) >= p.a1)
+			cond = (int(e.globals.sysclock.turn) >= p.a1)
 			cond = evalNotCond(not, cond)
 			if cond {
 				if not {
 					return fmt.Sprintf("it is not turn %d.", p.a1)
 				}
-				return fmt.Sprintf("it is turn %d.", int(teg.globals.sysclock.turn))
+				return fmt.Sprintf("it is turn %d.", int(e.globals.sysclock.turn))
 			}
 
 		case waitTagUnit: // unit n - wait for unit to arrive
-			if !valid_box(p.a1) {
-				return fmt.Sprintf("%s does not exist.", box_code(p.a1))
+			if !e.valid_box(p.a1) {
+				return fmt.Sprintf("%s does not exist.", e.box_code(p.a1))
 			}
-			cond = char_here(c.who, p.a1)
+			cond = e.char_here(c.who, p.a1)
 			cond = evalNotCond(not, cond)
 			if cond {
-				return fmt.Sprintf("%s is%s here.", box_code(p.a1), notStr(not))
+				return fmt.Sprintf("%s is%s here.", e.box_code(p.a1), notStr(not))
 			}
 
 		case waitTagGold: // gold n - wait until have n gold
-			cond = (has_item(c.who, item_gold) >= p.a1)
+			cond = (e.has_item(c.who, item_gold) >= p.a1)
 			cond = evalNotCond(not, cond)
 			if cond {
 				if not {
-					return fmt.Sprintf("%s doesn't have %s.", just_name(c.who), gold_s(p.a1))
+					return fmt.Sprintf("%s doesn't have %s.", e.just_name(c.who), gold_s(p.a1))
 				}
-				return fmt.Sprintf("%s has %s.", just_name(c.who), gold_s(has_item(c.who, item_gold)))
+				return fmt.Sprintf("%s has %s.", e.just_name(c.who), gold_s(e.has_item(c.who, item_gold)))
 			}
 
 		case waitTagItem: // item n q - wait until have q of item n
-			cond = (kind(p.a1) == T_item && has_item(c.who, p.a1) >= p.a2)
+			cond = (e.kind(p.a1) == T_item && e.has_item(c.who, p.a1) >= p.a2)
 			cond = evalNotCond(not, cond)
 			if cond {
 				if not {
-					return fmt.Sprintf("%s doesn't have %s.", just_name(c.who), just_name_qty(p.a1, p.a2))
+					return fmt.Sprintf("%s doesn't have %s.", e.just_name(c.who), e.just_name_qty(p.a1, p.a2))
 				}
-				return fmt.Sprintf("%s has %s.", just_name(c.who), just_name_qty(p.a1, has_item(c.who, p.a1)))
+				return fmt.Sprintf("%s has %s.", e.just_name(c.who), e.just_name_qty(p.a1, e.has_item(c.who, p.a1)))
 			}
 
 		case waitTagFlag: // flag - wait for flag signal
-			if p.a1 != 0 && !valid_box(p.a1) {
-				return fmt.Sprintf("%s does not exist.", box_code(p.a1))
+			if p.a1 != 0 && !e.valid_box(p.a1) {
+				return fmt.Sprintf("%s does not exist.", e.box_code(p.a1))
 			}
 
-			j := flag_raised(p.a1, p.flagStr)
+			j := e.flag_raised(p.a1, p.flagStr)
 
 			if not {
 				if j < 0 {
@@ -357,88 +347,88 @@ func check_wait_conditions(c *command) string {
 				}
 			} else {
 				if j >= 0 {
-					return fmt.Sprintf("%s signaled '%s'", box_name(flags[j].who), flags[j].flag)
+					return fmt.Sprintf("%s signaled '%s'", e.box_name(e.globals.flags[j].who), e.globals.flags[j].flag)
 				}
 			}
 
 		case waitTagLoc: // loc n - wait until at location n
-			if !is_loc_or_ship(p.a1) {
-				return fmt.Sprintf("%s is not a location or ship.", box_code(p.a1))
+			if !e.is_loc_or_ship(p.a1) {
+				return fmt.Sprintf("%s is not a location or ship.", e.box_code(p.a1))
 			}
-			cond = (subloc(c.who) == p.a1)
+			cond = (e.subloc(c.who) == p.a1)
 			cond = evalNotCond(not, cond)
 			if cond {
-				return fmt.Sprintf("%sat %s.", notStr2(not), box_name(p.a1))
+				return fmt.Sprintf("%sat %s.", notStr2(not), e.box_name(p.a1))
 			}
 
 		case waitTagShiploc: // shiploc n - wait for ship location
-			ship := subloc(c.who)
-			if !is_ship(ship) && !is_ship_notdone(ship) {
-				return fmt.Sprintf("%s is not on a ship.", box_name(c.who))
+			ship := e.subloc(c.who)
+			if !e.is_ship(ship) && !e.is_ship_notdone(ship) {
+				return fmt.Sprintf("%s is not on a ship.", e.box_name(c.who))
 			}
-			if !is_loc_or_ship(p.a1) {
-				return fmt.Sprintf("%s is not a location or ship.", box_code(p.a1))
+			if !e.is_loc_or_ship(p.a1) {
+				return fmt.Sprintf("%s is not a location or ship.", e.box_code(p.a1))
 			}
-			where := subloc(ship)
+			where := e.subloc(ship)
 			cond = (where == p.a1)
 			cond = evalNotCond(not, cond)
 			if cond {
-				return fmt.Sprintf("%sat %s.", notStr2(not), box_name(p.a1))
+				return fmt.Sprintf("%sat %s.", notStr2(not), e.box_name(p.a1))
 			}
 
 		case waitTagStack: // stack n - wait until stacked with n
-			if kind(p.a1) != T_char {
+			if e.kind(p.a1) != T_char {
 				break // just hang (don't error)
 			}
-			cond = (stack_leader(c.who) == stack_leader(p.a1))
+			cond = (e.stack_leader(c.who) == e.stack_leader(p.a1))
 			cond = evalNotCond(not, cond)
 			if cond {
-				return fmt.Sprintf("%s is%s stacked with us.", box_name(p.a1), notStr(not))
+				return fmt.Sprintf("%s is%s stacked with us.", e.box_name(p.a1), notStr(not))
 			}
 
 		case waitTagTop: // top - wait until stack leader
-			cond = (stack_leader(c.who) == c.who)
+			cond = (e.stack_leader(c.who) == c.who)
 			cond = evalNotCond(not, cond)
 			if cond {
 				return fmt.Sprintf("we are%s the stack leader", notStr(not))
 			}
 
 		case waitTagFerry: // ferry n - wait for ferry signal
-			if !is_ship(p.a1) {
-				return fmt.Sprintf("%s is not a ship", box_code(p.a1))
+			if !e.is_ship(p.a1) {
+				return fmt.Sprintf("%s is not a ship", e.box_code(p.a1))
 			}
-			cond = (subloc(p.a1) == subloc(c.who) && ferry_horn(p.a1) != 0)
+			cond = (e.subloc(p.a1) == e.subloc(c.who) && e.ferry_horn(p.a1) != 0)
 			cond = evalNotCond(not, cond)
 			if cond {
 				return fmt.Sprintf("the ferry has%s signaled.", notStr(not))
 			}
 
 		case waitTagShip: // ship n - wait for ship at location
-			if kind(p.a1) != T_ship {
-				return fmt.Sprintf("%s is not a ship.", box_code(p.a1))
+			if e.kind(p.a1) != T_ship {
+				return fmt.Sprintf("%s is not a ship.", e.box_code(p.a1))
 			}
-			cond = (whereShip == subloc(p.a1))
+			cond = (whereShip == e.subloc(p.a1))
 			cond = evalNotCond(not, cond)
 			if cond {
-				return fmt.Sprintf("%s is%s here.", box_code(p.a1), notStr(not))
+				return fmt.Sprintf("%s is%s here.", e.box_code(p.a1), notStr(not))
 			}
 
 		case waitTagRain: // rain - wait for rain
-			cond = (weather_here(province(c.who), sub_rain) != 0)
+			cond = (e.weather_here(e.province(c.who), sub_rain) != 0)
 			cond = evalNotCond(not, cond)
 			if cond {
 				return fmt.Sprintf("it is%s raining.", notStr(not))
 			}
 
 		case waitTagFog: // fog - wait for fog
-			cond = (weather_here(province(c.who), sub_fog) != 0)
+			cond = (e.weather_here(e.province(c.who), sub_fog) != 0)
 			cond = evalNotCond(not, cond)
 			if cond {
 				return fmt.Sprintf("it is%s foggy.", notStr(not))
 			}
 
 		case waitTagWind: // wind - wait for wind
-			cond = (weather_here(province(c.who), sub_wind) != 0)
+			cond = (e.weather_here(e.province(c.who), sub_wind) != 0)
 			cond = evalNotCond(not, cond)
 			if cond {
 				return fmt.Sprintf("it is%s windy.", notStr(not))
@@ -449,16 +439,16 @@ func check_wait_conditions(c *command) string {
 			setNot = true
 
 		case waitTagOwner: // owner - wait until first character in loc
-			cond = (first_character(subloc(c.who)) == c.who)
+			cond = (e.first_character(e.subloc(c.who)) == c.who)
 			cond = evalNotCond(not, cond)
 			if cond {
 				return fmt.Sprintf("we are%s the first character here", notStr(not))
 			}
 
 		case waitTagClear: // clear - wait for clear weather
-			cond = (weather_here(subloc(c.who), sub_fog) == 0 &&
-				weather_here(subloc(c.who), sub_rain) == 0 &&
-				weather_here(subloc(c.who), sub_wind) == 0)
+			cond = (e.weather_here(e.subloc(c.who), sub_fog) == 0 &&
+				e.weather_here(e.subloc(c.who), sub_rain) == 0 &&
+				e.weather_here(e.subloc(c.who), sub_wind) == 0)
 			cond = evalNotCond(not, cond)
 			if cond {
 				return fmt.Sprintf("it is%s clear.", notStr(not))
@@ -473,15 +463,15 @@ func check_wait_conditions(c *command) string {
 // Waits for one or more conditions to be met.
 // Usage: WAIT <condition> [args...]
 // Ported from src/c1.c lines 1582-1606.
-func v_wait(c *command) int {
-	if numargs(c) < 1 {
+func (e *Engine) v_wait(c *command) int {
+	if e.numargs(c) < 1 {
 		wout(c.who, "Must say what condition to wait for.")
 		return FALSE
 	}
 
 	clear_wait_parse(c)
 
-	if s := check_wait_conditions(c); s != "" {
+	if s := e.check_wait_conditions(c); s != "" {
 		wout(c.who, "Wait finished: %s", s)
 
 		c.wait = 0
@@ -489,17 +479,17 @@ func v_wait(c *command) int {
 		return TRUE
 	}
 
-	IListAppend(&wait_list, c.who)
+	IListAppend(&e.globals.wait_list, c.who)
 	return TRUE
 }
 
 // d_wait is the finish routine for the WAIT command.
 // Called each day to check if wait conditions are met.
 // Ported from src/c1.c lines 1609-1625.
-func d_wait(c *command) int {
-	if s := check_wait_conditions(c); s != "" {
+func (e *Engine) d_wait(c *command) int {
+	if s := e.check_wait_conditions(c); s != "" {
 		wout(c.who, "Wait finished: %s", s)
-		IListRemValue(&wait_list, c.who)
+		IListRemValue(&e.globals.wait_list, c.who)
 
 		c.wait = 0
 		c.inhibit_finish = TRUE
@@ -512,8 +502,8 @@ func d_wait(c *command) int {
 // i_wait is the interrupt routine for the WAIT command.
 // Called when the WAIT command is interrupted.
 // Ported from src/c1.c lines 1628-1634.
-func i_wait(c *command) int {
-	IListRemValue(&wait_list, c.who)
+func (e *Engine) i_wait(c *command) int {
+	IListRemValue(&e.globals.wait_list, c.who)
 	return TRUE
 }
 
@@ -561,7 +551,7 @@ func pluralHaveHas(n int) string {
 }
 
 // command_days returns the number of days a command has been executing.
-func command_days(c *command) int {
+func (e *Engine) command_days(c *command) int {
 	return c.days_executing
 }
 
@@ -589,8 +579,12 @@ func get_wait_parse_arg(c *command, i int) string {
 // getCommandParseArgs returns the parsed arguments for a command.
 // Workaround since c.parse is **char in C.
 func getCommandParseArgs(c *command) []string {
-	// For now, construct from command args field.
-	// This will be replaced when full order parsing is implemented.
+	if c.parse != nil {
+		return c.parse
+	}
+
+	// Command built by hand rather than parsed from an order;
+	// construct the arguments from the numeric fields.
 	var args []string
 	args = append(args, "wait") // command name placeholder
 
@@ -612,60 +606,57 @@ func getCommandParseArgs(c *command) []string {
 }
 
 // appendWaitParse appends a waitArgExt to the command's wait_parse list.
-func appendWaitParse(c *command, arg *waitArgExt) {
+func (e *Engine) appendWaitParse(c *command, arg *waitArgExt) {
 	// Workaround: wait_parse is **wait_arg in C
 	// We store in engine globals keyed by command pointer
-	waitParseLists[c] = append(waitParseLists[c], arg)
+	if e.globals.waitParseLists == nil {
+		e.globals.waitParseLists = make(map[*command][]*waitArgExt)
+	}
+	e.globals.waitParseLists[c] = append(e.globals.waitParseLists[c], arg)
 }
 
 // getWaitParse returns the wait_parse list for a command.
-func getWaitParse(c *command) []*waitArgExt {
-	return waitParseLists[c]
+func (e *Engine) getWaitParse(c *command) []*waitArgExt {
+	return e.globals.waitParseLists[c]
 }
 
-// waitParseLists stores wait_parse lists keyed by command pointer.
-// Workaround since command.wait_parse is **wait_arg (C plist).
-var waitParseLists = make(map[*command][]*waitArgExt)
-
 // clearWaitParseLists clears all stored wait parse lists.
 // Called at end of turn processing.
-func clearWaitParseLists() {
-	waitParseLists = make(map[*command][]*waitArgExt)
+func (e *Engine) clearWaitParseLists() {
+	e.globals.waitParseLists = make(map[*command][]*waitArgExt)
 }
 
 // clearWaitParseList clears the wait parse list for a specific command.
-func clearWaitParseList(c *command) {
-	delete(waitParseLists, c)
+func (e *Engine) clearWaitParseList(c *command) {
+	delete(e.globals.waitParseLists, c)
 }
 
 // init_wait_list initializes the wait_list at the start of a turn.
 // Called from input.c init_wait_list.
-func init_wait_list() {
-	wait_list = nil
+func (e *Engine) init_wait_list() {
+	e.globals.wait_list = nil
 
 	// Scan all characters for those with running WAIT commands
-	for id := teg.KindFirst(T_char); id > 0; id = teg.KindNext(id) {
-		c := rp_command(id)
+	for id := e.KindFirst(T_char); id > 0; id = e.KindNext(id) {
+		c := e.rp_command(id)
 		if c != nil && c.state == STATE_RUN && is_wait_command(c.cmd) {
-			IListAppend(&wait_list, id)
+			IListAppend(&e.globals.wait_list, id)
 		}
 	}
 }
 
 // check_all_wait_conditions checks wait conditions for all waiting units.
 // Called each day during turn processing.
-func check_all_wait_conditions() {
-	for i := 0; i < len(wait_list); i++ {
-		c := rp_command(wait_list[i])
+func (e *Engine) check_all_wait_conditions() {
+	for i := 0; i < len(e.globals.wait_list); i++ {
+		c := e.rp_command(e.globals.wait_list[i])
 		if c != nil {
-			check_wait_conditions(c)
+			e.check_wait_conditions(c)
 		}
 	}
 }
 
 // is_wait_command checks if cmd is the WAIT command.
-// TODO: Replace with proper command lookup when cmd_tbl is implemented.
 func is_wait_command(cmd int) bool {
-	// Placeholder - will be implemented when command table is available
-	return false
+	return cmd > 0 && cmd < len(cmd_tbl) && cmd_tbl[cmd].name == "wait"
 }
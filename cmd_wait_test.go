@@ -23,39 +23,39 @@ import (
 // TestFlagRaised tests the flag_raised function.
 func TestFlagRaised(t *testing.T) {
 	// Clear any existing flags
-	flags = nil
+	teg.globals.flags = nil
 
 	// No flags raised yet
-	if got := flag_raised(0, "attack"); got != -1 {
+	if got := teg.flag_raised(0, "attack"); got != -1 {
 		t.Errorf("flag_raised(0, 'attack') = %d, want -1 (no flags)", got)
 	}
 
 	// Add a flag
-	flags = append(flags, &flag_ent{who: 1001, flag: "attack"})
+	teg.globals.flags = append(teg.globals.flags, &flag_ent{who: 1001, flag: "attack"})
 
 	// Should find it with who=0 (any)
-	if got := flag_raised(0, "attack"); got != 0 {
+	if got := teg.flag_raised(0, "attack"); got != 0 {
 		t.Errorf("flag_raised(0, 'attack') = %d, want 0", got)
 	}
 
 	// Should find it with matching who
 	setupWaitTestPlayer(1001, 100)
-	if got := flag_raised(1001, "attack"); got != 0 {
+	if got := teg.flag_raised(1001, "attack"); got != 0 {
 		t.Errorf("flag_raised(1001, 'attack') = %d, want 0", got)
 	}
 
 	// Should find it by player
-	if got := flag_raised(100, "attack"); got != 0 {
+	if got := teg.flag_raised(100, "attack"); got != 0 {
 		t.Errorf("flag_raised(100, 'attack') = %d, want 0", got)
 	}
 
 	// Should not find with different flag
-	if got := flag_raised(0, "retreat"); got != -1 {
+	if got := teg.flag_raised(0, "retreat"); got != -1 {
 		t.Errorf("flag_raised(0, 'retreat') = %d, want -1", got)
 	}
 
 	// Case-insensitive match
-	if got := flag_raised(0, "ATTACK"); got != 0 {
+	if got := teg.flag_raised(0, "ATTACK"); got != 0 {
 		t.Errorf("flag_raised(0, 'ATTACK') = %d, want 0 (case-insensitive)", got)
 	}
 }
@@ -63,14 +63,14 @@ func TestFlagRaised(t *testing.T) {
 // TestVFlag tests the v_flag command.
 func TestVFlag(t *testing.T) {
 	// Clear any existing flags
-	flags = nil
+	teg.globals.flags = nil
 
 	charID := 1001
 	setupWaitTestChar(charID)
 
 	// Test with no arguments (numargs returns 0 when a=0)
 	c := &command{who: charID}
-	if got := v_flag(c); got != FALSE {
+	if got := teg.v_flag(c); got != FALSE {
 		t.Error("v_flag with no args should return FALSE")
 	}
 }
@@ -78,19 +78,19 @@ func TestVFlag(t *testing.T) {
 // TestClearFlags tests that clear_flags properly clears all flags.
 func TestClearFlags(t *testing.T) {
 	// Start fresh
-	flags = nil
+	teg.globals.flags = nil
 
-	flags = append(flags, &flag_ent{who: 1001, flag: "test"})
-	flags = append(flags, &flag_ent{who: 1002, flag: "test2"})
+	teg.globals.flags = append(teg.globals.flags, &flag_ent{who: 1001, flag: "test"})
+	teg.globals.flags = append(teg.globals.flags, &flag_ent{who: 1002, flag: "test2"})
 
-	if len(flags) != 2 {
+	if len(teg.globals.flags) != 2 {
 		t.Fatal("setup failed")
 	}
 
-	clear_flags()
+	teg.clear_flags()
 
-	if len(flags) != 0 {
-		t.Errorf("after clear_flags, len(flags) = %d, want 0", len(flags))
+	if len(teg.globals.flags) != 0 {
+		t.Errorf("after clear_flags, len(flags) = %d, want 0", len(teg.globals.flags))
 	}
 }
 
@@ -143,10 +143,10 @@ func TestParseWaitArgs(t *testing.T) {
 
 	// Test that parse_wait_args can be called and clears the list
 	c := &command{who: charID}
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagTime, a1: 5}}
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagTime, a1: 5}}
 
 	// Calling parse_wait_args should clear the list first
-	_ = parse_wait_args(c)
+	_ = teg.parse_wait_args(c)
 
 	// The actual parsing depends on getCommandParseArgs which is simplified for now
 }
@@ -159,16 +159,16 @@ func TestCheckWaitConditionsTime(t *testing.T) {
 	c := &command{who: charID, days_executing: 3}
 
 	// Set up wait for 5 days
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagTime, a1: 5}}
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagTime, a1: 5}}
 
 	// Should not trigger (only 3 days elapsed)
-	if got := check_wait_conditions(c); got != "" {
+	if got := teg.check_wait_conditions(c); got != "" {
 		t.Errorf("check_wait_conditions with 3/5 days = %q, want empty", got)
 	}
 
 	// Advance to 5 days
 	c.days_executing = 5
-	if got := check_wait_conditions(c); got == "" {
+	if got := teg.check_wait_conditions(c); got == "" {
 		t.Error("check_wait_conditions with 5/5 days should trigger")
 	}
 }
@@ -182,16 +182,16 @@ func TestCheckWaitConditionsDay(t *testing.T) {
 	teg.globals.sysclock.day = 10
 
 	c := &command{who: charID}
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagDay, a1: 15}}
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagDay, a1: 15}}
 
 	// Should not trigger (day 10, waiting for day 15)
-	if got := check_wait_conditions(c); got != "" {
+	if got := teg.check_wait_conditions(c); got != "" {
 		t.Errorf("check_wait_conditions day 10/15 = %q, want empty", got)
 	}
 
 	// Advance to day 15
 	teg.globals.sysclock.day = 15
-	if got := check_wait_conditions(c); got == "" {
+	if got := teg.check_wait_conditions(c); got == "" {
 		t.Error("check_wait_conditions day 15/15 should trigger")
 	}
 }
@@ -204,16 +204,16 @@ func TestCheckWaitConditionsTurn(t *testing.T) {
 	teg.globals.sysclock.turn = 5
 
 	c := &command{who: charID}
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagTurn, a1: 10}}
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagTurn, a1: 10}}
 
 	// Should not trigger (turn 5, waiting for turn 10)
-	if got := check_wait_conditions(c); got != "" {
+	if got := teg.check_wait_conditions(c); got != "" {
 		t.Errorf("check_wait_conditions turn 5/10 = %q, want empty", got)
 	}
 
 	// Advance to turn 10
 	teg.globals.sysclock.turn = 10
-	if got := check_wait_conditions(c); got == "" {
+	if got := teg.check_wait_conditions(c); got == "" {
 		t.Error("check_wait_conditions turn 10/10 should trigger")
 	}
 }
@@ -226,10 +226,10 @@ func TestCheckWaitConditionsGold(t *testing.T) {
 	setupWaitTestChar(charID)
 
 	c := &command{who: charID}
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagGold, a1: 100}}
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagGold, a1: 100}}
 
 	// With no gold, should not trigger
-	result := check_wait_conditions(c)
+	result := teg.check_wait_conditions(c)
 	if result != "" {
 		t.Logf("check_wait_conditions without gold = %q", result)
 	}
@@ -244,10 +244,10 @@ func TestCheckWaitConditionsItem(t *testing.T) {
 	itemID := 10
 
 	c := &command{who: charID}
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagItem, a1: itemID, a2: 10}}
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagItem, a1: itemID, a2: 10}}
 
 	// Without item, should not trigger (item kind check fails)
-	result := check_wait_conditions(c)
+	result := teg.check_wait_conditions(c)
 	if result != "" {
 		t.Logf("check_wait_conditions item without setup = %q", result)
 	}
@@ -265,20 +265,20 @@ func TestCheckWaitConditionsUnit(t *testing.T) {
 	setupWaitTestLoc(locID)
 	setupWaitTestLoc(otherLocID)
 
-	set_where(charID, locID)
-	set_where(targetID, otherLocID)
+	teg.set_where(charID, locID)
+	teg.set_where(targetID, otherLocID)
 
 	c := &command{who: charID}
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagUnit, a1: targetID}}
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagUnit, a1: targetID}}
 
 	// Should not trigger (target in different location)
-	if got := check_wait_conditions(c); got != "" {
+	if got := teg.check_wait_conditions(c); got != "" {
 		t.Errorf("check_wait_conditions unit not here = %q, want empty", got)
 	}
 
 	// Move target to same location
-	set_where(targetID, locID)
-	if got := check_wait_conditions(c); got == "" {
+	teg.set_where(targetID, locID)
+	if got := teg.check_wait_conditions(c); got == "" {
 		t.Error("check_wait_conditions unit here should trigger")
 	}
 }
@@ -289,19 +289,19 @@ func TestCheckWaitConditionsFlag(t *testing.T) {
 	setupWaitTestChar(charID)
 	setupWaitTestPlayer(charID, 100)
 
-	flags = nil
+	teg.globals.flags = nil
 
 	c := &command{who: charID}
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagFlag, a1: 100, flagStr: "attack"}}
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagFlag, a1: 100, flagStr: "attack"}}
 
 	// Should not trigger (no flags raised)
-	if got := check_wait_conditions(c); got != "" {
+	if got := teg.check_wait_conditions(c); got != "" {
 		t.Errorf("check_wait_conditions flag not raised = %q, want empty", got)
 	}
 
 	// Raise the flag
-	flags = append(flags, &flag_ent{who: charID, flag: "attack"})
-	if got := check_wait_conditions(c); got == "" {
+	teg.globals.flags = append(teg.globals.flags, &flag_ent{who: charID, flag: "attack"})
+	if got := teg.check_wait_conditions(c); got == "" {
 		t.Error("check_wait_conditions flag raised should trigger")
 	}
 }
@@ -316,19 +316,19 @@ func TestCheckWaitConditionsLoc(t *testing.T) {
 	setupWaitTestLoc(locID)
 	setupWaitTestLoc(targetLocID)
 
-	set_where(charID, locID)
+	teg.set_where(charID, locID)
 
 	c := &command{who: charID}
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagLoc, a1: targetLocID}}
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagLoc, a1: targetLocID}}
 
 	// Should not trigger (not at target location)
-	if got := check_wait_conditions(c); got != "" {
+	if got := teg.check_wait_conditions(c); got != "" {
 		t.Errorf("check_wait_conditions loc not there = %q, want empty", got)
 	}
 
 	// Move to target location
-	set_where(charID, targetLocID)
-	if got := check_wait_conditions(c); got == "" {
+	teg.set_where(charID, targetLocID)
+	if got := teg.check_wait_conditions(c); got == "" {
 		t.Error("check_wait_conditions loc there should trigger")
 	}
 }
@@ -343,15 +343,15 @@ func TestCheckWaitConditionsStack(t *testing.T) {
 	setupWaitTestChar(targetID)
 	setupWaitTestLoc(locID)
 
-	set_where(charID, locID)
-	set_where(targetID, locID)
+	teg.set_where(charID, locID)
+	teg.set_where(targetID, locID)
 
 	c := &command{who: charID}
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagStack, a1: targetID}}
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagStack, a1: targetID}}
 
 	// Note: Stack leader depends on stacking system
 	// For now just test that the condition can be evaluated
-	_ = check_wait_conditions(c)
+	_ = teg.check_wait_conditions(c)
 }
 
 // TestCheckWaitConditionsTop tests the "top" wait condition.
@@ -360,10 +360,10 @@ func TestCheckWaitConditionsTop(t *testing.T) {
 	setupWaitTestChar(charID)
 
 	c := &command{who: charID}
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagTop}}
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagTop}}
 
 	// Character alone is stack leader
-	result := check_wait_conditions(c)
+	result := teg.check_wait_conditions(c)
 	if result == "" {
 		t.Error("check_wait_conditions top (alone) should trigger")
 	}
@@ -379,23 +379,23 @@ func TestCheckWaitConditionsNot(t *testing.T) {
 	setupWaitTestLoc(locID)
 	setupWaitTestLoc(targetLocID)
 
-	set_where(charID, locID)
+	teg.set_where(charID, locID)
 
 	c := &command{who: charID}
 	// "not loc target" - trigger if NOT at target location
-	waitParseLists[c] = []*waitArgExt{
+	teg.globals.waitParseLists[c] = []*waitArgExt{
 		{tag: waitTagNot},
 		{tag: waitTagLoc, a1: targetLocID},
 	}
 
 	// Should trigger (we are NOT at target location)
-	if got := check_wait_conditions(c); got == "" {
+	if got := teg.check_wait_conditions(c); got == "" {
 		t.Error("check_wait_conditions 'not loc' should trigger when not at target")
 	}
 
 	// Move to target location
-	set_where(charID, targetLocID)
-	if got := check_wait_conditions(c); got != "" {
+	teg.set_where(charID, targetLocID)
+	if got := teg.check_wait_conditions(c); got != "" {
 		t.Errorf("check_wait_conditions 'not loc' at target = %q, want empty", got)
 	}
 }
@@ -407,11 +407,11 @@ func TestCheckWaitConditionsWeather(t *testing.T) {
 
 	setupWaitTestChar(charID)
 	setupWaitTestProvince(provID)
-	set_where(charID, provID)
+	teg.set_where(charID, provID)
 
 	// Set up province accessor to return province
 	ensureWaitBox(provID)
-	bxProv := teg.globals.bx[provID]
+	bxProv := teg.globals.bx.get(provID)
 	bxProv.kind = T_loc
 	if bxProv.x_loc == nil {
 		bxProv.x_loc = &entity_loc{}
@@ -420,14 +420,14 @@ func TestCheckWaitConditionsWeather(t *testing.T) {
 	c := &command{who: charID}
 
 	// Test rain condition (no rain)
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagRain}}
-	if got := check_wait_conditions(c); got != "" {
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagRain}}
+	if got := teg.check_wait_conditions(c); got != "" {
 		t.Errorf("check_wait_conditions rain (none) = %q, want empty", got)
 	}
 
 	// Test clear condition (should trigger when no weather)
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagClear}}
-	if got := check_wait_conditions(c); got == "" {
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagClear}}
+	if got := teg.check_wait_conditions(c); got == "" {
 		t.Error("check_wait_conditions clear (no weather) should trigger")
 	}
 }
@@ -438,24 +438,24 @@ func TestVWait(t *testing.T) {
 	setupWaitTestChar(charID)
 
 	// Clear wait list
-	wait_list = nil
+	teg.globals.wait_list = nil
 
 	// Test with no arguments (numargs returns 0 when a=0)
 	c := &command{who: charID}
-	if got := v_wait(c); got != FALSE {
+	if got := teg.v_wait(c); got != FALSE {
 		t.Error("v_wait with no args should return FALSE")
 	}
 
 	// Test with a condition set up - should add to wait list
 	c = &command{who: charID, a: 1}                           // numargs > 0
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagTime, a1: 99}} // Wait 99 days (won't trigger)
-	initialLen := len(wait_list)
-	result := v_wait(c)
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagTime, a1: 99}} // Wait 99 days (won't trigger)
+	initialLen := len(teg.globals.wait_list)
+	result := teg.v_wait(c)
 	if result != TRUE {
 		t.Error("v_wait with waiting condition should return TRUE")
 	}
 	// Unit should be added to wait list
-	if len(wait_list) <= initialLen {
+	if len(teg.globals.wait_list) <= initialLen {
 		t.Error("v_wait should add unit to wait_list")
 	}
 }
@@ -465,13 +465,13 @@ func TestDWait(t *testing.T) {
 	charID := 1001
 	setupWaitTestChar(charID)
 
-	wait_list = []int{charID}
+	teg.globals.wait_list = []int{charID}
 
 	c := &command{who: charID, days_executing: 3}
-	waitParseLists[c] = []*waitArgExt{{tag: waitTagTime, a1: 5}}
+	teg.globals.waitParseLists[c] = []*waitArgExt{{tag: waitTagTime, a1: 5}}
 
 	// Not yet time - should return TRUE but not finish
-	if got := d_wait(c); got != TRUE {
+	if got := teg.d_wait(c); got != TRUE {
 		t.Error("d_wait should return TRUE")
 	}
 	if c.inhibit_finish == TRUE {
@@ -481,7 +481,7 @@ func TestDWait(t *testing.T) {
 	// Time elapsed - should finish
 	c.days_executing = 5
 	c.inhibit_finish = 0
-	if got := d_wait(c); got != TRUE {
+	if got := teg.d_wait(c); got != TRUE {
 		t.Error("d_wait should return TRUE when finished")
 	}
 	if c.inhibit_finish != TRUE {
@@ -494,15 +494,15 @@ func TestIWait(t *testing.T) {
 	charID := 1001
 	setupWaitTestChar(charID)
 
-	wait_list = []int{charID, 1002, 1003}
+	teg.globals.wait_list = []int{charID, 1002, 1003}
 
 	c := &command{who: charID}
-	if got := i_wait(c); got != TRUE {
+	if got := teg.i_wait(c); got != TRUE {
 		t.Error("i_wait should return TRUE")
 	}
 
 	// Character should be removed from wait_list
-	for _, id := range wait_list {
+	for _, id := range teg.globals.wait_list {
 		if id == charID {
 			t.Error("i_wait should remove character from wait_list")
 		}
@@ -511,25 +511,25 @@ func TestIWait(t *testing.T) {
 
 // TestWaitListOperations tests wait_list add/remove operations.
 func TestWaitListOperations(t *testing.T) {
-	wait_list = nil
+	teg.globals.wait_list = nil
 
 	// Add characters
-	IListAppend(&wait_list, 1001)
-	IListAppend(&wait_list, 1002)
-	IListAppend(&wait_list, 1003)
+	IListAppend(&teg.globals.wait_list, 1001)
+	IListAppend(&teg.globals.wait_list, 1002)
+	IListAppend(&teg.globals.wait_list, 1003)
 
-	if len(wait_list) != 3 {
-		t.Errorf("wait_list length = %d, want 3", len(wait_list))
+	if len(teg.globals.wait_list) != 3 {
+		t.Errorf("wait_list length = %d, want 3", len(teg.globals.wait_list))
 	}
 
 	// Remove one
-	IListRemValue(&wait_list, 1002)
-	if len(wait_list) != 2 {
-		t.Errorf("wait_list length after remove = %d, want 2", len(wait_list))
+	IListRemValue(&teg.globals.wait_list, 1002)
+	if len(teg.globals.wait_list) != 2 {
+		t.Errorf("wait_list length after remove = %d, want 2", len(teg.globals.wait_list))
 	}
 
 	// Verify 1002 is gone
-	for _, id := range wait_list {
+	for _, id := range teg.globals.wait_list {
 		if id == 1002 {
 			t.Error("1002 should be removed from wait_list")
 		}
@@ -595,15 +595,18 @@ func TestNotStrHelpers(t *testing.T) {
 
 // Helper function to ensure a box exists
 func ensureWaitBox(id int) {
-	if teg.globals.bx[id] == nil {
-		teg.globals.bx[id] = &box{}
+	if teg.globals.bx.get(id) == nil {
+		teg.setBox(id, &box{})
 	}
 }
 
 // Helper function to set up a test character for wait tests
 func setupWaitTestChar(charID int) {
 	ensureWaitBox(charID)
-	bxChar := teg.globals.bx[charID]
+	if teg.globals.waitParseLists == nil {
+		teg.globals.waitParseLists = make(map[*command][]*waitArgExt)
+	}
+	bxChar := teg.globals.bx.get(charID)
 	bxChar.kind = T_char
 	if bxChar.x_char == nil {
 		bxChar.x_char = &entity_char{}
@@ -616,7 +619,7 @@ func setupWaitTestChar(charID int) {
 // Helper function to set up a test location for wait tests
 func setupWaitTestLoc(locID int) {
 	ensureWaitBox(locID)
-	bxLoc := teg.globals.bx[locID]
+	bxLoc := teg.globals.bx.get(locID)
 	bxLoc.kind = T_loc
 	bxLoc.x_loc_info = loc_info{}
 }
@@ -624,11 +627,11 @@ func setupWaitTestLoc(locID int) {
 // Helper function to set up test player for wait tests
 func setupWaitTestPlayer(charID, playerID int) {
 	ensureWaitBox(playerID)
-	bxPlayer := teg.globals.bx[playerID]
+	bxPlayer := teg.globals.bx.get(playerID)
 	bxPlayer.kind = T_player
 
 	ensureWaitBox(charID)
-	bxChar := teg.globals.bx[charID]
+	bxChar := teg.globals.bx.get(charID)
 	if bxChar.x_char == nil {
 		bxChar.x_char = &entity_char{}
 	}
@@ -638,7 +641,7 @@ func setupWaitTestPlayer(charID, playerID int) {
 // Helper function to set up test province for wait tests
 func setupWaitTestProvince(id int) {
 	ensureWaitBox(id)
-	bx := teg.globals.bx[id]
+	bx := teg.globals.bx.get(id)
 	bx.kind = T_loc
 	bx.skind = sub_plain // province subkind
 	if bx.x_loc == nil {
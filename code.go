@@ -202,52 +202,52 @@ func scode(s string) int {
 
 // name returns the name of entity n.
 // Returns empty string if entity doesn't exist or has no name.
-func name(n int) string {
-	if !valid_box(n) {
+func (e *Engine) name(n int) string {
+	if !e.valid_box(n) {
 		return ""
 	}
-	return teg.getName(n)
+	return e.getName(n)
 }
 
 // set_name sets the name of entity n.
 // Replaces brackets with braces in the name.
-func set_name(n int, s string) {
-	if !valid_box(n) {
+func (e *Engine) set_name(n int, s string) {
+	if !e.valid_box(n) {
 		return
 	}
 	// Replace brackets with braces (as in C code)
 	s = strings.ReplaceAll(s, "[", "{")
 	s = strings.ReplaceAll(s, "]", "}")
-	teg.setName(n, s)
+	e.setName(n, s)
 }
 
 // set_banner sets the display banner for entity n.
 // Truncates to 50 characters max.
-func set_banner(n int, s string) {
+func (e *Engine) set_banner(n int, s string) {
 	if s != "" && len(s) > 50 {
 		s = s[:50]
 	}
-	teg.setBanner(n, s)
+	e.setBanner(n, s)
 }
 
 // banner returns the display banner for entity n.
-func get_banner(n int) string {
-	return teg.getBanner(n)
+func (e *Engine) get_banner(n int) string {
+	return e.getBanner(n)
 }
 
 // display_name returns the display name for entity n.
 // Falls back to kind-based names if entity has no name.
-func display_name(n int) string {
-	if !valid_box(n) {
+func (e *Engine) display_name(n int) string {
+	if !e.valid_box(n) {
 		return ""
 	}
 
-	s := name(n)
+	s := e.name(n)
 	if s != "" {
 		return s
 	}
 
-	switch kind(n) {
+	switch e.kind(n) {
 	case T_player:
 		return "Player"
 	case T_gate:
@@ -256,11 +256,11 @@ func display_name(n int) string {
 		return "Sign"
 	}
 
-	if i := noble_item(n); i != 0 {
-		return cap_str(plural_item_name(int(i), 1))
+	if i := e.noble_item(n); i != 0 {
+		return cap_str(e.plural_item_name(int(i), 1))
 	}
 
-	sk := subkind(n)
+	sk := e.subkind(n)
 	if int(sk) < len(subkind_s) {
 		return cap_str(subkind_s[sk])
 	}
@@ -268,12 +268,12 @@ func display_name(n int) string {
 }
 
 // display_kind returns the kind description for entity n.
-func display_kind(n int) string {
-	sk := subkind(n)
+func (e *Engine) display_kind(n int) string {
+	sk := e.subkind(n)
 
 	switch sk {
 	case sub_city:
-		if is_port_city(n) {
+		if e.is_port_city(n) {
 			return "port city"
 		}
 		return "city"
@@ -295,252 +295,260 @@ func box_code_less(n int) string {
 }
 
 // box_code returns the code for entity n with brackets.
-func box_code(n int) string {
-	if n == teg.globals.garrison_magic {
+func (e *Engine) box_code(n int) string {
+	if n == e.globals.garrison_magic {
 		return "Garrison"
 	}
 	return fmt.Sprintf("[%s]", int_to_code(n))
 }
 
 // box_name returns the display name and code for entity n.
-func box_name(n int) string {
-	if n == teg.globals.garrison_magic {
+func (e *Engine) box_name(n int) string {
+	if n == e.globals.garrison_magic {
 		return "Garrison"
 	}
 
-	if valid_box(n) {
-		s := display_name(n)
+	if e.valid_box(n) {
+		s := e.display_name(n)
 		if s != "" {
-			return fmt.Sprintf("%s~%s", s, box_code(n))
+			return fmt.Sprintf("%s~%s", s, e.box_code(n))
 		}
 	}
 
-	return box_code(n)
+	return e.box_code(n)
 }
 
 // just_name returns just the display name, or the code if no name.
-func just_name(n int) string {
-	if n == teg.globals.garrison_magic {
+func (e *Engine) just_name(n int) string {
+	if n == e.globals.garrison_magic {
 		return "Garrison"
 	}
 
-	if valid_box(n) {
-		s := display_name(n)
+	if e.valid_box(n) {
+		s := e.display_name(n)
 		if s != "" {
 			return s
 		}
 	}
 
-	return box_code(n)
+	return e.box_code(n)
 }
 
 // plural_item_name returns the plural name for an item.
-func plural_item_name(item, qty int) string {
+func (e *Engine) plural_item_name(item, qty int) string {
 	if qty == 1 {
-		return display_name(item)
+		return e.display_name(item)
 	}
 
-	s := teg.getPluralName(item)
+	s := e.getPluralName(item)
 	if s != "" {
 		return s
 	}
 
 	// Fall back to singular name
-	return display_name(item)
+	return e.display_name(item)
 }
 
 // plural_item_box returns the plural name with box code.
-func plural_item_box(item, qty int) string {
+func (e *Engine) plural_item_box(item, qty int) string {
 	if qty == 1 {
-		return box_name(item)
+		return e.box_name(item)
 	}
 
-	s := plural_item_name(item, qty)
-	return fmt.Sprintf("%s~%s", s, box_code(item))
+	s := e.plural_item_name(item, qty)
+	return fmt.Sprintf("%s~%s", s, e.box_code(item))
 }
 
 // just_name_qty returns quantity and plural item name.
-func just_name_qty(item, qty int) string {
-	return fmt.Sprintf("%s~%s", nice_num(qty), plural_item_name(item, qty))
+func (e *Engine) just_name_qty(item, qty int) string {
+	return fmt.Sprintf("%s~%s", nice_num(qty), e.plural_item_name(item, qty))
 }
 
 // box_name_qty returns quantity and plural item box name.
-func box_name_qty(item, qty int) string {
-	return fmt.Sprintf("%s~%s", nice_num(qty), plural_item_box(item, qty))
+func (e *Engine) box_name_qty(item, qty int) string {
+	return fmt.Sprintf("%s~%s", nice_num(qty), e.plural_item_box(item, qty))
 }
 
 // box_name_kind returns box name with kind description.
-func box_name_kind(n int) string {
-	return fmt.Sprintf("%s, %s", box_name(n), display_kind(n))
+func (e *Engine) box_name_kind(n int) string {
+	return fmt.Sprintf("%s, %s", e.box_name(n), e.display_kind(n))
 }
 
 // Entity allocation and chain management functions
 
 // add_next_chain adds entity n to the kind chain.
-func add_next_chain(n int) {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) add_next_chain(n int) {
+	if e.globals.bx.get(n) == nil {
 		return
 	}
-	k := int(teg.globals.bx[n].kind)
+	k := int(e.globals.bx.get(n).kind)
 	if k == 0 {
 		return
 	}
 
 	// Find insertion point (keep sorted by ID)
-	if teg.globals.box_head[k] == 0 {
-		teg.globals.box_head[k] = n
-		teg.globals.bx[n].x_next_kind = 0
+	if e.globals.box_head[k] == 0 {
+		e.globals.box_head[k] = n
+		e.globals.bx.get(n).x_next_kind = 0
 		return
 	}
 
-	if n < teg.globals.box_head[k] {
-		teg.globals.bx[n].x_next_kind = teg.globals.box_head[k]
-		teg.globals.box_head[k] = n
+	if n < e.globals.box_head[k] {
+		e.globals.bx.get(n).x_next_kind = e.globals.box_head[k]
+		e.globals.box_head[k] = n
 		return
 	}
 
-	i := teg.globals.box_head[k]
-	for teg.globals.bx[i].x_next_kind > 0 && teg.globals.bx[i].x_next_kind < n {
-		i = teg.globals.bx[i].x_next_kind
+	i := e.globals.box_head[k]
+	for e.globals.bx.get(i).x_next_kind > 0 && e.globals.bx.get(i).x_next_kind < n {
+		i = e.globals.bx.get(i).x_next_kind
 	}
 
-	teg.globals.bx[n].x_next_kind = teg.globals.bx[i].x_next_kind
-	teg.globals.bx[i].x_next_kind = n
+	e.globals.bx.get(n).x_next_kind = e.globals.bx.get(i).x_next_kind
+	e.globals.bx.get(i).x_next_kind = n
 }
 
 // remove_next_chain removes entity n from the kind chain.
-func remove_next_chain(n int) {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) remove_next_chain(n int) {
+	if e.globals.bx.get(n) == nil {
 		return
 	}
 
-	k := int(teg.globals.bx[n].kind)
-	i := teg.globals.box_head[k]
+	k := int(e.globals.bx.get(n).kind)
+	i := e.globals.box_head[k]
 
 	if i == n {
-		teg.globals.box_head[k] = teg.globals.bx[n].x_next_kind
+		e.globals.box_head[k] = e.globals.bx.get(n).x_next_kind
 	} else {
-		for i > 0 && teg.globals.bx[i] != nil && teg.globals.bx[i].x_next_kind != n {
-			i = teg.globals.bx[i].x_next_kind
+		for i > 0 && e.globals.bx.get(i) != nil && e.globals.bx.get(i).x_next_kind != n {
+			i = e.globals.bx.get(i).x_next_kind
 		}
-		if i > 0 && teg.globals.bx[i] != nil {
-			teg.globals.bx[i].x_next_kind = teg.globals.bx[n].x_next_kind
+		if i > 0 && e.globals.bx.get(i) != nil {
+			e.globals.bx.get(i).x_next_kind = e.globals.bx.get(n).x_next_kind
 		}
 	}
 
-	teg.globals.bx[n].x_next_kind = 0
+	e.globals.bx.get(n).x_next_kind = 0
 }
 
 // add_sub_chain adds entity n to the subkind chain.
-func add_sub_chain(n int) {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) add_sub_chain(n int) {
+	if e.globals.bx.get(n) == nil {
 		return
 	}
-	sk := int(teg.globals.bx[n].skind)
+	sk := int(e.globals.bx.get(n).skind)
 
 	// Find insertion point (keep sorted by ID)
-	if teg.globals.sub_head[sk] == 0 {
-		teg.globals.sub_head[sk] = n
-		teg.globals.bx[n].x_next_sub = 0
+	if e.globals.sub_head[sk] == 0 {
+		e.globals.sub_head[sk] = n
+		e.globals.bx.get(n).x_next_sub = 0
 		return
 	}
 
-	if n < teg.globals.sub_head[sk] {
-		teg.globals.bx[n].x_next_sub = teg.globals.sub_head[sk]
-		teg.globals.sub_head[sk] = n
+	if n < e.globals.sub_head[sk] {
+		e.globals.bx.get(n).x_next_sub = e.globals.sub_head[sk]
+		e.globals.sub_head[sk] = n
 		return
 	}
 
-	i := teg.globals.sub_head[sk]
-	for teg.globals.bx[i].x_next_sub > 0 && teg.globals.bx[i].x_next_sub < n {
-		i = teg.globals.bx[i].x_next_sub
+	i := e.globals.sub_head[sk]
+	for e.globals.bx.get(i).x_next_sub > 0 && e.globals.bx.get(i).x_next_sub < n {
+		i = e.globals.bx.get(i).x_next_sub
 	}
 
-	teg.globals.bx[n].x_next_sub = teg.globals.bx[i].x_next_sub
-	teg.globals.bx[i].x_next_sub = n
+	e.globals.bx.get(n).x_next_sub = e.globals.bx.get(i).x_next_sub
+	e.globals.bx.get(i).x_next_sub = n
 }
 
 // remove_sub_chain removes entity n from the subkind chain.
-func remove_sub_chain(n int) {
-	if teg.globals.bx[n] == nil {
+func (e *Engine) remove_sub_chain(n int) {
+	if e.globals.bx.get(n) == nil {
 		return
 	}
 
-	sk := int(teg.globals.bx[n].skind)
-	i := teg.globals.sub_head[sk]
+	sk := int(e.globals.bx.get(n).skind)
+	i := e.globals.sub_head[sk]
 
 	if i == n {
-		teg.globals.sub_head[sk] = teg.globals.bx[n].x_next_sub
+		e.globals.sub_head[sk] = e.globals.bx.get(n).x_next_sub
 	} else {
-		for i > 0 && teg.globals.bx[i] != nil && teg.globals.bx[i].x_next_sub != n {
-			i = teg.globals.bx[i].x_next_sub
+		for i > 0 && e.globals.bx.get(i) != nil && e.globals.bx.get(i).x_next_sub != n {
+			i = e.globals.bx.get(i).x_next_sub
 		}
-		if i > 0 && teg.globals.bx[i] != nil {
-			teg.globals.bx[i].x_next_sub = teg.globals.bx[n].x_next_sub
+		if i > 0 && e.globals.bx.get(i) != nil {
+			e.globals.bx.get(i).x_next_sub = e.globals.bx.get(n).x_next_sub
 		}
 	}
 
-	teg.globals.bx[n].x_next_sub = 0
+	e.globals.bx.get(n).x_next_sub = 0
 }
 
 // delete_box marks an entity as deleted.
-func delete_box(n int) {
-	remove_next_chain(n)
-	remove_sub_chain(n)
-	teg.globals.bx[n].kind = T_deleted
+func (e *Engine) delete_box(n int) {
+	e.remove_next_chain(n)
+	e.remove_sub_chain(n)
+	e.globals.bx.get(n).kind = T_deleted
+	e.markDirty(n)
 }
 
 // change_box_kind changes the kind of entity n.
-func change_box_kind(n int, k schar) {
-	remove_next_chain(n)
-	teg.globals.bx[n].kind = k
-	add_next_chain(n)
+func (e *Engine) change_box_kind(n int, k schar) {
+	e.remove_next_chain(n)
+	e.globals.bx.get(n).kind = k
+	e.markDirty(n)
+	e.add_next_chain(n)
 }
 
 // change_box_subkind changes the subkind of entity n.
-func change_box_subkind(n int, sk schar) {
-	if subkind(n) == sk {
+func (e *Engine) change_box_subkind(n int, sk schar) {
+	if e.subkind(n) == sk {
 		return
 	}
-	remove_sub_chain(n)
-	teg.globals.bx[n].skind = sk
-	add_sub_chain(n)
+	e.remove_sub_chain(n)
+	e.globals.bx.get(n).skind = sk
+	e.markDirty(n)
+	e.add_sub_chain(n)
 }
 
 // alloc_box allocates a new box at position n with given kind and subkind.
-func alloc_box(n int, k, sk schar) {
+func (e *Engine) alloc_box(n int, k, sk schar) {
 	if n <= 0 || n >= MAX_BOXES {
 		logger.Error("alloc_box", "invalid box id", n)
 		panic(fmt.Sprintf("alloc_box: invalid box ID %d", n))
 	}
-	if teg.globals.bx[n] != nil {
+	if e.globals.bx.get(n) != nil {
 		logger.Error("alloc_box", "duplicate box id", n)
 		panic(fmt.Sprintf("alloc_box: DUP box %d", n))
 	}
 
-	teg.globals.bx[n] = &box{
+	e.setBox(n, &box{
 		kind:  k,
 		skind: sk,
-	}
-	add_next_chain(n)
-	add_sub_chain(n)
+	})
+	e.add_next_chain(n)
+	e.add_sub_chain(n)
 }
 
 // rnd_alloc_num finds a random unallocated box ID in range [low, high].
 // Returns -1 if no free slot is found.
-func rnd_alloc_num(low, high int) int {
-	n := rnd(low, high)
+func (e *Engine) rnd_alloc_num(low, high int) int {
+	return e.rnd_alloc_from(streamSeeding, low, high)
+}
+
+// rnd_alloc_from is rnd_alloc_num drawing from the named stream.
+func (e *Engine) rnd_alloc_from(stream string, low, high int) int {
+	n := e.rndFrom(stream, low, high)
 
 	// Search from n to high
 	for i := n; i <= high; i++ {
-		if teg.globals.bx[i] == nil {
+		if e.globals.bx.get(i) == nil {
 			return i
 		}
 	}
 
 	// Search from low to n-1
 	for i := low; i < n; i++ {
-		if teg.globals.bx[i] == nil {
+		if e.globals.bx.get(i) == nil {
 			return i
 		}
 	}
@@ -550,24 +558,24 @@ func rnd_alloc_num(low, high int) int {
 
 // new_ent allocates a new entity of the given kind and subkind.
 // Returns the entity ID, or panics if no space available.
-func new_ent(k, sk schar) int {
+func (e *Engine) new_ent(k, sk schar) int {
 	var n int = -1
 
 	switch k {
 	case T_player:
-		n = rnd_alloc_num(50_000, 56_759)
+		n = e.rnd_alloc_num(50_000, 56_759)
 
 	case T_char, T_unform:
 		if sk == sub_ni {
-			n = rnd_alloc_num(79_000, MAX_BOXES-1)
+			n = e.rnd_alloc_num(79_000, MAX_BOXES-1)
 		} else {
-			n = rnd_alloc_num(1_000, 9_999)
+			n = e.rnd_alloc_num(1_000, 9_999)
 		}
 		if n < 0 {
-			n = rnd_alloc_num(59_000, 78_999)
+			n = e.rnd_alloc_num(59_000, 78_999)
 		}
 		if n < 0 {
-			n = rnd_alloc_num(79_000, MAX_BOXES-1)
+			n = e.rnd_alloc_num(79_000, MAX_BOXES-1)
 		}
 
 	case T_skill:
@@ -576,50 +584,50 @@ func new_ent(k, sk schar) int {
 	case T_loc:
 		switch sk {
 		case sub_city:
-			n = rnd_alloc_num(56_760, 58_759)
+			n = e.rnd_alloc_num(56_760, 58_759)
 		case sub_region:
-			n = rnd_alloc_num(58_760, 58_999)
+			n = e.rnd_alloc_num(58_760, 58_999)
 		case sub_under, sub_forest, sub_ocean, sub_cloud, sub_tunnel:
-			n = rnd_alloc_num(20_000, 49_999)
+			n = e.rnd_alloc_num(20_000, 49_999)
 		default:
-			n = rnd_alloc_num(59_000, 78_999)
+			n = e.rnd_alloc_num(59_000, 78_999)
 		}
 
 	case T_storm:
-		n = rnd_alloc_num(79_000, MAX_BOXES-1)
+		n = e.rnd_alloc_from(streamWeather, 79_000, MAX_BOXES-1)
 
 	default:
-		n = rnd_alloc_num(59_000, 78_999)
+		n = e.rnd_alloc_num(59_000, 78_999)
 	}
 
 	if n < 0 {
-		n = rnd_alloc_num(79_000, MAX_BOXES-1)
+		n = e.rnd_alloc_num(79_000, MAX_BOXES-1)
 	}
 
 	if n < 0 {
 		panic("new_ent: out of entity space")
 	}
 
-	alloc_box(n, k, sk)
+	e.alloc_box(n, k, sk)
 	return n
 }
 
 // print_box_usage prints entity space usage statistics.
-func print_box_usage() {
+func (e *Engine) print_box_usage() {
 	fmt.Println("entity space usage:")
-	print_box_usage_sup(1_000, 9_999, "char")
-	print_box_usage_sup(20_000, 59_999, "locs")
-	print_box_usage_sup(50_000, 56_759, "play")
-	print_box_usage_sup(56_760, 58_759, "lloc")
-	print_box_usage_sup(59_000, 78_999, "subl")
-	print_box_usage_sup(79_000, MAX_BOXES-1, "rest")
+	e.print_box_usage_sup(1_000, 9_999, "char")
+	e.print_box_usage_sup(20_000, 59_999, "locs")
+	e.print_box_usage_sup(50_000, 56_759, "play")
+	e.print_box_usage_sup(56_760, 58_759, "lloc")
+	e.print_box_usage_sup(59_000, 78_999, "subl")
+	e.print_box_usage_sup(79_000, MAX_BOXES-1, "rest")
 }
 
 // print_box_usage_sup prints usage for a specific range.
-func print_box_usage_sup(low, high int, label string) {
+func (e *Engine) print_box_usage_sup(low, high int, label string) {
 	used := 0
 	for i := low; i <= high; i++ {
-		if teg.globals.bx[i] != nil {
+		if e.globals.bx.get(i) != nil {
 			used++
 		}
 	}
@@ -664,13 +672,3 @@ func cap_str(s string) string {
 func nice_num(n int) string {
 	return nice_num_words(n)
 }
-
-// is_port_city returns true if the location is a port city.
-// Stub implementation - will be completed when loc.c is ported.
-func is_port_city(n int) bool {
-	// TODO: implement when loc.c is ported
-	logger.Error("is_port_city",
-		"todo", "implement when `loc.c` is ported",
-	)
-	return false
-}
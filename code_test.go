@@ -222,14 +222,14 @@ func TestScode(t *testing.T) {
 }
 
 func TestBoxCode(t *testing.T) {
-	got := box_code(10000)
+	got := teg.box_code(10000)
 	want := "[aa00]"
 	if got != want {
 		t.Errorf("box_code(10000) = %q, want %q", got, want)
 	}
 
 	// Test garrison_magic special case
-	got = box_code(teg.globals.garrison_magic)
+	got = teg.box_code(teg.globals.garrison_magic)
 	want = "Garrison"
 	if got != want {
 		t.Errorf("box_code(garrison_magic) = %q, want %q", got, want)
@@ -247,27 +247,27 @@ func TestBoxCodeLess(t *testing.T) {
 func TestNameSetName(t *testing.T) {
 	// Allocate a test entity
 	testID := 1000
-	teg.globals.bx[testID] = &box{kind: T_char, skind: 0}
+	teg.setBox(testID, &box{kind: T_char, skind: 0})
 
 	// Initially no name
-	if n := name(testID); n != "" {
+	if n := teg.name(testID); n != "" {
 		t.Errorf("name(%d) = %q, want empty string", testID, n)
 	}
 
 	// Set a name
-	set_name(testID, "Test Character")
-	if n := name(testID); n != "Test Character" {
+	teg.set_name(testID, "Test Character")
+	if n := teg.name(testID); n != "Test Character" {
 		t.Errorf("name(%d) = %q, want %q", testID, n, "Test Character")
 	}
 
 	// Brackets should be replaced with braces
-	set_name(testID, "Char [with] brackets")
-	if n := name(testID); n != "Char {with} brackets" {
+	teg.set_name(testID, "Char [with] brackets")
+	if n := teg.name(testID); n != "Char {with} brackets" {
 		t.Errorf("name(%d) = %q, want %q", testID, n, "Char {with} brackets")
 	}
 
 	// Clean up
-	teg.globals.bx[testID] = nil
+	teg.deleteBox(testID)
 	delete(teg.globals.names, testID)
 }
 
@@ -329,25 +329,25 @@ func TestAllocBoxDeleteBox(t *testing.T) {
 	testID := 1001
 
 	// Ensure clean state
-	teg.globals.bx[testID] = nil
+	teg.deleteBox(testID)
 
 	// Allocate
-	alloc_box(testID, T_char, 0)
-	if teg.globals.bx[testID] == nil {
+	teg.alloc_box(testID, T_char, 0)
+	if teg.globals.bx.get(testID) == nil {
 		t.Fatalf("alloc_box(%d) did not create box", testID)
 	}
-	if kind(testID) != T_char {
-		t.Errorf("kind(%d) = %d, want %d", testID, kind(testID), T_char)
+	if teg.kind(testID) != T_char {
+		t.Errorf("kind(%d) = %d, want %d", testID, teg.kind(testID), T_char)
 	}
 
 	// Delete
-	delete_box(testID)
-	if kind(testID) != T_deleted {
-		t.Errorf("after delete, kind(%d) = %d, want %d", testID, kind(testID), T_deleted)
+	teg.delete_box(testID)
+	if teg.kind(testID) != T_deleted {
+		t.Errorf("after delete, kind(%d) = %d, want %d", testID, teg.kind(testID), T_deleted)
 	}
 
 	// Clean up
-	teg.globals.bx[testID] = nil
+	teg.deleteBox(testID)
 }
 
 func TestChangeBoxKind(t *testing.T) {
@@ -362,38 +362,38 @@ func TestChangeBoxKind(t *testing.T) {
 	}
 
 	// Allocate as char
-	teg.globals.bx[testID] = nil
-	alloc_box(testID, T_char, 0)
+	teg.deleteBox(testID)
+	teg.alloc_box(testID, T_char, 0)
 
 	// Change to player
-	change_box_kind(testID, T_player)
-	if kind(testID) != T_player {
-		t.Errorf("after change, kind(%d) = %d, want %d", testID, kind(testID), T_player)
+	teg.change_box_kind(testID, T_player)
+	if teg.kind(testID) != T_player {
+		t.Errorf("after change, kind(%d) = %d, want %d", testID, teg.kind(testID), T_player)
 	}
 
 	// Clean up
-	teg.globals.bx[testID] = nil
+	teg.deleteBox(testID)
 }
 
 func TestChangeBoxSubkind(t *testing.T) {
 	testID := 1003
 
 	// Allocate
-	teg.globals.bx[testID] = nil
-	alloc_box(testID, T_loc, sub_forest)
+	teg.deleteBox(testID)
+	teg.alloc_box(testID, T_loc, sub_forest)
 
 	// Change subkind
-	change_box_subkind(testID, sub_mountain)
-	if subkind(testID) != sub_mountain {
-		t.Errorf("after change, subkind(%d) = %d, want %d", testID, subkind(testID), sub_mountain)
+	teg.change_box_subkind(testID, sub_mountain)
+	if teg.subkind(testID) != sub_mountain {
+		t.Errorf("after change, subkind(%d) = %d, want %d", testID, teg.subkind(testID), sub_mountain)
 	}
 
 	// No change if same
-	change_box_subkind(testID, sub_mountain)
-	if subkind(testID) != sub_mountain {
-		t.Errorf("after no-op change, subkind(%d) = %d, want %d", testID, subkind(testID), sub_mountain)
+	teg.change_box_subkind(testID, sub_mountain)
+	if teg.subkind(testID) != sub_mountain {
+		t.Errorf("after no-op change, subkind(%d) = %d, want %d", testID, teg.subkind(testID), sub_mountain)
 	}
 
 	// Clean up
-	teg.globals.bx[testID] = nil
+	teg.deleteBox(testID)
 }
@@ -0,0 +1,152 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// combat.go - Combat helpers for artifacts ported from src/combat.c
+//
+// The battle engine itself is not ported yet. These are the pieces
+// that depend on item magic: what a character wields and wears, and
+// monsters only defeatable by a rare artifact.
+
+package taygete
+
+import "fmt"
+
+// wield records the items a character fights with: an attack weapon,
+// a missile weapon and some sort of defensive garment.
+type wield struct {
+	attack  int
+	defense int
+	missile int
+}
+
+// cannot_take_prisoners reports whether who's side may not take
+// prisoners after a win.
+// Ported from src/combat.c lines 76-92.
+func (e *Engine) cannot_take_prisoners(who int) bool {
+	if e.only_defeatable(who) != 0 {
+		return true
+	}
+
+	if e.subkind(who) == sub_garrison {
+		return true
+	}
+
+	return false
+}
+
+// cannot_take_booty reports whether who's side may not loot the losers.
+// Ported from src/combat.c lines 95-108.
+func (e *Engine) cannot_take_booty(who int) bool {
+	return e.subkind(who) == sub_garrison
+}
+
+// find_wield determines what who is wielding and wearing: the items
+// with the best attack, defense and missile bonuses. w may be nil.
+// Returns true if the character is wearing or wielding something.
+// Ported from src/combat.c lines 412-466.
+func (e *Engine) find_wield(w *wield, who int) bool {
+	if w == nil {
+		w = &wield{}
+	}
+	*w = wield{}
+
+	attack_max, defense_max, missile_max := -1, -1, -1
+
+	for _, it := range e.globals.inventories[who] {
+		if n := int(e.item_attack_bonus(it.item)); n != 0 && n > attack_max {
+			attack_max = n
+			w.attack = it.item
+		}
+
+		if n := int(e.item_defense_bonus(it.item)); n != 0 && n > defense_max {
+			defense_max = n
+			w.defense = it.item
+		}
+
+		if n := int(e.item_missile_bonus(it.item)); n != 0 && n > missile_max {
+			missile_max = n
+			w.missile = it.item
+		}
+	}
+
+	return w.attack != 0 || w.defense != 0 || w.missile != 0
+}
+
+// wield_bonus returns the attack, defense and missile bonuses who
+// gets in battle from the items found by find_wield.
+// Port of the fight-struct branch of C find_wield() from combat.c.
+func (e *Engine) wield_bonus(who int) (attack, defense, missile int) {
+	var w wield
+	if !e.find_wield(&w, who) {
+		return 0, 0, 0
+	}
+
+	if w.attack != 0 {
+		attack = int(e.item_attack_bonus(w.attack))
+	}
+	if w.defense != 0 {
+		defense = int(e.item_defense_bonus(w.defense))
+	}
+	if w.missile != 0 {
+		missile = int(e.item_missile_bonus(w.missile))
+	}
+
+	return attack, defense, missile
+}
+
+// wield_s describes what who is wielding and wearing, for reports.
+// Ported from src/combat.c lines 469-511.
+func (e *Engine) wield_s(who int) string {
+	var w wield
+	if !e.find_wield(&w, who) {
+		return ""
+	}
+
+	// Clear out multiple copies of the same item. This would happen
+	// if one weapon had multiple bonuses.
+	if w.attack == w.missile {
+		w.missile = 0
+	}
+	if w.attack == w.defense {
+		w.defense = 0
+	}
+
+	var buf string
+
+	switch {
+	case w.attack == 0 && w.missile != 0:
+		buf = fmt.Sprintf(", wielding %s", e.box_name(w.missile))
+	case w.attack != 0 && w.missile == 0:
+		buf = fmt.Sprintf(", wielding %s", e.box_name(w.attack))
+	case w.attack != 0 && w.missile != 0:
+		buf = fmt.Sprintf(", wielding %s and %s", e.box_name(w.attack), e.box_name(w.missile))
+	}
+
+	if w.defense != 0 {
+		buf += fmt.Sprintf(", wearing %s", e.box_name(w.defense))
+	}
+
+	return buf
+}
+
+// may_defeat reports whether who can defeat target. A monster guarding
+// a rare artifact may only be defeated by one who possesses it.
+// Port of the only_defeatable checks in C fail_defeat_check() and the
+// auto-attack target scan from combat.c.
+func (e *Engine) may_defeat(who, target int) bool {
+	n := e.only_defeatable(target)
+	return n == 0 || e.has_item(who, n) > 0
+}
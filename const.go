@@ -17,6 +17,8 @@
 package taygete
 
 const RAND = 1
+
+const CHAR_FIELD = 6 // field length for box_code_less
 const LAND = 1
 const WATER = 2
 
@@ -16,6 +16,11 @@
 
 package taygete
 
+import (
+	"fmt"
+	"slices"
+)
+
 // day.c -- turn processing: process_orders and post_month
 //
 // This file ports the turn processing logic from day.c and input.c.
@@ -36,6 +41,9 @@ func (e *Engine) ProcessOrders() error {
 	e.initLocsTouched()
 	e.initWeatherViews()
 	e.olytimeTurnChange()
+	e.clearExpThisMonth()
+	e.clearAdmitFlags()
+	e.clearGoldStats()
 
 	// Initialize processing lists
 	e.initWaitList()
@@ -52,8 +60,14 @@ func (e *Engine) ProcessOrders() error {
 
 	e.stage("")
 
-	// Daily loop: process each day of the month
-	for e.globals.sysclock.day < MONTH_DAYS {
+	return e.runDays(MONTH_DAYS)
+}
+
+// runDays runs the days of the month after the one on the clock, up to
+// and including last. Each day is saved when it ends so a crashed turn
+// resumes from it; see checkpointDay.
+func (e *Engine) runDays(last int) error {
+	for int(e.globals.sysclock.day) < last {
 		e.olytimeIncrement()
 
 		if e.globals.sysclock.day == 1 {
@@ -62,9 +76,13 @@ func (e *Engine) ProcessOrders() error {
 
 		e.dailyCommandLoop()
 		e.dailyEvents()
+
+		if err := e.checkpointDay(); err != nil {
+			return err
+		}
 	}
 
-	e.globals.monthDone = true
+	e.globals.monthDone = e.globals.sysclock.day >= MONTH_DAYS
 	return nil
 }
 
@@ -134,13 +152,60 @@ func (e *Engine) PostMonth() error {
 	return nil
 }
 
-// RunTurn executes a complete turn: process orders then post-month cleanup.
+// RunTurn executes a complete turn: snapshot the world for rollback,
+// process orders, post-month cleanup, then the turn reports. The
+// outcome is recorded for ReplayTurn.
 // This is a convenience method that combines ProcessOrders and PostMonth.
+//
+// A turn left unfinished by a crash, with the world loaded as the last
+// day saved, picks up from the next day instead; see resumeTurn.
 func (e *Engine) RunTurn() error {
-	if err := e.ProcessOrders(); err != nil {
+	turn := int(e.globals.sysclock.turn)
+	resumed, err := e.resumeTurn()
+	if err != nil {
+		return err
+	}
+	if resumed {
+		// The clock moved on when the turn started
+		turn--
+		if err := e.runDays(MONTH_DAYS); err != nil {
+			return err
+		}
+	} else {
+		if err := e.snapshotTurn(); err != nil {
+			return err
+		}
+		if err := e.startTurn(turn); err != nil {
+			return err
+		}
+		if err := e.ProcessOrders(); err != nil {
+			return err
+		}
+	}
+	if err := e.PostMonth(); err != nil {
 		return err
 	}
-	return e.PostMonth()
+
+	e.playerEntInfo()
+	e.scanCharSkillLore()
+	e.showLoreSheets()
+	e.gmReport(gm_player)
+	e.gmShowAllSkills(skill_player)
+
+	// The reports are cleared once saved
+	outcome := e.worldOutcome()
+	if err := e.saveReports(); err != nil {
+		return err
+	}
+	if e.db != nil {
+		if err := e.savePrngStreams(); err != nil {
+			return err
+		}
+	}
+	if err := e.recordOutcome(turn, outcome); err != nil {
+		return err
+	}
+	return e.finishTurn(turn)
 }
 
 // stage logs the current processing stage (for debugging/progress tracking).
@@ -178,19 +243,23 @@ func (e *Engine) olytimeIncrement() {
 func (e *Engine) initLocsTouched()  {} // stub
 func (e *Engine) initWeatherViews() {} // stub
 func (e *Engine) initWaitList()     {} // stub
-func (e *Engine) initCollectList()  {} // stub
 
 // initialCommandLoad loads initial commands for all characters and players.
 // Port of C initial_command_load() from input.c.
 func (e *Engine) initialCommandLoad() {
 	e.initialCommandLoadImpl()
 }
+
+// checkTokenUnits replaces or melts the units controlled by npc tokens.
+// Port of C check_token_units() from art.c.
+func (e *Engine) checkTokenUnits() {
+	e.check_token_units()
+}
+
 func (e *Engine) queueNpcOrders()          {} // stub
 func (e *Engine) pingGarrisons()           {} // stub
-func (e *Engine) checkTokenUnits()         {} // stub
 func (e *Engine) processInterruptedUnits() {} // stub
 func (e *Engine) processPlayerOrders()     {} // stub
-func (e *Engine) scanCharItemLore()        {} // stub
 func (e *Engine) matchAllTrades()          {} // stub
 
 // dailyCommandLoop runs the command processing loop for one day.
@@ -206,41 +275,665 @@ func (e *Engine) dailyCommandLoop() {
 	e.evening_phase()
 }
 
-func (e *Engine) dailyEvents() {} // stub
+// weatherDay reports whether natural storms form today. The four days
+// of the month come from a weather stream of their own for the turn, so
+// a turn resumed from a day checkpoint picks the same days.
+// Ported from daily_events() in src/day.c lines 1809-1866.
+func (e *Engine) weatherDay() bool {
+	if e.globals.weather_turn != e.globals.sysclock.turn || e.globals.weather_days == nil {
+		r := deriveStream(e.seed, fmt.Sprintf("%s.%d", streamWeather, e.globals.sysclock.turn))
+		days := make([]int, MONTH_DAYS)
+		for i := range days {
+			days[i] = i + 1
+		}
+		r.Shuffle(len(days), func(i, j int) { days[i], days[j] = days[j], days[i] })
+		e.globals.weather_days = days[:4]
+		e.globals.weather_turn = e.globals.sysclock.turn
+		slices.Sort(e.globals.weather_days)
+	}
+	return slices.Contains(e.globals.weather_days, int(e.globals.sysclock.day))
+}
+
+// stormDecay weakens every storm by one and dissipates the spent ones.
+// Ported from storm_decay() in src/day.c.
+func (e *Engine) stormDecay() {
+	for _, i := range e.Storms() {
+		p := e.p_misc(i)
+
+		p.storm_str--
+		if p.storm_str > 0 {
+			continue
+		}
+
+		p.storm_str = 0
+		e.dissipate_storm(i, true)
+	}
+}
+
+// stormMove moves every directed storm to the province it was directed
+// to, letting its summoner know the place.
+// Ported from storm_move() in src/day.c.
+func (e *Engine) stormMove() {
+	for _, i := range e.Storms() {
+		p := e.p_misc(i)
+
+		if p.npc_dir == 0 {
+			continue
+		}
+
+		if e.loc_depth(p.storm_move) != LOC_province {
+			panic("stormMove: storm not directed to a province")
+		}
+
+		e.set_where(i, p.storm_move)
+
+		if owner := e.npc_summoner(i); owner != 0 && e.valid_box(owner) {
+			e.set_known(owner, p.storm_move)
+		}
+
+		p.npc_dir = 0
+		p.storm_move = 0
+	}
+}
+
+// dailyEvents runs the world's events for the day.
+// Ported from src/day.c lines 1809-1866; only natural weather so far.
+func (e *Engine) dailyEvents() {
+	if e.weatherDay() {
+		e.natural_weather()
+	}
+}
 
 // Stubbed handlers for PostMonth
 // These will be fully implemented in later sprints.
 
 func (e *Engine) clearOrdersSent()           {} // stub
-func (e *Engine) specialLocsOpen()           {} // stub
-func (e *Engine) specialLocsClose()          {} // stub
-func (e *Engine) moveCityGold()              {} // stub
 func (e *Engine) garrisonGold()              {} // stub
 func (e *Engine) collectTaxes()              {} // stub
-func (e *Engine) addClaimGold()              {} // stub
-func (e *Engine) addNoblePoints()            {} // stub
-func (e *Engine) addUnformed()               {} // stub
-func (e *Engine) incrementCurrentAura()      {} // stub
 func (e *Engine) decrementAbilityShroud()    {} // stub
 func (e *Engine) decrementRegionShroud()     {} // stub
 func (e *Engine) decrementMeditationHinder() {} // stub
 func (e *Engine) decrementLocBarrier()       {} // stub
 func (e *Engine) loyaltyDecay()              {} // stub
 func (e *Engine) pillageDecay()              {} // stub
-func (e *Engine) relicDecay()                {} // stub
 func (e *Engine) hideMageDecay()             {} // stub
-func (e *Engine) innIncome()                 {} // stub
-func (e *Engine) templeIncome()              {} // stub
-func (e *Engine) chargeMaintCosts()          {} // stub
-func (e *Engine) animalDeaths()              {} // stub
-func (e *Engine) ghostWarriorDecay()         {} // stub
-func (e *Engine) corpseDecay()               {} // stub
-func (e *Engine) deadBodyRot()               {} // stub
-func (e *Engine) stormDecay()                {} // stub
-func (e *Engine) stormMove()                 {} // stub
 func (e *Engine) collapsedMineDecay()        {} // stub
 func (e *Engine) postProduction()            {} // stub
-func (e *Engine) autoDrop()                  {} // stub
-func (e *Engine) linkDecay()                 {} // stub
-func (e *Engine) questDecay()                {} // stub
 func (e *Engine) determineNobleRanks()       {} // stub
+
+// addNoblePoints grants each regular player a noble point on the
+// turns next_np_turn schedules.
+// Port of C add_noble_points() from day.c.
+func (e *Engine) addNoblePoints() {
+	for _, pl := range e.Players() {
+		if e.subkind(pl) != sub_pl_regular {
+			continue
+		}
+
+		if e.next_np_turn(pl) == 0 {
+			e.add_np(pl, 1)
+		}
+	}
+}
+
+// add_unformed_sup tops up pl's pool of unformed nobles to five.
+// Ported from src/day.c lines 419-437.
+func (e *Engine) add_unformed_sup(pl int) {
+	if e.rp_player(pl) == nil {
+		return
+	}
+
+	for len(e.getPlayerUnformed(pl)) < 5 {
+		n := e.new_ent(T_unform, 0)
+		if n <= 0 {
+			break
+		}
+
+		e.addPlayerUnformed(pl, n)
+	}
+}
+
+// addUnformed replenishes every player's unformed noble pool.
+// Port of C add_unformed() from day.c.
+func (e *Engine) addUnformed() {
+	for _, pl := range e.Players() {
+		e.add_unformed_sup(pl)
+	}
+}
+
+// autoDrop drops regular factions that haven't sent orders in
+// autoQuitTurns turns. Their units are released to the independent
+// player rather than melting away.
+// Port of C auto_drop() from day.c, which queued a QUIT instead.
+func (e *Engine) autoDrop() {
+	for _, pl := range e.Players() {
+		if e.subkind(pl) != sub_pl_regular {
+			continue
+		}
+
+		p := e.p_player(pl)
+
+		if int(e.globals.sysclock.turn)-p.last_order_turn < e.globals.autoQuitTurns {
+			continue
+		}
+
+		log_write(LOG_SPECIAL, "Auto-dropping %s", e.box_name(pl))
+		log_write(LOG_SPECIAL, "    %s <%s>", p.full_name, p.email)
+
+		for _, who := range e.loop_units(pl) {
+			if !e.is_prisoner(who) {
+				e.unit_deserts(who, indep_player, false, LOY_UNCHANGED, 0)
+			}
+		}
+
+		e.drop_player(pl)
+	}
+}
+
+// Season  Month   Name
+// ------  -----   ----
+// Spring    1     Fierce winds
+// Spring    2     Snowmelt
+// Summer    3     Blossom bloom
+// Summer    4     Sunsear
+// Fall      5     Thunder and rain
+// Fall      6     Harvest
+// Winter    7     Waning days
+// Winter    8     Dark night
+//
+// Uldim pass and Summerbridge are open during months 3-4-5-6.  At the
+// end of month 2 the provinces hear they are open, and at the end of
+// month 6 that they have closed.
+
+// specialLocsOpen announces the opening of Uldim pass and
+// Summerbridge.
+// Port of C special_locs_open() from day.c.
+func (e *Engine) specialLocsOpen() {
+	for _, i := range e.Provinces() {
+		switch {
+		case e.summerbridge(i) == 1:
+			log_write(LOG_CODE, "%s open to the north.", e.box_name(i))
+			wout(i, "The swamps of Summerbridge have dried "+
+				"enough to permit passage north.")
+		case e.summerbridge(i) == 2:
+			log_write(LOG_CODE, "%s open to the south.", e.box_name(i))
+			wout(i, "The swamps of Summerbridge have dried "+
+				"enough to permit passage south.")
+		case e.uldim(i) == 3:
+			log_write(LOG_CODE, "%s open to the south.", e.box_name(i))
+			wout(i, "The snows blocking Uldim pass to the south "+
+				"have melted.")
+		case e.uldim(i) == 4:
+			log_write(LOG_CODE, "%s open to the north.", e.box_name(i))
+			wout(i, "The snows blocking Uldim pass to the north "+
+				"have melted.")
+		}
+	}
+}
+
+// specialLocsClose announces the closing of Uldim pass and
+// Summerbridge.
+// Port of C special_locs_close() from day.c.
+func (e *Engine) specialLocsClose() {
+	for _, i := range e.Provinces() {
+		switch {
+		case e.summerbridge(i) == 1:
+			log_write(LOG_CODE, "%s closed to the north.", e.box_name(i))
+			wout(i, "Seasonal rains have made Summerbridge an "+
+				"impassable bog to the north.")
+		case e.summerbridge(i) == 2:
+			log_write(LOG_CODE, "%s closed to the south.", e.box_name(i))
+			wout(i, "Seasonal rains have made Summerbridge an "+
+				"impassable bog to the south.")
+		case e.uldim(i) == 3:
+			log_write(LOG_CODE, "%s closed to the south.", e.box_name(i))
+			wout(i, "Falling snow blocks Uldim pass to the south "+
+				"for the winter.")
+		case e.uldim(i) == 4:
+			log_write(LOG_CODE, "%s closed to the north.", e.box_name(i))
+			wout(i, "Falling snow blocks Uldim pass to the north "+
+				"for the winter.")
+		}
+	}
+}
+
+// linkDecay closes linked sublocations a turn at a time, and opens
+// them again for two turns in their month. A link_open of -1 keeps a
+// link shut for good.
+// Port of C link_decay() from day.c.
+func (e *Engine) linkDecay() {
+	for _, i := range e.Locations() {
+		p := e.rp_subloc(i)
+
+		if p == nil || len(p.link_to) < 1 {
+			continue
+		}
+
+		e.markDirty(i)
+		if p.link_open > 0 {
+			p.link_open--
+		}
+
+		if int(p.link_when) == e.olyMonth() {
+			if p.link_open < 2 && p.link_open >= 0 {
+				p.link_open = 2
+			}
+		}
+	}
+}
+
+// moveCityGold moves the taxes collected in each city up to its
+// province.
+// Port of C move_city_gold() from day.c.
+func (e *Engine) moveCityGold() {
+	for _, i := range e.Cities() {
+		prov := e.province(i)
+		has := e.has_item(i, item_tax_cookie)
+
+		e.move_item(i, prov, item_tax_cookie, has)
+	}
+}
+
+// addClaimGold adds a month's 25 gold to each regular player's claim.
+// Port of C add_claim_gold() from day.c.
+func (e *Engine) addClaimGold() {
+	for _, pl := range e.Players() {
+		if e.subkind(pl) == sub_pl_regular {
+			e.gen_item(pl, item_gold, 25)
+		}
+	}
+}
+
+// maint_cost returns the monthly upkeep of one of item.
+// Ported from src/day.c lines 850-877.
+func maint_cost(item int) int {
+	switch item {
+	case item_peasant:
+		return 1
+
+	case item_worker, item_soldier, item_sailor, item_angry_peasant,
+		item_crossbowman:
+		return 2
+
+	case item_blessed_soldier, item_pikeman, item_swordsman, item_pirate,
+		item_archer:
+		return 3
+
+	case item_knight, item_elite_arch:
+		return 4
+
+	case item_elite_guard:
+		return 5
+	}
+
+	return 0
+}
+
+// men_starve pays what upkeep who can afford with have gold, one man
+// at a time across the unit, and loses a third of the unpaid men to
+// starvation and desertion.
+// Ported from src/day.c lines 880-981.
+func (e *Engine) men_starve(who, have int) {
+	var item, qty, cost, starve []int
+	nmen, npaid := 0, 0
+
+	for _, it := range slices.Clone(e.globals.inventories[who]) {
+		if n := maint_cost(it.item); n != 0 {
+			item = append(item, it.item)
+			qty = append(qty, it.qty)
+			cost = append(cost, n)
+			starve = append(starve, 0)
+
+			nmen += it.qty
+		}
+	}
+
+	gold := have
+
+	for hitOne := true; hitOne && have > 0; {
+		hitOne = false
+
+		for i := range item {
+			if qty[i] > 0 && have >= cost[i] {
+				have -= cost[i]
+				qty[i]--
+				npaid++
+				hitOne = true
+			}
+		}
+	}
+
+	gold -= have
+	nstarve := (nmen - npaid + 2) / 3
+
+	if nstarve <= 0 {
+		panic("men_starve: nobody to starve")
+	}
+
+	for i := 0; nstarve > 0; i = (i + 1) % len(item) {
+		if qty[i] != 0 {
+			nstarve--
+			starve[i]++
+			qty[i]--
+		}
+	}
+
+	e.autocharge(who, gold)
+
+	for i := range item {
+		if starve[i] == 0 {
+			continue
+		}
+
+		s := "starved"
+		if item[i] != item_peasant {
+			if e.rndFrom(streamUpkeep, 1, 2) == 1 {
+				s = "left service"
+			} else {
+				s = "deserted"
+			}
+		}
+
+		wout(who, "%s %s.", cap(e.just_name_qty(item[i], starve[i])), s)
+		e.consume_item(who, item[i], starve[i])
+
+		if item[i] == item_sailor || item[i] == item_pirate {
+			check_captain_loses_sailors(starve[i], who, 0)
+		}
+	}
+}
+
+// unit_maint_cost returns who's monthly upkeep. A noble that is itself
+// a beast doesn't pay for its own kind.
+// Ported from src/day.c lines 984-998.
+func (e *Engine) unit_maint_cost(who int) int {
+	cost := 0
+
+	for _, it := range e.globals.inventories[who] {
+		if it.item != int(e.noble_item(who)) {
+			cost += maint_cost(it.item) * it.qty
+		}
+	}
+
+	return cost
+}
+
+// charge_maint_sup charges who's upkeep, starving men if the stack
+// can't afford it.
+// Ported from src/day.c lines 1001-1024.
+func (e *Engine) charge_maint_sup(who int) {
+	cost := e.unit_maint_cost(who)
+
+	if cost < 1 {
+		return
+	}
+
+	if e.autocharge(who, cost) {
+		wout(who, "Paid maintenance of %s.", gold_s(cost))
+		return
+	}
+
+	have := e.stack_has_item(who, item_gold)
+
+	wout(who, "Maintenance costs are %s, can afford %s.",
+		gold_s(cost), gold_s(have))
+
+	e.men_starve(who, have)
+}
+
+// chargeMaintCosts charges the monthly upkeep of every player unit.
+// Port of C charge_maint_costs() from day.c.
+func (e *Engine) chargeMaintCosts() {
+	for _, who := range e.Characters() {
+		if e.subkind(e.player(who)) != sub_pl_regular {
+			continue
+		}
+
+		e.charge_maint_sup(who)
+	}
+}
+
+// animalDeaths kills about one in a hundred of the animals player
+// units keep each month.
+// Port of C animal_deaths() from day.c.
+func (e *Engine) animalDeaths() {
+	e.stage("animal_deaths()")
+
+	for _, who := range e.Characters() {
+		if e.subkind(e.player(who)) != sub_pl_regular {
+			continue
+		}
+
+		for _, it := range slices.Clone(e.globals.inventories[who]) {
+			if it.qty <= 0 || e.item_animal(it.item) == 0 {
+				continue
+			}
+
+			dead := 0
+			for range it.qty {
+				if e.rndFrom(streamUpkeep, 1, 1000) < 10 {
+					dead++
+				}
+			}
+
+			if dead > 0 {
+				e.consume_item(who, it.item, dead)
+				wout(who, "%s %s died.",
+					cap(nice_num(dead)),
+					e.plural_item_name(it.item, dead))
+			}
+		}
+	}
+}
+
+// innIncome pays each inn's takings to its owner. Inns in the same
+// province share the trade, and pillaging drives patrons away.
+// Port of C inn_income() from day.c.
+func (e *Engine) innIncome() {
+	for _, i := range e.Inns() {
+		owner := e.building_owner(i)
+
+		if owner == 0 {
+			continue
+		}
+
+		where := e.subloc(i)
+		nInns := e.count_loc_structures(where, sub_inn, 0)
+
+		base := e.rndFrom(streamMarkets, 50, 75)
+
+		pil := int(e.loc_pillage(where))
+		if pil != 0 {
+			base /= pil + 1
+		}
+
+		base /= max(nInns, 1)
+
+		if pil == 0 && e.rndFrom(streamMarkets, 1, 8) == 1 {
+			bonus := e.rndFrom(streamMarkets, 5, 13) * 10
+
+			wout(owner, "A rich traveller stayed in %s this "+
+				"month, spending %s.", e.box_name(i), gold_s(bonus))
+
+			base += bonus
+		}
+
+		e.gen_item(owner, item_gold, base)
+		e.globals.gold_inn += base
+		wout(owner, "%s yielded %s in income.", e.box_name(i), gold_s(base))
+
+		if pil != 0 {
+			switch e.rndFrom(streamMarkets, 1, 3) {
+			case 1:
+				wout(owner, "Patrons were scared away by "+
+					"recent looting in the province.")
+			case 2:
+				wout(owner, "Profits were hurt by pillaging "+
+					"in the area.")
+			case 3:
+				wout(owner, "Recent pillaging in the area "+
+					"lowered profits.")
+			}
+		}
+	}
+}
+
+// ghostWarriorDecay evaporates one ghost warrior from each player
+// unit at the end of each turn.
+// Port of C ghost_warrior_decay() from day.c.
+func (e *Engine) ghostWarriorDecay() {
+	for _, i := range e.Characters() {
+		if e.is_npc(i) {
+			continue
+		}
+
+		if e.has_item(i, item_ghost_warrior) <= 0 {
+			continue
+		}
+
+		wout(i, "%s evaporated.", cap(e.box_name_qty(item_ghost_warrior, 1)))
+		e.consume_item(i, item_ghost_warrior, 1)
+	}
+}
+
+// corpseDecay decomposes zero to two corpses held by each player unit
+// at the end of each month.
+// Port of C corpse_decay() from day.c.
+func (e *Engine) corpseDecay() {
+	for _, i := range e.Characters() {
+		if e.is_npc(i) {
+			continue
+		}
+
+		has := e.has_item(i, item_corpse)
+		if has <= 0 {
+			continue
+		}
+
+		has = min(has, e.rndFrom(streamUpkeep, 0, 2))
+		if has != 0 {
+			wout(i, "%s decomposed.", cap(e.box_name_qty(item_corpse, has)))
+			e.consume_item(i, item_corpse, has)
+		}
+	}
+}
+
+// deadBodyRot destroys the bodies of dead nobles twelve turns after
+// they died.
+// Port of C dead_body_rot() from day.c.
+func (e *Engine) deadBodyRot() {
+	for _, i := range e.DeadBodies() {
+		owner := e.item_unique(i)
+		if owner == 0 {
+			panic("deadBodyRot: dead body has no owner")
+		}
+
+		if e.globals.sysclock.turn-e.p_char(i).death_time.turn < 12 {
+			continue
+		}
+
+		if e.kind(owner) == T_char {
+			wout(owner, "%s decomposed.", e.box_name(i))
+		}
+
+		e.destroy_unique_item(owner, i)
+	}
+}
+
+// templeIncome pays each temple's offerings to its owner, if the owner
+// is a priest. Recent pillaging in the area cuts the offerings.
+// Port of C temple_income() from day.c.
+func (e *Engine) templeIncome() {
+	for _, i := range e.Temples() {
+		owner := e.building_owner(i)
+
+		if owner == 0 || !e.is_priest(owner) {
+			continue
+		}
+
+		amount := 100
+		if pil := int(e.loc_pillage(e.subloc(i))); pil != 0 {
+			amount /= pil + 1
+		}
+
+		e.gen_item(owner, item_gold, amount)
+		e.globals.gold_temple += amount
+		wout(owner, "%s collected offerings of %s.", e.box_name(i), gold_s(amount))
+	}
+}
+
+// incrementCurrentAura regenerates aura for each magician: two points
+// a month, two more with an auraculum in hand, and one for each aura
+// bonus item carried, never rising above max_eff_aura.
+// Port of C increment_current_aura() from day.c.
+func (e *Engine) incrementCurrentAura() {
+	for _, who := range e.Characters() {
+		if e.is_magician(who) == 0 {
+			continue
+		}
+
+		ac := e.has_auraculum(who)
+		ma := e.max_eff_aura(who)
+
+		p := e.p_magic(who)
+
+		rise := func() {
+			if p.cur_aura < ma {
+				p.cur_aura++
+			}
+		}
+
+		rise() // two point natural rise
+		rise()
+
+		if ac != 0 { // auraculum grants two more points
+			rise()
+			rise()
+		}
+
+		for _, it := range e.globals.inventories[who] {
+			if e.item_aura_bonus(it.item) != 0 {
+				rise()
+			}
+		}
+	}
+}
+
+// relicDecay counts down the relics with a decay timer. A relic whose
+// time runs out vanishes back to Nowhere.
+// Port of C relic_decay() from day.c.
+func (e *Engine) relicDecay() {
+	for _, item := range e.Items() {
+		if e.subkind(item) != sub_relic {
+			continue
+		}
+
+		if e.item_relic_decay(item) == 0 {
+			continue
+		}
+
+		e.p_item_magic(item).relic_decay--
+
+		if e.item_relic_decay(item) > 0 {
+			continue
+		}
+
+		owner := e.item_unique(item)
+		if e.kind(owner) == T_char {
+			wout(owner, "%s vanishes.", e.box_name(item))
+		}
+
+		e.move_item(owner, e.globals.nowhereLoc, item, 1)
+	}
+}
+
+// questDecay counts down the months until each subloc may be quested
+// again.
+// Port of C quest_decay() from day.c.
+func (e *Engine) questDecay() {
+	for _, where := range e.Locations() {
+		if e.subloc_quest(where) > 0 {
+			e.p_subloc(where).quest_late--
+		}
+	}
+}
@@ -60,7 +60,7 @@ func TestProcessOrdersNoOp(t *testing.T) {
 }
 
 func TestPostMonthNoOp(t *testing.T) {
-	e := teg
+	e := newTestEngine(t)
 
 	// Save initial state
 	initialPostRun := e.globals.post_has_been_run
@@ -85,7 +85,7 @@ func TestPostMonthNoOp(t *testing.T) {
 }
 
 func TestRunTurnNoOp(t *testing.T) {
-	e := teg
+	e := newTestEngine(t)
 
 	// Save initial state
 	initialTurn := e.globals.sysclock.turn
@@ -200,3 +200,228 @@ func TestMonthDays(t *testing.T) {
 		t.Errorf("MONTH_DAYS: got %d, want 30", MONTH_DAYS)
 	}
 }
+
+func TestUnitMaintCost(t *testing.T) {
+	_, who := setupUseTest(t)
+	teg.alloc_box(item_peasant, T_item, 0)
+	teg.alloc_box(item_soldier, T_item, 0)
+	teg.alloc_box(item_knight, T_item, 0)
+
+	teg.gen_item(who, item_peasant, 10)
+	teg.gen_item(who, item_soldier, 5)
+	teg.gen_item(who, item_knight, 2)
+
+	if got := teg.unit_maint_cost(who); got != 10+10+8 {
+		t.Errorf("unit_maint_cost = %d, want 28", got)
+	}
+
+	// a noble doesn't pay for its own kind
+	teg.p_char(who).unit_item = item_knight
+	if got := teg.unit_maint_cost(who); got != 20 {
+		t.Errorf("unit_maint_cost as knight = %d, want 20", got)
+	}
+}
+
+func TestChargeMaintCosts(t *testing.T) {
+	_, who := setupUseTest(t)
+	teg.alloc_box(item_gold, T_item, 0)
+	teg.alloc_box(item_peasant, T_item, 0)
+	teg.alloc_box(item_soldier, T_item, 0)
+
+	teg.gen_item(who, item_peasant, 10)
+	teg.gen_item(who, item_soldier, 5)
+	teg.gen_item(who, item_gold, 50)
+
+	teg.chargeMaintCosts()
+	if got := teg.has_item(who, item_gold); got != 30 {
+		t.Errorf("gold = %d, want 30 after upkeep", got)
+	}
+	if teg.has_item(who, item_peasant) != 10 || teg.has_item(who, item_soldier) != 5 {
+		t.Errorf("men lost despite paid upkeep")
+	}
+
+	// 4 gold pays for 2 soldiers at best; a third of the rest starve
+	teg.consume_item(who, item_gold, 26)
+	teg.chargeMaintCosts()
+	if got := teg.has_item(who, item_gold); got != 0 {
+		t.Errorf("gold = %d, want 0 after short upkeep", got)
+	}
+	men := teg.has_item(who, item_peasant) + teg.has_item(who, item_soldier)
+	if men >= 15 || men < 10 {
+		t.Errorf("men = %d after starvation, want 10..14", men)
+	}
+}
+
+func TestInnIncome(t *testing.T) {
+	_, who := setupUseTest(t)
+	teg.alloc_box(item_gold, T_item, 0)
+
+	prov, inn := 10_101, 56_762
+	teg.alloc_box(prov, T_loc, sub_plain)
+	teg.alloc_box(inn, T_loc, sub_inn)
+	teg.set_where(inn, prov)
+	teg.set_where(who, inn)
+
+	saved := teg.globals.gold_inn
+	defer func() { teg.globals.gold_inn = saved }()
+	teg.globals.gold_inn = 0
+
+	teg.innIncome()
+	got := teg.has_item(who, item_gold)
+	if got < 50 || got > 75+130 {
+		t.Errorf("inn income = %d, want 50..205", got)
+	}
+	if teg.globals.gold_inn != got {
+		t.Errorf("gold_inn = %d, want %d", teg.globals.gold_inn, got)
+	}
+
+	// pillaging cuts the takings and scares off rich travellers
+	teg.p_subloc(prov).loot = 4
+	teg.innIncome()
+	if n := teg.has_item(who, item_gold) - got; n < 10 || n > 15 {
+		t.Errorf("pillaged inn income = %d, want 10..15", n)
+	}
+}
+
+func TestAddClaimGold(t *testing.T) {
+	pl, _ := setupUseTest(t)
+	teg.alloc_box(item_gold, T_item, 0)
+	teg.alloc_box(indep_player, T_player, sub_pl_npc)
+
+	teg.addClaimGold()
+	if got := teg.has_item(pl, item_gold); got != 25 {
+		t.Errorf("claim gold = %d, want 25", got)
+	}
+	if got := teg.has_item(indep_player, item_gold); got != 0 {
+		t.Errorf("npc claim gold = %d, want 0", got)
+	}
+}
+
+func TestMoveCityGold(t *testing.T) {
+	newTestEngine(t)
+	teg.alloc_box(item_tax_cookie, T_item, 0)
+
+	prov, city := 10_101, 56_763
+	teg.alloc_box(prov, T_loc, sub_plain)
+	teg.alloc_box(city, T_loc, sub_city)
+	teg.set_where(city, prov)
+	teg.gen_item(city, item_tax_cookie, 120)
+
+	teg.moveCityGold()
+	if teg.has_item(city, item_tax_cookie) != 0 || teg.has_item(prov, item_tax_cookie) != 120 {
+		t.Errorf("city/province taxes = %d/%d, want 0/120",
+			teg.has_item(city, item_tax_cookie), teg.has_item(prov, item_tax_cookie))
+	}
+}
+
+func TestAnimalDeaths(t *testing.T) {
+	_, who := setupUseTest(t)
+	teg.alloc_box(item_riding_horse, T_item, 0)
+	teg.p_item(item_riding_horse).animal = 1
+
+	teg.gen_item(who, item_riding_horse, 5000)
+
+	teg.animalDeaths()
+	got := teg.has_item(who, item_riding_horse)
+	if got >= 5000 || got < 4800 {
+		t.Errorf("horses = %d after a month, want about 4955", got)
+	}
+}
+
+func TestAddNoblePoints(t *testing.T) {
+	pl, _ := setupUseTest(t)
+	teg.p_player(pl).noble_points = 3
+
+	teg.globals.sysclock.turn = 7
+	teg.addNoblePoints()
+	if got := teg.p_player(pl).noble_points; got != 3 {
+		t.Errorf("NP on turn 7 = %d, want 3", got)
+	}
+
+	teg.globals.sysclock.turn = 8
+	teg.addNoblePoints()
+	if got := teg.p_player(pl).noble_points; got != 4 {
+		t.Errorf("NP on turn 8 = %d, want 4", got)
+	}
+}
+
+func TestAddUnformed(t *testing.T) {
+	pl, _ := setupUseTest(t)
+
+	teg.addUnformed()
+	unformed := teg.getPlayerUnformed(pl)
+	if len(unformed) != 5 {
+		t.Fatalf("unformed = %v, want 5 nobles", unformed)
+	}
+	for _, n := range unformed {
+		if teg.kind(n) != T_unform {
+			t.Errorf("kind(%d) = %d, want T_unform", n, teg.kind(n))
+		}
+	}
+
+	// forming a noble leaves a gap that is filled next month
+	teg.removePlayerUnformed(pl, unformed[0])
+	teg.addUnformed()
+	if got := len(teg.getPlayerUnformed(pl)); got != 5 {
+		t.Errorf("unformed after refill = %d, want 5", got)
+	}
+}
+
+func TestAutoDrop(t *testing.T) {
+	pl, who := setupUseTest(t)
+	teg.alloc_box(indep_player, T_player, sub_pl_npc)
+
+	teg.globals.autoQuitTurns = 3
+	teg.globals.sysclock.turn = 10
+
+	teg.p_player(pl).last_order_turn = 8
+	teg.autoDrop()
+	if teg.kind(pl) != T_player {
+		t.Fatal("faction dropped two turns after its last orders")
+	}
+
+	teg.p_player(pl).last_order_turn = 7
+	teg.autoDrop()
+	if teg.kind(pl) == T_player {
+		t.Error("idle faction not dropped")
+	}
+	if teg.kind(who) != T_char || teg.player(who) != indep_player {
+		t.Errorf("unit %d kind %d player %d, want an independent noble",
+			who, teg.kind(who), teg.player(who))
+	}
+	if teg.kind(indep_player) != T_player {
+		t.Error("independent player dropped")
+	}
+}
+
+func TestLinkDecay(t *testing.T) {
+	newTestEngine(t)
+
+	hill, grave := 56_772, 56_773
+	teg.alloc_box(hill, T_loc, sub_faery_hill)
+	teg.alloc_box(grave, T_loc, sub_graveyard)
+	teg.p_subloc(hill).link_to = []int{10_101}
+	teg.p_subloc(hill).link_when = 4
+	teg.p_subloc(grave).link_to = []int{10_102}
+	teg.p_subloc(grave).link_when = -1
+	teg.p_subloc(grave).link_open = -1
+
+	want := []schar{0, 0, 2, 1, 0, 0}
+	for i, turn := range []int{2, 3, 4, 5, 6, 7} {
+		teg.globals.sysclock.turn = turn
+		teg.linkDecay()
+		if got := teg.p_subloc(hill).link_open; got != want[i] {
+			t.Errorf("turn %d: link_open = %d, want %d", turn, got, want[i])
+		}
+	}
+
+	// turn 12 is month 4 again
+	teg.globals.sysclock.turn = 12
+	teg.linkDecay()
+	if got := teg.p_subloc(hill).link_open; got != 2 {
+		t.Errorf("link_open = %d, want 2 in its month", got)
+	}
+	if got := teg.p_subloc(grave).link_open; got != -1 {
+		t.Errorf("graveyard link_open = %d, want -1", got)
+	}
+}
@@ -18,23 +18,34 @@ package taygete
 
 import (
 	"database/sql"
-	"embed"
 	"errors"
 	"fmt"
-	"io/fs"
-	"sort"
-	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
-//go:embed migrations/*.sql
-var migrationsFS embed.FS
-
-// OpenGameDB opens or creates a SQLite3 database for a game.
+// OpenGameDB opens or creates a SQLite3 database for a game and
+// brings its schema up to date.
 // Use ":memory:" for an in-memory database (useful for tests).
 // Use a file path for a persistent database.
 func OpenGameDB(dsn string) (*sql.DB, error) {
+	db, err := OpenGameDBNoMigrate(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Run migrations
+	if err := runMigrations(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("run migrations: %w", err)
+	}
+
+	return db, nil
+}
+
+// OpenGameDBNoMigrate opens a database without touching its schema,
+// for commands that report on or upgrade the schema themselves.
+func OpenGameDBNoMigrate(dsn string) (*sql.DB, error) {
 	if dsn == "" {
 		return nil, errors.New("dsn is required")
 	}
@@ -55,12 +66,6 @@ func OpenGameDB(dsn string) (*sql.DB, error) {
 		return nil, fmt.Errorf("enable foreign keys: %w", err)
 	}
 
-	// Run migrations
-	if err := runMigrations(db); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("run migrations: %w", err)
-	}
-
 	return db, nil
 }
 
@@ -70,87 +75,6 @@ func OpenTestDB() (*sql.DB, error) {
 	return OpenGameDB(":memory:")
 }
 
-// runMigrations applies all pending migrations to the database.
-func runMigrations(db *sql.DB) error {
-	// Create migrations tracking table
-	_, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS schema_migrations (
-			version TEXT PRIMARY KEY,
-			applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
-		)
-	`)
-	if err != nil {
-		return fmt.Errorf("create migrations table: %w", err)
-	}
-
-	// Get list of applied migrations
-	applied := make(map[string]bool)
-	rows, err := db.Query("SELECT version FROM schema_migrations")
-	if err != nil {
-		return fmt.Errorf("query migrations: %w", err)
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var version string
-		if err := rows.Scan(&version); err != nil {
-			return fmt.Errorf("scan migration version: %w", err)
-		}
-		applied[version] = true
-	}
-	if err := rows.Err(); err != nil {
-		return fmt.Errorf("iterate migrations: %w", err)
-	}
-
-	// Get list of migration files
-	entries, err := fs.ReadDir(migrationsFS, "migrations")
-	if err != nil {
-		return fmt.Errorf("read migrations dir: %w", err)
-	}
-
-	var migrations []string
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
-			migrations = append(migrations, entry.Name())
-		}
-	}
-	sort.Strings(migrations)
-
-	// Apply pending migrations
-	for _, name := range migrations {
-		version := strings.TrimSuffix(name, ".sql")
-		if applied[version] {
-			continue
-		}
-
-		content, err := fs.ReadFile(migrationsFS, "migrations/"+name)
-		if err != nil {
-			return fmt.Errorf("read migration %s: %w", name, err)
-		}
-
-		tx, err := db.Begin()
-		if err != nil {
-			return fmt.Errorf("begin transaction for %s: %w", name, err)
-		}
-
-		if _, err := tx.Exec(string(content)); err != nil {
-			tx.Rollback()
-			return fmt.Errorf("execute migration %s: %w", name, err)
-		}
-
-		if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (?)", version); err != nil {
-			tx.Rollback()
-			return fmt.Errorf("record migration %s: %w", name, err)
-		}
-
-		if err := tx.Commit(); err != nil {
-			return fmt.Errorf("commit migration %s: %w", name, err)
-		}
-	}
-
-	return nil
-}
-
 // TurnTx wraps a database transaction for turn processing.
 type TurnTx struct {
 	tx         *sql.Tx
@@ -17,6 +17,9 @@
 package taygete
 
 import (
+	"database/sql"
+	"errors"
+	"io/fs"
 	"testing"
 )
 
@@ -79,6 +82,11 @@ func TestMigrationsIdempotent(t *testing.T) {
 	}
 	defer db.Close()
 
+	entries, err := fs.Glob(migrationsFS, "migrations/*.sql")
+	if err != nil {
+		t.Fatalf("glob migrations: %v", err)
+	}
+
 	// Running migrations again should be a no-op
 	err = runMigrations(db)
 	if err != nil {
@@ -90,9 +98,9 @@ func TestMigrationsIdempotent(t *testing.T) {
 	if err != nil {
 		t.Fatalf("query schema_migrations: %v", err)
 	}
-	// Should still have exactly one migration
-	if count != 1 {
-		t.Errorf("migration count = %d, want 1", count)
+	// Should still have exactly one row per migration file
+	if count != len(entries) {
+		t.Errorf("migration count = %d, want %d", count, len(entries))
 	}
 }
 
@@ -211,3 +219,166 @@ func TestIsolatedTestDBs(t *testing.T) {
 		t.Errorf("db2 game_meta count = %d, want 0 (dbs not isolated)", count)
 	}
 }
+
+// testMigrations is a small history with one SQL and one Go step.
+func testMigrations(t *testing.T) []Migration {
+	t.Helper()
+	list, err := sortMigrations([]Migration{
+		{Name: "002_seed", Go: func(tx *sql.Tx) error {
+			_, err := tx.Exec("INSERT INTO widgets (name) VALUES ('first')")
+			return err
+		}},
+		{Name: "001_widgets", SQL: "CREATE TABLE widgets (name TEXT NOT NULL)"},
+	})
+	if err != nil {
+		t.Fatalf("sortMigrations: %v", err)
+	}
+	return list
+}
+
+func TestMigrateSQLAndGoSteps(t *testing.T) {
+	db, err := OpenGameDBNoMigrate(":memory:")
+	if err != nil {
+		t.Fatalf("OpenGameDBNoMigrate: %v", err)
+	}
+	defer db.Close()
+
+	known := testMigrations(t)
+	done, err := migrate(db, known)
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if len(done) != 2 || done[0] != "001_widgets" || done[1] != "002_seed" {
+		t.Errorf("applied = %v, want [001_widgets 002_seed]", done)
+	}
+
+	var name string
+	if err := db.QueryRow("SELECT name FROM widgets").Scan(&name); err != nil || name != "first" {
+		t.Errorf("widget = %q, %v; want first", name, err)
+	}
+	for _, m := range known {
+		var sum string
+		if err := db.QueryRow("SELECT checksum FROM schema_migrations WHERE version = ?", m.Name).Scan(&sum); err != nil {
+			t.Fatalf("checksum %s: %v", m.Name, err)
+		}
+		if sum != m.Checksum() {
+			t.Errorf("checksum %s = %q, want %q", m.Name, sum, m.Checksum())
+		}
+	}
+
+	done, err = migrate(db, known)
+	if err != nil || len(done) != 0 {
+		t.Errorf("second migrate = %v, %v; want nothing applied", done, err)
+	}
+}
+
+func TestMigrateRefusesFutureSchema(t *testing.T) {
+	db, err := OpenTestDB()
+	if err != nil {
+		t.Fatalf("OpenTestDB: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("INSERT INTO schema_migrations (version) VALUES ('999_from_the_future')"); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := runMigrations(db); !errors.Is(err, ErrFutureSchema) {
+		t.Errorf("runMigrations = %v, want ErrFutureSchema", err)
+	}
+
+	states, err := MigrationStatus(db)
+	if err != nil {
+		t.Fatalf("MigrationStatus: %v", err)
+	}
+	last := states[len(states)-1]
+	if last.Name != "999_from_the_future" || last.State != "unknown" {
+		t.Errorf("last state = %+v, want 999_from_the_future unknown", last)
+	}
+}
+
+func TestMigrateRefusesModifiedStep(t *testing.T) {
+	db, err := OpenGameDBNoMigrate(":memory:")
+	if err != nil {
+		t.Fatalf("OpenGameDBNoMigrate: %v", err)
+	}
+	defer db.Close()
+
+	known := testMigrations(t)
+	if _, err := migrate(db, known); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	known[0].SQL = "CREATE TABLE widgets (name TEXT)"
+	if _, err := migrate(db, known); err == nil {
+		t.Error("migrate accepted an edited step")
+	}
+	if st := migrationStates(known, mustApplied(t, db)); st[0].State != "modified" {
+		t.Errorf("state = %q, want modified", st[0].State)
+	}
+}
+
+func TestMigrateRefusesOlderPendingStep(t *testing.T) {
+	db, err := OpenGameDBNoMigrate(":memory:")
+	if err != nil {
+		t.Fatalf("OpenGameDBNoMigrate: %v", err)
+	}
+	defer db.Close()
+
+	known := testMigrations(t)
+	later := Migration{Version: 3, Name: "003_later", SQL: "SELECT 1"}
+	if _, err := migrate(db, []Migration{known[0], later}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if _, err := migrate(db, append(known, later)); err == nil {
+		t.Error("migrate applied 002_seed after 003_later")
+	}
+}
+
+func TestMigrateBackfillsChecksums(t *testing.T) {
+	db, err := OpenGameDBNoMigrate(":memory:")
+	if err != nil {
+		t.Fatalf("OpenGameDBNoMigrate: %v", err)
+	}
+	defer db.Close()
+
+	// The tracking table as written before checksums were kept.
+	known := testMigrations(t)
+	if _, err := db.Exec(`
+		CREATE TABLE schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE widgets (name TEXT NOT NULL);
+		INSERT INTO schema_migrations (version) VALUES ('001_widgets');
+	`); err != nil {
+		t.Fatalf("old schema: %v", err)
+	}
+
+	states := migrationStates(known, mustApplied(t, db))
+	if states[0].State != "applied" || states[1].State != "pending" {
+		t.Errorf("states = %+v, want applied, pending", states)
+	}
+
+	done, err := migrate(db, known)
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if len(done) != 1 || done[0] != "002_seed" {
+		t.Errorf("applied = %v, want [002_seed]", done)
+	}
+	var sum string
+	if err := db.QueryRow("SELECT checksum FROM schema_migrations WHERE version = '001_widgets'").Scan(&sum); err != nil {
+		t.Fatalf("checksum: %v", err)
+	}
+	if sum != known[0].Checksum() {
+		t.Errorf("checksum = %q, want %q", sum, known[0].Checksum())
+	}
+}
+
+func mustApplied(t *testing.T, db *sql.DB) map[string]appliedMigration {
+	t.Helper()
+	applied, err := readAppliedMigrations(db)
+	if err != nil {
+		t.Fatalf("readAppliedMigrations: %v", err)
+	}
+	return applied
+}
@@ -22,130 +22,131 @@ package taygete
 // If in ocean, kills everyone aboard via kill_stack_ocean.
 // If near land, moves everyone to the shore.
 // Ported from src/u.c lines 874-928.
-func sink_ship(ship int) {
-	where := subloc(ship)
+func (e *Engine) sink_ship(ship int) {
+	where := e.subloc(ship)
 
-	log_write(LOG_SPECIAL, "%s has sunk in %s.", box_name(ship), box_name(subloc(ship)))
+	log_write(LOG_SPECIAL, "%s has sunk in %s.", e.box_name(ship), e.box_name(e.subloc(ship)))
 
-	wout(ship, "%s has sunk!", box_name(ship))
-	wout(subloc(ship), "%s has sunk!", box_name(ship))
+	wout(ship, "%s has sunk!", e.box_name(ship))
+	wout(e.subloc(ship), "%s has sunk!", e.box_name(ship))
 
-	if subkind(where) == sub_ocean {
-		p := rp_loc_info(ship)
+	if e.subkind(where) == sub_ocean {
+		p := e.rp_loc_info(ship)
 		if p != nil {
 			for _, who := range p.here_list {
-				if kind(who) == T_char {
-					kill_stack_ocean(who)
+				if e.kind(who) == T_char {
+					e.kill_stack_ocean(who)
 				}
 			}
 		}
 	} else {
-		p := rp_loc_info(ship)
+		p := e.rp_loc_info(ship)
 		if p != nil {
 			for _, who := range p.here_list {
-				if kind(who) == T_char {
+				if e.kind(who) == T_char {
 					move_stack(who, where)
 				} else {
-					set_where(who, where)
+					e.set_where(who, where)
 				}
 			}
 		}
 	}
 
-	p := rp_subloc(ship)
+	p := e.rp_subloc(ship)
 	if p != nil && len(p.bound_storms) > 0 {
 		for _, storm := range p.bound_storms {
-			if kind(storm) == T_storm {
-				m := p_misc(storm)
+			if e.kind(storm) == T_storm {
+				m := e.p_misc(storm)
 				if m != nil {
 					m.bind_storm = 0
 				}
 			}
 		}
 		p.bound_storms = nil
+		e.markDirty(ship)
 	}
 
-	set_where(ship, 0)
-	delete_box(ship)
+	e.set_where(ship, 0)
+	e.delete_box(ship)
 }
 
 // get_rid_of_collapsed_mine removes a collapsed mine from the game.
 // Moves any contents out first.
 // Ported from src/u.c lines 932-954.
-func get_rid_of_collapsed_mine(fort int) {
-	if subkind(fort) != sub_mine_collapsed {
+func (e *Engine) get_rid_of_collapsed_mine(fort int) {
+	if e.subkind(fort) != sub_mine_collapsed {
 		panic("get_rid_of_collapsed_mine: not a collapsed mine")
 	}
 
-	where := subloc(fort)
+	where := e.subloc(fort)
 
-	p := rp_loc_info(fort)
+	p := e.rp_loc_info(fort)
 	if p != nil {
 		for _, who := range p.here_list {
-			if kind(who) == T_char {
+			if e.kind(who) == T_char {
 				move_stack(who, where)
 			} else {
-				set_where(who, where)
+				e.set_where(who, where)
 			}
 		}
 	}
 
-	set_where(fort, 0)
-	delete_box(fort)
+	e.set_where(fort, 0)
+	e.delete_box(fort)
 }
 
 // building_collapses handles building destruction.
 // Mines collapse to sub_mine_collapsed instead of being deleted.
 // Castles clear any garrison links.
 // Ported from src/u.c lines 958-999.
-func building_collapses(fort int) {
-	where := subloc(fort)
+func (e *Engine) building_collapses(fort int) {
+	where := e.subloc(fort)
 
-	log_write(LOG_SPECIAL, "%s collapsed in %s.", box_name(fort), box_name(where))
+	log_write(LOG_SPECIAL, "%s collapsed in %s.", e.box_name(fort), e.box_name(where))
 
 	vector_char_here(fort)
 	vector_add(where)
-	wout(VECT, "%s collapses!", box_name(fort))
+	wout(VECT, "%s collapses!", e.box_name(fort))
 
-	p := rp_loc_info(fort)
+	p := e.rp_loc_info(fort)
 	if p != nil {
 		for _, who := range p.here_list {
-			if kind(who) == T_char {
+			if e.kind(who) == T_char {
 				move_stack(who, where)
 			} else {
-				set_where(who, where)
+				e.set_where(who, where)
 			}
 		}
 	}
 
-	if subkind(fort) == sub_mine {
-		change_box_subkind(fort, sub_mine_collapsed)
-		p_misc(fort).mine_delay = 8
+	if e.subkind(fort) == sub_mine {
+		e.change_box_subkind(fort, sub_mine_collapsed)
+		e.p_misc(fort).mine_delay = 8
 		return
 	}
 
-	if subkind(fort) == sub_castle {
-		for i := sub_first(sub_garrison); i != 0; i = sub_next(i) {
-			if garrison_castle(i) == fort {
-				p_misc(i).garr_castle = 0
+	if e.subkind(fort) == sub_castle {
+		for i := e.sub_first(sub_garrison); i != 0; i = e.sub_next(i) {
+			if e.garrison_castle(i) == fort {
+				e.p_misc(i).garr_castle = 0
 			}
 		}
 	}
 
-	set_where(fort, 0)
-	delete_box(fort)
+	e.set_where(fort, 0)
+	e.delete_box(fort)
 }
 
 // add_structure_damage adds damage to a structure (ship or building).
 // Returns true if the structure was destroyed.
 // If can_destroy is false, damage is capped at 99.
 // Ported from src/u.c lines 1004-1035.
-func add_structure_damage(fort, damage int, can_destroy bool) bool {
+func (e *Engine) add_structure_damage(fort, damage int, can_destroy bool) bool {
 	if damage < 0 {
 		panic("add_structure_damage: negative damage")
 	}
 
-	p := p_subloc(fort)
+	p := e.p_subloc(fort)
 
 	if int(p.damage)+damage > 100 {
 		p.damage = 100
@@ -162,10 +163,10 @@ func add_structure_damage(fort, damage int, can_destroy bool) bool {
 		return false
 	}
 
-	if is_ship(fort) {
-		sink_ship(fort)
+	if e.is_ship(fort) {
+		e.sink_ship(fort)
 	} else {
-		building_collapses(fort)
+		e.building_collapses(fort)
 	}
 
 	return true
@@ -174,28 +175,28 @@ func add_structure_damage(fort, damage int, can_destroy bool) bool {
 // find_nearest_land finds the nearest land province from an ocean location.
 // Uses random direction walking with fallback to region scan.
 // Ported from src/u.c lines 1871-1951.
-func find_nearest_land(where int) int {
+func (e *Engine) find_nearest_land(where int) int {
 	origWhere := where
 	ret := 0
 
 	tryTwo := 100
 	for tryTwo > 0 {
 		tryTwo--
-		dir := rnd(1, 4)
+		dir := e.rndFrom(streamUpkeep, 1, 4)
 
 		tryOne := 1000
 		for tryOne > 0 {
 			tryOne--
 
-			if subkind(where) != sub_ocean {
+			if e.subkind(where) != sub_ocean {
 				return where
 			}
 
-			p := rp_loc_info(where)
+			p := e.rp_loc_info(where)
 			if p != nil {
 				for _, i := range p.here_list {
-					if subkind(i) == sub_island {
-						if kind(i) != T_loc {
+					if e.subkind(i) == sub_island {
+						if e.kind(i) != T_loc {
 							panic("find_nearest_land: island not T_loc")
 						}
 						ret = i
@@ -208,7 +209,7 @@ func find_nearest_land(where int) int {
 				return ret
 			}
 
-			where = location_direction(where, dir)
+			where = e.location_direction(where, dir)
 
 			check := 0
 			for where == 0 {
@@ -219,7 +220,7 @@ func find_nearest_land(where int) int {
 					// No valid direction found, break out to try fallback
 					break
 				}
-				where = location_direction(where, dir)
+				where = e.location_direction(where, dir)
 			}
 			if check > 4 {
 				break
@@ -234,14 +235,14 @@ func find_nearest_land(where int) int {
 	log_write(LOG_CODE, "find_nearest_land: Plan C")
 
 	var l []int
-	for i := kind_first(T_loc); i != 0; i = kind_next(i) {
-		if region(i) != region(origWhere) {
+	for i := e.kind_first(T_loc); i != 0; i = e.kind_next(i) {
+		if e.region(i) != e.region(origWhere) {
 			continue
 		}
-		if loc_depth(i) != LOC_province {
+		if e.loc_depth(i) != LOC_province {
 			continue
 		}
-		if subkind(i) == sub_ocean {
+		if e.subkind(i) == sub_ocean {
 			continue
 		}
 		l = append(l, i)
@@ -251,17 +252,17 @@ func find_nearest_land(where int) int {
 		return 0
 	}
 
-	ret = l[rnd(0, len(l)-1)]
+	ret = l[e.rndFrom(streamUpkeep, 0, len(l)-1)]
 	return ret
 }
 
 // location_direction returns the province in the given direction from where.
 // dir is 1-4 for N/E/S/W.
 // Ported from src/dir.c lines 145-156.
-func location_direction(where, dir int) int {
+func (e *Engine) location_direction(where, dir int) int {
 	dir--
 
-	p := rp_loc(where)
+	p := e.rp_loc(where)
 	if p == nil {
 		return 0
 	}
@@ -21,7 +21,7 @@ import "testing"
 // clearDestructionTestBoxes clears boxes used in destruction tests.
 func clearDestructionTestBoxes() {
 	for _, id := range []int{100, 200, 1000, 10000, 10001, 58760, 59000, 59100, 59200, 79000} {
-		teg.globals.bx[id] = nil
+		teg.deleteBox(id)
 	}
 	teg.globals.sub_head[sub_garrison] = 0
 }
@@ -32,18 +32,18 @@ func TestAddStructureDamage(t *testing.T) {
 
 	t.Run("damage accumulation below 100", func(t *testing.T) {
 		shipID := 100
-		teg.globals.bx[shipID] = &box{
+		teg.setBox(shipID, &box{
 			kind:     T_ship,
 			skind:    sub_galley,
 			x_subloc: &entity_subloc{damage: 0},
-		}
+		})
 
-		destroyed := add_structure_damage(shipID, 50, true)
+		destroyed := teg.add_structure_damage(shipID, 50, true)
 		if destroyed {
 			t.Error("ship should not be destroyed at 50 damage")
 		}
 
-		p := rp_subloc(shipID)
+		p := teg.rp_subloc(shipID)
 		if p.damage != 50 {
 			t.Errorf("damage = %d, want 50", p.damage)
 		}
@@ -54,20 +54,20 @@ func TestAddStructureDamage(t *testing.T) {
 		shipID := 100
 		provinceID := 10000
 
-		teg.globals.bx[provinceID] = &box{kind: T_loc, skind: sub_ocean}
-		teg.globals.bx[shipID] = &box{
+		teg.setBox(provinceID, &box{kind: T_loc, skind: sub_ocean})
+		teg.setBox(shipID, &box{
 			kind:     T_ship,
 			skind:    sub_galley,
 			x_subloc: &entity_subloc{damage: 90},
-		}
-		set_where(shipID, provinceID)
+		})
+		teg.set_where(shipID, provinceID)
 
-		destroyed := add_structure_damage(shipID, 50, false)
+		destroyed := teg.add_structure_damage(shipID, 50, false)
 		if destroyed {
 			t.Error("ship should not be destroyed when can_destroy is false")
 		}
 
-		p := rp_subloc(shipID)
+		p := teg.rp_subloc(shipID)
 		if p.damage != 99 {
 			t.Errorf("damage = %d, want 99 (capped)", p.damage)
 		}
@@ -78,21 +78,21 @@ func TestAddStructureDamage(t *testing.T) {
 		shipID := 100
 		provinceID := 10000
 
-		teg.globals.bx[provinceID] = &box{kind: T_loc, skind: sub_plain}
-		teg.globals.bx[shipID] = &box{
+		teg.setBox(provinceID, &box{kind: T_loc, skind: sub_plain})
+		teg.setBox(shipID, &box{
 			kind:     T_ship,
 			skind:    sub_galley,
 			x_subloc: &entity_subloc{damage: 90},
-		}
-		set_where(shipID, provinceID)
+		})
+		teg.set_where(shipID, provinceID)
 
-		destroyed := add_structure_damage(shipID, 20, true)
+		destroyed := teg.add_structure_damage(shipID, 20, true)
 		if !destroyed {
 			t.Error("ship should be destroyed at 100+ damage")
 		}
 
-		if kind(shipID) != T_deleted {
-			t.Errorf("kind(ship) = %d, want T_deleted", kind(shipID))
+		if teg.kind(shipID) != T_deleted {
+			t.Errorf("kind(ship) = %d, want T_deleted", teg.kind(shipID))
 		}
 	})
 }
@@ -106,19 +106,19 @@ func TestSinkShip(t *testing.T) {
 		shipID := 100
 		provinceID := 10000
 
-		teg.globals.bx[provinceID] = &box{kind: T_loc, skind: sub_plain}
-		teg.globals.bx[shipID] = &box{
+		teg.setBox(provinceID, &box{kind: T_loc, skind: sub_plain})
+		teg.setBox(shipID, &box{
 			kind:     T_ship,
 			skind:    sub_galley,
 			x_subloc: &entity_subloc{},
-		}
+		})
 
-		set_where(shipID, provinceID)
+		teg.set_where(shipID, provinceID)
 
-		sink_ship(shipID)
+		teg.sink_ship(shipID)
 
-		if kind(shipID) != T_deleted {
-			t.Errorf("ship kind = %d, want T_deleted", kind(shipID))
+		if teg.kind(shipID) != T_deleted {
+			t.Errorf("ship kind = %d, want T_deleted", teg.kind(shipID))
 		}
 		// Note: character relocation tested implicitly; move_stack is stub
 	})
@@ -129,25 +129,25 @@ func TestSinkShip(t *testing.T) {
 		stormID := 79000
 		provinceID := 10000
 
-		teg.globals.bx[provinceID] = &box{kind: T_loc, skind: sub_plain}
-		teg.globals.bx[stormID] = &box{
+		teg.setBox(provinceID, &box{kind: T_loc, skind: sub_plain})
+		teg.setBox(stormID, &box{
 			kind:   T_storm,
 			x_misc: &entity_misc{bind_storm: shipID},
-		}
-		teg.globals.bx[shipID] = &box{
+		})
+		teg.setBox(shipID, &box{
 			kind:  T_ship,
 			skind: sub_galley,
 			x_subloc: &entity_subloc{
 				bound_storms: []int{stormID},
 			},
-		}
+		})
 
-		set_where(shipID, provinceID)
+		teg.set_where(shipID, provinceID)
 
-		sink_ship(shipID)
+		teg.sink_ship(shipID)
 
-		if p_misc(stormID).bind_storm != 0 {
-			t.Errorf("storm bind_storm = %d, want 0", p_misc(stormID).bind_storm)
+		if teg.p_misc(stormID).bind_storm != 0 {
+			t.Errorf("storm bind_storm = %d, want 0", teg.p_misc(stormID).bind_storm)
 		}
 	})
 }
@@ -161,23 +161,23 @@ func TestBuildingCollapses(t *testing.T) {
 		mineID := 59000
 		provinceID := 10000
 
-		teg.globals.bx[provinceID] = &box{kind: T_loc, skind: sub_plain}
-		teg.globals.bx[mineID] = &box{
+		teg.setBox(provinceID, &box{kind: T_loc, skind: sub_plain})
+		teg.setBox(mineID, &box{
 			kind:     T_loc,
 			skind:    sub_mine,
 			x_subloc: &entity_subloc{damage: 100},
-		}
+		})
 
-		set_where(mineID, provinceID)
+		teg.set_where(mineID, provinceID)
 
-		building_collapses(mineID)
+		teg.building_collapses(mineID)
 
-		if subkind(mineID) != sub_mine_collapsed {
-			t.Errorf("mine subkind = %d, want sub_mine_collapsed (%d)", subkind(mineID), sub_mine_collapsed)
+		if teg.subkind(mineID) != sub_mine_collapsed {
+			t.Errorf("mine subkind = %d, want sub_mine_collapsed (%d)", teg.subkind(mineID), sub_mine_collapsed)
 		}
 
-		if p_misc(mineID).mine_delay != 8 {
-			t.Errorf("mine_delay = %d, want 8", p_misc(mineID).mine_delay)
+		if teg.p_misc(mineID).mine_delay != 8 {
+			t.Errorf("mine_delay = %d, want 8", teg.p_misc(mineID).mine_delay)
 		}
 	})
 
@@ -186,19 +186,19 @@ func TestBuildingCollapses(t *testing.T) {
 		towerID := 59100
 		provinceID := 10000
 
-		teg.globals.bx[provinceID] = &box{kind: T_loc, skind: sub_plain}
-		teg.globals.bx[towerID] = &box{
+		teg.setBox(provinceID, &box{kind: T_loc, skind: sub_plain})
+		teg.setBox(towerID, &box{
 			kind:     T_loc,
 			skind:    sub_tower,
 			x_subloc: &entity_subloc{damage: 100},
-		}
+		})
 
-		set_where(towerID, provinceID)
+		teg.set_where(towerID, provinceID)
 
-		building_collapses(towerID)
+		teg.building_collapses(towerID)
 
-		if kind(towerID) != T_deleted {
-			t.Errorf("tower kind = %d, want T_deleted", kind(towerID))
+		if teg.kind(towerID) != T_deleted {
+			t.Errorf("tower kind = %d, want T_deleted", teg.kind(towerID))
 		}
 	})
 
@@ -208,31 +208,31 @@ func TestBuildingCollapses(t *testing.T) {
 		garrisonID := 1000
 		provinceID := 10000
 
-		teg.globals.bx[provinceID] = &box{kind: T_loc, skind: sub_plain}
-		teg.globals.bx[castleID] = &box{
+		teg.setBox(provinceID, &box{kind: T_loc, skind: sub_plain})
+		teg.setBox(castleID, &box{
 			kind:     T_loc,
 			skind:    sub_castle,
 			x_subloc: &entity_subloc{damage: 100},
-		}
-		teg.globals.bx[garrisonID] = &box{
+		})
+		teg.setBox(garrisonID, &box{
 			kind:   T_char,
 			skind:  sub_garrison,
 			x_char: &entity_char{},
 			x_misc: &entity_misc{garr_castle: castleID},
-		}
+		})
 
 		teg.globals.sub_head[sub_garrison] = garrisonID
 
-		set_where(castleID, provinceID)
+		teg.set_where(castleID, provinceID)
 
-		building_collapses(castleID)
+		teg.building_collapses(castleID)
 
-		if kind(castleID) != T_deleted {
-			t.Errorf("castle kind = %d, want T_deleted", kind(castleID))
+		if teg.kind(castleID) != T_deleted {
+			t.Errorf("castle kind = %d, want T_deleted", teg.kind(castleID))
 		}
 
-		if p_misc(garrisonID).garr_castle != 0 {
-			t.Errorf("garrison garr_castle = %d, want 0", p_misc(garrisonID).garr_castle)
+		if teg.p_misc(garrisonID).garr_castle != 0 {
+			t.Errorf("garrison garr_castle = %d, want 0", teg.p_misc(garrisonID).garr_castle)
 		}
 	})
 }
@@ -244,19 +244,19 @@ func TestGetRidOfCollapsedMine(t *testing.T) {
 	mineID := 59000
 	provinceID := 10000
 
-	teg.globals.bx[provinceID] = &box{kind: T_loc, skind: sub_plain}
-	teg.globals.bx[mineID] = &box{
+	teg.setBox(provinceID, &box{kind: T_loc, skind: sub_plain})
+	teg.setBox(mineID, &box{
 		kind:     T_loc,
 		skind:    sub_mine_collapsed,
 		x_subloc: &entity_subloc{},
-	}
+	})
 
-	set_where(mineID, provinceID)
+	teg.set_where(mineID, provinceID)
 
-	get_rid_of_collapsed_mine(mineID)
+	teg.get_rid_of_collapsed_mine(mineID)
 
-	if kind(mineID) != T_deleted {
-		t.Errorf("mine kind = %d, want T_deleted", kind(mineID))
+	if teg.kind(mineID) != T_deleted {
+		t.Errorf("mine kind = %d, want T_deleted", teg.kind(mineID))
 	}
 	// Note: character relocation tested implicitly; move_stack is stub
 }
@@ -269,9 +269,9 @@ func TestFindNearestLand(t *testing.T) {
 		clearDestructionTestBoxes()
 		provinceID := 10000
 
-		teg.globals.bx[provinceID] = &box{kind: T_loc, skind: sub_plain}
+		teg.setBox(provinceID, &box{kind: T_loc, skind: sub_plain})
 
-		result := find_nearest_land(provinceID)
+		result := teg.find_nearest_land(provinceID)
 		if result != provinceID {
 			t.Errorf("find_nearest_land = %d, want %d", result, provinceID)
 		}
@@ -282,12 +282,12 @@ func TestFindNearestLand(t *testing.T) {
 		oceanID := 10000
 		islandID := 59000
 
-		teg.globals.bx[oceanID] = &box{kind: T_loc, skind: sub_ocean}
-		teg.globals.bx[islandID] = &box{kind: T_loc, skind: sub_island}
+		teg.setBox(oceanID, &box{kind: T_loc, skind: sub_ocean})
+		teg.setBox(islandID, &box{kind: T_loc, skind: sub_island})
 
-		set_where(islandID, oceanID)
+		teg.set_where(islandID, oceanID)
 
-		result := find_nearest_land(oceanID)
+		result := teg.find_nearest_land(oceanID)
 		if result != islandID {
 			t.Errorf("find_nearest_land = %d, want island %d", result, islandID)
 		}
@@ -299,20 +299,20 @@ func TestFindNearestLand(t *testing.T) {
 		landID := 10001
 		regionID := 58760
 
-		teg.globals.bx[regionID] = &box{kind: T_loc, skind: sub_region}
-		teg.globals.bx[oceanID] = &box{
+		teg.setBox(regionID, &box{kind: T_loc, skind: sub_region})
+		teg.setBox(oceanID, &box{
 			kind:  T_loc,
 			skind: sub_ocean,
 			x_loc: &entity_loc{
 				prov_dest: []int{landID, 0, 0, 0},
 			},
-		}
-		teg.globals.bx[landID] = &box{kind: T_loc, skind: sub_plain}
+		})
+		teg.setBox(landID, &box{kind: T_loc, skind: sub_plain})
 
-		set_where(oceanID, regionID)
-		set_where(landID, regionID)
+		teg.set_where(oceanID, regionID)
+		teg.set_where(landID, regionID)
 
-		result := find_nearest_land(oceanID)
+		result := teg.find_nearest_land(oceanID)
 		if result != landID {
 			t.Errorf("find_nearest_land = %d, want %d", result, landID)
 		}
@@ -329,13 +329,13 @@ func TestLocationDirection(t *testing.T) {
 	southID := 10003
 	westID := 10004
 
-	teg.globals.bx[provinceID] = &box{
+	teg.setBox(provinceID, &box{
 		kind:  T_loc,
 		skind: sub_plain,
 		x_loc: &entity_loc{
 			prov_dest: []int{northID, eastID, southID, westID},
 		},
-	}
+	})
 
 	tests := []struct {
 		dir  int
@@ -348,13 +348,13 @@ func TestLocationDirection(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		got := location_direction(provinceID, tt.dir)
+		got := teg.location_direction(provinceID, tt.dir)
 		if got != tt.want {
 			t.Errorf("location_direction(%d, %d) = %d, want %d", provinceID, tt.dir, got, tt.want)
 		}
 	}
 
-	got := location_direction(provinceID, 5)
+	got := teg.location_direction(provinceID, 5)
 	if got != 0 {
 		t.Errorf("location_direction with invalid dir = %d, want 0", got)
 	}
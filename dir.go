@@ -0,0 +1,586 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// dir.go - exits and routes between locations ported from src/dir.c
+
+package taygete
+
+import "strings"
+
+// exit_distance returns the number of days it takes to travel from
+// loc1 to loc2.
+// Ported from src/dir.c lines 159-255.
+func (e *Engine) exit_distance(loc1, loc2 int) int {
+	if e.subkind(loc1) == sub_hades_pit || e.subkind(loc2) == sub_hades_pit {
+		return 28
+	}
+
+	if e.loc_depth(loc1) > e.loc_depth(loc2) {
+		loc1, loc2 = loc2, loc1
+	}
+
+	switch e.loc_depth(loc2) {
+	case LOC_build:
+		return 0
+	case LOC_subloc:
+		return 1
+	}
+
+	// water-land links are distance=2
+	if e.subkind(loc1) == sub_ocean && e.subkind(loc2) != sub_ocean {
+		return 2
+	}
+	if e.subkind(loc1) != sub_ocean && e.subkind(loc2) == sub_ocean {
+		return 2
+	}
+
+	// linked sublocs between regions
+	if e.province(loc1) != e.province(loc2) {
+		loc1 = e.province(loc1)
+		loc2 = e.province(loc2)
+	}
+
+	switch e.subkind(loc2) {
+	case sub_ocean:
+		if e.loc_sea_lane(loc1) != 0 && e.loc_sea_lane(loc2) != 0 {
+			return 2
+		}
+		return 3
+	case sub_mountain:
+		return 10
+	case sub_forest:
+		return 8
+	case sub_swamp:
+		return 14
+	case sub_desert:
+		return 8
+	case sub_plain:
+		return 7
+	case sub_under:
+		return 7
+	case sub_cloud:
+		return 7
+	case sub_tunnel:
+		return 5
+	case sub_chamber:
+		return 5
+	}
+
+	panic(sout("exit_distance: subkind=%s, loc1=%d, loc2=%d",
+		subkind_s[e.subkind(loc2)], loc1, loc2))
+}
+
+// is_port_city reports whether where is a city on the coast. Cities
+// in the mountains are never ports.
+// Ported from src/dir.c lines 258-286.
+func (e *Engine) is_port_city(where int) bool {
+	if e.subkind(where) != sub_city {
+		return false
+	}
+
+	if e.loc_depth(where) != LOC_subloc {
+		panic("is_port_city: city is not a subloc")
+	}
+
+	p := e.province(where)
+
+	if e.subkind(p) == sub_mountain {
+		return false
+	}
+
+	for _, dir := range []int{DIR_N, DIR_S, DIR_E, DIR_W} {
+		if n := e.location_direction(p, dir); n != 0 && e.subkind(n) == sub_ocean {
+			return true
+		}
+	}
+
+	return false
+}
+
+// province_has_port_city returns the port city in province where,
+// or 0 if it has none.
+// Ported from src/dir.c lines 289-309.
+func (e *Engine) province_has_port_city(where int) int {
+	if e.loc_depth(where) != LOC_province {
+		panic("province_has_port_city: not a province")
+	}
+
+	for _, i := range e.rp_loc_info(where).here_list {
+		if e.subkind(i) == sub_city && e.is_port_city(i) {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// summer_uldim_open_now reports whether Uldim pass and Summerbridge
+// are passable. They open once month 2 is done and close after month
+// 6.
+// Ported from src/dir.c lines 312-324.
+func (e *Engine) summer_uldim_open_now() bool {
+	month := e.olyMonth()
+
+	if month >= 3 && month <= 6 {
+		return true
+	}
+
+	return month == 2 && e.globals.monthDone
+}
+
+// add_province_exit appends the route from where to dest to l,
+// flagging it impassable, hidden or watery as the terrain demands.
+// Ported from src/dir.c lines 327-467.
+func (e *Engine) add_province_exit(who, where, dest, dir int, l *[]*exit_view) {
+	if !e.valid_box(dest) {
+		panic("add_province_exit: invalid destination")
+	}
+
+	v := &exit_view{}
+
+	if (e.is_ship_either(where) && e.ship_gone(where) != 0) ||
+		(e.is_ship_either(dest) && e.ship_gone(dest) != 0) {
+		v.in_transit = TRUE
+	}
+
+	if e.is_ship_either(where) && e.subkind(dest) == sub_ocean {
+		v.impassable = TRUE
+	}
+
+	if e.subkind(where) == sub_ocean && !e.is_ship_either(dest) {
+		v.water = TRUE
+	}
+
+	if e.subkind(dest) == sub_ocean {
+		v.water = TRUE
+	}
+
+	// if land->water && land has a city, then impassable
+	if e.loc_depth(where) == LOC_province &&
+		e.subkind(dest) == sub_ocean &&
+		e.province_has_port_city(where) != 0 {
+		v.impassable = TRUE
+	}
+
+	// can't go into collapsed mines
+	if e.subkind(dest) == sub_mine_collapsed {
+		v.impassable = TRUE
+	}
+
+	// if water->land && land has a city, then the route is to the city
+	if e.subkind(where) == sub_ocean &&
+		e.subkind(dest) != sub_ocean &&
+		e.subkind(dest) != sub_mountain &&
+		e.loc_depth(dest) == LOC_province {
+		if n := e.province_has_port_city(dest); n != 0 {
+			v.impassable = TRUE
+			e.add_province_exit(who, where, n, dir, l)
+		}
+	}
+
+	// if water-mountain, then impassable
+	if (e.subkind(where) == sub_mountain && e.subkind(dest) == sub_ocean) ||
+		(e.subkind(where) == sub_ocean && e.subkind(dest) == sub_mountain) {
+		v.impassable = TRUE
+	}
+
+	// if surface-cloud, then impassable (except by FLYing)
+	if (dir == DIR_UP || dir == DIR_DOWN) &&
+		(e.subkind(where) == sub_cloud || e.subkind(dest) == sub_cloud) {
+		v.impassable = TRUE
+	}
+
+	// the Uldim mountains are impassable
+	if (dir == DIR_N && e.uldim(where) == 1) ||
+		(dir == DIR_S && e.uldim(where) == 2) {
+		v.impassable = TRUE
+	}
+
+	// Uldim pass and Summerbridge are passable part of the year
+	if (dir == DIR_N && (e.uldim(where) == 4 || e.summerbridge(where) == 1)) ||
+		(dir == DIR_S && (e.uldim(where) == 3 || e.summerbridge(where) == 2)) {
+		if !e.summer_uldim_open_now() {
+			v.impassable = TRUE
+		}
+	}
+
+	v.orig = where
+	v.destination = dest
+	v.direction = dir
+	v.distance = e.exit_distance(where, dest)
+
+	if e.loc_hidden(where) {
+		v.orig_hidden = TRUE
+	}
+
+	if e.loc_hidden(dest) {
+		v.dest_hidden = TRUE
+	}
+
+	// Don't make Out routes be hidden.  The character may have poofed
+	// into a building, and it's unreasonable not to know how to leave.
+	if e.loc_hidden(dest) && !e.test_known(who, dest) && dir != DIR_OUT {
+		v.hidden = TRUE
+	}
+
+	if e.region(where) != e.region(dest) {
+		v.inside = e.region(dest)
+
+		if !e.in_hades(where) && e.in_hades(dest) {
+			v.hades_cost = 100
+		}
+	}
+
+	// a magical barrier around the destination prevents travel
+	if e.loc_barrier(dest) != 0 && dir != DIR_OUT {
+		v.impassable = TRUE
+		v.magic_barrier = TRUE
+	}
+
+	*l = append(*l, v)
+}
+
+// extra_routes adds the roads leading out of where.
+// Ported from src/dir.c lines 470-525.
+func (e *Engine) extra_routes(who, where int, l *[]*exit_view) {
+	for _, i := range e.rp_loc_info(where).here_list {
+		if e.kind(i) != T_road {
+			continue
+		}
+
+		dest := e.road_dest(i)
+		if !e.valid_box(dest) {
+			panic("extra_routes: invalid road destination")
+		}
+
+		v := &exit_view{
+			orig:        where,
+			destination: dest,
+			distance:    e.exit_distance(where, dest),
+			road:        i,
+		}
+
+		// surface-cloud links are impassable (except by flying)
+		if (e.subkind(where) == sub_mountain && e.subkind(dest) == sub_cloud) ||
+			(e.subkind(where) == sub_cloud && e.subkind(dest) == sub_mountain) {
+			v.impassable = TRUE
+		}
+
+		if e.road_hidden(i) != 0 {
+			v.orig_hidden = TRUE
+			v.dest_hidden = TRUE
+
+			if !e.test_known(who, i) {
+				v.hidden = TRUE
+			}
+		}
+
+		if e.region(where) != e.region(dest) {
+			v.inside = e.region(dest)
+		}
+
+		if e.subkind(where) == sub_ocean || e.subkind(dest) == sub_ocean {
+			v.water = TRUE
+		}
+
+		*l = append(*l, v)
+	}
+}
+
+// province_exits adds the compass and up/down exits of where.
+// Ported from src/dir.c lines 531-544.
+func (e *Engine) province_exits(who, where int, l *[]*exit_view) {
+	for dir := 1; dir <= DIR_DOWN; dir++ {
+		if n := e.location_direction(where, dir); n != 0 {
+			e.add_province_exit(who, where, n, dir, l)
+		}
+	}
+}
+
+// province_sub_exits adds the sublocations inside a province, and
+// any open links leading into it from elsewhere.
+// Ported from src/dir.c lines 547-571.
+func (e *Engine) province_sub_exits(who, where int, l *[]*exit_view) {
+	for _, i := range e.rp_loc_info(where).here_list {
+		if e.is_loc_or_ship(i) {
+			e.add_province_exit(who, where, i, DIR_IN, l)
+		}
+	}
+
+	if p := e.rp_subloc(where); p != nil {
+		for _, i := range p.link_from {
+			if e.loc_link_open(i) != 0 {
+				e.add_province_exit(who, where, i, DIR_IN, l)
+			}
+		}
+	}
+}
+
+// subloc_exits adds the exits of a sublocation: the ocean for a port
+// city, what is inside, the way out, and its links while they are
+// open.
+// Ported from src/dir.c lines 574-617.
+func (e *Engine) subloc_exits(who, where int, l *[]*exit_view) {
+	if e.is_port_city(where) {
+		p := e.province(where)
+
+		for dir := 1; dir <= 4; dir++ {
+			if n := e.location_direction(p, dir); n != 0 && e.subkind(n) == sub_ocean {
+				e.add_province_exit(who, where, n, dir, l)
+			}
+		}
+	}
+
+	for _, i := range e.rp_loc_info(where).here_list {
+		if e.is_loc_or_ship(i) {
+			e.add_province_exit(who, where, i, DIR_IN, l)
+		}
+	}
+
+	e.add_province_exit(who, where, e.loc(where), DIR_OUT, l)
+
+	if p := e.rp_subloc(where); p != nil && e.loc_link_open(where) != 0 {
+		for _, i := range p.link_to {
+			e.add_province_exit(who, where, i, 0, l)
+		}
+	}
+}
+
+// ship_exits adds the way off a ship and the links to the other
+// ships around it. The links are kept even after the ships have
+// left, so that an attack on a departing ship can still be tried.
+// Ported from src/dir.c lines 622-656.
+func (e *Engine) ship_exits(who, ship int, l *[]*exit_view) {
+	if !e.is_ship_either(ship) {
+		panic("ship_exits: not a ship")
+	}
+
+	outerLoc := e.loc(ship)
+
+	e.add_province_exit(who, ship, outerLoc, DIR_OUT, l)
+
+	for _, i := range e.rp_loc_info(outerLoc).here_list {
+		if i != ship && e.is_ship_either(i) {
+			e.add_province_exit(who, ship, i, 0, l)
+		}
+	}
+}
+
+// building_exits adds the way out of a building or ship and whatever
+// is inside it.
+// Ported from src/dir.c lines 659-675.
+func (e *Engine) building_exits(who, where int, l *[]*exit_view) {
+	if e.is_ship_either(where) {
+		e.ship_exits(who, where, l)
+	} else {
+		e.add_province_exit(who, where, e.loc(where), DIR_OUT, l)
+	}
+
+	for _, i := range e.rp_loc_info(where).here_list {
+		if e.is_loc_or_ship(i) {
+			e.add_province_exit(who, where, i, DIR_IN, l)
+		}
+	}
+}
+
+// exits_from_loc returns the routes leaving where, as seen by who.
+// Ported from src/dir.c lines 678-716.
+func (e *Engine) exits_from_loc(who, where int) []*exit_view {
+	var l []*exit_view
+
+	switch e.loc_depth(where) {
+	case LOC_province:
+		e.province_exits(who, where, &l)
+		e.province_sub_exits(who, where, &l)
+
+	case LOC_subloc:
+		e.subloc_exits(who, where, &l)
+
+	case LOC_build:
+		e.province_exits(who, where, &l)
+		e.building_exits(who, where, &l)
+
+	default:
+		panic(sout("exits_from_loc: where=%d, depth=%d", where, e.loc_depth(where)))
+	}
+
+	e.extra_routes(who, where, &l) // add secret hidden roads
+
+	return l
+}
+
+// exits_from_loc_nsew returns the compass exits from a province.
+// Ported from src/dir.c lines 719-737.
+func (e *Engine) exits_from_loc_nsew(who, where int) []*exit_view {
+	if e.loc_depth(where) != LOC_province {
+		return nil
+	}
+
+	var l []*exit_view
+	e.province_exits(who, where, &l)
+
+	return l
+}
+
+// list_exit_extras notes a magical barrier or the Hades toll on a
+// route.
+// Ported from src/dir.c lines 799-824.
+func (e *Engine) list_exit_extras(who int, v *exit_view) {
+	if v.magic_barrier != 0 {
+		e.globals.indent += 3
+		wout(who, "A magical barrier prevents entry.")
+		e.globals.indent -= 3
+	}
+
+	if v.hades_cost != 0 {
+		e.globals.indent += 3
+		wout(who, "\"Notice to mortals, from the Gatekeeper "+
+			"Spirit of Hades: 100 gold/head is removed "+
+			"from any stack taking this road.\"")
+		e.globals.indent -= 3
+	}
+}
+
+// list_exits_sup lists one route, e.g.
+//
+//	East, swamp, to Athens [aa59], 15 days
+//
+// Routes to ships in transit are listed too, so that a player trying
+// to follow one gets a useful error message.
+// Ported from src/dir.c lines 839-890.
+func (e *Engine) list_exits_sup(who, where int, v *exit_view, first *string) {
+	if v.hidden != 0 && !e.see_all(who) {
+		return
+	}
+
+	if *first != "" {
+		out(who, "%s", *first)
+		e.globals.indent += 3
+		*first = ""
+	}
+
+	var ret []string
+
+	if v.direction > 0 {
+		ret = append(ret, full_dir_s[v.direction])
+	}
+
+	if e.name(v.destination) != "" && !e.is_ship_either(v.destination) {
+		ret = append(ret, subkind_s[e.subkind(v.destination)])
+	}
+
+	ret = append(ret, sout("to %s", e.box_name(v.destination)))
+
+	if v.inside != 0 {
+		if s := e.name(v.inside); s != "" {
+			ret = append(ret, s)
+		}
+	}
+
+	if v.dest_hidden != 0 {
+		ret = append(ret, "hidden")
+	}
+
+	if v.impassable != 0 {
+		ret = append(ret, "impassable")
+	} else {
+		ret = append(ret, sout("%d~day%s", v.distance, add_s(v.distance)))
+	}
+
+	wout(who, "%s", cap(strings.Join(ret, ", ")))
+
+	e.list_exit_extras(who, v)
+}
+
+// list_road_sup lists one road leading out of where.
+// Ported from src/dir.c lines 893-931.
+func (e *Engine) list_road_sup(who, where int, v *exit_view, first *string) {
+	if v.hidden != 0 && !e.see_all(who) {
+		return
+	}
+
+	hid := ""
+	if v.dest_hidden != 0 {
+		hid = "hidden, "
+	}
+
+	if *first != "" {
+		out(who, "")
+		out(who, "%s", *first)
+		*first = ""
+		e.globals.indent += 3
+	}
+
+	dist := "impassable"
+	if v.impassable == 0 && v.in_transit == 0 {
+		dist = sout("%d~day%s", v.distance, add_s(v.distance))
+	}
+
+	out(who, "%s, to %s, %s%s",
+		e.just_name(v.road), e.box_name(v.destination), hid, dist)
+
+	e.list_exit_extras(who, v)
+}
+
+// list_exits lists the routes leaving where, and warns when the
+// season has closed Uldim pass or Summerbridge.
+// Ported from src/dir.c lines 933-992.
+func (e *Engine) list_exits(who, where int) {
+	l := e.exits_from_loc(who, where)
+
+	// direction may be zero for roads and secret passages
+	first := sout("Routes leaving %s: ", e.just_name(where))
+
+	for _, v := range l {
+		if v.road == 0 && (v.direction != DIR_IN || e.see_all(who)) {
+			e.list_exits_sup(who, where, v, &first)
+		}
+	}
+
+	for _, v := range l {
+		if v.road != 0 {
+			e.list_road_sup(who, where, v, &first)
+		}
+	}
+
+	if first != "" {
+		if e.is_ship_either(where) {
+			wout(who, "No current exits from %s", e.box_name(where))
+		} else {
+			wout(who, "No known routes leaving %s", e.box_name(where))
+		}
+	} else {
+		e.globals.indent -= 3
+	}
+
+	if (e.uldim(where) == 3 || e.uldim(where) == 4 || e.summerbridge(where) != 0) &&
+		!e.summer_uldim_open_now() {
+		out(who, "")
+
+		switch {
+		case e.uldim(where) == 3:
+			wout(who, "Heavy snow blocks Uldim pass to the south.")
+		case e.uldim(where) == 4:
+			wout(who, "Heavy snow blocks Uldim pass to the north.")
+		case e.summerbridge(where) == 1:
+			wout(who, "Summerbridge to the north is impassable "+
+				"until the muds dry in the spring.")
+		case e.summerbridge(where) == 2:
+			wout(who, "Summerbridge to the south is impassable "+
+				"until the muds dry in the spring.")
+		}
+	}
+}
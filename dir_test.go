@@ -0,0 +1,169 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package taygete
+
+import (
+	"testing"
+)
+
+// setupDirTest builds two plains, north and south, each with a
+// sublocation. The southern plain has its passage north seasonal.
+func setupDirTest(t *testing.T) (south, north, cave, grove int) {
+	t.Helper()
+	newTestEngine(t)
+
+	south, north, cave, grove = 10_101, 10_102, 56_770, 56_771
+	teg.alloc_box(south, T_loc, sub_plain)
+	teg.alloc_box(north, T_loc, sub_plain)
+	teg.alloc_box(cave, T_loc, sub_cave)
+	teg.alloc_box(grove, T_loc, sub_yew_grove)
+	teg.set_where(cave, south)
+	teg.set_where(grove, north)
+
+	teg.p_loc(south).prov_dest = []int{north, 0, 0, 0}
+	teg.p_loc(north).prov_dest = []int{0, 0, south, 0}
+
+	return south, north, cave, grove
+}
+
+func findExit(l []*exit_view, dest int) *exit_view {
+	for _, v := range l {
+		if v.destination == dest {
+			return v
+		}
+	}
+	return nil
+}
+
+func TestExitDistance(t *testing.T) {
+	south, north, cave, _ := setupDirTest(t)
+
+	tests := []struct {
+		from, to, want int
+	}{
+		{south, north, 7},
+		{south, cave, 1},
+		{cave, south, 1},
+	}
+	for _, tt := range tests {
+		if got := teg.exit_distance(tt.from, tt.to); got != tt.want {
+			t.Errorf("exit_distance(%d, %d) = %d, want %d", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestExitsFromProvince(t *testing.T) {
+	south, north, cave, _ := setupDirTest(t)
+
+	l := teg.exits_from_loc(0, south)
+	if v := findExit(l, north); v == nil || v.direction != DIR_N || v.impassable != 0 {
+		t.Errorf("north exit = %+v, want a passable route north", v)
+	}
+	if v := findExit(l, cave); v == nil || v.direction != DIR_IN {
+		t.Errorf("cave exit = %+v, want a route in", v)
+	}
+
+	l = teg.exits_from_loc(0, cave)
+	if v := findExit(l, south); v == nil || v.direction != DIR_OUT {
+		t.Errorf("cave exits = %+v, want a route out", l)
+	}
+}
+
+func TestSummerbridgeSeason(t *testing.T) {
+	south, north, _, _ := setupDirTest(t)
+	teg.p_subloc(south).summer_flag = 1
+
+	tests := []struct {
+		turn      int
+		monthDone bool
+		open      bool
+	}{
+		{1, false, false}, // fierce winds
+		{2, false, false}, // snowmelt, still muddy
+		{2, true, true},   // dried by the end of snowmelt
+		{3, false, true},
+		{6, false, true},
+		{7, false, false},
+	}
+	for _, tt := range tests {
+		teg.globals.sysclock.turn = tt.turn
+		teg.globals.monthDone = tt.monthDone
+
+		v := findExit(teg.exits_from_loc(0, south), north)
+		if v == nil {
+			t.Fatalf("turn %d: no route north", tt.turn)
+		}
+		if open := v.impassable == 0; open != tt.open {
+			t.Errorf("turn %d done %v: open = %v, want %v", tt.turn, tt.monthDone, open, tt.open)
+		}
+	}
+
+	// only the passage north is seasonal
+	teg.globals.sysclock.turn = 1
+	if v := findExit(teg.exits_from_loc(0, north), south); v == nil || v.impassable != 0 {
+		t.Errorf("route south = %+v, want passable in winter", v)
+	}
+}
+
+func TestLinkedSublocExits(t *testing.T) {
+	_, north, cave, _ := setupDirTest(t)
+
+	// a faery hill style link from the cave to the northern plain
+	teg.p_subloc(cave).link_to = []int{north}
+	teg.p_subloc(north).link_from = []int{cave}
+
+	if v := findExit(teg.exits_from_loc(0, cave), north); v != nil {
+		t.Errorf("closed link listed: %+v", v)
+	}
+	if v := findExit(teg.exits_from_loc(0, north), cave); v != nil {
+		t.Errorf("closed link listed from the far side: %+v", v)
+	}
+
+	teg.p_subloc(cave).link_open = 2
+	if v := findExit(teg.exits_from_loc(0, cave), north); v == nil || v.impassable != 0 || v.direction != 0 {
+		t.Errorf("open link = %+v, want a passable route", v)
+	}
+	if v := findExit(teg.exits_from_loc(0, north), cave); v == nil || v.direction != DIR_IN {
+		t.Errorf("open link from the far side = %+v, want a route in", v)
+	}
+}
+
+func TestIsPortCity(t *testing.T) {
+	south, north, _, _ := setupDirTest(t)
+
+	city, sea := 56_774, 10_103
+	teg.alloc_box(city, T_loc, sub_city)
+	teg.alloc_box(sea, T_loc, sub_ocean)
+	teg.set_where(city, north)
+
+	if teg.is_port_city(city) {
+		t.Error("inland city is a port")
+	}
+
+	teg.p_loc(north).prov_dest = []int{sea, 0, south, 0}
+	if !teg.is_port_city(city) {
+		t.Error("coastal city is not a port")
+	}
+
+	// a port city keeps ships from landing anywhere else in the province
+	if v := findExit(teg.exits_from_loc(0, north), sea); v == nil || v.impassable == 0 || v.water == 0 {
+		t.Errorf("route to sea = %+v, want impassable water", v)
+	}
+	if v := findExit(teg.exits_from_loc(0, city), sea); v == nil || v.impassable != 0 {
+		t.Errorf("route from port = %+v, want passable", v)
+	}
+}
@@ -0,0 +1,939 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// eat.go - email order intake ported from src/eat.c
+
+package taygete
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MAX_ERR is the number of errors after which the scanner gives up
+// on the rest of a message.
+const MAX_ERR = 50
+
+// MAX_POST is the longest line allowed in a post or message.
+const MAX_POST = 60
+
+// ErrNotOrders is returned for a message with no BEGIN line. The C
+// scanner treated these as spam and dropped them.
+var ErrNotOrders = errors.New("eat: no begin line in message")
+
+// ErrNoReplyAddress is returned for a message that has no address to
+// send the acknowledgement to.
+var ErrNoReplyAddress = errors.New("eat: no reply address in message")
+
+// EatResult is the outcome of scanning one order message.
+type EatResult struct {
+	Player  int      // player the orders were for, 0 if BEGIN failed
+	ReplyTo string   // address the message came from
+	To      []string // recipients of the acknowledgement
+	Queued  int      // orders queued
+	Errors  int      // errors found
+	Looped  bool     // bounce or X-Loop; the ack goes to the GM
+	Ack     []byte   // acknowledgement message, headers included
+}
+
+// eater holds the scanner state for one message. It replaces the
+// static variables of eat.c.
+type eater struct {
+	e           *Engine
+	lines       []string // message body
+	pos         int      // next body line to read
+	lineCount   int
+	saveLine    string // copy of the line being scanned, for errors
+	pl          int    // player named on the BEGIN line
+	unit        int    // unit orders are queued for; -1 to ignore
+	emailSet    bool   // an EMAIL order was seen
+	ccAddr      string // old address, copied on the ack
+	replyAddr   string
+	alreadySeen bool
+	nQueued     int
+	nFail       int
+	out         []string // errors, warnings and scanner output
+	lore        []string // lore sheets asked for
+	posts       []string // how press and rumors will look
+}
+
+// crack_address pulls the bare address out of a header value such as
+// "Name <addr>" or "addr (Name)".
+// Ported from src/eat.c lines 166-190.
+func crack_address(s string) string {
+	if i := strings.IndexByte(s, '<'); i >= 0 {
+		t := s[i+1:]
+		if j := strings.IndexByte(t, '>'); j >= 0 {
+			t = t[:j]
+		}
+		return t
+	}
+
+	s = strings.TrimLeft(s, " \t")
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// is_bounce_address reports whether mail from addr should never be
+// answered, since it is a mailer reporting a bounce.
+// Ported from src/eat.c lines 1097-1104.
+func is_bounce_address(addr string) bool {
+	return i_strncmp(addr, "postmaster", 10) == 0 ||
+		i_strncmp(addr, "mailer-daemon", 13) == 0 ||
+		i_strncmp(addr, "mail-daemon", 11) == 0
+}
+
+// has_begin_line reports whether any line of the message starts with
+// BEGIN. Messages without one are not orders.
+func has_begin_line(raw []byte) bool {
+	sc := bufio.NewScanner(bytes.NewReader(raw))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		if i_strncmp(sc.Text(), "begin", 5) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// EatMessage scans one RFC 822 message of orders, queues the orders
+// it finds and builds the acknowledgement. The message may start with
+// an mbox "From " line. The caller saves the queued orders.
+// Port of C eat() and parse_reply().
+func (e *Engine) EatMessage(r io.Reader) (*EatResult, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("eat: read: %w", err)
+	}
+	raw = bytes.ReplaceAll(raw, []byte("\r\n"), []byte("\n"))
+
+	if !has_begin_line(raw) {
+		return nil, ErrNotOrders
+	}
+
+	text := string(raw)
+	var fromSpace string
+	if strings.HasPrefix(text, "From ") {
+		first, rest, _ := strings.Cut(text, "\n")
+		if f := strings.Fields(first[5:]); len(f) > 0 {
+			fromSpace = f[0]
+		}
+		text = rest
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(text))
+	if err != nil {
+		return nil, fmt.Errorf("eat: parse message: %w", err)
+	}
+	body, err := io.ReadAll(msg.Body)
+	if err != nil {
+		return nil, fmt.Errorf("eat: read body: %w", err)
+	}
+
+	ea := &eater{
+		e:     e,
+		lines: strings.Split(string(body), "\n"),
+	}
+
+	switch {
+	case msg.Header.Get("Reply-To") != "":
+		ea.replyAddr = crack_address(msg.Header.Get("Reply-To"))
+	case msg.Header.Get("From") != "":
+		ea.replyAddr = crack_address(msg.Header.Get("From"))
+	default:
+		ea.replyAddr = fromSpace
+	}
+	if ea.replyAddr == "" {
+		return nil, ErrNoReplyAddress
+	}
+
+	for key := range msg.Header {
+		if i_strncmp(key, "X-Loop", 6) == 0 {
+			ea.alreadySeen = true
+		}
+	}
+	if is_bounce_address(ea.replyAddr) {
+		ea.alreadySeen = true
+	}
+
+	ea.parse_and_munch()
+
+	res := &EatResult{
+		Player:  ea.pl,
+		ReplyTo: ea.replyAddr,
+		Queued:  ea.nQueued,
+		Errors:  ea.nFail,
+		Looped:  ea.alreadySeen,
+	}
+
+	var ack []string
+	ack, res.To = ea.banner()
+	ack = append(ack, "")
+	ack = append(ack, ea.out...)
+	if len(ea.lore) > 0 {
+		ack = append(ack, "")
+		ack = append(ack, ea.lore...)
+	}
+	if len(ea.posts) > 0 {
+		ack = append(ack, "")
+		ack = append(ack, ea.posts...)
+	}
+	if ea.pl != 0 {
+		ack = append(ack, "", "Current order queues:")
+		ack = append(ack, e.orders_template(ea.pl)...)
+	}
+	ack = append(ack, "", "Your message was:", "")
+	ack = append(ack, strings.Split(strings.TrimRight(string(raw), "\n"), "\n")...)
+
+	var buf bytes.Buffer
+	for _, s := range ack {
+		buf.WriteString(s)
+		buf.WriteByte('\n')
+	}
+	res.Ack = buf.Bytes()
+
+	return res, nil
+}
+
+// err records a scanner message for the current line. Only EAT_ERR
+// counts against the sender.
+// Ported from src/eat.c lines 375-385.
+func (ea *eater) err(k int, s string) {
+	if k == EAT_ERR {
+		ea.nFail++
+	}
+	ea.out = append(ea.out, fmt.Sprintf("line %d: %s: %s", ea.lineCount, s, ea.saveLine))
+}
+
+// note records a line of scanner output that isn't tied to an error.
+func (ea *eater) note(format string, args ...any) {
+	ea.out = append(ea.out, fmt.Sprintf(format, args...))
+}
+
+// eat_line_2 returns the next body line as-is, for the text of posts.
+// Ported from src/eat.c lines 303-330.
+func (ea *eater) eat_line_2(eatWhite bool) (string, bool) {
+	if ea.pos >= len(ea.lines) {
+		return "", false
+	}
+	line := remove_ctrl_chars(ea.lines[ea.pos])
+	ea.pos++
+
+	if eatWhite {
+		line = strings.TrimSpace(line)
+	}
+
+	ea.saveLine = line
+	ea.lineCount++
+	return line, true
+}
+
+// eat_next_line returns the next non-blank body line with comments
+// removed.
+// Ported from src/eat.c lines 333-372.
+func (ea *eater) eat_next_line() (string, bool) {
+	for ea.pos < len(ea.lines) {
+		line := ea.lines[ea.pos]
+		ea.pos++
+
+		line = strings.TrimSpace(remove_ctrl_chars(remove_comment(line)))
+		ea.saveLine = line
+		ea.lineCount++
+
+		if line != "" {
+			return line, true
+		}
+	}
+	return "", false
+}
+
+// next_cmd parses the next order line into c. At the end of input, or
+// after too many errors, c is left as the END command.
+// Ported from src/eat.c lines 388-426.
+func (ea *eater) next_cmd(c *command) {
+	c.cmd = 0
+
+	for {
+		line, ok := ea.eat_next_line()
+		if !ok {
+			c.cmd, _ = ea.e.find_command("end")
+			return
+		}
+
+		if !ea.e.oly_parse(c, line) {
+			ea.err(EAT_ERR, "unrecognized command")
+
+			if ea.nFail > MAX_ERR {
+				ea.err(EAT_ERR, "too many errors, aborting")
+				c.cmd, _ = ea.e.find_command("end")
+				return
+			}
+			continue
+		}
+
+		if c.fuzzy != 0 {
+			ea.err(EAT_WARN, fmt.Sprintf("assuming you meant '%s'", cmd_tbl[c.cmd].name))
+		}
+
+		return
+	}
+}
+
+// parse_and_munch runs each order in the message.
+// Ported from src/eat.c lines 958-972.
+func (ea *eater) parse_and_munch() {
+	c := &command{}
+
+	ea.next_cmd(c)
+	for cmd_tbl[c.cmd].name != "end" {
+		ea.do_eat_command(c)
+		ea.next_cmd(c)
+	}
+}
+
+// do_begin checks the player and password on the BEGIN line.
+// Ported from src/eat.c lines 429-484.
+func (ea *eater) do_begin(c *command) {
+	if ea.e.numargs(c) < 1 {
+		ea.err(EAT_ERR, "No player specified on BEGIN line")
+		return
+	}
+
+	if ea.e.kind(c.a) != T_player {
+		ea.err(EAT_ERR, "No such player")
+		return
+	}
+
+	plPass := ea.e.p_player(c.a).password
+
+	if ea.e.numargs(c) > 1 {
+		if plPass == "" {
+			ea.err(EAT_WARN, "No password is currently set")
+		} else if !ea.e.check_order_password(c.a, c.parse[2]) {
+			ea.err(EAT_ERR, "Incorrect password")
+			return
+		}
+	} else if plPass != "" {
+		ea.err(EAT_ERR, "Incorrect password")
+		ea.err(EAT_ERR, "Must give password on BEGIN line.")
+		return
+	}
+
+	ea.pl = c.a
+
+	ea.e.p_player(ea.pl).sent_orders = 1
+	ea.e.p_player(ea.pl).last_email = ea.replyAddr
+}
+
+// valid_char_or_player reports whether orders may be queued for who.
+// Ported from src/eat.c lines 487-498.
+func (e *Engine) valid_char_or_player(who int) bool {
+	if e.kind(who) == T_char || e.kind(who) == T_player {
+		return true
+	}
+
+	if e.kind(who) == T_item && e.subkind(who) == sub_dead_body {
+		return true
+	}
+
+	return false
+}
+
+// do_unit starts the orders for a unit, flushing any it had queued.
+// Ported from src/eat.c lines 501-533.
+func (ea *eater) do_unit(c *command) {
+	ea.unit = -1 // ignore following unit commands
+
+	if ea.pl == 0 {
+		ea.err(EAT_ERR, "BEGIN must appear before UNIT")
+		ea.note("      rest of commands for unit ignored")
+		return
+	}
+
+	if ea.e.kind(c.a) == T_unform {
+		if !containsInt(ea.e.getPlayerUnformed(ea.pl), c.a) {
+			ea.err(EAT_WARN, "Not an unformed unit of yours")
+		}
+	} else if !ea.e.valid_char_or_player(c.a) {
+		ea.err(EAT_ERR, "Not a character or unformed unit")
+	} else if ea.e.player(c.a) != ea.pl {
+		ea.err(EAT_WARN, "Not one of your controlled characters")
+	}
+
+	ea.unit = c.a
+	ea.e.flush_unit_orders(ea.pl, ea.unit)
+}
+
+// do_email changes the player's address. The old address is copied
+// on the acknowledgement.
+// Ported from src/eat.c lines 536-565.
+func (ea *eater) do_email(c *command) {
+	if ea.emailSet {
+		ea.err(EAT_ERR, "no more than one EMAIL order per message")
+		ea.note("      new email address not set")
+		return
+	}
+
+	if ea.pl == 0 {
+		ea.err(EAT_ERR, "BEGIN must come before EMAIL")
+		ea.note("      new email address not set")
+		return
+	}
+
+	if ea.e.numargs(c) < 1 || c.parse[1] == "" {
+		ea.err(EAT_ERR, "no new email address given")
+		ea.note("      new email address not set")
+		return
+	}
+
+	ea.emailSet = true
+	ea.ccAddr = ea.e.p_player(ea.pl).email
+	ea.e.p_player(ea.pl).email = c.parse[1]
+}
+
+// do_vis_email sets the address shown in the player list.
+// Ported from src/eat.c lines 568-588.
+func (ea *eater) do_vis_email(c *command) {
+	if ea.pl == 0 {
+		ea.err(EAT_ERR, "BEGIN must come before VIS_EMAIL")
+		ea.note("      new address not set")
+		return
+	}
+
+	if ea.e.numargs(c) < 1 || c.parse[1] == "" {
+		ea.e.p_player(ea.pl).vis_email = ""
+		return
+	}
+
+	ea.e.p_player(ea.pl).vis_email = c.parse[1]
+}
+
+// do_lore resends a lore sheet the player has already seen.
+// Ported from src/eat.c lines 591-621.
+func (ea *eater) do_lore(c *command) {
+	sheet := c.a
+
+	if ea.pl == 0 {
+		ea.err(EAT_ERR, "BEGIN must appear before LORE")
+		return
+	}
+
+	if ea.e.kind(sheet) == T_item {
+		sheet = ea.e.item_lore(sheet)
+	}
+
+	if !ea.e.valid_box(sheet) {
+		ea.err(EAT_ERR, "no such lore sheet")
+		return
+	}
+
+	if !ea.e.test_known(ea.pl, sheet) {
+		ea.err(EAT_ERR, "you haven't seen that lore sheet before")
+		return
+	}
+
+	// deliver_lore writes to the player's turn report; move what
+	// it wrote onto the acknowledgement instead.
+	g := &ea.e.globals
+	n := len(g.reports[ea.pl])
+	ea.e.deliver_lore(ea.pl, c.a)
+	ea.lore = append(ea.lore, g.reports[ea.pl][n:]...)
+	g.reports[ea.pl] = g.reports[ea.pl][:n]
+}
+
+// do_format sets the report formatting option.
+// Ported from src/eat.c lines 684-698.
+func (ea *eater) do_format(c *command) {
+	if ea.pl == 0 {
+		ea.err(EAT_ERR, "BEGIN must appear before FORMAT")
+		return
+	}
+
+	ea.e.p_player(ea.pl).format = schar(c.a)
+	ea.err(EAT_WARN, fmt.Sprintf("Report formatting set to %d", c.a))
+}
+
+// do_split sets the limits at which mailed reports are split.
+// Ported from src/eat.c lines 701-743.
+func (ea *eater) do_split(c *command) {
+	lines := c.a
+	bytes := c.b
+
+	if ea.pl == 0 {
+		ea.err(EAT_ERR, "BEGIN must appear before SPLIT")
+		return
+	}
+
+	if lines > 0 && lines < 500 {
+		lines = 500
+		ea.note("Minimum lines to split at is 500")
+	}
+
+	if bytes > 0 && bytes < 10000 {
+		bytes = 10000
+		ea.note("Minimum bytes to split at is 10,000")
+	}
+
+	ea.e.p_player(ea.pl).split_lines = lines
+	ea.e.p_player(ea.pl).split_bytes = bytes
+
+	switch {
+	case lines == 0 && bytes == 0:
+		ea.note("Reports will not be split when mailed.")
+	case lines != 0 && bytes != 0:
+		ea.note("Reports will be split at %d lines or %d bytes, whichever limit is hit first.", lines, bytes)
+	case lines != 0:
+		ea.note("Reports will be split at %d lines.", lines)
+	default:
+		ea.note("Reports will be split at %d bytes.", bytes)
+	}
+}
+
+// do_notab sets whether reports may contain TAB characters.
+// Ported from src/eat.c lines 746-767.
+func (ea *eater) do_notab(c *command) {
+	if ea.pl == 0 {
+		ea.err(EAT_ERR, "BEGIN must appear before NOTAB")
+		return
+	}
+
+	ea.e.p_player(ea.pl).notab = schar(c.a)
+
+	if c.a != 0 {
+		ea.note("No TAB characters will appear in turn reports.")
+	} else {
+		ea.note("TAB characters may appear in turn reports.")
+	}
+}
+
+// do_password sets or clears the password checked on the BEGIN line.
+// Ported from src/eat.c lines 770-799.
+func (ea *eater) do_password(c *command) {
+	if ea.pl == 0 {
+		ea.err(EAT_ERR, "BEGIN must appear before PASSWORD")
+		return
+	}
+
+	if ea.e.numargs(c) < 1 || c.parse[1] == "" {
+		_ = ea.e.set_order_password(ea.pl, "")
+		ea.note("Password cleared.")
+		return
+	}
+
+	if err := ea.e.set_order_password(ea.pl, c.parse[1]); err != nil {
+		ea.err(EAT_ERR, "Password could not be set")
+		return
+	}
+	ea.note("Password set to \"%s\".", c.parse[1])
+}
+
+// show_post shows how a press or rumor post will look.
+// Ported from src/eat.c lines 802-836.
+func (ea *eater) show_post(l []string, cmd string) {
+	for _, s := range l {
+		if strings.HasPrefix(s, "=-=-") {
+			ea.posts = append(ea.posts, "> "+s)
+		} else {
+			ea.posts = append(ea.posts, s)
+		}
+	}
+
+	attrib := strings.ReplaceAll("-- "+ea.e.box_name(ea.unit), "~", " ")
+
+	ea.posts = append(ea.posts, "")
+
+	if cmd == "press" {
+		ea.posts = append(ea.posts, fmt.Sprintf("%55s", attrib), "")
+	}
+
+	ea.posts = append(ea.posts, strings.Repeat("=-", 36)+"=")
+}
+
+// queue queues an order for the current unit, marking it as having
+// arrived by email.
+func (ea *eater) queue(s string) {
+	ea.e.queue_order(ea.pl, ea.unit, s)
+	ea.tag_email()
+}
+
+// tag_email marks the unit's queued orders that have no source as
+// having arrived by email.
+func (ea *eater) tag_email() {
+	q := ea.e.rp_order_head(ea.pl, ea.unit)
+	if q == nil {
+		return
+	}
+	for _, o := range q.Orders {
+		if o.SourceChannel == "" {
+			o.SourceChannel = "email"
+		}
+	}
+}
+
+// do_eat_command runs the scanner directives and queues everything
+// else for the current unit.
+// Ported from src/eat.c lines 839-955.
+func (ea *eater) do_eat_command(c *command) {
+	cmd := cmd_tbl[c.cmd].name
+
+	switch cmd {
+	case "begin":
+		ea.do_begin(c)
+		return
+	case "unit":
+		ea.do_unit(c)
+		return
+	case "email":
+		ea.do_email(c)
+		return
+	case "vis_email":
+		ea.do_vis_email(c)
+		return
+	case "lore":
+		ea.do_lore(c)
+		return
+	case "resend":
+		ea.err(EAT_ERR, "Sorry, reports are not resent by mail")
+		return
+	case "format":
+		ea.do_format(c)
+		return
+	case "notab":
+		ea.do_notab(c)
+		return
+	case "split":
+		ea.do_split(c)
+		return
+	case "players":
+		ea.err(EAT_ERR, "Sorry, couldn't find the player list.")
+		return
+	case "passwd", "password":
+		ea.do_password(c)
+		return
+	}
+
+	if ea.unit == 0 {
+		ea.err(EAT_ERR, "can't queue orders, missing UNIT command")
+		ea.unit = -1
+		return
+	}
+
+	if ea.unit == -1 {
+		ea.nFail++
+		return
+	}
+
+	if cmd == "stop" {
+		ea.e.queue_stop(ea.pl, ea.unit)
+		ea.tag_email()
+	} else {
+		ea.queue(c.line)
+	}
+	ea.nQueued++
+
+	if cmd == "wait" {
+		if s := ea.e.parse_wait_args(c); s != "" {
+			ea.err(EAT_ERR, fmt.Sprintf("Bad WAIT: %s", s))
+		}
+		clear_wait_parse(c)
+	}
+
+	if cmd == "post" || cmd == "message" || cmd == "rumor" || cmd == "press" {
+		ea.eat_post(c, cmd)
+	}
+}
+
+// eat_post queues the text lines that follow a post, message, rumor
+// or press order. Either c.a lines follow, or lines up to an END.
+// Ported from src/eat.c lines 911-952.
+func (ea *eater) eat_post(c *command, cmd string) {
+	count := c.a
+	maxLen := MAX_POST
+	rejectFlag := false
+	var l []string
+
+	if cmd == "rumor" || cmd == "press" {
+		maxLen = 78
+	}
+
+	for {
+		s, ok := ea.eat_line_2(cmd == "post" || cmd == "message")
+		if !ok {
+			ea.err(EAT_ERR, "End of input reached before end of post!")
+			break
+		}
+
+		if len(s) > maxLen {
+			ea.err(EAT_ERR, fmt.Sprintf("Line length exceeds %d characters", maxLen))
+			rejectFlag = true
+		}
+
+		ea.queue(s)
+
+		if count == 0 {
+			if i_strcmp(strings.TrimSpace(s), "end") == 0 {
+				break
+			}
+			l = append(l, s)
+		} else {
+			l = append(l, s)
+			if count--; count <= 0 {
+				break
+			}
+		}
+	}
+
+	if rejectFlag {
+		ea.err(EAT_ERR, "Post will be rejected.")
+	} else if cmd == "press" || cmd == "rumor" {
+		ea.show_post(l, cmd)
+	}
+}
+
+// banner returns the acknowledgement headers and greeting, and the
+// addresses the acknowledgement goes to.
+// Ported from src/eat.c lines 975-1033.
+func (ea *eater) banner() ([]string, []string) {
+	var l []string
+	to := ea.replyAddr
+	fullName := ""
+
+	l = append(l, "From: "+from_host, "Reply-To: "+reply_host)
+
+	if ea.pl != 0 {
+		if p := ea.e.rp_player(ea.pl); p != nil {
+			if p.email != "" {
+				to = p.email
+			}
+			if p.full_name != "" {
+				fullName = fmt.Sprintf(" (%s)", p.full_name)
+			}
+		}
+	}
+
+	if ea.alreadySeen {
+		to = gm_address
+		fullName = " (Error Watcher)"
+		ea.ccAddr = ""
+	}
+
+	whoTo := []string{to}
+	l = append(l, fmt.Sprintf("To: %s%s", to, fullName))
+
+	if ea.ccAddr != "" {
+		l = append(l, "Cc: "+ea.ccAddr)
+		whoTo = append(whoTo, ea.ccAddr)
+	}
+
+	l = append(l, "Subject: Acknowledge", "X-Loop: "+from_host, "")
+	l = append(l, "     - Olympia order scanner -", "")
+	if ea.pl != 0 {
+		l = append(l, fmt.Sprintf("Hello, %s", ea.e.box_name(ea.pl)))
+	}
+	l = append(l, "")
+
+	s := "s"
+	if ea.nFail == 1 {
+		s = ""
+	}
+	l = append(l, fmt.Sprintf("%d queued, %d error%s.", ea.nQueued, ea.nFail, s))
+
+	return l, whoTo
+}
+
+// spoolMessage is one message waiting in a spool, with the function
+// that removes it from the spool once it has been eaten.
+type spoolMessage struct {
+	name string
+	raw  []byte
+	done func() error
+}
+
+// read_maildir returns the messages in a maildir's new/ directory.
+// Eaten messages are moved to cur/ and marked seen.
+func read_maildir(dir string) ([]spoolMessage, error) {
+	newDir := filepath.Join(dir, "new")
+	curDir := filepath.Join(dir, "cur")
+	entries, err := os.ReadDir(newDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs []spoolMessage
+	for _, ent := range entries {
+		if ent.IsDir() || strings.HasPrefix(ent.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(newDir, ent.Name())
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		dest := filepath.Join(curDir, ent.Name()+":2,S")
+		msgs = append(msgs, spoolMessage{
+			name: ent.Name(),
+			raw:  raw,
+			done: func() error {
+				if err := os.MkdirAll(curDir, 0o755); err != nil {
+					return err
+				}
+				return os.Rename(path, dest)
+			},
+		})
+	}
+	return msgs, nil
+}
+
+// split_mbox splits an mbox file into its messages. Each message
+// starts with a "From " line; ">From " quoting in the body is undone.
+func split_mbox(raw []byte) [][]byte {
+	var msgs [][]byte
+	var cur []byte
+	prevBlank := true
+
+	for _, line := range bytes.SplitAfter(raw, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if prevBlank && bytes.HasPrefix(line, []byte("From ")) && cur != nil {
+			msgs = append(msgs, cur)
+			cur = nil
+		}
+		if bytes.HasPrefix(line, []byte(">From ")) {
+			line = line[1:]
+		}
+		cur = append(cur, line...)
+		prevBlank = len(bytes.TrimRight(line, "\r\n")) == 0
+	}
+	if len(bytes.TrimSpace(cur)) > 0 {
+		msgs = append(msgs, cur)
+	}
+	return msgs
+}
+
+// read_mbox_dir returns the messages in each mbox file in dir. A file
+// holding a single message without a "From " line is also accepted.
+// Files are removed once all of their messages have been eaten.
+func read_mbox_dir(dir string) ([]spoolMessage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs []spoolMessage
+	for _, ent := range entries {
+		if ent.IsDir() || strings.HasPrefix(ent.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, ent.Name())
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		parts := split_mbox(raw)
+		for i, part := range parts {
+			m := spoolMessage{
+				name: fmt.Sprintf("%s.%d", ent.Name(), i+1),
+				raw:  part,
+				done: func() error { return nil },
+			}
+			if i == len(parts)-1 {
+				m.done = func() error { return os.Remove(path) }
+			}
+			msgs = append(msgs, m)
+		}
+	}
+	return msgs, nil
+}
+
+// EatSpool eats every message waiting in spool, which is either a
+// maildir or a directory of mbox files. Orders are saved for the
+// current turn along with the player settings that changed, and an
+// acknowledgement for each message is written to ackDir. Messages
+// are taken out of the spool only after everything has been saved.
+// Port of C read_spool().
+func (e *Engine) EatSpool(spool, ackDir string) ([]*EatResult, error) {
+	var msgs []spoolMessage
+	var err error
+	if fi, serr := os.Stat(filepath.Join(spool, "new")); serr == nil && fi.IsDir() {
+		msgs, err = read_maildir(spool)
+	} else {
+		msgs, err = read_mbox_dir(spool)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("eat: read spool: %w", err)
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].name < msgs[j].name })
+
+	if err := os.MkdirAll(ackDir, 0o755); err != nil {
+		return nil, fmt.Errorf("eat: ack dir: %w", err)
+	}
+
+	turn := int(e.globals.sysclock.turn)
+	e.ClearOrders()
+	if err := e.LoadOrders(turn); err != nil {
+		return nil, fmt.Errorf("eat: load orders: %w", err)
+	}
+
+	var results []*EatResult
+	for _, m := range msgs {
+		res, err := e.EatMessage(bytes.NewReader(m.raw))
+		if errors.Is(err, ErrNotOrders) || errors.Is(err, ErrNoReplyAddress) {
+			if e.logger != nil {
+				e.logger.Warn("eat: ignoring message", "msg", m.name, "err", err)
+			}
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("eat: %s: %w", m.name, err)
+		}
+
+		path := filepath.Join(ackDir, m.name+".ack")
+		if err := os.WriteFile(path, res.Ack, 0o644); err != nil {
+			return nil, fmt.Errorf("eat: write ack: %w", err)
+		}
+		results = append(results, res)
+	}
+
+	// The world and the orders that changed it are saved together
+	err = e.saveWorld(e.dirty == nil, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO turns (turn_number) VALUES (?)`, turn); err != nil {
+			return fmt.Errorf("turn %d: %w", turn, err)
+		}
+		return e.writeOrders(tx, turn)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("eat: save: %w", err)
+	}
+
+	for _, m := range msgs {
+		if err := m.done(); err != nil {
+			return nil, fmt.Errorf("eat: remove %s from spool: %w", m.name, err)
+		}
+	}
+
+	return results, nil
+}
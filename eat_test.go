@@ -0,0 +1,349 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package taygete
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupEatTest builds a faction with one noble and returns the codes
+// used on the BEGIN and UNIT lines.
+func setupEatTest(t *testing.T) (pl, who int, plCode, whoCode string) {
+	t.Helper()
+	newTestEngine(t)
+
+	pl, who = 50_001, 1001
+	teg.alloc_box(pl, T_player, sub_pl_regular)
+	teg.set_name(pl, "Red Company")
+	teg.p_player(pl).email = "ann@example.com"
+	teg.alloc_box(who, T_char, 0)
+	teg.set_name(who, "Osswid")
+	teg.p_char(who).unit_lord = pl
+	teg.p_char(who).health = 100
+
+	return pl, who, box_code_less(pl), box_code_less(who)
+}
+
+// orderMail returns an order message from ann with the given body.
+func orderMail(body string) string {
+	return "From: Ann <ann@example.com>\n" +
+		"To: orders@example.com\n" +
+		"Subject: orders\n" +
+		"\n" + body
+}
+
+func TestEatMessage(t *testing.T) {
+	pl, who, plCode, whoCode := setupEatTest(t)
+
+	msg := orderMail("begin " + plCode + "\n" +
+		"unit " + whoCode + "\n" +
+		"  study 600   # combat\n" +
+		"\n" +
+		"  move north\n" +
+		"  stop\n" +
+		"end\n" +
+		"unit " + whoCode + "\n")
+
+	res, err := teg.EatMessage(strings.NewReader(msg))
+	if err != nil {
+		t.Fatalf("EatMessage: %v", err)
+	}
+	if res.Player != pl || res.Queued != 3 || res.Errors != 0 {
+		t.Errorf("result = player %d, %d queued, %d errors", res.Player, res.Queued, res.Errors)
+	}
+	if res.ReplyTo != "ann@example.com" {
+		t.Errorf("ReplyTo = %q", res.ReplyTo)
+	}
+
+	q := teg.GetOrderQueue(pl, who)
+	if q == nil || len(q.Orders) != 3 {
+		t.Fatalf("queue = %v, want 3 orders", teg.GetAllOrders(pl, who))
+	}
+	want := []string{"stop", "study 600", "move north"}
+	for i, o := range q.Orders {
+		if o.RawText != want[i] || o.SourceChannel != "email" {
+			t.Errorf("order %d = %q from %q, want %q from email", i, o.RawText, o.SourceChannel, want[i])
+		}
+	}
+
+	if p := teg.rp_player(pl); p.sent_orders != 1 || p.last_email != "ann@example.com" {
+		t.Errorf("player sent_orders, last_email = %d %q", p.sent_orders, p.last_email)
+	}
+
+	ack := string(res.Ack)
+	for _, s := range []string{
+		"To: ann@example.com\n",
+		"Subject: Acknowledge\n",
+		"X-Loop: " + from_host + "\n",
+		"Hello, " + teg.box_name(pl) + "\n",
+		"3 queued, 0 errors.\n",
+		"begin " + plCode + "  # Red Company\n",
+		"   move north\n",
+		"Your message was:\n",
+	} {
+		if !strings.Contains(ack, s) {
+			t.Errorf("ack missing %q:\n%s", s, ack)
+		}
+	}
+}
+
+func TestEatBeginPassword(t *testing.T) {
+	pl, _, plCode, whoCode := setupEatTest(t)
+	if err := teg.set_order_password(pl, "Swordfish"); err != nil {
+		t.Fatalf("set_order_password: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		begin  string
+		player int
+		errs   int
+	}{
+		{"no password", "begin " + plCode, 0, 2},
+		{"wrong password", "begin " + plCode + " tuna", 0, 1},
+		{"right password", "begin " + plCode + " swordfish", pl, 0},
+		{"no such player", "begin 9999", 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teg.ClearOrders()
+			res, err := teg.EatMessage(strings.NewReader(orderMail(
+				tt.begin + "\nunit " + whoCode + "\nmove north\n")))
+			if err != nil {
+				t.Fatalf("EatMessage: %v", err)
+			}
+			if res.Player != tt.player {
+				t.Errorf("Player = %d, want %d", res.Player, tt.player)
+			}
+			// Without a BEGIN the UNIT line and its order are
+			// errors too
+			errs := tt.errs
+			if tt.player == 0 {
+				errs += 2
+			}
+			if res.Errors != errs {
+				t.Errorf("Errors = %d, want %d:\n%s", res.Errors, errs, res.Ack)
+			}
+		})
+	}
+}
+
+func TestEatDirectives(t *testing.T) {
+	pl, _, plCode, _ := setupEatTest(t)
+
+	res, err := teg.EatMessage(strings.NewReader(orderMail("begin " + plCode + "\n" +
+		"email ann@new.example.com\n" +
+		"vis_email \"ann at example\"\n" +
+		"format 2\n" +
+		"split 100 0\n" +
+		"notab 1\n" +
+		"password hunter2\n")))
+	if err != nil {
+		t.Fatalf("EatMessage: %v", err)
+	}
+	if res.Errors != 0 {
+		t.Errorf("Errors = %d:\n%s", res.Errors, res.Ack)
+	}
+
+	p := teg.rp_player(pl)
+	if p.email != "ann@new.example.com" || p.vis_email != "ann at example" {
+		t.Errorf("email, vis_email = %q %q", p.email, p.vis_email)
+	}
+	if p.format != 2 || p.notab != 1 || p.split_lines != 500 || p.split_bytes != 0 {
+		t.Errorf("format %d notab %d split %d %d", p.format, p.notab, p.split_lines, p.split_bytes)
+	}
+	if !is_password_hash(p.password) || !teg.check_order_password(pl, "HUNTER2") {
+		t.Errorf("password = %q, want a hash of hunter2", p.password)
+	}
+
+	// The ack goes to the new address, copied to the old one
+	if len(res.To) != 2 || res.To[0] != "ann@new.example.com" || res.To[1] != "ann@example.com" {
+		t.Errorf("To = %v", res.To)
+	}
+	ack := string(res.Ack)
+	for _, s := range []string{
+		"Minimum lines to split at is 500\n",
+		"Reports will be split at 500 lines.\n",
+		"No TAB characters will appear in turn reports.\n",
+		"Password set to \"hunter2\".\n",
+		"begin " + plCode + " \"password\"  # Red Company\n",
+	} {
+		if !strings.Contains(ack, s) {
+			t.Errorf("ack missing %q:\n%s", s, ack)
+		}
+	}
+}
+
+func TestEatNotOrders(t *testing.T) {
+	setupEatTest(t)
+
+	_, err := teg.EatMessage(strings.NewReader(orderMail("Buy cheap watches!\n")))
+	if !errors.Is(err, ErrNotOrders) {
+		t.Errorf("err = %v, want ErrNotOrders", err)
+	}
+}
+
+func TestEatLooped(t *testing.T) {
+	_, _, plCode, _ := setupEatTest(t)
+
+	for _, hdr := range []string{
+		"From: MAILER-DAEMON@example.com\n",
+		"From: ann@example.com\nX-Loop: " + from_host + "\n",
+	} {
+		res, err := teg.EatMessage(strings.NewReader(hdr + "\nbegin " + plCode + "\n"))
+		if err != nil {
+			t.Fatalf("EatMessage: %v", err)
+		}
+		if !res.Looped || len(res.To) != 1 || res.To[0] != gm_address {
+			t.Errorf("%q: Looped %v, To %v", hdr, res.Looped, res.To)
+		}
+	}
+}
+
+func TestEatSpool(t *testing.T) {
+	pl, who, plCode, whoCode := setupEatTest(t)
+
+	spool := t.TempDir()
+	acks := t.TempDir()
+	for _, dir := range []string{"new", "cur", "tmp"} {
+		if err := os.Mkdir(filepath.Join(spool, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(name, body string) {
+		if err := os.WriteFile(filepath.Join(spool, "new", name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("1.orders", "From ann@example.com Mon Oct 19 12:00:00 2026\n"+
+		orderMail("begin "+plCode+"\nunit "+whoCode+"\nmove north\nunit "+plCode+"\nflush\n"))
+	write("2.spam", orderMail("Buy cheap watches!\n"))
+
+	results, err := teg.EatSpool(spool, acks)
+	if err != nil {
+		t.Fatalf("EatSpool: %v", err)
+	}
+	if len(results) != 1 || results[0].Player != pl || results[0].Queued != 2 {
+		t.Fatalf("results = %+v", results)
+	}
+
+	if _, err := os.Stat(filepath.Join(acks, "1.orders.ack")); err != nil {
+		t.Errorf("ack not written: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(spool, "cur", "1.orders:2,S")); err != nil {
+		t.Errorf("message not moved to cur: %v", err)
+	}
+	if left, _ := os.ReadDir(filepath.Join(spool, "new")); len(left) != 0 {
+		t.Errorf("new/ still has %d messages", len(left))
+	}
+
+	// Orders for the faction itself have no character to refer to
+	rows, err := teg.db.Query(`SELECT source_char_id, extra, source_channel FROM orders ORDER BY id`)
+	if err != nil {
+		t.Fatalf("query orders: %v", err)
+	}
+	var n int
+	for rows.Next() {
+		var char sql.NullInt64
+		var extra, channel sql.NullString
+		if err := rows.Scan(&char, &extra, &channel); err != nil {
+			t.Fatal(err)
+		}
+		if channel.String != "email" {
+			t.Errorf("source_channel = %q, want email", channel.String)
+		}
+		if char.Valid == extra.Valid {
+			t.Errorf("source_char_id %v, extra %v: want exactly one", char, extra)
+		}
+		n++
+	}
+	rows.Close()
+	if n != 2 {
+		t.Fatalf("%d orders saved, want 2", n)
+	}
+
+	teg.ClearOrders()
+	if err := teg.LoadOrders(int(teg.globals.sysclock.turn)); err != nil {
+		t.Fatalf("LoadOrders: %v", err)
+	}
+	if got := teg.GetAllOrders(pl, who); len(got) != 1 || got[0] != "move north" {
+		t.Errorf("unit orders = %q", got)
+	}
+	if got := teg.GetAllOrders(pl, pl); len(got) != 1 || got[0] != "flush" {
+		t.Errorf("faction orders = %q", got)
+	}
+}
+
+func TestSplitMbox(t *testing.T) {
+	raw := "From a@example.com Mon Oct 19 12:00:00 2026\n" +
+		"From: a@example.com\n\nbegin 1\n>From the top\n\n" +
+		"From b@example.com Mon Oct 19 12:01:00 2026\n" +
+		"From: b@example.com\n\nbegin 2\n"
+
+	msgs := split_mbox([]byte(raw))
+	if len(msgs) != 2 {
+		t.Fatalf("split_mbox = %d messages, want 2", len(msgs))
+	}
+	if !strings.Contains(string(msgs[0]), "\nFrom the top\n") {
+		t.Errorf("quoted From not undone:\n%s", msgs[0])
+	}
+	if !strings.HasPrefix(string(msgs[1]), "From b@example.com") {
+		t.Errorf("second message = %q", msgs[1])
+	}
+}
+
+func TestEatSpoolSavesAtomically(t *testing.T) {
+	_, _, plCode, whoCode := setupEatTest(t)
+
+	spool := t.TempDir()
+	for _, dir := range []string{"new", "cur", "tmp"} {
+		if err := os.Mkdir(filepath.Join(spool, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	body := orderMail("begin " + plCode + "\nunit " + whoCode + "\nmove north\n")
+	if err := os.WriteFile(filepath.Join(spool, "new", "1.orders"), []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fail writing the orders: the world must not be saved without them
+	if _, err := teg.db.Exec(`
+		CREATE TRIGGER fail_orders BEFORE INSERT ON orders
+		BEGIN SELECT RAISE(ABORT, 'disk full'); END
+	`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+	if _, err := teg.EatSpool(spool, t.TempDir()); err == nil {
+		t.Fatal("EatSpool succeeded without saving orders")
+	}
+
+	var n int
+	if err := teg.db.QueryRow(`SELECT COUNT(*) FROM players`).Scan(&n); err != nil {
+		t.Fatalf("count players: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("%d players saved without their orders", n)
+	}
+	if left, _ := os.ReadDir(filepath.Join(spool, "new")); len(left) != 1 {
+		t.Errorf("new/ has %d messages, want the unsaved one left", len(left))
+	}
+}
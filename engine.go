@@ -18,25 +18,33 @@ package taygete
 
 import (
 	"database/sql"
+	"errors"
 	"log/slog"
 	"math/rand/v2"
 
 	"github.com/mdhender/prng"
 )
 
-var (
-	// use a global Engine while porting
-	teg *Engine
-)
-
+// Engine holds the complete state of one game. Every ported routine is
+// a method on Engine, so separate engines never share state and may run
+// turns concurrently.
 type Engine struct {
-	db     *sql.DB
-	logger *slog.Logger
-	prng   *prng.Rand
+	db      *sql.DB
+	logger  *slog.Logger
+	prng    *prng.Rand
+	seed    uint64                            // game seed the named streams derive from, see rnd.go
+	streams map[string]*prng.Rand             // named random streams
+	saved   map[*saveTable]map[int]saveDigest // digests of the rows last written or read, for checkSaves
+
+	// change tracking for SaveWorld, see save.go
+	dirty      map[int]bool // entities changed since the last load or save, nil before either
+	savedTurn  short        // turn on the clock at the last load or save
+	checkSaves bool         // check that no entity changed without being marked dirty
+
 	// use this globals struct for C globals while porting.
 	// as we refactor, these will become state in Engine.
 	globals struct {
-		bx       [MAX_BOXES]*box
+		bx       boxStore // entities by ID, see store.go
 		box_head [T_MAX]int
 		sub_head [SUB_MAX]int
 
@@ -63,6 +71,15 @@ type Engine struct {
 		// Inventory storage - workaround for C-style **item_ent in box
 		inventories map[int][]item_ent
 
+		// Locations flagged is_start_loc, where new factions begin
+		startLocs map[int]bool
+
+		// Lore sheets by skill or lore number, loaded from skill_lore
+		loreSheets map[int][]string
+
+		// Turn report lines - maps entity ID -> lines written this turn
+		reports map[int][]string
+
 		// Order queues - maps player ID -> (unit ID -> OrderQueue)
 		// Replaces C entity_player.orders plist
 		orderQueues map[int]map[int]*OrderQueue
@@ -88,9 +105,45 @@ type Engine struct {
 		faeryRegion    int // Faery realm region ID
 		hadesRegion    int // Hades realm region ID
 		nowhereRegion  int // Nowhere region ID
+		nowhereLoc     int // Nowhere location holding unassigned relics
 		cloudRegion    int // Cloud realm region ID
 		tunnelRegion   int // Tunnel realm region ID
 		underRegion    int // Underground realm region ID
+
+		// Gold income statistics, summed over the turn for the GM report
+		// (from gm.c, alchem.c, relig.c and u.c)
+		gold_common_magic int
+		gold_pot_basket   int
+		gold_trade        int
+		gold_inn          int
+		gold_taxes        int
+		gold_provinces    int
+		gold_times        int
+		gold_petty_thief  int
+		gold_pillage      int
+		gold_ferry        int
+		gold_opium        int
+		gold_garrison     int
+		gold_lead_to_gold int // gold created by alchemy
+		gold_temple       int // gold collected as temple offerings
+		gold_combat       int // gold taken from players in combat
+		gold_combat_indep int // gold taken from independents in combat
+
+		// Flags raised and units waiting this turn (from c1.c)
+		flags          []*flag_ent
+		wait_list      []int
+		collectors     []int // units running COLLECT (from produce.c)
+		waitParseLists map[*command][]*waitArgExt // stands in for command.wait_parse
+
+		// Movement and output state (from move.c and u.c)
+		ocean_chars      []int // characters flying over ocean
+		show_to_garrison bool  // garrison units see the current messages
+		indent           int   // current output indentation level
+
+		weather_days        []int          // days of the month natural storms form (from day.c)
+		weather_turn        short          // turn weather_days were drawn for
+		orb_used_this_month IList          // orbs used this month (from art.c)
+		savedNames          map[int]string // original names of dead bodies (replaces entity_misc.save_name)
 	}
 }
 
@@ -112,6 +165,7 @@ func (e *Engine) setName(n int, s string) {
 	} else {
 		e.globals.names[n] = s
 	}
+	e.markDirty(n)
 }
 
 // getBanner returns the display banner for entity n.
@@ -132,6 +186,7 @@ func (e *Engine) setBanner(n int, s string) {
 	} else {
 		e.globals.banners[n] = s
 	}
+	e.markDirty(n)
 }
 
 // getPluralName returns the plural name for item n.
@@ -152,6 +207,7 @@ func (e *Engine) setPluralName(n int, s string) {
 	} else {
 		e.globals.pluralNames[n] = s
 	}
+	e.markDirty(n)
 }
 
 // getPlayerKnowledge returns the knowledge set for player pl.
@@ -171,6 +227,7 @@ func (e *Engine) setPlayerKnowledge(pl, i int) {
 		e.globals.playerKnowledge[pl] = make(map[int]bool)
 	}
 	e.globals.playerKnowledge[pl][i] = true
+	e.markDirty(pl)
 }
 
 // clearPlayerKnowledge clears all knowledge for player pl.
@@ -181,30 +238,25 @@ func (e *Engine) clearPlayerKnowledge(pl int) {
 	if known := e.globals.playerKnowledge[pl]; known != nil {
 		clear_know_rec(known)
 	}
+	e.markDirty(pl)
 }
 
 func NewEngine(db *sql.DB, p *prng.Rand) (*Engine, error) {
 	if p == nil {
 		p = prng.New(rand.NewPCG(0xC0FFEECAFE, 0xBEEFF00D))
 	}
-	e := &Engine{db: db, prng: p}
+	e := &Engine{db: db, prng: p, seed: seedOf(p), logger: slog.Default()}
+	e.globals.bx = boxStore{}
+	e.globals.garrison_magic = 999
+	// A new database has no saved state; keep the seeded generator.
 	err := e.restorePrngState(".")
-	if err != nil {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		e.logger.Error("new engine", "err", err)
 		return nil, err
 	}
-	return e, nil
-}
-
-func init() {
-	db, err := OpenTestDB()
-	if err != nil {
-		panic(err)
-	}
-	teg = &Engine{
-		db:   db,
-		prng: prng.New(rand.NewPCG(0xC0FFEECAFE, 0xBEEFF00D)),
+	if err := e.restorePrngStreams(); err != nil {
+		e.logger.Error("new engine", "err", err)
+		return nil, err
 	}
-	// initialize game state flags
-	teg.globals.garrison_magic = 999
+	return e, nil
 }
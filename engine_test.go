@@ -0,0 +1,153 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package taygete
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/mdhender/prng"
+)
+
+// engineGame is one independent game for the concurrency test.
+type engineGame struct {
+	e   *Engine
+	pl  int
+	who int
+}
+
+// newEngineGame builds a small world on its own engine: a province with
+// one start city and a faction with a single noble.
+func newEngineGame(t *testing.T, seed uint64, faction, noble string) *engineGame {
+	t.Helper()
+	db, err := OpenTestDB()
+	if err != nil {
+		t.Fatalf("OpenTestDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	e, err := NewEngine(db, prng.New(rand.NewPCG(seed, seed)))
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	e.logger = nil
+	e.checkSaves = true
+	e.globals.sysclock.turn = 16
+
+	for _, item := range []int{item_peasant, item_gold, item_lumber, item_stone, item_riding_horse} {
+		e.alloc_box(item, T_item, 0)
+	}
+	prov, city := 10_101, 56_760
+	e.alloc_box(prov, T_loc, sub_plain)
+	e.alloc_box(city, T_loc, sub_city)
+	e.set_name(city, "Drassa")
+	e.set_where(city, prov)
+	if err := e.SetStartCity(city, true); err != nil {
+		t.Fatalf("SetStartCity: %v", err)
+	}
+
+	pl, _, err := e.AddPlayer(NewPlayer{Faction: faction, Character: noble, StartCity: "drassa"})
+	if err != nil {
+		t.Fatalf("AddPlayer: %v", err)
+	}
+	who := e.loop_units(pl)[0]
+	return &engineGame{e: e, pl: pl, who: who}
+}
+
+// run plays turns on the game and returns a summary of the noble and
+// its faction after each one.
+func (g *engineGame) run(turns int) ([]string, error) {
+	var states []string
+	for i := 0; i < turns; i++ {
+		if err := g.e.RunTurn(); err != nil {
+			return nil, err
+		}
+		states = append(states, g.state())
+	}
+	return states, nil
+}
+
+// state summarizes the game after a turn, ending with a draw from the
+// engine's random number generator.
+func (g *engineGame) state() string {
+	e := g.e
+	s := fmt.Sprintf("turn %d: %s in %d, gold %d/%d, health %d",
+		e.globals.sysclock.turn, e.just_name(g.who), e.subloc(g.who),
+		e.has_item(g.who, item_gold), e.has_item(g.pl, item_gold), e.char_health(g.who))
+	for _, sk := range e.getCharSkills(g.who) {
+		s += fmt.Sprintf(", skill %d %d/%d", sk.skill, sk.days_studied, sk.know)
+	}
+	return s + fmt.Sprintf(", rnd %d", e.rnd(1, 1_000_000))
+}
+
+func TestEnginesRunConcurrently(t *testing.T) {
+	const turns = 3
+	type setup struct {
+		seed           uint64
+		faction, noble string
+	}
+	setups := []setup{
+		{seed: 1, faction: "Red Company", noble: "Osswid"},
+		{seed: 2, faction: "Blue Guild", noble: "Marta"},
+	}
+
+	// Play each game alone first to get the expected reports.
+	var want [][]string
+	for _, s := range setups {
+		reports, err := newEngineGame(t, s.seed, s.faction, s.noble).run(turns)
+		if err != nil {
+			t.Fatalf("%s: RunTurn: %v", s.faction, err)
+		}
+		want = append(want, reports)
+	}
+
+	// Now play fresh copies of both games at the same time.
+	games := make([]*engineGame, len(setups))
+	for i, s := range setups {
+		games[i] = newEngineGame(t, s.seed, s.faction, s.noble)
+	}
+	got := make([][]string, len(games))
+	errs := make([]error, len(games))
+	var wg sync.WaitGroup
+	for i, g := range games {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i], errs[i] = g.run(turns)
+		}()
+	}
+	wg.Wait()
+
+	for i, s := range setups {
+		if errs[i] != nil {
+			t.Fatalf("%s: RunTurn: %v", s.faction, errs[i])
+		}
+		if games[i].e.globals.sysclock.turn != 16+turns {
+			t.Errorf("%s: turn = %d, want %d", s.faction, games[i].e.globals.sysclock.turn, 16+turns)
+		}
+		for turn := range want[i] {
+			if got[i][turn] != want[i][turn] {
+				t.Errorf("%s: turn %d differs when run concurrently:\n got %s\nwant %s",
+					s.faction, turn+1, got[i][turn], want[i][turn])
+			}
+		}
+		if games[i].e.just_name(games[i].who) != s.noble {
+			t.Errorf("%s: noble = %q, want %q", s.faction, games[i].e.just_name(games[i].who), s.noble)
+		}
+	}
+}
@@ -150,10 +150,10 @@ func gold_s(n int) string {
 
 // loyal_s returns a string describing the loyalty type and level.
 // Port of C loyal_s() from u.c.
-func loyal_s(who int) string {
+func (e *Engine) loyal_s(who int) string {
 	var s string
 
-	switch loyal_kind(who) {
+	switch e.loyal_kind(who) {
 	case 0:
 		s = "unsworn"
 	case LOY_contract:
@@ -170,7 +170,7 @@ func loyal_s(who int) string {
 		s = "unknown"
 	}
 
-	return fmt.Sprintf("%s-%d", s, loyal_rate(who))
+	return fmt.Sprintf("%s-%d", s, e.loyal_rate(who))
 }
 
 // cap capitalizes the first character of a string.
@@ -205,19 +205,19 @@ func TestLoyalS(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			// Set up character with loyalty
-			e.globals.bx[charID] = &box{kind: T_char}
-			e.globals.bx[charID].x_char = &entity_char{
+			e.setBox(charID, &box{kind: T_char})
+			e.globals.bx.get(charID).x_char = &entity_char{
 				loy_kind: tc.loyKind,
 				loy_rate: tc.loyRate,
 			}
 
-			got := loyal_s(charID)
+			got := teg.loyal_s(charID)
 			if got != tc.expected {
 				t.Errorf("loyal_s() = %q, want %q", got, tc.expected)
 			}
 
 			// Clean up
-			e.globals.bx[charID] = nil
+			e.deleteBox(charID)
 		})
 	}
 }
@@ -20,18 +20,18 @@ package taygete
 
 // gates_here returns all gates in the here_list at the given location.
 // Ported from loop_gates_here macro in src/loop.h lines 256-267.
-func gates_here(where int) []int {
-	if !valid_box(where) {
+func (e *Engine) gates_here(where int) []int {
+	if !e.valid_box(where) {
 		return nil
 	}
-	p := rp_loc_info(where)
+	p := e.rp_loc_info(where)
 	if p == nil {
 		return nil
 	}
 
 	var gates []int
 	for _, id := range p.here_list {
-		if kind(id) == T_gate {
+		if e.kind(id) == T_gate {
 			gates = append(gates, id)
 		}
 	}
@@ -41,14 +41,14 @@ func gates_here(where int) []int {
 // province_gate_here finds a gate in the here_list of a location.
 // Returns 0 if no gate found.
 // Ported from src/gate.c lines 51-68.
-func province_gate_here(where int) int {
-	p := rp_loc_info(where)
+func (e *Engine) province_gate_here(where int) int {
+	p := e.rp_loc_info(where)
 	if p == nil {
 		return 0
 	}
 
 	for _, id := range p.here_list {
-		if kind(id) == T_gate {
+		if e.kind(id) == T_gate {
 			return id
 		}
 	}
@@ -56,19 +56,19 @@ func province_gate_here(where int) int {
 }
 
 // is_gate returns true if the entity is a gate.
-func is_gate(n int) bool {
-	return kind(n) == T_gate
+func (e *Engine) is_gate(n int) bool {
+	return e.kind(n) == T_gate
 }
 
 // is_road returns true if the entity is a road.
-func is_road(n int) bool {
-	return kind(n) == T_road
+func (e *Engine) is_road(n int) bool {
+	return e.kind(n) == T_road
 }
 
 // gate_notify_jumps returns the character to notify when someone jumps through the gate.
 // Ported from src/oly.h entity_gate structure.
-func gate_notify_jumps(n int) int {
-	g := rp_gate(n)
+func (e *Engine) gate_notify_jumps(n int) int {
+	g := e.rp_gate(n)
 	if g == nil {
 		return 0
 	}
@@ -77,8 +77,8 @@ func gate_notify_jumps(n int) int {
 
 // gate_notify_unseal returns the character to notify when someone unseals the gate.
 // Ported from src/oly.h entity_gate structure.
-func gate_notify_unseal(n int) int {
-	g := rp_gate(n)
+func (e *Engine) gate_notify_unseal(n int) int {
+	g := e.rp_gate(n)
 	if g == nil {
 		return 0
 	}
@@ -88,11 +88,549 @@ func gate_notify_unseal(n int) int {
 // check_gate_here validates that a gate exists and is at the same sublocation as who.
 // Returns true if the gate is valid and at the same location.
 // Ported from src/gate.c lines 98-109.
-func check_gate_here(who, gate int) bool {
-	if kind(gate) != T_gate || subloc(gate) != subloc(who) {
+func (e *Engine) check_gate_here(who, gate int) bool {
+	if e.kind(gate) != T_gate || e.subloc(gate) != e.subloc(who) {
+		wout(who, "There is no gate %s here.", e.box_code(gate))
 		return false
 	}
 	return true
 }
 
+// list_gates_here lists the gates at where, marking them known to
+// who. Returns false if there are none.
+// Ported from src/gate.c lines 8-48.
+func (e *Engine) list_gates_here(who, where int, show_dest bool) bool {
+	first := true
 
+	for _, gate := range e.gates_here(where) {
+		if first {
+			out(who, "Gates here:")
+			e.globals.indent += 3
+			first = false
+		}
+
+		sealed := ""
+		if e.gate_seal(gate) != 0 {
+			sealed = ", sealed"
+		}
+
+		dest := ""
+		if show_dest {
+			dest = sout(", to %s", e.box_name(e.gate_dest(gate)))
+		}
+
+		out(who, "%s%s%s", e.box_name(gate), sealed, dest)
+		e.set_known(who, gate)
+	}
+
+	if first {
+		out(who, "There are no gates here.")
+		return false
+	}
+
+	e.globals.indent -= 3
+	return true
+}
+
+// list_province_gates tells who whether a gate lies somewhere in
+// the province.
+// Ported from src/gate.c lines 71-82.
+func (e *Engine) list_province_gates(who, where int) int {
+	gate := e.province_gate_here(where)
+
+	if gate != 0 {
+		wout(who, "A gate exists somewhere in this province.")
+	}
+
+	return gate
+}
+
+// list_nearby_gates tells who how far away the nearest gate is.
+// Ported from src/gate.c lines 85-95.
+func (e *Engine) list_nearby_gates(who, where int) {
+	dist := int(e.gate_dist(where))
+
+	if dist == 0 {
+		wout(who, "There are no nearby gates.")
+	} else {
+		wout(who, "The nearest gate is %s province%s away.", nice_num(dist), add_s(dist))
+	}
+}
+
+// v_detect_gates starts casting Detect gates.
+// Ported from src/gate.c lines 112-120.
+func (e *Engine) v_detect_gates(c *command) int {
+	if !e.check_aura(c.who, 1) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_detect_gates lists the gates here, or failing that says whether
+// the province has one, or failing that how far the nearest one is.
+// Ported from src/gate.c lines 123-140.
+func (e *Engine) d_detect_gates(c *command) int {
+	if !e.charge_aura(c.who, 1) {
+		return FALSE
+	}
+
+	if !e.list_gates_here(c.who, e.subloc(c.who), true) &&
+		e.list_province_gates(c.who, e.province(c.who)) == 0 {
+		e.list_nearby_gates(c.who, e.province(c.who))
+	}
+
+	return TRUE
+}
+
+// do_jump moves who's stack to dest, telling whoever asked to be
+// notified of jumps through gate.
+// Ported from src/gate.c lines 143-170.
+func (e *Engine) do_jump(who, dest, gate int, backwards bool) {
+	e.leave_stack(who)
+	wout(who, "Successful jump to %s.", e.box_name(dest))
+	e.move_stack(who, dest)
+
+	e.clear_guard_flag(who)
+
+	if gate != 0 {
+		if e.kind(gate) != T_gate {
+			panic("do_jump: not a gate")
+		}
+
+		if p := e.rp_gate(gate); p != nil && e.kind(p.notify_jumps) == T_char {
+			how := ""
+			if backwards {
+				how = " backwards"
+			}
+			wout(p.notify_jumps, "%s has jumped%s through %s.", e.box_name(who), how, e.box_name(gate))
+		}
+	}
+}
+
+// jump_cost returns the aura needed to move who's stack: one for
+// each per units of weight, rounded up.
+func (e *Engine) jump_cost(who, per int) int {
+	var w weights
+	e.determine_stack_weights(who, &w)
+	return (w.total_weight + per - 1) / per
+}
+
+// v_jump_gate jumps the caster's stack through a gate here. A wrong
+// key uses up the order without jumping.
+// Ported from src/gate.c lines 173-215.
+func (e *Engine) v_jump_gate(c *command) int {
+	gate := c.a
+	key := c.b
+
+	if !e.check_gate_here(c.who, gate) {
+		return FALSE
+	}
+
+	e.set_known(c.who, gate)
+
+	sealed := int(e.gate_seal(gate))
+	dest := e.gate_dest(gate)
+
+	if sealed > 0 && key == 0 {
+		wout(c.who, "The gate is sealed.")
+		return FALSE
+	}
+
+	if sealed > 0 && key != sealed {
+		wout(c.who, "Incorrect gate key.  Jump fails.")
+		return TRUE
+	}
+
+	if !e.charge_aura(c.who, e.jump_cost(c.who, 250)) {
+		return FALSE
+	}
+
+	if !e.is_loc_or_ship(dest) {
+		panic("v_jump_gate: gate leads nowhere")
+	}
+
+	e.do_jump(c.who, dest, gate, false)
+
+	return TRUE
+}
+
+// v_reverse_jump jumps backwards through a gate leading here, at
+// twice the aura of a forward jump.
+// Ported from src/gate.c lines 218-261.
+func (e *Engine) v_reverse_jump(c *command) int {
+	gate := c.a
+	key := c.b
+
+	if e.kind(gate) != T_gate || e.gate_dest(gate) != e.subloc(c.who) {
+		wout(c.who, "No gate %s leads here.", e.box_code(gate))
+		return FALSE
+	}
+
+	sealed := int(e.gate_seal(gate))
+	dest := e.subloc(gate)
+
+	if sealed > 0 && key == 0 {
+		wout(c.who, "The gate is sealed.")
+		return FALSE
+	}
+
+	if sealed > 0 && key != sealed {
+		wout(c.who, "Incorrect gate key.  Jump fails.")
+		return TRUE
+	}
+
+	if !e.charge_aura(c.who, e.jump_cost(c.who, 250)*2) {
+		return FALSE
+	}
+
+	if !e.is_loc_or_ship(dest) {
+		panic("v_reverse_jump: gate is nowhere")
+	}
+
+	e.do_jump(c.who, dest, gate, true)
+
+	return TRUE
+}
+
+// v_reveal_key starts learning the key of a sealed gate.
+// Ported from src/gate.c lines 264-286.
+func (e *Engine) v_reveal_key(c *command) int {
+	gate := c.a
+
+	if !e.check_gate_here(c.who, gate) {
+		return FALSE
+	}
+
+	if e.gate_seal(gate) == 0 {
+		wout(c.who, "%s is not sealed.", e.box_name(gate))
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 10) {
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to learn the key for %s.", e.box_name(gate))
+	return TRUE
+}
+
+// d_reveal_key tells the caster the key of a sealed gate.
+// Ported from src/gate.c lines 289-311.
+func (e *Engine) d_reveal_key(c *command) int {
+	gate := c.a
+
+	if !e.check_gate_here(c.who, gate) {
+		return FALSE
+	}
+
+	sealed := e.gate_seal(gate)
+	if sealed == 0 {
+		wout(c.who, "%s is not sealed.", e.box_name(gate))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, 10) {
+		return FALSE
+	}
+
+	wout(c.who, "The key to %s is: %d", e.box_name(gate), sealed)
+	return TRUE
+}
+
+// v_seal_gate starts sealing a gate with a key from 1 to 999.
+// Ported from src/gate.c lines 314-348.
+func (e *Engine) v_seal_gate(c *command) int {
+	gate := c.a
+	key := c.b
+
+	if !e.check_gate_here(c.who, gate) {
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 6) {
+		return FALSE
+	}
+
+	if key == 0 {
+		wout(c.who, "Must specify a key to seal the gate with.")
+		return FALSE
+	}
+
+	if key > 999 {
+		wout(c.who, "The key must be between 1 and 999.")
+		return FALSE
+	}
+
+	if e.gate_seal(gate) != 0 {
+		wout(c.who, "%s is already sealed.", e.box_name(gate))
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_seal_gate seals the gate, unless someone else sealed it first.
+// Ported from src/gate.c lines 351-377.
+func (e *Engine) d_seal_gate(c *command) int {
+	gate := c.a
+	key := c.b
+
+	if !e.check_gate_here(c.who, gate) {
+		return FALSE
+	}
+
+	if e.gate_seal(gate) != 0 {
+		wout(c.who, "%s has been sealed by someone else.", e.box_name(gate))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, 6) {
+		return FALSE
+	}
+
+	e.p_gate(gate).seal_key = short(key)
+
+	wout(c.who, "Sealed %s with key %d.", e.box_name(gate), key)
+	return TRUE
+}
+
+// unseal_gate removes the seal from gate, telling whoever asked to
+// be notified.
+// Ported from src/gate.c lines 380-395.
+func (e *Engine) unseal_gate(who, gate int) {
+	if e.kind(gate) != T_gate {
+		panic("unseal_gate: not a gate")
+	}
+
+	p := e.p_gate(gate)
+	p.seal_key = 0
+
+	if e.kind(p.notify_unseal) == T_char {
+		wout(p.notify_unseal, "%s has been unsealed by %s.", e.box_name(gate), e.box_name(who))
+	}
+
+	p.notify_unseal = 0
+}
+
+// v_unseal_gate starts unsealing a gate with its key.
+// Ported from src/gate.c lines 398-428.
+func (e *Engine) v_unseal_gate(c *command) int {
+	gate := c.a
+	key := c.b
+
+	if !e.check_gate_here(c.who, gate) {
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 3) {
+		return FALSE
+	}
+
+	sealed := int(e.gate_seal(gate))
+	if sealed == 0 {
+		wout(c.who, "The gate is not sealed.")
+		return FALSE
+	}
+
+	if key == 0 {
+		wout(c.who, "Must specify a key to unseal the gate.")
+		return FALSE
+	}
+
+	c.d = sealed // pass gate key to d_unseal_gate
+
+	return TRUE
+}
+
+// d_unseal_gate unseals the gate if the key was right.
+// Ported from src/gate.c lines 431-452.
+func (e *Engine) d_unseal_gate(c *command) int {
+	gate := c.a
+	key := c.b
+	sealed := c.d
+
+	if key != sealed {
+		wout(c.who, "Incorrect gate key.  Unseal fails.")
+		return TRUE
+	}
+
+	if !e.charge_aura(c.who, 3) {
+		return FALSE
+	}
+
+	e.unseal_gate(c.who, gate)
+
+	wout(c.who, "Successfully unsealed %s.", e.box_name(gate))
+
+	return TRUE
+}
+
+// v_notify_unseal starts casting a watch for the unsealing of a
+// sealed gate. The caster must know the key.
+// Ported from src/gate.c lines 455-485.
+func (e *Engine) v_notify_unseal(c *command) int {
+	gate := c.a
+	key := c.b
+
+	if !e.check_gate_here(c.who, gate) {
+		return FALSE
+	}
+
+	sealed := int(e.gate_seal(gate))
+	if sealed == 0 {
+		wout(c.who, "The gate is not sealed.")
+		return FALSE
+	}
+
+	if key == 0 {
+		wout(c.who, "Must specify the gate seal.")
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 5) {
+		return FALSE
+	}
+
+	c.d = sealed
+
+	return TRUE
+}
+
+// d_notify_unseal asks to be told when the gate is unsealed.
+// Ported from src/gate.c lines 488-509.
+func (e *Engine) d_notify_unseal(c *command) int {
+	gate := c.a
+	key := c.b
+	sealed := c.d
+
+	if key != sealed {
+		wout(c.who, "Incorrect gate key.  Spell fails.")
+		return TRUE
+	}
+
+	if e.kind(gate) != T_gate {
+		panic("d_notify_unseal: not a gate")
+	}
+	e.p_gate(gate).notify_unseal = c.who
+
+	if !e.charge_aura(c.who, 5) {
+		return FALSE
+	}
+
+	wout(c.who, "Notification spell successfully cast.")
+	return TRUE
+}
+
+// v_rem_seal starts breaking the seal on a gate without its key.
+// Ported from src/gate.c lines 512-533.
+func (e *Engine) v_rem_seal(c *command) int {
+	gate := c.a
+
+	if !e.check_gate_here(c.who, gate) {
+		return FALSE
+	}
+
+	if e.gate_seal(gate) == 0 {
+		wout(c.who, "The gate is not sealed.")
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 8) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_rem_seal breaks the seal on the gate.
+// Ported from src/gate.c lines 536-552.
+func (e *Engine) d_rem_seal(c *command) int {
+	gate := c.a
+
+	if !e.check_gate_here(c.who, gate) {
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, 8) {
+		return FALSE
+	}
+
+	e.unseal_gate(c.who, gate)
+
+	wout(c.who, "Unsealed %s.", e.box_name(gate))
+
+	return TRUE
+}
+
+// v_notify_jump starts casting a watch for jumps through a gate.
+// Ported from src/gate.c lines 555-567.
+func (e *Engine) v_notify_jump(c *command) int {
+	gate := c.a
+
+	if !e.check_gate_here(c.who, gate) {
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 6) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_notify_jump asks to be told of jumps through the gate.
+// Ported from src/gate.c lines 570-586.
+func (e *Engine) d_notify_jump(c *command) int {
+	gate := c.a
+
+	if !e.check_gate_here(c.who, gate) {
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, 6) {
+		return FALSE
+	}
+
+	if e.kind(gate) != T_gate {
+		panic("d_notify_jump: not a gate")
+	}
+	e.p_gate(gate).notify_jumps = c.who
+
+	wout(c.who, "Notification spell successfully cast.")
+	return TRUE
+}
+
+// v_teleport moves the caster's stack to any location in the same
+// region, for a gate crystal and one aura per 50 weight.
+// Ported from src/gate.c lines 589-626.
+func (e *Engine) v_teleport(c *command) int {
+	dest := c.a
+
+	if !e.is_loc_or_ship(dest) {
+		wout(c.who, "There is no location %s.", get_parse_arg(c, 1))
+		return FALSE
+	}
+
+	if e.diff_region(c.who, dest) {
+		wout(c.who, "It is not possible to teleport there from here.")
+		return FALSE
+	}
+
+	cost := e.jump_cost(c.who, 50)
+
+	if e.has_item(c.who, item_gate_crystal) < 1 {
+		wout(c.who, "Teleportation requires %s.", e.box_name_qty(item_gate_crystal, 1))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, cost) {
+		return FALSE
+	}
+
+	e.consume_item(c.who, item_gate_crystal, 1)
+
+	e.do_jump(c.who, dest, 0, false)
+
+	return TRUE
+}
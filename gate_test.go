@@ -23,18 +23,18 @@ func TestGatesHere(t *testing.T) {
 	clearBx()
 
 	// Create a province location
-	teg.globals.bx[100] = &box{
+	teg.setBox(100, &box{
 		kind:       T_loc,
 		skind:      sub_plain,
 		x_loc_info: loc_info{here_list: []int{200, 300, 400}},
-	}
+	})
 
 	// Create entities in the province
-	teg.globals.bx[200] = &box{kind: T_gate} // gate
-	teg.globals.bx[300] = &box{kind: T_char} // character
-	teg.globals.bx[400] = &box{kind: T_gate} // another gate
+	teg.setBox(200, &box{kind: T_gate}) // gate
+	teg.setBox(300, &box{kind: T_char}) // character
+	teg.setBox(400, &box{kind: T_gate}) // another gate
 
-	gates := gates_here(100)
+	gates := teg.gates_here(100)
 
 	if len(gates) != 2 {
 		t.Errorf("gates_here(100) returned %d gates, want 2", len(gates))
@@ -53,16 +53,16 @@ func TestGatesHereEmpty(t *testing.T) {
 	clearBx()
 
 	// Create a province with no gates
-	teg.globals.bx[100] = &box{
+	teg.setBox(100, &box{
 		kind:       T_loc,
 		skind:      sub_plain,
 		x_loc_info: loc_info{here_list: []int{200, 300}},
-	}
+	})
 
-	teg.globals.bx[200] = &box{kind: T_char}
-	teg.globals.bx[300] = &box{kind: T_loc}
+	teg.setBox(200, &box{kind: T_char})
+	teg.setBox(300, &box{kind: T_loc})
 
-	gates := gates_here(100)
+	gates := teg.gates_here(100)
 
 	if len(gates) != 0 {
 		t.Errorf("gates_here(100) returned %d gates, want 0", len(gates))
@@ -73,7 +73,7 @@ func TestGatesHereInvalidBox(t *testing.T) {
 	defer clearBx()
 	clearBx()
 
-	gates := gates_here(999)
+	gates := teg.gates_here(999)
 
 	if gates != nil {
 		t.Error("gates_here(999) should return nil for invalid box")
@@ -85,17 +85,17 @@ func TestProvinceGateHere(t *testing.T) {
 	clearBx()
 
 	// Create a province with a gate
-	teg.globals.bx[100] = &box{
+	teg.setBox(100, &box{
 		kind:       T_loc,
 		skind:      sub_plain,
 		x_loc_info: loc_info{here_list: []int{200, 300, 400}},
-	}
+	})
 
-	teg.globals.bx[200] = &box{kind: T_char}
-	teg.globals.bx[300] = &box{kind: T_gate}
-	teg.globals.bx[400] = &box{kind: T_gate}
+	teg.setBox(200, &box{kind: T_char})
+	teg.setBox(300, &box{kind: T_gate})
+	teg.setBox(400, &box{kind: T_gate})
 
-	gate := province_gate_here(100)
+	gate := teg.province_gate_here(100)
 
 	if gate != 300 {
 		t.Errorf("province_gate_here(100) = %d, want 300 (first gate)", gate)
@@ -106,15 +106,15 @@ func TestProvinceGateHereNoGate(t *testing.T) {
 	defer clearBx()
 	clearBx()
 
-	teg.globals.bx[100] = &box{
+	teg.setBox(100, &box{
 		kind:       T_loc,
 		skind:      sub_plain,
 		x_loc_info: loc_info{here_list: []int{200}},
-	}
+	})
 
-	teg.globals.bx[200] = &box{kind: T_char}
+	teg.setBox(200, &box{kind: T_char})
 
-	gate := province_gate_here(100)
+	gate := teg.province_gate_here(100)
 
 	if gate != 0 {
 		t.Errorf("province_gate_here(100) = %d, want 0 (no gate)", gate)
@@ -125,17 +125,17 @@ func TestIsGate(t *testing.T) {
 	defer clearBx()
 	clearBx()
 
-	teg.globals.bx[100] = &box{kind: T_gate}
-	teg.globals.bx[200] = &box{kind: T_road}
-	teg.globals.bx[300] = &box{kind: T_loc}
+	teg.setBox(100, &box{kind: T_gate})
+	teg.setBox(200, &box{kind: T_road})
+	teg.setBox(300, &box{kind: T_loc})
 
-	if !is_gate(100) {
+	if !teg.is_gate(100) {
 		t.Error("is_gate(100) should be true")
 	}
-	if is_gate(200) {
+	if teg.is_gate(200) {
 		t.Error("is_gate(200) should be false for road")
 	}
-	if is_gate(300) {
+	if teg.is_gate(300) {
 		t.Error("is_gate(300) should be false for loc")
 	}
 }
@@ -144,17 +144,17 @@ func TestIsRoad(t *testing.T) {
 	defer clearBx()
 	clearBx()
 
-	teg.globals.bx[100] = &box{kind: T_gate}
-	teg.globals.bx[200] = &box{kind: T_road}
-	teg.globals.bx[300] = &box{kind: T_loc}
+	teg.setBox(100, &box{kind: T_gate})
+	teg.setBox(200, &box{kind: T_road})
+	teg.setBox(300, &box{kind: T_loc})
 
-	if is_road(100) {
+	if teg.is_road(100) {
 		t.Error("is_road(100) should be false for gate")
 	}
-	if !is_road(200) {
+	if !teg.is_road(200) {
 		t.Error("is_road(200) should be true")
 	}
-	if is_road(300) {
+	if teg.is_road(300) {
 		t.Error("is_road(300) should be false for loc")
 	}
 }
@@ -163,14 +163,14 @@ func TestRoadDest(t *testing.T) {
 	defer clearBx()
 	clearBx()
 
-	teg.globals.bx[100] = &box{
+	teg.setBox(100, &box{
 		kind: T_road,
 		x_gate: &entity_gate{
 			to_loc: 500,
 		},
-	}
+	})
 
-	dest := road_dest(100)
+	dest := teg.road_dest(100)
 	if dest != 500 {
 		t.Errorf("road_dest(100) = %d, want 500", dest)
 	}
@@ -180,9 +180,9 @@ func TestRoadDestNil(t *testing.T) {
 	defer clearBx()
 	clearBx()
 
-	teg.globals.bx[100] = &box{kind: T_road}
+	teg.setBox(100, &box{kind: T_road})
 
-	dest := road_dest(100)
+	dest := teg.road_dest(100)
 	if dest != 0 {
 		t.Errorf("road_dest(100) = %d, want 0 for nil gate struct", dest)
 	}
@@ -192,14 +192,14 @@ func TestRoadHidden(t *testing.T) {
 	defer clearBx()
 	clearBx()
 
-	teg.globals.bx[100] = &box{
+	teg.setBox(100, &box{
 		kind: T_road,
 		x_gate: &entity_gate{
 			road_hidden: 1,
 		},
-	}
+	})
 
-	hidden := road_hidden(100)
+	hidden := teg.road_hidden(100)
 	if hidden != 1 {
 		t.Errorf("road_hidden(100) = %d, want 1", hidden)
 	}
@@ -209,14 +209,14 @@ func TestRoadHiddenFalse(t *testing.T) {
 	defer clearBx()
 	clearBx()
 
-	teg.globals.bx[100] = &box{
+	teg.setBox(100, &box{
 		kind: T_road,
 		x_gate: &entity_gate{
 			road_hidden: 0,
 		},
-	}
+	})
 
-	hidden := road_hidden(100)
+	hidden := teg.road_hidden(100)
 	if hidden != 0 {
 		t.Errorf("road_hidden(100) = %d, want 0", hidden)
 	}
@@ -226,14 +226,14 @@ func TestGateNotifyJumps(t *testing.T) {
 	defer clearBx()
 	clearBx()
 
-	teg.globals.bx[100] = &box{
+	teg.setBox(100, &box{
 		kind: T_gate,
 		x_gate: &entity_gate{
 			notify_jumps: 999,
 		},
-	}
+	})
 
-	notify := gate_notify_jumps(100)
+	notify := teg.gate_notify_jumps(100)
 	if notify != 999 {
 		t.Errorf("gate_notify_jumps(100) = %d, want 999", notify)
 	}
@@ -243,14 +243,14 @@ func TestGateNotifyUnseal(t *testing.T) {
 	defer clearBx()
 	clearBx()
 
-	teg.globals.bx[100] = &box{
+	teg.setBox(100, &box{
 		kind: T_gate,
 		x_gate: &entity_gate{
 			notify_unseal: 888,
 		},
-	}
+	})
 
-	notify := gate_notify_unseal(100)
+	notify := teg.gate_notify_unseal(100)
 	if notify != 888 {
 		t.Errorf("gate_notify_unseal(100) = %d, want 888", notify)
 	}
@@ -261,45 +261,45 @@ func TestCheckGateHere(t *testing.T) {
 	clearBx()
 
 	// Create a province
-	teg.globals.bx[100] = &box{
+	teg.setBox(100, &box{
 		kind:       T_loc,
 		skind:      sub_plain,
 		x_loc_info: loc_info{here_list: []int{200, 300}},
-	}
+	})
 
 	// Character at province
-	teg.globals.bx[200] = &box{
+	teg.setBox(200, &box{
 		kind:       T_char,
 		x_loc_info: loc_info{where: 100},
-	}
+	})
 
 	// Gate at same province
-	teg.globals.bx[300] = &box{
+	teg.setBox(300, &box{
 		kind:       T_gate,
 		x_loc_info: loc_info{where: 100},
-	}
+	})
 
 	// Gate at different location
-	teg.globals.bx[400] = &box{
+	teg.setBox(400, &box{
 		kind:       T_gate,
 		x_loc_info: loc_info{where: 500},
-	}
+	})
 
-	teg.globals.bx[500] = &box{
+	teg.setBox(500, &box{
 		kind:  T_loc,
 		skind: sub_forest,
-	}
+	})
 
-	if !check_gate_here(200, 300) {
+	if !teg.check_gate_here(200, 300) {
 		t.Error("check_gate_here(200, 300) should be true (same subloc)")
 	}
 
-	if check_gate_here(200, 400) {
+	if teg.check_gate_here(200, 400) {
 		t.Error("check_gate_here(200, 400) should be false (different subloc)")
 	}
 
 	// Non-gate should fail
-	if check_gate_here(200, 200) {
+	if teg.check_gate_here(200, 200) {
 		t.Error("check_gate_here(200, 200) should be false (not a gate)")
 	}
 }
@@ -314,57 +314,154 @@ func TestDiffRegion(t *testing.T) {
 	defer func() { teg.globals.faeryRegion = oldFaery }()
 
 	// Create normal regions (1 and 2) and Faery region (3)
-	teg.globals.bx[1] = &box{
+	teg.setBox(1, &box{
 		kind:  T_loc,
 		skind: sub_region,
-	}
-	teg.globals.bx[2] = &box{
+	})
+	teg.setBox(2, &box{
 		kind:  T_loc,
 		skind: sub_region,
-	}
-	teg.globals.bx[3] = &box{
+	})
+	teg.setBox(3, &box{
 		kind:  T_loc,
 		skind: sub_region,
-	}
+	})
 
 	// Provinces in different normal regions
-	teg.globals.bx[100] = &box{
+	teg.setBox(100, &box{
 		kind:       T_loc,
 		skind:      sub_plain,
 		x_loc_info: loc_info{where: 1},
-	}
-	teg.globals.bx[200] = &box{
+	})
+	teg.setBox(200, &box{
 		kind:       T_loc,
 		skind:      sub_plain,
 		x_loc_info: loc_info{where: 2},
-	}
+	})
 
 	// Same region as 100
-	teg.globals.bx[300] = &box{
+	teg.setBox(300, &box{
 		kind:       T_loc,
 		skind:      sub_plain,
 		x_loc_info: loc_info{where: 1},
-	}
+	})
 
 	// Province in Faery
-	teg.globals.bx[400] = &box{
+	teg.setBox(400, &box{
 		kind:       T_loc,
 		skind:      sub_plain,
 		x_loc_info: loc_info{where: 3},
-	}
+	})
 
 	// diff_region checks greater_region, which returns 0 for normal world regions.
 	// Two normal regions both return greater_region=0, so diff_region is false.
-	if diff_region(100, 200) {
+	if teg.diff_region(100, 200) {
 		t.Error("diff_region(100, 200) should be false (both in normal world)")
 	}
 
-	if diff_region(100, 300) {
+	if teg.diff_region(100, 300) {
 		t.Error("diff_region(100, 300) should be false (same region)")
 	}
 
 	// Normal vs Faery should be different
-	if !diff_region(100, 400) {
+	if !teg.diff_region(100, 400) {
 		t.Error("diff_region(100, 400) should be true (normal vs faery)")
 	}
 }
+
+// setupGateTest builds a mage at from and a gate there leading to to.
+func setupGateTest(t *testing.T) (who, gate, from, to int) {
+	t.Helper()
+	e := newTestEngine(t)
+
+	from, to = 10_101, 10_102
+	e.alloc_box(from, T_loc, sub_plain)
+	e.alloc_box(to, T_loc, sub_forest)
+
+	gate = 20_001
+	e.alloc_box(gate, T_gate, 0)
+	e.set_where(gate, from)
+	e.p_gate(gate).to_loc = to
+
+	who = 1001
+	e.alloc_box(who, T_char, 0)
+	e.set_where(who, from)
+	e.p_magic(who).cur_aura = 50
+
+	e.alloc_box(item_peasant, T_item, 0)
+	e.p_item(item_peasant).weight = 100
+	e.gen_item(who, item_peasant, 5)
+	return who, gate, from, to
+}
+
+func TestSealedGateJump(t *testing.T) {
+	who, gate, from, to := setupGateTest(t)
+
+	c := &command{who: who, a: gate, b: 123}
+	if teg.v_seal_gate(c) != TRUE || teg.d_seal_gate(c) != TRUE {
+		t.Fatal("seal gate failed")
+	}
+	if got := teg.gate_seal(gate); got != 123 {
+		t.Fatalf("seal = %d, want 123", got)
+	}
+
+	// no key is refused; a wrong key uses up the order without jumping
+	c = &command{who: who, a: gate}
+	if got := teg.v_jump_gate(c); got != FALSE {
+		t.Errorf("v_jump_gate without key = %d, want FALSE", got)
+	}
+	c.b = 7
+	if got := teg.v_jump_gate(c); got != TRUE || teg.subloc(who) != from {
+		t.Errorf("v_jump_gate with wrong key = %d at %d, want TRUE at %d", got, teg.subloc(who), from)
+	}
+
+	aura := teg.char_cur_aura(who)
+	cost := teg.jump_cost(who, 250)
+	if cost < 1 {
+		t.Fatalf("jump cost = %d, want at least 1", cost)
+	}
+	c.b = 123
+	if got := teg.v_jump_gate(c); got != TRUE {
+		t.Fatalf("v_jump_gate = %d, want TRUE", got)
+	}
+	if teg.subloc(who) != to {
+		t.Errorf("jumped to %d, want %d", teg.subloc(who), to)
+	}
+	if got := teg.char_cur_aura(who); got != aura-cost {
+		t.Errorf("aura = %d, want %d", got, aura-cost)
+	}
+
+	// back the way we came, at double the cost
+	aura = teg.char_cur_aura(who)
+	c = &command{who: who, a: gate, b: 123}
+	if got := teg.v_reverse_jump(c); got != TRUE || teg.subloc(who) != from {
+		t.Errorf("v_reverse_jump = %d at %d, want TRUE at %d", got, teg.subloc(who), from)
+	}
+	if got := teg.char_cur_aura(who); got != aura-2*cost {
+		t.Errorf("aura = %d, want %d", got, aura-2*cost)
+	}
+}
+
+func TestUnsealGate(t *testing.T) {
+	who, gate, _, _ := setupGateTest(t)
+	watcher := 1002
+	teg.alloc_box(watcher, T_char, 0)
+	teg.p_gate(gate).seal_key = 55
+	teg.p_gate(gate).notify_unseal = watcher
+
+	c := &command{who: who, a: gate, b: 54}
+	if got := teg.v_unseal_gate(c); got != TRUE {
+		t.Fatalf("v_unseal_gate = %d, want TRUE", got)
+	}
+	if teg.d_unseal_gate(c); teg.gate_seal(gate) != 55 {
+		t.Error("wrong key unsealed the gate")
+	}
+
+	c = &command{who: who, a: gate}
+	if teg.v_rem_seal(c) != TRUE || teg.d_rem_seal(c) != TRUE {
+		t.Fatal("remove seal failed")
+	}
+	if teg.gate_seal(gate) != 0 || teg.gate_notify_unseal(gate) != 0 {
+		t.Errorf("seal = %d, notify = %d, want both cleared", teg.gate_seal(gate), teg.gate_notify_unseal(gate))
+	}
+}
@@ -305,12 +305,12 @@ var month_names = []string{ //  (treat as const)
 // glob_init initializes global game state.
 // In Go, the bx array is pre-allocated in Engine.globals.
 // This function resets the box_head and sub_head chains.
-func glob_init() {
+func (e *Engine) glob_init() {
 	for i := 0; i < T_MAX; i++ {
-		teg.globals.box_head[i] = 0
+		e.globals.box_head[i] = 0
 	}
 	for i := 0; i < SUB_MAX; i++ {
-		teg.globals.sub_head[i] = 0
+		e.globals.sub_head[i] = 0
 	}
 }
 
@@ -347,7 +347,7 @@ func (e *Engine) KindNext(id int) int {
 	if id <= 0 || id >= MAX_BOXES {
 		return 0
 	}
-	b := e.globals.bx[id]
+	b := e.globals.bx.get(id)
 	if b == nil {
 		return 0
 	}
@@ -367,7 +367,7 @@ func (e *Engine) SubNext(id int) int {
 	if id <= 0 || id >= MAX_BOXES {
 		return 0
 	}
-	b := e.globals.bx[id]
+	b := e.globals.bx.get(id)
 	if b == nil {
 		return 0
 	}
@@ -512,7 +512,16 @@ func (e *Engine) CollapsedMines() []int {
 	return result
 }
 
-// DeadBodies returns all dead body sublocation IDs.
+// NpcTokens returns all npc token item IDs.
+func (e *Engine) NpcTokens() []int {
+	var result []int
+	for id := e.SubFirst(sub_npc_token); id > 0; id = e.SubNext(id) {
+		result = append(result, id)
+	}
+	return result
+}
+
+// DeadBodies returns all dead body item IDs.
 func (e *Engine) DeadBodies() []int {
 	var result []int
 	for id := e.SubFirst(sub_dead_body); id > 0; id = e.SubNext(id) {
@@ -556,8 +565,8 @@ func (e *Engine) LocsAndShips() []int {
 // Boxes returns all valid (non-deleted) box IDs.
 func (e *Engine) Boxes() []int {
 	var result []int
-	for id := 1; id < MAX_BOXES; id++ {
-		if e.Kind(id) != T_deleted {
+	for _, id := range e.boxIDs() {
+		if e.globals.bx.get(id).kind != T_deleted {
 			result = append(result, id)
 		}
 	}
@@ -567,8 +576,8 @@ func (e *Engine) Boxes() []int {
 // Kind returns the kind of an entity.
 // Returns T_deleted if the entity doesn't exist.
 func (e *Engine) Kind(id int) schar {
-	if id > 0 && id < MAX_BOXES && e.globals.bx[id] != nil {
-		return e.globals.bx[id].kind
+	if id > 0 && id < MAX_BOXES && e.globals.bx.get(id) != nil {
+		return e.globals.bx.get(id).kind
 	}
 	return T_deleted
 }
@@ -576,8 +585,8 @@ func (e *Engine) Kind(id int) schar {
 // Subkind returns the subkind of an entity.
 // Returns 0 if the entity doesn't exist.
 func (e *Engine) Subkind(id int) schar {
-	if id > 0 && id < MAX_BOXES && e.globals.bx[id] != nil {
-		return e.globals.bx[id].skind
+	if id > 0 && id < MAX_BOXES && e.globals.bx.get(id) != nil {
+		return e.globals.bx.get(id).skind
 	}
 	return 0
 }
@@ -590,12 +599,181 @@ func (e *Engine) ValidBox(id int) bool {
 // LocDepth returns the depth of a location (region, province, subloc, build).
 // Returns 0 if the entity is not a location.
 func (e *Engine) LocDepth(id int) int {
-	return loc_depth(id)
+	return e.loc_depth(id)
 }
 
-// Package-level accessor functions for special regions
-// These provide access to the region IDs stored in engine globals.
-
-var cloud_region int  // Cloud realm region ID
-var hades_region int  // Hades realm region ID
-var faery_region int  // Faery realm region ID
+// cmd_tbl is the order dispatch table, ported from src/glob.c.
+//
+// Allow codes:
+//
+//	c	character
+//	p	player entity
+//	i	immediate mode only (debugging/maintenance)
+//	r	restricted -- for npc units under control
+//	g	garrison
+//	m	Gamemaster only
+//
+// Entries whose handlers have not been ported yet carry a nil start
+// routine; do_command reports them as unimplemented.
+//
+// The table is filled in by init() because several handlers reach
+// back into find_command, which would otherwise be an initialization cycle.
+var cmd_tbl []cmd_tbl_ent
+
+func init() {
+	cmd_tbl = []cmd_tbl_ent{
+		// allow, name, start, finish, intr, time, poll, pri
+
+		{"", "", nil, nil, nil, 0, 0, 3},
+		{"cpr", "accept", (*Engine).v_accept, nil, nil, 0, 0, 0},
+		{"cpr", "admit", (*Engine).v_admit, nil, nil, 0, 0, 0},
+		{"cr", "attack", nil, nil, nil, 1, 0, 3},
+		{"cr", "banner", (*Engine).v_banner, nil, nil, 0, 0, 1},
+		{"cr", "behind", nil, nil, nil, 0, 0, 1},
+		{"c", "bind", nil, nil, nil, 7, 0, 3},
+		{"c", "board", (*Engine).v_board, nil, nil, 0, 0, 2},
+		{"c", "breed", (*Engine).v_breed, (*Engine).d_breed, nil, 7, 0, 3},
+		{"c", "bribe", (*Engine).v_bribe, (*Engine).d_bribe, nil, 7, 0, 3},
+		{"c", "build", nil, nil, nil, -1, 1, 3},
+		{"c", "buy", nil, nil, nil, 0, 0, 1},
+		{"c", "catch", (*Engine).v_catch, nil, nil, -1, 1, 3},
+		{"c", "claim", (*Engine).v_claim, nil, nil, 0, 0, 1},
+		{"c", "collect", (*Engine).v_collect, (*Engine).d_collect, (*Engine).i_collect, -1, 1, 3},
+		{"cr", "contact", (*Engine).v_contact, nil, nil, 0, 0, 0},
+		{"m", "credit", (*Engine).v_credit, nil, nil, 0, 0, 0},
+		{"c", "decree", nil, nil, nil, 0, 0, 0},
+		{"cpr", "default", nil, nil, nil, 0, 0, 0},
+		{"cpr", "defend", (*Engine).v_defend, nil, nil, 0, 0, 0},
+		{"c", "die", (*Engine).v_die, nil, nil, 0, 0, 1},
+		{"c", "discard", (*Engine).v_discard, nil, nil, 0, 0, 1},
+		{"cr", "drop", (*Engine).v_discard, nil, nil, 0, 0, 1},
+		{"m", "emote", (*Engine).v_emote, nil, nil, 0, 0, 1},
+		{"cr", "execute", nil, nil, nil, 0, 0, 1},
+		{"c", "explore", (*Engine).v_explore, (*Engine).d_explore, nil, 7, 0, 3},
+		{"c", "fee", (*Engine).v_fee, nil, nil, 0, 0, 1},
+		{"c", "ferry", (*Engine).v_ferry, nil, nil, 0, 0, 1},
+		{"c", "fish", (*Engine).v_fish, nil, nil, -1, 1, 3},
+		{"cr", "flag", (*Engine).v_flag, nil, nil, 0, 0, 1},
+		{"c", "fly", (*Engine).v_fly, (*Engine).d_fly, nil, -1, 0, 2},
+		{"c", "forget", (*Engine).v_forget, nil, nil, 0, 0, 1},
+		{"c", "form", (*Engine).v_form, (*Engine).d_form, nil, 7, 0, 3},
+		{"cp", "format", (*Engine).v_format, nil, nil, 0, 0, 1},
+		{"c", "garrison", nil, nil, nil, 1, 0, 3},
+		{"cr", "get", (*Engine).v_get, nil, nil, 0, 0, 1},
+		{"cr", "give", (*Engine).v_give, nil, nil, 0, 0, 1},
+		{"cr", "go", (*Engine).v_move, (*Engine).d_move, nil, -1, 0, 2},
+		{"c", "guard", nil, nil, nil, 0, 0, 1},
+		{"c", "hide", (*Engine).v_hide, (*Engine).d_hide, nil, 3, 0, 3},
+		{"c", "honor", nil, nil, nil, 1, 0, 3},
+		{"c", "honour", nil, nil, nil, 1, 0, 3},
+		{"cpr", "hostile", (*Engine).v_hostile, nil, nil, 0, 0, 0},
+		{"c", "improve", nil, nil, nil, -1, 1, 3},
+		{"c", "incite", (*Engine).v_incite, nil, nil, 7, 0, 3},
+		{"c", "make", (*Engine).v_make, (*Engine).d_make, (*Engine).i_make, -1, 1, 3},
+		{"c", "mallorn", (*Engine).v_mallorn, nil, nil, -1, 1, 3},
+		{"cp", "message", (*Engine).v_message, nil, nil, 1, 0, 3},
+		{"cr", "move", (*Engine).v_move, (*Engine).d_move, nil, -1, 0, 2},
+		{"cpr", "name", (*Engine).v_name, nil, nil, 0, 0, 1},
+		{"cpr", "neutral", (*Engine).v_neutral, nil, nil, 0, 0, 0},
+		{"cp", "notab", (*Engine).v_notab, nil, nil, 0, 0, 1},
+		{"c", "oath", nil, nil, nil, 1, 0, 3},
+		{"c", "opium", (*Engine).v_opium, nil, nil, -1, 1, 3},
+		{"cr", "pay", (*Engine).v_pay, nil, nil, 0, 0, 1},
+		{"cr", "pillage", nil, nil, nil, 7, 0, 3},
+		{"c", "pledge", nil, nil, nil, 0, 0, 1},
+		{"cr", "plugh", (*Engine).v_plugh, nil, nil, 0, 0, 3},
+		{"c", "post", (*Engine).v_post, nil, nil, 1, 0, 3},
+		{"cp", "press", (*Engine).v_press, nil, nil, 0, 0, 1},
+		{"cr", "promote", (*Engine).v_promote, nil, nil, 0, 0, 1},
+		{"cp", "public", (*Engine).v_public, nil, nil, 0, 0, 1},
+		{"c", "quarry", (*Engine).v_quarry, nil, nil, -1, 1, 3},
+		{"c", "quest", (*Engine).v_quest, (*Engine).d_quest, nil, 7, 0, 3},
+		{"p", "quit", (*Engine).v_quit, nil, nil, 0, 0, 1},
+		{"c", "raise", (*Engine).v_raise, (*Engine).d_raise, nil, 7, 0, 3},
+		{"c", "rally", (*Engine).v_rally, (*Engine).d_rally, nil, 7, 0, 3},
+		{"cr", "raze", nil, nil, nil, -1, 1, 3},
+		{"cpr", "realname", (*Engine).v_fullname, nil, nil, 0, 0, 1},
+		{"c", "reclaim", nil, nil, nil, 0, 0, 1},
+		{"c", "recruit", (*Engine).v_recruit, nil, nil, -1, 1, 3},
+		{"c", "repair", nil, nil, nil, -1, 1, 3},
+		{"c", "research", nil, nil, nil, 7, 0, 3},
+		{"cp", "rumor", (*Engine).v_rumor, nil, nil, 0, 0, 1},
+		{"c", "sail", (*Engine).v_sail, (*Engine).d_sail, (*Engine).i_sail, -1, 0, 4},
+		{"c", "sell", nil, nil, nil, 0, 0, 1},
+		{"cr", "seek", (*Engine).v_seek, (*Engine).d_seek, nil, 7, 1, 3},
+		{"c", "sneak", (*Engine).v_sneak, (*Engine).d_sneak, nil, 3, 0, 3},
+		{"cp", "split", (*Engine).v_split, nil, nil, 0, 0, 1},
+		{"cr", "stack", (*Engine).v_stack, nil, nil, 0, 0, 1},
+		{"c", "stone", (*Engine).v_quarry, nil, nil, -1, 1, 3},
+		{"c", "study", nil, nil, nil, 7, 1, 3},
+		{"c", "surrender", (*Engine).v_surrender, nil, nil, 1, 0, 1},
+		{"c", "swear", nil, nil, nil, 0, 0, 1},
+		{"cr", "take", (*Engine).v_get, nil, nil, 0, 0, 1},
+		{"cp", "times", (*Engine).v_times, nil, nil, 0, 0, 1},
+		{"c", "train", nil, nil, nil, -1, 1, 3},
+		{"c", "trance", nil, nil, nil, 28, 0, 3},
+		{"cr", "terrorize", nil, nil, nil, 7, 0, 3},
+		{"c", "torture", nil, nil, nil, 7, 0, 3},
+		{"c", "unload", (*Engine).v_unload, nil, nil, 0, 0, 3},
+		{"c", "ungarrison", nil, nil, nil, 1, 0, 3},
+		{"cr", "unstack", (*Engine).v_unstack, nil, nil, 0, 0, 1},
+		{"c", "use", (*Engine).v_use, (*Engine).d_use, (*Engine).i_use, -1, 1, 3},
+		{"crm", "wait", (*Engine).v_wait, (*Engine).d_wait, (*Engine).i_wait, -1, 1, 1},
+		{"c", "wood", (*Engine).v_wood, nil, nil, -1, 1, 3},
+		{"cr", "xyzzy", (*Engine).v_xyzzy, nil, nil, 0, 0, 3},
+		{"c", "yew", (*Engine).v_yew, nil, nil, -1, 1, 3},
+
+		{"cr", "north", (*Engine).v_north, nil, nil, -1, 0, 2},
+		{"cr", "n", (*Engine).v_north, nil, nil, -1, 0, 2},
+		{"cr", "s", (*Engine).v_south, nil, nil, -1, 0, 2},
+		{"cr", "south", (*Engine).v_south, nil, nil, -1, 0, 2},
+		{"cr", "east", (*Engine).v_east, nil, nil, -1, 0, 2},
+		{"cr", "e", (*Engine).v_east, nil, nil, -1, 0, 2},
+		{"cr", "west", (*Engine).v_west, nil, nil, -1, 0, 2},
+		{"cr", "w", (*Engine).v_west, nil, nil, -1, 0, 2},
+		{"cr", "enter", (*Engine).v_enter, nil, nil, -1, 0, 2},
+		{"cr", "exit", (*Engine).v_exit, nil, nil, -1, 0, 2},
+		{"cr", "in", (*Engine).v_enter, nil, nil, -1, 0, 2},
+		{"cr", "out", (*Engine).v_exit, nil, nil, -1, 0, 2},
+
+		{"", "begin", nil, nil, nil, 0, 0, 0},
+		{"", "unit", nil, nil, nil, 0, 0, 0},
+		{"", "email", nil, nil, nil, 0, 0, 0},
+		{"", "vis_email", nil, nil, nil, 0, 0, 0},
+		{"", "end", nil, nil, nil, 0, 0, 0},
+		{"", "flush", nil, nil, nil, 0, 0, 0},
+		{"", "lore", nil, nil, nil, 0, 0, 0},
+		{"", "passwd", nil, nil, nil, 0, 0, 0},
+		{"", "password", nil, nil, nil, 0, 0, 0},
+		{"", "players", nil, nil, nil, 0, 0, 0},
+		{"", "resend", nil, nil, nil, 0, 0, 0},
+		{"cpr", "stop", (*Engine).v_stop, nil, nil, 0, 0, 0},
+
+		{"i", "look", (*Engine).v_look, nil, nil, 0, 0, 1},
+		{"i", "l", (*Engine).v_look, nil, nil, 0, 0, 1},
+		{"i", "ct", (*Engine).v_ct, nil, nil, 0, 0, 1},
+		{"i", "be", (*Engine).v_be, nil, nil, 0, 0, 1},
+		{"i", "additem", (*Engine).v_add_item, nil, nil, 0, 0, 1},
+		{"i", "subitem", (*Engine).v_sub_item, nil, nil, 0, 0, 1},
+		{"i", "h", (*Engine).v_listcmds, nil, nil, 0, 0, 1},
+		{"i", "dump", (*Engine).v_dump, nil, nil, 0, 0, 1},
+		{"i", "i", (*Engine).v_invent, nil, nil, 0, 0, 1},
+		{"i", "fix", (*Engine).v_fix, nil, nil, 0, 0, 1},
+		{"i", "fix2", (*Engine).v_fix2, nil, nil, 0, 0, 1},
+		{"i", "kill", (*Engine).v_kill, nil, nil, 0, 0, 1},
+		{"i", "los", (*Engine).v_los, nil, nil, 0, 0, 1},
+		{"m", "relore", (*Engine).v_relore, nil, nil, 0, 0, 1},
+		{"i", "sk", (*Engine).v_skills, nil, nil, 0, 0, 1},
+		{"i", "know", (*Engine).v_know, nil, nil, 0, 0, 1},
+		{"i", "seed", (*Engine).v_seed, nil, nil, 0, 0, 1},
+		{"i", "seedmarket", (*Engine).v_seedmarket, nil, nil, 0, 0, 1},
+		{"i", "sheet", (*Engine).v_lore, nil, nil, 0, 0, 1},
+		{"i", "poof", (*Engine).v_poof, nil, nil, 0, 0, 1},
+		{"i", "postproc", (*Engine).v_postproc, nil, nil, 0, 0, 1},
+		{"i", "save", (*Engine).v_save, nil, nil, 0, 0, 1},
+		{"i", "seeall", (*Engine).v_see_all, nil, nil, 0, 0, 1},
+		{"i", "tp", (*Engine).v_take_pris, nil, nil, 0, 0, 1},
+		{"i", "makeloc", (*Engine).v_makeloc, nil, nil, 0, 0, 1},
+		{"i", "remail", nil, nil, nil, 0, 0, 1},
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 func TestGlobInit(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 
 	// Set some non-zero values first
 	e.globals.box_head[T_char] = 100
@@ -46,6 +47,7 @@ func TestGlobInit(t *testing.T) {
 
 func TestSysclock(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 
 	// Set and get sysclock
 	testTime := olytime{day: 15, turn: 42, days_since_epoch: 1260}
@@ -65,12 +67,13 @@ func TestSysclock(t *testing.T) {
 
 func TestKindFirstNext(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 	e.GlobInit()
 
 	// Create a chain of characters: 10 -> 20 -> 30
-	e.globals.bx[10] = &box{kind: T_char, x_next_kind: 20}
-	e.globals.bx[20] = &box{kind: T_char, x_next_kind: 30}
-	e.globals.bx[30] = &box{kind: T_char, x_next_kind: 0}
+	e.setBox(10, &box{kind: T_char, x_next_kind: 20})
+	e.setBox(20, &box{kind: T_char, x_next_kind: 30})
+	e.setBox(30, &box{kind: T_char, x_next_kind: 0})
 	e.globals.box_head[T_char] = 10
 
 	// Test KindFirst
@@ -109,12 +112,13 @@ func TestKindFirstNext(t *testing.T) {
 
 func TestSubFirstNext(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 	e.GlobInit()
 
 	// Create a chain of cities: 100 -> 200 -> 300
-	e.globals.bx[100] = &box{kind: T_loc, skind: sub_city, x_next_sub: 200}
-	e.globals.bx[200] = &box{kind: T_loc, skind: sub_city, x_next_sub: 300}
-	e.globals.bx[300] = &box{kind: T_loc, skind: sub_city, x_next_sub: 0}
+	e.setBox(100, &box{kind: T_loc, skind: sub_city, x_next_sub: 200})
+	e.setBox(200, &box{kind: T_loc, skind: sub_city, x_next_sub: 300})
+	e.setBox(300, &box{kind: T_loc, skind: sub_city, x_next_sub: 0})
 	e.globals.sub_head[sub_city] = 100
 
 	// Test SubFirst
@@ -142,12 +146,13 @@ func TestSubFirstNext(t *testing.T) {
 
 func TestCharactersIterator(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 	e.GlobInit()
 
 	// Create a chain of characters: 10 -> 20 -> 30
-	e.globals.bx[10] = &box{kind: T_char, x_next_kind: 20}
-	e.globals.bx[20] = &box{kind: T_char, x_next_kind: 30}
-	e.globals.bx[30] = &box{kind: T_char, x_next_kind: 0}
+	e.setBox(10, &box{kind: T_char, x_next_kind: 20})
+	e.setBox(20, &box{kind: T_char, x_next_kind: 30})
+	e.setBox(30, &box{kind: T_char, x_next_kind: 0})
 	e.globals.box_head[T_char] = 10
 
 	chars := e.Characters()
@@ -166,11 +171,12 @@ func TestCharactersIterator(t *testing.T) {
 
 func TestPlayersIterator(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 	e.GlobInit()
 
 	// Create players: 100 -> 200
-	e.globals.bx[100] = &box{kind: T_player, x_next_kind: 200}
-	e.globals.bx[200] = &box{kind: T_player, x_next_kind: 0}
+	e.setBox(100, &box{kind: T_player, x_next_kind: 200})
+	e.setBox(200, &box{kind: T_player, x_next_kind: 0})
 	e.globals.box_head[T_player] = 100
 
 	players := e.Players()
@@ -189,11 +195,12 @@ func TestPlayersIterator(t *testing.T) {
 
 func TestCitiesIterator(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 	e.GlobInit()
 
 	// Create cities via subkind chain: 1000 -> 2000
-	e.globals.bx[1000] = &box{kind: T_loc, skind: sub_city, x_next_sub: 2000}
-	e.globals.bx[2000] = &box{kind: T_loc, skind: sub_city, x_next_sub: 0}
+	e.setBox(1000, &box{kind: T_loc, skind: sub_city, x_next_sub: 2000})
+	e.setBox(2000, &box{kind: T_loc, skind: sub_city, x_next_sub: 0})
 	e.globals.sub_head[sub_city] = 1000
 
 	cities := e.Cities()
@@ -212,6 +219,7 @@ func TestCitiesIterator(t *testing.T) {
 
 func TestEmptyIterators(t *testing.T) {
 	e := &Engine{}
+	e.globals.bx = boxStore{}
 	e.GlobInit()
 
 	// All iterators should return empty slices when no entities exist
@@ -0,0 +1,752 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// gm.go - Game master statistics report ported from src/gm.c
+
+package taygete
+
+import "slices"
+
+// clearGoldStats zeroes the gold statistics at the start of a turn.
+// The C game started each turn in a fresh process.
+func (e *Engine) clearGoldStats() {
+	e.globals.gold_common_magic, e.globals.gold_lead_to_gold, e.globals.gold_pot_basket = 0, 0, 0
+	e.globals.gold_trade, e.globals.gold_inn, e.globals.gold_taxes, e.globals.gold_provinces = 0, 0, 0, 0
+	e.globals.gold_times, e.globals.gold_combat, e.globals.gold_combat_indep = 0, 0, 0
+	e.globals.gold_petty_thief, e.globals.gold_temple, e.globals.gold_pillage = 0, 0, 0
+	e.globals.gold_ferry, e.globals.gold_opium, e.globals.gold_garrison = 0, 0, 0
+}
+
+// percent returns n as a percentage of total, or 0 when there is
+// nothing to divide by. Generated databases often have no gates,
+// sublocs or nobles, which crashed the C report.
+func percent(n, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return n * 100 / total
+}
+
+// sortByTemp orders l by descending temp count.
+func (e *Engine) sortByTemp(l []int) {
+	slices.SortStableFunc(l, func(a, b int) int {
+		return e.globals.bx.get(b).temp - e.globals.bx.get(a).temp
+	})
+}
+
+// gm_show_skill_use_counts lists the skills used this turn, most used
+// first.
+// Ported from src/gm.c lines 41-88.
+func (e *Engine) gm_show_skill_use_counts(pl int) {
+	var l []int
+
+	for _, sk := range e.Skills() {
+		if e.skill_school(sk) == sk { // skip category skills
+			continue
+		}
+
+		if p := e.rp_skill(sk); p != nil && p.use_count != 0 {
+			l = append(l, sk)
+		}
+	}
+
+	slices.SortStableFunc(l, func(a, b int) int {
+		return e.rp_skill(b).use_count - e.rp_skill(a).use_count
+	})
+
+	e.report_out(pl, "")
+	e.report_out(pl, "Skill use counts:")
+	e.report_out(pl, "-----------------")
+	e.report_out(pl, "")
+	e.report_out(pl, "%5s  %4s  %5s  %s", "count", "who", "skill", "name")
+	e.report_out(pl, "%5s  %4s  %5s  %s", "-----", "---", "-----", "----")
+
+	for _, sk := range l {
+		p := e.rp_skill(sk)
+
+		e.report_out(pl, "%4d   %4s  %5s  %s",
+			p.use_count,
+			box_code_less(e.player(p.last_use_who)),
+			box_code_less(sk),
+			e.just_name(sk))
+	}
+
+	e.report_out(pl, "")
+}
+
+// gm_show_skills_known lists how many characters know each skill.
+// Ported from src/gm.c lines 100-162.
+func (e *Engine) gm_show_skills_known(pl int) {
+	var l []int
+
+	e.clear_temps(T_skill)
+
+	for _, i := range e.Characters() {
+		for _, se := range e.getCharSkills(i) {
+			if se.know == SKILL_know && e.valid_box(se.skill) {
+				e.globals.bx.get(se.skill).temp++
+			}
+		}
+	}
+
+	for _, sk := range e.Skills() {
+		if e.skill_school(sk) == sk { // skip category skills
+			continue
+		}
+
+		l = append(l, sk)
+	}
+
+	e.sortByTemp(l)
+
+	e.report_out(pl, "")
+	e.report_out(pl, "Skills known by players:")
+	e.report_out(pl, "------------------------")
+	e.report_out(pl, "")
+	e.report_out(pl, "%5s  %5s  %4s  %s", "count", "skill", "use", "name")
+	e.report_out(pl, "%5s  %5s  %4s  %s", "-----", "-----", "---", "----")
+
+	for _, sk := range l {
+		use := ""
+		if p := e.rp_skill(sk); p != nil && p.use_count != 0 {
+			use = comma_num(p.use_count)
+		}
+
+		e.report_out(pl, "%4d   %5s  %4s  %s",
+			e.globals.bx.get(sk).temp,
+			box_code_less(sk),
+			use,
+			e.just_name(sk))
+	}
+
+	e.report_out(pl, "")
+}
+
+// gm_show_interesting_attributes counts rarely seen magical effects.
+// Ported from src/gm.c lines 165-262.
+func (e *Engine) gm_show_interesting_attributes(pl int) {
+	var abilityShroud, hinderMeditation, projectCast, quickCast int
+	var locShroud, locBarrier, locOpium int
+	var formatOne, nplay int
+	var rams, ngal int
+
+	for _, i := range e.Characters() {
+		pc := e.rp_magic(i)
+		if pc == nil {
+			continue
+		}
+
+		if pc.ability_shroud != 0 {
+			abilityShroud++
+		}
+		if pc.hinder_meditation != 0 {
+			hinderMeditation++
+		}
+		if pc.project_cast != 0 {
+			projectCast++
+		}
+		if pc.quick_cast != 0 {
+			quickCast++
+		}
+	}
+
+	for _, i := range e.Locations() {
+		lc := e.rp_loc(i)
+
+		if lc != nil && lc.shroud != 0 {
+			locShroud++
+		}
+		if lc != nil && lc.barrier != 0 {
+			locBarrier++
+		}
+		if e.loc_opium(i) != 0 {
+			locOpium++
+		}
+	}
+
+	for _, i := range e.Ships() {
+		if e.subkind(i) != sub_galley {
+			continue
+		}
+		ngal++
+		if e.ship_has_ram(i) != 0 {
+			rams++
+		}
+	}
+
+	for _, i := range e.Players() {
+		if e.subkind(i) != sub_pl_regular {
+			continue
+		}
+
+		nplay++
+		if e.player_format(i) != 0 {
+			formatOne++
+		}
+	}
+
+	e.report_out(pl, "Interesting attribute counts")
+	e.report_out(pl, "----------------------------")
+	e.report_out(pl, "")
+
+	e.report_out(pl, "char ability shroud:    %d", abilityShroud)
+	e.report_out(pl, "char hinder meditate:   %d", hinderMeditation)
+	e.report_out(pl, "char project cast:      %d", projectCast)
+	e.report_out(pl, "char quick cast:        %d", quickCast)
+	e.report_out(pl, "loc shroud:             %d", locShroud)
+	e.report_out(pl, "loc barrier:            %d", locBarrier)
+	e.report_out(pl, "loc opium:              %d", locOpium)
+	e.report_out(pl, "format one:             %d/%d", formatOne, nplay)
+
+	e.report_out(pl, "")
+
+	if ngal != 0 {
+		e.report_out(pl, "galleys with rams:      %d (%d%%)",
+			rams, percent(rams, ngal))
+	}
+}
+
+// gm_list_animate_items lists the items that fight, work or can be
+// captured.
+// Ported from src/gm.c lines 265-312.
+func (e *Engine) gm_list_animate_items(pl int) {
+	yesNo := func(b bool) string {
+		if b {
+			return "yes "
+		}
+		return "no  "
+	}
+
+	e.report_out(pl, "")
+	e.report_out(pl, "Animate items list")
+	e.report_out(pl, "------------------")
+	e.report_out(pl, "")
+
+	e.report_out(pl, "%25s %8s %8s %8s  %s",
+		"name", "swamp", "man-like", "beast", "fighter")
+	e.report_out(pl, "%25s %8s %8s %8s  %s",
+		"----", "-----", "--------", "-----", "-------")
+
+	for _, i := range e.Items() {
+		if !e.is_fighter(i) &&
+			e.man_item(i) == 0 &&
+			!beast_capturable(i) &&
+			e.item_animal(i) == 0 {
+			continue
+		}
+
+		buf := " -"
+		if e.is_fighter(i) {
+			buf = sout("(%d,%d,%d)",
+				e.item_attack(i),
+				e.item_defense(i),
+				e.item_missile(i))
+		}
+
+		e.report_out(pl, "%25s %8s %8s %8s  %s",
+			e.box_name(i),
+			yesNo(e.item_animal(i) != 0),
+			yesNo(e.man_item(i) != 0),
+			yesNo(e.item_capturable(i) != 0),
+			buf)
+	}
+}
+
+// gm_show_gold breaks down where the turn's new gold came from.
+// Ported from src/gm.c lines 315-360.
+func (e *Engine) gm_show_gold(pl int) {
+	sum := e.globals.gold_common_magic + e.globals.gold_lead_to_gold + e.globals.gold_pot_basket +
+		e.globals.gold_trade + e.globals.gold_opium + e.globals.gold_inn + e.globals.gold_taxes +
+		e.globals.gold_provinces + e.globals.gold_garrison + e.globals.gold_times +
+		e.globals.gold_combat_indep + e.globals.gold_petty_thief +
+		e.globals.gold_temple + e.globals.gold_pillage + e.globals.gold_ferry
+
+	e.report_out(pl, "")
+	e.report_out(pl, "Gold report")
+	e.report_out(pl, "-----------")
+	e.report_out(pl, "")
+
+	if sum <= 0 {
+		return
+	}
+
+	line := func(label string, n int) {
+		e.report_out(pl, "%-22s%10s %3d%%", label, comma_num(n), percent(n, sum))
+	}
+
+	line("Common magic:", e.globals.gold_common_magic)
+	line("Lead to gold:", e.globals.gold_lead_to_gold)
+	line("Pots and baskets:", e.globals.gold_pot_basket)
+	line("Opium:", e.globals.gold_opium)
+	line("Trade to cities:", e.globals.gold_trade)
+	line("Inn income:", e.globals.gold_inn)
+	line("Taxes:", e.globals.gold_taxes)
+	line("Provinces:", e.globals.gold_provinces)
+	line("Times press:", e.globals.gold_times)
+	line("Combat with indeps:", e.globals.gold_combat_indep)
+	line("Petty thievery:", e.globals.gold_petty_thief)
+	line("Temple income:", e.globals.gold_temple)
+	line("Pillaging:", e.globals.gold_pillage)
+	line("Ferry boarding:", e.globals.gold_ferry)
+	line("Garrison Pay:", e.globals.gold_garrison)
+	e.report_out(pl, "%-22s%10s %4s", "", "", "----")
+	e.report_out(pl, "%-22s%10s", "Total:", comma_num(sum))
+
+	e.report_out(pl, "")
+	e.report_out(pl, "%-22s%10s", "Player combat:", comma_num(e.globals.gold_combat))
+	e.report_out(pl, "")
+}
+
+// gm_show_control_arts lists the npc control tokens held by players.
+// Ported from src/gm.c lines 362-388.
+func (e *Engine) gm_show_control_arts(pl int) {
+	e.report_out(pl, "")
+	e.report_out(pl, "Captured control artifacts")
+	e.report_out(pl, "--------------------------")
+	e.report_out(pl, "")
+
+	for item := e.SubFirst(sub_npc_token); item > 0; item = e.SubNext(item) {
+		n := e.token_player(e.item_unique(item))
+		if n != indep_player {
+			e.report_out(pl, "%-33s  %s", e.box_name(item), e.box_name(n))
+		}
+	}
+
+	e.report_out(pl, "")
+}
+
+// gm_count_stuff counts finished and unfinished structures.
+// Ported from src/gm.c lines 391-456.
+func (e *Engine) gm_count_stuff(pl int) {
+	var castle, castleNotdone, tower, towerNotdone int
+	var mine, mineNotdone, temple, templeNotdone int
+	var galley, galleyNotdone, round, roundNotdone int
+	var inn, innNotdone int
+
+	for _, i := range e.LocsAndShips() {
+		switch e.subkind(i) {
+		case sub_castle:
+			castle++
+		case sub_castle_notdone:
+			castleNotdone++
+		case sub_tower:
+			tower++
+		case sub_tower_notdone:
+			towerNotdone++
+		case sub_galley:
+			galley++
+		case sub_galley_notdone:
+			galleyNotdone++
+		case sub_roundship:
+			round++
+		case sub_roundship_notdone:
+			roundNotdone++
+		case sub_temple:
+			temple++
+		case sub_temple_notdone:
+			templeNotdone++
+		case sub_inn:
+			inn++
+		case sub_inn_notdone:
+			innNotdone++
+		case sub_mine:
+			mine++
+		case sub_mine_notdone:
+			mineNotdone++
+		}
+	}
+
+	row := func(name string, done, notdone int) {
+		e.report_out(pl, "%10s |%9s  %8s", name, comma_num(done), comma_num(notdone))
+	}
+
+	e.report_out(pl, "%10s  %9s  %s", "", "finished", "unfinished")
+	e.report_out(pl, "%10s +----------------------", "")
+	row("galley", galley, galleyNotdone)
+	row("roundship", round, roundNotdone)
+	row("tower", tower, towerNotdone)
+	row("castle", castle, castleNotdone)
+	row("mine", mine, mineNotdone)
+	row("inn", inn, innNotdone)
+	row("temple", temple, templeNotdone)
+	e.report_out(pl, "")
+}
+
+// gm_show_gate_stats reports how many gates players have found.
+// Ported from src/gm.c lines 458-519.
+func (e *Engine) gm_show_gate_stats(pl int) {
+	var nGates, nFound int
+	var ngateSeal, ngateJump, ngateUnseal int
+
+	e.clear_temps(T_gate)
+
+	for _, i := range e.Players() {
+		for j := range e.getPlayerKnowledge(i) {
+			if e.kind(j) == T_gate {
+				e.globals.bx.get(j).temp++
+			}
+		}
+	}
+
+	for _, i := range e.Gates() {
+		nGates++
+		if e.globals.bx.get(i).temp != 0 {
+			nFound++
+		}
+
+		if g := e.rp_gate(i); g != nil {
+			if g.seal_key != 0 {
+				ngateSeal++
+			}
+			if g.notify_jumps != 0 {
+				ngateJump++
+			}
+			if g.notify_unseal != 0 {
+				ngateUnseal++
+			}
+		}
+	}
+
+	if nGates == 0 { // generated databases often have no gates
+		return
+	}
+
+	e.report_out(pl, "%d/%d gates found (%d%%)", nFound, nGates,
+		percent(nFound, nGates))
+	e.report_out(pl, "    %d sealed (%d%%), %d notify jump (%d%%), %d notify "+
+		"unseal (%d%%)",
+		ngateSeal, percent(ngateSeal, nGates),
+		ngateJump, percent(ngateJump, nGates),
+		ngateUnseal, percent(ngateUnseal, nGates))
+	e.report_out(pl, "")
+}
+
+// gm_show_locs_visited reports how much of the map players have seen.
+// Ported from src/gm.c lines 521-617.
+func (e *Engine) gm_show_locs_visited(pl int) {
+	var nProv, nProvVisit, nSub, nSubVisit, hid, vis int
+	var nf, nt, nfVis, nfHid int
+
+	e.clear_temps(T_loc)
+
+	for _, i := range e.Players() {
+		for j := range e.getPlayerKnowledge(i) {
+			if e.kind(j) == T_loc {
+				e.globals.bx.get(j).temp++
+			}
+		}
+	}
+
+	for _, i := range e.Locations() {
+		switch e.loc_depth(i) {
+		case LOC_province:
+			nProv++
+			if e.globals.bx.get(i).temp != 0 {
+				nProvVisit++
+			}
+		case LOC_subloc:
+			nSub++
+			if e.globals.bx.get(i).temp != 0 {
+				nSubVisit++
+				if e.loc_hidden(i) {
+					hid++
+				} else {
+					vis++
+				}
+			}
+		}
+	}
+
+	e.report_out(pl, "%d/%d provinces visited (%d%%)", nProvVisit, nProv,
+		percent(nProvVisit, nProv))
+	e.report_out(pl, "%d/%d sublocs visited (%d%%)", nSubVisit, nSub,
+		percent(nSubVisit, nSub))
+	e.report_out(pl, "    %d%% visible, %d%% hidden",
+		percent(vis, nSubVisit),
+		percent(hid, nSubVisit))
+
+	for _, i := range e.Locations() {
+		if e.loc_depth(i) != LOC_province || e.globals.bx.get(i).temp == 0 {
+			continue
+		}
+
+		li := e.rp_loc_info(i)
+		if li == nil {
+			continue
+		}
+
+		for _, j := range li.here_list {
+			if e.kind(j) != T_loc || e.loc_depth(j) != LOC_subloc {
+				continue
+			}
+
+			nt++
+			if e.globals.bx.get(j).temp != 0 {
+				nf++
+				if e.loc_hidden(j) {
+					nfHid++
+				} else {
+					nfVis++
+				}
+			}
+		}
+	}
+
+	e.report_out(pl, "    %d%% of visisted province's sublocs found",
+		percent(nf, nt))
+	e.report_out(pl, "    %d%% visible, %d%% hidden",
+		percent(nfVis, nf),
+		percent(nfHid, nf))
+
+	e.report_out(pl, "")
+}
+
+// gm_loyalty_stats breaks down the loyalty of player nobles.
+// Ported from src/gm.c lines 619-660.
+func (e *Engine) gm_loyalty_stats(pl int) {
+	var tot, oath, fear, cont, unsw int
+
+	for _, i := range e.Characters() {
+		if e.subkind(i) != 0 {
+			continue
+		}
+
+		tot++
+		switch e.loyal_kind(i) {
+		case LOY_oath:
+			oath++
+		case LOY_fear:
+			fear++
+		case LOY_contract:
+			cont++
+		case LOY_unsworn:
+			unsw++
+		}
+	}
+
+	e.report_out(pl, "%d chars: %d oath (%d%%), %d fear (%d%%), %d contract "+
+		"(%d%%), %d unsworn (%d%%)",
+		tot, oath, percent(oath, tot),
+		fear, percent(fear, tot),
+		cont, percent(cont, tot),
+		unsw, percent(unsw, tot))
+
+	e.report_out(pl, "")
+}
+
+// gm_land_stats lists how many nobles are in each region.
+// Ported from src/gm.c lines 673-716.
+func (e *Engine) gm_land_stats(pl int) {
+	var l []int
+	nChars := 0
+
+	e.clear_temps(T_loc)
+
+	for _, i := range e.Characters() {
+		if r := e.region(i); e.valid_box(r) {
+			e.globals.bx.get(r).temp++
+		}
+		nChars++
+	}
+
+	for _, i := range e.Locations() {
+		if e.globals.bx.get(i).temp != 0 {
+			l = append(l, i)
+		}
+	}
+
+	e.sortByTemp(l)
+
+	e.report_out(pl, "%10s  %s", "nobles", "region")
+	e.report_out(pl, "%10s  %s", "------", "------")
+
+	for _, i := range l {
+		e.report_out(pl, "%10s  %s",
+			comma_num(e.globals.bx.get(i).temp),
+			e.just_name(i))
+	}
+	e.report_out(pl, "%10s  %s", "======", "")
+	e.report_out(pl, "%10s  %s", comma_num(nChars), "")
+
+	e.report_out(pl, "")
+}
+
+// gm_rank_factions lists the regular factions ranked by temp.
+func (e *Engine) gm_rank_factions(pl int, label, underline string) {
+	var l []int
+
+	for _, i := range e.Players() {
+		if e.subkind(i) == sub_pl_regular {
+			l = append(l, i)
+		}
+	}
+
+	e.sortByTemp(l)
+
+	e.report_out(pl, "%4s %11s  %s", "rank", label, "faction")
+	e.report_out(pl, "%4s %11s  %s", "----", underline, "-------")
+
+	for rank, i := range l {
+		e.report_out(pl, "%4d %11s  %s", rank+1,
+			comma_num(e.globals.bx.get(i).temp),
+			e.box_name(i))
+	}
+
+	e.report_out(pl, "")
+}
+
+// gm_faction_wealth ranks factions by the gold their nobles hold.
+// Ported from src/gm.c lines 729-769.
+func (e *Engine) gm_faction_wealth(pl int) {
+	e.clear_temps(T_player)
+
+	for _, i := range e.Characters() {
+		if p := e.player(i); p != 0 {
+			e.globals.bx.get(p).temp += e.has_item(i, item_gold)
+		}
+	}
+
+	e.gm_rank_factions(pl, "gold", "----")
+}
+
+// gm_nobles_list ranks factions by the number of nobles they have.
+// Ported from src/gm.c lines 782-822.
+func (e *Engine) gm_nobles_list(pl int) {
+	e.clear_temps(T_player)
+
+	for _, i := range e.Characters() {
+		if p := e.player(i); p != 0 {
+			e.globals.bx.get(p).temp++
+		}
+	}
+
+	e.gm_rank_factions(pl, "nobles", "------")
+}
+
+// gm_player_details summarizes each faction's holdings.
+// Ported from src/gm.c lines 825-938.
+func (e *Engine) gm_player_details(pl int) {
+	e.report_out(pl, "%4s %3s %5s %5s %4s %6s %5s %6s",
+		"who", "age", "gold", "units", "bld", "subloc", "ships", "skills")
+	e.report_out(pl, "%4s %3s %5s %5s %4s %6s %5s %6s",
+		"---", "---", "----", "-----", "---", "------", "-----", "------")
+
+	for _, i := range e.Players() {
+		if e.subkind(i) == sub_pl_system {
+			continue
+		}
+
+		var sumGold, sumUnits, sumBld, sumSubloc, sumShip, sumSkills int
+		skills := make(map[int]bool)
+
+		for _, j := range e.loop_units(i) {
+			sumGold += e.has_item(j, item_gold)
+			sumUnits++
+
+			where := e.subloc(j)
+
+			if e.loc_depth(where) == LOC_build &&
+				e.building_owner(where) == j &&
+				!e.is_ship(where) &&
+				!e.is_ship_notdone(where) {
+				sumBld++
+			}
+
+			if e.loc_depth(where) == LOC_subloc &&
+				e.first_character(where) == j {
+				sumSubloc++
+			}
+
+			if (e.is_ship(where) || e.is_ship_notdone(where)) &&
+				e.building_owner(where) == j {
+				sumShip++
+			}
+
+			for _, sk := range e.getCharSkills(j) {
+				if sk.know == SKILL_know {
+					skills[sk.skill] = true
+				}
+			}
+		}
+		sumSkills = len(skills)
+
+		age := "???"
+		if p := e.rp_player(i); p != nil {
+			age = sout("%d", e.globals.sysclock.turn-p.first_turn)
+		}
+
+		e.report_out(pl, "%4s %3s %5s %5s %4s %6s %5s %6s  %s",
+			box_code_less(i),
+			age,
+			knum(sumGold, false),
+			knum(sumUnits, false),
+			knum(sumBld, true),
+			knum(sumSubloc, true),
+			knum(sumShip, true),
+			knum(sumSkills, true),
+			e.just_name(i))
+	}
+
+	e.report_out(pl, "")
+}
+
+// list_all_items lists every item's weight and capacities.
+// Ported from src/gm.c lines 971-999.
+func (e *Engine) list_all_items(pl int) {
+	e.report_out(pl, "%4s %-24s %6s %4s %4s %4s", "item", "name", "weight", "land", "ride", "fly")
+	e.report_out(pl, "%4s %-24s %6s %4s %4s %4s", "----", "----", "------", "----", "----", "---")
+
+	for _, i := range e.Items() {
+		e.report_out(pl, "%4s %-24s %6s %4s %4s %4s",
+			box_code_less(i),
+			e.just_name(i),
+			comma_num(int(e.item_weight(i))),
+			comma_num(int(e.item_land_cap(i))),
+			comma_num(int(e.item_ride_cap(i))),
+			comma_num(int(e.item_fly_cap(i))))
+	}
+
+	e.report_out(pl, "")
+}
+
+// gmReport writes the game master's statistics report to pl and the
+// item listing to skill_player. list_all_notices is not ported; posted
+// notices are not kept yet.
+// Port of C gm_report() from gm.c.
+func (e *Engine) gmReport(pl int) {
+	e.stage("gm_report()")
+
+	if e.kind(pl) != T_player {
+		return
+	}
+
+	e.gm_show_interesting_attributes(pl)
+	e.gm_show_gold(pl)
+	e.gm_show_control_arts(pl)
+	e.gm_list_animate_items(pl)
+	e.gm_show_skill_use_counts(pl)
+	e.gm_count_stuff(pl)
+	e.gm_land_stats(pl)
+	e.gm_show_gate_stats(pl)
+	e.gm_show_locs_visited(pl)
+	e.gm_loyalty_stats(pl)
+	e.gm_show_skills_known(pl)
+	e.gm_faction_wealth(pl)
+	e.gm_nobles_list(pl)
+	e.gm_player_details(pl)
+
+	e.list_all_items(skill_player)
+}
@@ -0,0 +1,73 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package taygete
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGmReport(t *testing.T) {
+	e, pl1, who1, _, who2, _ := setupPermTest(t)
+
+	teg.alloc_box(gm_player, T_player, sub_pl_system)
+	teg.alloc_box(skill_player, T_player, sub_pl_system)
+	teg.alloc_box(item_gold, T_item, 0)
+	teg.set_name(item_gold, "gold")
+	teg.gen_item(who1, item_gold, 1500)
+	teg.gen_item(who2, item_gold, 200)
+
+	e.clearGoldStats()
+	t.Cleanup(e.clearGoldStats)
+	teg.globals.gold_temple = 300
+	teg.add_gold_ferry(100)
+
+	e.gmReport(gm_player)
+
+	report := e.Report(gm_player)
+	for _, want := range []string{
+		"Gold report",
+		"Temple income:               300  75%",
+		"Ferry boarding:              100  25%",
+		"Total:                       400",
+		"   1       1,500  " + strings.ReplaceAll(teg.box_name(pl1), "~", " "),
+		"2 chars: 0 oath (0%), 0 fear (0%), 0 contract (0%), 2 unsworn (100%)",
+	} {
+		if !slices.Contains(report, want) {
+			t.Errorf("gm report missing %q", want)
+		}
+	}
+
+	items := e.Report(skill_player)
+	if !slices.ContainsFunc(items, func(s string) bool {
+		return strings.Contains(s, " gold ")
+	}) {
+		t.Errorf("item listing missing gold:\n%q", items)
+	}
+}
+
+func TestGmReportNoPlayer(t *testing.T) {
+	e := newTestEngine(t)
+
+	e.gmReport(gm_player)
+	e.gmShowAllSkills(skill_player)
+
+	if len(e.globals.reports) != 0 {
+		t.Errorf("reports = %v, want none without the gm players", e.globals.reports)
+	}
+}
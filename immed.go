@@ -111,7 +111,7 @@ func (e *Engine) v_sub_item(c *command) int {
 // Port of C v_dump().
 func (e *Engine) v_dump(c *command) int {
 	if e.valid_box(c.a) {
-		e.globals.bx[c.a].temp = 0
+		e.globals.bx.get(c.a).temp = 0
 		// In the Go port, we'll log the box data instead of save_box to stdout
 		e.out(c.who, "Box %s dumped.", e.box_code(c.a))
 		return TRUE
@@ -122,7 +122,7 @@ func (e *Engine) v_dump(c *command) int {
 // v_poof teleports a character to a location.
 // Port of C v_poof().
 func (e *Engine) v_poof(c *command) int {
-	if !is_loc_or_ship(c.a) {
+	if !e.is_loc_or_ship(c.a) {
 		e.wout(c.who, "%s is not a location.", e.box_code(c.a))
 		return FALSE
 	}
@@ -130,7 +130,7 @@ func (e *Engine) v_poof(c *command) int {
 	e.move_stack(c.who, c.a)
 	e.wout(c.who, ">poof!< A cloud of orange smoke appears and wisks you away...")
 	e.out(c.who, "")
-	e.show_loc(c.who, loc(c.who))
+	e.show_loc(c.who, e.loc(c.who))
 
 	return TRUE
 }
@@ -201,12 +201,12 @@ func (e *Engine) v_save(c *command) int {
 // Port of C v_los().
 func (e *Engine) v_los(c *command) int {
 	target := c.a
-	if !is_loc_or_ship(target) {
+	if !e.is_loc_or_ship(target) {
 		e.wout(c.who, "%s is not a location.", e.box_code(target))
 		return FALSE
 	}
 
-	d := e.los_province_distance(subloc(c.who), target)
+	d := e.los_province_distance(e.subloc(c.who), target)
 	e.wout(c.who, "distance=%d", d)
 	return TRUE
 }
@@ -240,7 +240,7 @@ func (e *Engine) v_seed(c *command) int {
 func (e *Engine) v_postproc(c *command) int {
 	// Reset studied flags and run post_month
 	for _, i := range e.Characters() {
-		ch := rp_char(i)
+		ch := e.rp_char(i)
 		if ch != nil {
 			ch.studied = 0
 		}
@@ -267,8 +267,8 @@ func (e *Engine) v_lore(c *command) int {
 // v_ct clears city trades and regenerates them.
 // Port of C v_ct().
 func (e *Engine) v_ct(c *command) int {
-	for i := sub_first(sub_city); i != 0; i = sub_next(i) {
-		e.globals.bx[i].trades = nil
+	for i := e.sub_first(sub_city); i != 0; i = e.sub_next(i) {
+		e.globals.bx.get(i).trades = nil
 	}
 	e.location_trades()
 	return TRUE
@@ -277,10 +277,10 @@ func (e *Engine) v_ct(c *command) int {
 // v_seedmarket seeds city market trades.
 // Port of C v_seedmarket().
 func (e *Engine) v_seedmarket(c *command) int {
-	for i := sub_first(sub_city); i != 0; i = sub_next(i) {
+	for i := e.sub_first(sub_city); i != 0; i = e.sub_next(i) {
 		e.seed_city_trade(i)
 	}
-	for i := sub_first(sub_city); i != 0; i = sub_next(i) {
+	for i := e.sub_first(sub_city); i != 0; i = e.sub_next(i) {
 		e.loc_trade_sup(i, true)
 	}
 	return TRUE
@@ -315,8 +315,8 @@ func (e *Engine) v_credit(c *command) int {
 		return FALSE
 	}
 
-	pl := player(target)
-	if e.times_paid(pl) {
+	pl := e.player(target)
+	if e.times_paid(pl) != 0 {
 		e.wout(c.who, "Already paid faction %s.", e.box_name(pl))
 		return FALSE
 	}
@@ -355,7 +355,7 @@ func (e *Engine) v_xyzzy(c *command) int {
 // Port of C v_fix2().
 func (e *Engine) v_fix2(c *command) int {
 	for _, i := range e.Characters() {
-		if char_auraculum(i) != 0 {
+		if e.char_auraculum(i) != 0 {
 			e.learn_skill(i, sk_adv_sorcery)
 		}
 	}
@@ -368,24 +368,24 @@ func (e *Engine) fix_gates() {
 	e.clear_temps(T_loc)
 
 	// First pass: mark provinces adjacent to gates
-	for where := kind_first(T_loc); where != 0; where = kind_next(where) {
-		if loc_depth(where) != LOC_province {
+	for where := e.kind_first(T_loc); where != 0; where = e.kind_next(where) {
+		if e.loc_depth(where) != LOC_province {
 			continue
 		}
 		if !e.in_hades(where) && !e.in_clouds(where) && !e.in_faery(where) {
 			continue
 		}
-		if !e.province_gate_here(where) {
+		if e.province_gate_here(where) == 0 {
 			continue
 		}
 
 		l := e.exits_from_loc_nsew(0, where)
 		for _, exit := range l {
-			if loc_depth(exit.destination) != LOC_province {
+			if e.loc_depth(exit.destination) != LOC_province {
 				continue
 			}
-			if !e.province_gate_here(exit.destination) {
-				e.globals.bx[exit.destination].temp = 1
+			if e.province_gate_here(exit.destination) == 0 {
+				e.globals.bx.get(exit.destination).temp = 1
 			}
 		}
 	}
@@ -394,25 +394,25 @@ func (e *Engine) fix_gates() {
 	m := 1
 	for {
 		setOne := false
-		for where := kind_first(T_loc); where != 0; where = kind_next(where) {
-			if loc_depth(where) != LOC_province {
+		for where := e.kind_first(T_loc); where != 0; where = e.kind_next(where) {
+			if e.loc_depth(where) != LOC_province {
 				continue
 			}
 			if !e.in_hades(where) && !e.in_clouds(where) && !e.in_faery(where) {
 				continue
 			}
-			if e.province_gate_here(where) || e.globals.bx[where].temp != m {
+			if e.province_gate_here(where) != 0 || e.globals.bx.get(where).temp != m {
 				continue
 			}
 
 			l := e.exits_from_loc_nsew(0, where)
 			for _, exit := range l {
 				dest := exit.destination
-				if loc_depth(dest) != LOC_province {
+				if e.loc_depth(dest) != LOC_province {
 					continue
 				}
-				if !e.province_gate_here(dest) && e.globals.bx[dest].temp == 0 {
-					e.globals.bx[dest].temp = m + 1
+				if e.province_gate_here(dest) == 0 && e.globals.bx.get(dest).temp == 0 {
+					e.globals.bx.get(dest).temp = m + 1
 					setOne = true
 				}
 			}
@@ -424,21 +424,21 @@ func (e *Engine) fix_gates() {
 	}
 
 	// Copy temp to dist_from_gate
-	for where := kind_first(T_loc); where != 0; where = kind_next(where) {
-		if loc_depth(where) != LOC_province {
+	for where := e.kind_first(T_loc); where != 0; where = e.kind_next(where) {
+		if e.loc_depth(where) != LOC_province {
 			continue
 		}
 		if !e.in_hades(where) && !e.in_clouds(where) && !e.in_faery(where) {
 			continue
 		}
-		p_loc(where).dist_from_gate = schar(e.globals.bx[where].temp)
+		e.p_loc(where).dist_from_gate = schar(e.globals.bx.get(where).temp)
 	}
 }
 
 // v_fix sets HTML passwords for all players.
 // Port of C v_fix().
 func (e *Engine) v_fix(c *command) int {
-	for pl := kind_first(T_player); pl != 0; pl = kind_next(pl) {
+	for pl := e.kind_first(T_player); pl != 0; pl = e.kind_next(pl) {
 		e.set_html_pass(pl)
 	}
 	return TRUE
@@ -455,87 +455,25 @@ func (e *Engine) v_plugh(c *command) int {
 func (e *Engine) out(who int, format string, args ...any)  {}
 func (e *Engine) wout(who int, format string, args ...any) {}
 
-func (e *Engine) box_name(n int) string {
-	return e.getName(n)
-}
-
-func (e *Engine) box_code(n int) string {
-	return int_to_code(n)
-}
-
-func (e *Engine) valid_box(n int) bool {
-	return valid_box(n)
-}
-
 // Note: Engine.Kind() is defined in glob.go
 
-func (e *Engine) gen_item(who, item, qty int) {
-	inv := e.globals.inventories[who]
-	for i := range inv {
-		if inv[i].item == item {
-			inv[i].qty += qty
-			return
-		}
-	}
-	if e.globals.inventories == nil {
-		e.globals.inventories = make(map[int][]item_ent)
-	}
-	e.globals.inventories[who] = append(e.globals.inventories[who], item_ent{item: item, qty: qty})
-}
-
-func (e *Engine) consume_item(who, item, qty int) int {
-	inv := e.globals.inventories[who]
-	for i := range inv {
-		if inv[i].item == item {
-			if inv[i].qty >= qty {
-				inv[i].qty -= qty
-				return qty
-			}
-			consumed := inv[i].qty
-			inv[i].qty = 0
-			return consumed
-		}
-	}
-	return 0
-}
-
 func (e *Engine) move_stack(who, where int) {
 	// Move character and stack to location
-	set_where(who, where)
+	e.set_where(who, where)
 }
 
-func (e *Engine) show_loc(who, where int)                    {}
 func (e *Engine) show_char_inventory(who, num int)           {}
 func (e *Engine) show_carry_capacity(who, num int)           {}
 func (e *Engine) show_item_skills(who, num int)              {}
-func (e *Engine) learn_skill(who, sk int)                    {}
 func (e *Engine) list_skills(who, num int)                   {}
 func (e *Engine) list_partial_skills(who, num int)           {}
 func (e *Engine) los_province_distance(from, to int) int     { return 0 }
-func (e *Engine) kill_char(who, inherit int)                 {}
-func (e *Engine) check_char_here(who, target int) bool       { return true }
-func (e *Engine) take_prisoner(who, target int)              {}
 func (e *Engine) seed_initial_locations()                    {}
-// Note: Engine.getCharSkills() is defined in load.go
-func (e *Engine) deliver_lore(who, num int)                  {}
 func (e *Engine) location_trades()                           {}
 func (e *Engine) seed_city_trade(where int)                  {}
 func (e *Engine) loc_trade_sup(where int, flag bool)         {}
-func (e *Engine) times_paid(pl int) bool                     { return p_player(pl).times_paid != 0 }
-func (e *Engine) has_skill(who, skill int) bool              { return false }
-func (e *Engine) queue_lore(who, num int, anyway bool)       {}
-func (e *Engine) clear_temps(k int)                          {}
-func (e *Engine) in_hades(where int) bool                    { return false }
-func (e *Engine) in_clouds(where int) bool                   { return false }
-func (e *Engine) in_faery(where int) bool                    { return false }
-func (e *Engine) province_gate_here(where int) bool          { return false }
-func (e *Engine) exits_from_loc_nsew(who, where int) []*exit_view { return nil }
 func (e *Engine) set_html_pass(pl int)                       {}
 
-func (e *Engine) p_player(n int) *entity_player {
-	return p_player(n)
-}
-
 // Engine.globals extension for immediate mode
 func init() {
 	// Note: immedSeeAll is added to Engine.globals in engine.go
@@ -21,18 +21,18 @@ package taygete
 // has_item returns the quantity of item held by who.
 // Returns 0 if who doesn't have any of the item.
 // Ported from src/u.c lines 1677-1698.
-func has_item(who, item int) int {
-	if !valid_box(who) {
+func (e *Engine) has_item(who, item int) int {
+	if !e.valid_box(who) {
 		return 0
 	}
-	if !valid_box(item) {
+	if !e.valid_box(item) {
 		return 0
 	}
 
-	inv := teg.globals.inventories[who]
-	for _, e := range inv {
-		if e.item == item {
-			return e.qty
+	inv := e.globals.inventories[who]
+	for _, it := range inv {
+		if it.item == item {
+			return it.qty
 		}
 	}
 	return 0
@@ -41,27 +41,28 @@ func has_item(who, item int) int {
 // add_item adds qty of item to who's inventory.
 // Also handles lore delivery for new items.
 // Ported from src/u.c lines 1702-1742.
-func add_item(who, item, qty int) {
-	if !valid_box(who) {
+func (e *Engine) add_item(who, item, qty int) {
+	if !e.valid_box(who) {
 		panic("add_item: invalid who")
 	}
-	if !valid_box(item) {
+	if !e.valid_box(item) {
 		panic("add_item: invalid item")
 	}
 	if qty < 0 {
 		panic("add_item: qty < 0")
 	}
 
-	lore := item_lore(item)
-	if lore != 0 && kind(who) == T_char && !test_known(who, item) {
-		queue_lore(who, item, false)
+	lore := e.item_lore(item)
+	if lore != 0 && e.kind(who) == T_char && !e.test_known(who, item) {
+		e.queue_lore(who, item, false)
 	}
 
-	if teg.globals.inventories == nil {
-		teg.globals.inventories = make(map[int][]item_ent)
+	if e.globals.inventories == nil {
+		e.globals.inventories = make(map[int][]item_ent)
 	}
 
-	inv := teg.globals.inventories[who]
+	e.markDirty(who)
+	inv := e.globals.inventories[who]
 	for i := range inv {
 		if inv[i].item == item {
 			inv[i].qty += qty
@@ -70,31 +71,32 @@ func add_item(who, item, qty int) {
 		}
 	}
 
-	teg.globals.inventories[who] = append(teg.globals.inventories[who], item_ent{item: item, qty: qty})
+	e.globals.inventories[who] = append(e.globals.inventories[who], item_ent{item: item, qty: qty})
 	investigate_possible_trade(who, item, 0)
 }
 
 // sub_item subtracts qty of item from who's inventory.
 // Returns true if successful, false if who doesn't have enough.
 // Ported from src/u.c lines 498-518.
-func sub_item(who, item, qty int) bool {
-	if !valid_box(who) {
+func (e *Engine) sub_item(who, item, qty int) bool {
+	if !e.valid_box(who) {
 		return false
 	}
-	if !valid_box(item) {
+	if !e.valid_box(item) {
 		return false
 	}
 	if qty < 0 {
 		return false
 	}
 
-	inv := teg.globals.inventories[who]
+	inv := e.globals.inventories[who]
 	for i := range inv {
 		if inv[i].item == item {
 			if inv[i].qty < qty {
 				return false
 			}
 			inv[i].qty -= qty
+			e.markDirty(who)
 			return true
 		}
 	}
@@ -104,51 +106,51 @@ func sub_item(who, item, qty int) bool {
 // gen_item generates qty of a non-unique item for who.
 // Panics if item is unique.
 // Ported from src/u.c lines 1745-1751.
-func gen_item(who, item, qty int) {
-	if item_unique(item) != 0 {
+func (e *Engine) gen_item(who, item, qty int) {
+	if e.item_unique(item) != 0 {
 		panic("gen_item: item is unique")
 	}
-	add_item(who, item, qty)
+	e.add_item(who, item, qty)
 }
 
 // consume_item consumes qty of a non-unique item from who.
 // Panics if item is unique.
 // Returns true if successful, false if who doesn't have enough.
 // Ported from src/u.c lines 1754-1760.
-func consume_item(who, item, qty int) bool {
-	if item_unique(item) != 0 {
+func (e *Engine) consume_item(who, item, qty int) bool {
+	if e.item_unique(item) != 0 {
 		panic("consume_item: item is unique")
 	}
-	return sub_item(who, item, qty)
+	return e.sub_item(who, item, qty)
 }
 
 // move_item moves qty of item from one unit to another.
 // If to is 0, the items are discarded (via drop_item).
 // Returns true if successful, false if from doesn't have enough.
 // Ported from src/u.c lines 1768-1795.
-func move_item(from, to, item, qty int) bool {
+func (e *Engine) move_item(from, to, item, qty int) bool {
 	if qty <= 0 {
 		return true
 	}
 
 	if to == 0 {
-		return drop_item(from, item, qty)
+		return e.drop_item(from, item, qty)
 	}
 
-	if !sub_item(from, item, qty) {
+	if !e.sub_item(from, item, qty) {
 		return false
 	}
 
-	add_item(to, item, qty)
+	e.add_item(to, item, qty)
 
-	if item_unique(item) != 0 {
+	if e.item_unique(item) != 0 {
 		if qty != 1 {
 			panic("move_item: unique item qty != 1")
 		}
-		p_item(item).who_has = to
+		e.p_item(item).who_has = to
 
-		if subkind(item) == sub_npc_token {
-			move_token(item, from, to)
+		if e.subkind(item) == sub_npc_token {
+			e.move_token(item, from, to)
 		}
 	}
 
@@ -158,22 +160,22 @@ func move_item(from, to, item, qty int) bool {
 // hack_unique_item sets the owner of a unique item without transfer checks.
 // Used for initial setup and special cases.
 // Ported from src/u.c lines 1798-1804.
-func hack_unique_item(item, owner int) {
-	p_item(item).who_has = owner
-	add_item(owner, item, 1)
+func (e *Engine) hack_unique_item(item, owner int) {
+	e.p_item(item).who_has = owner
+	e.add_item(owner, item, 1)
 }
 
 // create_unique_item creates a new unique item and gives it to who.
 // Returns the new item ID, or -1 on failure.
 // Ported from src/u.c lines 1807-1821.
-func create_unique_item(who int, sk schar) int {
-	newItem := new_ent(T_item, sk)
+func (e *Engine) create_unique_item(who int, sk schar) int {
+	newItem := e.new_ent(T_item, sk)
 	if newItem < 0 {
 		return -1
 	}
 
-	p_item(newItem).who_has = who
-	add_item(who, newItem, 1)
+	e.p_item(newItem).who_has = who
+	e.add_item(who, newItem, 1)
 
 	return newItem
 }
@@ -181,11 +183,11 @@ func create_unique_item(who int, sk schar) int {
 // create_unique_item_alloc creates a unique item with a specific pre-allocated ID.
 // Returns the item ID.
 // Ported from src/u.c lines 1824-1834.
-func create_unique_item_alloc(newItem, who int, sk schar) int {
-	alloc_box(newItem, T_item, sk)
+func (e *Engine) create_unique_item_alloc(newItem, who int, sk schar) int {
+	e.alloc_box(newItem, T_item, sk)
 
-	p_item(newItem).who_has = who
-	add_item(who, newItem, 1)
+	e.p_item(newItem).who_has = who
+	e.add_item(who, newItem, 1)
 
 	return newItem
 }
@@ -193,95 +195,95 @@ func create_unique_item_alloc(newItem, who int, sk schar) int {
 // destroy_unique_item destroys a unique item.
 // If the item is a dead body, grants NP to the original lord.
 // Ported from src/u.c lines 1837-1867.
-func destroy_unique_item(who, item int) {
-	if kind(item) != T_item {
+func (e *Engine) destroy_unique_item(who, item int) {
+	if e.kind(item) != T_item {
 		panic("destroy_unique_item: item is not T_item")
 	}
-	if item_unique(item) == 0 {
+	if e.item_unique(item) == 0 {
 		panic("destroy_unique_item: item is not unique")
 	}
 
-	if subkind(item) == sub_dead_body {
-		pl := body_old_lord(item)
+	if e.subkind(item) == sub_dead_body {
+		pl := e.body_old_lord(item)
 
 		if pl == indep_player {
-			pl = char_prev_lord(item)
+			pl = e.char_prev_lord(item)
 		}
 
-		if kind(pl) == T_player {
-			nps := char_np_total(item)
+		if e.kind(pl) == T_player {
+			nps := e.char_np_total(item)
 			out(pl, "%s~%s has passed on.  Gained %d NP%s.",
-				save_name(item), box_code(item),
+				e.save_name(item), e.box_code(item),
 				nps, add_s(nps))
-			add_np(pl, nps)
+			e.add_np(pl, nps)
 		}
 	}
 
-	if !sub_item(who, item, 1) {
+	if !e.sub_item(who, item, 1) {
 		panic("destroy_unique_item: sub_item failed")
 	}
 
-	delete_box(item)
+	e.delete_box(item)
 }
 
 // drop_item drops an item from who's inventory.
 // Non-unique items are simply consumed.
 // Unique items are moved to the province (or nearest land if at sea).
 // Ported from src/u.c lines 1962-1992.
-func drop_item(who, item, qty int) bool {
-	if item_unique(item) == 0 {
-		return consume_item(who, item, qty)
+func (e *Engine) drop_item(who, item, qty int) bool {
+	if e.item_unique(item) == 0 {
+		return e.consume_item(who, item, qty)
 	}
 
-	whoGets := province(who)
+	whoGets := e.province(who)
 
-	if subkind(item) == sub_dead_body {
+	if e.subkind(item) == sub_dead_body {
 		if whoGets == 0 {
-			destroy_unique_item(who, item)
+			e.destroy_unique_item(who, item)
 			return true
 		}
 	}
 
-	if subkind(whoGets) == sub_ocean {
-		whoGets = find_nearest_land(whoGets)
+	if e.subkind(whoGets) == sub_ocean {
+		whoGets = e.find_nearest_land(whoGets)
 	}
 
 	if whoGets == 0 {
-		whoGets = province(who)
+		whoGets = e.province(who)
 	}
 
 	log_write(LOG_CODE, "drop_item: %s from %s to %s",
-		box_name(item), box_name(subloc(who)), box_name(whoGets))
+		e.box_name(item), e.box_name(e.subloc(who)), e.box_name(whoGets))
 
-	return move_item(who, whoGets, item, qty)
+	return e.move_item(who, whoGets, item, qty)
 }
 
 // can_pay returns true if who has at least amount gold.
 // Ported from src/u.c lines 1995-2000.
-func can_pay(who, amount int) bool {
-	return has_item(who, item_gold) >= amount
+func (e *Engine) can_pay(who, amount int) bool {
+	return e.has_item(who, item_gold) >= amount
 }
 
 // charge deducts amount gold from who.
 // Returns true if successful, false if who doesn't have enough.
 // Ported from src/u.c lines 2003-2007.
-func charge(who, amount int) bool {
-	return sub_item(who, item_gold, amount)
+func (e *Engine) charge(who, amount int) bool {
+	return e.sub_item(who, item_gold, amount)
 }
 
 // stack_has_item returns the total quantity of item held by who's stack.
 // Only counts units owned by the same player as who.
 // Ported from src/u.c lines 2010-2027.
 // Note: This replaces the stub in stack.go.
-func stack_has_item(who, item int) int {
-	head := stack_leader(who)
+func (e *Engine) stack_has_item(who, item int) int {
+	head := e.stack_leader(who)
 	sum := 0
 
-	for _, i := range stackMembers(head) {
-		if player(i) != player(who) {
+	for _, i := range e.stackMembers(head) {
+		if e.player(i) != e.player(who) {
 			continue
 		}
-		sum += has_item(i, item)
+		sum += e.has_item(i, item)
 	}
 
 	return sum
@@ -290,12 +292,12 @@ func stack_has_item(who, item int) int {
 // has_use_key returns the first item in who's inventory with the given use key.
 // Returns 0 if no such item is found.
 // Ported from src/u.c lines 2030-2050.
-func has_use_key(who, key int) int {
-	inv := teg.globals.inventories[who]
-	for _, e := range inv {
-		p := rp_item_magic(e.item)
+func (e *Engine) has_use_key(who, key int) int {
+	inv := e.globals.inventories[who]
+	for _, it := range inv {
+		p := e.rp_item_magic(it.item)
 		if p != nil && int(p.use_key) == key {
-			return e.item
+			return it.item
 		}
 	}
 	return 0
@@ -305,14 +307,14 @@ func has_use_key(who, key int) int {
 // Only checks units owned by the same player as who.
 // Returns 0 if no such item is found.
 // Ported from src/u.c lines 2053-2072.
-func stack_has_use_key(who, key int) int {
-	head := stack_leader(who)
+func (e *Engine) stack_has_use_key(who, key int) int {
+	head := e.stack_leader(who)
 
-	for _, i := range stackMembers(head) {
-		if player(i) != player(who) {
+	for _, i := range e.stackMembers(head) {
+		if e.player(i) != e.player(who) {
 			continue
 		}
-		ret := has_use_key(i, key)
+		ret := e.has_use_key(i, key)
 		if ret != 0 {
 			return ret
 		}
@@ -325,36 +327,36 @@ func stack_has_use_key(who, key int) int {
 // First tries who, then borrows from friendly stackmates.
 // Returns true if successful, false if the stack doesn't have enough.
 // Ported from src/u.c lines 2083-2146.
-func stack_sub_item(who, item, qty int) bool {
-	if stack_has_item(who, item) < qty {
+func (e *Engine) stack_sub_item(who, item, qty int) bool {
+	if e.stack_has_item(who, item) < qty {
 		return false
 	}
 
-	n := min(has_item(who, item), qty)
+	n := min(e.has_item(who, item), qty)
 	if n > 0 {
 		qty -= n
-		sub_item(who, item, n)
+		e.sub_item(who, item, n)
 	}
 
 	if qty == 0 {
 		return true
 	}
 
-	head := stack_leader(who)
+	head := e.stack_leader(who)
 
-	for _, i := range stackMembers(head) {
+	for _, i := range e.stackMembers(head) {
 		if qty <= 0 {
 			break
 		}
 
-		if player(i) != player(who) {
+		if e.player(i) != e.player(who) {
 			continue
 		}
 
-		n = min(has_item(i, item), qty)
+		n = min(e.has_item(i, item), qty)
 		if n > 0 {
 			qty -= n
-			sub_item(i, item, n)
+			e.sub_item(i, item, n)
 		}
 	}
 
@@ -368,24 +370,24 @@ func stack_sub_item(who, item, qty int) bool {
 // autocharge charges amount gold from who's stack.
 // Returns true if successful, false if the stack doesn't have enough.
 // Ported from src/u.c lines 2149-2154.
-func autocharge(who, amount int) bool {
-	return stack_sub_item(who, item_gold, amount)
+func (e *Engine) autocharge(who, amount int) bool {
+	return e.stack_sub_item(who, item_gold, amount)
 }
 
 // stackMembers returns all characters stacked under leader (including leader).
 // This is a helper for iterating over stack members in Go style.
-func stackMembers(leader int) []int {
+func (e *Engine) stackMembers(leader int) []int {
 	var result []int
 	result = append(result, leader)
 
-	p := rp_loc_info(leader)
+	p := e.rp_loc_info(leader)
 	if p == nil {
 		return result
 	}
 
 	for _, id := range p.here_list {
-		if kind(id) == T_char && !is_prisoner(id) {
-			result = append(result, stackMembers(id)...)
+		if e.kind(id) == T_char && !e.is_prisoner(id) {
+			result = append(result, e.stackMembers(id)...)
 		}
 	}
 
@@ -394,11 +396,11 @@ func stackMembers(leader int) []int {
 
 // add_np adds noble points to a player.
 // Ported from src/u.c lines 1619-1629.
-func add_np(pl, num int) {
-	if kind(pl) != T_player {
+func (e *Engine) add_np(pl, num int) {
+	if e.kind(pl) != T_player {
 		panic("add_np: not a player")
 	}
-	p := p_player(pl)
+	p := e.p_player(pl)
 	p.noble_points += short(num)
 	p.np_gained += short(num)
 }
@@ -406,11 +408,11 @@ func add_np(pl, num int) {
 // deduct_np deducts noble points from a player.
 // Returns true if successful, false if the player doesn't have enough.
 // Ported from src/u.c lines 1601-1616.
-func deduct_np(pl, num int) bool {
-	if kind(pl) != T_player {
+func (e *Engine) deduct_np(pl, num int) bool {
+	if e.kind(pl) != T_player {
 		panic("deduct_np: not a player")
 	}
-	p := p_player(pl)
+	p := e.p_player(pl)
 	if int(p.noble_points) < num {
 		return false
 	}
@@ -422,22 +424,23 @@ func deduct_np(pl, num int) bool {
 // deduct_aura deducts aura from a character.
 // Returns true if successful, false if the character doesn't have enough aura.
 // Ported from src/u.c lines 1632-1644.
-func deduct_aura(who, amount int) bool {
-	p := rp_magic(who)
+func (e *Engine) deduct_aura(who, amount int) bool {
+	p := e.rp_magic(who)
 	if p == nil || p.cur_aura < amount {
 		return false
 	}
 	p.cur_aura -= amount
+	e.markDirty(who)
 	return true
 }
 
 // charge_aura deducts aura from a character and reports failure.
 // Returns true if successful, false if insufficient aura (with message).
 // Ported from src/u.c lines 1647-1659.
-func charge_aura(who, amount int) bool {
-	if !deduct_aura(who, amount) {
+func (e *Engine) charge_aura(who, amount int) bool {
+	if !e.deduct_aura(who, amount) {
 		wout(who, "%s aura required, current level is %s.",
-			cap(nice_num(amount)), nice_num(char_cur_aura(who)))
+			cap(nice_num(amount)), nice_num(e.char_cur_aura(who)))
 		return false
 	}
 	return true
@@ -446,10 +449,10 @@ func charge_aura(who, amount int) bool {
 // check_aura checks if a character has enough aura (without deducting).
 // Returns true if sufficient, false otherwise (with message).
 // Ported from src/u.c lines 1662-1674.
-func check_aura(who, amount int) bool {
-	if char_cur_aura(who) < amount {
+func (e *Engine) check_aura(who, amount int) bool {
+	if e.char_cur_aura(who) < amount {
 		wout(who, "%s aura required, current level is %s.",
-			cap(nice_num(amount)), nice_num(char_cur_aura(who)))
+			cap(nice_num(amount)), nice_num(e.char_cur_aura(who)))
 		return false
 	}
 	return true
@@ -458,41 +461,23 @@ func check_aura(who, amount int) bool {
 // Stub functions for dependencies not yet implemented.
 // These will be implemented in later sprints.
 
-// queue_lore queues lore delivery for an item.
-// TODO: Implement in later sprint.
-func queue_lore(who, item int, anyway bool) {
-}
-
 // investigate_possible_trade checks if a trade can be made.
 // TODO: Implement in later sprint (trade system).
 func investigate_possible_trade(who, item, oldQty int) {
 }
 
-// move_token handles NPC token movement.
-// TODO: Implement in later sprint (NPC system).
-func move_token(token, from, to int) {
-}
-
 // char_prev_lord returns the previous lord of a character.
-func char_prev_lord(n int) int {
-	c := rp_char(n)
+func (e *Engine) char_prev_lord(n int) int {
+	c := e.rp_char(n)
 	if c == nil {
 		return 0
 	}
 	return c.prev_lord
 }
 
-// save_name returns the saved name for a dead body.
-// Note: save_name is stored as *char (legacy C type), currently returns empty.
-// TODO: Implement proper string storage for save_name when needed.
-func save_name(n int) string {
-	return ""
-}
-
-// char_np_total returns the total NP cost for a character.
-// TODO: Implement properly (uses skill system).
-func char_np_total(who int) int {
-	return 1
+// save_name returns the noble's name saved when a dead body was made.
+func (e *Engine) save_name(n int) string {
+	return e.globals.savedNames[n]
 }
 
 // add_s returns "s" for plural or "" for singular.
@@ -35,7 +35,7 @@ func setupInventoryTest() {
 	}
 
 	for i := 0; i < MAX_BOXES; i++ {
-		teg.globals.bx[i] = nil
+		teg.deleteBox(i)
 	}
 	for i := range teg.globals.box_head {
 		teg.globals.box_head[i] = 0
@@ -44,29 +44,29 @@ func setupInventoryTest() {
 		teg.globals.sub_head[i] = 0
 	}
 
-	alloc_box(item_gold, T_item, 0)
-	alloc_box(item_iron, T_item, 0)
+	teg.alloc_box(item_gold, T_item, 0)
+	teg.alloc_box(item_iron, T_item, 0)
 }
 
 func TestHasItem(t *testing.T) {
 	setupInventoryTest()
 
 	charID := 1000
-	alloc_box(charID, T_char, 0)
+	teg.alloc_box(charID, T_char, 0)
 
 	itemID := item_gold
 
-	if got := has_item(charID, itemID); got != 0 {
+	if got := teg.has_item(charID, itemID); got != 0 {
 		t.Errorf("has_item empty = %d, want 0", got)
 	}
 
 	teg.globals.inventories[charID] = []item_ent{{item: itemID, qty: 100}}
 
-	if got := has_item(charID, itemID); got != 100 {
+	if got := teg.has_item(charID, itemID); got != 100 {
 		t.Errorf("has_item with gold = %d, want 100", got)
 	}
 
-	if got := has_item(charID, item_iron); got != 0 {
+	if got := teg.has_item(charID, item_iron); got != 0 {
 		t.Errorf("has_item no iron = %d, want 0", got)
 	}
 }
@@ -75,20 +75,20 @@ func TestSubItem(t *testing.T) {
 	setupInventoryTest()
 
 	charID := 1000
-	alloc_box(charID, T_char, 0)
+	teg.alloc_box(charID, T_char, 0)
 	teg.globals.inventories[charID] = []item_ent{{item: item_gold, qty: 100}}
 
-	if !sub_item(charID, item_gold, 30) {
+	if !teg.sub_item(charID, item_gold, 30) {
 		t.Error("sub_item should succeed")
 	}
-	if got := has_item(charID, item_gold); got != 70 {
+	if got := teg.has_item(charID, item_gold); got != 70 {
 		t.Errorf("after sub_item = %d, want 70", got)
 	}
 
-	if sub_item(charID, item_gold, 100) {
+	if teg.sub_item(charID, item_gold, 100) {
 		t.Error("sub_item should fail (insufficient)")
 	}
-	if got := has_item(charID, item_gold); got != 70 {
+	if got := teg.has_item(charID, item_gold); got != 70 {
 		t.Errorf("after failed sub_item = %d, want 70 (unchanged)", got)
 	}
 }
@@ -97,20 +97,20 @@ func TestAddItem(t *testing.T) {
 	setupInventoryTest()
 
 	charID := 1000
-	alloc_box(charID, T_char, 0)
+	teg.alloc_box(charID, T_char, 0)
 
-	add_item(charID, item_gold, 50)
-	if got := has_item(charID, item_gold); got != 50 {
+	teg.add_item(charID, item_gold, 50)
+	if got := teg.has_item(charID, item_gold); got != 50 {
 		t.Errorf("add_item first = %d, want 50", got)
 	}
 
-	add_item(charID, item_gold, 25)
-	if got := has_item(charID, item_gold); got != 75 {
+	teg.add_item(charID, item_gold, 25)
+	if got := teg.has_item(charID, item_gold); got != 75 {
 		t.Errorf("add_item second = %d, want 75", got)
 	}
 
-	add_item(charID, item_iron, 10)
-	if got := has_item(charID, item_iron); got != 10 {
+	teg.add_item(charID, item_iron, 10)
+	if got := teg.has_item(charID, item_iron); got != 10 {
 		t.Errorf("add_item different item = %d, want 10", got)
 	}
 }
@@ -119,10 +119,10 @@ func TestGenItem(t *testing.T) {
 	setupInventoryTest()
 
 	charID := 1000
-	alloc_box(charID, T_char, 0)
+	teg.alloc_box(charID, T_char, 0)
 
-	gen_item(charID, item_gold, 100)
-	if got := has_item(charID, item_gold); got != 100 {
+	teg.gen_item(charID, item_gold, 100)
+	if got := teg.has_item(charID, item_gold); got != 100 {
 		t.Errorf("gen_item = %d, want 100", got)
 	}
 }
@@ -131,13 +131,13 @@ func TestConsumeItem(t *testing.T) {
 	setupInventoryTest()
 
 	charID := 1000
-	alloc_box(charID, T_char, 0)
+	teg.alloc_box(charID, T_char, 0)
 	teg.globals.inventories[charID] = []item_ent{{item: item_gold, qty: 100}}
 
-	if !consume_item(charID, item_gold, 50) {
+	if !teg.consume_item(charID, item_gold, 50) {
 		t.Error("consume_item should succeed")
 	}
-	if got := has_item(charID, item_gold); got != 50 {
+	if got := teg.has_item(charID, item_gold); got != 50 {
 		t.Errorf("after consume_item = %d, want 50", got)
 	}
 }
@@ -147,21 +147,21 @@ func TestMoveItem(t *testing.T) {
 
 	char1 := 1000
 	char2 := 1001
-	alloc_box(char1, T_char, 0)
-	alloc_box(char2, T_char, 0)
+	teg.alloc_box(char1, T_char, 0)
+	teg.alloc_box(char2, T_char, 0)
 	teg.globals.inventories[char1] = []item_ent{{item: item_gold, qty: 100}}
 
-	if !move_item(char1, char2, item_gold, 40) {
+	if !teg.move_item(char1, char2, item_gold, 40) {
 		t.Error("move_item should succeed")
 	}
-	if got := has_item(char1, item_gold); got != 60 {
+	if got := teg.has_item(char1, item_gold); got != 60 {
 		t.Errorf("char1 after move = %d, want 60", got)
 	}
-	if got := has_item(char2, item_gold); got != 40 {
+	if got := teg.has_item(char2, item_gold); got != 40 {
 		t.Errorf("char2 after move = %d, want 40", got)
 	}
 
-	if move_item(char1, char2, item_gold, 100) {
+	if teg.move_item(char1, char2, item_gold, 100) {
 		t.Error("move_item should fail (insufficient)")
 	}
 }
@@ -170,20 +170,20 @@ func TestCanPayAndCharge(t *testing.T) {
 	setupInventoryTest()
 
 	charID := 1000
-	alloc_box(charID, T_char, 0)
+	teg.alloc_box(charID, T_char, 0)
 	teg.globals.inventories[charID] = []item_ent{{item: item_gold, qty: 100}}
 
-	if !can_pay(charID, 50) {
+	if !teg.can_pay(charID, 50) {
 		t.Error("can_pay(50) should be true")
 	}
-	if can_pay(charID, 150) {
+	if teg.can_pay(charID, 150) {
 		t.Error("can_pay(150) should be false")
 	}
 
-	if !charge(charID, 30) {
+	if !teg.charge(charID, 30) {
 		t.Error("charge(30) should succeed")
 	}
-	if got := has_item(charID, item_gold); got != 70 {
+	if got := teg.has_item(charID, item_gold); got != 70 {
 		t.Errorf("after charge = %d, want 70", got)
 	}
 }
@@ -196,25 +196,25 @@ func TestStackHasItem(t *testing.T) {
 	playerID := 100
 	locID := 2000
 
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(locID, T_loc, sub_plain)
-	alloc_box(leader, T_char, 0)
-	alloc_box(follower, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(locID, T_loc, sub_plain)
+	teg.alloc_box(leader, T_char, 0)
+	teg.alloc_box(follower, T_char, 0)
 
-	p_char(leader).unit_lord = playerID
-	p_char(follower).unit_lord = playerID
+	teg.p_char(leader).unit_lord = playerID
+	teg.p_char(follower).unit_lord = playerID
 
-	set_where(leader, locID)
-	set_where(follower, leader)
+	teg.set_where(leader, locID)
+	teg.set_where(follower, leader)
 
 	teg.globals.inventories[leader] = []item_ent{{item: item_gold, qty: 100}}
 	teg.globals.inventories[follower] = []item_ent{{item: item_gold, qty: 50}}
 
-	if got := stack_has_item(leader, item_gold); got != 150 {
+	if got := teg.stack_has_item(leader, item_gold); got != 150 {
 		t.Errorf("stack_has_item = %d, want 150", got)
 	}
 
-	if got := stack_has_item(follower, item_gold); got != 150 {
+	if got := teg.stack_has_item(follower, item_gold); got != 150 {
 		t.Errorf("stack_has_item from follower = %d, want 150", got)
 	}
 }
@@ -227,26 +227,26 @@ func TestStackSubItem(t *testing.T) {
 	playerID := 100
 	locID := 2000
 
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(locID, T_loc, sub_plain)
-	alloc_box(leader, T_char, 0)
-	alloc_box(follower, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(locID, T_loc, sub_plain)
+	teg.alloc_box(leader, T_char, 0)
+	teg.alloc_box(follower, T_char, 0)
 
-	p_char(leader).unit_lord = playerID
-	p_char(follower).unit_lord = playerID
+	teg.p_char(leader).unit_lord = playerID
+	teg.p_char(follower).unit_lord = playerID
 
-	set_where(leader, locID)
-	set_where(follower, leader)
+	teg.set_where(leader, locID)
+	teg.set_where(follower, leader)
 
 	teg.globals.inventories[leader] = []item_ent{{item: item_gold, qty: 100}}
 	teg.globals.inventories[follower] = []item_ent{{item: item_gold, qty: 50}}
 
-	if !stack_sub_item(leader, item_gold, 120) {
+	if !teg.stack_sub_item(leader, item_gold, 120) {
 		t.Error("stack_sub_item should succeed with borrowing")
 	}
 
-	leaderGold := has_item(leader, item_gold)
-	followerGold := has_item(follower, item_gold)
+	leaderGold := teg.has_item(leader, item_gold)
+	followerGold := teg.has_item(follower, item_gold)
 	if leaderGold+followerGold != 30 {
 		t.Errorf("total after stack_sub_item = %d, want 30", leaderGold+followerGold)
 	}
@@ -260,25 +260,25 @@ func TestAutocharge(t *testing.T) {
 	playerID := 100
 	locID := 2000
 
-	alloc_box(playerID, T_player, sub_pl_regular)
-	alloc_box(locID, T_loc, sub_plain)
-	alloc_box(leader, T_char, 0)
-	alloc_box(follower, T_char, 0)
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.alloc_box(locID, T_loc, sub_plain)
+	teg.alloc_box(leader, T_char, 0)
+	teg.alloc_box(follower, T_char, 0)
 
-	p_char(leader).unit_lord = playerID
-	p_char(follower).unit_lord = playerID
+	teg.p_char(leader).unit_lord = playerID
+	teg.p_char(follower).unit_lord = playerID
 
-	set_where(leader, locID)
-	set_where(follower, leader)
+	teg.set_where(leader, locID)
+	teg.set_where(follower, leader)
 
 	teg.globals.inventories[leader] = []item_ent{{item: item_gold, qty: 50}}
 	teg.globals.inventories[follower] = []item_ent{{item: item_gold, qty: 50}}
 
-	if !autocharge(leader, 75) {
+	if !teg.autocharge(leader, 75) {
 		t.Error("autocharge should succeed with borrowing")
 	}
 
-	total := has_item(leader, item_gold) + has_item(follower, item_gold)
+	total := teg.has_item(leader, item_gold) + teg.has_item(follower, item_gold)
 	if total != 25 {
 		t.Errorf("total after autocharge = %d, want 25", total)
 	}
@@ -290,18 +290,18 @@ func TestHasUseKey(t *testing.T) {
 	charID := 1000
 	artifactID := 5000
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(artifactID, T_item, sub_artifact)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(artifactID, T_item, sub_artifact)
 
-	p_item_magic(artifactID).use_key = use_heal_potion
+	teg.p_item_magic(artifactID).use_key = use_heal_potion
 
 	teg.globals.inventories[charID] = []item_ent{{item: artifactID, qty: 1}}
 
-	if got := has_use_key(charID, use_heal_potion); got != artifactID {
+	if got := teg.has_use_key(charID, use_heal_potion); got != artifactID {
 		t.Errorf("has_use_key = %d, want %d", got, artifactID)
 	}
 
-	if got := has_use_key(charID, use_death_potion); got != 0 {
+	if got := teg.has_use_key(charID, use_death_potion); got != 0 {
 		t.Errorf("has_use_key wrong key = %d, want 0", got)
 	}
 }
@@ -310,28 +310,28 @@ func TestAddNpDeductNp(t *testing.T) {
 	setupInventoryTest()
 
 	playerID := 100
-	alloc_box(playerID, T_player, sub_pl_regular)
-	p_player(playerID).noble_points = 10
+	teg.alloc_box(playerID, T_player, sub_pl_regular)
+	teg.p_player(playerID).noble_points = 10
 
-	add_np(playerID, 5)
-	if got := p_player(playerID).noble_points; got != 15 {
+	teg.add_np(playerID, 5)
+	if got := teg.p_player(playerID).noble_points; got != 15 {
 		t.Errorf("after add_np = %d, want 15", got)
 	}
-	if got := p_player(playerID).np_gained; got != 5 {
+	if got := teg.p_player(playerID).np_gained; got != 5 {
 		t.Errorf("np_gained = %d, want 5", got)
 	}
 
-	if !deduct_np(playerID, 3) {
+	if !teg.deduct_np(playerID, 3) {
 		t.Error("deduct_np should succeed")
 	}
-	if got := p_player(playerID).noble_points; got != 12 {
+	if got := teg.p_player(playerID).noble_points; got != 12 {
 		t.Errorf("after deduct_np = %d, want 12", got)
 	}
-	if got := p_player(playerID).np_spent; got != 3 {
+	if got := teg.p_player(playerID).np_spent; got != 3 {
 		t.Errorf("np_spent = %d, want 3", got)
 	}
 
-	if deduct_np(playerID, 100) {
+	if teg.deduct_np(playerID, 100) {
 		t.Error("deduct_np should fail (insufficient)")
 	}
 }
@@ -340,22 +340,22 @@ func TestCreateUniqueItem(t *testing.T) {
 	setupInventoryTest()
 
 	charID := 1000
-	alloc_box(charID, T_char, 0)
+	teg.alloc_box(charID, T_char, 0)
 
-	itemID := create_unique_item(charID, sub_artifact)
+	itemID := teg.create_unique_item(charID, sub_artifact)
 	if itemID < 0 {
 		t.Fatal("create_unique_item failed")
 	}
 
-	if kind(itemID) != T_item {
-		t.Errorf("created item kind = %d, want T_item", kind(itemID))
+	if teg.kind(itemID) != T_item {
+		t.Errorf("created item kind = %d, want T_item", teg.kind(itemID))
 	}
 
-	if got := item_unique(itemID); got != charID {
+	if got := teg.item_unique(itemID); got != charID {
 		t.Errorf("item_unique = %d, want %d", got, charID)
 	}
 
-	if got := has_item(charID, itemID); got != 1 {
+	if got := teg.has_item(charID, itemID); got != 1 {
 		t.Errorf("has_item after create = %d, want 1", got)
 	}
 }
@@ -366,16 +366,16 @@ func TestHackUniqueItem(t *testing.T) {
 	charID := 1000
 	itemID := 5000
 
-	alloc_box(charID, T_char, 0)
-	alloc_box(itemID, T_item, sub_artifact)
+	teg.alloc_box(charID, T_char, 0)
+	teg.alloc_box(itemID, T_item, sub_artifact)
 
-	hack_unique_item(itemID, charID)
+	teg.hack_unique_item(itemID, charID)
 
-	if got := item_unique(itemID); got != charID {
+	if got := teg.item_unique(itemID); got != charID {
 		t.Errorf("item_unique after hack = %d, want %d", got, charID)
 	}
 
-	if got := has_item(charID, itemID); got != 1 {
+	if got := teg.has_item(charID, itemID); got != 1 {
 		t.Errorf("has_item after hack = %d, want 1", got)
 	}
 }
@@ -411,24 +411,24 @@ func TestDeductAura(t *testing.T) {
 	setupInventoryTest()
 
 	charID := 1000
-	alloc_box(charID, T_char, 0)
-	p_magic(charID).cur_aura = 10
+	teg.alloc_box(charID, T_char, 0)
+	teg.p_magic(charID).cur_aura = 10
 
-	if !deduct_aura(charID, 3) {
+	if !teg.deduct_aura(charID, 3) {
 		t.Error("deduct_aura(3) should succeed")
 	}
-	if got := char_cur_aura(charID); got != 7 {
+	if got := teg.char_cur_aura(charID); got != 7 {
 		t.Errorf("cur_aura after deduct = %d, want 7", got)
 	}
 
-	if !deduct_aura(charID, 7) {
+	if !teg.deduct_aura(charID, 7) {
 		t.Error("deduct_aura(7) should succeed")
 	}
-	if got := char_cur_aura(charID); got != 0 {
+	if got := teg.char_cur_aura(charID); got != 0 {
 		t.Errorf("cur_aura after second deduct = %d, want 0", got)
 	}
 
-	if deduct_aura(charID, 1) {
+	if teg.deduct_aura(charID, 1) {
 		t.Error("deduct_aura(1) should fail (insufficient)")
 	}
 }
@@ -437,9 +437,9 @@ func TestDeductAuraNilMagic(t *testing.T) {
 	setupInventoryTest()
 
 	charID := 1001
-	alloc_box(charID, T_char, 0)
+	teg.alloc_box(charID, T_char, 0)
 
-	if deduct_aura(charID, 1) {
+	if teg.deduct_aura(charID, 1) {
 		t.Error("deduct_aura should fail for nil magic")
 	}
 }
@@ -448,20 +448,20 @@ func TestChargeAura(t *testing.T) {
 	setupInventoryTest()
 
 	charID := 1000
-	alloc_box(charID, T_char, 0)
-	p_magic(charID).cur_aura = 5
+	teg.alloc_box(charID, T_char, 0)
+	teg.p_magic(charID).cur_aura = 5
 
-	if !charge_aura(charID, 3) {
+	if !teg.charge_aura(charID, 3) {
 		t.Error("charge_aura(3) should succeed")
 	}
-	if got := char_cur_aura(charID); got != 2 {
+	if got := teg.char_cur_aura(charID); got != 2 {
 		t.Errorf("cur_aura after charge = %d, want 2", got)
 	}
 
-	if charge_aura(charID, 10) {
+	if teg.charge_aura(charID, 10) {
 		t.Error("charge_aura(10) should fail (insufficient)")
 	}
-	if got := char_cur_aura(charID); got != 2 {
+	if got := teg.char_cur_aura(charID); got != 2 {
 		t.Errorf("cur_aura should be unchanged = %d, want 2", got)
 	}
 }
@@ -470,24 +470,24 @@ func TestCheckAura(t *testing.T) {
 	setupInventoryTest()
 
 	charID := 1000
-	alloc_box(charID, T_char, 0)
-	p_magic(charID).cur_aura = 5
+	teg.alloc_box(charID, T_char, 0)
+	teg.p_magic(charID).cur_aura = 5
 
-	if !check_aura(charID, 5) {
+	if !teg.check_aura(charID, 5) {
 		t.Error("check_aura(5) should succeed")
 	}
-	if got := char_cur_aura(charID); got != 5 {
+	if got := teg.char_cur_aura(charID); got != 5 {
 		t.Errorf("cur_aura should be unchanged after check = %d, want 5", got)
 	}
 
-	if !check_aura(charID, 3) {
+	if !teg.check_aura(charID, 3) {
 		t.Error("check_aura(3) should succeed")
 	}
 
-	if check_aura(charID, 10) {
+	if teg.check_aura(charID, 10) {
 		t.Error("check_aura(10) should fail (insufficient)")
 	}
-	if got := char_cur_aura(charID); got != 5 {
+	if got := teg.char_cur_aura(charID); got != 5 {
 		t.Errorf("cur_aura should be unchanged = %d, want 5", got)
 	}
 }
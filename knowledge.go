@@ -50,34 +50,34 @@ func clear_know_rec(kr map[int]bool) {
 
 // test_known checks if entity i is known to the player owning who.
 // Ported from src/u.c test_known().
-func test_known(who, i int) bool {
+func (e *Engine) test_known(who, i int) bool {
 	if who == 0 {
 		return false
 	}
-	if !valid_box(who) || !valid_box(i) {
+	if !e.valid_box(who) || !e.valid_box(i) {
 		return false
 	}
 
-	pl := player(who)
+	pl := e.player(who)
 	if pl == 0 {
 		return false
 	}
 
-	known := teg.getPlayerKnowledge(pl)
+	known := e.getPlayerKnowledge(pl)
 	return test_bit(known, i)
 }
 
 // set_known marks entity i as known to the player owning who.
 // Ported from src/u.c set_known().
-func set_known(who, i int) {
-	if !valid_box(who) || !valid_box(i) {
+func (e *Engine) set_known(who, i int) {
+	if !e.valid_box(who) || !e.valid_box(i) {
 		return
 	}
 
-	pl := player(who)
-	if !valid_box(pl) {
+	pl := e.player(who)
+	if !e.valid_box(pl) {
 		return
 	}
 
-	teg.setPlayerKnowledge(pl, i)
+	e.setPlayerKnowledge(pl, i)
 }
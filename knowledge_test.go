@@ -104,14 +104,14 @@ func TestClearKnowRec(t *testing.T) {
 
 func TestSetKnownAndTestKnown(t *testing.T) {
 	setupTestPlayer := func(playerID, charID int) func() {
-		teg.globals.bx[playerID] = &box{kind: T_player}
-		teg.globals.bx[playerID].x_player = &entity_player{}
-		teg.globals.bx[charID] = &box{kind: T_char}
-		teg.globals.bx[charID].x_char = &entity_char{unit_lord: playerID}
+		teg.setBox(playerID, &box{kind: T_player})
+		teg.globals.bx.get(playerID).x_player = &entity_player{}
+		teg.setBox(charID, &box{kind: T_char})
+		teg.globals.bx.get(charID).x_char = &entity_char{unit_lord: playerID}
 
 		return func() {
-			teg.globals.bx[playerID] = nil
-			teg.globals.bx[charID] = nil
+			teg.deleteBox(playerID)
+			teg.deleteBox(charID)
 			if teg.globals.playerKnowledge != nil {
 				delete(teg.globals.playerKnowledge, playerID)
 			}
@@ -122,7 +122,7 @@ func TestSetKnownAndTestKnown(t *testing.T) {
 		cleanup := setupTestPlayer(1001, 2001)
 		defer cleanup()
 
-		if test_known(2001, 3001) {
+		if teg.test_known(2001, 3001) {
 			t.Error("expected false for unknown entity")
 		}
 	})
@@ -131,11 +131,11 @@ func TestSetKnownAndTestKnown(t *testing.T) {
 		cleanup := setupTestPlayer(1001, 2001)
 		defer cleanup()
 
-		teg.globals.bx[3001] = &box{kind: T_loc}
-		defer func() { teg.globals.bx[3001] = nil }()
+		teg.setBox(3001, &box{kind: T_loc})
+		defer func() { teg.deleteBox(3001) }()
 
-		set_known(2001, 3001)
-		if !test_known(2001, 3001) {
+		teg.set_known(2001, 3001)
+		if !teg.test_known(2001, 3001) {
 			t.Error("expected true after set_known")
 		}
 	})
@@ -144,64 +144,64 @@ func TestSetKnownAndTestKnown(t *testing.T) {
 		cleanup := setupTestPlayer(1001, 2001)
 		defer cleanup()
 
-		teg.globals.bx[3001] = &box{kind: T_loc}
-		defer func() { teg.globals.bx[3001] = nil }()
+		teg.setBox(3001, &box{kind: T_loc})
+		defer func() { teg.deleteBox(3001) }()
 
-		set_known(2001, 3001)
-		set_known(2001, 3001)
-		set_known(2001, 3001)
-		if !test_known(2001, 3001) {
+		teg.set_known(2001, 3001)
+		teg.set_known(2001, 3001)
+		teg.set_known(2001, 3001)
+		if !teg.test_known(2001, 3001) {
 			t.Error("expected true after multiple set_known calls")
 		}
 	})
 
 	t.Run("who=0 returns false", func(t *testing.T) {
-		if test_known(0, 100) {
+		if teg.test_known(0, 100) {
 			t.Error("expected false for who=0")
 		}
 	})
 
 	t.Run("invalid who returns false", func(t *testing.T) {
-		if test_known(99999, 100) {
+		if teg.test_known(99999, 100) {
 			t.Error("expected false for invalid who")
 		}
 	})
 
 	t.Run("set_known ignores invalid who", func(t *testing.T) {
-		set_known(99999, 100)
+		teg.set_known(99999, 100)
 	})
 
 	t.Run("set_known ignores invalid i", func(t *testing.T) {
 		cleanup := setupTestPlayer(1001, 2001)
 		defer cleanup()
 
-		set_known(2001, 99999)
+		teg.set_known(2001, 99999)
 	})
 
 	t.Run("clear knowledge", func(t *testing.T) {
 		cleanup := setupTestPlayer(1001, 2001)
 		defer cleanup()
 
-		teg.globals.bx[3001] = &box{kind: T_loc}
-		teg.globals.bx[3002] = &box{kind: T_loc}
+		teg.setBox(3001, &box{kind: T_loc})
+		teg.setBox(3002, &box{kind: T_loc})
 		defer func() {
-			teg.globals.bx[3001] = nil
-			teg.globals.bx[3002] = nil
+			teg.deleteBox(3001)
+			teg.deleteBox(3002)
 		}()
 
-		set_known(2001, 3001)
-		set_known(2001, 3002)
+		teg.set_known(2001, 3001)
+		teg.set_known(2001, 3002)
 
-		if !test_known(2001, 3001) || !test_known(2001, 3002) {
+		if !teg.test_known(2001, 3001) || !teg.test_known(2001, 3002) {
 			t.Fatal("setup failed: entities not known")
 		}
 
 		teg.clearPlayerKnowledge(1001)
 
-		if test_known(2001, 3001) {
+		if teg.test_known(2001, 3001) {
 			t.Error("expected false after clear")
 		}
-		if test_known(2001, 3002) {
+		if teg.test_known(2001, 3002) {
 			t.Error("expected false after clear")
 		}
 	})
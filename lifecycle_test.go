@@ -22,40 +22,40 @@ import (
 
 // setupTestCharacter creates a basic test character with the given ID.
 func setupTestCharacter(who int, health schar) {
-	if teg.globals.bx[who] == nil {
-		teg.globals.bx[who] = &box{}
+	if teg.globals.bx.get(who) == nil {
+		teg.setBox(who, &box{})
 	}
-	teg.globals.bx[who].kind = T_char
-	teg.globals.bx[who].skind = 0
-	teg.globals.bx[who].x_char = &entity_char{
+	teg.globals.bx.get(who).kind = T_char
+	teg.globals.bx.get(who).skind = 0
+	teg.globals.bx.get(who).x_char = &entity_char{
 		health:    health,
 		melt_me:   FALSE,
 		prisoner:  FALSE,
 		unit_lord: indep_player,
 	}
-	teg.globals.bx[who].x_loc_info = loc_info{where: 0}
+	teg.globals.bx.get(who).x_loc_info = loc_info{where: 0}
 	teg.setName(who, "Test Character")
 }
 
 // setupTestLocation creates a basic test location.
 func setupTestLocation(loc int, sk schar) {
-	if teg.globals.bx[loc] == nil {
-		teg.globals.bx[loc] = &box{}
+	if teg.globals.bx.get(loc) == nil {
+		teg.setBox(loc, &box{})
 	}
-	teg.globals.bx[loc].kind = T_loc
-	teg.globals.bx[loc].skind = sk
-	teg.globals.bx[loc].x_loc_info = loc_info{where: 0}
+	teg.globals.bx.get(loc).kind = T_loc
+	teg.globals.bx.get(loc).skind = sk
+	teg.globals.bx.get(loc).x_loc_info = loc_info{where: 0}
 	teg.setName(loc, "Test Location")
 }
 
 // setupTestPlayer creates a basic test player.
 func setupTestPlayer(pl int) {
-	if teg.globals.bx[pl] == nil {
-		teg.globals.bx[pl] = &box{}
+	if teg.globals.bx.get(pl) == nil {
+		teg.setBox(pl, &box{})
 	}
-	teg.globals.bx[pl].kind = T_player
-	teg.globals.bx[pl].skind = sub_pl_regular
-	teg.globals.bx[pl].x_player = &entity_player{}
+	teg.globals.bx.get(pl).kind = T_player
+	teg.globals.bx.get(pl).skind = sub_pl_regular
+	teg.globals.bx.get(pl).x_player = &entity_player{}
 	teg.setName(pl, "Test Player")
 }
 
@@ -64,7 +64,7 @@ func TestSurviveFatalWithoutSkill(t *testing.T) {
 	who := 5001
 	setupTestCharacter(who, 100)
 
-	result := survive_fatal(who)
+	result := teg.survive_fatal(who)
 
 	if result {
 		t.Error("survive_fatal should return false when character lacks sk_survive_fatal")
@@ -83,17 +83,17 @@ func TestSurviveFatalWithSkill(t *testing.T) {
 		{skill: sk_survive_fatal, know: SKILL_know},
 	}
 
-	result := survive_fatal(who)
+	result := teg.survive_fatal(who)
 
 	if !result {
 		t.Error("survive_fatal should return true when character has sk_survive_fatal")
 	}
 
-	if p_char(who).health != 100 {
-		t.Errorf("health = %d, want 100", p_char(who).health)
+	if teg.p_char(who).health != 100 {
+		t.Errorf("health = %d, want 100", teg.p_char(who).health)
 	}
 
-	if p_char(who).sick != FALSE {
+	if teg.p_char(who).sick != FALSE {
 		t.Error("sick should be FALSE after survive_fatal")
 	}
 
@@ -111,12 +111,12 @@ func TestCharReclaimLifecycle(t *testing.T) {
 	loc := 10001
 	setupTestLocation(loc, sub_plain)
 	setupTestCharacter(who, 100)
-	set_where(who, loc)
+	teg.set_where(who, loc)
 
-	char_reclaim(who)
+	teg.char_reclaim(who)
 
-	if kind(who) != T_deadchar {
-		t.Errorf("kind = %d, want T_deadchar (%d)", kind(who), T_deadchar)
+	if teg.kind(who) != T_deadchar {
+		t.Errorf("kind = %d, want T_deadchar (%d)", teg.kind(who), T_deadchar)
 	}
 }
 
@@ -125,7 +125,7 @@ func TestStackmateInheritorNoStackmates(t *testing.T) {
 	who := 5004
 	setupTestCharacter(who, 100)
 
-	result := stackmate_inheritor(who)
+	result := teg.stackmate_inheritor(who)
 
 	if result != 0 {
 		t.Errorf("stackmate_inheritor = %d, want 0 (no stackmates)", result)
@@ -140,13 +140,13 @@ func TestStackmateInheritorWithStackmate(t *testing.T) {
 	setupTestCharacter(stackmate, 100)
 
 	// Manually set up the stack relationship without using set_where to avoid duplicate add
-	if teg.globals.bx[who].x_loc_info.here_list == nil {
-		teg.globals.bx[who].x_loc_info.here_list = []int{}
+	if teg.globals.bx.get(who).x_loc_info.here_list == nil {
+		teg.globals.bx.get(who).x_loc_info.here_list = []int{}
 	}
-	teg.globals.bx[who].x_loc_info.here_list = append(teg.globals.bx[who].x_loc_info.here_list, stackmate)
-	teg.globals.bx[stackmate].x_loc_info.where = who
+	teg.globals.bx.get(who).x_loc_info.here_list = append(teg.globals.bx.get(who).x_loc_info.here_list, stackmate)
+	teg.globals.bx.get(stackmate).x_loc_info.where = who
 
-	result := stackmate_inheritor(who)
+	result := teg.stackmate_inheritor(who)
 
 	if result != stackmate {
 		t.Errorf("stackmate_inheritor = %d, want %d", result, stackmate)
@@ -159,12 +159,12 @@ func TestAddCharDamageReducesHealth(t *testing.T) {
 	loc := 10002
 	setupTestLocation(loc, sub_plain)
 	setupTestCharacter(who, 100)
-	set_where(who, loc)
+	teg.set_where(who, loc)
 
-	add_char_damage(who, 30, MATES)
+	teg.add_char_damage(who, 30, MATES)
 
-	if p_char(who).health != 70 {
-		t.Errorf("health = %d, want 70", p_char(who).health)
+	if teg.p_char(who).health != 70 {
+		t.Errorf("health = %d, want 70", teg.p_char(who).health)
 	}
 }
 
@@ -174,14 +174,14 @@ func TestAddCharDamageKillsWhenZero(t *testing.T) {
 	loc := 10003
 	setupTestLocation(loc, sub_plain)
 	setupTestCharacter(who, 50)
-	set_where(who, loc)
+	teg.set_where(who, loc)
 
-	add_char_damage(who, 50, MATES)
+	teg.add_char_damage(who, 50, MATES)
 
 	// Characters owned by indep_player (not NPC subkind) create dead bodies
-	if kind(who) != T_item || subkind(who) != sub_dead_body {
+	if teg.kind(who) != T_item || teg.subkind(who) != sub_dead_body {
 		t.Errorf("kind = %d, subkind = %d, want T_item (%d) with sub_dead_body (%d)",
-			kind(who), subkind(who), T_item, sub_dead_body)
+			teg.kind(who), teg.subkind(who), T_item, sub_dead_body)
 	}
 }
 
@@ -190,10 +190,10 @@ func TestAddCharDamageZeroAmountDoesNothing(t *testing.T) {
 	who := 5009
 	setupTestCharacter(who, 100)
 
-	add_char_damage(who, 0, MATES)
+	teg.add_char_damage(who, 0, MATES)
 
-	if p_char(who).health != 100 {
-		t.Errorf("health = %d, want 100", p_char(who).health)
+	if teg.p_char(who).health != 100 {
+		t.Errorf("health = %d, want 100", teg.p_char(who).health)
 	}
 }
 
@@ -203,16 +203,16 @@ func TestKillCharWithMeltMe(t *testing.T) {
 	loc := 10004
 	setupTestLocation(loc, sub_plain)
 	setupTestCharacter(who, 100)
-	p_char(who).melt_me = TRUE
-	set_where(who, loc)
+	teg.p_char(who).melt_me = TRUE
+	teg.set_where(who, loc)
 
-	kill_char(who, MATES)
+	teg.kill_char(who, MATES)
 
-	if kind(who) != T_deadchar {
-		t.Errorf("kind = %d, want T_deadchar (%d)", kind(who), T_deadchar)
+	if teg.kind(who) != T_deadchar {
+		t.Errorf("kind = %d, want T_deadchar (%d)", teg.kind(who), T_deadchar)
 	}
 
-	if subkind(who) == sub_dead_body {
+	if teg.subkind(who) == sub_dead_body {
 		t.Error("melting characters should not become dead bodies")
 	}
 }
@@ -225,17 +225,17 @@ func TestKillCharCreatesDeadBody(t *testing.T) {
 	setupTestLocation(loc, sub_plain)
 	setupTestPlayer(pl)
 	setupTestCharacter(who, 100)
-	p_char(who).unit_lord = pl
-	set_where(who, loc)
+	teg.p_char(who).unit_lord = pl
+	teg.set_where(who, loc)
 
-	kill_char(who, MATES)
+	teg.kill_char(who, MATES)
 
-	if kind(who) != T_item {
-		t.Errorf("kind = %d, want T_item (%d)", kind(who), T_item)
+	if teg.kind(who) != T_item {
+		t.Errorf("kind = %d, want T_item (%d)", teg.kind(who), T_item)
 	}
 
-	if subkind(who) != sub_dead_body {
-		t.Errorf("subkind = %d, want sub_dead_body (%d)", subkind(who), sub_dead_body)
+	if teg.subkind(who) != sub_dead_body {
+		t.Errorf("subkind = %d, want sub_dead_body (%d)", teg.subkind(who), sub_dead_body)
 	}
 }
 
@@ -248,23 +248,23 @@ func TestPutBackCookie(t *testing.T) {
 	setupTestLocation(home, sub_lair)
 
 	// Create a valid item box for the cookie
-	if teg.globals.bx[cookie] == nil {
-		teg.globals.bx[cookie] = &box{}
+	if teg.globals.bx.get(cookie) == nil {
+		teg.setBox(cookie, &box{})
 	}
-	teg.globals.bx[cookie].kind = T_item
-	teg.globals.bx[cookie].skind = 0
-	teg.globals.bx[cookie].x_item = &entity_item{}
+	teg.globals.bx.get(cookie).kind = T_item
+	teg.globals.bx.get(cookie).skind = 0
+	teg.globals.bx.get(cookie).x_item = &entity_item{}
 
-	teg.globals.bx[who].x_misc = &entity_misc{
+	teg.globals.bx.get(who).x_misc = &entity_misc{
 		npc_home:   home,
 		npc_cookie: cookie,
 	}
 
-	initialQty := has_item(home, cookie)
+	initialQty := teg.has_item(home, cookie)
 
-	put_back_cookie(who)
+	teg.put_back_cookie(who)
 
-	finalQty := has_item(home, cookie)
+	finalQty := teg.has_item(home, cookie)
 	if finalQty != initialQty+1 {
 		t.Errorf("cookie qty = %d, want %d", finalQty, initialQty+1)
 	}
@@ -276,12 +276,12 @@ func TestDeadCharBodyAtSea(t *testing.T) {
 	ocean := 10007
 	setupTestLocation(ocean, sub_ocean)
 	setupTestCharacter(who, 100)
-	set_where(who, ocean)
+	teg.set_where(who, ocean)
 
-	dead_char_body(indep_player, who)
+	teg.dead_char_body(indep_player, who)
 
-	if kind(who) != T_deadchar {
-		t.Errorf("kind = %d, want T_deadchar (body lost at sea becomes deadchar)", kind(who))
+	if teg.kind(who) != T_deadchar {
+		t.Errorf("kind = %d, want T_deadchar (body lost at sea becomes deadchar)", teg.kind(who))
 	}
 }
 
@@ -295,32 +295,32 @@ func TestRestoreDeadBody(t *testing.T) {
 	setupTestLocation(loc, sub_plain)
 	setupTestPlayer(pl)
 	setupTestCharacter(owner, 100)
-	set_where(owner, loc)
-	p_char(owner).unit_lord = pl
+	teg.set_where(owner, loc)
+	teg.p_char(owner).unit_lord = pl
 
-	if teg.globals.bx[who] == nil {
-		teg.globals.bx[who] = &box{}
+	if teg.globals.bx.get(who) == nil {
+		teg.setBox(who, &box{})
 	}
-	teg.globals.bx[who].kind = T_item
-	teg.globals.bx[who].skind = sub_dead_body
-	teg.globals.bx[who].x_item = &entity_item{weight: 10}
-	teg.globals.bx[who].x_char = &entity_char{}
-	teg.globals.bx[who].x_misc = &entity_misc{old_lord: pl}
+	teg.globals.bx.get(who).kind = T_item
+	teg.globals.bx.get(who).skind = sub_dead_body
+	teg.globals.bx.get(who).x_item = &entity_item{weight: 10}
+	teg.globals.bx.get(who).x_char = &entity_char{}
+	teg.globals.bx.get(who).x_misc = &entity_misc{old_lord: pl}
 	teg.setName(who, "dead body")
 	// Use savedNames map for restored name
-	savedNames[who] = "Dead Hero"
+	teg.globals.savedNames[who] = "Dead Hero"
 
-	add_item(owner, who, 1)
-	p_item(who).who_has = owner
+	teg.add_item(owner, who, 1)
+	teg.p_item(who).who_has = owner
 
-	restore_dead_body(owner, who)
+	teg.restore_dead_body(owner, who)
 
-	if kind(who) != T_char {
-		t.Errorf("kind = %d, want T_char (%d)", kind(who), T_char)
+	if teg.kind(who) != T_char {
+		t.Errorf("kind = %d, want T_char (%d)", teg.kind(who), T_char)
 	}
 
-	if p_char(who).health != 100 {
-		t.Errorf("health = %d, want 100", p_char(who).health)
+	if teg.p_char(who).health != 100 {
+		t.Errorf("health = %d, want 100", teg.p_char(who).health)
 	}
 
 	if teg.getName(who) != "Dead Hero" {
@@ -333,23 +333,23 @@ func TestIsNpc(t *testing.T) {
 	who := 5016
 	setupTestCharacter(who, 100)
 
-	if is_npc(who) {
+	if teg.is_npc(who) {
 		t.Error("character with subkind 0 and LOY_unsworn should not be NPC")
 	}
 
-	teg.globals.bx[who].skind = sub_ni
-	if !is_npc(who) {
+	teg.globals.bx.get(who).skind = sub_ni
+	if !teg.is_npc(who) {
 		t.Error("character with non-zero subkind should be NPC")
 	}
 
-	teg.globals.bx[who].skind = 0
-	p_char(who).loy_kind = LOY_npc
-	if !is_npc(who) {
+	teg.globals.bx.get(who).skind = 0
+	teg.p_char(who).loy_kind = LOY_npc
+	if !teg.is_npc(who) {
 		t.Error("character with LOY_npc should be NPC")
 	}
 
-	p_char(who).loy_kind = LOY_summon
-	if !is_npc(who) {
+	teg.p_char(who).loy_kind = LOY_summon
+	if !teg.is_npc(who) {
 		t.Error("character with LOY_summon should be NPC")
 	}
 }
@@ -367,7 +367,7 @@ func TestForgetSkill(t *testing.T) {
 		{skill: sk_survive_fatal, know: SKILL_know},
 	}
 
-	result := forget_skill(who, sk_survive_fatal)
+	result := teg.forget_skill(who, sk_survive_fatal)
 
 	if !result {
 		t.Error("forget_skill should return true when skill is known")
@@ -380,7 +380,7 @@ func TestForgetSkill(t *testing.T) {
 		}
 	}
 
-	result = forget_skill(who, sk_survive_fatal)
+	result = teg.forget_skill(who, sk_survive_fatal)
 	if result {
 		t.Error("forget_skill should return false when skill already forgotten")
 	}
@@ -394,14 +394,14 @@ func TestTakeUnitItemsToStackmate(t *testing.T) {
 	setupTestCharacter(to, 100)
 
 	// Manually set up the stack relationship without using set_where
-	teg.globals.bx[from].x_loc_info.here_list = []int{to}
-	teg.globals.bx[to].x_loc_info.where = from
+	teg.globals.bx.get(from).x_loc_info.here_list = []int{to}
+	teg.globals.bx.get(to).x_loc_info.where = from
 
-	add_item(from, item_gold, 100)
+	teg.add_item(from, item_gold, 100)
 
-	take_unit_items(from, MATES, TAKE_ALL)
+	teg.take_unit_items(from, MATES, TAKE_ALL)
 
-	toGold := has_item(to, item_gold)
+	toGold := teg.has_item(to, item_gold)
 	if toGold == 0 {
 		t.Error("stackmate should have received gold")
 	}
@@ -412,11 +412,11 @@ func TestTakeUnitItemsDiscard(t *testing.T) {
 	from := 5020
 	setupTestCharacter(from, 100)
 
-	add_item(from, item_gold, 100)
+	teg.add_item(from, item_gold, 100)
 
-	take_unit_items(from, 0, TAKE_ALL)
+	teg.take_unit_items(from, 0, TAKE_ALL)
 
-	fromGold := has_item(from, item_gold)
+	fromGold := teg.has_item(from, item_gold)
 	if fromGold != 0 {
 		t.Errorf("from should have 0 gold after discard, got %d", fromGold)
 	}
@@ -432,12 +432,12 @@ func TestLoopStackList(t *testing.T) {
 	setupTestCharacter(follower2, 100)
 
 	// Manually set up the stack relationship without using set_where
-	teg.globals.bx[leader].x_loc_info.here_list = []int{follower1}
-	teg.globals.bx[follower1].x_loc_info.here_list = []int{follower2}
-	teg.globals.bx[follower1].x_loc_info.where = leader
-	teg.globals.bx[follower2].x_loc_info.where = follower1
+	teg.globals.bx.get(leader).x_loc_info.here_list = []int{follower1}
+	teg.globals.bx.get(follower1).x_loc_info.here_list = []int{follower2}
+	teg.globals.bx.get(follower1).x_loc_info.where = leader
+	teg.globals.bx.get(follower2).x_loc_info.where = follower1
 
-	result := loop_stack_list(leader)
+	result := teg.loop_stack_list(leader)
 
 	if len(result) != 3 {
 		t.Errorf("loop_stack_list returned %d members, want 3", len(result))
@@ -458,29 +458,29 @@ func TestChangeBoxKindLifecycle(t *testing.T) {
 	who := 5024
 	setupTestCharacter(who, 100)
 
-	if kind(who) != T_char {
-		t.Errorf("initial kind = %d, want T_char", kind(who))
+	if teg.kind(who) != T_char {
+		t.Errorf("initial kind = %d, want T_char", teg.kind(who))
 	}
 
-	change_box_kind(who, T_deadchar)
+	teg.change_box_kind(who, T_deadchar)
 
-	if kind(who) != T_deadchar {
-		t.Errorf("kind = %d, want T_deadchar", kind(who))
+	if teg.kind(who) != T_deadchar {
+		t.Errorf("kind = %d, want T_deadchar", teg.kind(who))
 	}
 }
 
 // TestChangeBoxSubkindLifecycle tests subkind changing.
 func TestChangeBoxSubkindLifecycle(t *testing.T) {
 	who := 5025
-	if teg.globals.bx[who] == nil {
-		teg.globals.bx[who] = &box{}
+	if teg.globals.bx.get(who) == nil {
+		teg.setBox(who, &box{})
 	}
-	teg.globals.bx[who].kind = T_item
-	teg.globals.bx[who].skind = 0
+	teg.globals.bx.get(who).kind = T_item
+	teg.globals.bx.get(who).skind = 0
 
-	change_box_subkind(who, sub_dead_body)
+	teg.change_box_subkind(who, sub_dead_body)
 
-	if subkind(who) != sub_dead_body {
-		t.Errorf("subkind = %d, want sub_dead_body", subkind(who))
+	if teg.subkind(who) != sub_dead_body {
+		t.Errorf("subkind = %d, want sub_dead_body", teg.subkind(who))
 	}
 }
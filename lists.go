@@ -152,17 +152,6 @@ func (l *List[T]) RemValueUniq(value T) {
 	}
 }
 
-// Scramble performs a Fisher-Yates shuffle on the list.
-func (l *List[T]) Scramble() {
-	n := len(l.values) - 1
-	for i := 0; i < n; i++ {
-		r := rnd(i, n)
-		if r != i {
-			l.values[i], l.values[r] = l.values[r], l.values[i]
-		}
-	}
-}
-
 // SortList sorts a list of ordered values in ascending order.
 // This is a standalone function because Sort requires the cmp.Ordered constraint,
 // which is more restrictive than the comparable constraint used by List[T].
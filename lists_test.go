@@ -332,26 +332,6 @@ func TestList_Insert(t *testing.T) {
 	})
 }
 
-func TestList_Scramble(t *testing.T) {
-	l := NewList(1, 2, 3, 4, 5)
-	original := make([]int, 5)
-	copy(original, l.Values())
-
-	l.Scramble()
-
-	// Check all elements are preserved
-	if l.Len() != 5 {
-		t.Errorf("expected len 5 after scramble, got %d", l.Len())
-	}
-
-	// Check all original values still present
-	for _, v := range original {
-		if l.Lookup(v) == -1 {
-			t.Errorf("value %d missing after scramble", v)
-		}
-	}
-}
-
 func TestSortList(t *testing.T) {
 	t.Run("integers", func(t *testing.T) {
 		l := NewList(3, 1, 4, 1, 5, 9, 2, 6)
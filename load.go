@@ -18,7 +18,11 @@ package taygete
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 // LoadWorld loads the game world from the database into memory.
@@ -38,6 +42,10 @@ func (e *Engine) LoadWorld() error {
 		return fmt.Errorf("load locations: %w", err)
 	}
 
+	if err := e.loadLocLinks(); err != nil {
+		return fmt.Errorf("load loc links: %w", err)
+	}
+
 	// Load characters
 	if err := e.loadCharacters(); err != nil {
 		return fmt.Errorf("load characters: %w", err)
@@ -63,11 +71,31 @@ func (e *Engine) LoadWorld() error {
 		return fmt.Errorf("load item_types: %w", err)
 	}
 
+	// Load magical item attributes
+	if err := e.loadItemMagic(); err != nil {
+		return fmt.Errorf("load item_magic: %w", err)
+	}
+
+	// Load inventories
+	if err := e.loadInventories(); err != nil {
+		return fmt.Errorf("load inventories: %w", err)
+	}
+
 	// Load skills
 	if err := e.loadSkills(); err != nil {
 		return fmt.Errorf("load skills: %w", err)
 	}
 
+	// Load lore sheets
+	if err := e.loadSkillLore(); err != nil {
+		return fmt.Errorf("load skill_lore: %w", err)
+	}
+
+	// Load dead bodies
+	if err := e.loadDeadBodies(); err != nil {
+		return fmt.Errorf("load dead_bodies: %w", err)
+	}
+
 	// Load gates
 	if err := e.loadGates(); err != nil {
 		return fmt.Errorf("load gates: %w", err)
@@ -83,19 +111,461 @@ func (e *Engine) LoadWorld() error {
 		return fmt.Errorf("load ships: %w", err)
 	}
 
+	// Load admit permissions and declared attitudes
+	if err := e.loadAdmits(); err != nil {
+		return fmt.Errorf("load player_admits: %w", err)
+	}
+	if err := e.loadAttitudes(); err != nil {
+		return fmt.Errorf("load attitudes: %w", err)
+	}
+
+	// Load the orders units have loaded or running
+	if err := e.loadCommands(); err != nil {
+		return fmt.Errorf("load commands: %w", err)
+	}
+
+	// Load what players know and their unit lists
+	if err := e.loadPlayerKnowledge(); err != nil {
+		return fmt.Errorf("load player_knowledge: %w", err)
+	}
+	if err := e.loadPlayerUnits(); err != nil {
+		return fmt.Errorf("load player_units: %w", err)
+	}
+
+	// Load entity_subloc and entity_misc, over what the tables above
+	// hold of them
+	if err := e.loadSublocs(); err != nil {
+		return fmt.Errorf("load sublocs: %w", err)
+	}
+	if err := e.loadEntityMisc(); err != nil {
+		return fmt.Errorf("load entity_misc: %w", err)
+	}
+
 	// Load system config
 	if err := e.loadSystemConfig(); err != nil {
 		return fmt.Errorf("load system_config: %w", err)
 	}
 
+	// Remember what was read so SaveWorld writes only the changes
+	e.trackSaved(nil)
+
 	return nil
 }
 
+// loadAdmits loads the players' ADMIT declarations.
+func (e *Engine) loadAdmits() error {
+	rows, err := e.db.Query(`
+		SELECT player_id, targ_id, sense
+		FROM player_admits
+		ORDER BY player_id, targ_id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	admits := make(map[[2]int]*admit)
+
+	for rows.Next() {
+		var pl, targ, sense int
+
+		if err := rows.Scan(&pl, &targ, &sense); err != nil {
+			return fmt.Errorf("scan player_admit: %w", err)
+		}
+
+		b := e.globals.bx.get(pl)
+		if b == nil || b.x_player == nil {
+			continue
+		}
+
+		a := &admit{targ: targ, sense: sense}
+		b.x_player.admits = append(b.x_player.admits, a)
+		admits[[2]int{pl, targ}] = a
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	entRows, err := e.db.Query(`
+		SELECT player_id, targ_id, ent_id
+		FROM player_admit_ents
+		ORDER BY player_id, targ_id, seq
+	`)
+	if err != nil {
+		return err
+	}
+	defer entRows.Close()
+
+	for entRows.Next() {
+		var pl, targ, n int
+
+		if err := entRows.Scan(&pl, &targ, &n); err != nil {
+			return fmt.Errorf("scan player_admit_ent: %w", err)
+		}
+
+		if a := admits[[2]int{pl, targ}]; a != nil {
+			a.l.Append(n)
+		}
+	}
+
+	return entRows.Err()
+}
+
+// loadAttitudes loads declared neutral, hostile and defend lists.
+func (e *Engine) loadAttitudes() error {
+	rows, err := e.db.Query(`
+		SELECT ent_id, target_id, disp
+		FROM attitudes
+		ORDER BY ent_id, target_id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, target, disp int
+
+		if err := rows.Scan(&id, &target, &disp); err != nil {
+			return fmt.Errorf("scan attitude: %w", err)
+		}
+
+		b := e.globals.bx.get(id)
+		if b == nil {
+			continue
+		}
+		if b.x_disp == nil {
+			b.x_disp = &att_ent{}
+		}
+
+		switch disp {
+		case NEUTRAL:
+			b.x_disp.neutral.Append(target)
+		case HOSTILE:
+			b.x_disp.hostile.Append(target)
+		case DEFEND:
+			b.x_disp.defend.Append(target)
+		}
+	}
+
+	return rows.Err()
+}
+
+// loadCommands loads the command state saved by commandRows. The
+// scheduling queues are rebuilt from it when the turn starts.
+func (e *Engine) loadCommands() error {
+	rows, err := e.db.Query(`
+		SELECT who_id, cmd_code, use_skill, use_ent, use_exp, days_executing,
+		       state, status, poll, pri, conditional, inhibit_finish,
+		       wait, second_wait, fuzzy, args_json, parse_json, wait_parse_json, raw_line
+		FROM commands
+		ORDER BY who_id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var who, code, daysExecuting, wait int
+		var useSkill, useEnt, useExp sql.NullInt64
+		var state, status, poll, pri, conditional, inhibitFinish, secondWait, fuzzy int
+		var args, parse, waits, line sql.NullString
+
+		if err := rows.Scan(&who, &code, &useSkill, &useEnt, &useExp, &daysExecuting,
+			&state, &status, &poll, &pri, &conditional, &inhibitFinish,
+			&wait, &secondWait, &fuzzy, &args, &parse, &waits, &line); err != nil {
+			return fmt.Errorf("scan command: %w", err)
+		}
+		if e.globals.bx.get(who) == nil {
+			continue
+		}
+
+		c := e.p_command(who)
+		c.cmd = code
+		c.use_skill = int(useSkill.Int64)
+		c.use_ent = int(useEnt.Int64)
+		c.use_exp = int(useExp.Int64)
+		c.days_executing = daysExecuting
+		c.state = schar(state)
+		c.status = schar(status)
+		c.poll = schar(poll)
+		c.pri = schar(pri)
+		c.conditional = schar(conditional)
+		c.inhibit_finish = schar(inhibitFinish)
+		c.wait = wait
+		c.second_wait = schar(secondWait)
+		c.fuzzy = schar(fuzzy)
+		c.line = line.String
+
+		var a []int
+		if args.Valid {
+			if err := json.Unmarshal([]byte(args.String), &a); err != nil {
+				return fmt.Errorf("command %d: args: %w", who, err)
+			}
+		}
+		a = append(a, make([]int, 8)...)
+		c.a, c.b, c.c, c.d, c.e, c.f, c.g, c.h = a[0], a[1], a[2], a[3], a[4], a[5], a[6], a[7]
+
+		c.parse = nil
+		if parse.Valid {
+			if err := json.Unmarshal([]byte(parse.String), &c.parse); err != nil {
+				return fmt.Errorf("command %d: parse: %w", who, err)
+			}
+		}
+
+		if waits.Valid {
+			var l []waitArgJSON
+			if err := json.Unmarshal([]byte(waits.String), &l); err != nil {
+				return fmt.Errorf("command %d: wait_parse: %w", who, err)
+			}
+			for _, w := range l {
+				e.appendWaitParse(c, &waitArgExt{tag: w.Tag, a1: w.A1, a2: w.A2, flagStr: w.Flag})
+			}
+		}
+	}
+
+	return rows.Err()
+}
+
+// loadPlayerKnowledge loads the entities each player knows of.
+func (e *Engine) loadPlayerKnowledge() error {
+	rows, err := e.db.Query(`SELECT player_id, entity_id FROM player_knowledge ORDER BY player_id, entity_id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pl, id int
+		if err := rows.Scan(&pl, &id); err != nil {
+			return fmt.Errorf("scan player_knowledge: %w", err)
+		}
+		e.setPlayerKnowledge(pl, id)
+	}
+
+	return rows.Err()
+}
+
+// loadPlayerUnits loads each player's units and unformed nobles, in
+// order.
+func (e *Engine) loadPlayerUnits() error {
+	rows, err := e.db.Query(`SELECT player_id, kind, unit_id FROM player_units ORDER BY player_id, kind, seq`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pl, id int
+		var kind string
+		if err := rows.Scan(&pl, &kind, &id); err != nil {
+			return fmt.Errorf("scan player_units: %w", err)
+		}
+		switch kind {
+		case "unit":
+			e.globals.playerUnits[pl] = append(e.globals.playerUnits[pl], id)
+		case "unformed":
+			e.globals.playerUnits[pl+100_000] = append(e.globals.playerUnits[pl+100_000], id)
+		}
+	}
+
+	return rows.Err()
+}
+
+// loadSublocs loads entity_subloc. Its lists replace those built from
+// loc_links.
+func (e *Engine) loadSublocs() error {
+	rows, err := e.db.Query(`
+		SELECT id, opium_econ, defense, loot, damage, galley_ram, shaft_depth, castle_lev,
+		       build_materials, effort_required, effort_given, moving, capacity,
+		       safe, major, prominence, uldim_flag, summer_flag, quest_late, tunnel_level,
+		       link_when, link_open
+		FROM sublocs
+		ORDER BY id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var opiumEcon, defense, loot, damage, galleyRam, shaftDepth, castleLev int
+		var buildMaterials, effortRequired, effortGiven, moving, capacity int
+		var safe, major, prominence, uldimFlag, summerFlag, questLate, tunnelLevel int
+		var linkWhen, linkOpen int
+
+		if err := rows.Scan(&id, &opiumEcon, &defense, &loot, &damage, &galleyRam, &shaftDepth, &castleLev,
+			&buildMaterials, &effortRequired, &effortGiven, &moving, &capacity,
+			&safe, &major, &prominence, &uldimFlag, &summerFlag, &questLate, &tunnelLevel,
+			&linkWhen, &linkOpen); err != nil {
+			return fmt.Errorf("scan subloc: %w", err)
+		}
+		b := e.globals.bx.get(id)
+		if b == nil {
+			continue
+		}
+		if b.x_subloc == nil {
+			b.x_subloc = &entity_subloc{}
+		}
+
+		sl := b.x_subloc
+		sl.opium_econ = opiumEcon
+		sl.defense = defense
+		sl.loot = schar(loot)
+		sl.damage = uchar(damage)
+		sl.galley_ram = schar(galleyRam)
+		sl.shaft_depth = short(shaftDepth)
+		sl.castle_lev = schar(castleLev)
+		sl.build_materials = buildMaterials
+		sl.effort_required = effortRequired
+		sl.effort_given = effortGiven
+		sl.moving = moving
+		sl.capacity = capacity
+		sl.safe = schar(safe)
+		sl.major = schar(major)
+		sl.prominence = schar(prominence)
+		sl.uldim_flag = schar(uldimFlag)
+		sl.summer_flag = schar(summerFlag)
+		sl.quest_late = schar(questLate)
+		sl.tunnel_level = schar(tunnelLevel)
+		sl.link_when = schar(linkWhen)
+		sl.link_open = schar(linkOpen)
+		sl.teaches, sl.near_cities = IList{}, IList{}
+		sl.link_to, sl.link_from, sl.bound_storms = nil, nil, nil
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return e.loadSublocLists()
+}
+
+// loadSublocLists loads the lists of each entity_subloc, in order.
+func (e *Engine) loadSublocLists() error {
+	rows, err := e.db.Query(`SELECT subloc_id, list, value FROM subloc_lists ORDER BY subloc_id, list, seq`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, value int
+		var list string
+		if err := rows.Scan(&id, &list, &value); err != nil {
+			return fmt.Errorf("scan subloc_lists: %w", err)
+		}
+		b := e.globals.bx.get(id)
+		if b == nil || b.x_subloc == nil {
+			continue
+		}
+
+		sl := b.x_subloc
+		switch list {
+		case "teaches":
+			sl.teaches.Append(value)
+		case "near_cities":
+			sl.near_cities.Append(value)
+		case "link_to":
+			sl.link_to = append(sl.link_to, value)
+		case "link_from":
+			sl.link_from = append(sl.link_from, value)
+		case "bound_storms":
+			sl.bound_storms = append(sl.bound_storms, value)
+		}
+	}
+
+	return rows.Err()
+}
+
+// loadEntityMisc loads entity_misc, the original names of dead bodies
+// and what NPCs remember. An entity gets an entity_misc only if it
+// holds something.
+func (e *Engine) loadEntityMisc() error {
+	rows, err := e.db.Query(`
+		SELECT id, npc_created, npc_home, npc_cookie, summoned_by, save_name, old_lord,
+		       only_vuln, garr_castle, bind_storm, storm_str, npc_dir, mine_delay, cmd_allow
+		FROM entity_misc
+		ORDER BY id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var saveName sql.NullString
+		var npcCreated, npcHome, npcCookie, summonedBy, oldLord int
+		var onlyVuln, garrCastle, bindStorm, stormStr, npcDir, mineDelay, cmdAllow int
+
+		if err := rows.Scan(&id, &npcCreated, &npcHome, &npcCookie, &summonedBy, &saveName,
+			&oldLord, &onlyVuln, &garrCastle, &bindStorm, &stormStr,
+			&npcDir, &mineDelay, &cmdAllow); err != nil {
+			return fmt.Errorf("scan entity_misc: %w", err)
+		}
+		b := e.globals.bx.get(id)
+		if b == nil {
+			continue
+		}
+
+		if saveName.Valid && saveName.String != "" {
+			e.globals.savedNames[id] = saveName.String
+		}
+		if b.x_misc == nil && !slices.ContainsFunc([]int{npcCreated, npcHome, npcCookie, summonedBy, oldLord,
+			onlyVuln, garrCastle, bindStorm, stormStr, npcDir, mineDelay, cmdAllow},
+			func(v int) bool { return v != 0 }) {
+			continue
+		}
+		if b.x_misc == nil {
+			b.x_misc = &entity_misc{}
+		}
+
+		// Fields that are not saved, such as storm_move, are kept
+		m := b.x_misc
+		m.npc_created = npcCreated
+		m.npc_home = npcHome
+		m.npc_cookie = npcCookie
+		m.summoned_by = summonedBy
+		m.old_lord = oldLord
+		m.only_vuln = onlyVuln
+		m.garr_castle = garrCastle
+		m.bind_storm = bindStorm
+		m.storm_str = short(stormStr)
+		m.npc_dir = schar(npcDir)
+		m.mine_delay = schar(mineDelay)
+		m.cmd_allow = char(cmdAllow)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	memRows, err := e.db.Query(`SELECT entity_id, known_id FROM npc_memory ORDER BY entity_id, known_id`)
+	if err != nil {
+		return err
+	}
+	defer memRows.Close()
+
+	for memRows.Next() {
+		var id, known int
+		if err := memRows.Scan(&id, &known); err != nil {
+			return fmt.Errorf("scan npc_memory: %w", err)
+		}
+		if e.globals.bx.get(id) == nil {
+			continue
+		}
+		m := e.p_misc(id)
+		m.npc_memory = set_bit(m.npc_memory, known)
+	}
+
+	return memRows.Err()
+}
+
 // clearWorld resets the in-memory world state.
 func (e *Engine) clearWorld() {
-	for i := range e.globals.bx {
-		e.globals.bx[i] = nil
-	}
+	e.saved, e.dirty = nil, nil
+	e.globals.bx = boxStore{}
 	for i := range e.globals.box_head {
 		e.globals.box_head[i] = 0
 	}
@@ -106,12 +576,21 @@ func (e *Engine) clearWorld() {
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
 	e.globals.charSkills = make(map[int][]*skill_ent)
+	e.globals.inventories = make(map[int][]item_ent)
+	e.globals.playerUnits = make(map[int][]int)
+	e.globals.startLocs = make(map[int]bool)
+	e.globals.savedNames = make(map[int]string)
+	e.globals.waitParseLists = nil
+	e.globals.cmdQueues = nil
+	e.globals.nowhereRegion = 0
+	e.globals.nowhereLoc = 0
+	e.globals.autoQuitTurns = 0
 }
 
 // loadEntities loads all entities from the database.
 func (e *Engine) loadEntities() error {
 	rows, err := e.db.Query(`
-		SELECT id, kind, subkind, name, display_name, parent_loc_id
+		SELECT id, kind, subkind, name, display_name, parent_loc_id, owner_player_id
 		FROM entities
 		WHERE is_deleted = 0
 		ORDER BY id
@@ -121,12 +600,14 @@ func (e *Engine) loadEntities() error {
 	}
 	defer rows.Close()
 
+	var kindTail [T_MAX]int
+	var subTail [SUB_MAX]int
 	for rows.Next() {
 		var id, kind, subkind int
 		var name, displayName sql.NullString
-		var parentLocID sql.NullInt64
+		var parentLocID, ownerID sql.NullInt64
 
-		if err := rows.Scan(&id, &kind, &subkind, &name, &displayName, &parentLocID); err != nil {
+		if err := rows.Scan(&id, &kind, &subkind, &name, &displayName, &parentLocID, &ownerID); err != nil {
 			return fmt.Errorf("scan entity %d: %w", id, err)
 		}
 
@@ -135,14 +616,25 @@ func (e *Engine) loadEntities() error {
 		}
 
 		// Allocate the box
-		e.globals.bx[id] = &box{
+		e.setBox(id, &box{
 			kind:  schar(kind),
 			skind: schar(subkind),
+		})
+
+		// Rows come in ID order, so each box goes on the end of its
+		// kind and subkind chains.
+		if t := kindTail[kind]; t != 0 {
+			e.globals.bx.get(t).x_next_kind = id
+		} else {
+			e.globals.box_head[kind] = id
 		}
-
-		// Add to kind and subkind chains
-		e.addToKindChain(id)
-		e.addToSubkindChain(id)
+		kindTail[kind] = id
+		if t := subTail[subkind]; t != 0 {
+			e.globals.bx.get(t).x_next_sub = id
+		} else {
+			e.globals.sub_head[subkind] = id
+		}
+		subTail[subkind] = id
 
 		// Set name
 		if name.Valid && name.String != "" {
@@ -151,7 +643,11 @@ func (e *Engine) loadEntities() error {
 
 		// Set parent location
 		if parentLocID.Valid {
-			e.globals.bx[id].x_loc_info.where = int(parentLocID.Int64)
+			e.globals.bx.get(id).x_loc_info.where = int(parentLocID.Int64)
+		}
+
+		if displayName.Valid && displayName.String != "" {
+			e.globals.banners[id] = displayName.String
 		}
 	}
 
@@ -160,51 +656,52 @@ func (e *Engine) loadEntities() error {
 
 // addToKindChain adds entity n to the kind chain (sorted by ID).
 func (e *Engine) addToKindChain(n int) {
-	if e.globals.bx[n] == nil {
+	if e.globals.bx.get(n) == nil {
 		return
 	}
-	k := int(e.globals.bx[n].kind)
+	k := int(e.globals.bx.get(n).kind)
 
 	if e.globals.box_head[k] == 0 || n < e.globals.box_head[k] {
-		e.globals.bx[n].x_next_kind = e.globals.box_head[k]
+		e.globals.bx.get(n).x_next_kind = e.globals.box_head[k]
 		e.globals.box_head[k] = n
 		return
 	}
 
 	i := e.globals.box_head[k]
-	for e.globals.bx[i].x_next_kind > 0 && e.globals.bx[i].x_next_kind < n {
-		i = e.globals.bx[i].x_next_kind
+	for e.globals.bx.get(i).x_next_kind > 0 && e.globals.bx.get(i).x_next_kind < n {
+		i = e.globals.bx.get(i).x_next_kind
 	}
-	e.globals.bx[n].x_next_kind = e.globals.bx[i].x_next_kind
-	e.globals.bx[i].x_next_kind = n
+	e.globals.bx.get(n).x_next_kind = e.globals.bx.get(i).x_next_kind
+	e.globals.bx.get(i).x_next_kind = n
 }
 
 // addToSubkindChain adds entity n to the subkind chain (sorted by ID).
 func (e *Engine) addToSubkindChain(n int) {
-	if e.globals.bx[n] == nil {
+	if e.globals.bx.get(n) == nil {
 		return
 	}
-	sk := int(e.globals.bx[n].skind)
+	sk := int(e.globals.bx.get(n).skind)
 
 	if e.globals.sub_head[sk] == 0 || n < e.globals.sub_head[sk] {
-		e.globals.bx[n].x_next_sub = e.globals.sub_head[sk]
+		e.globals.bx.get(n).x_next_sub = e.globals.sub_head[sk]
 		e.globals.sub_head[sk] = n
 		return
 	}
 
 	i := e.globals.sub_head[sk]
-	for e.globals.bx[i].x_next_sub > 0 && e.globals.bx[i].x_next_sub < n {
-		i = e.globals.bx[i].x_next_sub
+	for e.globals.bx.get(i).x_next_sub > 0 && e.globals.bx.get(i).x_next_sub < n {
+		i = e.globals.bx.get(i).x_next_sub
 	}
-	e.globals.bx[n].x_next_sub = e.globals.bx[i].x_next_sub
-	e.globals.bx[i].x_next_sub = n
+	e.globals.bx.get(n).x_next_sub = e.globals.bx.get(i).x_next_sub
+	e.globals.bx.get(i).x_next_sub = n
 }
 
 // loadLocations loads location data into entity_loc structs.
 func (e *Engine) loadLocations() error {
 	rows, err := e.db.Query(`
 		SELECT id, region_id, province_id, parent_loc_id, terrain_subkind,
-		       barrier, shroud, civ, sea_lane, is_safe_haven
+		       barrier, shroud, civ, sea_lane, is_safe_haven, is_start_loc,
+		       quest_late, uldim_flag, summer_flag, link_when, link_open
 		FROM locations
 	`)
 	if err != nil {
@@ -216,42 +713,101 @@ func (e *Engine) loadLocations() error {
 		var id int
 		var regionID, provinceID, parentLocID sql.NullInt64
 		var terrainSubkind, barrier, shroud, civ, seaLane, safeHaven int
+		var startLoc, questLate sql.NullInt64
+		var uldimFlag, summerFlag, linkWhen, linkOpen int
 
 		if err := rows.Scan(&id, &regionID, &provinceID, &parentLocID,
-			&terrainSubkind, &barrier, &shroud, &civ, &seaLane, &safeHaven); err != nil {
+			&terrainSubkind, &barrier, &shroud, &civ, &seaLane, &safeHaven,
+			&startLoc, &questLate, &uldimFlag, &summerFlag, &linkWhen, &linkOpen); err != nil {
 			return fmt.Errorf("scan location %d: %w", id, err)
 		}
 
-		if e.globals.bx[id] == nil {
+		if e.globals.bx.get(id) == nil {
 			continue
 		}
 
 		// Ensure x_loc exists
-		if e.globals.bx[id].x_loc == nil {
-			e.globals.bx[id].x_loc = &entity_loc{}
+		if e.globals.bx.get(id).x_loc == nil {
+			e.globals.bx.get(id).x_loc = &entity_loc{}
 		}
-		loc := e.globals.bx[id].x_loc
+		loc := e.globals.bx.get(id).x_loc
 
-		loc.barrier = short(barrier)
+		loc.barrier = barrier
 		loc.shroud = short(shroud)
 		loc.civ = schar(civ)
 		loc.sea_lane = schar(seaLane)
 
+		if questLate.Int64 != 0 || safeHaven != 0 || uldimFlag != 0 ||
+			summerFlag != 0 || linkWhen != 0 || linkOpen != 0 {
+			if e.globals.bx.get(id).x_subloc == nil {
+				e.globals.bx.get(id).x_subloc = &entity_subloc{}
+			}
+			sl := e.globals.bx.get(id).x_subloc
+			sl.quest_late = schar(questLate.Int64)
+			sl.safe = schar(safeHaven)
+			sl.uldim_flag = schar(uldimFlag)
+			sl.summer_flag = schar(summerFlag)
+			sl.link_when = schar(linkWhen)
+			sl.link_open = schar(linkOpen)
+		}
+
+		if startLoc.Int64 != 0 {
+			e.globals.startLocs[id] = true
+		}
+
 		// Set parent location in loc_info
 		if parentLocID.Valid {
-			e.globals.bx[id].x_loc_info.where = int(parentLocID.Int64)
+			e.globals.bx.get(id).x_loc_info.where = int(parentLocID.Int64)
 		}
 	}
 
 	return rows.Err()
 }
 
+// loadLocLinks loads the sublocation links and rebuilds link_from
+// from them.
+func (e *Engine) loadLocLinks() error {
+	rows, err := e.db.Query(`
+		SELECT loc_id, dest_id
+		FROM loc_links
+		ORDER BY loc_id, seq
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var locID, destID int
+		if err := rows.Scan(&locID, &destID); err != nil {
+			return fmt.Errorf("scan loc link: %w", err)
+		}
+
+		from, to := e.globals.bx.get(locID), e.globals.bx.get(destID)
+		if from == nil || to == nil {
+			continue
+		}
+
+		if from.x_subloc == nil {
+			from.x_subloc = &entity_subloc{}
+		}
+		if to.x_subloc == nil {
+			to.x_subloc = &entity_subloc{}
+		}
+
+		from.x_subloc.link_to = append(from.x_subloc.link_to, destID)
+		to.x_subloc.link_from = append(to.x_subloc.link_from, locID)
+	}
+
+	return rows.Err()
+}
+
 // loadCharacters loads character data into entity_char structs.
 func (e *Engine) loadCharacters() error {
 	rows, err := e.db.Query(`
 		SELECT id, player_id, loc_id, health, sick, loy_kind, loy_rate,
 		       unit_item, guard, npc_prog, moving_since, gone_flag,
-		       is_npc, is_dead
+		       is_npc, is_dead, only_vuln
 		FROM characters
 	`)
 	if err != nil {
@@ -266,22 +822,23 @@ func (e *Engine) loadCharacters() error {
 		var loyKind, loyRate, unitItem, guard sql.NullInt64
 		var npcProg, movingSince, goneFlag sql.NullInt64
 		var isNPC, isDead int
+		var onlyVuln sql.NullInt64
 
 		if err := rows.Scan(&id, &playerID, &locID, &health, &sick,
 			&loyKind, &loyRate, &unitItem, &guard, &npcProg,
-			&movingSince, &goneFlag, &isNPC, &isDead); err != nil {
+			&movingSince, &goneFlag, &isNPC, &isDead, &onlyVuln); err != nil {
 			return fmt.Errorf("scan character %d: %w", id, err)
 		}
 
-		if e.globals.bx[id] == nil {
+		if e.globals.bx.get(id) == nil {
 			continue
 		}
 
 		// Ensure x_char exists
-		if e.globals.bx[id].x_char == nil {
-			e.globals.bx[id].x_char = &entity_char{}
+		if e.globals.bx.get(id).x_char == nil {
+			e.globals.bx.get(id).x_char = &entity_char{}
 		}
-		ch := e.globals.bx[id].x_char
+		ch := e.globals.bx.get(id).x_char
 
 		ch.health = schar(health)
 		ch.sick = schar(sick)
@@ -312,19 +869,28 @@ func (e *Engine) loadCharacters() error {
 
 		// Set location
 		if locID.Valid {
-			e.globals.bx[id].x_loc_info.where = int(locID.Int64)
+			e.globals.bx.get(id).x_loc_info.where = int(locID.Int64)
+		}
+
+		// Only defeatable by a rare artifact
+		if onlyVuln.Valid {
+			if e.globals.bx.get(id).x_misc == nil {
+				e.globals.bx.get(id).x_misc = &entity_misc{}
+			}
+			e.globals.bx.get(id).x_misc.only_vuln = int(onlyVuln.Int64)
 		}
 	}
 
 	return rows.Err()
 }
 
-// loadCharMagic loads character magic data.
+// loadCharMagic loads character magic data. Units controlled by an
+// npc token are added back to the token's unit list.
 func (e *Engine) loadCharMagic() error {
 	rows, err := e.db.Query(`
 		SELECT char_id, pray, hide_self, vis_protect, hide_mage,
 		       cur_aura, max_aura, aura_reflect, pledge, auraculum,
-		       ability_shroud, fee, ferry_flag
+		       ability_shroud, fee, ferry_flag, magician, token
 		FROM char_magic
 	`)
 	if err != nil {
@@ -338,25 +904,27 @@ func (e *Engine) loadCharMagic() error {
 		var curAura, maxAura, auraReflect int
 		var pledge, auraculum sql.NullInt64
 		var abilityShroud, fee, ferryFlag int
+		var magician int
+		var token sql.NullInt64
 
 		if err := rows.Scan(&charID, &pray, &hideSelf, &visProtect, &hideMage,
 			&curAura, &maxAura, &auraReflect, &pledge, &auraculum,
-			&abilityShroud, &fee, &ferryFlag); err != nil {
+			&abilityShroud, &fee, &ferryFlag, &magician, &token); err != nil {
 			return fmt.Errorf("scan char_magic %d: %w", charID, err)
 		}
 
-		if e.globals.bx[charID] == nil {
+		if e.globals.bx.get(charID) == nil {
 			continue
 		}
 
 		// Ensure x_char and x_char_magic exist
-		if e.globals.bx[charID].x_char == nil {
-			e.globals.bx[charID].x_char = &entity_char{}
+		if e.globals.bx.get(charID).x_char == nil {
+			e.globals.bx.get(charID).x_char = &entity_char{}
 		}
-		if e.globals.bx[charID].x_char.x_char_magic == nil {
-			e.globals.bx[charID].x_char.x_char_magic = &char_magic{}
+		if e.globals.bx.get(charID).x_char.x_char_magic == nil {
+			e.globals.bx.get(charID).x_char.x_char_magic = &char_magic{}
 		}
-		m := e.globals.bx[charID].x_char.x_char_magic
+		m := e.globals.bx.get(charID).x_char.x_char_magic
 
 		m.pray = schar(pray)
 		m.hide_self = schar(hideSelf)
@@ -366,6 +934,7 @@ func (e *Engine) loadCharMagic() error {
 		m.max_aura = maxAura
 		m.aura_reflect = schar(auraReflect)
 		m.ability_shroud = short(abilityShroud)
+		m.magician = schar(magician)
 
 		if pledge.Valid {
 			m.pledge = int(pledge.Int64)
@@ -373,15 +942,51 @@ func (e *Engine) loadCharMagic() error {
 		if auraculum.Valid {
 			m.auraculum = int(auraculum.Int64)
 		}
+		if token.Valid {
+			m.token = int(token.Int64)
+
+			e.addUnit(m.token, charID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
 	}
 
-	return rows.Err()
+	visionRows, err := e.db.Query(`
+		SELECT char_id, target_id
+		FROM char_visions
+	`)
+	if err != nil {
+		return err
+	}
+	defer visionRows.Close()
+
+	for visionRows.Next() {
+		var charID, target int
+
+		if err := visionRows.Scan(&charID, &target); err != nil {
+			return fmt.Errorf("scan char_vision: %w", err)
+		}
+
+		b := e.globals.bx.get(charID)
+		if b == nil || b.x_char == nil || b.x_char.x_char_magic == nil {
+			continue
+		}
+
+		m := b.x_char.x_char_magic
+		m.visions = set_bit(m.visions, target)
+	}
+
+	return visionRows.Err()
 }
 
 // loadPlayers loads player data into entity_player structs.
 func (e *Engine) loadPlayers() error {
 	rows, err := e.db.Query(`
-		SELECT id, code, name, subkind
+		SELECT id, account_id, code, name, subkind, email, vis_email,
+		       full_name, noble_points, fast_study, first_turn, last_order_turn,
+		       report_format, notab, last_email, split_lines, split_bytes, sent_orders,
+		       dont_remind, first_tower, compuserve, broken_mailer
 		FROM players
 	`)
 	if err != nil {
@@ -391,11 +996,17 @@ func (e *Engine) loadPlayers() error {
 
 	for rows.Next() {
 		var id int
+		var account sql.NullInt64
 		var code string
-		var name sql.NullString
-		var subkind int
-
-		if err := rows.Scan(&id, &code, &name, &subkind); err != nil {
+		var name, email, visEmail, fullName, lastEmail sql.NullString
+		var subkind, noblePoints, fastStudy, firstTurn, lastOrderTurn int
+		var format, notab, splitLines, splitBytes, sentOrders, dontRemind int
+		var firstTower, compuserve, brokenMailer int
+
+		if err := rows.Scan(&id, &account, &code, &name, &subkind, &email, &visEmail,
+			&fullName, &noblePoints, &fastStudy, &firstTurn, &lastOrderTurn,
+			&format, &notab, &lastEmail, &splitLines, &splitBytes, &sentOrders,
+			&dontRemind, &firstTower, &compuserve, &brokenMailer); err != nil {
 			return fmt.Errorf("scan player %d: %w", id, err)
 		}
 
@@ -404,25 +1015,75 @@ func (e *Engine) loadPlayers() error {
 		}
 
 		// Create box if it doesn't exist (players may not be in entities table)
-		if e.globals.bx[id] == nil {
-			e.globals.bx[id] = &box{
+		if e.globals.bx.get(id) == nil {
+			e.setBox(id, &box{
 				kind:  T_player,
 				skind: schar(subkind),
-			}
+			})
 			e.addToKindChain(id)
 			e.addToSubkindChain(id)
 		}
 
 		// Ensure x_player exists
-		if e.globals.bx[id].x_player == nil {
-			e.globals.bx[id].x_player = &entity_player{}
+		if e.globals.bx.get(id).x_player == nil {
+			e.globals.bx.get(id).x_player = &entity_player{}
 		}
 
+		p := e.globals.bx.get(id).x_player
+		p.account_id = int(account.Int64)
+		p.email = email.String
+		p.vis_email = visEmail.String
+		p.full_name = fullName.String
+		p.noble_points = short(noblePoints)
+		p.fast_study = short(fastStudy)
+		p.first_turn = firstTurn
+		p.last_order_turn = lastOrderTurn
+		p.format = schar(format)
+		p.notab = schar(notab)
+		p.last_email = lastEmail.String
+		p.split_lines = splitLines
+		p.split_bytes = splitBytes
+		p.sent_orders = schar(sentOrders)
+		p.dont_remind = schar(dontRemind)
+		p.first_tower = schar(firstTower)
+		p.compuserve = schar(compuserve)
+		p.broken_mailer = schar(brokenMailer)
+
 		// Set name
 		if name.Valid && name.String != "" {
 			e.globals.names[id] = name.String
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	return e.loadPlayerPasswords()
+}
+
+// loadPlayerPasswords loads the order passwords saved for players.
+func (e *Engine) loadPlayerPasswords() error {
+	rows, err := e.db.Query(`SELECT key, value FROM passwords WHERE key LIKE 'player:%'`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return fmt.Errorf("scan password: %w", err)
+		}
+
+		pl := code_to_int(strings.TrimPrefix(key, "player:"))
+		if pl <= 0 || pl >= MAX_BOXES {
+			continue
+		}
+		if b := e.globals.bx.get(pl); b != nil && b.x_player != nil {
+			b.x_player.password = value
+		}
+	}
 
 	return rows.Err()
 }
@@ -445,21 +1106,21 @@ func (e *Engine) loadGates() error {
 			return fmt.Errorf("scan gate %d: %w", id, err)
 		}
 
-		if e.globals.bx[id] == nil {
+		if e.globals.bx.get(id) == nil {
 			continue
 		}
 
 		// Ensure x_gate exists
-		if e.globals.bx[id].x_gate == nil {
-			e.globals.bx[id].x_gate = &entity_gate{}
+		if e.globals.bx.get(id).x_gate == nil {
+			e.globals.bx.get(id).x_gate = &entity_gate{}
 		}
-		g := e.globals.bx[id].x_gate
+		g := e.globals.bx.get(id).x_gate
 
 		g.to_loc = toLocID
 		g.road_hidden = schar(roadHidden)
 
 		// Set location (from_loc_id)
-		e.globals.bx[id].x_loc_info.where = fromLocID
+		e.globals.bx.get(id).x_loc_info.where = fromLocID
 	}
 
 	return rows.Err()
@@ -484,15 +1145,15 @@ func (e *Engine) loadStorms() error {
 			return fmt.Errorf("scan storm %d: %w", id, err)
 		}
 
-		if e.globals.bx[id] == nil {
+		if e.globals.bx.get(id) == nil {
 			continue
 		}
 
 		// Ensure x_misc exists
-		if e.globals.bx[id].x_misc == nil {
-			e.globals.bx[id].x_misc = &entity_misc{}
+		if e.globals.bx.get(id).x_misc == nil {
+			e.globals.bx.get(id).x_misc = &entity_misc{}
 		}
-		m := e.globals.bx[id].x_misc
+		m := e.globals.bx.get(id).x_misc
 
 		m.storm_str = short(strength)
 
@@ -524,15 +1185,15 @@ func (e *Engine) loadShips() error {
 			return fmt.Errorf("scan ship %d: %w", id, err)
 		}
 
-		if e.globals.bx[id] == nil {
+		if e.globals.bx.get(id) == nil {
 			continue
 		}
 
 		// Ensure x_subloc exists
-		if e.globals.bx[id].x_subloc == nil {
-			e.globals.bx[id].x_subloc = &entity_subloc{}
+		if e.globals.bx.get(id).x_subloc == nil {
+			e.globals.bx.get(id).x_subloc = &entity_subloc{}
 		}
-		s := e.globals.bx[id].x_subloc
+		s := e.globals.bx.get(id).x_subloc
 
 		if capacity.Valid {
 			s.capacity = int(capacity.Int64)
@@ -543,15 +1204,15 @@ func (e *Engine) loadShips() error {
 
 		// Set location
 		if locID.Valid {
-			e.globals.bx[id].x_loc_info.where = int(locID.Int64)
+			e.globals.bx.get(id).x_loc_info.where = int(locID.Int64)
 		}
 
 		// Storm binding goes in x_misc
 		if stormBind.Valid {
-			if e.globals.bx[id].x_misc == nil {
-				e.globals.bx[id].x_misc = &entity_misc{}
+			if e.globals.bx.get(id).x_misc == nil {
+				e.globals.bx.get(id).x_misc = &entity_misc{}
 			}
-			e.globals.bx[id].x_misc.bind_storm = int(stormBind.Int64)
+			e.globals.bx.get(id).x_misc.bind_storm = int(stormBind.Int64)
 		}
 	}
 
@@ -561,7 +1222,7 @@ func (e *Engine) loadShips() error {
 // loadItemTypes loads item type definitions into entity_item structs.
 func (e *Engine) loadItemTypes() error {
 	rows, err := e.db.Query(`
-		SELECT id, subkind, name, weight, is_animal, prominent
+		SELECT id, subkind, name, weight, is_animal, prominent, who_has
 		FROM item_types
 	`)
 	if err != nil {
@@ -573,8 +1234,9 @@ func (e *Engine) loadItemTypes() error {
 		var id, subkind int
 		var name string
 		var weight, isAnimal, prominent int
+		var whoHas sql.NullInt64
 
-		if err := rows.Scan(&id, &subkind, &name, &weight, &isAnimal, &prominent); err != nil {
+		if err := rows.Scan(&id, &subkind, &name, &weight, &isAnimal, &prominent, &whoHas); err != nil {
 			return fmt.Errorf("scan item_type %d: %w", id, err)
 		}
 
@@ -583,24 +1245,27 @@ func (e *Engine) loadItemTypes() error {
 		}
 
 		// Create box if it doesn't exist
-		if e.globals.bx[id] == nil {
-			e.globals.bx[id] = &box{
+		if e.globals.bx.get(id) == nil {
+			e.setBox(id, &box{
 				kind:  T_item,
 				skind: schar(subkind),
-			}
+			})
 			e.addToKindChain(id)
 			e.addToSubkindChain(id)
 		}
 
 		// Ensure x_item exists
-		if e.globals.bx[id].x_item == nil {
-			e.globals.bx[id].x_item = &entity_item{}
+		if e.globals.bx.get(id).x_item == nil {
+			e.globals.bx.get(id).x_item = &entity_item{}
 		}
-		it := e.globals.bx[id].x_item
+		it := e.globals.bx.get(id).x_item
 
 		it.weight = short(weight)
 		it.is_man_item = schar(isAnimal)
 		it.prominent = schar(prominent)
+		if whoHas.Valid {
+			it.who_has = int(whoHas.Int64)
+		}
 
 		// Set name
 		if name != "" {
@@ -611,6 +1276,142 @@ func (e *Engine) loadItemTypes() error {
 	return rows.Err()
 }
 
+// loadItemMagic loads magical item attributes into item_magic structs.
+func (e *Engine) loadItemMagic() error {
+	rows, err := e.db.Query(`
+		SELECT item_id, creator, region_created, lore,
+		       curse_loyalty, cloak_region, cloak_creator, use_key,
+		       project_cast, token_ni, quick_cast,
+		       aura_bonus, aura, relic_decay,
+		       attack_bonus, defense_bonus, missile_bonus,
+		       token_num, orb_use_count
+		FROM item_magic
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var creator, regionCreated, lore sql.NullInt64
+		var curseLoyalty, cloakRegion, cloakCreator, useKey int
+		var projectCast, tokenNI, quickCast int
+		var auraBonus, aura, relicDecay int
+		var attackBonus, defenseBonus, missileBonus int
+		var tokenNum, orbUseCount int
+
+		if err := rows.Scan(&id, &creator, &regionCreated, &lore,
+			&curseLoyalty, &cloakRegion, &cloakCreator, &useKey,
+			&projectCast, &tokenNI, &quickCast,
+			&auraBonus, &aura, &relicDecay,
+			&attackBonus, &defenseBonus, &missileBonus,
+			&tokenNum, &orbUseCount); err != nil {
+			return fmt.Errorf("scan item_magic %d: %w", id, err)
+		}
+
+		if id <= 0 || id >= MAX_BOXES || e.globals.bx.get(id) == nil {
+			continue
+		}
+
+		// Ensure x_item and x_item_magic exist
+		if e.globals.bx.get(id).x_item == nil {
+			e.globals.bx.get(id).x_item = &entity_item{}
+		}
+		if e.globals.bx.get(id).x_item.x_item_magic == nil {
+			e.globals.bx.get(id).x_item.x_item_magic = &item_magic{}
+		}
+		m := e.globals.bx.get(id).x_item.x_item_magic
+
+		m.creator = int(creator.Int64)
+		m.region_created = int(regionCreated.Int64)
+		m.lore = int(lore.Int64)
+		m.curse_loyalty = schar(curseLoyalty)
+		m.cloak_region = schar(cloakRegion)
+		m.cloak_creator = schar(cloakCreator)
+		m.use_key = schar(useKey)
+		m.project_cast = projectCast
+		m.token_ni = tokenNI
+		m.quick_cast = short(quickCast)
+		m.aura_bonus = short(auraBonus)
+		m.aura = short(aura)
+		m.relic_decay = short(relicDecay)
+		m.attack_bonus = schar(attackBonus)
+		m.defense_bonus = schar(defenseBonus)
+		m.missile_bonus = schar(missileBonus)
+		m.token_num = schar(tokenNum)
+		m.orb_use_count = schar(orbUseCount)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	skillRows, err := e.db.Query(`
+		SELECT item_id, kind, skill_id
+		FROM item_magic_skills
+		ORDER BY item_id, kind, seq
+	`)
+	if err != nil {
+		return err
+	}
+	defer skillRows.Close()
+
+	for skillRows.Next() {
+		var id, skill int
+		var kind string
+
+		if err := skillRows.Scan(&id, &kind, &skill); err != nil {
+			return fmt.Errorf("scan item_magic_skills %d: %w", id, err)
+		}
+
+		if id <= 0 || id >= MAX_BOXES || e.globals.bx.get(id) == nil || e.globals.bx.get(id).x_item == nil {
+			continue
+		}
+		m := e.globals.bx.get(id).x_item.x_item_magic
+		if m == nil {
+			continue
+		}
+
+		switch kind {
+		case "use":
+			m.may_use.Append(skill)
+		case "study":
+			m.may_study.Append(skill)
+		}
+	}
+
+	return skillRows.Err()
+}
+
+// loadInventories loads the items held by each entity.
+func (e *Engine) loadInventories() error {
+	rows, err := e.db.Query(`
+		SELECT owner_entity_id, item_id, qty
+		FROM inventories
+		ORDER BY owner_entity_id, rowid
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var owner, item, qty int
+
+		if err := rows.Scan(&owner, &item, &qty); err != nil {
+			return fmt.Errorf("scan inventory %d: %w", owner, err)
+		}
+
+		if owner <= 0 || owner >= MAX_BOXES || e.globals.bx.get(owner) == nil {
+			continue
+		}
+
+		e.globals.inventories[owner] = append(e.globals.inventories[owner], item_ent{item: item, qty: qty})
+	}
+
+	return rows.Err()
+}
+
 // loadSkills loads skill definitions into entity_skill structs.
 func (e *Engine) loadSkills() error {
 	rows, err := e.db.Query(`
@@ -643,18 +1444,18 @@ func (e *Engine) loadSkills() error {
 		}
 
 		// Create box if it doesn't exist
-		if e.globals.bx[id] == nil {
-			e.globals.bx[id] = &box{
+		if e.globals.bx.get(id) == nil {
+			e.setBox(id, &box{
 				kind:  T_skill,
 				skind: subkind,
-			}
+			})
 			e.addToKindChain(id)
 			e.addToSubkindChain(id)
 		}
 
 		// Ensure x_skill exists
-		if e.globals.bx[id].x_skill == nil {
-			e.globals.bx[id].x_skill = &entity_skill{}
+		if e.globals.bx.get(id).x_skill == nil {
+			e.globals.bx.get(id).x_skill = &entity_skill{}
 		}
 
 		// Set name
@@ -667,9 +1468,95 @@ func (e *Engine) loadSkills() error {
 }
 
 // loadCharSkills loads character skill data.
+// loadDeadBodies restores the noble data carried by dead bodies.
+func (e *Engine) loadDeadBodies() error {
+	rows, err := e.db.Query(`
+		SELECT item_id, save_name, old_lord, prev_lord,
+		       death_turn, death_day, attack, defense, missile
+		FROM dead_bodies
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, deathTurn, deathDay, attack, defense, missile int
+		var saveName sql.NullString
+		var oldLord, prevLord sql.NullInt64
+
+		if err := rows.Scan(&id, &saveName, &oldLord, &prevLord,
+			&deathTurn, &deathDay, &attack, &defense, &missile); err != nil {
+			return fmt.Errorf("scan dead_body: %w", err)
+		}
+
+		b := e.globals.bx.get(id)
+		if b == nil {
+			continue
+		}
+
+		if b.x_char == nil {
+			b.x_char = &entity_char{}
+		}
+		ch := b.x_char
+		ch.prev_lord = int(prevLord.Int64)
+		ch.death_time.turn = short(deathTurn)
+		ch.death_time.day = short(deathDay)
+		ch.attack = short(attack)
+		ch.defense = short(defense)
+		ch.missile = short(missile)
+
+		if oldLord.Valid {
+			if b.x_misc == nil {
+				b.x_misc = &entity_misc{}
+			}
+			b.x_misc.old_lord = int(oldLord.Int64)
+		}
+
+		if saveName.Valid {
+			e.globals.savedNames[id] = saveName.String
+		}
+		e.setPluralName(id, "dead bodies")
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	skillRows, err := e.db.Query(`
+		SELECT item_id, skill_id, level, experience
+		FROM dead_body_skills
+		ORDER BY item_id, skill_id
+	`)
+	if err != nil {
+		return err
+	}
+	defer skillRows.Close()
+
+	for skillRows.Next() {
+		var id, skillID, level, experience int
+
+		if err := skillRows.Scan(&id, &skillID, &level, &experience); err != nil {
+			return fmt.Errorf("scan dead_body_skill: %w", err)
+		}
+
+		if e.globals.bx.get(id) == nil {
+			continue
+		}
+
+		e.appendCharSkill(id, &skill_ent{
+			skill:        skillID,
+			days_studied: level,
+			experience:   short(experience),
+			know:         SKILL_know,
+		})
+	}
+
+	return skillRows.Err()
+}
+
 func (e *Engine) loadCharSkills() error {
 	rows, err := e.db.Query(`
-		SELECT char_id, skill_id, level, experience
+		SELECT char_id, skill_id, level, experience, know
 		FROM char_skills
 	`)
 	if err != nil {
@@ -678,19 +1565,19 @@ func (e *Engine) loadCharSkills() error {
 	defer rows.Close()
 
 	for rows.Next() {
-		var charID, skillID, level, experience int
+		var charID, skillID, level, experience, know int
 
-		if err := rows.Scan(&charID, &skillID, &level, &experience); err != nil {
+		if err := rows.Scan(&charID, &skillID, &level, &experience, &know); err != nil {
 			return fmt.Errorf("scan char_skill: %w", err)
 		}
 
-		if e.globals.bx[charID] == nil {
+		if e.globals.bx.get(charID) == nil {
 			continue
 		}
 
 		// Ensure x_char exists
-		if e.globals.bx[charID].x_char == nil {
-			e.globals.bx[charID].x_char = &entity_char{}
+		if e.globals.bx.get(charID).x_char == nil {
+			e.globals.bx.get(charID).x_char = &entity_char{}
 		}
 
 		// Add skill to character's skill list
@@ -698,7 +1585,7 @@ func (e *Engine) loadCharSkills() error {
 			skill:        skillID,
 			days_studied: level,
 			experience:   short(experience),
-			know:         SKILL_know,
+			know:         char(know),
 		}
 
 		// Append to skills list using the C-style plist pattern
@@ -710,6 +1597,10 @@ func (e *Engine) loadCharSkills() error {
 
 // loadSystemConfig loads system configuration from game_meta.
 func (e *Engine) loadSystemConfig() error {
+	if err := e.loadSystemSettings(); err != nil {
+		return err
+	}
+
 	row := e.db.QueryRow(`
 		SELECT game_name, current_turn, options_json
 		FROM game_meta
@@ -738,7 +1629,8 @@ func (e *Engine) loadSystemConfig() error {
 // appendCharSkill appends a skill_ent to a character's skills list.
 // This handles the C-style **skill_ent (plist) pattern.
 func (e *Engine) appendCharSkill(charID int, sk *skill_ent) {
-	ch := e.globals.bx[charID].x_char
+	e.markDirty(charID)
+	ch := e.globals.bx.get(charID).x_char
 	if ch == nil {
 		return
 	}
@@ -759,3 +1651,36 @@ func (e *Engine) getCharSkills(charID int) []*skill_ent {
 	}
 	return e.globals.charSkills[charID]
 }
+
+// loadSystemSettings reads the special locations and settings saved
+// by saveSystemConfig. Unknown keys are left for other tools.
+func (e *Engine) loadSystemSettings() error {
+	rows, err := e.db.Query(`SELECT key, value FROM system_config`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return fmt.Errorf("scan system_config: %w", err)
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+
+		switch key {
+		case "nowhere_region":
+			e.globals.nowhereRegion = n
+		case "nowhere_loc":
+			e.globals.nowhereLoc = n
+		case "auto_quit_turns":
+			e.globals.autoQuitTurns = n
+		}
+	}
+
+	return rows.Err()
+}
@@ -144,6 +144,7 @@ func TestLoadWorldEntities(t *testing.T) {
 
 	// Create engine and load world
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
@@ -154,14 +155,14 @@ func TestLoadWorldEntities(t *testing.T) {
 	}
 
 	// Verify region loaded
-	if e.globals.bx[58760] == nil {
+	if e.globals.bx.get(58760) == nil {
 		t.Error("region 58760 not loaded")
 	} else {
-		if e.globals.bx[58760].kind != T_loc {
-			t.Errorf("region kind = %d, want %d", e.globals.bx[58760].kind, T_loc)
+		if e.globals.bx.get(58760).kind != T_loc {
+			t.Errorf("region kind = %d, want %d", e.globals.bx.get(58760).kind, T_loc)
 		}
-		if e.globals.bx[58760].skind != sub_region {
-			t.Errorf("region subkind = %d, want %d", e.globals.bx[58760].skind, sub_region)
+		if e.globals.bx.get(58760).skind != sub_region {
+			t.Errorf("region subkind = %d, want %d", e.globals.bx.get(58760).skind, sub_region)
 		}
 		if e.globals.names[58760] != "Provinia" {
 			t.Errorf("region name = %q, want 'Provinia'", e.globals.names[58760])
@@ -169,29 +170,29 @@ func TestLoadWorldEntities(t *testing.T) {
 	}
 
 	// Verify province loaded
-	if e.globals.bx[10000] == nil {
+	if e.globals.bx.get(10000) == nil {
 		t.Error("province 10000 not loaded")
 	} else {
-		if e.globals.bx[10000].kind != T_loc {
-			t.Errorf("province kind = %d, want %d", e.globals.bx[10000].kind, T_loc)
+		if e.globals.bx.get(10000).kind != T_loc {
+			t.Errorf("province kind = %d, want %d", e.globals.bx.get(10000).kind, T_loc)
 		}
-		if e.globals.bx[10000].skind != sub_plain {
-			t.Errorf("province subkind = %d, want %d", e.globals.bx[10000].skind, sub_plain)
+		if e.globals.bx.get(10000).skind != sub_plain {
+			t.Errorf("province subkind = %d, want %d", e.globals.bx.get(10000).skind, sub_plain)
 		}
 		if e.globals.names[10000] != "Greyfell" {
 			t.Errorf("province name = %q, want 'Greyfell'", e.globals.names[10000])
 		}
-		if e.globals.bx[10000].x_loc_info.where != 58760 {
-			t.Errorf("province parent = %d, want 58760", e.globals.bx[10000].x_loc_info.where)
+		if e.globals.bx.get(10000).x_loc_info.where != 58760 {
+			t.Errorf("province parent = %d, want 58760", e.globals.bx.get(10000).x_loc_info.where)
 		}
 	}
 
 	// Verify location details loaded
-	if e.globals.bx[10000].x_loc == nil {
+	if e.globals.bx.get(10000).x_loc == nil {
 		t.Error("province x_loc not allocated")
 	} else {
-		if e.globals.bx[10000].x_loc.civ != 5 {
-			t.Errorf("province civ = %d, want 5", e.globals.bx[10000].x_loc.civ)
+		if e.globals.bx.get(10000).x_loc.civ != 5 {
+			t.Errorf("province civ = %d, want 5", e.globals.bx.get(10000).x_loc.civ)
 		}
 	}
 }
@@ -206,6 +207,7 @@ func TestLoadWorldCharacters(t *testing.T) {
 	insertTestWorld(t, db)
 
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
@@ -216,19 +218,19 @@ func TestLoadWorldCharacters(t *testing.T) {
 	}
 
 	// Verify character loaded
-	if e.globals.bx[1001] == nil {
+	if e.globals.bx.get(1001) == nil {
 		t.Fatal("character 1001 not loaded")
 	}
 
-	if e.globals.bx[1001].kind != T_char {
-		t.Errorf("char kind = %d, want %d", e.globals.bx[1001].kind, T_char)
+	if e.globals.bx.get(1001).kind != T_char {
+		t.Errorf("char kind = %d, want %d", e.globals.bx.get(1001).kind, T_char)
 	}
 	if e.globals.names[1001] != "Osswid" {
 		t.Errorf("char name = %q, want 'Osswid'", e.globals.names[1001])
 	}
 
 	// Verify character details
-	ch := e.globals.bx[1001].x_char
+	ch := e.globals.bx.get(1001).x_char
 	if ch == nil {
 		t.Fatal("character x_char not allocated")
 	}
@@ -246,8 +248,8 @@ func TestLoadWorldCharacters(t *testing.T) {
 	}
 
 	// Verify character location
-	if e.globals.bx[1001].x_loc_info.where != 10000 {
-		t.Errorf("char location = %d, want 10000", e.globals.bx[1001].x_loc_info.where)
+	if e.globals.bx.get(1001).x_loc_info.where != 10000 {
+		t.Errorf("char location = %d, want 10000", e.globals.bx.get(1001).x_loc_info.where)
 	}
 
 	// Verify character magic
@@ -276,6 +278,7 @@ func TestLoadWorldPlayers(t *testing.T) {
 	insertTestWorld(t, db)
 
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
@@ -286,15 +289,15 @@ func TestLoadWorldPlayers(t *testing.T) {
 	}
 
 	// Verify player loaded
-	if e.globals.bx[50001] == nil {
+	if e.globals.bx.get(50001) == nil {
 		t.Fatal("player 50001 not loaded")
 	}
 
-	if e.globals.bx[50001].kind != T_player {
-		t.Errorf("player kind = %d, want %d", e.globals.bx[50001].kind, T_player)
+	if e.globals.bx.get(50001).kind != T_player {
+		t.Errorf("player kind = %d, want %d", e.globals.bx.get(50001).kind, T_player)
 	}
-	if e.globals.bx[50001].skind != sub_pl_regular {
-		t.Errorf("player subkind = %d, want %d", e.globals.bx[50001].skind, sub_pl_regular)
+	if e.globals.bx.get(50001).skind != sub_pl_regular {
+		t.Errorf("player subkind = %d, want %d", e.globals.bx.get(50001).skind, sub_pl_regular)
 	}
 	if e.globals.names[50001] != "Test Faction" {
 		t.Errorf("player name = %q, want 'Test Faction'", e.globals.names[50001])
@@ -311,6 +314,7 @@ func TestLoadWorldGates(t *testing.T) {
 	insertTestWorld(t, db)
 
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
@@ -321,19 +325,19 @@ func TestLoadWorldGates(t *testing.T) {
 	}
 
 	// Verify gate loaded
-	if e.globals.bx[59001] == nil {
+	if e.globals.bx.get(59001) == nil {
 		t.Fatal("gate 59001 not loaded")
 	}
 
-	if e.globals.bx[59001].kind != T_gate {
-		t.Errorf("gate kind = %d, want %d", e.globals.bx[59001].kind, T_gate)
+	if e.globals.bx.get(59001).kind != T_gate {
+		t.Errorf("gate kind = %d, want %d", e.globals.bx.get(59001).kind, T_gate)
 	}
 	if e.globals.names[59001] != "Ancient Gate" {
 		t.Errorf("gate name = %q, want 'Ancient Gate'", e.globals.names[59001])
 	}
 
 	// Verify gate details
-	g := e.globals.bx[59001].x_gate
+	g := e.globals.bx.get(59001).x_gate
 	if g == nil {
 		t.Fatal("gate x_gate not allocated")
 	}
@@ -345,8 +349,8 @@ func TestLoadWorldGates(t *testing.T) {
 	}
 
 	// Verify gate location (from_loc)
-	if e.globals.bx[59001].x_loc_info.where != 10000 {
-		t.Errorf("gate location = %d, want 10000", e.globals.bx[59001].x_loc_info.where)
+	if e.globals.bx.get(59001).x_loc_info.where != 10000 {
+		t.Errorf("gate location = %d, want 10000", e.globals.bx.get(59001).x_loc_info.where)
 	}
 }
 
@@ -360,6 +364,7 @@ func TestLoadWorldKindChains(t *testing.T) {
 	insertTestWorld(t, db)
 
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
@@ -410,6 +415,7 @@ func TestLoadWorldSubkindChains(t *testing.T) {
 	insertTestWorld(t, db)
 
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
@@ -458,12 +464,13 @@ func TestLoadWorldClearsExisting(t *testing.T) {
 	defer db.Close()
 
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
 
 	// Manually create a box
-	e.globals.bx[999] = &box{kind: T_item}
+	e.setBox(999, &box{kind: T_item})
 	e.globals.names[999] = "Old Item"
 
 	// Insert minimal world
@@ -476,7 +483,7 @@ func TestLoadWorldClearsExisting(t *testing.T) {
 	}
 
 	// Verify old box was cleared
-	if e.globals.bx[999] != nil {
+	if e.globals.bx.get(999) != nil {
 		t.Error("old box 999 not cleared")
 	}
 	if e.globals.names[999] != "" {
@@ -508,6 +515,7 @@ func TestLoadWorldItemTypes(t *testing.T) {
 	}
 
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
@@ -519,25 +527,25 @@ func TestLoadWorldItemTypes(t *testing.T) {
 	}
 
 	// Verify gold loaded
-	if e.globals.bx[1] == nil {
+	if e.globals.bx.get(1) == nil {
 		t.Fatal("item 1 (gold) not loaded")
 	}
-	if e.globals.bx[1].kind != T_item {
-		t.Errorf("item 1 kind = %d, want %d", e.globals.bx[1].kind, T_item)
+	if e.globals.bx.get(1).kind != T_item {
+		t.Errorf("item 1 kind = %d, want %d", e.globals.bx.get(1).kind, T_item)
 	}
 	if e.globals.names[1] != "gold" {
 		t.Errorf("item 1 name = %q, want 'gold'", e.globals.names[1])
 	}
 
 	// Verify peasant loaded with weight
-	if e.globals.bx[10] == nil {
+	if e.globals.bx.get(10) == nil {
 		t.Fatal("item 10 (peasant) not loaded")
 	}
-	if e.globals.bx[10].x_item == nil {
+	if e.globals.bx.get(10).x_item == nil {
 		t.Fatal("item 10 x_item not allocated")
 	}
-	if e.globals.bx[10].x_item.weight != 100 {
-		t.Errorf("item 10 weight = %d, want 100", e.globals.bx[10].x_item.weight)
+	if e.globals.bx.get(10).x_item.weight != 100 {
+		t.Errorf("item 10 weight = %d, want 100", e.globals.bx.get(10).x_item.weight)
 	}
 }
 
@@ -565,6 +573,7 @@ func TestLoadWorldSkills(t *testing.T) {
 	}
 
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
@@ -576,22 +585,22 @@ func TestLoadWorldSkills(t *testing.T) {
 	}
 
 	// Verify Shipcraft loaded
-	if e.globals.bx[600] == nil {
+	if e.globals.bx.get(600) == nil {
 		t.Fatal("skill 600 (Shipcraft) not loaded")
 	}
-	if e.globals.bx[600].kind != T_skill {
-		t.Errorf("skill 600 kind = %d, want %d", e.globals.bx[600].kind, T_skill)
+	if e.globals.bx.get(600).kind != T_skill {
+		t.Errorf("skill 600 kind = %d, want %d", e.globals.bx.get(600).kind, T_skill)
 	}
 	if e.globals.names[600] != "Shipcraft" {
 		t.Errorf("skill 600 name = %q, want 'Shipcraft'", e.globals.names[600])
 	}
 
 	// Verify Basic Magic loaded with magic subkind
-	if e.globals.bx[800] == nil {
+	if e.globals.bx.get(800) == nil {
 		t.Fatal("skill 800 (Basic Magic) not loaded")
 	}
-	if e.globals.bx[800].skind != sub_magic {
-		t.Errorf("skill 800 skind = %d, want %d", e.globals.bx[800].skind, sub_magic)
+	if e.globals.bx.get(800).skind != sub_magic {
+		t.Errorf("skill 800 skind = %d, want %d", e.globals.bx.get(800).skind, sub_magic)
 	}
 }
 
@@ -622,6 +631,7 @@ func TestLoadWorldCharSkills(t *testing.T) {
 	}
 
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
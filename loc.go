@@ -21,8 +21,8 @@ package taygete
 // loc_depth returns the depth level of a location based on its subkind.
 // Returns LOC_region, LOC_province, LOC_subloc, or LOC_build.
 // Ported from src/u.c lines 150-220.
-func loc_depth(n int) int {
-	switch subkind(n) {
+func (e *Engine) loc_depth(n int) int {
+	switch e.subkind(n) {
 	case sub_region:
 		return LOC_region
 
@@ -51,18 +51,18 @@ func loc_depth(n int) int {
 
 // region returns the ultimate region containing who.
 // Ported from src/loc.c lines 133-142.
-func region(who int) int {
-	for who > 0 && (kind(who) != T_loc || loc_depth(who) != LOC_region) {
-		who = loc(who)
+func (e *Engine) region(who int) int {
+	for who > 0 && (e.kind(who) != T_loc || e.loc_depth(who) != LOC_region) {
+		who = e.loc(who)
 	}
 	return who
 }
 
 // province returns the ultimate province containing who.
 // Ported from src/loc.c lines 149-158.
-func province(who int) int {
-	for who > 0 && (kind(who) != T_loc || loc_depth(who) != LOC_province) {
-		who = loc(who)
+func (e *Engine) province(who int) int {
+	for who > 0 && (e.kind(who) != T_loc || e.loc_depth(who) != LOC_province) {
+		who = e.loc(who)
 	}
 	return who
 }
@@ -70,10 +70,10 @@ func province(who int) int {
 // subloc returns the immediate location (T_loc or T_ship) containing who.
 // Ignores stacked characters.
 // Ported from src/loc.c lines 167-178.
-func subloc(who int) int {
+func (e *Engine) subloc(who int) int {
 	for {
-		who = loc(who)
-		if who <= 0 || kind(who) == T_loc || kind(who) == T_ship {
+		who = e.loc(who)
+		if who <= 0 || e.kind(who) == T_loc || e.kind(who) == T_ship {
 			break
 		}
 	}
@@ -84,26 +84,26 @@ func subloc(who int) int {
 // Steps out from a location until reaching the appropriate viewing level.
 // Provinces see into everything except cities, graveyards, sewers, and faery hills.
 // Ported from src/loc.c lines 192-207.
-func viewloc(who int) int {
+func (e *Engine) viewloc(who int) int {
 	for who > 0 &&
-		loc_depth(who) != LOC_province &&
-		subkind(who) != sub_city &&
-		subkind(who) != sub_graveyard &&
-		subkind(who) != sub_sewer &&
-		subkind(who) != sub_faery_hill {
-		who = loc(who)
+		e.loc_depth(who) != LOC_province &&
+		e.subkind(who) != sub_city &&
+		e.subkind(who) != sub_graveyard &&
+		e.subkind(who) != sub_sewer &&
+		e.subkind(who) != sub_faery_hill {
+		who = e.loc(who)
 	}
 	return who
 }
 
 // in_safe_now returns true if who is anywhere inside a safe haven.
 // Ported from src/loc.c lines 210-224.
-func in_safe_now(who int) bool {
+func (e *Engine) in_safe_now(who int) bool {
 	for {
-		if safe_haven(who) != 0 {
+		if e.safe_haven(who) != 0 {
 			return true
 		}
-		who = loc(who)
+		who = e.loc(who)
 		if who <= 0 {
 			break
 		}
@@ -113,12 +113,12 @@ func in_safe_now(who int) bool {
 
 // somewhere_inside returns true if b is nested somewhere inside a.
 // Ported from src/loc.c lines 11-26.
-func somewhere_inside(a, b int) bool {
+func (e *Engine) somewhere_inside(a, b int) bool {
 	if a == b {
 		return false
 	}
 	for b > 0 {
-		b = loc(b)
+		b = e.loc(b)
 		if a == b {
 			return true
 		}
@@ -128,110 +128,110 @@ func somewhere_inside(a, b int) bool {
 
 // add_here recursively adds who and all entities in its here_list to l.
 // Ported from src/loc.c lines 29-44.
-func add_here(who int, l *[]int) {
-	if !valid_box(who) {
+func (e *Engine) add_here(who int, l *[]int) {
+	if !e.valid_box(who) {
 		panic("add_here: invalid box")
 	}
 
 	*l = append(*l, who)
 
-	p := rp_loc_info(who)
+	p := e.rp_loc_info(who)
 	if p == nil {
 		panic("add_here: nil loc_info")
 	}
 
 	for _, id := range p.here_list {
-		add_here(id, l)
+		e.add_here(id, l)
 	}
 }
 
 // all_here returns all entities at and below who.
 // Ported from src/loc.c lines 47-64.
-func all_here(who int, l *[]int) {
-	if !valid_box(who) {
+func (e *Engine) all_here(who int, l *[]int) {
+	if !e.valid_box(who) {
 		panic("all_here: invalid box")
 	}
 
 	*l = (*l)[:0] // clear the list
 
-	p := rp_loc_info(who)
+	p := e.rp_loc_info(who)
 	if p == nil {
 		return
 	}
 
 	for _, id := range p.here_list {
-		add_here(id, l)
+		e.add_here(id, l)
 	}
 }
 
 // add_char_here recursively adds who and all characters in its here_list to l.
 // Ported from src/loc.c lines 67-83.
-func add_char_here(who int, l *[]int) {
-	if !valid_box(who) {
+func (e *Engine) add_char_here(who int, l *[]int) {
+	if !e.valid_box(who) {
 		panic("add_char_here: invalid box")
 	}
 
 	*l = append(*l, who)
 
-	p := rp_loc_info(who)
+	p := e.rp_loc_info(who)
 	if p == nil {
 		panic("add_char_here: nil loc_info")
 	}
 
 	for _, id := range p.here_list {
-		if kind(id) == T_char {
-			add_char_here(id, l)
+		if e.kind(id) == T_char {
+			e.add_char_here(id, l)
 		}
 	}
 }
 
 // all_char_here returns all characters at and below who.
 // Ported from src/loc.c lines 86-104.
-func all_char_here(who int, l *[]int) {
-	if !valid_box(who) {
+func (e *Engine) all_char_here(who int, l *[]int) {
+	if !e.valid_box(who) {
 		panic("all_char_here: invalid box")
 	}
 
 	*l = (*l)[:0] // clear the list
 
-	p := rp_loc_info(who)
+	p := e.rp_loc_info(who)
 	if p == nil {
 		return
 	}
 
 	for _, id := range p.here_list {
-		if kind(id) == T_char {
-			add_char_here(id, l)
+		if e.kind(id) == T_char {
+			e.add_char_here(id, l)
 		}
 	}
 }
 
 // all_stack returns who plus all characters stacked under who.
 // Ported from src/loc.c lines 107-126.
-func all_stack(who int, l *[]int) {
-	if !valid_box(who) {
+func (e *Engine) all_stack(who int, l *[]int) {
+	if !e.valid_box(who) {
 		panic("all_stack: invalid box")
 	}
 
 	*l = (*l)[:0] // clear the list
 	*l = append(*l, who)
 
-	p := rp_loc_info(who)
+	p := e.rp_loc_info(who)
 	if p == nil {
 		return
 	}
 
 	for _, id := range p.here_list {
-		if kind(id) == T_char {
-			add_char_here(id, l)
+		if e.kind(id) == T_char {
+			e.add_char_here(id, l)
 		}
 	}
 }
 
 // in_here_list returns true if who is in the here_list of loc.
 // Ported from src/loc.c lines 298-309.
-func in_here_list(loc, who int) bool {
-	p := rp_loc_info(loc)
+func (e *Engine) in_here_list(loc, who int) bool {
+	p := e.rp_loc_info(loc)
 	if p == nil {
 		return false
 	}
@@ -240,60 +240,60 @@ func in_here_list(loc, who int) bool {
 
 // add_to_here_list adds who to the here_list of loc.
 // Ported from src/loc.c lines 227-234.
-func add_to_here_list(loc, who int) {
-	if in_here_list(loc, who) {
+func (e *Engine) add_to_here_list(loc, who int) {
+	if e.in_here_list(loc, who) {
 		panic("add_to_here_list: already in here_list")
 	}
-	p := p_loc_info(loc)
+	p := e.p_loc_info(loc)
 	p.here_list = append(p.here_list, who)
-	if !in_here_list(loc, who) {
+	if !e.in_here_list(loc, who) {
 		panic("add_to_here_list: failed to add")
 	}
 }
 
 // remove_from_here_list removes who from the here_list of loc.
 // Ported from src/loc.c lines 237-244.
-func remove_from_here_list(loc, who int) {
-	if !in_here_list(loc, who) {
+func (e *Engine) remove_from_here_list(loc, who int) {
+	if !e.in_here_list(loc, who) {
 		panic("remove_from_here_list: not in here_list")
 	}
-	p := rp_loc_info(loc)
+	p := e.rp_loc_info(loc)
 	IListRemValue(&p.here_list, who)
-	if in_here_list(loc, who) {
+	if e.in_here_list(loc, who) {
 		panic("remove_from_here_list: failed to remove")
 	}
 }
 
 // set_where moves who from its current location to new_loc.
 // Ported from src/loc.c lines 248-274.
-func set_where(who, new_loc int) {
+func (e *Engine) set_where(who, new_loc int) {
 	if who == new_loc {
 		panic("set_where: who == new_loc")
 	}
 
-	old_loc := loc(who)
+	old_loc := e.loc(who)
 
 	if old_loc > 0 {
-		remove_from_here_list(old_loc, who)
+		e.remove_from_here_list(old_loc, who)
 	}
 
 	if new_loc > 0 {
-		add_to_here_list(new_loc, who)
+		e.add_to_here_list(new_loc, who)
 	}
 
-	p_loc_info(who).where = new_loc
+	e.p_loc_info(who).where = new_loc
 }
 
 // first_character returns the first character in the here_list of where.
 // Ported from src/loc.c lines 312-329.
-func first_character(where int) int {
-	p := rp_loc_info(where)
+func (e *Engine) first_character(where int) int {
+	p := e.rp_loc_info(where)
 	if p == nil {
 		return 0
 	}
 
 	for _, id := range p.here_list {
-		if kind(id) == T_char {
+		if e.kind(id) == T_char {
 			return id
 		}
 	}
@@ -302,14 +302,14 @@ func first_character(where int) int {
 
 // subloc_here returns the first sublocation of subkind sk in the here_list of where.
 // Ported from src/loc.c lines 332-349.
-func subloc_here(where int, sk schar) int {
-	p := rp_loc_info(where)
+func (e *Engine) subloc_here(where int, sk schar) int {
+	p := e.rp_loc_info(where)
 	if p == nil {
 		return 0
 	}
 
 	for _, id := range p.here_list {
-		if kind(id) == T_loc && subkind(id) == sk {
+		if e.kind(id) == T_loc && e.subkind(id) == sk {
 			return id
 		}
 	}
@@ -318,15 +318,15 @@ func subloc_here(where int, sk schar) int {
 
 // count_loc_structures counts locations with subkind a or b in the here_list of where.
 // Ported from src/loc.c lines 352-366.
-func count_loc_structures(where int, a, b schar) int {
-	p := rp_loc_info(where)
+func (e *Engine) count_loc_structures(where int, a, b schar) int {
+	p := e.rp_loc_info(where)
 	if p == nil {
 		return 0
 	}
 
 	sum := 0
 	for _, id := range p.here_list {
-		if kind(id) == T_loc && (subkind(id) == a || subkind(id) == b) {
+		if e.kind(id) == T_loc && (e.subkind(id) == a || e.subkind(id) == b) {
 			sum++
 		}
 	}
@@ -335,39 +335,39 @@ func count_loc_structures(where int, a, b schar) int {
 
 // building_owner returns the first character in a building.
 // Ported from src/loc.c lines 391-397.
-func building_owner(where int) int {
-	if loc_depth(where) != LOC_build {
+func (e *Engine) building_owner(where int) int {
+	if e.loc_depth(where) != LOC_build {
 		panic("building_owner: not a building")
 	}
-	return first_character(where)
+	return e.first_character(where)
 }
 
 // city_here returns the city sublocation in the here_list of where.
 // Equivalent to C macro: #define city_here(a) subloc_here((a), sub_city)
-func city_here(where int) int {
-	return subloc_here(where, sub_city)
+func (e *Engine) city_here(where int) int {
+	return e.subloc_here(where, sub_city)
 }
 
 // mark_loc_stack_known marks that each member of a stack (or ship) has visited a location.
 // Ported from src/loc.c lines 281-295.
-func mark_loc_stack_known(stack, where int) {
-	if kind(stack) == T_char {
-		set_known(stack, where)
+func (e *Engine) mark_loc_stack_known(stack, where int) {
+	if e.kind(stack) == T_char {
+		e.set_known(stack, where)
 	}
 
 	var chars []int
-	all_char_here(stack, &chars)
+	e.all_char_here(stack, &chars)
 	for _, id := range chars {
-		if !is_prisoner(id) {
-			set_known(id, where)
+		if !e.is_prisoner(id) {
+			e.set_known(id, where)
 		}
 	}
 }
 
 // loc_hidden returns true if the location is hidden.
 // Ported from src/u.c lines 2284-2293.
-func loc_hidden(n int) bool {
-	l := rp_loc(n)
+func (e *Engine) loc_hidden(n int) bool {
+	l := e.rp_loc(n)
 	if l == nil {
 		return false
 	}
@@ -377,32 +377,32 @@ func loc_hidden(n int) bool {
 // nprovinces returns the total count of provinces in the world.
 // The result is cached after first computation.
 // Ported from src/u.c lines 2317-2332.
-func nprovinces() int {
-	if teg.globals.nprov > 0 {
-		return teg.globals.nprov
+func (e *Engine) nprovinces() int {
+	if e.globals.nprov > 0 {
+		return e.globals.nprov
 	}
 
-	for id := teg.KindFirst(T_loc); id > 0; id = teg.KindNext(id) {
-		if loc_depth(id) == LOC_province {
-			teg.globals.nprov++
+	for id := e.KindFirst(T_loc); id > 0; id = e.KindNext(id) {
+		if e.loc_depth(id) == LOC_province {
+			e.globals.nprov++
 		}
 	}
-	return teg.globals.nprov
+	return e.globals.nprov
 }
 
 // greater_region returns the "greater region" containing who.
 // Returns 0 for normal world regions (not Faery, Hades, Nowhere, Cloud, Tunnel, or Under).
 // Returns the special region ID for entities in those realms.
 // Ported from src/u.c lines 2415-2430.
-func greater_region(who int) int {
-	reg := region(who)
-
-	if reg != teg.globals.faeryRegion &&
-		reg != teg.globals.hadesRegion &&
-		reg != teg.globals.nowhereRegion &&
-		reg != teg.globals.cloudRegion &&
-		reg != teg.globals.tunnelRegion &&
-		reg != teg.globals.underRegion {
+func (e *Engine) greater_region(who int) int {
+	reg := e.region(who)
+
+	if reg != e.globals.faeryRegion &&
+		reg != e.globals.hadesRegion &&
+		reg != e.globals.nowhereRegion &&
+		reg != e.globals.cloudRegion &&
+		reg != e.globals.tunnelRegion &&
+		reg != e.globals.underRegion {
 		return 0
 	}
 	return reg
@@ -411,15 +411,15 @@ func greater_region(who int) int {
 // diff_region returns true if two entities are in different greater regions.
 // Used to check if teleportation is possible between locations.
 // Ported from src/u.c lines 2433-2436.
-func diff_region(a, b int) bool {
-	return greater_region(a) != greater_region(b)
+func (e *Engine) diff_region(a, b int) bool {
+	return e.greater_region(a) != e.greater_region(b)
 }
 
 // clear_temps clears the temp field on all entities of the given kind.
 // Ported from src/u.c lines 1213-1222.
-func clear_temps(k schar) {
-	for id := teg.KindFirst(int(k)); id > 0; id = teg.KindNext(id) {
-		if b := teg.globals.bx[id]; b != nil {
+func (e *Engine) clear_temps(k schar) {
+	for id := e.KindFirst(int(k)); id > 0; id = e.KindNext(id) {
+		if b := e.globals.bx.get(id); b != nil {
 			b.temp = 0
 		}
 	}
@@ -465,18 +465,18 @@ func strcasecmp(a, b string) int {
 }
 
 // in_faery returns true if n is in the Faery region.
-func in_faery(n int) bool {
-	return region(n) == teg.globals.faeryRegion
+func (e *Engine) in_faery(n int) bool {
+	return e.region(n) == e.globals.faeryRegion
 }
 
 // in_hades returns true if n is in the Hades region.
-func in_hades(n int) bool {
-	return region(n) == teg.globals.hadesRegion
+func (e *Engine) in_hades(n int) bool {
+	return e.region(n) == e.globals.hadesRegion
 }
 
 // in_clouds returns true if n is in the Cloud region.
-func in_clouds(n int) bool {
-	return region(n) == teg.globals.cloudRegion
+func (e *Engine) in_clouds(n int) bool {
+	return e.region(n) == e.globals.cloudRegion
 }
 
 
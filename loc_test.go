@@ -32,37 +32,37 @@ func setupLocTestWorld(t *testing.T) func() {
 	t.Helper()
 
 	// Initialize boxes
-	teg.globals.bx[1000] = &box{kind: T_loc, skind: sub_region}
-	teg.globals.bx[1001] = &box{kind: T_loc, skind: sub_forest}
-	teg.globals.bx[1002] = &box{kind: T_loc, skind: sub_city}
-	teg.globals.bx[1003] = &box{kind: T_loc, skind: sub_castle}
-	teg.globals.bx[1004] = &box{kind: T_loc, skind: sub_graveyard}
-	teg.globals.bx[2001] = &box{kind: T_char}
-	teg.globals.bx[2002] = &box{kind: T_char}
-	teg.globals.bx[2003] = &box{kind: T_char}
+	teg.setBox(1000, &box{kind: T_loc, skind: sub_region})
+	teg.setBox(1001, &box{kind: T_loc, skind: sub_forest})
+	teg.setBox(1002, &box{kind: T_loc, skind: sub_city})
+	teg.setBox(1003, &box{kind: T_loc, skind: sub_castle})
+	teg.setBox(1004, &box{kind: T_loc, skind: sub_graveyard})
+	teg.setBox(2001, &box{kind: T_char})
+	teg.setBox(2002, &box{kind: T_char})
+	teg.setBox(2003, &box{kind: T_char})
 
 	// Set location hierarchy via x_loc_info.where
-	teg.globals.bx[1000].x_loc_info.where = 0    // region has no parent
-	teg.globals.bx[1001].x_loc_info.where = 1000 // province in region
-	teg.globals.bx[1002].x_loc_info.where = 1001 // city in province
-	teg.globals.bx[1003].x_loc_info.where = 1002 // castle in city
-	teg.globals.bx[1004].x_loc_info.where = 1001 // graveyard in province
-	teg.globals.bx[2001].x_loc_info.where = 1003 // char in castle
-	teg.globals.bx[2002].x_loc_info.where = 1001 // char in province
-	teg.globals.bx[2003].x_loc_info.where = 2002 // char stacked under char
+	teg.globals.bx.get(1000).x_loc_info.where = 0    // region has no parent
+	teg.globals.bx.get(1001).x_loc_info.where = 1000 // province in region
+	teg.globals.bx.get(1002).x_loc_info.where = 1001 // city in province
+	teg.globals.bx.get(1003).x_loc_info.where = 1002 // castle in city
+	teg.globals.bx.get(1004).x_loc_info.where = 1001 // graveyard in province
+	teg.globals.bx.get(2001).x_loc_info.where = 1003 // char in castle
+	teg.globals.bx.get(2002).x_loc_info.where = 1001 // char in province
+	teg.globals.bx.get(2003).x_loc_info.where = 2002 // char stacked under char
 
 	// Mark city as safe haven
-	teg.globals.bx[1002].x_subloc = &entity_subloc{safe: 1}
+	teg.globals.bx.get(1002).x_subloc = &entity_subloc{safe: 1}
 
 	return func() {
-		teg.globals.bx[1000] = nil
-		teg.globals.bx[1001] = nil
-		teg.globals.bx[1002] = nil
-		teg.globals.bx[1003] = nil
-		teg.globals.bx[1004] = nil
-		teg.globals.bx[2001] = nil
-		teg.globals.bx[2002] = nil
-		teg.globals.bx[2003] = nil
+		teg.deleteBox(1000)
+		teg.deleteBox(1001)
+		teg.deleteBox(1002)
+		teg.deleteBox(1003)
+		teg.deleteBox(1004)
+		teg.deleteBox(2001)
+		teg.deleteBox(2002)
+		teg.deleteBox(2003)
 	}
 }
 
@@ -86,7 +86,7 @@ func TestLocDepth(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := loc_depth(tt.id)
+			got := teg.loc_depth(tt.id)
 			if got != tt.expected {
 				t.Errorf("loc_depth(%d) = %d, want %d", tt.id, got, tt.expected)
 			}
@@ -115,7 +115,7 @@ func TestRegion(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := region(tt.who)
+			got := teg.region(tt.who)
 			if got != tt.expected {
 				t.Errorf("region(%d) = %d, want %d", tt.who, got, tt.expected)
 			}
@@ -143,7 +143,7 @@ func TestProvince(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := province(tt.who)
+			got := teg.province(tt.who)
 			if got != tt.expected {
 				t.Errorf("province(%d) = %d, want %d", tt.who, got, tt.expected)
 			}
@@ -171,7 +171,7 @@ func TestSubloc(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := subloc(tt.who)
+			got := teg.subloc(tt.who)
 			if got != tt.expected {
 				t.Errorf("subloc(%d) = %d, want %d", tt.who, got, tt.expected)
 			}
@@ -198,7 +198,7 @@ func TestViewloc(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := viewloc(tt.who)
+			got := teg.viewloc(tt.who)
 			if got != tt.expected {
 				t.Errorf("viewloc(%d) = %d, want %d", tt.who, got, tt.expected)
 			}
@@ -226,7 +226,7 @@ func TestInSafeNow(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := in_safe_now(tt.who)
+			got := teg.in_safe_now(tt.who)
 			if got != tt.expected {
 				t.Errorf("in_safe_now(%d) = %v, want %v", tt.who, got, tt.expected)
 			}
@@ -256,7 +256,7 @@ func TestSomewhereInside(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := somewhere_inside(tt.a, tt.b)
+			got := teg.somewhere_inside(tt.a, tt.b)
 			if got != tt.expected {
 				t.Errorf("somewhere_inside(%d, %d) = %v, want %v", tt.a, tt.b, got, tt.expected)
 			}
@@ -300,12 +300,12 @@ func TestLocDepthSubkinds(t *testing.T) {
 
 	for _, tt := range tests {
 		// Create a temporary box with this subkind
-		teg.globals.bx[9999] = &box{kind: T_loc, skind: tt.subkind}
-		got := loc_depth(9999)
+		teg.setBox(9999, &box{kind: T_loc, skind: tt.subkind})
+		got := teg.loc_depth(9999)
 		if got != tt.expected {
 			t.Errorf("loc_depth for subkind %d = %d, want %d", tt.subkind, got, tt.expected)
 		}
-		teg.globals.bx[9999] = nil
+		teg.deleteBox(9999)
 	}
 }
 
@@ -324,48 +324,48 @@ func setupHereListTestWorld(t *testing.T) func() {
 	t.Helper()
 
 	// Initialize boxes
-	teg.globals.bx[1001] = &box{kind: T_loc, skind: sub_forest}
-	teg.globals.bx[1002] = &box{kind: T_loc, skind: sub_city}
-	teg.globals.bx[1003] = &box{kind: T_loc, skind: sub_castle}
-	teg.globals.bx[1004] = &box{kind: T_loc, skind: sub_graveyard}
-	teg.globals.bx[1005] = &box{kind: T_loc, skind: sub_inn}
-	teg.globals.bx[2001] = &box{kind: T_char}
-	teg.globals.bx[2002] = &box{kind: T_char}
-	teg.globals.bx[2003] = &box{kind: T_char}
-	teg.globals.bx[3001] = &box{kind: T_item}
+	teg.setBox(1001, &box{kind: T_loc, skind: sub_forest})
+	teg.setBox(1002, &box{kind: T_loc, skind: sub_city})
+	teg.setBox(1003, &box{kind: T_loc, skind: sub_castle})
+	teg.setBox(1004, &box{kind: T_loc, skind: sub_graveyard})
+	teg.setBox(1005, &box{kind: T_loc, skind: sub_inn})
+	teg.setBox(2001, &box{kind: T_char})
+	teg.setBox(2002, &box{kind: T_char})
+	teg.setBox(2003, &box{kind: T_char})
+	teg.setBox(3001, &box{kind: T_item})
 
 	// Set location hierarchy via x_loc_info.where
-	teg.globals.bx[1001].x_loc_info.where = 0    // province has no parent (for this test)
-	teg.globals.bx[1002].x_loc_info.where = 1001 // city in province
-	teg.globals.bx[1003].x_loc_info.where = 1002 // castle in city
-	teg.globals.bx[1004].x_loc_info.where = 1001 // graveyard in province
-	teg.globals.bx[1005].x_loc_info.where = 1002 // inn in city
-	teg.globals.bx[2001].x_loc_info.where = 1003 // char in castle
-	teg.globals.bx[2002].x_loc_info.where = 1001 // char in province
-	teg.globals.bx[2003].x_loc_info.where = 2002 // char stacked under char
-	teg.globals.bx[3001].x_loc_info.where = 1001 // item in province
+	teg.globals.bx.get(1001).x_loc_info.where = 0    // province has no parent (for this test)
+	teg.globals.bx.get(1002).x_loc_info.where = 1001 // city in province
+	teg.globals.bx.get(1003).x_loc_info.where = 1002 // castle in city
+	teg.globals.bx.get(1004).x_loc_info.where = 1001 // graveyard in province
+	teg.globals.bx.get(1005).x_loc_info.where = 1002 // inn in city
+	teg.globals.bx.get(2001).x_loc_info.where = 1003 // char in castle
+	teg.globals.bx.get(2002).x_loc_info.where = 1001 // char in province
+	teg.globals.bx.get(2003).x_loc_info.where = 2002 // char stacked under char
+	teg.globals.bx.get(3001).x_loc_info.where = 1001 // item in province
 
 	// Set here_lists
-	teg.globals.bx[1001].x_loc_info.here_list = []int{1002, 1004, 2002, 3001}
-	teg.globals.bx[1002].x_loc_info.here_list = []int{1003, 1005}
-	teg.globals.bx[1003].x_loc_info.here_list = []int{2001}
-	teg.globals.bx[1004].x_loc_info.here_list = []int{}
-	teg.globals.bx[1005].x_loc_info.here_list = []int{}
-	teg.globals.bx[2001].x_loc_info.here_list = []int{}
-	teg.globals.bx[2002].x_loc_info.here_list = []int{2003}
-	teg.globals.bx[2003].x_loc_info.here_list = []int{}
-	teg.globals.bx[3001].x_loc_info.here_list = []int{}
+	teg.globals.bx.get(1001).x_loc_info.here_list = []int{1002, 1004, 2002, 3001}
+	teg.globals.bx.get(1002).x_loc_info.here_list = []int{1003, 1005}
+	teg.globals.bx.get(1003).x_loc_info.here_list = []int{2001}
+	teg.globals.bx.get(1004).x_loc_info.here_list = []int{}
+	teg.globals.bx.get(1005).x_loc_info.here_list = []int{}
+	teg.globals.bx.get(2001).x_loc_info.here_list = []int{}
+	teg.globals.bx.get(2002).x_loc_info.here_list = []int{2003}
+	teg.globals.bx.get(2003).x_loc_info.here_list = []int{}
+	teg.globals.bx.get(3001).x_loc_info.here_list = []int{}
 
 	return func() {
-		teg.globals.bx[1001] = nil
-		teg.globals.bx[1002] = nil
-		teg.globals.bx[1003] = nil
-		teg.globals.bx[1004] = nil
-		teg.globals.bx[1005] = nil
-		teg.globals.bx[2001] = nil
-		teg.globals.bx[2002] = nil
-		teg.globals.bx[2003] = nil
-		teg.globals.bx[3001] = nil
+		teg.deleteBox(1001)
+		teg.deleteBox(1002)
+		teg.deleteBox(1003)
+		teg.deleteBox(1004)
+		teg.deleteBox(1005)
+		teg.deleteBox(2001)
+		teg.deleteBox(2002)
+		teg.deleteBox(2003)
+		teg.deleteBox(3001)
 	}
 }
 
@@ -392,7 +392,7 @@ func TestInHereList(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := in_here_list(tt.loc, tt.who)
+			got := teg.in_here_list(tt.loc, tt.who)
 			if got != tt.expected {
 				t.Errorf("in_here_list(%d, %d) = %v, want %v", tt.loc, tt.who, got, tt.expected)
 			}
@@ -407,7 +407,7 @@ func TestAllHere(t *testing.T) {
 	var result []int
 
 	// Test all_here from province - should get everything nested below
-	all_here(1001, &result)
+	teg.all_here(1001, &result)
 
 	// Expected: 1002, 1003, 2001, 1005, 1004, 2002, 2003, 3001
 	// Order depends on recursion order
@@ -427,7 +427,7 @@ func TestAllHere(t *testing.T) {
 	}
 
 	// Test all_here from city - should get castle, inn, and char in castle
-	all_here(1002, &result)
+	teg.all_here(1002, &result)
 	expectedCity := map[int]bool{1003: true, 1005: true, 2001: true}
 
 	if len(result) != len(expectedCity) {
@@ -441,7 +441,7 @@ func TestAllHere(t *testing.T) {
 	}
 
 	// Test all_here from empty location (graveyard)
-	all_here(1004, &result)
+	teg.all_here(1004, &result)
 	if len(result) != 0 {
 		t.Errorf("all_here(1004) returned %d items, want 0", len(result))
 	}
@@ -454,7 +454,7 @@ func TestAllCharHere(t *testing.T) {
 	var result []int
 
 	// Test all_char_here from province - should get only characters
-	all_char_here(1001, &result)
+	teg.all_char_here(1001, &result)
 
 	// Expected: 2002, 2003 (not 2001 because it's not directly in province's here_list)
 	expected := map[int]bool{2002: true, 2003: true}
@@ -470,13 +470,13 @@ func TestAllCharHere(t *testing.T) {
 	}
 
 	// Test all_char_here from castle - should get char 2001
-	all_char_here(1003, &result)
+	teg.all_char_here(1003, &result)
 	if len(result) != 1 || result[0] != 2001 {
 		t.Errorf("all_char_here(1003) = %v, want [2001]", result)
 	}
 
 	// Test all_char_here from empty graveyard
-	all_char_here(1004, &result)
+	teg.all_char_here(1004, &result)
 	if len(result) != 0 {
 		t.Errorf("all_char_here(1004) returned %d items, want 0", len(result))
 	}
@@ -489,7 +489,7 @@ func TestAllStack(t *testing.T) {
 	var result []int
 
 	// Test all_stack from char 2002 - should get 2002 and 2003
-	all_stack(2002, &result)
+	teg.all_stack(2002, &result)
 
 	if len(result) != 2 {
 		t.Errorf("all_stack(2002) returned %d items, want 2: got %v", len(result), result)
@@ -499,7 +499,7 @@ func TestAllStack(t *testing.T) {
 	}
 
 	// Test all_stack from char with no stack
-	all_stack(2001, &result)
+	teg.all_stack(2001, &result)
 	if len(result) != 1 || result[0] != 2001 {
 		t.Errorf("all_stack(2001) = %v, want [2001]", result)
 	}
@@ -522,7 +522,7 @@ func TestFirstCharacter(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := first_character(tt.where)
+			got := teg.first_character(tt.where)
 			if got != tt.expected {
 				t.Errorf("first_character(%d) = %d, want %d", tt.where, got, tt.expected)
 			}
@@ -550,7 +550,7 @@ func TestSublocHere(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := subloc_here(tt.where, tt.sk)
+			got := teg.subloc_here(tt.where, tt.sk)
 			if got != tt.expected {
 				t.Errorf("subloc_here(%d, %d) = %d, want %d", tt.where, tt.sk, got, tt.expected)
 			}
@@ -576,7 +576,7 @@ func TestCountLocStructures(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := count_loc_structures(tt.where, tt.a, tt.b)
+			got := teg.count_loc_structures(tt.where, tt.a, tt.b)
 			if got != tt.expected {
 				t.Errorf("count_loc_structures(%d, %d, %d) = %d, want %d", tt.where, tt.a, tt.b, got, tt.expected)
 			}
@@ -588,12 +588,12 @@ func TestCityHere(t *testing.T) {
 	cleanup := setupHereListTestWorld(t)
 	defer cleanup()
 
-	got := city_here(1001)
+	got := teg.city_here(1001)
 	if got != 1002 {
 		t.Errorf("city_here(1001) = %d, want 1002", got)
 	}
 
-	got = city_here(1002)
+	got = teg.city_here(1002)
 	if got != 0 {
 		t.Errorf("city_here(1002) = %d, want 0", got)
 	}
@@ -604,13 +604,13 @@ func TestBuildingOwner(t *testing.T) {
 	defer cleanup()
 
 	// Castle 1003 has char 2001 in it
-	got := building_owner(1003)
+	got := teg.building_owner(1003)
 	if got != 2001 {
 		t.Errorf("building_owner(1003) = %d, want 2001", got)
 	}
 
 	// Inn 1005 is empty
-	got = building_owner(1005)
+	got = teg.building_owner(1005)
 	if got != 0 {
 		t.Errorf("building_owner(1005) = %d, want 0", got)
 	}
@@ -621,20 +621,20 @@ func TestAddRemoveHereList(t *testing.T) {
 	defer cleanup()
 
 	// Create a new character
-	teg.globals.bx[2004] = &box{kind: T_char}
-	defer func() { teg.globals.bx[2004] = nil }()
+	teg.setBox(2004, &box{kind: T_char})
+	defer func() { teg.deleteBox(2004) }()
 
 	// Add to graveyard's here_list
-	add_to_here_list(1004, 2004)
+	teg.add_to_here_list(1004, 2004)
 
-	if !in_here_list(1004, 2004) {
+	if !teg.in_here_list(1004, 2004) {
 		t.Error("add_to_here_list failed: 2004 not in 1004's here_list")
 	}
 
 	// Remove from here_list
-	remove_from_here_list(1004, 2004)
+	teg.remove_from_here_list(1004, 2004)
 
-	if in_here_list(1004, 2004) {
+	if teg.in_here_list(1004, 2004) {
 		t.Error("remove_from_here_list failed: 2004 still in 1004's here_list")
 	}
 }
@@ -644,35 +644,35 @@ func TestSetWhere(t *testing.T) {
 	defer cleanup()
 
 	// Create a new character in graveyard
-	teg.globals.bx[2004] = &box{kind: T_char}
-	teg.globals.bx[2004].x_loc_info.where = 1004
-	teg.globals.bx[1004].x_loc_info.here_list = []int{2004}
-	defer func() { teg.globals.bx[2004] = nil }()
+	teg.setBox(2004, &box{kind: T_char})
+	teg.globals.bx.get(2004).x_loc_info.where = 1004
+	teg.globals.bx.get(1004).x_loc_info.here_list = []int{2004}
+	defer func() { teg.deleteBox(2004) }()
 
 	// Verify initial state
-	if !in_here_list(1004, 2004) {
+	if !teg.in_here_list(1004, 2004) {
 		t.Fatal("setup failed: 2004 not in graveyard's here_list")
 	}
-	if loc(2004) != 1004 {
-		t.Fatalf("setup failed: loc(2004) = %d, want 1004", loc(2004))
+	if teg.loc(2004) != 1004 {
+		t.Fatalf("setup failed: loc(2004) = %d, want 1004", teg.loc(2004))
 	}
 
 	// Move character from graveyard to castle
-	set_where(2004, 1003)
+	teg.set_where(2004, 1003)
 
 	// Check old location
-	if in_here_list(1004, 2004) {
+	if teg.in_here_list(1004, 2004) {
 		t.Error("set_where failed: 2004 still in graveyard's here_list")
 	}
 
 	// Check new location
-	if !in_here_list(1003, 2004) {
+	if !teg.in_here_list(1003, 2004) {
 		t.Error("set_where failed: 2004 not in castle's here_list")
 	}
 
 	// Check where field
-	if loc(2004) != 1003 {
-		t.Errorf("set_where failed: loc(2004) = %d, want 1003", loc(2004))
+	if teg.loc(2004) != 1003 {
+		t.Errorf("set_where failed: loc(2004) = %d, want 1003", teg.loc(2004))
 	}
 }
 
@@ -708,42 +708,42 @@ func setupRegionTestWorld(t *testing.T) func() {
 	teg.globals.nprov = 0 // reset cache
 
 	// Normal region
-	teg.globals.bx[1000] = &box{kind: T_loc, skind: sub_region}
-	teg.globals.bx[1001] = &box{kind: T_loc, skind: sub_forest}
-	teg.globals.bx[2001] = &box{kind: T_char}
+	teg.setBox(1000, &box{kind: T_loc, skind: sub_region})
+	teg.setBox(1001, &box{kind: T_loc, skind: sub_forest})
+	teg.setBox(2001, &box{kind: T_char})
 
-	teg.globals.bx[1000].x_loc_info.where = 0
-	teg.globals.bx[1001].x_loc_info.where = 1000
-	teg.globals.bx[2001].x_loc_info.where = 1001
+	teg.globals.bx.get(1000).x_loc_info.where = 0
+	teg.globals.bx.get(1001).x_loc_info.where = 1000
+	teg.globals.bx.get(2001).x_loc_info.where = 1001
 
 	// Faery region
-	teg.globals.bx[1100] = &box{kind: T_loc, skind: sub_region}
-	teg.globals.bx[1101] = &box{kind: T_loc, skind: sub_forest}
-	teg.globals.bx[2101] = &box{kind: T_char}
+	teg.setBox(1100, &box{kind: T_loc, skind: sub_region})
+	teg.setBox(1101, &box{kind: T_loc, skind: sub_forest})
+	teg.setBox(2101, &box{kind: T_char})
 
-	teg.globals.bx[1100].x_loc_info.where = 0
-	teg.globals.bx[1101].x_loc_info.where = 1100
-	teg.globals.bx[2101].x_loc_info.where = 1101
+	teg.globals.bx.get(1100).x_loc_info.where = 0
+	teg.globals.bx.get(1101).x_loc_info.where = 1100
+	teg.globals.bx.get(2101).x_loc_info.where = 1101
 
 	// Hades region with hidden province
-	teg.globals.bx[1200] = &box{kind: T_loc, skind: sub_region}
-	teg.globals.bx[1201] = &box{kind: T_loc, skind: sub_forest, x_loc: &entity_loc{hidden: 1}}
-	teg.globals.bx[2201] = &box{kind: T_char}
+	teg.setBox(1200, &box{kind: T_loc, skind: sub_region})
+	teg.setBox(1201, &box{kind: T_loc, skind: sub_forest, x_loc: &entity_loc{hidden: 1}})
+	teg.setBox(2201, &box{kind: T_char})
 
-	teg.globals.bx[1200].x_loc_info.where = 0
-	teg.globals.bx[1201].x_loc_info.where = 1200
-	teg.globals.bx[2201].x_loc_info.where = 1201
+	teg.globals.bx.get(1200).x_loc_info.where = 0
+	teg.globals.bx.get(1201).x_loc_info.where = 1200
+	teg.globals.bx.get(2201).x_loc_info.where = 1201
 
 	return func() {
-		teg.globals.bx[1000] = nil
-		teg.globals.bx[1001] = nil
-		teg.globals.bx[2001] = nil
-		teg.globals.bx[1100] = nil
-		teg.globals.bx[1101] = nil
-		teg.globals.bx[2101] = nil
-		teg.globals.bx[1200] = nil
-		teg.globals.bx[1201] = nil
-		teg.globals.bx[2201] = nil
+		teg.deleteBox(1000)
+		teg.deleteBox(1001)
+		teg.deleteBox(2001)
+		teg.deleteBox(1100)
+		teg.deleteBox(1101)
+		teg.deleteBox(2101)
+		teg.deleteBox(1200)
+		teg.deleteBox(1201)
+		teg.deleteBox(2201)
 
 		teg.globals.faeryRegion = oldFaery
 		teg.globals.hadesRegion = oldHades
@@ -773,7 +773,7 @@ func TestLocHidden(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := loc_hidden(tt.id)
+			got := teg.loc_hidden(tt.id)
 			if got != tt.expected {
 				t.Errorf("loc_hidden(%d) = %v, want %v", tt.id, got, tt.expected)
 			}
@@ -803,7 +803,7 @@ func TestGreaterRegion(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := greater_region(tt.who)
+			got := teg.greater_region(tt.who)
 			if got != tt.expected {
 				t.Errorf("greater_region(%d) = %d, want %d", tt.who, got, tt.expected)
 			}
@@ -829,7 +829,7 @@ func TestDiffRegionGreater(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := diff_region(tt.a, tt.b)
+			got := teg.diff_region(tt.a, tt.b)
 			if got != tt.expected {
 				t.Errorf("diff_region(%d, %d) = %v, want %v", tt.a, tt.b, got, tt.expected)
 			}
@@ -841,13 +841,13 @@ func TestInFaery(t *testing.T) {
 	cleanup := setupRegionTestWorld(t)
 	defer cleanup()
 
-	if in_faery(2001) {
+	if teg.in_faery(2001) {
 		t.Error("in_faery(2001) should be false for normal region")
 	}
-	if !in_faery(2101) {
+	if !teg.in_faery(2101) {
 		t.Error("in_faery(2101) should be true for faery region")
 	}
-	if in_faery(2201) {
+	if teg.in_faery(2201) {
 		t.Error("in_faery(2201) should be false for hades region")
 	}
 }
@@ -856,13 +856,13 @@ func TestInHades(t *testing.T) {
 	cleanup := setupRegionTestWorld(t)
 	defer cleanup()
 
-	if in_hades(2001) {
+	if teg.in_hades(2001) {
 		t.Error("in_hades(2001) should be false for normal region")
 	}
-	if in_hades(2101) {
+	if teg.in_hades(2101) {
 		t.Error("in_hades(2101) should be false for faery region")
 	}
-	if !in_hades(2201) {
+	if !teg.in_hades(2201) {
 		t.Error("in_hades(2201) should be true for hades region")
 	}
 }
@@ -899,30 +899,30 @@ func TestClearTemps(t *testing.T) {
 	defer cleanup()
 
 	// Set temp values on locations
-	teg.globals.bx[1000].temp = 42
-	teg.globals.bx[1001].temp = 99
-	teg.globals.bx[1100].temp = 7
+	teg.globals.bx.get(1000).temp = 42
+	teg.globals.bx.get(1001).temp = 99
+	teg.globals.bx.get(1100).temp = 7
 
 	// Need to set up kind chain for iteration
 	teg.globals.box_head[T_loc] = 1000
-	teg.globals.bx[1000].x_next_kind = 1001
-	teg.globals.bx[1001].x_next_kind = 1100
-	teg.globals.bx[1100].x_next_kind = 1101
-	teg.globals.bx[1101].x_next_kind = 1200
-	teg.globals.bx[1200].x_next_kind = 1201
-	teg.globals.bx[1201].x_next_kind = 0
+	teg.globals.bx.get(1000).x_next_kind = 1001
+	teg.globals.bx.get(1001).x_next_kind = 1100
+	teg.globals.bx.get(1100).x_next_kind = 1101
+	teg.globals.bx.get(1101).x_next_kind = 1200
+	teg.globals.bx.get(1200).x_next_kind = 1201
+	teg.globals.bx.get(1201).x_next_kind = 0
 
 	// Set temp on one
-	teg.globals.bx[1001].temp = 123
+	teg.globals.bx.get(1001).temp = 123
 
 	// Clear temps for T_loc
-	clear_temps(T_loc)
+	teg.clear_temps(T_loc)
 
 	// Verify all are cleared
 	locs := []int{1000, 1001, 1100, 1101, 1200, 1201}
 	for _, id := range locs {
-		if teg.globals.bx[id].temp != 0 {
-			t.Errorf("clear_temps: bx[%d].temp = %d, want 0", id, teg.globals.bx[id].temp)
+		if teg.globals.bx.get(id).temp != 0 {
+			t.Errorf("clear_temps: bx[%d].temp = %d, want 0", id, teg.globals.bx.get(id).temp)
 		}
 	}
 }
@@ -0,0 +1,427 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// lore.go - Lore sheet delivery ported from src/lore.c
+
+package taygete
+
+import (
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// report_out appends a line to the turn report of who's player. Like
+// the C output routines, a '~' in the line is a non-breaking space.
+func (e *Engine) report_out(who int, format string, args ...any) {
+	pl := e.player(who)
+	if pl == 0 {
+		return
+	}
+
+	if e.globals.reports == nil {
+		e.globals.reports = make(map[int][]string)
+	}
+
+	line := fmt.Sprintf(format, args...)
+	line = strings.ReplaceAll(line, "~", " ")
+
+	e.globals.reports[pl] = append(e.globals.reports[pl], line)
+}
+
+// match_lines writes s underlined with dashes.
+// Port of C match_lines() from u.c.
+func (e *Engine) match_lines(who int, s string) {
+	e.report_out(who, "%s", s)
+	e.report_out(who, "%s", strings.Repeat("-", len(s)))
+}
+
+// lore_function expands a $function line in a lore sheet.
+// Ported from src/lore.c lines 9-42.
+func (e *Engine) lore_function(who int, fn string) {
+	switch fn {
+	case "capturable_animals":
+		for _, i := range e.Items() {
+			if e.item_animal(i) != 0 && e.item_capturable(i) != 0 {
+				e.report_out(who, "    %s", e.box_name(i))
+			}
+		}
+
+	case "animal_fighters":
+		for _, i := range e.Items() {
+			if e.item_animal(i) != 0 && e.is_fighter(i) {
+				e.report_out(who, "    %-20s %s", e.box_name(i),
+					sout("(%d,%d,%d)", e.item_attack(i),
+						e.item_defense(i), e.item_missile(i)))
+			}
+		}
+
+	default:
+		log_write(LOG_CODE, "bad lore sheet function: %s", fn)
+	}
+}
+
+// deliver_lore_sheet writes lore sheet num to who's report under the
+// heading of display_num. Lines starting with '#' are comments, '$'
+// runs a lore function, and '@' stands for display_num's code.
+// Ported from src/lore.c lines 45-118.
+func (e *Engine) deliver_lore_sheet(who, num, display_num int) {
+	e.report_out(who, "")
+	e.match_lines(who, e.box_name(display_num))
+
+	lines, ok := e.globals.loreSheets[num]
+	if !ok {
+		e.report_out(who, "<lore sheet not available>")
+		log_write(LOG_CODE, "no lore sheet for %d", num)
+		return
+	}
+
+	code := box_code_less(display_num)
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "$") {
+			e.lore_function(who, line[1:])
+			continue
+		}
+
+		var b strings.Builder
+		for i := 0; i < len(line); i++ {
+			if line[i] != '@' {
+				b.WriteByte(line[i])
+				continue
+			}
+			b.WriteString(code)
+			for i+1 < len(line) && line[i+1] == '@' {
+				i++
+			}
+		}
+
+		e.report_out(who, "%s", b.String())
+	}
+}
+
+// np_req_s describes the noble points needed to learn skill.
+// Ported from src/lore.c lines 121-135.
+func (e *Engine) np_req_s(skill int) string {
+	np := e.skill_np_req(skill)
+
+	if np < 1 {
+		return ""
+	}
+
+	if np == 1 {
+		return ", 1 NP req'd"
+	}
+
+	return sout(", %d NP req'd", np)
+}
+
+// out_skill_line writes one row of a skill table.
+// Ported from src/lore.c lines 138-147.
+func (e *Engine) out_skill_line(who, sk int, indent string) {
+	e.report_out(who, "%s%-*s  %-34s %s%s", indent,
+		CHAR_FIELD, box_code_less(sk),
+		e.just_name(sk),
+		weeks(e.learn_time(sk)),
+		e.np_req_s(sk))
+}
+
+// deliver_skill_lore writes the lore sheet for a skill, followed by
+// the skills it leads to.
+// Ported from src/lore.c lines 150-200.
+func (e *Engine) deliver_skill_lore(who, sk int, show_research bool) {
+	e.deliver_lore_sheet(who, sk, sk)
+
+	p := e.rp_skill(sk)
+	const indent = "   "
+
+	if p != nil && len(p.offered) > 0 {
+		e.report_out(who, "")
+		e.report_out(who, "The following skills may be studied directly "+
+			"once %s is known:", e.just_name(sk))
+		e.report_out(who, "")
+
+		e.report_out(who, "%s%-*s  %-34s %13s", indent,
+			CHAR_FIELD, "num", "skill", "time to learn")
+		e.report_out(who, "%s%-*s  %-34s %13s", indent,
+			CHAR_FIELD, "---", "-----", "-------------")
+
+		for _, i := range p.offered {
+			e.out_skill_line(who, i, indent)
+		}
+
+		e.report_out(who, "")
+		if len(p.research) > 0 {
+			e.report_out(who, "Further skills may be found through research.")
+		}
+	} else if p != nil && len(p.research) > 0 {
+		e.report_out(who, "")
+		e.report_out(who, "Skills within this category may be found "+
+			"through research.")
+	}
+
+	if show_research && p != nil && len(p.research) > 0 {
+		e.report_out(who, "")
+
+		for _, i := range p.research {
+			e.out_skill_line(who, i, indent)
+		}
+	}
+}
+
+// deliver_lore writes the lore sheet for a skill, item or other
+// entity to who's report.
+// Ported from src/lore.c lines 203-222.
+func (e *Engine) deliver_lore(who, num int) {
+	switch e.kind(num) {
+	case T_skill:
+		e.deliver_skill_lore(who, num, false)
+	case T_item:
+		e.deliver_lore_sheet(who, e.item_lore(num), num)
+	default:
+		e.deliver_lore_sheet(who, num, num)
+	}
+
+	e.report_out(who, "")
+}
+
+// queue_lore queues a lore sheet for who's player. Unless anyway is
+// set, a player only sees each sheet once.
+// Ported from src/lore.c lines 231-246.
+func (e *Engine) queue_lore(who, num int, anyway bool) {
+	pl := e.player(who)
+	if e.kind(pl) != T_player {
+		return
+	}
+
+	if !anyway && e.test_known(pl, num) {
+		return
+	}
+
+	e.p_player(pl).deliver_lore.Append(num)
+	e.set_known(pl, num)
+}
+
+// showLoreSheets writes each player's queued lore sheets, in order and
+// without duplicates, into their turn report.
+// Port of C show_lore_sheets() from lore.c.
+func (e *Engine) showLoreSheets() {
+	e.stage("showLoreSheets()")
+
+	for _, pl := range e.Players() {
+		p := e.rp_player(pl)
+		if p == nil || p.deliver_lore.Len() == 0 {
+			continue
+		}
+
+		l := slices.Clone(p.deliver_lore.Values())
+		slices.Sort(l)
+		l = slices.Compact(l)
+
+		for _, num := range l {
+			e.deliver_lore(pl, num)
+		}
+
+		p.deliver_lore.Clear()
+	}
+}
+
+// gmShowAllSkills writes the full skill listing and every skill's
+// lore sheet to pl, for the rulebook.
+// Port of C gm_show_all_skills() from lore.c.
+func (e *Engine) gmShowAllSkills(pl int) {
+	if e.kind(pl) != T_player {
+		return
+	}
+
+	skills := e.Skills()
+
+	e.report_out(pl, "")
+	e.report_out(pl, "Skill listing:")
+	e.report_out(pl, "--------------")
+
+	e.report_out(pl, "")
+	e.report_out(pl, "Skill schools:")
+	e.report_out(pl, "")
+
+	for _, sk := range skills {
+		if e.skill_school(sk) == sk {
+			e.out_skill_line(pl, sk, "")
+		}
+	}
+
+	e.report_out(pl, "")
+
+	for _, sk := range skills {
+		if e.skill_school(sk) != sk {
+			continue
+		}
+
+		e.report_out(pl, "%s", e.box_name(sk))
+		for _, i := range skills {
+			if e.skill_school(i) == sk && i != sk {
+				e.out_skill_line(pl, i, "   ")
+			}
+		}
+		e.report_out(pl, "")
+	}
+
+	// Output lore sheets for all skills, schools first.
+	for _, sk := range skills {
+		if e.skill_school(sk) == sk {
+			e.deliver_skill_lore(pl, sk, true)
+			e.report_out(pl, "")
+		}
+	}
+
+	for _, sk := range skills {
+		if e.skill_school(sk) != sk {
+			e.deliver_skill_lore(pl, sk, true)
+			e.report_out(pl, "")
+		}
+	}
+}
+
+// scanCharSkillLore queues the lore for every skill a character knows.
+// Port of C scan_char_skill_lore() from lore.c.
+func (e *Engine) scanCharSkillLore() {
+	for _, who := range e.Characters() {
+		for _, se := range e.getCharSkills(who) {
+			e.queue_lore(who, se.skill, false)
+		}
+	}
+}
+
+// scanCharItemLore queues the lore for notable items characters hold.
+// Port of C scan_char_item_lore() from lore.c.
+func (e *Engine) scanCharItemLore() {
+	for _, who := range e.Characters() {
+		for _, ie := range e.globals.inventories[who] {
+			if e.item_lore(ie.item) != 0 && !e.test_known(who, ie.item) {
+				e.queue_lore(who, ie.item, false)
+			}
+		}
+	}
+}
+
+// loadSkillLore loads the lore sheets from the skill_lore table. The
+// rows for a sheet are joined in display order and split into lines.
+func (e *Engine) loadSkillLore() error {
+	rows, err := e.db.Query(`
+		SELECT skill_id, lore_text
+		FROM skill_lore
+		ORDER BY skill_id, display_order, id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	e.globals.loreSheets = make(map[int][]string)
+
+	for rows.Next() {
+		var num int
+		var text string
+		if err := rows.Scan(&num, &text); err != nil {
+			return fmt.Errorf("scan skill_lore: %w", err)
+		}
+
+		text = strings.TrimRight(text, "\n")
+		e.globals.loreSheets[num] = append(e.globals.loreSheets[num],
+			strings.Split(text, "\n")...)
+	}
+
+	return rows.Err()
+}
+
+// saveReports writes the turn reports built this turn to the reports
+// table, one row per player, and clears them. Reports already written
+// for the turn are replaced.
+func (e *Engine) saveReports() error {
+	if e.db == nil || len(e.globals.reports) == 0 {
+		return nil
+	}
+
+	tx, err := e.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := e.writeReports(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	e.globals.reports = nil
+	return nil
+}
+
+// writeReports replaces the reports of the turn on the clock in tx with
+// the ones built so far.
+func (e *Engine) writeReports(tx *sql.Tx) error {
+	turn := e.globals.sysclock.turn
+
+	if _, err := tx.Exec(`INSERT OR IGNORE INTO turns (turn_number) VALUES (?)`, turn); err != nil {
+		return fmt.Errorf("insert turn %d: %w", turn, err)
+	}
+	if _, err := tx.Exec(`DELETE FROM reports WHERE turn_number = ?`, turn); err != nil {
+		return fmt.Errorf("clear reports: %w", err)
+	}
+
+	// Players created this turn are not saved until the next SaveWorld.
+	playerStmt, err := tx.Prepare(`
+		INSERT OR IGNORE INTO players (id, code, name, subkind) VALUES (?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer playerStmt.Close()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO reports (turn_number, player_id, body) VALUES (?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, pl := range slices.Sorted(maps.Keys(e.globals.reports)) {
+		if _, err := playerStmt.Exec(pl, int_to_code(pl), e.globals.names[pl], int(e.Subkind(pl))); err != nil {
+			return fmt.Errorf("insert player %d: %w", pl, err)
+		}
+
+		body := strings.Join(e.globals.reports[pl], "\n") + "\n"
+		if _, err := stmt.Exec(turn, pl, body); err != nil {
+			return fmt.Errorf("insert report %d: %w", pl, err)
+		}
+	}
+
+	return nil
+}
+
+// Report returns the lines written to player pl's turn report so far.
+func (e *Engine) Report(pl int) []string {
+	return e.globals.reports[pl]
+}
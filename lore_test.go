@@ -0,0 +1,140 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// lore_test.go - Tests for lore sheet delivery
+
+package taygete
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestQueueLoreOnce(t *testing.T) {
+	pl, who := setupUseTest(t)
+
+	teg.queue_lore(who, sk_archery, false)
+	teg.queue_lore(who, sk_archery, false)
+	if got := teg.p_player(pl).deliver_lore.Len(); got != 1 {
+		t.Errorf("queued %d sheets, want 1", got)
+	}
+	if !teg.test_known(pl, sk_archery) {
+		t.Error("skill not marked known")
+	}
+
+	teg.queue_lore(who, sk_archery, true)
+	if got := teg.p_player(pl).deliver_lore.Len(); got != 2 {
+		t.Errorf("queued %d sheets with anyway, want 2", got)
+	}
+}
+
+func TestShowLoreSheets(t *testing.T) {
+	pl, who := setupUseTest(t)
+	teg.globals.reports = nil
+	teg.globals.loreSheets = map[int][]string{
+		sk_archery: {"# not shown", "Study @ to shoot."},
+	}
+	teg.set_name(sk_archery, "Archery")
+
+	teg.queue_lore(who, sk_archery, true)
+	teg.queue_lore(who, sk_archery, true)
+	teg.showLoreSheets()
+
+	report := teg.Report(pl)
+	want := "Study " + box_code_less(sk_archery) + " to shoot."
+	if n := countLines(report, want); n != 1 {
+		t.Errorf("lore line appears %d times in %q, want once", n, report)
+	}
+	if countLines(report, "# not shown") != 0 {
+		t.Error("comment line delivered")
+	}
+	if teg.p_player(pl).deliver_lore.Len() != 0 {
+		t.Error("delivered lore still queued")
+	}
+}
+
+func TestScanCharItemLore(t *testing.T) {
+	pl, who := setupUseTest(t)
+
+	item := teg.create_unique_item(who, sub_artifact)
+	teg.p_item_magic(item).lore = lore_orb
+
+	teg.scanCharItemLore()
+	if !slices.Contains(teg.p_player(pl).deliver_lore.Values(), item) {
+		t.Fatal("item lore not queued")
+	}
+
+	teg.p_player(pl).deliver_lore.Clear()
+	teg.scanCharItemLore()
+	if teg.p_player(pl).deliver_lore.Len() != 0 {
+		t.Error("item lore queued a second time")
+	}
+}
+
+func TestLoreReportSaved(t *testing.T) {
+	e := newTestEngine(t)
+
+	for _, q := range []string{
+		`INSERT INTO skills (id, name) VALUES (600, 'Combat')`,
+		`INSERT INTO skill_lore (skill_id, lore_text, display_order) VALUES (600, 'Second line.', 2)`,
+		`INSERT INTO skill_lore (skill_id, lore_text, display_order) VALUES (600, 'First line.', 1)`,
+		`INSERT INTO players (id, code, subkind) VALUES (50001, 'aa1', 1)`,
+	} {
+		if _, err := e.db.Exec(q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	if err := e.loadSkillLore(); err != nil {
+		t.Fatalf("loadSkillLore: %v", err)
+	}
+	if got := e.globals.loreSheets[600]; !slices.Equal(got, []string{"First line.", "Second line."}) {
+		t.Fatalf("lore sheet = %q", got)
+	}
+
+	teg.alloc_box(50001, T_player, sub_pl_regular)
+	teg.alloc_box(600, T_skill, 0)
+	teg.set_name(600, "Combat")
+	teg.deliver_lore(50001, 600)
+
+	e.globals.sysclock.turn = 3
+	if err := e.saveReports(); err != nil {
+		t.Fatalf("saveReports: %v", err)
+	}
+
+	var body string
+	err := e.db.QueryRow(`SELECT body FROM reports WHERE turn_number = 3 AND player_id = 50001`).Scan(&body)
+	if err != nil {
+		t.Fatalf("query report: %v", err)
+	}
+	if !strings.Contains(body, "First line.\nSecond line.") {
+		t.Errorf("report body = %q", body)
+	}
+	if len(e.Report(50001)) != 0 {
+		t.Error("report not cleared after saving")
+	}
+}
+
+// countLines counts the lines of report equal to s.
+func countLines(report []string, s string) int {
+	n := 0
+	for _, line := range report {
+		if line == s {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,373 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// make.go - The MAKE command ported from src/make.c
+
+package taygete
+
+import "fmt"
+
+// WHERE_SHIP in a make entry's where field means production needs a
+// ship.
+const WHERE_SHIP = -1
+
+// make_ent describes something MAKE can produce: its inputs, the skill
+// and place it needs, and how long each one takes.
+type make_ent struct {
+	item      int
+	inp1      int
+	inp2      int
+	req_skill int
+	worker    int // worker needed
+	got_em    string
+	public    bool // does everyone see us make this
+	where     int  // place required for production
+	aura      int  // aura per unit required
+	factor    int  // multiplying qty factor, usually 1
+	days      int  // days to make each thing
+}
+
+// make_tbl lists what MAKE can produce.
+var make_tbl = []make_ent{
+	// One-day things
+	{item_blank_scroll, item_lana_bark, 0, sk_alchemy, 0, "made", false, 0, 0, 1, 1},
+	{item_elite_arch, item_archer, 0, sk_archery, 0, "trained", false, sub_castle, 0, 1, 1},
+	{item_angry_peasant, item_peasant, 0, sk_train_angry, 0, "trained", false, 0, 0, 1, 1},
+	{item_peasant, item_angry_peasant, 0, sk_train_angry, 0, "trained", false, 0, 0, 1, 1},
+	{item_archer, item_soldier, item_longbow, sk_archery, 0, "trained", false, 0, 0, 1, 1},
+	{item_elite_guard, item_knight, item_plate, sk_swordplay, 0, "trained", false, sub_castle, 0, 1, 1},
+	{item_knight, item_swordsman, item_warmount, sk_swordplay, 0, "trained", false, 0, 0, 1, 1},
+	{item_blessed_soldier, item_soldier, 0, sk_religion, 0, "trained", false, sub_temple, 0, 1, 1},
+	{item_ghost_warrior, 0, 0, sk_summon_ghost, 0, "summoned", false, 0, 1, 2, 1},
+	{item_swordsman, item_soldier, item_longsword, sk_swordplay, 0, "trained", false, 0, 0, 1, 1},
+	{item_pirate, item_sailor, item_longsword, sk_swordplay, 0, "trained", false, WHERE_SHIP, 0, 1, 1},
+	{item_pikeman, item_soldier, item_pike, sk_combat, 0, "trained", false, 0, 0, 1, 1},
+	{item_soldier, item_peasant, 0, sk_combat, 0, "trained", false, 0, 0, 1, 1},
+	{item_crossbowman, item_peasant, item_crossbow, sk_combat, 0, "trained", false, 0, 0, 1, 1},
+	{item_sailor, item_peasant, 0, sk_pilot_ship, 0, "trained", false, 0, 0, 1, 1},
+	{item_worker, item_peasant, 0, 0, 0, "trained", false, 0, 0, 1, 1},
+	{item_basket, 0, 0, 0, 0, "made", false, 0, 0, 1, 1},
+	{item_pot, 0, 0, 0, 0, "made", false, 0, 0, 1, 1},
+	{item_crossbow, item_lumber, 0, sk_weaponsmith, 0, "made", false, 0, 0, 1, 1},
+	{item_pike, item_lumber, 0, sk_weaponsmith, 0, "made", false, 0, 0, 1, 1},
+	{item_longsword, item_iron, 0, sk_weaponsmith, 0, "made", false, 0, 0, 1, 1},
+	{item_plate, item_iron, 0, sk_weaponsmith, 0, "made", false, 0, 0, 1, 1},
+	{item_longbow, item_yew, 0, sk_weaponsmith, 0, "made", false, 0, 0, 1, 1},
+	{item_drum, item_mallorn_wood, 0, sk_summon_savage, 0, "made", false, 0, 0, 1, 1},
+	{item_hide, item_ox, 0, 0, 0, "made", false, 0, 0, 1, 1},
+
+	// Multi-day things
+	{item_riding_horse, item_wild_horse, 0, sk_train_wild, 0, "trained", true, 0, 0, 1, 3},
+	{item_warmount, item_wild_horse, 0, sk_train_warmount, 0, "trained", true, 0, 0, 1, 7},
+}
+
+// find_make returns the make_tbl entry for item, or nil.
+// Ported from src/make.c lines 176-186.
+func find_make(item int) *make_ent {
+	for i := range make_tbl {
+		if make_tbl[i].item == item {
+			return &make_tbl[i]
+		}
+	}
+
+	return nil
+}
+
+// v_generic_make starts making something that takes a day each. It
+// runs as long as it takes to make number, or all possible if number
+// is 0; things with no inputs stop at month end.
+// Ported from src/make.c lines 193-256.
+func (e *Engine) v_generic_make(c *command, number int, t *make_ent) int {
+	where := e.subloc(c.who)
+	days := -1 // as long as it takes to get number
+
+	// Don't run forever for non-resource limited production
+	if number == 0 && t.inp1 == 0 && t.inp2 == 0 {
+		days = (MONTH_DAYS + 1) - int(e.globals.sysclock.day)
+	}
+
+	c.c = number // number desired; 0 means all possible
+	c.d = 0      // number we have obtained so far
+
+	if t.req_skill != 0 && !e.has_skill(c.who, t.req_skill) {
+		wout(c.who, "Requires %s.", e.box_name(t.req_skill))
+		return FALSE
+	}
+
+	if t.worker != 0 && e.has_item(c.who, t.worker) < 1 {
+		wout(c.who, "Need at least one %s.", e.box_name(t.worker))
+		return FALSE
+	}
+
+	if t.inp1 != 0 && e.has_item(c.who, t.inp1) < 1 {
+		wout(c.who, "Don't have any %s.", e.plural_item_box(t.inp1, 2))
+		return FALSE
+	}
+
+	if t.inp2 != 0 && e.has_item(c.who, t.inp2) < 1 {
+		wout(c.who, "Don't have any %s.", e.plural_item_box(t.inp2, 2))
+		return FALSE
+	}
+
+	if t.where == WHERE_SHIP && !e.is_ship(where) && !e.is_ship_notdone(where) {
+		wout(c.who, "Must be on a ship.")
+		return FALSE
+	}
+
+	if t.where > 0 && int(e.subkind(where)) != t.where {
+		wout(c.who, "Must be in a %s.", subkind_s[t.where])
+		return FALSE
+	}
+
+	if t.aura != 0 && e.char_cur_aura(c.who) < t.aura {
+		wout(c.who, "Need at least %d aura.", t.aura)
+		return FALSE
+	}
+
+	c.wait = days
+	return TRUE
+}
+
+// d_generic_make makes one day's worth: as many as the workers,
+// inputs and aura allow, up to the number still wanted. It keeps
+// going while days are left, inputs remain and the number isn't made.
+// Ported from src/make.c lines 259-326.
+func (e *Engine) d_generic_make(c *command, t *make_ent) int {
+	number := c.c
+
+	qty := 1
+	if t.worker != 0 {
+		qty = e.has_item(c.who, t.worker)
+	}
+
+	if t.inp1 != 0 {
+		qty = min(qty, e.has_item(c.who, t.inp1))
+	}
+
+	if t.inp2 != 0 {
+		qty = min(qty, e.has_item(c.who, t.inp2))
+	}
+
+	if t.aura != 0 {
+		qty = min(qty, e.char_cur_aura(c.who))
+	}
+
+	if qty > 0 {
+		if number > 0 && c.d+qty > number {
+			qty = number - c.d
+		}
+
+		if qty < 0 {
+			panic("d_generic_make: negative quantity")
+		}
+
+		if t.inp1 != 0 {
+			e.consume_item(c.who, t.inp1, qty)
+		}
+
+		if t.inp2 != 0 {
+			e.consume_item(c.who, t.inp2, qty)
+		}
+
+		if t.aura != 0 {
+			e.deduct_aura(c.who, t.aura)
+		}
+
+		e.gen_item(c.who, t.item, qty*t.factor)
+		c.d += qty
+
+		if t.req_skill != 0 {
+			e.add_skill_experience(c.who, t.req_skill)
+		}
+
+		if (t.inp1 == 0 || e.has_item(c.who, t.inp1) > 0) &&
+			(t.inp2 == 0 || e.has_item(c.who, t.inp2) > 0) &&
+			c.wait != 0 &&
+			!(number > 0 && c.d >= number) {
+			return TRUE // not done yet
+		}
+	}
+
+	return e.i_generic_make(c, t)
+}
+
+// i_generic_make reports what was made and ends the order. It
+// succeeds only if the number wanted was made.
+// Ported from src/make.c lines 329-350.
+func (e *Engine) i_generic_make(c *command, t *make_ent) int {
+	where := e.subloc(c.who)
+
+	out(c.who, "%s %s.", cap(t.got_em), e.just_name_qty(t.item, c.d*t.factor))
+
+	if t.public {
+		out(where, "%s %s %s.", e.box_name(c.who), t.got_em, e.just_name_qty(t.item, c.d))
+	}
+
+	c.wait = 0
+
+	if c.d > 0 && c.d >= c.c {
+		return TRUE
+	}
+	return FALSE
+}
+
+// v_second_make starts making something that takes more than a day
+// each; skill experience speeds it up.
+// Ported from src/make.c lines 357-393.
+func (e *Engine) v_second_make(c *command, number int, t *make_ent) int {
+	c.c = number // number desired; 0 means all possible
+	c.d = 0      // number we have obtained so far
+
+	if t.req_skill != 0 && !e.has_skill(c.who, t.req_skill) {
+		wout(c.who, "Requires %s.", e.box_name(t.req_skill))
+		return FALSE
+	}
+
+	if t.inp1 != 0 && e.has_item(c.who, t.inp1) < 1 {
+		wout(c.who, "Don't have any %s.", e.plural_item_box(t.inp1, 2))
+		return FALSE
+	}
+
+	if t.inp2 != 0 && e.has_item(c.who, t.inp2) < 1 {
+		wout(c.who, "Don't have any %s.", e.plural_item_box(t.inp2, 2))
+		return FALSE
+	}
+
+	c.wait = t.days
+	c.poll = FALSE
+
+	if t.req_skill != 0 {
+		c.use_exp = e.has_skill_level(c.who, t.req_skill)
+		experience_use_speedup(c)
+	}
+
+	return TRUE
+}
+
+// d_second_make makes one, and starts on the next if inputs remain
+// and more are wanted.
+// Ported from src/make.c lines 396-442.
+func (e *Engine) d_second_make(c *command, t *make_ent) int {
+	if t.inp1 != 0 && e.has_item(c.who, t.inp1) < 1 {
+		wout(c.who, "Don't have %s.", e.box_name_qty(t.inp1, 2))
+		return FALSE
+	}
+
+	if t.inp2 != 0 && e.has_item(c.who, t.inp2) < 1 {
+		wout(c.who, "Don't have %s.", e.box_name_qty(t.inp2, 2))
+		return FALSE
+	}
+
+	if t.inp1 != 0 {
+		e.consume_item(c.who, t.inp1, 1)
+	}
+
+	if t.inp2 != 0 {
+		e.consume_item(c.who, t.inp2, 1)
+	}
+
+	e.gen_item(c.who, t.item, 1)
+
+	out(c.who, "%s %s.", cap(t.got_em), e.just_name_qty(t.item, 1))
+
+	if t.public {
+		out(e.subloc(c.who), "%s %s %s.", e.box_name(c.who), t.got_em, e.just_name_qty(t.item, 1))
+	}
+
+	c.d++
+
+	if (t.inp1 == 0 || e.has_item(c.who, t.inp1) > 0) &&
+		(t.inp2 == 0 || e.has_item(c.who, t.inp2) > 0) &&
+		!(c.c > 0 && c.d >= c.c) {
+		c.wait = t.days
+	}
+
+	if t.req_skill != 0 {
+		e.add_skill_experience(c.who, t.req_skill)
+	}
+
+	return TRUE
+}
+
+// v_make starts making number of item.
+// Ported from src/make.c lines 445-465.
+func (e *Engine) v_make(c *command) int {
+	item := c.a
+	number := c.b
+
+	t := find_make(item)
+	if t == nil {
+		wout(c.who, "Don't know how to make %s.", e.box_code(item))
+		return FALSE
+	}
+
+	if t.days == 1 {
+		return e.v_generic_make(c, number, t)
+	}
+	return e.v_second_make(c, number, t)
+}
+
+// d_make finishes a day or a unit of MAKE.
+// Ported from src/make.c lines 468-487.
+func (e *Engine) d_make(c *command) int {
+	t := find_make(c.a)
+	if t == nil {
+		out(c.who, "Internal error.")
+		log_write(LOG_CODE, "d_make: t is NULL, who=%d", c.who)
+		return FALSE
+	}
+
+	if t.days == 1 {
+		return e.d_generic_make(c, t)
+	}
+	return e.d_second_make(c, t)
+}
+
+// i_make ends an interrupted MAKE.
+// Ported from src/make.c lines 490-509.
+func (e *Engine) i_make(c *command) int {
+	t := find_make(c.a)
+	if t == nil {
+		out(c.who, "Internal error.")
+		log_write(LOG_CODE, "i_make: t is NULL, who=%d", c.who)
+		return FALSE
+	}
+
+	if t.days == 1 {
+		return e.i_generic_make(c, t)
+	}
+	return TRUE
+}
+
+// make_as runs line as a MAKE order in place of the skill being used.
+func (e *Engine) make_as(c *command, line string) int {
+	if !e.oly_parse(c, line) {
+		panic("make_as: cannot parse " + line)
+	}
+	return e.v_make(c)
+}
+
+// v_use_train_riding trains wild horses into riding horses.
+// Ported from src/make.c lines 512-522.
+func (e *Engine) v_use_train_riding(c *command) int {
+	return e.make_as(c, fmt.Sprintf("make %s %d", box_code_less(item_riding_horse), c.a))
+}
+
+// v_use_train_war trains wild horses into warmounts.
+// Ported from src/make.c lines 525-535.
+func (e *Engine) v_use_train_war(c *command) int {
+	return e.make_as(c, fmt.Sprintf("make %s %d", box_code_less(item_warmount), c.a))
+}
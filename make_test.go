@@ -0,0 +1,83 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// make_test.go - Tests for the MAKE command
+
+package taygete
+
+import "testing"
+
+// setupMakeTest builds a character who knows sk and holds qty of inp.
+func setupMakeTest(t *testing.T, sk, inp, qty int, items ...int) (who int) {
+	t.Helper()
+	who = setupBasicTest(t, 0, 0)
+	teg.alloc_box(sk, T_skill, 0)
+	teg.p_skill_ent(who, sk).know = SKILL_know
+	for _, item := range append(items, inp) {
+		teg.alloc_box(item, T_item, 0)
+	}
+	teg.gen_item(who, inp, qty)
+	return who
+}
+
+func TestMakeSoldiers(t *testing.T) {
+	who := setupMakeTest(t, sk_combat, item_peasant, 5, item_soldier)
+
+	c := &command{who: who, a: item_soldier, b: 3}
+	if got := teg.v_make(c); got != TRUE {
+		t.Fatalf("v_make = %d, want TRUE", got)
+	}
+	for day := 1; day <= 3; day++ {
+		if got := teg.d_make(c); got != TRUE {
+			t.Fatalf("day %d: d_make = %d, want TRUE", day, got)
+		}
+	}
+	if c.wait != 0 {
+		t.Errorf("wait = %d, want 0 once 3 are made", c.wait)
+	}
+	if got := teg.has_item(who, item_soldier); got != 3 {
+		t.Errorf("soldiers = %d, want 3", got)
+	}
+	if got := teg.has_item(who, item_peasant); got != 2 {
+		t.Errorf("peasants = %d, want 2", got)
+	}
+}
+
+func TestUseTrainRiding(t *testing.T) {
+	who := setupMakeTest(t, sk_train_wild, item_wild_horse, 2, item_riding_horse)
+
+	c := &command{who: who, a: 1}
+	if got := teg.v_use_train_riding(c); got != TRUE {
+		t.Fatalf("v_use_train_riding = %d, want TRUE", got)
+	}
+	if c.a != item_riding_horse || c.b != 1 || c.wait != 3 {
+		t.Errorf("parsed make: a=%d b=%d wait=%d, want a=%d b=1 wait=3", c.a, c.b, c.wait, item_riding_horse)
+	}
+
+	c.wait = 0
+	if got := teg.d_make(c); got != TRUE {
+		t.Fatalf("d_make = %d, want TRUE", got)
+	}
+	if got := teg.has_item(who, item_riding_horse); got != 1 {
+		t.Errorf("riding horses = %d, want 1", got)
+	}
+	if got := teg.has_item(who, item_wild_horse); got != 1 {
+		t.Errorf("wild horses = %d, want 1", got)
+	}
+	if c.wait != 0 {
+		t.Errorf("wait = %d, want 0 once the one asked for is trained", c.wait)
+	}
+}
@@ -0,0 +1,388 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// migrate.go - versioned, forward-only schema migrations
+
+package taygete
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// ErrFutureSchema is returned when a database records migrations this
+// binary doesn't know, most likely because a newer binary wrote it.
+var ErrFutureSchema = errors.New("database schema is newer than this binary")
+
+// Migration is one step in the schema's history. Steps are applied in
+// version order and never undone. A step is either SQL from the
+// migrations directory or a Go function, for data transforms that are
+// awkward to write in SQL.
+type Migration struct {
+	Version int    // numeric prefix of the name
+	Name    string // recorded in schema_migrations, e.g. "016_box_state"
+	SQL     string
+	Go      func(tx *sql.Tx) error
+}
+
+// Kind returns "sql" or "go".
+func (m Migration) Kind() string {
+	if m.Go != nil {
+		return "go"
+	}
+	return "sql"
+}
+
+// Checksum identifies the content of the step. SQL steps hash their
+// text, so editing an applied file is caught; Go steps can only hash
+// their name.
+func (m Migration) Checksum() string {
+	body := m.SQL
+	if m.Go != nil {
+		body = "go:" + m.Name
+	}
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// goMigrations are the Go steps. Each must use a version that no SQL
+// file uses; they are merged with the SQL steps by version.
+var goMigrations []Migration
+
+// migrationVersion parses the numeric prefix of a migration name.
+func migrationVersion(name string) (int, error) {
+	prefix, _, ok := strings.Cut(name, "_")
+	if !ok {
+		return 0, fmt.Errorf("migration %q: name must be NNN_description", name)
+	}
+	version, err := strconv.Atoi(prefix)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("migration %q: bad version %q", name, prefix)
+	}
+	return version, nil
+}
+
+// Migrations returns every step this binary knows, in version order.
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationsFS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+
+	var list []Migration
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		content, err := fs.ReadFile(migrationsFS, "migrations/"+entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+		list = append(list, Migration{
+			Name: strings.TrimSuffix(entry.Name(), ".sql"),
+			SQL:  string(content),
+		})
+	}
+	list = append(list, goMigrations...)
+
+	return sortMigrations(list)
+}
+
+// sortMigrations fills in the versions and sorts the steps, rejecting
+// duplicate versions.
+func sortMigrations(list []Migration) ([]Migration, error) {
+	for i := range list {
+		version, err := migrationVersion(list[i].Name)
+		if err != nil {
+			return nil, err
+		}
+		list[i].Version = version
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+	for i := 1; i < len(list); i++ {
+		if list[i].Version == list[i-1].Version {
+			return nil, fmt.Errorf("migrations %s and %s share version %d",
+				list[i-1].Name, list[i].Name, list[i].Version)
+		}
+	}
+	return list, nil
+}
+
+// appliedMigration is a row of schema_migrations.
+type appliedMigration struct {
+	Name      string
+	Checksum  string
+	AppliedAt string
+}
+
+// MigrationState is the status of one step in a database.
+type MigrationState struct {
+	Name      string
+	Kind      string // "sql", "go", or "" for a step this binary doesn't know
+	State     string // "applied", "pending", "modified" or "unknown"
+	AppliedAt string
+}
+
+// MigrationStatus reports every known step and every step the database
+// records, in version order. It doesn't change the database.
+func MigrationStatus(db *sql.DB) ([]MigrationState, error) {
+	known, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := readAppliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+	return migrationStates(known, applied), nil
+}
+
+func migrationStates(known []Migration, applied map[string]appliedMigration) []MigrationState {
+	var states []MigrationState
+	seen := make(map[string]bool)
+	for _, m := range known {
+		seen[m.Name] = true
+		st := MigrationState{Name: m.Name, Kind: m.Kind(), State: "pending"}
+		if a, ok := applied[m.Name]; ok {
+			st.State, st.AppliedAt = "applied", a.AppliedAt
+			if a.Checksum != "" && a.Checksum != m.Checksum() {
+				st.State = "modified"
+			}
+		}
+		states = append(states, st)
+	}
+	for name, a := range applied {
+		if !seen[name] {
+			states = append(states, MigrationState{Name: name, State: "unknown", AppliedAt: a.AppliedAt})
+		}
+	}
+	sort.SliceStable(states, func(i, j int) bool {
+		vi, _ := migrationVersion(states[i].Name)
+		vj, _ := migrationVersion(states[j].Name)
+		return vi < vj
+	})
+	return states
+}
+
+// ErrSchemaOutOfDate is returned when a database has migrations still
+// to apply.
+var ErrSchemaOutOfDate = errors.New("database schema is out of date")
+
+// CheckSchema returns nil if every step this binary knows, and no
+// other, is applied to db as written. It doesn't change the database.
+func CheckSchema(db *sql.DB) error {
+	states, err := MigrationStatus(db)
+	if err != nil {
+		return err
+	}
+	for _, st := range states {
+		switch st.State {
+		case "pending":
+			return fmt.Errorf("migration %s: %w", st.Name, ErrSchemaOutOfDate)
+		case "unknown":
+			return fmt.Errorf("migration %s: %w", st.Name, ErrFutureSchema)
+		case "modified":
+			return fmt.Errorf("migration %s: checksum differs from the applied step", st.Name)
+		}
+	}
+	return nil
+}
+
+// readAppliedMigrations returns the rows of schema_migrations, or none
+// if the table doesn't exist yet. Databases written before checksums
+// were recorded have no checksum column.
+func readAppliedMigrations(db *sql.DB) (map[string]appliedMigration, error) {
+	cols, err := migrationColumns(db)
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[string]appliedMigration)
+	if len(cols) == 0 {
+		return applied, nil
+	}
+	query := "SELECT version, '', applied_at FROM schema_migrations"
+	if cols["checksum"] {
+		query = "SELECT version, checksum, applied_at FROM schema_migrations"
+	}
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("query migrations: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a appliedMigration
+		if err := rows.Scan(&a.Name, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, fmt.Errorf("scan migration version: %w", err)
+		}
+		applied[a.Name] = a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// migrationColumns returns the columns of schema_migrations; the map
+// is empty if the table doesn't exist yet.
+func migrationColumns(db *sql.DB) (map[string]bool, error) {
+	rows, err := db.Query("SELECT name FROM pragma_table_info('schema_migrations')")
+	if err != nil {
+		return nil, fmt.Errorf("columns of schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	cols := make(map[string]bool)
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			return nil, fmt.Errorf("columns of schema_migrations: %w", err)
+		}
+		cols[col] = true
+	}
+	return cols, rows.Err()
+}
+
+// MigrateDB applies all pending migrations and returns the names of
+// the steps it applied.
+func MigrateDB(db *sql.DB) ([]string, error) {
+	known, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	return migrate(db, known)
+}
+
+// runMigrations applies all pending migrations to the database.
+func runMigrations(db *sql.DB) error {
+	_, err := MigrateDB(db)
+	return err
+}
+
+// migrate applies the pending steps of known, in order. It refuses a
+// database that records steps it doesn't know or whose applied SQL has
+// since been edited, and a pending step older than one already applied,
+// since steps only ever move forward.
+func migrate(db *sql.DB, known []Migration) ([]string, error) {
+	// Create migrations tracking table
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			checksum TEXT NOT NULL DEFAULT ''
+		)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("create migrations table: %w", err)
+	}
+	cols, err := migrationColumns(db)
+	if err != nil {
+		return nil, err
+	}
+	if !cols["checksum"] {
+		if _, err := db.Exec("ALTER TABLE schema_migrations ADD COLUMN checksum TEXT NOT NULL DEFAULT ''"); err != nil {
+			return nil, fmt.Errorf("add migration checksums: %w", err)
+		}
+	}
+
+	applied, err := readAppliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	newest := 0
+	if len(known) > 0 {
+		newest = known[len(known)-1].Version
+	}
+	latest := 0
+	for _, st := range migrationStates(known, applied) {
+		switch st.State {
+		case "unknown":
+			if v, _ := migrationVersion(st.Name); v > newest {
+				return nil, fmt.Errorf("migration %s: %w", st.Name, ErrFutureSchema)
+			}
+			return nil, fmt.Errorf("migration %s: not known to this binary", st.Name)
+		case "modified":
+			return nil, fmt.Errorf("migration %s: checksum differs from the applied step", st.Name)
+		case "applied":
+			v, _ := migrationVersion(st.Name)
+			latest = max(latest, v)
+		}
+	}
+
+	var done []string
+	for _, m := range known {
+		a, ok := applied[m.Name]
+		if ok {
+			// Record checksums for steps applied before they were kept.
+			if a.Checksum == "" {
+				if _, err := db.Exec("UPDATE schema_migrations SET checksum = ? WHERE version = ?", m.Checksum(), m.Name); err != nil {
+					return done, fmt.Errorf("record checksum %s: %w", m.Name, err)
+				}
+			}
+			continue
+		}
+		if m.Version < latest {
+			return done, fmt.Errorf("migration %s is older than the applied schema", m.Name)
+		}
+		if err := applyMigration(db, m); err != nil {
+			return done, err
+		}
+		done = append(done, m.Name)
+	}
+
+	return done, nil
+}
+
+// applyMigration runs one step and records it in the same transaction.
+func applyMigration(db *sql.DB, m Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction for %s: %w", m.Name, err)
+	}
+
+	if m.Go != nil {
+		err = m.Go(tx)
+	} else {
+		_, err = tx.Exec(m.SQL)
+	}
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("execute migration %s: %w", m.Name, err)
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version, checksum) VALUES (?, ?)", m.Name, m.Checksum()); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("record migration %s: %w", m.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migration %s: %w", m.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+--  taygete - a game engine for a game.
+--  Copyright (c) 2026 Michael D Henderson.
+--
+--  This program is free software: you can redistribute it and/or modify
+--  it under the terms of the GNU Affero General Public License as published by
+--  the Free Software Foundation, either version 3 of the License, or
+--  (at your option) any later version.
+--
+--  This program is distributed in the hope that it will be useful,
+--  but WITHOUT ANY WARRANTY; without even the implied warranty of
+--  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+--  GNU Affero General Public License for more details.
+--
+--  You should have received a copy of the GNU Affero General Public License
+--  along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+-- Magical item attributes (struct item_magic) and unique item holders
+
+ALTER TABLE item_types ADD COLUMN who_has INTEGER;
+
+CREATE TABLE item_magic (
+  item_id        INTEGER PRIMARY KEY REFERENCES item_types(id),
+  creator        INTEGER,
+  region_created INTEGER,
+  lore           INTEGER,
+  curse_loyalty  INTEGER DEFAULT 0,
+  cloak_region   INTEGER DEFAULT 0,
+  cloak_creator  INTEGER DEFAULT 0,
+  use_key        INTEGER DEFAULT 0,
+  project_cast   INTEGER DEFAULT 0,
+  token_ni       INTEGER DEFAULT 0,
+  quick_cast     INTEGER DEFAULT 0,
+  aura_bonus     INTEGER DEFAULT 0,
+  aura           INTEGER DEFAULT 0,
+  relic_decay    INTEGER DEFAULT 0,
+  attack_bonus   INTEGER DEFAULT 0,
+  defense_bonus  INTEGER DEFAULT 0,
+  missile_bonus  INTEGER DEFAULT 0,
+  token_num      INTEGER DEFAULT 0,
+  orb_use_count  INTEGER DEFAULT 0
+);
+
+-- Skills usable (kind = 'use') or studyable (kind = 'study') through an item
+CREATE TABLE item_magic_skills (
+  item_id      INTEGER NOT NULL REFERENCES item_magic(item_id),
+  kind         TEXT NOT NULL CHECK (kind IN ('use', 'study')),
+  seq          INTEGER NOT NULL,
+  skill_id     INTEGER NOT NULL,
+  PRIMARY KEY (item_id, kind, seq)
+);
@@ -0,0 +1,38 @@
+--  taygete - a game engine for a game.
+--  Copyright (c) 2026 Michael D Henderson.
+--
+--  This program is free software: you can redistribute it and/or modify
+--  it under the terms of the GNU Affero General Public License as published by
+--  the Free Software Foundation, either version 3 of the License, or
+--  (at your option) any later version.
+--
+--  This program is distributed in the hope that it will be useful,
+--  but WITHOUT ANY WARRANTY; without even the implied warranty of
+--  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+--  GNU Affero General Public License for more details.
+--
+--  You should have received a copy of the GNU Affero General Public License
+--  along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+-- Bodies of dead nobles (T_item, sub_dead_body). A body keeps the noble's
+-- name, lords, time of death, combat ratings and skills for resurrection.
+
+CREATE TABLE dead_bodies (
+  item_id      INTEGER PRIMARY KEY REFERENCES item_types(id),
+  save_name    TEXT,
+  old_lord     INTEGER,
+  prev_lord    INTEGER,
+  death_turn   INTEGER NOT NULL DEFAULT 0,
+  death_day    INTEGER NOT NULL DEFAULT 0,
+  attack       INTEGER DEFAULT 0,
+  defense      INTEGER DEFAULT 0,
+  missile      INTEGER DEFAULT 0
+);
+
+CREATE TABLE dead_body_skills (
+  item_id      INTEGER NOT NULL REFERENCES dead_bodies(item_id),
+  skill_id     INTEGER NOT NULL REFERENCES skills(id),
+  level        INTEGER NOT NULL DEFAULT 0,
+  experience   INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (item_id, skill_id)
+);
@@ -0,0 +1,22 @@
+--  taygete - a game engine for a game.
+--  Copyright (c) 2026 Michael D Henderson.
+--
+--  This program is free software: you can redistribute it and/or modify
+--  it under the terms of the GNU Affero General Public License as published by
+--  the Free Software Foundation, either version 3 of the License, or
+--  (at your option) any later version.
+--
+--  This program is distributed in the hope that it will be useful,
+--  but WITHOUT ANY WARRANTY; without even the implied warranty of
+--  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+--  GNU Affero General Public License for more details.
+--
+--  You should have received a copy of the GNU Affero General Public License
+--  along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+-- Magician status and npc token control (struct char_magic), and the
+-- rare artifact a monster may only be defeated by (struct entity_misc).
+ALTER TABLE char_magic ADD COLUMN magician INTEGER DEFAULT 0;
+ALTER TABLE char_magic ADD COLUMN token INTEGER;
+
+ALTER TABLE characters ADD COLUMN only_vuln INTEGER;
@@ -0,0 +1,43 @@
+--  taygete - a game engine for a game.
+--  Copyright (c) 2026 Michael D Henderson.
+--
+--  This program is free software: you can redistribute it and/or modify
+--  it under the terms of the GNU Affero General Public License as published by
+--  the Free Software Foundation, either version 3 of the License, or
+--  (at your option) any later version.
+--
+--  This program is distributed in the hope that it will be useful,
+--  but WITHOUT ANY WARRANTY; without even the implied warranty of
+--  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+--  GNU Affero General Public License for more details.
+--
+--  You should have received a copy of the GNU Affero General Public License
+--  along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+-- ADMIT declarations (entity_player.admits). sense is 1 when the
+-- player declared "all", inverting the list.
+CREATE TABLE player_admits (
+  player_id    INTEGER NOT NULL REFERENCES players(id),
+  targ_id      INTEGER NOT NULL,
+  sense        INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (player_id, targ_id)
+);
+
+-- Units and factions named in an ADMIT declaration, in order.
+CREATE TABLE player_admit_ents (
+  player_id    INTEGER NOT NULL,
+  targ_id      INTEGER NOT NULL,
+  seq          INTEGER NOT NULL,
+  ent_id       INTEGER NOT NULL,
+  PRIMARY KEY (player_id, targ_id, seq),
+  FOREIGN KEY (player_id, targ_id) REFERENCES player_admits(player_id, targ_id)
+);
+
+-- Declared attitudes (att_ent): disp is NEUTRAL (1), HOSTILE (2) or
+-- DEFEND (3). A target appears in at most one list.
+CREATE TABLE attitudes (
+  ent_id       INTEGER NOT NULL REFERENCES entities(id),
+  target_id    INTEGER NOT NULL,
+  disp         INTEGER NOT NULL,
+  PRIMARY KEY (ent_id, target_id)
+);
@@ -0,0 +1,29 @@
+--  taygete - a game engine for a game.
+--  Copyright (c) 2026 Michael D Henderson.
+--
+--  This program is free software: you can redistribute it and/or modify
+--  it under the terms of the GNU Affero General Public License as published by
+--  the Free Software Foundation, either version 3 of the License, or
+--  (at your option) any later version.
+--
+--  This program is distributed in the hope that it will be useful,
+--  but WITHOUT ANY WARRANTY; without even the implied warranty of
+--  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+--  GNU Affero General Public License for more details.
+--
+--  You should have received a copy of the GNU Affero General Public License
+--  along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+-- Seasonal passes and linked sublocations (struct entity_subloc).
+ALTER TABLE locations ADD COLUMN uldim_flag INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE locations ADD COLUMN summer_flag INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE locations ADD COLUMN link_when INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE locations ADD COLUMN link_open INTEGER NOT NULL DEFAULT 0;
+
+-- Where a subloc is linked to (link_to); link_from is the inverse.
+CREATE TABLE loc_links (
+  loc_id   INTEGER NOT NULL REFERENCES entities(id),
+  seq      INTEGER NOT NULL,
+  dest_id  INTEGER NOT NULL REFERENCES entities(id),
+  PRIMARY KEY (loc_id, seq)
+);
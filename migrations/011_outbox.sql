@@ -0,0 +1,38 @@
+--  taygete - a game engine for a game.
+--  Copyright (c) 2026 Michael D Henderson.
+--
+--  This program is free software: you can redistribute it and/or modify
+--  it under the terms of the GNU Affero General Public License as published by
+--  the Free Software Foundation, either version 3 of the License, or
+--  (at your option) any later version.
+--
+--  This program is distributed in the hope that it will be useful,
+--  but WITHOUT ANY WARRANTY; without even the implied warranty of
+--  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+--  GNU Affero General Public License for more details.
+--
+--  You should have received a copy of the GNU Affero General Public License
+--  along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+-- Players who don't want to be reminded to send orders.
+ALTER TABLE players ADD COLUMN dont_remind INTEGER NOT NULL DEFAULT 0;
+
+-- Outgoing mail: turn reports and reminders waiting to be delivered.
+-- message holds the complete RFC 822 text, headers included.
+CREATE TABLE outbox (
+  id           INTEGER PRIMARY KEY AUTOINCREMENT,
+  player_id    INTEGER,
+  turn_number  INTEGER NOT NULL,
+  kind         TEXT NOT NULL,
+  part         INTEGER NOT NULL DEFAULT 1,
+  parts        INTEGER NOT NULL DEFAULT 1,
+  to_addr      TEXT NOT NULL,
+  message      TEXT NOT NULL,
+  status       TEXT NOT NULL DEFAULT 'pending',
+  attempts     INTEGER NOT NULL DEFAULT 0,
+  last_error   TEXT,
+  created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  sent_at      DATETIME
+);
+
+CREATE INDEX idx_outbox_status ON outbox(status);
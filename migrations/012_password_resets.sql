@@ -0,0 +1,25 @@
+--  taygete - a game engine for a game.
+--  Copyright (c) 2026 Michael D Henderson.
+--
+--  This program is free software: you can redistribute it and/or modify
+--  it under the terms of the GNU Affero General Public License as published by
+--  the Free Software Foundation, either version 3 of the License, or
+--  (at your option) any later version.
+--
+--  This program is distributed in the hope that it will be useful,
+--  but WITHOUT ANY WARRANTY; without even the implied warranty of
+--  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+--  GNU Affero General Public License for more details.
+--
+--  You should have received a copy of the GNU Affero General Public License
+--  along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+-- One-time tokens for resetting an account password. Only a hash of
+-- the token is kept.
+CREATE TABLE password_resets (
+  token_hash   TEXT PRIMARY KEY,
+  account_id   INTEGER NOT NULL REFERENCES accounts(id),
+  expires_at   DATETIME NOT NULL,
+  used_at      DATETIME,
+  created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
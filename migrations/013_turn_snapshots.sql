@@ -0,0 +1,31 @@
+--  taygete - a game engine for a game.
+--  Copyright (c) 2026 Michael D Henderson.
+--
+--  This program is free software: you can redistribute it and/or modify
+--  it under the terms of the GNU Affero General Public License as published by
+--  the Free Software Foundation, either version 3 of the License, or
+--  (at your option) any later version.
+--
+--  This program is distributed in the hope that it will be useful,
+--  but WITHOUT ANY WARRANTY; without even the implied warranty of
+--  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+--  GNU Affero General Public License for more details.
+--
+--  You should have received a copy of the GNU Affero General Public License
+--  along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+-- Copies of the world taken before each turn is processed, so a GM can
+-- roll a turn back and run it again. turn_number is the game clock when
+-- the snapshot was taken; each table's rows are stored as JSON.
+CREATE TABLE turn_snapshots (
+  turn_number  INTEGER PRIMARY KEY REFERENCES turns(turn_number),
+  created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE turn_snapshot_tables (
+  turn_number  INTEGER NOT NULL REFERENCES turn_snapshots(turn_number),
+  table_name   TEXT NOT NULL,
+  columns      TEXT NOT NULL,
+  rows         TEXT NOT NULL,
+  PRIMARY KEY (turn_number, table_name)
+);
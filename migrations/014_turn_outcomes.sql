@@ -0,0 +1,27 @@
+--  taygete - a game engine for a game.
+--  Copyright (c) 2026 Michael D Henderson.
+--
+--  This program is free software: you can redistribute it and/or modify
+--  it under the terms of the GNU Affero General Public License as published by
+--  the Free Software Foundation, either version 3 of the License, or
+--  (at your option) any later version.
+--
+--  This program is distributed in the hope that it will be useful,
+--  but WITHOUT ANY WARRANTY; without even the implied warranty of
+--  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+--  GNU Affero General Public License for more details.
+--
+--  You should have received a copy of the GNU Affero General Public License
+--  along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+-- What each turn produced, recorded by RunTurn so a replay of the turn
+-- from its snapshot can be checked against it. turn_number matches the
+-- turn's snapshot. entities is a JSON object of entity hashes and
+-- events a JSON array of the report lines written during the turn.
+CREATE TABLE turn_outcomes (
+  turn_number  INTEGER PRIMARY KEY REFERENCES turns(turn_number),
+  world_hash   TEXT NOT NULL,
+  entities     TEXT NOT NULL,
+  events       TEXT NOT NULL,
+  created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
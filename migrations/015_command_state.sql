@@ -0,0 +1,31 @@
+--  taygete - a game engine for a game.
+--  Copyright (c) 2026 Michael D Henderson.
+--
+--  This program is free software: you can redistribute it and/or modify
+--  it under the terms of the GNU Affero General Public License as published by
+--  the Free Software Foundation, either version 3 of the License, or
+--  (at your option) any later version.
+--
+--  This program is distributed in the hope that it will be useful,
+--  but WITHOUT ANY WARRANTY; without even the implied warranty of
+--  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+--  GNU Affero General Public License for more details.
+--
+--  You should have received a copy of the GNU Affero General Public License
+--  along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+-- Command state saved with the world, one row per unit with an order
+-- loaded or running. args_json holds the a..h arguments, parse_json the
+-- parsed words of raw_line and wait_parse_json the conditions of a WAIT.
+-- second_wait, the delay from auto attacks, and fuzzy, set when the
+-- command name was guessed, matter only to saves made mid-turn.
+ALTER TABLE commands ADD COLUMN wait INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE commands ADD COLUMN second_wait INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE commands ADD COLUMN fuzzy INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE commands ADD COLUMN parse_json TEXT;
+ALTER TABLE commands ADD COLUMN wait_parse_json TEXT;
+CREATE UNIQUE INDEX commands_who_id ON commands (who_id);
+
+-- The last day of the turn saved while the turn runs. RunTurn resumes a
+-- turn left 'processing' from the day after it.
+ALTER TABLE turns ADD COLUMN day INTEGER NOT NULL DEFAULT 0;
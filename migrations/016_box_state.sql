@@ -0,0 +1,103 @@
+--  taygete - a game engine for a game.
+--  Copyright (c) 2026 Michael D Henderson.
+--
+--  This program is free software: you can redistribute it and/or modify
+--  it under the terms of the GNU Affero General Public License as published by
+--  the Free Software Foundation, either version 3 of the License, or
+--  (at your option) any later version.
+--
+--  This program is distributed in the hope that it will be useful,
+--  but WITHOUT ANY WARRANTY; without even the implied warranty of
+--  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+--  GNU Affero General Public License for more details.
+--
+--  You should have received a copy of the GNU Affero General Public License
+--  along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+-- The rest of the C box model: player knowledge and unit lists, the
+-- whole of entity_subloc and entity_misc. Where an older table already
+-- holds some of these fields (locations, ships, storms, dead_bodies)
+-- it is still written, but these tables are read last and win.
+
+-- Player flags kept by the C player file.
+ALTER TABLE players ADD COLUMN first_tower INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE players ADD COLUMN compuserve INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE players ADD COLUMN broken_mailer INTEGER NOT NULL DEFAULT 0;
+
+-- Entities a player knows of: visited, seen in lore or encountered.
+CREATE TABLE player_knowledge (
+  player_id    INTEGER NOT NULL REFERENCES entities(id),
+  entity_id    INTEGER NOT NULL,
+  PRIMARY KEY (player_id, entity_id)
+);
+
+-- A player's units (kind 'unit') and unformed nobles (kind 'unformed'),
+-- in order.
+CREATE TABLE player_units (
+  player_id    INTEGER NOT NULL REFERENCES entities(id),
+  kind         TEXT NOT NULL,
+  seq          INTEGER NOT NULL,
+  unit_id      INTEGER NOT NULL,
+  PRIMARY KEY (player_id, kind, seq)
+);
+
+-- Unformed nobles were kept only as entities owned by a player.
+INSERT INTO player_units (player_id, kind, seq, unit_id)
+SELECT owner_player_id, 'unformed',
+       ROW_NUMBER() OVER (PARTITION BY owner_player_id ORDER BY id) - 1, id
+FROM entities
+WHERE kind = 12 AND owner_player_id IS NOT NULL;
+
+-- entity_subloc: structures, sublocations and ships.
+CREATE TABLE sublocs (
+  id               INTEGER PRIMARY KEY REFERENCES entities(id),
+  opium_econ       INTEGER NOT NULL DEFAULT 0,
+  defense          INTEGER NOT NULL DEFAULT 0,
+  loot             INTEGER NOT NULL DEFAULT 0,
+  damage           INTEGER NOT NULL DEFAULT 0,
+  galley_ram       INTEGER NOT NULL DEFAULT 0,
+  shaft_depth      INTEGER NOT NULL DEFAULT 0,
+  castle_lev       INTEGER NOT NULL DEFAULT 0,
+  build_materials  INTEGER NOT NULL DEFAULT 0,
+  effort_required  INTEGER NOT NULL DEFAULT 0,
+  effort_given     INTEGER NOT NULL DEFAULT 0,
+  moving           INTEGER NOT NULL DEFAULT 0,
+  capacity         INTEGER NOT NULL DEFAULT 0,
+  safe             INTEGER NOT NULL DEFAULT 0,
+  major            INTEGER NOT NULL DEFAULT 0,
+  prominence       INTEGER NOT NULL DEFAULT 0,
+  uldim_flag       INTEGER NOT NULL DEFAULT 0,
+  summer_flag      INTEGER NOT NULL DEFAULT 0,
+  quest_late       INTEGER NOT NULL DEFAULT 0,
+  tunnel_level     INTEGER NOT NULL DEFAULT 0,
+  link_when        INTEGER NOT NULL DEFAULT 0,
+  link_open        INTEGER NOT NULL DEFAULT 0
+);
+
+-- The lists of an entity_subloc, in order: list is one of teaches,
+-- near_cities, link_to, link_from or bound_storms.
+CREATE TABLE subloc_lists (
+  subloc_id    INTEGER NOT NULL REFERENCES sublocs(id),
+  list         TEXT NOT NULL,
+  seq          INTEGER NOT NULL,
+  value        INTEGER NOT NULL,
+  PRIMARY KEY (subloc_id, list, seq)
+);
+
+-- entity_misc. The display banner is entities.display_name.
+CREATE TABLE entity_misc (
+  id           INTEGER PRIMARY KEY REFERENCES entities(id),
+  npc_created  INTEGER NOT NULL DEFAULT 0,
+  npc_home     INTEGER NOT NULL DEFAULT 0,
+  npc_cookie   INTEGER NOT NULL DEFAULT 0,
+  summoned_by  INTEGER NOT NULL DEFAULT 0,
+  save_name    TEXT,
+  old_lord     INTEGER NOT NULL DEFAULT 0,
+  only_vuln    INTEGER NOT NULL DEFAULT 0,
+  garr_castle  INTEGER NOT NULL DEFAULT 0,
+  bind_storm   INTEGER NOT NULL DEFAULT 0,
+  storm_str    INTEGER NOT NULL DEFAULT 0,
+  npc_dir      INTEGER NOT NULL DEFAULT 0,
+  mine_delay   INTEGER NOT NULL DEFAULT 0,
+  cmd_allow    INTEGER NOT NULL DEFAULT 0
+);
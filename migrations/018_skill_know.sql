@@ -0,0 +1,21 @@
+--  taygete - a game engine for a game.
+--  Copyright (c) 2026 Michael D Henderson.
+--
+--  This program is free software: you can redistribute it and/or modify
+--  it under the terms of the GNU Affero General Public License as published by
+--  the Free Software Foundation, either version 3 of the License, or
+--  (at your option) any later version.
+--
+--  This program is distributed in the hope that it will be useful,
+--  but WITHOUT ANY WARRANTY; without even the implied warranty of
+--  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+--  GNU Affero General Public License for more details.
+--
+--  You should have received a copy of the GNU Affero General Public License
+--  along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+-- How far a noble has got with a skill (skill_ent.know): 1 while still
+-- studying it (SKILL_learning), 2 once learned (SKILL_know). Rows written
+-- before this column existed are taken as learned. Dead bodies only keep
+-- learned skills, so dead_body_skills needs no such column.
+ALTER TABLE char_skills ADD COLUMN know INTEGER NOT NULL DEFAULT 2;
@@ -19,30 +19,26 @@
 
 package taygete
 
-import "strings"
-
-// Global state for ocean character tracking
-var ocean_chars []int // characters flying over ocean
+import (
+	"fmt"
+	"strings"
+)
 
 // exit_opposite is defined in glob.go
 
-// show_to_garrison controls whether garrison units should see messages.
-// This is toggled when movements pass by garrisons.
-var show_to_garrison = false
-
 // departure_message outputs a message when a character departs a location.
 // Only shows if the character is not hidden, weather permits, and destination is not hidden.
 // Ported from src/move.c lines 11-73.
-func departure_message(who int, v *exit_view) {
-	if !valid_box(who) {
+func (e *Engine) departure_message(who int, v *exit_view) {
+	if !e.valid_box(who) {
 		return
 	}
 
-	if char_really_hidden(who) {
+	if e.char_really_hidden(who) {
 		return
 	}
 
-	if loc_depth(v.orig) == LOC_province && weather_here(v.orig, sub_fog) != 0 {
+	if e.loc_depth(v.orig) == LOC_province && e.weather_here(v.orig, sub_fog) != 0 {
 		return
 	}
 
@@ -50,18 +46,18 @@ func departure_message(who int, v *exit_view) {
 		return
 	}
 
-	desc := liner_desc(who)
+	desc := e.liner_desc(who)
 
 	to := ""
-	if subloc(v.destination) == v.orig {
-		to = sout(" entered %s", box_name(v.destination))
-	} else if subloc(v.orig) == v.destination {
-		to = sout(" exited %s", box_name(v.orig))
-	} else if viewloc(v.orig) != viewloc(v.destination) {
+	if e.subloc(v.destination) == v.orig {
+		to = sout(" entered %s", e.box_name(v.destination))
+	} else if e.subloc(v.orig) == v.destination {
+		to = sout(" exited %s", e.box_name(v.orig))
+	} else if e.viewloc(v.orig) != e.viewloc(v.destination) {
 		if v.direction >= DIR_N && v.direction <= DIR_W {
 			to = sout(" went %s", full_dir_s[v.direction])
 		} else {
-			to = sout(" left for %s", box_name(v.destination))
+			to = sout(" left for %s", e.box_name(v.destination))
 		}
 	} else {
 		return
@@ -76,39 +72,39 @@ func departure_message(who int, v *exit_view) {
 		with = "."
 	}
 
-	if viewloc(v.orig) != viewloc(v.destination) {
-		garr := garrison_here(v.orig)
+	if e.viewloc(v.orig) != e.viewloc(v.destination) {
+		garr := e.garrison_here(v.orig)
 		if garr != 0 && garrison_notices(garr, who) {
-			show_to_garrison = true
+			e.globals.show_to_garrison = true
 		}
 	}
 
 	wout(v.orig, "%s%s%s%s", desc, comma, to, with)
 	show_chars_below(v.orig, who)
 
-	show_to_garrison = false
+	e.globals.show_to_garrison = false
 }
 
 // arrival_message outputs a message when a character arrives at a location.
 // Only shows if the character is not hidden and weather permits.
 // Ported from src/move.c lines 77-133.
-func arrival_message(who int, v *exit_view) {
-	if char_really_hidden(who) {
+func (e *Engine) arrival_message(who int, v *exit_view) {
+	if e.char_really_hidden(who) {
 		return
 	}
 
-	if loc_depth(v.destination) == LOC_province && weather_here(v.destination, sub_fog) != 0 {
+	if e.loc_depth(v.destination) == LOC_province && e.weather_here(v.destination, sub_fog) != 0 {
 		return
 	}
 
-	desc := liner_desc(who)
+	desc := e.liner_desc(who)
 
 	from := ""
 	if v.orig_hidden == 0 {
 		if v.direction >= DIR_N && v.direction <= DIR_W {
 			from = sout(" from the %s", full_dir_s[exit_opposite[v.direction]])
 		} else {
-			from = sout(" from %s", box_name(v.orig))
+			from = sout(" from %s", e.box_name(v.orig))
 		}
 	}
 
@@ -121,17 +117,17 @@ func arrival_message(who int, v *exit_view) {
 		with = "."
 	}
 
-	if viewloc(v.orig) != viewloc(v.destination) {
-		garr := garrison_here(v.destination)
+	if e.viewloc(v.orig) != e.viewloc(v.destination) {
+		garr := e.garrison_here(v.destination)
 		if garr != 0 {
 			if garrison_notices(garr, who) {
-				show_to_garrison = true
+				e.globals.show_to_garrison = true
 			}
 			if garrison_spot_check(garr, who) {
-				indent += 3
+				e.globals.indent += 3
 				wout(garr, "%s%s", desc, with)
 				show_chars_below(garr, who)
-				indent -= 3
+				e.globals.indent -= 3
 			}
 		}
 	}
@@ -139,24 +135,24 @@ func arrival_message(who int, v *exit_view) {
 	wout(v.destination, "%s%s arrived%s%s", desc, comma, from, with)
 	show_chars_below(v.destination, who)
 
-	show_to_garrison = false
+	e.globals.show_to_garrison = false
 }
 
 // discover_road marks both ends of a hidden road as known to the character and stack.
 // Ported from src/move.c lines 140-162.
-func discover_road(who, where int, v *exit_view) {
-	l := exits_from_loc(who, v.destination)
+func (e *Engine) discover_road(who, where int, v *exit_view) {
+	l := e.exits_from_loc(who, v.destination)
 
 	for _, exit := range l {
 		if exit.road != 0 && exit.destination == where {
-			set_known(who, exit.road)
-			set_known(who, v.road)
+			e.set_known(who, exit.road)
+			e.set_known(who, v.road)
 
 			var chars []int
-			loop_char_here(who, &chars)
+			e.loop_char_here(who, &chars)
 			for _, j := range chars {
-				set_known(j, exit.road)
-				set_known(j, v.road)
+				e.set_known(j, exit.road)
+				e.set_known(j, v.road)
 			}
 		}
 	}
@@ -190,60 +186,60 @@ func restore_v_array(c *command, v *exit_view) {
 // suspend_stack_actions marks all characters in a stack as moving.
 // This prevents them from executing orders while in transit.
 // Ported from src/move.c lines 457-467.
-func suspend_stack_actions(who int) {
+func (e *Engine) suspend_stack_actions(who int) {
 	var stackMembers []int
-	loop_stack(who, &stackMembers)
+	e.loop_stack(who, &stackMembers)
 	for _, i := range stackMembers {
-		p_char(i).moving = teg.globals.sysclock.days_since_epoch
+		e.p_char(i).moving = e.globals.sysclock.days_since_epoch
 	}
 }
 
 // restore_stack_actions_impl clears the moving flag for all characters in a stack.
 // This is called when movement completes.
 // Ported from src/move.c lines 470-480.
-func restore_stack_actions_impl(who int) {
+func (e *Engine) restore_stack_actions_impl(who int) {
 	var stackMembers []int
-	loop_stack(who, &stackMembers)
+	e.loop_stack(who, &stackMembers)
 	for _, i := range stackMembers {
-		p_char(i).moving = 0
+		e.p_char(i).moving = 0
 	}
 }
 
 // clear_guard_flag clears the guard flag for a character and all stacked beneath them.
 // Ported from src/move.c lines 483-497.
-func clear_guard_flag(who int) {
-	if kind(who) == T_char {
-		p_char(who).guard = FALSE
+func (e *Engine) clear_guard_flag(who int) {
+	if e.kind(who) == T_char {
+		e.p_char(who).guard = FALSE
 	}
 
 	var chars []int
-	loop_char_here(who, &chars)
+	e.loop_char_here(who, &chars)
 	for _, i := range chars {
-		p_char(i).guard = FALSE
+		e.p_char(i).guard = FALSE
 	}
 }
 
 // move_exit_land calculates the delay for land movement.
 // Returns -1 if movement is not possible due to weight constraints.
 // Ported from src/move.c lines 250-355.
-func move_exit_land(c *command, v *exit_view, show bool) int {
+func (e *Engine) move_exit_land(c *command, v *exit_view, show bool) int {
 	delay := v.distance
 	if delay == 0 {
 		return 0
 	}
 
-	terr := subkind(v.destination)
+	terr := e.subkind(v.destination)
 	swamp := (terr == sub_swamp || terr == sub_bog || terr == sub_pits)
 
 	var w weights
-	determine_stack_weights(c.who, &w)
+	e.determine_stack_weights(c.who, &w)
 
 	if delay > 1 && w.ride_cap >= w.ride_weight && !swamp {
 		delay -= delay / 2
 	} else {
 		if w.land_weight > w.land_cap*2 {
 			if show {
-				wout(c.who, "%s is too overloaded to travel.", box_name(c.who))
+				wout(c.who, "%s is too overloaded to travel.", e.box_name(c.who))
 			}
 			return -1
 		}
@@ -270,8 +266,8 @@ func move_exit_land(c *command, v *exit_view, show bool) int {
 		}
 	}
 
-	nobles := count_stack_move_nobles(c.who)
-	men := count_stack_figures(c.who) - nobles
+	nobles := e.count_stack_move_nobles(c.who)
+	men := e.count_stack_figures(c.who) - nobles
 	if nobles < 1 {
 		nobles = 1
 	}
@@ -301,10 +297,10 @@ func move_exit_land(c *command, v *exit_view, show bool) int {
 // move_exit_fly calculates the delay for flying movement.
 // Returns -1 if movement is not possible.
 // Ported from src/move.c lines 358-424.
-func move_exit_fly(c *command, v *exit_view, show bool) int {
+func (e *Engine) move_exit_fly(c *command, v *exit_view, show bool) int {
 	delay := v.distance
 
-	if subkind(v.destination) == sub_under || subkind(v.destination) == sub_tunnel {
+	if e.subkind(v.destination) == sub_under || e.subkind(v.destination) == sub_tunnel {
 		if show {
 			wout(c.who, "Cannot fly underground.")
 		}
@@ -320,17 +316,17 @@ func move_exit_fly(c *command, v *exit_view, show bool) int {
 	}
 
 	var w weights
-	determine_stack_weights(c.who, &w)
+	e.determine_stack_weights(c.who, &w)
 
 	if w.fly_cap < w.fly_weight {
 		if show {
-			wout(c.who, "%s is too overloaded to fly.", box_name(c.who))
+			wout(c.who, "%s is too overloaded to fly.", e.box_name(c.who))
 		}
 		return -1
 	}
 
-	nobles := count_stack_move_nobles(c.who)
-	men := count_stack_figures(c.who) - nobles
+	nobles := e.count_stack_move_nobles(c.who)
+	men := e.count_stack_figures(c.who) - nobles
 	if nobles < 1 {
 		nobles = 1
 	}
@@ -359,7 +355,7 @@ func move_exit_fly(c *command, v *exit_view, show bool) int {
 
 // land_check validates if land movement to a destination is possible.
 // Ported from src/move.c lines 500-545.
-func land_check(c *command, v *exit_view, show bool) bool {
+func (e *Engine) land_check(c *command, v *exit_view, show bool) bool {
 	if v.water != 0 {
 		if show {
 			wout(c.who, "A sea-worthy ship is required for travel across water.")
@@ -376,18 +372,18 @@ func land_check(c *command, v *exit_view, show bool) bool {
 
 	if v.in_transit != 0 {
 		if show {
-			wout(c.who, "%s is underway. Boarding is not possible.", box_name(v.destination))
+			wout(c.who, "%s is underway. Boarding is not possible.", e.box_name(v.destination))
 		}
 		return false
 	}
 
-	if loc_depth(v.destination) == LOC_build &&
-		subkind(v.destination) != sub_sewer {
-		owner := building_owner(v.destination)
-		if owner != 0 && !will_admit(owner, c.who, v.destination) && v.direction != DIR_OUT {
+	if e.loc_depth(v.destination) == LOC_build &&
+		e.subkind(v.destination) != sub_sewer {
+		owner := e.building_owner(v.destination)
+		if owner != 0 && !e.will_admit(owner, c.who, v.destination) && v.direction != DIR_OUT {
 			if show {
-				wout(c.who, "%s refused to let us enter.", box_name(owner))
-				wout(owner, "Refused to let %s enter.", box_name(c.who))
+				wout(c.who, "%s refused to let us enter.", e.box_name(owner))
+				wout(owner, "Refused to let %s enter.", e.box_name(c.who))
 			}
 			return false
 		}
@@ -398,9 +394,9 @@ func land_check(c *command, v *exit_view, show bool) bool {
 
 // can_move_here checks if a character can move in a direction from where.
 // Ported from src/move.c lines 548-564.
-func can_move_here(where int, c *command) bool {
-	v := parse_exit_dir(c, where, "")
-	if v != nil && v.direction != DIR_IN && land_check(c, v, false) && move_exit_land(c, v, false) >= 0 {
+func (e *Engine) can_move_here(where int, c *command) bool {
+	v := e.parse_exit_dir(c, where, "")
+	if v != nil && v.direction != DIR_IN && e.land_check(c, v, false) && e.move_exit_land(c, v, false) >= 0 {
 		return true
 	}
 	return false
@@ -409,13 +405,13 @@ func can_move_here(where int, c *command) bool {
 // can_move_at_outer_level checks if movement is possible from an outer location.
 // Returns the depth difference if movement is possible, 0 otherwise.
 // Ported from src/move.c lines 567-581.
-func can_move_at_outer_level(where int, c *command) int {
-	outer := subloc(where)
-	for loc_depth(outer) > LOC_region {
-		if can_move_here(outer, c) {
-			return loc_depth(outer) - loc_depth(where)
+func (e *Engine) can_move_at_outer_level(where int, c *command) int {
+	outer := e.subloc(where)
+	for e.loc_depth(outer) > LOC_region {
+		if e.can_move_here(outer, c) {
+			return e.loc_depth(outer) - e.loc_depth(where)
 		}
-		outer = subloc(outer)
+		outer = e.subloc(outer)
 	}
 	return 0
 }
@@ -423,35 +419,35 @@ func can_move_at_outer_level(where int, c *command) int {
 // v_move is the MOVE command handler.
 // Handles land movement to a destination or direction.
 // Ported from src/move.c lines 584-682.
-func v_move(c *command) int {
-	where := subloc(c.who)
+func (e *Engine) v_move(c *command) int {
+	where := e.subloc(c.who)
 	checkOuter := true
 
-	if numargs(c) < 1 {
+	if e.numargs(c) < 1 {
 		wout(c.who, "Specify direction or destination to MOVE.")
 		return FALSE
 	}
 
 	var v *exit_view
-	for numargs(c) > 0 {
-		v = parse_exit_dir(c, where, "move")
+	for e.numargs(c) > 0 {
+		v = e.parse_exit_dir(c, where, "move")
 		if v != nil {
 			checkOuter = false
-			if land_check(c, v, true) {
+			if e.land_check(c, v, true) {
 				break
 			}
 			v = nil
 		}
-		cmd_shift(c)
+		e.cmd_shift(c)
 	}
 
-	if v == nil && checkOuter && can_move_at_outer_level(where, c) != 0 {
-		c.a = subloc(where)
-		v = parse_exit_dir(c, where, "")
+	if v == nil && checkOuter && e.can_move_at_outer_level(where, c) != 0 {
+		c.a = e.subloc(where)
+		v = e.parse_exit_dir(c, where, "")
 		if v != nil {
-			if move_exit_land(c, v, false) >= 0 {
+			if e.move_exit_land(c, v, false) >= 0 {
 				wout(c.who, "(assuming 'move out' first)")
-				prepend_order(player(c.who), c.who, cmd_to_string(c))
+				prepend_order(e.player(c.who), c.who, cmd_to_string(c))
 			}
 		}
 	}
@@ -460,19 +456,19 @@ func v_move(c *command) int {
 		return FALSE
 	}
 
-	delay := move_exit_land(c, v, true)
+	delay := e.move_exit_land(c, v, true)
 	if delay < 0 {
 		return FALSE
 	}
 
 	if v.hades_cost != 0 {
-		n := count_stack_figures(c.who)
+		n := e.count_stack_figures(c.who)
 		cost := v.hades_cost * n
 
 		log_write(LOG_SPECIAL, "%s (%s) tries to enter Hades",
-			box_name(player(c.who)), box_name(c.who))
+			e.box_name(e.player(c.who)), e.box_name(c.who))
 
-		if !autocharge(c.who, cost) {
+		if !e.autocharge(c.who, cost) {
 			wout(c.who, "Can't afford %s to enter Hades.", gold_s(cost))
 			return FALSE
 		}
@@ -483,57 +479,57 @@ func v_move(c *command) int {
 	v.distance = delay
 	c.wait = delay
 	save_v_array(c, v)
-	leave_stack(c.who)
+	e.leave_stack(c.who)
 
 	if delay > 0 {
 		vector_stack(c.who, true)
 		wout(VECT, "Travel to %s will take %s day%s.",
-			box_name(v.destination),
+			e.box_name(v.destination),
 			nice_num(delay),
 			add_s(delay))
 	}
 
-	suspend_stack_actions(c.who)
-	clear_guard_flag(c.who)
+	e.suspend_stack_actions(c.who)
+	e.clear_guard_flag(c.who)
 
 	if delay > 1 {
-		prisoner_movement_escape_check(c.who)
+		e.prisoner_movement_escape_check(c.who)
 	}
 
-	departure_message(c.who, v)
+	e.departure_message(c.who, v)
 	return TRUE
 }
 
 // d_move is the completion handler for MOVE command.
 // Called when land movement completes.
 // Ported from src/move.c lines 769-825.
-func d_move(c *command) int {
+func (e *Engine) d_move(c *command) int {
 	var v exit_view
 	restore_v_array(c, &v)
 
-	if !valid_box(v.destination) {
+	if !e.valid_box(v.destination) {
 		wout(c.who, "Your destination no longer exists!")
 		return FALSE
 	}
 
 	if v.road != 0 {
-		discover_road(c.who, subloc(c.who), &v)
+		e.discover_road(c.who, e.subloc(c.who), &v)
 	}
 
 	vector_stack(c.who, true)
-	wout(VECT, "Arrival at %s.", box_name(v.destination))
+	wout(VECT, "Arrival at %s.", e.box_name(v.destination))
 
-	if loc_depth(v.destination) == LOC_province &&
-		viewloc(subloc(c.who)) != viewloc(v.destination) &&
-		weather_here(v.destination, sub_fog) != 0 {
+	if e.loc_depth(v.destination) == LOC_province &&
+		e.viewloc(e.subloc(c.who)) != e.viewloc(v.destination) &&
+		e.weather_here(v.destination, sub_fog) != 0 {
 		wout(VECT, "The province is blanketed in fog.")
 	}
 
-	restore_stack_actions_impl(c.who)
-	move_stack_impl(c.who, v.destination)
+	e.restore_stack_actions_impl(c.who)
+	e.move_stack_impl(c.who, v.destination)
 
-	if viewloc(v.orig) != viewloc(v.destination) {
-		arrival_message(c.who, &v)
+	if e.viewloc(v.orig) != e.viewloc(v.destination) {
+		e.arrival_message(c.who, &v)
 	}
 
 	return TRUE
@@ -542,15 +538,15 @@ func d_move(c *command) int {
 // touch_loc_after_move touches a location for all characters in a stack.
 // This updates visibility and triggers location events.
 // Ported from src/move.c lines 685-700.
-func touch_loc_after_move(who, where int) {
-	if kind(who) == T_char {
+func (e *Engine) touch_loc_after_move(who, where int) {
+	if e.kind(who) == T_char {
 		touch_loc(who)
 	}
 
 	var chars []int
-	loop_char_here(who, &chars)
+	e.loop_char_here(who, &chars)
 	for _, i := range chars {
-		if !is_prisoner(i) {
+		if !e.is_prisoner(i) {
 			touch_loc(i)
 		}
 	}
@@ -559,71 +555,71 @@ func touch_loc_after_move(who, where int) {
 // move_stack_impl is the core implementation that moves a character and stack to a new location.
 // Updates location, known information, weather, and checks for special region effects.
 // Ported from src/move.c lines 703-766.
-func move_stack_impl(who, where int) {
-	if kind(who) != T_char {
+func (e *Engine) move_stack_impl(who, where int) {
+	if e.kind(who) != T_char {
 		panic("move_stack_impl: who is not a character")
 	}
 
-	if !in_faery(subloc(who)) && in_faery(where) {
-		log_write(LOG_SPECIAL, "%s enters Faery at %s.", box_name(who), box_name(where))
+	if !e.in_faery(e.subloc(who)) && e.in_faery(where) {
+		log_write(LOG_SPECIAL, "%s enters Faery at %s.", e.box_name(who), e.box_name(where))
 	}
 
-	set_where(who, where)
-	mark_loc_stack_known(who, where)
-	touch_loc_after_move(who, where)
+	e.set_where(who, where)
+	e.mark_loc_stack_known(who, where)
+	e.touch_loc_after_move(who, where)
 	update_weather_view_locs(who, where)
-	clear_contacts(who)
+	e.clear_contacts(who)
 
-	if subkind(where) == sub_city {
+	if e.subkind(where) == sub_city {
 		var stackMembers []int
-		loop_stack(who, &stackMembers)
+		e.loop_stack(who, &stackMembers)
 		for _, i := range stackMembers {
 			match_trades(i)
 		}
 	}
 
-	if subkind(where) == sub_ocean {
-		p := p_char(who)
+	if e.subkind(where) == sub_ocean {
+		p := e.p_char(who)
 		if p.time_flying == 0 {
 			p.time_flying++
-			ocean_chars = append(ocean_chars, who)
+			e.globals.ocean_chars = append(e.globals.ocean_chars, who)
 		}
 	}
 
-	if subkind(where) != sub_ocean {
-		p := rp_char(who)
+	if e.subkind(where) != sub_ocean {
+		p := e.rp_char(who)
 		if p != nil && p.time_flying != 0 {
 			p.time_flying = 0
-			for i, ch := range ocean_chars {
+			for i, ch := range e.globals.ocean_chars {
 				if ch == who {
-					ocean_chars = append(ocean_chars[:i], ocean_chars[i+1:]...)
+					e.globals.ocean_chars = append(e.globals.ocean_chars[:i], e.globals.ocean_chars[i+1:]...)
 					break
 				}
 			}
 		}
 	}
 
-	if loc_depth(where) == LOC_province &&
-		subkind(where) != sub_ocean &&
-		in_faery(where) {
+	if e.loc_depth(where) == LOC_province &&
+		e.subkind(where) != sub_ocean &&
+		e.in_faery(where) {
 		faery_attack_check(who, where)
 	}
 
-	if loc_depth(where) == LOC_province &&
-		subkind(where) != sub_ocean &&
-		in_hades(where) {
+	if e.loc_depth(where) == LOC_province &&
+		e.subkind(where) != sub_ocean &&
+		e.in_hades(where) {
 		hades_attack_check(who, where)
 	}
 }
 
 // init_ocean_chars initializes the ocean_chars list at game start.
 // Ported from src/move.c lines 828-842.
-func init_ocean_chars() {
-	ocean_chars = nil
-	for _, i := range teg.Characters() {
-		where := subloc(i)
-		if subkind(where) == sub_ocean {
-			ocean_chars = append(ocean_chars, i)
+func (e *Engine) init_ocean_chars() {
+	e.globals.ocean_chars = nil
+	for _, i := range e.Characters() {
+		where := e.subloc(i)
+		if e.subkind(where) == sub_ocean {
+			e.globals.ocean_chars = append(e.globals.ocean_chars, i)
 		}
 	}
 }
@@ -631,19 +627,19 @@ func init_ocean_chars() {
 // check_ocean_chars checks flying units over ocean for drowning.
 // Units that have been flying over ocean for too long will plunge into the sea.
 // Ported from src/move.c lines 845-888.
-func check_ocean_chars() {
-	charsCopy := make([]int, len(ocean_chars))
-	copy(charsCopy, ocean_chars)
+func (e *Engine) check_ocean_chars() {
+	charsCopy := make([]int, len(e.globals.ocean_chars))
+	copy(charsCopy, e.globals.ocean_chars)
 
 	for _, who := range charsCopy {
-		where := subloc(who)
-		p := p_char(who)
+		where := e.subloc(who)
+		p := e.p_char(who)
 
-		if !alive(who) || subkind(where) != sub_ocean {
+		if !e.alive(who) || e.subkind(where) != sub_ocean {
 			p.time_flying = 0
-			for i, ch := range ocean_chars {
+			for i, ch := range e.globals.ocean_chars {
 				if ch == who {
-					ocean_chars = append(ocean_chars[:i], ocean_chars[i+1:]...)
+					e.globals.ocean_chars = append(e.globals.ocean_chars[:i], e.globals.ocean_chars[i+1:]...)
 					break
 				}
 			}
@@ -656,31 +652,31 @@ func check_ocean_chars() {
 			continue
 		}
 
-		if stack_parent(who) != 0 {
+		if e.stack_parent(who) != 0 {
 			continue
 		}
 
 		vector_stack(who, true)
-		wout(VECT, "Flight can no longer be maintained. %s plunges into the sea.", box_name(who))
+		wout(VECT, "Flight can no longer be maintained. %s plunges into the sea.", e.box_name(who))
 
-		kill_stack_ocean(who)
+		e.kill_stack_ocean(who)
 	}
 }
 
 // fly_check validates if flying to a destination is possible.
 // Ported from src/move.c lines 891-912.
-func fly_check(c *command, v *exit_view) bool {
+func (e *Engine) fly_check(c *command, v *exit_view) bool {
 	if v.in_transit != 0 {
-		wout(c.who, "%s is underway. Boarding is not possible.", box_name(v.destination))
+		wout(c.who, "%s is underway. Boarding is not possible.", e.box_name(v.destination))
 		return false
 	}
 
-	if loc_depth(v.destination) == LOC_build &&
-		subkind(v.destination) != sub_sewer {
-		owner := building_owner(v.destination)
-		if owner != 0 && !will_admit(owner, c.who, v.destination) && v.direction != DIR_OUT {
-			wout(c.who, "%s refused to let us enter.", box_name(owner))
-			wout(owner, "Refused to let %s enter.", box_name(c.who))
+	if e.loc_depth(v.destination) == LOC_build &&
+		e.subkind(v.destination) != sub_sewer {
+		owner := e.building_owner(v.destination)
+		if owner != 0 && !e.will_admit(owner, c.who, v.destination) && v.direction != DIR_OUT {
+			wout(c.who, "%s refused to let us enter.", e.box_name(owner))
+			wout(owner, "Refused to let %s enter.", e.box_name(c.who))
 			return false
 		}
 	}
@@ -690,9 +686,9 @@ func fly_check(c *command, v *exit_view) bool {
 
 // can_fly_here checks if a character can fly in a direction from where.
 // Ported from src/move.c lines 915-928.
-func can_fly_here(where int, c *command) bool {
-	v := parse_exit_dir(c, where, "")
-	if v != nil && v.direction != DIR_IN && move_exit_fly(c, v, false) >= 0 {
+func (e *Engine) can_fly_here(where int, c *command) bool {
+	v := e.parse_exit_dir(c, where, "")
+	if v != nil && v.direction != DIR_IN && e.move_exit_fly(c, v, false) >= 0 {
 		return true
 	}
 	return false
@@ -700,13 +696,13 @@ func can_fly_here(where int, c *command) bool {
 
 // can_fly_at_outer_level checks if flying is possible from an outer location.
 // Ported from src/move.c lines 931-945.
-func can_fly_at_outer_level(where int, c *command) int {
-	outer := subloc(where)
-	for loc_depth(outer) > LOC_region {
-		if can_fly_here(outer, c) {
-			return loc_depth(outer) - loc_depth(where)
+func (e *Engine) can_fly_at_outer_level(where int, c *command) int {
+	outer := e.subloc(where)
+	for e.loc_depth(outer) > LOC_region {
+		if e.can_fly_here(outer, c) {
+			return e.loc_depth(outer) - e.loc_depth(where)
 		}
-		outer = subloc(outer)
+		outer = e.subloc(outer)
 	}
 	return 0
 }
@@ -714,35 +710,35 @@ func can_fly_at_outer_level(where int, c *command) int {
 // v_fly is the FLY command handler.
 // Handles flying movement to a destination or direction.
 // Ported from src/move.c lines 948-1021.
-func v_fly(c *command) int {
-	where := subloc(c.who)
+func (e *Engine) v_fly(c *command) int {
+	where := e.subloc(c.who)
 	checkOuter := true
 
-	if numargs(c) < 1 {
+	if e.numargs(c) < 1 {
 		wout(c.who, "Specify direction or destination to FLY.")
 		return FALSE
 	}
 
 	var v *exit_view
-	for numargs(c) > 0 {
-		v = parse_exit_dir(c, where, "fly")
+	for e.numargs(c) > 0 {
+		v = e.parse_exit_dir(c, where, "fly")
 		if v != nil {
 			checkOuter = false
-			if fly_check(c, v) {
+			if e.fly_check(c, v) {
 				break
 			}
 			v = nil
 		}
-		cmd_shift(c)
+		e.cmd_shift(c)
 	}
 
-	if v == nil && checkOuter && can_fly_at_outer_level(where, c) != 0 {
-		c.a = subloc(where)
-		v = parse_exit_dir(c, where, "")
+	if v == nil && checkOuter && e.can_fly_at_outer_level(where, c) != 0 {
+		c.a = e.subloc(where)
+		v = e.parse_exit_dir(c, where, "")
 		if v != nil {
-			if move_exit_fly(c, v, false) >= 0 {
+			if e.move_exit_fly(c, v, false) >= 0 {
 				wout(c.who, "(assuming 'fly out' first)")
-				prepend_order(player(c.who), c.who, cmd_to_string(c))
+				prepend_order(e.player(c.who), c.who, cmd_to_string(c))
 			}
 		}
 	}
@@ -751,7 +747,7 @@ func v_fly(c *command) int {
 		return FALSE
 	}
 
-	delay := move_exit_fly(c, v, true)
+	delay := e.move_exit_fly(c, v, true)
 	if delay < 0 {
 		return FALSE
 	}
@@ -759,45 +755,45 @@ func v_fly(c *command) int {
 	v.distance = delay
 	c.wait = delay
 	save_v_array(c, v)
-	leave_stack(c.who)
+	e.leave_stack(c.who)
 
 	if delay > 0 {
 		vector_stack(c.who, true)
 		wout(VECT, "Flying to %s will take %s day%s.",
-			box_name(v.destination),
+			e.box_name(v.destination),
 			nice_num(delay),
 			add_s(delay))
 	}
 
-	suspend_stack_actions(c.who)
-	clear_guard_flag(c.who)
-	departure_message(c.who, v)
+	e.suspend_stack_actions(c.who)
+	e.clear_guard_flag(c.who)
+	e.departure_message(c.who, v)
 
 	return TRUE
 }
 
 // d_fly is the completion handler for FLY command.
 // Ported from src/move.c lines 1024-1072.
-func d_fly(c *command) int {
+func (e *Engine) d_fly(c *command) int {
 	var v exit_view
 	restore_v_array(c, &v)
 
 	if v.road != 0 {
-		discover_road(c.who, subloc(c.who), &v)
+		e.discover_road(c.who, e.subloc(c.who), &v)
 	}
 
 	vector_stack(c.who, true)
-	wout(VECT, "Arrival at %s.", box_name(v.destination))
+	wout(VECT, "Arrival at %s.", e.box_name(v.destination))
 
-	if loc_depth(v.destination) == LOC_province &&
-		viewloc(subloc(c.who)) != viewloc(v.destination) &&
-		weather_here(v.destination, sub_fog) != 0 {
+	if e.loc_depth(v.destination) == LOC_province &&
+		e.viewloc(e.subloc(c.who)) != e.viewloc(v.destination) &&
+		e.weather_here(v.destination, sub_fog) != 0 {
 		wout(VECT, "The province is blanketed in fog.")
 	}
 
-	restore_stack_actions_impl(c.who)
-	move_stack_impl(c.who, v.destination)
-	arrival_message(c.who, &v)
+	e.restore_stack_actions_impl(c.who)
+	e.move_stack_impl(c.who, v.destination)
+	e.arrival_message(c.who, &v)
 
 	return TRUE
 }
@@ -805,65 +801,65 @@ func d_fly(c *command) int {
 // v_exit is the EXIT command handler.
 // Synonym for 'move out'.
 // Ported from src/move.c lines 1079-1088.
-func v_exit(c *command) int {
+func (e *Engine) v_exit(c *command) int {
 	c.a = 0 // clear destination, we'll parse "out"
 	// Set up parse for "move out"
-	oly_parse(c, "move out")
-	return v_move(c)
+	e.oly_parse(c, "move out")
+	return e.v_move(c)
 }
 
 // v_enter is the ENTER command handler.
 // Enters a sublocation or 'move in'.
 // Ported from src/move.c lines 1091-1107.
-func v_enter(c *command) int {
-	if numargs(c) < 1 {
-		oly_parse(c, "move in")
-		return v_move(c)
+func (e *Engine) v_enter(c *command) int {
+	if e.numargs(c) < 1 {
+		e.oly_parse(c, "move in")
+		return e.v_move(c)
 	}
-	oly_parse(c, sout("move %s", get_parse_arg(c, 1)))
-	return v_move(c)
+	e.oly_parse(c, sout("move %s", get_parse_arg(c, 1)))
+	return e.v_move(c)
 }
 
 // v_north is the NORTH command handler.
 // Ported from src/move.c lines 1110-1119.
-func v_north(c *command) int {
-	oly_parse(c, "move north")
-	return v_move(c)
+func (e *Engine) v_north(c *command) int {
+	e.oly_parse(c, "move north")
+	return e.v_move(c)
 }
 
 // v_south is the SOUTH command handler.
 // Ported from src/move.c lines 1122-1131.
-func v_south(c *command) int {
-	oly_parse(c, "move south")
-	return v_move(c)
+func (e *Engine) v_south(c *command) int {
+	e.oly_parse(c, "move south")
+	return e.v_move(c)
 }
 
 // v_east is the EAST command handler.
 // Ported from src/move.c lines 1134-1143.
-func v_east(c *command) int {
-	oly_parse(c, "move east")
-	return v_move(c)
+func (e *Engine) v_east(c *command) int {
+	e.oly_parse(c, "move east")
+	return e.v_move(c)
 }
 
 // v_west is the WEST command handler.
 // Ported from src/move.c lines 1146-1155.
-func v_west(c *command) int {
-	oly_parse(c, "move west")
-	return v_move(c)
+func (e *Engine) v_west(c *command) int {
+	e.oly_parse(c, "move west")
+	return e.v_move(c)
 }
 
 // check_captain_loses_sailors is defined in cmd_transfer.go
 
 // move_exit_water calculates the delay for sailing movement.
 // Ported from src/move.c lines 1230-1301.
-func move_exit_water(c *command, v *exit_view, ship int, show bool) int {
+func (e *Engine) move_exit_water(c *command, v *exit_view, ship int, show bool) int {
 	delay := v.distance
 	handsShort := 0
-	where := subloc(ship)
+	where := e.subloc(ship)
 
-	switch subkind(ship) {
+	switch e.subkind(ship) {
 	case sub_roundship:
-		n := has_item(c.who, item_sailor) + has_item(c.who, item_pirate)
+		n := e.has_item(c.who, item_sailor) + e.has_item(c.who, item_pirate)
 		if n < 8 {
 			handsShort = 8 - n
 			s := "day"
@@ -880,7 +876,7 @@ func move_exit_water(c *command, v *exit_view, ship int, show bool) int {
 		}
 
 	case sub_galley:
-		n := has_item(c.who, item_sailor) + has_item(c.who, item_pirate)
+		n := e.has_item(c.who, item_sailor) + e.has_item(c.who, item_pirate)
 		if n < 14 {
 			handsShort = 14 - n
 			s := "day"
@@ -901,7 +897,7 @@ func move_exit_water(c *command, v *exit_view, ship int, show bool) int {
 	}
 
 	windBonus := 0
-	if subkind(ship) == sub_roundship && weather_here(where, sub_wind) != 0 && delay > 2 {
+	if e.subkind(ship) == sub_roundship && e.weather_here(where, sub_wind) != 0 && delay > 2 {
 		windBonus = 1
 		if show {
 			wout(c.who, "Favorable winds speed our progress.")
@@ -914,12 +910,12 @@ func move_exit_water(c *command, v *exit_view, ship int, show bool) int {
 
 // sail_depart_message outputs a message when a ship departs.
 // Ported from src/move.c lines 1304-1320.
-func sail_depart_message(ship int, v *exit_view) {
-	desc := liner_desc(ship)
+func (e *Engine) sail_depart_message(ship int, v *exit_view) {
+	desc := e.liner_desc(ship)
 
 	to := ""
 	if v.dest_hidden == 0 {
-		to = sout(" for %s.", box_name(v.destination))
+		to = sout(" for %s.", e.box_name(v.destination))
 	}
 
 	comma := ""
@@ -932,12 +928,12 @@ func sail_depart_message(ship int, v *exit_view) {
 
 // sail_arrive_message outputs a message when a ship arrives.
 // Ported from src/move.c lines 1323-1350.
-func sail_arrive_message(ship int, v *exit_view) {
-	desc := liner_desc(ship)
+func (e *Engine) sail_arrive_message(ship int, v *exit_view) {
+	desc := e.liner_desc(ship)
 
 	from := ""
 	if v.orig_hidden == 0 {
-		from = sout(" from %s", box_name(v.orig))
+		from = sout(" from %s", e.box_name(v.orig))
 	}
 
 	comma := ""
@@ -950,10 +946,10 @@ func sail_arrive_message(ship int, v *exit_view) {
 		with = "."
 	}
 
-	show_to_garrison = true
+	e.globals.show_to_garrison = true
 	wout(v.destination, "%s%s arrived%s%s", desc, comma, from, with)
 	show_owner_stack(v.destination, ship)
-	show_to_garrison = false
+	e.globals.show_to_garrison = false
 }
 
 // sail_check validates if sailing in a direction is possible.
@@ -978,9 +974,9 @@ func sail_check(c *command, v *exit_view, show bool) bool {
 
 // can_sail_here checks if a ship can sail in a direction from where.
 // Ported from src/move.c lines 1376-1391.
-func can_sail_here(where int, c *command, ship int) bool {
-	v := parse_exit_dir(c, where, "")
-	if v != nil && v.direction != DIR_IN && sail_check(c, v, false) && move_exit_water(c, v, ship, false) >= 0 {
+func (e *Engine) can_sail_here(where int, c *command, ship int) bool {
+	v := e.parse_exit_dir(c, where, "")
+	if v != nil && v.direction != DIR_IN && sail_check(c, v, false) && e.move_exit_water(c, v, ship, false) >= 0 {
 		return true
 	}
 	return false
@@ -988,23 +984,23 @@ func can_sail_here(where int, c *command, ship int) bool {
 
 // can_sail_at_outer_level checks if sailing is possible from an outer location.
 // Ported from src/move.c lines 1394-1432.
-func can_sail_at_outer_level(ship, where int, c *command) int {
-	if ship_cap(ship) > 0 {
-		loaded := ship_weight(ship) * 100 / ship_cap(ship)
+func (e *Engine) can_sail_at_outer_level(ship, where int, c *command) int {
+	if e.ship_cap(ship) > 0 {
+		loaded := e.ship_weight(ship) * 100 / e.ship_cap(ship)
 		if loaded > 100 {
-			wout(c.who, "%s is too overloaded to sail.", box_name(ship))
+			wout(c.who, "%s is too overloaded to sail.", e.box_name(ship))
 			wout(c.who, "(ship capacity = %d, damage = %d, load = %d)",
-				ship_cap_raw(ship), loc_damage(ship), ship_weight(ship))
+				e.ship_cap_raw(ship), e.loc_damage(ship), e.ship_weight(ship))
 			return 0
 		}
 	}
 
-	outer := subloc(where)
-	for loc_depth(outer) > LOC_region {
-		if can_sail_here(outer, c, ship) {
-			return loc_depth(outer) - loc_depth(where)
+	outer := e.subloc(where)
+	for e.loc_depth(outer) > LOC_region {
+		if e.can_sail_here(outer, c, ship) {
+			return e.loc_depth(outer) - e.loc_depth(where)
 		}
-		outer = subloc(outer)
+		outer = e.subloc(outer)
 	}
 
 	return 0
@@ -1013,40 +1009,40 @@ func can_sail_at_outer_level(ship, where int, c *command) int {
 // v_sail is the SAIL command handler.
 // Handles ship sailing to a destination or direction.
 // Ported from src/move.c lines 1435-1572.
-func v_sail(c *command) int {
-	ship := subloc(c.who)
+func (e *Engine) v_sail(c *command) int {
+	ship := e.subloc(c.who)
 	checkOuter := true
 
-	if !is_ship(ship) {
-		sk := subkind(ship)
+	if !e.is_ship(ship) {
+		sk := e.subkind(ship)
 		if sk == sub_galley_notdone || sk == sub_roundship_notdone {
-			wout(c.who, "%s is not yet completed.", box_name(ship))
+			wout(c.who, "%s is not yet completed.", e.box_name(ship))
 		} else {
 			wout(c.who, "Must be on a sea-worthy ship to sail.")
 		}
 		return FALSE
 	}
 
-	if building_owner(ship) != c.who {
+	if e.building_owner(ship) != c.who {
 		wout(c.who, "Only the captain of a ship may sail.")
 		return FALSE
 	}
 
-	if !has_skill_check(c.who, sk_pilot_ship) {
-		wout(c.who, "Knowledge of %s is required to sail.", box_name(sk_pilot_ship))
+	if !e.has_skill_check(c.who, sk_pilot_ship) {
+		wout(c.who, "Knowledge of %s is required to sail.", e.box_name(sk_pilot_ship))
 		return FALSE
 	}
 
-	if numargs(c) < 1 {
+	if e.numargs(c) < 1 {
 		wout(c.who, "Specify direction or destination to sail.")
 		return FALSE
 	}
 
-	outerLoc := subloc(ship)
+	outerLoc := e.subloc(ship)
 
 	var v *exit_view
-	for numargs(c) > 0 {
-		v = parse_exit_dir(c, outerLoc, "sail")
+	for e.numargs(c) > 0 {
+		v = e.parse_exit_dir(c, outerLoc, "sail")
 		if v != nil {
 			checkOuter = false
 			if sail_check(c, v, true) {
@@ -1054,16 +1050,16 @@ func v_sail(c *command) int {
 			}
 			v = nil
 		}
-		cmd_shift(c)
+		e.cmd_shift(c)
 	}
 
-	if v == nil && checkOuter && can_sail_at_outer_level(ship, outerLoc, c) != 0 {
-		c.a = subloc(outerLoc)
-		v = parse_exit_dir(c, outerLoc, "")
+	if v == nil && checkOuter && e.can_sail_at_outer_level(ship, outerLoc, c) != 0 {
+		c.a = e.subloc(outerLoc)
+		v = e.parse_exit_dir(c, outerLoc, "")
 		if v != nil {
-			if move_exit_water(c, v, ship, false) >= 0 {
+			if e.move_exit_water(c, v, ship, false) >= 0 {
 				wout(c.who, "(assuming 'sail out' first)")
-				prepend_order(player(c.who), c.who, cmd_to_string(c))
+				prepend_order(e.player(c.who), c.who, cmd_to_string(c))
 			}
 		}
 	}
@@ -1072,12 +1068,12 @@ func v_sail(c *command) int {
 		return FALSE
 	}
 
-	if ship_cap(ship) > 0 {
-		loaded := ship_weight(ship) * 100 / ship_cap(ship)
+	if e.ship_cap(ship) > 0 {
+		loaded := e.ship_weight(ship) * 100 / e.ship_cap(ship)
 		if loaded > 100 {
-			wout(c.who, "%s is too overloaded to sail.", box_name(ship))
+			wout(c.who, "%s is too overloaded to sail.", e.box_name(ship))
 			wout(c.who, "(ship capacity = %d, damage = %d, load = %d)",
-				ship_cap_raw(ship), loc_damage(ship), ship_weight(ship))
+				e.ship_cap_raw(ship), e.loc_damage(ship), e.ship_weight(ship))
 			return FALSE
 		}
 	}
@@ -1087,7 +1083,7 @@ func v_sail(c *command) int {
 		return FALSE
 	}
 
-	delay := move_exit_water(c, v, ship, true)
+	delay := e.move_exit_water(c, v, ship, true)
 	if delay < 0 {
 		return FALSE
 	}
@@ -1100,17 +1096,17 @@ func v_sail(c *command) int {
 		vector_char_here(c.who)
 		vector_add(c.who)
 		wout(VECT, "Sailing to %s will take %s day%s.",
-			box_name(v.destination),
+			e.box_name(v.destination),
 			nice_num(delay),
 			add_s(delay))
 	}
 
-	sail_depart_message(ship, v)
+	e.sail_depart_message(ship, v)
 
-	p_subloc(ship).moving = teg.globals.sysclock.days_since_epoch
+	e.p_subloc(ship).moving = e.globals.sysclock.days_since_epoch
 
-	if ferry_horn(ship) != 0 {
-		p_magic(ship).ferry_flag = 0
+	if e.ferry_horn(ship) != 0 {
+		e.p_magic(ship).ferry_flag = 0
 	}
 
 	return TRUE
@@ -1118,13 +1114,13 @@ func v_sail(c *command) int {
 
 // d_sail is the completion handler for SAIL command.
 // Ported from src/move.c lines 1575-1615.
-func d_sail(c *command) int {
-	ship := subloc(c.who)
+func (e *Engine) d_sail(c *command) int {
+	ship := e.subloc(c.who)
 
-	if !is_ship(ship) {
+	if !e.is_ship(ship) {
 		return FALSE
 	}
-	if building_owner(ship) != c.who {
+	if e.building_owner(ship) != c.who {
 		return FALSE
 	}
 
@@ -1132,32 +1128,32 @@ func d_sail(c *command) int {
 	restore_v_array(c, &v)
 
 	if v.road != 0 {
-		discover_road(c.who, subloc(ship), &v)
+		e.discover_road(c.who, e.subloc(ship), &v)
 	}
 
 	vector_char_here(ship)
-	wout(VECT, "Arrival at %s.", box_name(v.destination))
+	wout(VECT, "Arrival at %s.", e.box_name(v.destination))
 
-	if loc_depth(v.destination) == LOC_province &&
-		viewloc(subloc(ship)) != viewloc(v.destination) &&
-		weather_here(v.destination, sub_fog) != 0 {
+	if e.loc_depth(v.destination) == LOC_province &&
+		e.viewloc(e.subloc(ship)) != e.viewloc(v.destination) &&
+		e.weather_here(v.destination, sub_fog) != 0 {
 		wout(VECT, "The province is blanketed in fog.")
 	}
 
-	p_subloc(ship).moving = 0
-	set_where(ship, v.destination)
-	mark_loc_stack_known(ship, v.destination)
-	move_bound_storms(ship, v.destination)
+	e.p_subloc(ship).moving = 0
+	e.set_where(ship, v.destination)
+	e.mark_loc_stack_known(ship, v.destination)
+	e.move_bound_storms(ship, v.destination)
 
-	if ferry_horn(ship) != 0 {
-		p_magic(ship).ferry_flag = 0
+	if e.ferry_horn(ship) != 0 {
+		e.p_magic(ship).ferry_flag = 0
 	}
 
-	touch_loc_after_move(ship, v.destination)
-	sail_arrive_message(ship, &v)
+	e.touch_loc_after_move(ship, v.destination)
+	e.sail_arrive_message(ship, &v)
 
 	if c.use_skill == 0 {
-		add_skill_experience(c.who, sk_pilot_ship)
+		e.add_skill_experience(c.who, sk_pilot_ship)
 	}
 
 	return TRUE
@@ -1166,17 +1162,17 @@ func d_sail(c *command) int {
 // i_sail is the interrupt handler for SAIL command.
 // Called when sailing is interrupted to reset the ship's moving state.
 // Ported from src/move.c lines 1623-1635.
-func i_sail(c *command) int {
-	ship := subloc(c.who)
+func (e *Engine) i_sail(c *command) int {
+	ship := e.subloc(c.who)
 
-	if !is_ship(ship) {
+	if !e.is_ship(ship) {
 		return TRUE
 	}
 
-	p_subloc(ship).moving = 0
+	e.p_subloc(ship).moving = 0
 
-	if ferry_horn(ship) != 0 {
-		p_magic(ship).ferry_flag = 0
+	if e.ferry_horn(ship) != 0 {
+		e.p_magic(ship).ferry_flag = 0
 	}
 
 	return TRUE
@@ -1186,14 +1182,14 @@ func i_sail(c *command) int {
 
 // count_stack_move_nobles counts the number of nobles in a stack for movement purposes.
 // Ported from src/u.c lines 1076-1090.
-func count_stack_move_nobles(who int) int {
+func (e *Engine) count_stack_move_nobles(who int) int {
 	sum := 1
-	pl := player(who)
+	pl := e.player(who)
 
 	var chars []int
-	loop_char_here(who, &chars)
+	e.loop_char_here(who, &chars)
 	for _, i := range chars {
-		if player(i) == pl || (!is_npc(i) && !is_prisoner(i)) {
+		if e.player(i) == pl || (!e.is_npc(i) && !e.is_prisoner(i)) {
 			sum++
 		}
 	}
@@ -1202,12 +1198,12 @@ func count_stack_move_nobles(who int) int {
 
 // count_stack_figures counts the total figures in a stack.
 // Ported from src/u.c lines 1093-1105.
-func count_stack_figures(who int) int {
+func (e *Engine) count_stack_figures(who int) int {
 	sum := 0
 	var stackMembers []int
-	loop_stack(who, &stackMembers)
+	e.loop_stack(who, &stackMembers)
 	for _, i := range stackMembers {
-		sum += count_any(i)
+		sum += e.count_any(i)
 	}
 	return sum
 }
@@ -1226,10 +1222,10 @@ func plural_man(n int) string {
 
 // Stub functions for dependencies not yet implemented
 
-// sout formats a string (simplified version for now).
+// sout formats a string.
+// Port of C sout() from u.c.
 func sout(format string, args ...any) string {
-	// Use fmt.Sprintf when available
-	return format // Placeholder - full implementation in later sprint
+	return fmt.Sprintf(format, args...)
 }
 
 // viewloc is defined in loc.go
@@ -1259,11 +1255,6 @@ func garrison_spot_check(garr, who int) bool {
 func update_weather_view_locs(who, where int) {
 }
 
-// clear_contacts clears a character's contacts.
-// Stub for now.
-func clear_contacts(who int) {
-}
-
 // match_trades matches trades for a character at a city.
 // Stub for now.
 func match_trades(who int) {
@@ -1294,37 +1285,13 @@ func hades_attack_check(who, where int) {
 func prepend_order(pl, who int, line string) {
 }
 
-// cmd_to_string converts a command to its string representation.
-// Stub for now.
+// cmd_to_string returns the original order line for a command.
 func cmd_to_string(c *command) string {
-	// c.line is *char (C-style), just return empty for now
-	return ""
-}
-
-// oly_parse parses an order line into a command.
-// Stub for now.
-func oly_parse(c *command, line string) bool {
-	return true
-}
-
-// find_command finds a command by name and returns its index.
-// Stub for now.
-func find_command(name string) int {
-	return -1
+	return c.line
 }
 
 // rp_command is defined in accessor.go
 
-// move_bound_storms moves storms bound to a ship.
-// Stub for now.
-func move_bound_storms(ship, where int) {
-}
-
-// add_skill_experience adds experience for using a skill.
-// Stub for now.
-func add_skill_experience(who, skill int) {
-}
-
 // ferry_horn is defined in accessor.go (returns schar, use ferry_horn(x) != 0)
 
 // display_owner returns a string describing the owner of a location.
@@ -1346,34 +1313,34 @@ func vector_clear() {
 // vector_add is defined in destruction.go
 
 // loop_stack collects all characters in a stack (including nested).
-func loop_stack(who int, l *[]int) {
+func (e *Engine) loop_stack(who int, l *[]int) {
 	*l = append(*l, who)
-	p := rp_loc_info(who)
+	p := e.rp_loc_info(who)
 	if p == nil {
 		return
 	}
 	for _, id := range p.here_list {
-		if kind(id) == T_char {
-			loop_stack(id, l)
+		if e.kind(id) == T_char {
+			e.loop_stack(id, l)
 		}
 	}
 }
 
 // count_any counts a character plus their "men" items.
-func count_any(who int) int {
+func (e *Engine) count_any(who int) int {
 	count := 1
-	inv := teg.globals.inventories[who]
-	for _, e := range inv {
-		if is_man_item(e.item) {
-			count += e.qty
+	inv := e.globals.inventories[who]
+	for _, it := range inv {
+		if e.is_man_item(it.item) {
+			count += it.qty
 		}
 	}
 	return count
 }
 
 // is_man_item returns true if an item represents "men" (soldiers, peasants, etc.)
-func is_man_item(item int) bool {
-	p := rp_item(item)
+func (e *Engine) is_man_item(item int) bool {
+	p := e.rp_item(item)
 	if p != nil {
 		return p.is_man_item != 0
 	}
@@ -1383,6 +1350,3 @@ func is_man_item(item int) bool {
 // is_npc is defined in lifecycle.go
 
 // alive is defined in accessor.go
-
-// indent is the current output indentation level.
-var indent = 0
@@ -103,14 +103,15 @@ func TestOceanCharsInit(t *testing.T) {
 	defer func() { teg = oldTeg }()
 
 	teg = &Engine{}
-	teg.globals.bx = [MAX_BOXES]*box{}
+	teg.globals.bx = boxStore{}
+	teg.globals.bx = boxStore{}
 
-	ocean_chars = []int{1, 2, 3}
-	init_ocean_chars()
+	teg.globals.ocean_chars = []int{1, 2, 3}
+	teg.init_ocean_chars()
 
 	// After init, ocean_chars is reset to empty slice (or nil)
-	if len(ocean_chars) != 0 {
-		t.Errorf("ocean_chars should be empty, got %d elements", len(ocean_chars))
+	if len(teg.globals.ocean_chars) != 0 {
+		t.Errorf("ocean_chars should be empty, got %d elements", len(teg.globals.ocean_chars))
 	}
 }
 
@@ -118,7 +119,7 @@ func TestLandCheckWater(t *testing.T) {
 	c := &command{who: 1}
 	v := &exit_view{water: 1}
 
-	result := land_check(c, v, false)
+	result := teg.land_check(c, v, false)
 	if result {
 		t.Error("land_check should return false for water routes")
 	}
@@ -128,7 +129,7 @@ func TestLandCheckImpassable(t *testing.T) {
 	c := &command{who: 1}
 	v := &exit_view{impassable: 1}
 
-	result := land_check(c, v, false)
+	result := teg.land_check(c, v, false)
 	if result {
 		t.Error("land_check should return false for impassable routes")
 	}
@@ -138,7 +139,7 @@ func TestLandCheckInTransit(t *testing.T) {
 	c := &command{who: 1}
 	v := &exit_view{in_transit: 1}
 
-	result := land_check(c, v, false)
+	result := teg.land_check(c, v, false)
 	if result {
 		t.Error("land_check should return false for in-transit destinations")
 	}
@@ -152,7 +153,7 @@ func TestLandCheckSuccess(t *testing.T) {
 		in_transit: 0,
 	}
 
-	result := land_check(c, v, false)
+	result := teg.land_check(c, v, false)
 	if !result {
 		t.Error("land_check should return true for valid land routes")
 	}
@@ -192,7 +193,7 @@ func TestFlyCheckInTransit(t *testing.T) {
 	c := &command{who: 1}
 	v := &exit_view{in_transit: 1}
 
-	result := fly_check(c, v)
+	result := teg.fly_check(c, v)
 	if result {
 		t.Error("fly_check should return false for in-transit destinations")
 	}
@@ -202,7 +203,7 @@ func TestFlyCheckSuccess(t *testing.T) {
 	c := &command{who: 1}
 	v := &exit_view{in_transit: 0}
 
-	result := fly_check(c, v)
+	result := teg.fly_check(c, v)
 	if !result {
 		t.Error("fly_check should return true for valid destinations")
 	}
@@ -213,29 +214,30 @@ func TestCountStackMoveNobles(t *testing.T) {
 	defer func() { teg = oldTeg }()
 
 	teg = &Engine{}
-	teg.globals.bx = [MAX_BOXES]*box{}
+	teg.globals.bx = boxStore{}
+	teg.globals.bx = boxStore{}
 
 	leaderID := 1000
 	followerID := 1001
 	playerID := 100
 
-	teg.globals.bx[leaderID] = &box{
+	teg.setBox(leaderID, &box{
 		kind:   T_char,
 		x_char: &entity_char{unit_lord: playerID},
-	}
-	teg.globals.bx[leaderID].x_loc_info.here_list = []int{followerID}
+	})
+	teg.globals.bx.get(leaderID).x_loc_info.here_list = []int{followerID}
 
-	teg.globals.bx[followerID] = &box{
+	teg.setBox(followerID, &box{
 		kind:   T_char,
 		x_char: &entity_char{unit_lord: playerID},
-	}
-	teg.globals.bx[followerID].x_loc_info.where = leaderID
+	})
+	teg.globals.bx.get(followerID).x_loc_info.where = leaderID
 
-	teg.globals.bx[playerID] = &box{
+	teg.setBox(playerID, &box{
 		kind: T_player,
-	}
+	})
 
-	count := count_stack_move_nobles(leaderID)
+	count := teg.count_stack_move_nobles(leaderID)
 	if count < 1 {
 		t.Errorf("count_stack_move_nobles should return at least 1, got %d", count)
 	}
@@ -246,25 +248,26 @@ func TestMoveAlive(t *testing.T) {
 	defer func() { teg = oldTeg }()
 
 	teg = &Engine{}
-	teg.globals.bx = [MAX_BOXES]*box{}
+	teg.globals.bx = boxStore{}
+	teg.globals.bx = boxStore{}
 
 	charID := 1000
-	teg.globals.bx[charID] = &box{
+	teg.setBox(charID, &box{
 		kind:   T_char,
 		x_char: &entity_char{health: 100},
-	}
+	})
 
 	// The alive() function in accessor.go just checks kind == T_char
-	if !alive(charID) {
+	if !teg.alive(charID) {
 		t.Error("alive should return true for character")
 	}
 
 	locID := 2000
-	teg.globals.bx[locID] = &box{
+	teg.setBox(locID, &box{
 		kind: T_loc,
-	}
+	})
 
-	if alive(locID) {
+	if teg.alive(locID) {
 		t.Error("alive should return false for non-character")
 	}
 }
@@ -274,40 +277,41 @@ func TestLoopStack(t *testing.T) {
 	defer func() { teg = oldTeg }()
 
 	teg = &Engine{}
-	teg.globals.bx = [MAX_BOXES]*box{}
+	teg.globals.bx = boxStore{}
+	teg.globals.bx = boxStore{}
 
 	leaderID := 1000
 	follower1ID := 1001
 	follower2ID := 1002
 	nestedID := 1003
 
-	teg.globals.bx[leaderID] = &box{
+	teg.setBox(leaderID, &box{
 		kind:   T_char,
 		x_char: &entity_char{},
-	}
-	teg.globals.bx[leaderID].x_loc_info.here_list = []int{follower1ID, follower2ID}
+	})
+	teg.globals.bx.get(leaderID).x_loc_info.here_list = []int{follower1ID, follower2ID}
 
-	teg.globals.bx[follower1ID] = &box{
+	teg.setBox(follower1ID, &box{
 		kind:   T_char,
 		x_char: &entity_char{},
-	}
-	teg.globals.bx[follower1ID].x_loc_info.where = leaderID
-	teg.globals.bx[follower1ID].x_loc_info.here_list = []int{nestedID}
+	})
+	teg.globals.bx.get(follower1ID).x_loc_info.where = leaderID
+	teg.globals.bx.get(follower1ID).x_loc_info.here_list = []int{nestedID}
 
-	teg.globals.bx[follower2ID] = &box{
+	teg.setBox(follower2ID, &box{
 		kind:   T_char,
 		x_char: &entity_char{},
-	}
-	teg.globals.bx[follower2ID].x_loc_info.where = leaderID
+	})
+	teg.globals.bx.get(follower2ID).x_loc_info.where = leaderID
 
-	teg.globals.bx[nestedID] = &box{
+	teg.setBox(nestedID, &box{
 		kind:   T_char,
 		x_char: &entity_char{},
-	}
-	teg.globals.bx[nestedID].x_loc_info.where = follower1ID
+	})
+	teg.globals.bx.get(nestedID).x_loc_info.where = follower1ID
 
 	var stack []int
-	loop_stack(leaderID, &stack)
+	teg.loop_stack(leaderID, &stack)
 
 	if len(stack) != 4 {
 		t.Errorf("loop_stack should return 4 members, got %d", len(stack))
@@ -337,16 +341,17 @@ func TestCountAny(t *testing.T) {
 	defer func() { teg = oldTeg }()
 
 	teg = &Engine{}
-	teg.globals.bx = [MAX_BOXES]*box{}
+	teg.globals.bx = boxStore{}
+	teg.globals.bx = boxStore{}
 	teg.globals.inventories = make(map[int][]item_ent)
 
 	charID := 1000
-	teg.globals.bx[charID] = &box{
+	teg.setBox(charID, &box{
 		kind:   T_char,
 		x_char: &entity_char{},
-	}
+	})
 
-	count := count_any(charID)
+	count := teg.count_any(charID)
 	if count != 1 {
 		t.Errorf("count_any for char with no items should be 1, got %d", count)
 	}
@@ -357,25 +362,26 @@ func TestIsManItem(t *testing.T) {
 	defer func() { teg = oldTeg }()
 
 	teg = &Engine{}
-	teg.globals.bx = [MAX_BOXES]*box{}
+	teg.globals.bx = boxStore{}
+	teg.globals.bx = boxStore{}
 
 	manItemID := 10
-	teg.globals.bx[manItemID] = &box{
+	teg.setBox(manItemID, &box{
 		kind:   T_item,
 		x_item: &entity_item{is_man_item: 1},
-	}
+	})
 
-	if !is_man_item(manItemID) {
+	if !teg.is_man_item(manItemID) {
 		t.Error("is_man_item should return true for man items")
 	}
 
 	nonManItemID := 20
-	teg.globals.bx[nonManItemID] = &box{
+	teg.setBox(nonManItemID, &box{
 		kind:   T_item,
 		x_item: &entity_item{is_man_item: 0},
-	}
+	})
 
-	if is_man_item(nonManItemID) {
+	if teg.is_man_item(nonManItemID) {
 		t.Error("is_man_item should return false for non-man items")
 	}
 }
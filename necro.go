@@ -0,0 +1,483 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// necro.go - Necromancy ported from src/necro.c
+//
+// Demon lords (sub_undead) are summoned from graveyards and stay bound
+// to their summoner for a few months. Dead bodies of nobles can be
+// eaten to learn the skills the noble knew.
+
+package taygete
+
+// keep_undead_check verifies that c.a is a demon lord at c.d and,
+// if check_bond is set, that it is still bound by a summoning.
+// Ported from src/necro.c lines 9-38.
+func (e *Engine) keep_undead_check(c *command, check_bond bool) bool {
+	target := c.a
+	where := c.d
+
+	if e.kind(target) != T_char || e.subkind(target) != sub_undead {
+		wout(c.who, "%s is not a demon lord.", e.box_code(target))
+		return false
+	}
+
+	if e.subloc(target) != where {
+		if e.subloc(c.who) == where {
+			wout(c.who, "%s is not here.", e.box_code(target))
+		} else {
+			wout(c.who, "%s is not in %s.", e.box_code(target), e.box_code(where))
+		}
+		return false
+	}
+
+	if check_bond && e.loyal_kind(target) != LOY_summon {
+		wout(c.who, "%s is no longer bonded.", e.box_code(target))
+		return false
+	}
+
+	return true
+}
+
+// v_keep_undead starts renewing the bond on a summoned demon lord.
+// Ported from src/necro.c lines 41-54.
+func (e *Engine) v_keep_undead(c *command) int {
+	c.d = e.subloc(c.who)
+
+	if !e.keep_undead_check(c, true) {
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 3) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_keep_undead extends a demon lord's bond by four months, to at
+// least eight.
+// Ported from src/necro.c lines 57-75.
+func (e *Engine) d_keep_undead(c *command) int {
+	target := c.a
+
+	if !e.keep_undead_check(c, true) {
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, 3) {
+		return FALSE
+	}
+
+	e.set_loyal(target, LOY_summon, max(e.loyal_rate(target)+4, 8))
+
+	wout(c.who, "%s will remain for %d months.", e.box_code(target), e.loyal_rate(target))
+	return TRUE
+}
+
+// v_undead_lord starts summoning a demon lord. The aura spent, from
+// three to eight, determines the demon's strength.
+// Ported from src/necro.c lines 78-96.
+func (e *Engine) v_undead_lord(c *command) int {
+	where := e.subloc(c.who)
+	aura := c.a
+
+	if aura < 3 {
+		aura = 3
+		c.a = aura
+	}
+	if aura > 8 {
+		aura = 8
+		c.a = aura
+	}
+
+	if !e.may_cookie_npc(c.who, where, item_undead_cookie) {
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_undead_lord summons a demon lord bound to the caster for five months.
+// Ported from src/necro.c lines 99-147.
+func (e *Engine) d_undead_lord(c *command) int {
+	where := e.subloc(c.who)
+	aura := c.a
+
+	if !e.may_cookie_npc(c.who, where, item_undead_cookie) {
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	undead := e.do_cookie_npc(c.who, where, item_undead_cookie, c.who)
+	if undead == 0 {
+		log_write(LOG_CODE, "d_undead_lord: why not?")
+		wout(c.who, "Unable to summon a demon lord.")
+		return FALSE
+	}
+
+	var rating int
+	switch aura {
+	case 3:
+		rating = 100
+	case 4:
+		rating = 150
+	case 5:
+		rating = 190
+	case 6:
+		rating = 220
+	case 7:
+		rating = 240
+	case 8:
+		rating = 250
+	default:
+		panic("d_undead_lord: aura out of range")
+	}
+
+	e.p_char(undead).attack = short(rating)
+	e.p_char(undead).defense = short(rating)
+
+	e.set_loyal(undead, LOY_summon, 5)
+
+	wout(c.who, "Summoned %s.", e.box_name(undead))
+	wout(where, "%s has summoned %s.", e.box_name(c.who), e.liner_desc(undead))
+
+	return TRUE
+}
+
+// v_banish_undead starts banishing a demon lord, bound or not.
+// Ported from src/necro.c lines 150-161.
+func (e *Engine) v_banish_undead(c *command) int {
+	if !e.check_aura(c.who, 6) {
+		return FALSE
+	}
+
+	c.d = e.reset_cast_where(c.who)
+	if !e.keep_undead_check(c, false) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_banish_undead destroys a demon lord.
+// Ported from src/necro.c lines 164-186.
+func (e *Engine) d_banish_undead(c *command) int {
+	target := c.a
+	where := c.d
+
+	if !e.charge_aura(c.who, 6) {
+		return FALSE
+	}
+
+	if !e.keep_undead_check(c, false) {
+		return FALSE
+	}
+
+	head := e.stack_leader(target)
+
+	wout(head, "%s banishes %s!", e.box_name(c.who), e.box_name(target))
+	wout(where, "%s banishes %s!", e.box_name(c.who), e.box_name(target))
+
+	e.extract_stacked_unit(target)
+	e.kill_char(target, 0)
+
+	return TRUE
+}
+
+// v_eat_dead starts consuming the dead body of a noble.
+// Ported from src/necro.c lines 189-218.
+func (e *Engine) v_eat_dead(c *command) int {
+	body := c.a
+
+	if e.kind(body) != T_item || e.subkind(body) != sub_dead_body {
+		wout(c.who, "%s is not the dead body of a noble.", e.box_code(body))
+		return FALSE
+	}
+
+	if e.has_item(c.who, body) == 0 {
+		wout(c.who, "Don't have %s.", e.box_code(body))
+		return FALSE
+	}
+
+	if e.has_item(c.who, item_ratspider_venom) == 0 {
+		wout(c.who, "Requires %s.", e.box_name_qty(item_ratspider_venom, 1))
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 5) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// get_some_skills teaches who the skills known by body, each with the
+// given percent chance. Schools are picked first; a subskill may only
+// be learned if its school is known or is being learned now. The NP
+// cost of every skill learned must be paid up front.
+// Ported from src/necro.c lines 221-303.
+func (e *Engine) get_some_skills(who, body, chance int) {
+	var to_learn IList
+
+	// first do category skills
+	for _, se := range e.getCharSkills(body) {
+		if se.know != SKILL_know {
+			continue
+		}
+		if e.skill_school(se.skill) != se.skill {
+			continue
+		}
+
+		// prevent Advanced Sorcery from being learned this way
+		if se.skill == sk_adv_sorcery {
+			continue
+		}
+
+		if e.has_skill(who, se.skill) {
+			continue
+		}
+
+		if e.rndFrom(streamMagic, 1, 100) > chance {
+			continue
+		}
+
+		to_learn.Append(se.skill)
+	}
+
+	// now do subskills; must know parent in order to pick up a subskill
+	for _, se := range e.getCharSkills(body) {
+		if se.know != SKILL_know {
+			continue
+		}
+		parent := e.skill_school(se.skill)
+		if parent == se.skill {
+			continue
+		}
+
+		if e.has_skill(who, se.skill) {
+			continue
+		}
+
+		if !e.has_skill(who, parent) && to_learn.Lookup(parent) < 0 {
+			continue
+		}
+
+		if e.rndFrom(streamMagic, 1, 100) > chance {
+			continue
+		}
+
+		to_learn.Append(se.skill)
+	}
+
+	if to_learn.Len() == 0 {
+		wout(who, "No new skills can be learned from this brain.")
+		return
+	}
+
+	nps := 0
+	for _, sk := range to_learn.Values() {
+		nps += e.skill_np_req(sk)
+	}
+
+	if int(e.player_np(e.player(who))) < nps {
+		wout(who, "Don't have the required %d NP%s.", nps, add_s(nps))
+		return
+	}
+
+	e.deduct_np(e.player(who), nps)
+
+	for _, sk := range to_learn.Values() {
+		e.learn_skill(who, sk)
+	}
+}
+
+// d_eat_dead consumes a dead body. One time in three the eater dies;
+// otherwise they learn the noble's skills and may fall ill.
+// Ported from src/necro.c lines 306-360.
+func (e *Engine) d_eat_dead(c *command) int {
+	body := c.a
+
+	if e.kind(body) != T_item || e.subkind(body) != sub_dead_body {
+		wout(c.who, "%s is not the dead body of a noble.", e.box_code(body))
+		return FALSE
+	}
+
+	if e.has_item(c.who, body) == 0 {
+		wout(c.who, "Don't have %s.", e.box_code(body))
+		return FALSE
+	}
+
+	if !e.consume_item(c.who, item_ratspider_venom, 1) {
+		wout(c.who, "Requires %s.", e.box_name_qty(item_ratspider_venom, 1))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, 5) {
+		return FALSE
+	}
+
+	wout(c.who, "Consumed %s.", e.box_name(body))
+
+	if e.rndFrom(streamMagic, 1, 100) <= 33 {
+		e.destroy_unique_item(c.who, body)
+		e.kill_char(c.who, MATES)
+		return TRUE
+	}
+
+	e.get_some_skills(c.who, body, 100)
+	e.destroy_unique_item(c.who, body)
+
+	if e.rndFrom(streamMagic, 1, 100) <= 25 && e.char_sick(c.who) == 0 {
+		e.p_char(c.who).sick = TRUE
+		wout(c.who, "%s has fallen ill.", e.box_name(c.who))
+	}
+
+	return TRUE
+}
+
+// auto_undead queues orders for an unbound demon lord: attack its
+// summoner if they share a location, otherwise wander and pillage.
+// Ported from src/necro.c lines 390-412.
+func (e *Engine) auto_undead(who int) {
+	where := e.subloc(who)
+
+	master := e.npc_summoner(who)
+
+	if master != 0 && e.subloc(who) == e.subloc(master) {
+		e.queue(who, "attack %s", box_code_less(master))
+		e.p_misc(who).summoned_by = 0
+		return
+	}
+
+	if e.loc_depth(where) != LOC_province || e.rndFrom(streamMagic, 1, 2) == 1 {
+		e.npc_move(who)
+		return
+	}
+
+	e.queue(who, "pillage 1")
+}
+
+// v_aura_blast starts blasting a target with aura.
+// Ported from src/necro.c lines 415-445.
+func (e *Engine) v_aura_blast(c *command) int {
+	target := c.a
+
+	if e.in_safe_now(c.who) {
+		wout(c.who, "Not allowed in a safe haven.")
+		return FALSE
+	}
+
+	where := e.reset_cast_where(c.who)
+	if !e.check_char_where(where, c.who, target) {
+		return FALSE
+	}
+
+	c.d = where
+
+	return TRUE
+}
+
+// d_aura_blast spends aura (c.b, or all current aura, keeping back c.c)
+// to deal twice that in damage to the target. A target with Absorb
+// Aura Blast either absorbs half of it or reflects it back.
+// Ported from src/necro.c lines 448-529.
+func (e *Engine) d_aura_blast(c *command) int {
+	target := c.a
+	aura := c.b
+	have_left := c.c
+	where := c.d
+
+	if !e.check_char_where(where, c.who, target) {
+		return FALSE
+	}
+
+	if e.in_safe_now(c.who) || e.in_safe_now(target) {
+		wout(c.who, "Not allowed in a safe haven.")
+		return FALSE
+	}
+
+	if aura < 1 {
+		aura = e.char_cur_aura(c.who)
+	}
+
+	if have_left != 0 {
+		m := max(e.char_cur_aura(c.who)-have_left, 0)
+		if aura > m {
+			aura = m
+		}
+	}
+
+	if aura == 0 {
+		wout(c.who, "No aura available for blast.")
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	vector_clear()
+	vector_add(c.who)
+	vector_add(target)
+	vector_add(where)
+
+	wout(VECT, "%s blasts %s with a burst of aura!", e.box_name(c.who), e.box_name(target))
+
+	log_write(LOG_SPECIAL, "%s blasts %s with a burst of aura!", e.box_name(c.who), e.box_name(target))
+
+	if e.has_skill(target, sk_absorb_blast) {
+		if e.reflect_blast(target) != 0 {
+			wout(VECT, "%s reflected the blast back to %s!", e.just_name(target), e.just_name(c.who))
+
+			e.add_char_damage(c.who, aura*2, MATES)
+		} else {
+			wout(VECT, "%s absorbed the blast!", e.just_name(target))
+
+			e.p_magic(target).cur_aura += aura / 2
+
+			e.limit_cur_aura(target)
+
+			wout(target, "Current aura is now %d.", e.rp_magic(target).cur_aura)
+		}
+	} else {
+		e.add_char_damage(target, aura*2, MATES)
+	}
+
+	return TRUE
+}
+
+// v_aura_reflect sets whether aura blasts are reflected or absorbed.
+// Ported from src/necro.c lines 532-545.
+func (e *Engine) v_aura_reflect(c *command) int {
+	flag := c.a
+
+	e.p_magic(c.who).aura_reflect = schar(flag)
+
+	if flag != 0 {
+		wout(c.who, "Will reflect aura blasts back at the attacker.")
+	} else {
+		wout(c.who, "Will absorb aura blasts.")
+	}
+
+	return TRUE
+}
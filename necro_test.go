@@ -0,0 +1,245 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// necro_test.go - Tests for necromancy and dead bodies
+
+package taygete
+
+import "testing"
+
+// setupNecroTest places a mage with 20 aura in a graveyard inside a
+// plain province. Returns the player, mage, province and graveyard.
+func setupNecroTest(t *testing.T) (pl, who, prov, grave int) {
+	t.Helper()
+	pl, who = setupUseTest(t)
+
+	teg.alloc_box(indep_player, T_player, sub_pl_npc)
+	teg.alloc_box(item_corpse, T_item, 0)
+	teg.alloc_box(item_undead_cookie, T_item, 0)
+	teg.alloc_box(item_ratspider_venom, T_item, 0)
+
+	prov, grave = 10_101, 56_760
+	teg.alloc_box(prov, T_loc, sub_plain)
+	teg.alloc_box(grave, T_loc, sub_graveyard)
+	teg.set_where(grave, prov)
+	teg.set_where(who, grave)
+
+	teg.p_magic(who).max_aura = 20
+	teg.p_magic(who).cur_aura = 20
+
+	return pl, who, prov, grave
+}
+
+func TestUndeadLordSummon(t *testing.T) {
+	_, who, _, grave := setupNecroTest(t)
+
+	c := &command{who: who, a: 5}
+	if got := teg.v_undead_lord(c); got != FALSE {
+		t.Errorf("v_undead_lord without a cookie = %d, want FALSE", got)
+	}
+
+	teg.gen_item(grave, item_undead_cookie, 1)
+	if got := teg.v_undead_lord(c); got != TRUE {
+		t.Fatalf("v_undead_lord = %d, want TRUE", got)
+	}
+	if got := teg.d_undead_lord(c); got != TRUE {
+		t.Fatalf("d_undead_lord = %d, want TRUE", got)
+	}
+
+	var undead int
+	for _, i := range teg.loop_stack_list(who) {
+		if teg.subkind(i) == sub_undead {
+			undead = i
+		}
+	}
+	if undead == 0 {
+		t.Fatal("no demon lord stacked with the summoner")
+	}
+	if got := teg.char_attack(undead); got != 190 {
+		t.Errorf("attack = %d, want 190 for five aura", got)
+	}
+	if teg.loyal_kind(undead) != LOY_summon || teg.loyal_rate(undead) != 5 {
+		t.Errorf("loyalty = %d/%d, want summon/5", teg.loyal_kind(undead), teg.loyal_rate(undead))
+	}
+	if n := teg.has_item(undead, item_corpse); n < 15 || n > 25 {
+		t.Errorf("corpses = %d, want 15..25", n)
+	}
+	if teg.has_item(grave, item_undead_cookie) != 0 {
+		t.Error("undead cookie was not consumed")
+	}
+	if got := teg.char_cur_aura(who); got != 15 {
+		t.Errorf("cur_aura = %d, want 15", got)
+	}
+	if got := teg.npc_summoner(undead); got != who {
+		t.Errorf("summoned_by = %d, want %d", got, who)
+	}
+
+	// renew the bond
+	c = &command{who: who, a: undead}
+	if got := teg.v_keep_undead(c); got != TRUE {
+		t.Fatalf("v_keep_undead = %d, want TRUE", got)
+	}
+	if got := teg.d_keep_undead(c); got != TRUE {
+		t.Fatalf("d_keep_undead = %d, want TRUE", got)
+	}
+	if got := teg.loyal_rate(undead); got != 9 {
+		t.Errorf("bond = %d months, want 9", got)
+	}
+}
+
+func TestGetSomeSkills(t *testing.T) {
+	pl, who, _, _ := setupNecroTest(t)
+	teg.p_player(pl).noble_points = 10
+
+	body := 2001
+	teg.alloc_box(body, T_item, sub_dead_body)
+	teg.alloc_box(sk_shipcraft, T_skill, 0)
+	teg.alloc_box(sk_pilot_ship, T_skill, 0)
+	teg.p_skill(sk_pilot_ship).required_skill = sk_shipcraft
+	teg.p_skill(sk_shipcraft).np_req = 1
+	teg.p_skill(sk_pilot_ship).np_req = 1
+	teg.p_skill_ent(body, sk_shipcraft).know = SKILL_know
+	teg.p_skill_ent(body, sk_pilot_ship).know = SKILL_know
+	teg.p_skill_ent(body, sk_archery).know = SKILL_know
+
+	teg.get_some_skills(who, body, 100)
+
+	if !teg.has_skill(who, sk_shipcraft) || !teg.has_skill(who, sk_pilot_ship) {
+		t.Error("did not learn the body's school and subskill")
+	}
+	if got := teg.player_np(pl); got != 8 {
+		t.Errorf("noble points = %d, want 8", got)
+	}
+}
+
+func TestAuraBlast(t *testing.T) {
+	_, who, _, grave := setupNecroTest(t)
+
+	target := 1002
+	teg.alloc_box(target, T_char, 0)
+	teg.p_char(target).health = 100
+	teg.set_where(target, grave)
+
+	c := &command{who: who, a: target, b: 10}
+	if got := teg.v_aura_blast(c); got != TRUE {
+		t.Fatalf("v_aura_blast = %d, want TRUE", got)
+	}
+	if got := teg.d_aura_blast(c); got != TRUE {
+		t.Fatalf("d_aura_blast = %d, want TRUE", got)
+	}
+	if got := teg.char_health(target); got != 80 {
+		t.Errorf("target health = %d, want 80", got)
+	}
+	if got := teg.char_cur_aura(who); got != 10 {
+		t.Errorf("cur_aura = %d, want 10", got)
+	}
+}
+
+func TestResurrect(t *testing.T) {
+	pl, who, prov, grave := setupNecroTest(t)
+
+	noble := 1002
+	teg.alloc_box(noble, T_char, 0)
+	teg.p_char(noble).health = 100
+	teg.p_char(noble).defense = 80
+	teg.set_name(noble, "Osric")
+	teg.set_where(noble, grave)
+	teg.set_lord(noble, pl, LOY_oath, 1)
+
+	teg.dead_char_body(pl, noble)
+	if teg.kind(noble) != T_item || teg.subkind(noble) != sub_dead_body {
+		t.Fatalf("noble %d did not become a dead body", noble)
+	}
+	if got := teg.has_item(prov, noble); got != 1 {
+		t.Fatalf("province holds %d of the body, want 1", got)
+	}
+	teg.move_item(prov, who, noble, 1)
+
+	teg.p_magic(who).pray = 1 // a prepared priest never fails
+
+	c := &command{who: who, a: noble}
+	if got := teg.v_resurrect(c); got != TRUE {
+		t.Fatalf("v_resurrect = %d, want TRUE", got)
+	}
+	if got := teg.d_resurrect(c); got != TRUE {
+		t.Fatalf("d_resurrect = %d, want TRUE", got)
+	}
+
+	if teg.kind(noble) != T_char {
+		t.Fatalf("kind = %d, want T_char", teg.kind(noble))
+	}
+	if got := teg.just_name(noble); got != "Osric" {
+		t.Errorf("name = %q, want %q", got, "Osric")
+	}
+	if teg.player(noble) != pl {
+		t.Errorf("lord = %d, want %d", teg.player(noble), pl)
+	}
+	if got := teg.char_defense(noble); got != 30 {
+		t.Errorf("defense = %d, want 30 after resurrection", got)
+	}
+	if teg.char_pray(who) != 0 {
+		t.Error("preparatory ritual was not used up")
+	}
+}
+
+func TestDeadBodyRot(t *testing.T) {
+	pl, who, prov, _ := setupNecroTest(t)
+	saved := teg.globals.sysclock
+	defer func() { teg.globals.sysclock = saved }()
+
+	noble := 1002
+	teg.alloc_box(noble, T_char, 0)
+	teg.set_where(noble, prov)
+	teg.set_lord(noble, pl, LOY_oath, 1)
+
+	teg.globals.sysclock.turn = 10
+	teg.p_char(noble).death_time = teg.globals.sysclock
+	teg.dead_char_body(pl, noble)
+	teg.move_item(prov, who, noble, 1)
+
+	teg.globals.sysclock.turn = 21
+	teg.deadBodyRot()
+	if teg.kind(noble) != T_item || teg.has_item(who, noble) != 1 {
+		t.Fatal("body rotted before twelve turns")
+	}
+
+	teg.globals.sysclock.turn = 22
+	teg.deadBodyRot()
+	if teg.kind(noble) == T_item {
+		t.Error("body did not rot after twelve turns")
+	}
+	if teg.has_item(who, noble) != 0 {
+		t.Error("rotted body still in inventory")
+	}
+}
+
+func TestGhostWarriorAndCorpseDecay(t *testing.T) {
+	_, who, _, _ := setupNecroTest(t)
+	teg.alloc_box(item_ghost_warrior, T_item, 0)
+
+	teg.gen_item(who, item_ghost_warrior, 3)
+	teg.gen_item(who, item_corpse, 10)
+
+	teg.ghostWarriorDecay()
+	if got := teg.has_item(who, item_ghost_warrior); got != 2 {
+		t.Errorf("ghost warriors = %d, want 2", got)
+	}
+
+	teg.corpseDecay()
+	if got := teg.has_item(who, item_corpse); got < 8 || got > 10 {
+		t.Errorf("corpses = %d, want 8..10", got)
+	}
+}
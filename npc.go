@@ -0,0 +1,281 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// npc.go - NPC movement and cookie monsters ported from src/npc.c
+//
+// A "cookie" is an item held by a location that limits how many NPCs
+// (mobs, demon lords, storms) may be raised from it.
+
+package taygete
+
+// PROV_OR_CITY is a cookie_monster terrain meaning any province or city.
+const PROV_OR_CITY = -1
+
+type cookie_monster_tbl struct {
+	cookie     int
+	kind       schar
+	sk         schar
+	ni         int
+	terrain    int
+	man_kind   int
+	low, high  int
+	not_here   string
+	no_cookies string
+}
+
+// Ported from src/npc.c lines 340-397.
+var cookie_monster = []cookie_monster_tbl{
+	{
+		item_mob_cookie,
+		T_char, sub_ni, item_angry_peasant,
+		PROV_OR_CITY,
+		item_angry_peasant, 12, 36,
+		"Mobs can only be raised in provinces and cities.",
+		"A mob has already been raised from this place.",
+	},
+	{
+		item_undead_cookie,
+		T_char, sub_undead, 0,
+		sub_graveyard,
+		item_corpse, 15, 25,
+		"Demon lords may only be summoned in graveyards.",
+		"A demon lord has already been summoned from this graveyard.",
+	},
+	{
+		item_rain_cookie,
+		T_storm, sub_rain, 0,
+		0,
+		0, 0, 0,
+		"Rain may not be summoned here.",
+		"A storm has already been summoned from this province.",
+	},
+	{
+		item_wind_cookie,
+		T_storm, sub_wind, 0,
+		0,
+		0, 0, 0,
+		"Wind may not be summoned here.",
+		"A storm has already been summoned from this province.",
+	},
+	{
+		item_fog_cookie,
+		T_storm, sub_fog, 0,
+		0,
+		0, 0, 0,
+		"Fog may not be summoned here.",
+		"A storm has already been summoned from this province.",
+	},
+}
+
+// controlled_humans_here reports whether a sworn noble is at or below
+// where.
+// Ported from src/npc.c lines 7-26.
+func (e *Engine) controlled_humans_here(where int) bool {
+	var l []int
+	e.all_here(where, &l)
+	for _, i := range l {
+		if e.kind(i) == T_char && e.subkind(i) == 0 && e.loyal_kind(i) != LOY_unsworn {
+			return true
+		}
+	}
+
+	return false
+}
+
+// get_exit_dir returns the exit in l leading in direction dir, or nil.
+// Ported from src/npc.c lines 29-39.
+func get_exit_dir(l []*exit_view, dir int) *exit_view {
+	for _, e := range l {
+		if e.direction == dir {
+			return e
+		}
+	}
+	return nil
+}
+
+// exits_from_loc_nsew_select returns the compass exits from a province,
+// restricted to land and/or water links and optionally shuffled.
+// Ported from src/dir.c lines 740-763.
+func (e *Engine) exits_from_loc_nsew_select(who, where, land, random int) []*exit_view {
+	if e.loc_depth(where) != LOC_province {
+		return nil
+	}
+
+	var ret []*exit_view
+	for _, v := range e.exits_from_loc_nsew(who, where) {
+		if ((land&LAND) != 0 && v.water == 0) || ((land&WATER) != 0 && v.water != 0) {
+			ret = append(ret, v)
+		}
+	}
+
+	if random != 0 {
+		for i := 0; i < len(ret)-1; i++ {
+			if r := e.rndFrom(streamNPC, i, len(ret)-1); r != i {
+				ret[i], ret[r] = ret[r], ret[i]
+			}
+		}
+	}
+
+	return ret
+}
+
+// choose_npc_direction picks a land exit for a wandering NPC.
+// There is a 90% chance an NPC will keep going in the same
+// direction, if it can.
+// Ported from src/npc.c lines 42-64.
+func (e *Engine) choose_npc_direction(who, where, dir int) *exit_view {
+	l := e.exits_from_loc_nsew_select(who, where, LAND, RAND)
+	if len(l) == 0 {
+		return nil
+	}
+
+	if dir != 0 && e.rndFrom(streamNPC, 1, 10) < 10 {
+		if v := get_exit_dir(l, dir); v != nil {
+			return v
+		}
+	}
+
+	return l[0] // order of l has already been randomized
+}
+
+// npc_move queues a move order for a wandering NPC.
+// Ported from src/npc.c lines 67-88.
+func (e *Engine) npc_move(who int) {
+	where := e.subloc(who)
+
+	if e.loc_depth(where) != LOC_province {
+		e.queue(who, "move out")
+		return
+	}
+
+	v := e.choose_npc_direction(who, where, int(e.npc_last_dir(who)))
+	if v != nil {
+		e.p_misc(who).npc_dir = schar(v.direction)
+		e.queue(who, "move %s", full_dir_s[v.direction])
+	}
+}
+
+// find_cookie returns the cookie_monster entry for cookie k, or nil.
+// Ported from src/npc.c lines 400-413.
+func (e *Engine) find_cookie(k int) *cookie_monster_tbl {
+	if e.kind(k) != T_item {
+		panic("find_cookie: cookie is not an item")
+	}
+
+	for i := range cookie_monster {
+		if cookie_monster[i].cookie == k {
+			return &cookie_monster[i]
+		}
+	}
+
+	return nil
+}
+
+// may_cookie_npc reports whether an NPC may be raised from where.
+// If who is non-zero, the reason for a refusal is reported to them.
+// Ported from src/npc.c lines 417-466.
+func (e *Engine) may_cookie_npc(who, where, cookie int) bool {
+	t := e.find_cookie(cookie)
+	if t == nil {
+		panic("may_cookie_npc: unknown cookie")
+	}
+
+	bad_place := false
+
+	if t.terrain > 0 && int(e.subkind(where)) != t.terrain {
+		bad_place = true
+	}
+
+	if t.terrain == 0 {
+		sk := e.subkind(where)
+		if cookie == item_wind_cookie &&
+			sk != sub_plain && sk != sub_mountain && sk != sub_desert && sk != sub_ocean {
+			bad_place = true
+		}
+		if cookie == item_rain_cookie &&
+			sk != sub_forest && sk != sub_ocean {
+			bad_place = true
+		}
+		if cookie == item_fog_cookie &&
+			sk != sub_forest && sk != sub_swamp && sk != sub_ocean {
+			bad_place = true
+		}
+	}
+
+	if t.terrain == PROV_OR_CITY &&
+		e.subkind(where) != sub_city && e.loc_depth(where) != LOC_province {
+		bad_place = true
+	}
+
+	if bad_place {
+		if who != 0 {
+			wout(who, "%s", t.not_here)
+		}
+		return false
+	}
+
+	if e.has_item(where, cookie) == 0 {
+		if who != 0 {
+			wout(who, "%s", t.no_cookies)
+		}
+		return false
+	}
+
+	return true
+}
+
+// do_cookie_npc raises an NPC from where, placing it at place and
+// consuming one cookie. Returns the new entity, or 0 on failure.
+// Ported from src/npc.c lines 470-516.
+func (e *Engine) do_cookie_npc(who, where, cookie, place int) int {
+	if !e.may_cookie_npc(who, where, cookie) {
+		return 0
+	}
+
+	t := e.find_cookie(cookie)
+
+	var newEnt int
+	if t.kind == T_char {
+		newEnt = e.new_char(int(t.sk), t.ni, place, 100, indep_player, LOY_npc, 0, "")
+	} else {
+		newEnt = e.new_ent(t.kind, t.sk)
+		if newEnt > 0 {
+			e.set_where(newEnt, place)
+		}
+	}
+
+	if newEnt <= 0 {
+		return 0
+	}
+
+	if t.sk == sub_ni {
+		e.p_char(newEnt).health = -1
+	}
+
+	p := e.p_misc(newEnt)
+	p.npc_home = where
+	p.npc_cookie = cookie
+	p.summoned_by = who
+	p.npc_created = e.globals.sysclock.turn
+
+	if t.man_kind != 0 {
+		e.gen_item(newEnt, t.man_kind, e.rndFrom(streamNPC, t.low, t.high))
+	}
+
+	e.consume_item(where, cookie, 1)
+
+	return newEnt
+}
@@ -19,6 +19,8 @@ package taygete
 import (
 	"database/sql"
 	"fmt"
+	"sort"
+	"strconv"
 )
 
 // order.c -- manage list of unit orders for each faction
@@ -149,7 +151,7 @@ func (e *Engine) pop_order(pl, who int) {
 // flush_unit_orders removes all orders for a unit.
 // Port of C flush_unit_orders().
 func (e *Engine) flush_unit_orders(pl, who int) {
-	if e.globals.bx[who] == nil {
+	if e.globals.bx.get(who) == nil {
 		return
 	}
 
@@ -226,7 +228,7 @@ func (e *Engine) LoadOrders(turnNumber int) error {
 	}
 
 	rows, err := e.db.Query(`
-		SELECT id, turn_number, player_id, source_char_id, raw_text, source_channel
+		SELECT id, turn_number, player_id, source_char_id, raw_text, source_channel, extra
 		FROM orders
 		WHERE turn_number = ?
 		ORDER BY id
@@ -240,18 +242,29 @@ func (e *Engine) LoadOrders(turnNumber int) error {
 		var id, turn, playerID int
 		var unitID sql.NullInt64
 		var rawText string
-		var sourceChannel sql.NullString
+		var sourceChannel, extra sql.NullString
 
-		if err := rows.Scan(&id, &turn, &playerID, &unitID, &rawText, &sourceChannel); err != nil {
+		if err := rows.Scan(&id, &turn, &playerID, &unitID, &rawText, &sourceChannel, &extra); err != nil {
 			return fmt.Errorf("scan order %d: %w", id, err)
 		}
 
-		// Skip orders without a unit
-		if !unitID.Valid {
+		// Orders for the faction itself or an unformed noble carry
+		// the unit in extra; skip orders without a unit
+		var who int
+		if unitID.Valid {
+			who = int(unitID.Int64)
+		} else if n, err := strconv.Atoi(extra.String); err == nil && n > 0 {
+			who = n
+		} else {
 			continue
 		}
 
-		who := int(unitID.Int64)
+		// Orders keep the faction from being auto-dropped
+		if b := e.globals.bx.get(playerID); b != nil && b.x_player != nil &&
+			b.x_player.last_order_turn < turn {
+			b.x_player.last_order_turn = turn
+			e.markDirty(playerID)
+		}
 
 		// Get or create order queue for this player/unit
 		if e.globals.orderQueues[playerID] == nil {
@@ -282,8 +295,23 @@ func (e *Engine) LoadOrders(turnNumber int) error {
 // SaveOrders saves all pending orders to the database.
 // This replaces the C save_orders() function which wrote to files.
 func (e *Engine) SaveOrders(turnNumber int) error {
+	tx, err := e.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := e.writeOrders(tx, turnNumber); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// writeOrders replaces the orders of turnNumber in tx with the orders
+// queued in memory.
+func (e *Engine) writeOrders(tx *sql.Tx, turnNumber int) error {
 	// Delete existing orders for this turn first
-	_, err := e.db.Exec(`DELETE FROM orders WHERE turn_number = ?`, turnNumber)
+	_, err := tx.Exec(`DELETE FROM orders WHERE turn_number = ?`, turnNumber)
 	if err != nil {
 		return fmt.Errorf("delete old orders: %w", err)
 	}
@@ -296,20 +324,30 @@ func (e *Engine) SaveOrders(turnNumber int) error {
 			}
 
 			// Skip invalid units
-			if e.globals.bx[queue.Unit] == nil || e.Kind(queue.Unit) == T_deadchar {
+			if e.globals.bx.get(queue.Unit) == nil || e.Kind(queue.Unit) == T_deadchar {
 				continue
 			}
 
+			// source_char_id refers to characters; other units
+			// (the faction, unformed nobles) go in extra instead
+			var sourceChar sql.NullInt64
+			var extra sql.NullString
+			if e.Kind(queue.Unit) == T_char {
+				sourceChar = sql.NullInt64{Int64: int64(queue.Unit), Valid: true}
+			} else {
+				extra = sql.NullString{String: strconv.Itoa(queue.Unit), Valid: true}
+			}
+
 			for _, order := range queue.Orders {
 				var sourceChannel sql.NullString
 				if order.SourceChannel != "" {
 					sourceChannel = sql.NullString{String: order.SourceChannel, Valid: true}
 				}
 
-				_, err := e.db.Exec(`
-					INSERT INTO orders (turn_number, player_id, source_char_id, raw_text, source_channel)
-					VALUES (?, ?, ?, ?, ?)
-				`, turnNumber, playerID, queue.Unit, order.RawText, sourceChannel)
+				_, err := tx.Exec(`
+					INSERT INTO orders (turn_number, player_id, source_char_id, raw_text, source_channel, extra)
+					VALUES (?, ?, ?, ?, ?, ?)
+				`, turnNumber, playerID, sourceChar, order.RawText, sourceChannel, extra)
 				if err != nil {
 					return fmt.Errorf("insert order for unit %d: %w", queue.Unit, err)
 				}
@@ -353,42 +391,121 @@ func (e *Engine) GetAllOrders(playerID, unitID int) []string {
 	return result
 }
 
-// helper stubs for functions that will be implemented in other sprints
+// orders_template_sup returns the template lines for one unit: what
+// it is doing now followed by its queued orders.
+// Ported from src/order.c lines 353-435.
+func (e *Engine) orders_template_sup(num, pl int) []string {
+	var l []string
+	nam := ""
+
+	if pl == e.player(num) {
+		if e.is_prisoner(num) || e.kind(num) != T_char {
+			nam = fmt.Sprintf("  # %s", e.just_name(num))
+		} else {
+			nam = fmt.Sprintf("  # %s in %s", e.just_name(num), e.box_name(e.subloc(num)))
+		}
+	}
 
-// rp_command returns the command structure for an entity, or nil.
-// This is a stub that will be properly implemented when command execution is ported.
-func (e *Engine) rp_command(who int) *command {
-	if e.globals.bx[who] == nil {
-		return nil
+	l = append(l, fmt.Sprintf("unit %s%s", box_code_less(num), nam))
+
+	if pl == e.player(num) {
+		if e.kind(num) == T_player && e.globals.autoQuitTurns > 0 {
+			turns := int(e.globals.sysclock.turn) - e.p_player(num).last_order_turn
+			if turns >= e.globals.autoQuitTurns/2 {
+				l = append(l, "   #",
+					fmt.Sprintf("   # You have not sent in orders for %d turn%s.", turns, add_s(turns)),
+					fmt.Sprintf("   # If you do not submit orders for %d turn%s,",
+						e.globals.autoQuitTurns, add_s(e.globals.autoQuitTurns)),
+					"   # your faction will be removed from the game.",
+					"   #")
+			}
+		}
+
+		if (e.loyal_kind(num) == LOY_contract && e.loyal_rate(num) < 50) ||
+			(e.loyal_kind(num) == LOY_fear && e.loyal_rate(num) < 2) {
+			l = append(l, "   #",
+				fmt.Sprintf("   # %s has loyalty %s and will renounce", box_code_less(num), e.loyal_s(num)),
+				"   # loyalty at the end of this turn.",
+				"   #")
+		}
+
+		if c := e.rp_command(num); c != nil {
+			switch c.state {
+			case STATE_RUN:
+				timeLeft := " (still executing)"
+				if c.wait >= 0 {
+					timeLeft = fmt.Sprintf(" (executing for %s more day%s)", nice_num(c.wait), add_s(c.wait))
+				}
+				l = append(l, fmt.Sprintf("   # > %s%s", c.line, timeLeft))
+			case STATE_LOAD: // command has loaded, but not started yet
+				l = append(l, "   "+c.line)
+			}
+		}
 	}
-	return e.globals.bx[who].cmd
-}
 
-// command_done marks a command as completed.
-// This is a stub that will be properly implemented when command execution is ported.
-func (e *Engine) command_done(c *command) {
-	if c != nil {
-		c.state = STATE_DONE
+	if q := e.rp_order_head(pl, num); q != nil {
+		for _, o := range q.Orders {
+			l = append(l, "   "+o.RawText)
+		}
 	}
+
+	return append(l, "")
 }
 
-// player returns the player ID that owns a unit.
-// This is a stub - the full implementation exists in accessor.go or similar.
-func (e *Engine) player(who int) int {
-	if e.globals.bx[who] == nil {
-		return 0
+// orders_template returns the orders template for a player: a BEGIN
+// line, the queue of each unit and an END line. Units with queued
+// orders that the player no longer controls are listed last. Only a
+// hash of the order password is kept, so unlike the C template the
+// BEGIN line can't include it.
+// Ported from src/order.c lines 438-529.
+func (e *Engine) orders_template(pl int) []string {
+	pass := ""
+	if p := e.rp_player(pl); p != nil && p.password != "" {
+		pass = ` "password"`
 	}
-	ch := e.globals.bx[who].x_char
-	if ch == nil {
-		return 0
+
+	l := []string{"", fmt.Sprintf("begin %s%s  # %s", box_code_less(pl), pass, e.just_name(pl)), ""}
+
+	l = append(l, e.orders_template_sup(pl, pl)...)
+
+	units := e.loop_units(pl)
+	for _, who := range units {
+		l = append(l, e.orders_template_sup(who, pl)...)
+	}
+
+	// orders_other
+	var others []int
+	for who := range e.globals.orderQueues[pl] {
+		if who == pl || !e.valid_box(who) || e.kind(who) == T_deadchar || containsInt(units, who) {
+			continue
+		}
+
+		// Don't output an empty template for a unit we swore away this turn
+		c := e.rp_command(who)
+		q := e.rp_order_head(pl, who)
+		if (c == nil || c.state == STATE_DONE) && (q == nil || len(q.Orders) == 0) {
+			continue
+		}
+		others = append(others, who)
+	}
+	sort.Ints(others)
+
+	if len(others) > 0 {
+		l = append(l, "#", "# Orders for units you do not control as of now", "#")
 	}
-	return ch.unit_lord
+	for _, who := range others {
+		l = append(l, e.orders_template_sup(who, pl)...)
+	}
+
+	return append(l, "end")
 }
 
-// rp_player returns the entity_player for a player, or nil.
-func (e *Engine) rp_player(pl int) *entity_player {
-	if e.globals.bx[pl] == nil {
-		return nil
+// helper stubs for functions that will be implemented in other sprints
+
+// command_done marks a command as completed.
+// This is a stub that will be properly implemented when command execution is ported.
+func (e *Engine) command_done(c *command) {
+	if c != nil {
+		c.state = STATE_DONE
 	}
-	return e.globals.bx[pl].x_player
 }
@@ -28,18 +28,18 @@ func TestOrderQueue(t *testing.T) {
 	unitID := 2001
 
 	// Ensure the player box exists
-	e.globals.bx[playerID] = &box{
+	e.setBox(playerID, &box{
 		kind:     T_player,
 		skind:    sub_pl_regular,
 		x_player: &entity_player{},
-	}
+	})
 
 	// Ensure the unit box exists
-	e.globals.bx[unitID] = &box{
+	e.setBox(unitID, &box{
 		kind:   T_char,
 		skind:  0,
 		x_char: &entity_char{unit_lord: playerID},
-	}
+	})
 
 	// Clear any existing orders
 	e.ClearOrders()
@@ -89,8 +89,8 @@ func TestOrderQueue(t *testing.T) {
 	}
 
 	// Cleanup
-	e.globals.bx[playerID] = nil
-	e.globals.bx[unitID] = nil
+	e.deleteBox(playerID)
+	e.deleteBox(unitID)
 	e.ClearOrders()
 }
 
@@ -100,16 +100,16 @@ func TestStopOrder(t *testing.T) {
 	playerID := 1002
 	unitID := 2002
 
-	e.globals.bx[playerID] = &box{
+	e.setBox(playerID, &box{
 		kind:     T_player,
 		skind:    sub_pl_regular,
 		x_player: &entity_player{},
-	}
-	e.globals.bx[unitID] = &box{
+	})
+	e.setBox(unitID, &box{
 		kind:   T_char,
 		skind:  0,
 		x_char: &entity_char{unit_lord: playerID},
-	}
+	})
 
 	e.ClearOrders()
 
@@ -138,8 +138,8 @@ func TestStopOrder(t *testing.T) {
 	}
 
 	// Cleanup
-	e.globals.bx[playerID] = nil
-	e.globals.bx[unitID] = nil
+	e.deleteBox(playerID)
+	e.deleteBox(unitID)
 	e.ClearOrders()
 }
 
@@ -174,16 +174,16 @@ func TestFlushUnitOrders(t *testing.T) {
 	playerID := 1003
 	unitID := 2003
 
-	e.globals.bx[playerID] = &box{
+	e.setBox(playerID, &box{
 		kind:     T_player,
 		skind:    sub_pl_regular,
 		x_player: &entity_player{},
-	}
-	e.globals.bx[unitID] = &box{
+	})
+	e.setBox(unitID, &box{
 		kind:   T_char,
 		skind:  0,
 		x_char: &entity_char{unit_lord: playerID},
-	}
+	})
 
 	e.ClearOrders()
 
@@ -204,8 +204,8 @@ func TestFlushUnitOrders(t *testing.T) {
 	}
 
 	// Cleanup
-	e.globals.bx[playerID] = nil
-	e.globals.bx[unitID] = nil
+	e.deleteBox(playerID)
+	e.deleteBox(unitID)
 	e.ClearOrders()
 }
 
@@ -215,16 +215,16 @@ func TestQueueLimit(t *testing.T) {
 	playerID := 1004
 	unitID := 2004
 
-	e.globals.bx[playerID] = &box{
+	e.setBox(playerID, &box{
 		kind:     T_player,
 		skind:    sub_pl_regular,
 		x_player: &entity_player{},
-	}
-	e.globals.bx[unitID] = &box{
+	})
+	e.setBox(unitID, &box{
 		kind:   T_char,
 		skind:  0,
 		x_char: &entity_char{unit_lord: playerID},
-	}
+	})
 
 	e.ClearOrders()
 
@@ -238,8 +238,8 @@ func TestQueueLimit(t *testing.T) {
 	}
 
 	// Cleanup
-	e.globals.bx[playerID] = nil
-	e.globals.bx[unitID] = nil
+	e.deleteBox(playerID)
+	e.deleteBox(unitID)
 	e.ClearOrders()
 }
 
@@ -249,16 +249,16 @@ func TestQueueConvenienceFunction(t *testing.T) {
 	playerID := 1005
 	unitID := 2005
 
-	e.globals.bx[playerID] = &box{
+	e.setBox(playerID, &box{
 		kind:     T_player,
 		skind:    sub_pl_regular,
 		x_player: &entity_player{},
-	}
-	e.globals.bx[unitID] = &box{
+	})
+	e.setBox(unitID, &box{
 		kind:   T_char,
 		skind:  0,
 		x_char: &entity_char{unit_lord: playerID},
-	}
+	})
 
 	e.ClearOrders()
 
@@ -281,8 +281,8 @@ func TestQueueConvenienceFunction(t *testing.T) {
 	}
 
 	// Cleanup
-	e.globals.bx[playerID] = nil
-	e.globals.bx[unitID] = nil
+	e.deleteBox(playerID)
+	e.deleteBox(unitID)
 	e.ClearOrders()
 }
 
@@ -295,22 +295,23 @@ func TestLoadSaveOrders(t *testing.T) {
 	defer db.Close()
 
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 
 	playerID := 1006
 	unitID := 2006
 	turnNumber := 99
 
 	// Ensure entities exist in the bx array
-	e.globals.bx[playerID] = &box{
+	e.setBox(playerID, &box{
 		kind:     T_player,
 		skind:    sub_pl_regular,
 		x_player: &entity_player{},
-	}
-	e.globals.bx[unitID] = &box{
+	})
+	e.setBox(unitID, &box{
 		kind:   T_char,
 		skind:  0,
 		x_char: &entity_char{unit_lord: playerID},
-	}
+	})
 
 	// Create player in database if needed
 	_, _ = e.db.Exec(`INSERT OR IGNORE INTO players (id, code, subkind) VALUES (?, ?, ?)`,
@@ -356,6 +357,9 @@ func TestLoadSaveOrders(t *testing.T) {
 	if e.CountOrders(playerID, unitID) != 2 {
 		t.Errorf("after load: got %d orders, want 2", e.CountOrders(playerID, unitID))
 	}
+	if got := e.globals.bx.get(playerID).x_player.last_order_turn; got != turnNumber {
+		t.Errorf("last_order_turn = %d, want %d", got, turnNumber)
+	}
 
 	orders := e.GetAllOrders(playerID, unitID)
 	if len(orders) >= 1 && orders[0] != "move north" {
@@ -366,7 +370,7 @@ func TestLoadSaveOrders(t *testing.T) {
 	}
 
 	// Cleanup memory (database cleanup happens via defer db.Close())
-	e.globals.bx[playerID] = nil
-	e.globals.bx[unitID] = nil
+	e.deleteBox(playerID)
+	e.deleteBox(unitID)
 	e.ClearOrders()
 }
@@ -0,0 +1,344 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// outbox.go - outgoing mail ported from send_rep() in src/main.c and
+// write_remind_list() in src/eat.c
+
+package taygete
+
+import (
+	"database/sql"
+	"fmt"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// maxSendAttempts is how often delivery of a message is tried before
+// it is marked as failed.
+const maxSendAttempts = 5
+
+// Sender delivers outgoing mail. from is the envelope sender, to the
+// envelope recipients and msg the complete message, headers included.
+type Sender interface {
+	Send(from string, to []string, msg []byte) error
+}
+
+// SMTPSender delivers mail through an SMTP server.
+type SMTPSender struct {
+	Addr string    // host:port of the server
+	Auth smtp.Auth // nil if the server needs no authentication
+}
+
+// Send implements Sender.
+func (s *SMTPSender) Send(from string, to []string, msg []byte) error {
+	return smtp.SendMail(s.Addr, s.Auth, from, to, msg)
+}
+
+// MaildirSender delivers mail into a local maildir, for testing and
+// for games run without a mail server.
+type MaildirSender struct {
+	Dir string
+	seq atomic.Int64
+}
+
+// Send implements Sender. The message is written to tmp/ and moved
+// into new/ once it is complete.
+func (s *MaildirSender) Send(from string, to []string, msg []byte) error {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(s.Dir, sub), 0o755); err != nil {
+			return err
+		}
+	}
+
+	name := fmt.Sprintf("%d.%d_%d.taygete", time.Now().Unix(), os.Getpid(), s.seq.Add(1))
+	tmp := filepath.Join(s.Dir, "tmp", name)
+	if err := os.WriteFile(tmp, msg, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.Dir, "new", name))
+}
+
+// entab replaces runs of blanks that end on a tab stop with a TAB, as
+// the report pipeline's "unexpand -t8" did for players who can take
+// tabs.
+func entab(s string) string {
+	var b strings.Builder
+	spaces, col := 0, 0
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == ' ' {
+			spaces++
+			col++
+			if col%8 == 0 {
+				if spaces > 1 {
+					b.WriteByte('\t')
+				} else {
+					b.WriteByte(' ')
+				}
+				spaces = 0
+			}
+			continue
+		}
+
+		b.WriteString(strings.Repeat(" ", spaces))
+		spaces = 0
+		b.WriteByte(c)
+		if c == '\t' {
+			col = (col/8 + 1) * 8
+		} else {
+			col++
+		}
+	}
+	b.WriteString(strings.Repeat(" ", spaces))
+
+	return b.String()
+}
+
+// split_report breaks a report into parts of at most splitLines lines
+// and splitBytes bytes, whichever limit is hit first. A limit of zero
+// is no limit. A single line longer than splitBytes gets a part of
+// its own.
+func split_report(lines []string, splitLines, splitBytes int) [][]string {
+	if splitLines == 0 && splitBytes == 0 {
+		return [][]string{lines}
+	}
+
+	var parts [][]string
+	var cur []string
+	size := 0
+
+	for _, line := range lines {
+		n := len(line) + 1
+		if len(cur) > 0 &&
+			((splitLines > 0 && len(cur) >= splitLines) ||
+				(splitBytes > 0 && size+n > splitBytes)) {
+			parts = append(parts, cur)
+			cur, size = nil, 0
+		}
+		cur = append(cur, line)
+		size += n
+	}
+	if len(cur) > 0 || len(parts) == 0 {
+		parts = append(parts, cur)
+	}
+
+	return parts
+}
+
+// mail_headers returns the headers for mail to a player.
+// Ported from src/main.c lines 315-322.
+func mail_headers(p *entity_player, subject string) []string {
+	fullName := p.full_name
+	if fullName == "" {
+		fullName = "???"
+	}
+
+	h := []string{"From: " + from_host}
+	if reply_host != "" {
+		h = append(h, "Reply-To: "+reply_host)
+	}
+	return append(h,
+		fmt.Sprintf("To: %s (%s)", p.email, fullName),
+		"Subject: "+subject,
+		"")
+}
+
+// queue_mail adds a message to the outbox.
+func (e *Engine) queue_mail(pl, turn int, kind string, part, parts int, to string, msg []string) error {
+	_, err := e.db.Exec(`
+		INSERT INTO outbox (player_id, turn_number, kind, part, parts, to_addr, message)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, pl, turn, kind, part, parts, to, strings.Join(msg, "\r\n")+"\r\n")
+	if err != nil {
+		return fmt.Errorf("queue %s for player %d: %w", kind, pl, err)
+	}
+	return nil
+}
+
+// queued reports whether mail of the given kind is already in the
+// outbox for a player and turn, so that queueing twice is harmless.
+func (e *Engine) queued(pl, turn int, kind string) (bool, error) {
+	var n int
+	err := e.db.QueryRow(`
+		SELECT COUNT(*) FROM outbox WHERE player_id = ? AND turn_number = ? AND kind = ?
+	`, pl, turn, kind).Scan(&n)
+	return n > 0, err
+}
+
+// QueueReports adds the turn report of each player with an email
+// address to the outbox, split as the player asked. Turn 0 is the
+// current turn. It returns the number of reports queued.
+// Port of C mail_reports() and send_rep().
+func (e *Engine) QueueReports(turn int) (int, error) {
+	if turn == 0 {
+		turn = int(e.globals.sysclock.turn)
+	}
+	count := 0
+
+	for _, pl := range e.Players() {
+		p := e.globals.bx.get(pl).x_player
+		if p == nil || p.email == "" {
+			continue
+		}
+
+		if done, err := e.queued(pl, turn, "report"); err != nil {
+			return count, err
+		} else if done {
+			continue
+		}
+
+		var body string
+		err := e.db.QueryRow(`
+			SELECT body FROM reports WHERE turn_number = ? AND player_id = ? ORDER BY id DESC LIMIT 1
+		`, turn, pl).Scan(&body)
+		if err == sql.ErrNoRows {
+			continue
+		} else if err != nil {
+			return count, fmt.Errorf("read report %d for player %d: %w", turn, pl, err)
+		}
+
+		lines := strings.Split(strings.TrimRight(body, "\n"), "\n")
+		if p.notab == 0 {
+			for i := range lines {
+				lines[i] = entab(lines[i])
+			}
+		}
+
+		parts := split_report(lines, p.split_lines, p.split_bytes)
+		for i, part := range parts {
+			subject := fmt.Sprintf("%s - Turn %d report", game_title, turn)
+			if len(parts) > 1 {
+				subject += fmt.Sprintf(" (part %d of %d)", i+1, len(parts))
+			}
+			msg := append(mail_headers(p, subject), part...)
+			if err := e.queue_mail(pl, turn, "report", i+1, len(parts), p.email, msg); err != nil {
+				return count, err
+			}
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// QueueReminders adds a reminder to the outbox for each regular player
+// who hasn't sent orders this turn and hasn't asked not to be
+// reminded. Turn 0 is the current turn. It returns the number of
+// reminders queued.
+// Port of C write_remind_list().
+func (e *Engine) QueueReminders(turn int) (int, error) {
+	if turn == 0 {
+		turn = int(e.globals.sysclock.turn)
+	}
+	count := 0
+
+	for _, pl := range e.RegularPlayers() {
+		p := e.globals.bx.get(pl).x_player
+		if p == nil || p.sent_orders != 0 || p.dont_remind != 0 {
+			continue
+		}
+
+		if p.email == "" {
+			if e.logger != nil {
+				e.logger.Warn("reminders: player has no email address", "player", e.box_code(pl))
+			}
+			continue
+		}
+
+		if done, err := e.queued(pl, turn, "reminder"); err != nil {
+			return count, err
+		} else if done {
+			continue
+		}
+
+		subject := fmt.Sprintf("%s - Turn %d orders reminder", game_title, turn)
+		msg := append(mail_headers(p, subject),
+			fmt.Sprintf("No orders have been received for %s this turn.", e.box_name(pl)),
+			"",
+			fmt.Sprintf("Please send your orders for turn %d to %s.", turn, reply_host))
+		if err := e.queue_mail(pl, turn, "reminder", 1, 1, p.email, msg); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// SendOutbox delivers the pending mail in the outbox. A message that
+// can't be delivered stays pending until it has failed
+// maxSendAttempts times. It returns the number sent and failed.
+func (e *Engine) SendOutbox(s Sender) (sent, failed int, err error) {
+	type pending struct {
+		id       int
+		to       string
+		msg      string
+		attempts int
+	}
+
+	rows, err := e.db.Query(`
+		SELECT id, to_addr, message, attempts FROM outbox WHERE status = 'pending' ORDER BY id
+	`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("query outbox: %w", err)
+	}
+	var queue []pending
+	for rows.Next() {
+		var m pending
+		if err := rows.Scan(&m.id, &m.to, &m.msg, &m.attempts); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("scan outbox: %w", err)
+		}
+		queue = append(queue, m)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, 0, err
+	}
+	rows.Close()
+
+	for _, m := range queue {
+		if serr := s.Send(from_host, strings.Fields(m.to), []byte(m.msg)); serr != nil {
+			status := "pending"
+			if m.attempts+1 >= maxSendAttempts {
+				status = "failed"
+			}
+			if _, err := e.db.Exec(`
+				UPDATE outbox SET status = ?, attempts = attempts + 1, last_error = ? WHERE id = ?
+			`, status, serr.Error(), m.id); err != nil {
+				return sent, failed, fmt.Errorf("update outbox %d: %w", m.id, err)
+			}
+			failed++
+			continue
+		}
+
+		if _, err := e.db.Exec(`
+			UPDATE outbox SET status = 'sent', attempts = attempts + 1, last_error = NULL,
+			                  sent_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`, m.id); err != nil {
+			return sent, failed, fmt.Errorf("update outbox %d: %w", m.id, err)
+		}
+		sent++
+	}
+
+	return sent, failed, nil
+}
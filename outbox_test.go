@@ -0,0 +1,223 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package taygete
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEntab(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"no blanks", "no blanks"},
+		{"        indented", "\tindented"},
+		{"           eleven", "\t   eleven"},
+		{"a       b", "a\tb"},
+		{"seven: x", "seven: x"},
+		{"trailing        ", "trailing\t"},
+	}
+	for _, tt := range tests {
+		if got := entab(tt.in); got != tt.want {
+			t.Errorf("entab(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSplitReport(t *testing.T) {
+	lines := make([]string, 1200)
+	for i := range lines {
+		lines[i] = "123456789" // ten bytes with the newline
+	}
+
+	tests := []struct {
+		name         string
+		lines, bytes int
+		want         []int
+	}{
+		{"no split", 0, 0, []int{1200}},
+		{"lines", 500, 0, []int{500, 500, 200}},
+		{"bytes", 0, 10000, []int{1000, 200}},
+		{"lines first", 500, 10000, []int{500, 500, 200}},
+		{"bytes first", 1100, 10000, []int{1000, 200}},
+	}
+	for _, tt := range tests {
+		parts := split_report(lines, tt.lines, tt.bytes)
+		var got []int
+		for _, p := range parts {
+			got = append(got, len(p))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: parts = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// setupOutboxTest builds a regular player with an email address and a
+// saved turn report of n lines.
+func setupOutboxTest(t *testing.T, n int) (pl int) {
+	t.Helper()
+	newTestEngine(t)
+	teg.globals.sysclock.turn = 12
+
+	pl = 50_001
+	teg.alloc_box(pl, T_player, sub_pl_regular)
+	teg.set_name(pl, "Red Company")
+	teg.p_player(pl).email = "ann@example.com"
+	teg.p_player(pl).full_name = "Ann Player"
+
+	teg.globals.reports = make(map[int][]string)
+	for i := 0; i < n; i++ {
+		teg.globals.reports[pl] = append(teg.globals.reports[pl], fmt.Sprintf("line %d        of report", i+1))
+	}
+	if n > 0 {
+		if err := teg.saveReports(); err != nil {
+			t.Fatalf("saveReports: %v", err)
+		}
+	}
+	return pl
+}
+
+// maildirMessages returns the messages delivered to dir/new.
+func maildirMessages(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(dir, "new"))
+	if err != nil {
+		t.Fatalf("read maildir: %v", err)
+	}
+	var msgs []string
+	for _, ent := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, "new", ent.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, string(b))
+	}
+	return msgs
+}
+
+func TestQueueReports(t *testing.T) {
+	pl := setupOutboxTest(t, 1200)
+	teg.p_player(pl).split_lines = 500
+
+	n, err := teg.QueueReports(0)
+	if err != nil || n != 1 {
+		t.Fatalf("QueueReports = %d, %v; want 1", n, err)
+	}
+	// Queueing again doesn't duplicate the report
+	if n, err := teg.QueueReports(12); err != nil || n != 0 {
+		t.Fatalf("QueueReports again = %d, %v; want 0", n, err)
+	}
+
+	dir := t.TempDir()
+	sent, failed, err := teg.SendOutbox(&MaildirSender{Dir: dir})
+	if err != nil || sent != 3 || failed != 0 {
+		t.Fatalf("SendOutbox = %d sent, %d failed, %v; want 3 sent", sent, failed, err)
+	}
+
+	msgs := maildirMessages(t, dir)
+	if len(msgs) != 3 {
+		t.Fatalf("%d messages delivered, want 3", len(msgs))
+	}
+	var parts []string
+	for _, m := range msgs {
+		if !strings.Contains(m, "To: ann@example.com (Ann Player)\r\n") {
+			t.Errorf("message missing To header:\n%.200s", m)
+		}
+		if !strings.Contains(m, "\t") {
+			t.Errorf("report lines not entabbed:\n%.400s", m)
+		}
+		for i := 1; i <= 3; i++ {
+			if strings.Contains(m, fmt.Sprintf("Turn 12 report (part %d of 3)\r\n", i)) {
+				parts = append(parts, fmt.Sprint(i))
+			}
+		}
+	}
+	if len(parts) != 3 {
+		t.Errorf("parts found = %v, want 1, 2 and 3", parts)
+	}
+
+	// Nothing is left to send
+	if sent, _, _ := teg.SendOutbox(&MaildirSender{Dir: dir}); sent != 0 {
+		t.Errorf("second SendOutbox sent %d", sent)
+	}
+}
+
+func TestQueueReminders(t *testing.T) {
+	quiet := setupOutboxTest(t, 0)
+
+	sent, noRemind, noEmail := 50_002, 50_003, 50_004
+	for _, pl := range []int{sent, noRemind, noEmail} {
+		teg.alloc_box(pl, T_player, sub_pl_regular)
+		teg.p_player(pl).email = fmt.Sprintf("%d@example.com", pl)
+	}
+	teg.p_player(sent).sent_orders = 1
+	teg.p_player(noRemind).dont_remind = 1
+	teg.p_player(noEmail).email = ""
+
+	n, err := teg.QueueReminders(0)
+	if err != nil || n != 1 {
+		t.Fatalf("QueueReminders = %d, %v; want 1", n, err)
+	}
+
+	var to, msg string
+	if err := teg.db.QueryRow(`SELECT to_addr, message FROM outbox WHERE player_id = ? AND kind = 'reminder'`,
+		quiet).Scan(&to, &msg); err != nil {
+		t.Fatalf("select reminder: %v", err)
+	}
+	if to != "ann@example.com" || !strings.Contains(msg, "Turn 12 orders reminder") {
+		t.Errorf("reminder to %q:\n%s", to, msg)
+	}
+}
+
+// failingSender fails every delivery.
+type failingSender struct{}
+
+func (failingSender) Send(from string, to []string, msg []byte) error {
+	return errors.New("connection refused")
+}
+
+func TestSendOutboxFailure(t *testing.T) {
+	setupOutboxTest(t, 0)
+	if _, err := teg.QueueReminders(0); err != nil {
+		t.Fatalf("QueueReminders: %v", err)
+	}
+
+	for i := 1; i <= maxSendAttempts; i++ {
+		if _, failed, err := teg.SendOutbox(failingSender{}); err != nil || failed != 1 {
+			t.Fatalf("attempt %d: failed %d, %v", i, failed, err)
+		}
+	}
+
+	var status, lastErr string
+	var attempts int
+	if err := teg.db.QueryRow(`SELECT status, attempts, last_error FROM outbox`).Scan(&status, &attempts, &lastErr); err != nil {
+		t.Fatalf("select outbox: %v", err)
+	}
+	if status != "failed" || attempts != maxSendAttempts || lastErr != "connection refused" {
+		t.Errorf("outbox = %s after %d attempts, %q", status, attempts, lastErr)
+	}
+	if _, failed, _ := teg.SendOutbox(failingSender{}); failed != 0 {
+		t.Errorf("failed message was retried")
+	}
+}
@@ -0,0 +1,441 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// perm.go - Admit permissions and declared attitudes ported from src/perm.c
+
+package taygete
+
+import (
+	"slices"
+	"strings"
+)
+
+// rp_admit returns pl's admit declaration for targ, or nil.
+// Ported from src/perm.c lines 9-24.
+func (e *Engine) rp_admit(pl, targ int) *admit {
+	p := e.rp_player(pl)
+	if p == nil {
+		return nil
+	}
+
+	for _, a := range p.admits {
+		if a.targ == targ {
+			return a
+		}
+	}
+
+	return nil
+}
+
+// p_admit returns pl's admit declaration for targ, creating it if
+// needed.
+// Ported from src/perm.c lines 27-49.
+func (e *Engine) p_admit(pl, targ int) *admit {
+	e.markDirty(pl)
+	if a := e.rp_admit(pl, targ); a != nil {
+		return a
+	}
+
+	a := &admit{targ: targ}
+	p := e.p_player(pl)
+	p.admits = append(p.admits, a)
+
+	return a
+}
+
+// will_admit reports whether pl will admit who into targ. Units of
+// the same faction are always admitted.
+// Ported from src/perm.c lines 52-88.
+func (e *Engine) will_admit(pl, who, targ int) bool {
+	if e.default_garrison(targ) != 0 {
+		return true
+	}
+
+	pl = e.player(pl)
+
+	if e.player(who) == pl {
+		return true
+	}
+
+	p := e.rp_admit(pl, targ)
+	if p == nil {
+		return false
+	}
+
+	found := p.l.Lookup(who) >= 0 || p.l.Lookup(e.player(who)) >= 0
+
+	if p.sense != 0 {
+		return !found
+	}
+
+	return found
+}
+
+// v_admit declares who may stack with or enter targ. The first ADMIT
+// for a target each turn replaces the old list; "all" admits everyone
+// except those listed.
+// Ported from src/perm.c lines 91-136.
+func (e *Engine) v_admit(c *command) int {
+	targ := c.a
+	pl := e.player(c.who)
+
+	if !e.valid_box(targ) {
+		wout(c.who, "Must specify an entity for admit.")
+		return FALSE
+	}
+
+	e.cmd_shift(c)
+
+	p := e.p_admit(pl, targ)
+
+	if p.flag == 0 {
+		p.sense = FALSE
+		p.l.Clear()
+		p.flag = TRUE
+	}
+
+	for e.numargs(c) > 0 {
+		if i_strcmp(get_parse_arg(c, 1), "all") == 0 {
+			p.sense = TRUE
+		} else if e.kind(c.a) == T_char ||
+			e.kind(c.a) == T_player ||
+			e.kind(c.a) == T_unform {
+			p.l.Append(c.a)
+		} else {
+			wout(c.who, "%s isn't a valid entity to admit.",
+				get_parse_arg(c, 1))
+		}
+
+		e.cmd_shift(c)
+	}
+
+	return TRUE
+}
+
+// clearAdmitFlags lets the first ADMIT of a new turn replace each
+// declaration again. The C game got this for free by reloading the
+// database every turn.
+func (e *Engine) clearAdmitFlags() {
+	for _, pl := range e.Players() {
+		if p := e.rp_player(pl); p != nil {
+			for _, a := range p.admits {
+				a.flag = FALSE
+			}
+		}
+	}
+}
+
+// print_admit_sup writes one admit declaration, a dozen entities to
+// a line.
+// Ported from src/perm.c lines 150-183.
+func (e *Engine) print_admit_sup(pl int, p *admit, indent string) {
+	buf := indent + sout("admit %4s", box_code_less(p.targ))
+	count := 0
+
+	if p.sense != 0 {
+		buf += "  all"
+		count++
+	}
+
+	for _, n := range p.l.Values() {
+		if !e.valid_box(n) {
+			continue
+		}
+
+		count++
+		if count >= 12 {
+			e.report_out(pl, "%s", buf)
+			buf = indent + strings.Repeat(" ", 10)
+			count = 1
+		}
+
+		buf += sout(" %4s", box_code_less(n))
+	}
+
+	if count != 0 {
+		e.report_out(pl, "%s", buf)
+	}
+}
+
+// print_admit writes pl's admit declarations to their report.
+// Ported from src/perm.c lines 186-216.
+func (e *Engine) print_admit(pl int) {
+	p := e.p_player(pl)
+
+	slices.SortFunc(p.admits, func(a, b *admit) int {
+		return a.targ - b.targ
+	})
+
+	first := true
+	for _, a := range p.admits {
+		if !e.valid_box(a.targ) {
+			continue
+		}
+
+		if first {
+			e.report_out(pl, "")
+			e.report_out(pl, "Admit permissions:")
+			e.report_out(pl, "")
+			first = false
+		}
+
+		e.print_admit_sup(pl, a, "   ")
+	}
+}
+
+// clear_all_att forgets every attitude who has declared.
+// Ported from src/perm.c lines 219-231.
+func (e *Engine) clear_all_att(who int) {
+	p := e.rp_disp(who)
+	if p == nil {
+		return
+	}
+
+	p.neutral.Clear()
+	p.hostile.Clear()
+	p.defend.Clear()
+	e.markDirty(who)
+}
+
+// set_att declares who's attitude toward targ. ATT_NONE just removes
+// any earlier declaration.
+// Ported from src/perm.c lines 234-269.
+func (e *Engine) set_att(who, targ, disp int) {
+	p := e.p_disp(who)
+
+	p.neutral.RemValue(targ)
+	p.hostile.RemValue(targ)
+	p.defend.RemValue(targ)
+
+	var l *IList
+	switch disp {
+	case NEUTRAL:
+		l = &p.neutral
+	case HOSTILE:
+		l = &p.hostile
+	case DEFEND:
+		l = &p.defend
+	case ATT_NONE:
+		return
+	default:
+		panic("set_att: bad disposition")
+	}
+
+	l.Append(targ)
+	slices.Sort(l.Values())
+}
+
+// is_hostile reports whether who will attack targ on sight. Subloc
+// monsters are hostile to every player unit they can be defeated by.
+// Ported from src/perm.c lines 272-310.
+func (e *Engine) is_hostile(who, targ int) bool {
+	if e.player(who) == e.player(targ) {
+		return false
+	}
+
+	if e.npc_program(who) == PROG_subloc_monster &&
+		!e.is_npc(targ) &&
+		e.only_defeatable(who) == 0 {
+		return true
+	}
+
+	if e.subkind(who) == sub_garrison {
+		if m := e.rp_misc(who); m != nil && m.garr_host.Lookup(targ) >= 0 {
+			return true
+		}
+	}
+
+	if p := e.rp_disp(who); p != nil && p.hostile.Lookup(targ) >= 0 {
+		return true
+	}
+
+	if p := e.rp_disp(e.player(who)); p != nil && p.hostile.Lookup(targ) >= 0 {
+		return true
+	}
+
+	return false
+}
+
+// disp_defend looks targ up in a unit's or player's declarations.
+// Returns whether a declaration was found and whether it is defend.
+func (e *Engine) disp_defend(p *att_ent, targ int) (found, defend bool) {
+	if p == nil {
+		return false, false
+	}
+
+	if p.defend.Lookup(targ) >= 0 {
+		return true, true
+	}
+	if p.neutral.Lookup(targ) >= 0 {
+		return true, false
+	}
+
+	if p.defend.Lookup(e.player(targ)) >= 0 {
+		return true, true
+	}
+	if p.neutral.Lookup(e.player(targ)) >= 0 {
+		return true, false
+	}
+
+	return false, false
+}
+
+// is_defend reports whether who will come to targ's aid. Declarations
+// by the unit override its faction's; otherwise units defend their
+// own faction unless their lord is cloaked.
+// Ported from src/perm.c lines 313-371.
+func (e *Engine) is_defend(who, targ int) bool {
+	if e.is_hostile(who, targ) {
+		return false
+	}
+
+	if e.default_garrison(who) != 0 {
+		return true
+	}
+
+	if found, defend := e.disp_defend(e.rp_disp(who), targ); found {
+		return defend
+	}
+
+	pl := e.player(who)
+
+	if found, defend := e.disp_defend(e.rp_disp(pl), targ); found {
+		return defend
+	}
+
+	if pl == e.player(targ) && pl != indep_player {
+		return !e.cloak_lord(who)
+	}
+
+	return false
+}
+
+// cloak_lord reports whether who hides the identity of its lord.
+// Ported from src/stealth.c cloak_lord().
+func (e *Engine) cloak_lord(n int) bool {
+	return e.has_skill(n, sk_hide_lord)
+}
+
+// v_set_att declares attitude k toward each entity listed.
+// Ported from src/perm.c lines 374-400.
+func (e *Engine) v_set_att(c *command, k int) int {
+	for e.numargs(c) > 0 {
+		if !e.valid_box(c.a) {
+			wout(c.who, "%s is not a valid entity.", get_parse_arg(c, 1))
+		} else if k == HOSTILE && e.player(c.who) == e.player(c.a) &&
+			e.player(c.who) != indep_player {
+			wout(c.who, "Can't be hostile to a unit in the same faction.")
+		} else {
+			e.set_att(c.who, c.a, k)
+		}
+
+		e.cmd_shift(c)
+	}
+
+	return TRUE
+}
+
+// v_hostile declares units or factions hostile.
+// Ported from src/perm.c lines 403-407.
+func (e *Engine) v_hostile(c *command) int {
+	return e.v_set_att(c, HOSTILE)
+}
+
+// v_defend declares units or factions to be defended.
+// Ported from src/perm.c lines 410-414.
+func (e *Engine) v_defend(c *command) int {
+	return e.v_set_att(c, DEFEND)
+}
+
+// v_neutral declares units or factions neutral.
+// Ported from src/perm.c lines 417-421.
+func (e *Engine) v_neutral(c *command) int {
+	return e.v_set_att(c, NEUTRAL)
+}
+
+// v_att_clear removes declared attitudes.
+// Ported from src/perm.c lines 424-428.
+func (e *Engine) v_att_clear(c *command) int {
+	return e.v_set_att(c, ATT_NONE)
+}
+
+// print_att_sup writes one list of declared attitudes, a dozen
+// entities to a line.
+// Ported from src/perm.c lines 431-470.
+func (e *Engine) print_att_sup(who int, l *IList, header string, first *bool) {
+	if l.Len() == 0 {
+		return
+	}
+
+	slices.Sort(l.Values())
+
+	const indent = "   "
+	buf := indent + header
+	count := 0
+
+	for _, n := range l.Values() {
+		if !e.valid_box(n) {
+			continue
+		}
+
+		if *first {
+			e.report_out(who, "")
+			e.report_out(who, "Declared attitudes:")
+			*first = false
+		}
+
+		count++
+		if count >= 12 {
+			e.report_out(who, "%s", buf)
+			buf = indent + strings.Repeat(" ", len(header))
+			count = 1
+		}
+
+		buf += sout(" %4s", box_code_less(n))
+	}
+
+	if count != 0 {
+		e.report_out(who, "%s", buf)
+	}
+}
+
+// playerEntInfo writes each faction's admit permissions and declared
+// attitudes to its report. Unclaimed items are not reported yet.
+// Port of C player_ent_info() from report.c.
+func (e *Engine) playerEntInfo() {
+	for _, pl := range e.Players() {
+		if e.subkind(pl) == sub_pl_silent {
+			continue
+		}
+
+		e.print_admit(pl)
+		e.print_att(pl, pl)
+	}
+}
+
+// print_att writes the attitudes n has declared to who's report.
+// Ported from src/perm.c lines 473-495.
+func (e *Engine) print_att(who, n int) {
+	p := e.rp_disp(n)
+	if p == nil {
+		return
+	}
+
+	first := true
+	e.print_att_sup(who, &p.hostile, "hostile", &first)
+	e.print_att_sup(who, &p.neutral, "neutral", &first)
+	e.print_att_sup(who, &p.defend, "defend ", &first)
+}
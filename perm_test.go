@@ -0,0 +1,177 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package taygete
+
+import (
+	"slices"
+	"testing"
+)
+
+// setupPermTest creates two factions with a noble each, and a tower
+// owned by the first.
+func setupPermTest(t *testing.T) (e *Engine, pl1, who1, pl2, who2, tower int) {
+	t.Helper()
+	e = newTestEngine(t)
+
+	pl1, pl2 = 50_001, 50_002
+	who1, who2 = 1001, 1002
+	tower = 1003
+
+	teg.alloc_box(pl1, T_player, sub_pl_regular)
+	teg.alloc_box(pl2, T_player, sub_pl_regular)
+	teg.alloc_box(who1, T_char, 0)
+	teg.alloc_box(who2, T_char, 0)
+	teg.alloc_box(tower, T_loc, sub_tower)
+	teg.p_char(who1).unit_lord = pl1
+	teg.p_char(who2).unit_lord = pl2
+
+	return e, pl1, who1, pl2, who2, tower
+}
+
+func TestWillAdmit(t *testing.T) {
+	e, pl1, who1, pl2, who2, tower := setupPermTest(t)
+
+	if !teg.will_admit(who1, who1, tower) {
+		t.Error("faction should admit its own units")
+	}
+	if teg.will_admit(who1, who2, tower) {
+		t.Error("admitted a stranger without a declaration")
+	}
+
+	c := &command{who: who1}
+	if !e.oly_parse(c, "admit "+box_code_less(tower)+" "+box_code_less(pl2)) {
+		t.Fatal("oly_parse failed")
+	}
+	if got := teg.v_admit(c); got != TRUE {
+		t.Fatalf("v_admit = %d, want TRUE", got)
+	}
+	if !teg.will_admit(pl1, who2, tower) {
+		t.Error("didn't admit a unit of an admitted faction")
+	}
+
+	// "all" admits everyone except those listed
+	c = &command{who: who1}
+	e.oly_parse(c, "admit "+box_code_less(tower)+" all "+box_code_less(who2))
+	teg.v_admit(c)
+	if teg.will_admit(pl1, who2, tower) {
+		t.Error("admitted a unit excluded from all")
+	}
+	if p := teg.rp_admit(pl1, tower); p == nil || p.sense != TRUE {
+		t.Error("admit all didn't set sense")
+	}
+}
+
+func TestAdmitReplacedEachTurn(t *testing.T) {
+	e, pl1, who1, pl2, who2, tower := setupPermTest(t)
+
+	admit := func(n int) {
+		c := &command{who: who1}
+		e.oly_parse(c, "admit "+box_code_less(tower)+" "+box_code_less(n))
+		teg.v_admit(c)
+	}
+
+	admit(who2)
+	admit(pl2)
+	if got := teg.rp_admit(pl1, tower).l.Values(); !slices.Equal(got, []int{who2, pl2}) {
+		t.Errorf("admit list = %v, want both entries in the same turn", got)
+	}
+
+	e.clearAdmitFlags()
+	admit(pl2)
+	if got := teg.rp_admit(pl1, tower).l.Values(); !slices.Equal(got, []int{pl2}) {
+		t.Errorf("admit list = %v, want first admit of the turn to replace it", got)
+	}
+}
+
+func TestSetAtt(t *testing.T) {
+	_, pl1, who1, pl2, who2, _ := setupPermTest(t)
+
+	if !teg.is_defend(who1, who1) {
+		t.Error("units should defend their own faction")
+	}
+	if teg.is_defend(who1, who2) || teg.is_hostile(who1, who2) {
+		t.Error("attitude toward a stranger should be neutral")
+	}
+
+	teg.set_att(pl1, who2, HOSTILE)
+	if !teg.is_hostile(who1, who2) {
+		t.Error("faction hostility didn't apply to its units")
+	}
+	if teg.is_defend(who1, who2) {
+		t.Error("defending a hostile unit")
+	}
+
+	teg.set_att(pl1, who2, ATT_NONE)
+	teg.set_att(pl1, pl2, DEFEND)
+	if p := teg.rp_disp(pl1); p.hostile.Len() != 0 || p.defend.Lookup(pl2) < 0 {
+		t.Error("set_att didn't move the declaration to defend")
+	}
+	if !teg.is_defend(who1, who2) {
+		t.Error("faction defend didn't apply to its units")
+	}
+
+	// A unit's own declaration overrides its faction's
+	teg.set_att(who1, who2, NEUTRAL)
+	if teg.is_defend(who1, who2) {
+		t.Error("unit neutral declaration didn't override faction defend")
+	}
+
+	teg.set_att(who1, who2, ATT_NONE)
+	if p := teg.rp_disp(who1); p.neutral.Len() != 0 {
+		t.Error("ATT_NONE didn't clear the declaration")
+	}
+}
+
+func TestHostileSameFaction(t *testing.T) {
+	e, pl1, who1, _, _, _ := setupPermTest(t)
+	teg.alloc_box(1004, T_char, 0)
+	teg.p_char(1004).unit_lord = pl1
+
+	c := &command{who: who1}
+	e.oly_parse(c, "hostile 1004")
+	teg.v_hostile(c)
+
+	if teg.is_hostile(who1, 1004) {
+		t.Error("declared hostility toward a unit in the same faction")
+	}
+}
+
+func TestPlayerEntInfo(t *testing.T) {
+	e, pl1, who1, pl2, _, tower := setupPermTest(t)
+
+	teg.p_admit(pl1, tower).l.Append(pl2)
+	teg.set_att(pl1, who1, DEFEND)
+	teg.set_att(pl1, pl2, HOSTILE)
+
+	e.playerEntInfo()
+
+	report := e.Report(pl1)
+	for _, want := range []string{
+		"Admit permissions:",
+		"   admit " + box_code_less(tower) + "  " + box_code_less(pl2),
+		"Declared attitudes:",
+		"   hostile  " + box_code_less(pl2),
+		"   defend  " + box_code_less(who1),
+	} {
+		if !slices.Contains(report, want) {
+			t.Errorf("report missing %q:\n%q", want, report)
+		}
+	}
+	if len(e.Report(pl2)) != 0 {
+		t.Errorf("report for %d = %q, want empty", pl2, e.Report(pl2))
+	}
+}
@@ -0,0 +1,591 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// produce.go - Mining and harvesting ported from src/produce.c
+//
+// Mines yield their whole stock of ore at the end of a week of work.
+// Harvesting (COLLECT and the orders and skills that rewrite
+// themselves into it) takes one item a day, or one per worker for
+// worker-driven harvests, until the location runs out.
+
+package taygete
+
+import "fmt"
+
+const (
+	MOUNTAIN_STONE = 50
+	POPPY_OPIUM    = 25
+)
+
+// terr_prod lists the goods each kind of location produces.
+var terr_prod = []struct {
+	terr schar // terrain type
+	item int   // good produced by location
+	qty  int   // amount produced
+}{
+	{sub_forest, item_lumber, 30},
+	{sub_sacred_grove, item_lumber, 5},
+	{sub_tree_circle, item_lumber, 5},
+
+	{sub_mountain, item_stone, MOUNTAIN_STONE},
+	{sub_rocky_hill, item_stone, MOUNTAIN_STONE},
+	{sub_desert, item_stone, 10},
+
+	{sub_cave, item_farrenstone, 2},
+	{sub_plain, item_wild_horse, 5},
+	{sub_pasture, item_wild_horse, 5},
+	{sub_ocean, item_fish, 50},
+
+	{sub_mallorn_grove, item_avinia_leaf, 2},
+	{sub_mallorn_grove, item_mallorn_wood, 2},
+
+	{sub_bog, item_spiny_root, 4},
+	{sub_pits, item_spiny_root, 4},
+	{sub_swamp, item_spiny_root, 1},
+
+	{sub_yew_grove, item_yew, 5},
+	{sub_graveyard, item_corpse, 15},
+	{sub_tree_circle, item_lana_bark, 3},
+	{sub_sand_pit, item_pretus_bones, 1},
+
+	{sub_swamp, item_opium, POPPY_OPIUM},
+	{sub_poppy_field, item_opium, POPPY_OPIUM},
+
+	{sub_forest, item_peasant, 10},
+	{sub_mountain, item_peasant, 10},
+	{sub_plain, item_peasant, 10},
+	{sub_city, item_peasant, 10},
+}
+
+// item_gen_here reports whether terrain terr produces item.
+// Ported from src/produce.c lines 172-182.
+func item_gen_here(terr schar, item int) bool {
+	for _, t := range terr_prod {
+		if t.terr == terr && t.item == item {
+			return true
+		}
+	}
+	return false
+}
+
+// start_generic_mine checks that who is in a mine with at least ten
+// workers before mining item.
+// Ported from src/produce.c lines 185-210.
+func (e *Engine) start_generic_mine(c *command, item int) int {
+	where := e.subloc(c.who)
+
+	if e.subkind(where) != sub_mine {
+		wout(c.who, "Must be in a mine to extract %s.", e.just_name(item))
+		return FALSE
+	}
+
+	if e.has_item(c.who, item_worker) < 10 {
+		wout(c.who, "Mining activity requires at least ten workers.")
+		return FALSE
+	}
+
+	wout(c.who, "Will mine %s for the next %s days.", e.just_name(item), nice_num(c.wait))
+
+	return TRUE
+}
+
+// finish_generic_mine deepens the shaft and takes all of item the
+// mine holds. Deep shafts may turn up a gate crystal.
+// Ported from src/produce.c lines 213-262.
+func (e *Engine) finish_generic_mine(c *command, item int) int {
+	where := e.subloc(c.who)
+
+	if e.subkind(where) != sub_mine {
+		wout(c.who, "%s is no longer in a mine.", e.box_name(c.who))
+		return FALSE
+	}
+
+	if e.has_item(c.who, item_worker) < 10 {
+		wout(c.who, "%s no longer has ten workers.", e.box_name(c.who))
+		return FALSE
+	}
+
+	depth := e.mine_depth(where)
+	e.p_subloc(where).shaft_depth++
+
+	if depth >= 4 && e.rndFrom(streamSkills, 1, 5) == 1 && e.has_item(where, item_gate_crystal) > 0 {
+		wout(c.who, "A gate crystal was found while mining!")
+		e.move_item(where, c.who, item_gate_crystal, 1)
+	}
+
+	qty := e.has_item(where, item)
+	if qty <= 0 {
+		wout(c.who, "Mining yielded no %s.", e.just_name(item))
+		return FALSE
+	}
+
+	e.move_item(where, c.who, item, qty)
+
+	wout(c.who, "Mining yielded %s.", e.box_name_qty(item, qty))
+	return TRUE
+}
+
+// v_mine_iron starts mining iron.
+// Ported from src/produce.c lines 265-270.
+func (e *Engine) v_mine_iron(c *command) int {
+	return e.start_generic_mine(c, item_iron)
+}
+
+// d_mine_iron finishes mining iron.
+// Ported from src/produce.c lines 273-278.
+func (e *Engine) d_mine_iron(c *command) int {
+	return e.finish_generic_mine(c, item_iron)
+}
+
+// v_mine_gold starts mining gold.
+// Ported from src/produce.c lines 281-286.
+func (e *Engine) v_mine_gold(c *command) int {
+	return e.start_generic_mine(c, item_gold)
+}
+
+// d_mine_gold finishes mining gold.
+// Ported from src/produce.c lines 289-294.
+func (e *Engine) d_mine_gold(c *command) int {
+	return e.finish_generic_mine(c, item_gold)
+}
+
+// v_mine_mithril starts mining mithril.
+// Ported from src/produce.c lines 297-302.
+func (e *Engine) v_mine_mithril(c *command) int {
+	return e.start_generic_mine(c, item_mithril)
+}
+
+// d_mine_mithril finishes mining mithril.
+// Ported from src/produce.c lines 305-310.
+func (e *Engine) d_mine_mithril(c *command) int {
+	return e.finish_generic_mine(c, item_mithril)
+}
+
+// harvest describes a good that can be collected from a location.
+type harvest struct {
+	item      int
+	vis_item  int // replace item with this when generated
+	mult      int // multiply vis_item by this when gen'ing
+	skill     int
+	worker    int
+	chance    int // chance to get one each day, if nonzero
+	got_em    string
+	none_now  string
+	none_ever string
+	task_desc string
+	public    bool // 3rd party view, yes/no
+}
+
+// harv_tbl lists what COLLECT can gather and how.
+var harv_tbl = []harvest{
+	{item_peasant, 0, 0, 0, 0, 0,
+		"recruited",
+		"There are no more peasants here to recruit.",
+		"Peasants must be recruited in provinces.",
+		"recruit peasants", true},
+	{item_corpse, 0, 0, sk_raise_corpses, 0, 0,
+		"raised",
+		"There are no more corpses here to raise.",
+		"Corpses are found in graveyards.",
+		"raise corpses", false},
+	{item_mallorn_wood, 0, 0, sk_harvest_mallorn, 0, 20,
+		"cut",
+		"All mallorn wood ready this month has been cut here.",
+		"Mallorn wood is found only in mallorn groves.",
+		"cut mallorn wood", true},
+	{item_opium, 0, 0, sk_harvest_opium, 0, 0,
+		"harvested",
+		"All opium  ready this month has been harvested.",
+		"Opium is harvested only in poppy fields.",
+		"harvest opium", true},
+	{item_stone, 0, 0, sk_quarry_stone, item_worker, 0,
+		"quarried",
+		"No further stone may be quarried here this month.",
+		"Stone must be quarried in mountain provinces.",
+		"quarry stone", true},
+	{item_fish, 0, 0, sk_fishing, item_sailor, 50,
+		"caught",
+		"No further fish may be caught here this month.",
+		"Fish must be caught in ocean provinces.",
+		"catch fish", true},
+	{item_lumber, 0, 0, sk_harvest_lumber, item_worker, 0,
+		"cut",
+		"All ready timber has already been cut this month.",
+		"Wood must be cut in forest provinces.",
+		"cut timber", true},
+	{item_yew, 0, 0, sk_harvest_yew, item_worker, 0,
+		"cut",
+		"All yew available this month has already been cut.",
+		"Yew must be cut in yew groves",
+		"cut yew", true},
+	{item_wild_horse, 0, 0, sk_catch_horse, 0, 50,
+		"caught",
+		"No wild horses can be found roaming here now.",
+		"Wild horses are found on the plains and in pastures.",
+		"catch horses", true},
+	{item_avinia_leaf, 0, 0, sk_collect_foliage, 0, 20,
+		"collected",
+		"All of the avinia leaves here have been collected.",
+		"Avinia leaves are found in mallorn groves.",
+		"collect avinia leaves", true},
+	{item_spiny_root, 0, 0, sk_collect_foliage, 0, 25,
+		"collected",
+		"All of the spiny roots here have been collected.",
+		"Avinia leaves are found in swamps, pits and bogs.",
+		"collect spiny roots", true},
+	{item_lana_bark, 0, 0, sk_collect_foliage, 0, 50,
+		"collected",
+		"All of the lana bark here has been collected.",
+		"Lana bark is found in circles of trees.",
+		"collect lana bark", true},
+	{item_farrenstone, 0, 0, sk_collect_elem, 0, 100,
+		"collected",
+		"This cave's supply of farrenstone for this month has been exhausted.",
+		"Farrenstone is found in caves.",
+		"collect farrenstone", true},
+	{item_pretus_bones, 0, 0, sk_collect_elem, 0, 100,
+		"collected",
+		"No pretus bones can be found.",
+		"Pretus bones are found in sand pits.",
+		"collect pretus bones", true},
+	{item_mage_menial, item_gold, 10, sk_mage_menial, 0, 100,
+		"earned",
+		"No work at common magic can be found here.",
+		"No work at common magic can be found here.",
+		"work at common magic", true},
+}
+
+// find_harv returns the harvest entry for item k, or nil.
+// Ported from src/produce.c lines 507-517.
+func find_harv(k int) *harvest {
+	for i := range harv_tbl {
+		if harv_tbl[i].item == k {
+			return &harv_tbl[i]
+		}
+	}
+	return nil
+}
+
+// initCollectList records the units already running COLLECT at the
+// start of the turn.
+// Ported from src/produce.c lines 523-541.
+func (e *Engine) initCollectList() {
+	cmdCollect, _ := e.find_command("collect")
+	if cmdCollect <= 0 {
+		panic("initCollectList: no collect command")
+	}
+
+	e.globals.collectors = nil
+	for i := e.kind_first(T_char); i != 0; i = e.kind_next(i) {
+		c := e.rp_command(i)
+		if c != nil && c.state == STATE_RUN && c.cmd == cmdCollect {
+			IListAppend(&e.globals.collectors, i)
+		}
+	}
+}
+
+// harvest_where returns the location who harvests t from. Fishing
+// from a ship takes from the ocean around it.
+func (e *Engine) harvest_where(who int, t *harvest) int {
+	where := e.subloc(who)
+	if t.item == item_fish && e.is_ship(where) {
+		where = e.loc(where)
+	}
+	return where
+}
+
+// bump_other_collectors interrupts everyone else collecting t at
+// where, since nothing is left for them.
+// Ported from src/produce.c lines 544-574.
+func (e *Engine) bump_other_collectors(where int, t *harvest) {
+	for _, who := range IListCopy(e.globals.collectors) {
+		c := e.rp_command(who)
+		if c == nil || c.a != t.item {
+			continue
+		}
+
+		if e.harvest_where(c.who, t) != where {
+			continue
+		}
+
+		e.interrupt_order(c.who)
+	}
+}
+
+// v_generic_harvest starts collecting number of t's item over days
+// days. A number of zero means all possible; days below one means as
+// long as it takes.
+// Ported from src/produce.c lines 577-621.
+func (e *Engine) v_generic_harvest(c *command, number, days int, t *harvest) int {
+	where := e.harvest_where(c.who, t)
+
+	if t.skill != 0 && !e.has_skill(c.who, t.skill) {
+		wout(c.who, "Requires %s.", e.box_name(t.skill))
+		return FALSE
+	}
+
+	if days < 1 {
+		days = -1 // as long as it takes to get number
+	}
+
+	c.c = number // number desired; 0 means all possible
+	c.d = 0      // number we have obtained so far
+
+	if e.has_item(where, t.item) <= 0 {
+		return e.i_generic_harvest(c, t)
+	}
+
+	if t.worker != 0 && e.has_item(c.who, t.worker) < 1 {
+		wout(c.who, "Need at least one %s to %s.", e.box_name(t.worker), t.task_desc)
+		return FALSE
+	}
+
+	IListAppend(&e.globals.collectors, c.who)
+
+	c.wait = days
+	return TRUE
+}
+
+// d_generic_harvest collects one day's worth of t: one per worker
+// for worker-driven harvests, otherwise one, subject to t.chance.
+// Ported from src/produce.c lines 624-695.
+func (e *Engine) d_generic_harvest(c *command, t *harvest) int {
+	where := e.harvest_where(c.who, t)
+	number := c.c
+
+	qty := e.has_item(where, t.item)
+
+	if t.worker != 0 {
+		qty = min(qty, e.has_item(c.who, t.worker))
+
+		if number > 0 && c.d+qty > number {
+			qty = number - c.d
+		}
+	} else {
+		qty = min(qty, 1)
+	}
+
+	if qty > 0 {
+		if t.chance != 0 && e.rndFrom(streamSkills, 1, 100) > t.chance {
+			if c.wait == 0 {
+				return e.i_generic_harvest(c, t)
+			}
+			return TRUE
+		}
+
+		if t.vis_item != 0 {
+			e.consume_item(where, t.item, qty)
+			e.gen_item(c.who, t.vis_item, qty*t.mult)
+			c.d += qty * t.mult
+		} else {
+			e.move_item(where, c.who, t.item, qty)
+			c.d += qty
+		}
+
+		// There's no point spending an extra day to find out that the
+		// resource is depleted, so stop now, and bump out anyone else
+		// collecting here for the same reason.
+		if e.has_item(where, t.item) == 0 {
+			ret := e.i_generic_harvest(c, t)
+			e.bump_other_collectors(where, t)
+			return ret
+		}
+
+		if c.wait != 0 && !(number > 0 && c.d >= number) {
+			return TRUE // not done yet
+		}
+	}
+
+	return e.i_generic_harvest(c, t)
+}
+
+// mage_menial_how describes how a mage earned money at common magic.
+// Ported from src/produce.c lines 698-719.
+func (e *Engine) mage_menial_how() string {
+	switch e.rndFrom(streamSkills, 1, 9) {
+	case 1:
+		return " curing runny noses"
+	case 2:
+		return " dowsing for water"
+	case 3:
+		return " selling love potions"
+	case 4:
+		return " selling good luck charms"
+	case 5:
+		return " predicting the future"
+	case 6:
+		return " reading palms"
+	}
+	return ""
+}
+
+// i_generic_harvest reports what was collected and ends the harvest.
+// Ported from src/produce.c lines 722-777.
+func (e *Engine) i_generic_harvest(c *command, t *harvest) int {
+	where := e.harvest_where(c.who, t)
+
+	if c.d == 0 {
+		if item_gen_here(e.subkind(where), t.item) {
+			out(c.who, "%s", t.none_now)
+		} else {
+			out(c.who, "%s", t.none_ever)
+		}
+	} else {
+		item := t.item
+		if t.vis_item != 0 {
+			item = t.vis_item
+		}
+
+		if t.item == item_mage_menial {
+			wout(c.who, "Earned %s%s.", gold_s(c.d), e.mage_menial_how())
+		} else {
+			out(c.who, "%s %s.", cap(t.got_em), e.just_name_qty(item, c.d))
+		}
+
+		if t.public {
+			e.globals.show_to_garrison = true
+
+			if t.item == item_mage_menial {
+				wout(where, "%s earned %s working at common magic.", e.box_name(c.who), gold_s(c.d))
+			} else {
+				out(where, "%s %s %s.", e.box_name(c.who), t.got_em, e.just_name_qty(item, c.d))
+			}
+
+			e.globals.show_to_garrison = false
+		}
+
+		if t.skill != 0 {
+			e.add_skill_experience(c.who, t.skill)
+		}
+	}
+
+	IListRemValue(&e.globals.collectors, c.who)
+
+	c.wait = 0
+	if c.d > 0 && c.d >= c.c {
+		return TRUE
+	}
+	return FALSE
+}
+
+// v_collect starts the COLLECT order.
+// Ported from src/produce.c lines 780-798.
+func (e *Engine) v_collect(c *command) int {
+	item, number, days := c.a, c.b, c.c
+
+	t := find_harv(item)
+	if t == nil {
+		wout(c.who, "Don't know how to collect %s.", e.box_code(item))
+		return FALSE
+	}
+
+	return e.v_generic_harvest(c, number, days, t)
+}
+
+// d_collect is the daily poll routine for the COLLECT order.
+// Ported from src/produce.c lines 801-817.
+func (e *Engine) d_collect(c *command) int {
+	t := find_harv(c.a)
+	if t == nil {
+		out(c.who, "Internal error.")
+		log_write(LOG_CODE, "d_collect: t is NULL, who=%d", c.who)
+		return FALSE
+	}
+
+	return e.d_generic_harvest(c, t)
+}
+
+// i_collect is the interrupt routine for the COLLECT order.
+// Ported from src/produce.c lines 820-836.
+func (e *Engine) i_collect(c *command) int {
+	t := find_harv(c.a)
+	if t == nil {
+		out(c.who, "Internal error.")
+		log_write(LOG_CODE, "i_collect: t is NULL, who=%d", c.who)
+		return FALSE
+	}
+
+	return e.i_generic_harvest(c, t)
+}
+
+// collect_as rewrites c into a COLLECT order for item and starts it.
+// The harvesting orders and skills below all work this way.
+func (e *Engine) collect_as(c *command, line string) int {
+	if !e.oly_parse(c, line) {
+		panic("collect_as: cannot parse " + line)
+	}
+	return e.v_collect(c)
+}
+
+// v_quarry quarries stone.
+// Ported from src/produce.c lines 839-848.
+func (e *Engine) v_quarry(c *command) int {
+	return e.collect_as(c, fmt.Sprintf("collect %d %d %d", item_stone, c.a, c.b))
+}
+
+// v_recruit recruits peasants.
+// Ported from src/produce.c lines 851-860.
+func (e *Engine) v_recruit(c *command) int {
+	return e.collect_as(c, fmt.Sprintf("collect %d %d %d", item_peasant, c.a, c.b))
+}
+
+// v_raise_corpses raises corpses from a graveyard.
+// Ported from src/produce.c lines 863-872.
+func (e *Engine) v_raise_corpses(c *command) int {
+	return e.collect_as(c, fmt.Sprintf("collect %d %d %d", item_corpse, c.a, c.b))
+}
+
+// v_fish catches fish.
+// Ported from src/produce.c lines 875-884.
+func (e *Engine) v_fish(c *command) int {
+	return e.collect_as(c, fmt.Sprintf("collect %d %d %d", item_fish, c.a, c.b))
+}
+
+// v_wood cuts timber.
+// Ported from src/produce.c lines 887-896.
+func (e *Engine) v_wood(c *command) int {
+	return e.collect_as(c, fmt.Sprintf("collect %d %d %d", item_lumber, c.a, c.b))
+}
+
+// v_opium harvests opium.
+// Ported from src/produce.c lines 899-908.
+func (e *Engine) v_opium(c *command) int {
+	return e.collect_as(c, fmt.Sprintf("collect %d %d %d", item_opium, c.a, c.b))
+}
+
+// v_mallorn cuts mallorn wood.
+// Ported from src/produce.c lines 911-921.
+func (e *Engine) v_mallorn(c *command) int {
+	return e.collect_as(c, fmt.Sprintf("collect %d %d %d", item_mallorn_wood, c.a, c.b))
+}
+
+// v_yew cuts yew.
+// Ported from src/produce.c lines 924-933.
+func (e *Engine) v_yew(c *command) int {
+	return e.collect_as(c, fmt.Sprintf("collect %d %d %d", item_yew, c.a, c.b))
+}
+
+// v_catch catches wild horses.
+// Ported from src/produce.c lines 936-946.
+func (e *Engine) v_catch(c *command) int {
+	return e.collect_as(c, fmt.Sprintf("collect %d %d %d", item_wild_horse, c.a, c.b))
+}
+
+// v_mage_menial earns gold working at common magic for c.a days.
+// Ported from src/produce.c lines 949-959.
+func (e *Engine) v_mage_menial(c *command) int {
+	return e.collect_as(c, fmt.Sprintf("collect %d 0 %d", item_mage_menial, c.a))
+}
@@ -0,0 +1,140 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// produce_test.go - Tests for mining and harvesting
+
+package taygete
+
+import "testing"
+
+// setupProduceTest builds a player with one noble standing in a
+// location of the given subkind.
+func setupProduceTest(t *testing.T, terr schar) (pl, who, where int) {
+	t.Helper()
+	pl, who = setupUseTest(t)
+
+	for _, item := range []int{item_worker, item_iron, item_stone, item_peasant, item_gate_crystal} {
+		teg.alloc_box(item, T_item, 0)
+	}
+
+	where = 10_101
+	teg.alloc_box(where, T_loc, terr)
+	teg.set_where(who, where)
+	return pl, who, where
+}
+
+func TestMineYieldsOre(t *testing.T) {
+	_, who, mine := setupProduceTest(t, sub_mine)
+	teg.gen_item(mine, item_iron, 15)
+
+	c := &command{who: who, wait: 7}
+	if got := teg.v_mine_iron(c); got != FALSE {
+		t.Fatalf("v_mine_iron without workers = %d, want FALSE", got)
+	}
+
+	teg.gen_item(who, item_worker, 10)
+	if got := teg.v_mine_iron(c); got != TRUE {
+		t.Fatalf("v_mine_iron = %d, want TRUE", got)
+	}
+	if got := teg.d_mine_iron(c); got != TRUE {
+		t.Fatalf("d_mine_iron = %d, want TRUE", got)
+	}
+	if got := teg.has_item(who, item_iron); got != 15 {
+		t.Errorf("iron mined = %d, want 15", got)
+	}
+	if got := teg.rp_subloc(mine).shaft_depth; got != 1 {
+		t.Errorf("shaft depth = %d, want 1", got)
+	}
+
+	// the mine is empty until production replenishes it
+	if got := teg.d_mine_iron(c); got != FALSE {
+		t.Errorf("d_mine_iron on an empty mine = %d, want FALSE", got)
+	}
+}
+
+func TestQuarryCollectsStone(t *testing.T) {
+	pl, who, mountain := setupProduceTest(t, sub_mountain)
+	teg.alloc_box(sk_quarry_stone, T_skill, 0)
+	teg.p_skill_ent(who, sk_quarry_stone).know = SKILL_know
+	teg.gen_item(mountain, item_stone, 5)
+	teg.gen_item(who, item_worker, 3)
+
+	teg.queue_order(pl, who, "quarry")
+	teg.initialCommandLoad()
+	teg.initCollectList()
+
+	c := teg.rp_command(who)
+	teg.dailyCommandLoop()
+	if cmd_tbl[c.cmd].name != "collect" {
+		t.Fatalf("cmd = %q, want %q", cmd_tbl[c.cmd].name, "collect")
+	}
+	if got := teg.has_item(who, item_stone); got != 3 {
+		t.Errorf("stone after one day = %d, want 3 (one per worker)", got)
+	}
+
+	teg.dailyCommandLoop()
+	if c.state == STATE_RUN {
+		t.Fatalf("quarry still running after the stone ran out")
+	}
+	if got := teg.has_item(who, item_stone); got != 5 {
+		t.Errorf("stone quarried = %d, want 5", got)
+	}
+	if got := teg.rp_skill_ent(who, sk_quarry_stone).experience; got != 1 {
+		t.Errorf("quarry experience = %d, want 1", got)
+	}
+	if len(teg.globals.collectors) != 0 {
+		t.Errorf("collectors = %v, want none", teg.globals.collectors)
+	}
+}
+
+func TestCollectDepletionBumpsOthers(t *testing.T) {
+	_, who, forest := setupProduceTest(t, sub_forest)
+	other := 1002
+	teg.alloc_box(other, T_char, 0)
+	teg.set_where(other, forest)
+	teg.gen_item(forest, item_peasant, 2)
+	teg.globals.collectors = nil
+
+	start := func(who int) *command {
+		c := teg.p_command(who)
+		teg.oly_parse(c, "recruit")
+		c.who = who
+		c.state = STATE_RUN
+		if got := teg.v_recruit(c); got != TRUE {
+			t.Fatalf("v_recruit(%d) = %d, want TRUE", who, got)
+		}
+		return c
+	}
+	c1, c2 := start(who), start(other)
+
+	teg.d_collect(c1)
+	if got := teg.d_collect(c1); got != TRUE {
+		t.Errorf("d_collect = %d, want TRUE once the peasants ran out", got)
+	}
+	if got := teg.has_item(who, item_peasant); got != 2 {
+		t.Errorf("peasants recruited = %d, want 2", got)
+	}
+	if c2.state == STATE_RUN {
+		t.Error("other recruiter was not interrupted when the peasants ran out")
+	}
+	if len(teg.globals.collectors) != 0 {
+		t.Errorf("collectors = %v, want none", teg.globals.collectors)
+	}
+
+	if !item_gen_here(sub_forest, item_peasant) || item_gen_here(sub_ocean, item_peasant) {
+		t.Error("item_gen_here: peasants come from forests, not oceans")
+	}
+}
@@ -18,6 +18,6 @@ package taygete
 
 // read_pw loads the password file, searches for the key.
 // returns the current password for the key, if found, or an error.
-func read_pw(key string) (string, error) {
-	return teg.readPassword(key)
+func (e *Engine) read_pw(key string) (string, error) {
+	return e.readPassword(key)
 }
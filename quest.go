@@ -0,0 +1,538 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// quest.go - Quests, relics and subloc monsters ported from src/quest.c
+
+package taygete
+
+// create_nowhere makes the hidden region and location which hold
+// the relics no monster is guarding.
+// Ported from src/quest.c lines 13-27.
+func (e *Engine) create_nowhere() {
+	reg := e.new_ent(T_loc, sub_region)
+	e.set_name(reg, "Nowhere")
+
+	where := e.new_ent(T_loc, sub_under)
+	e.set_name(where, "Nowhere")
+	e.set_where(where, reg)
+
+	e.globals.nowhereRegion = reg
+	e.globals.nowhereLoc = where
+
+	log_write(LOG_SPECIAL, "INIT: created %s, %s",
+		e.box_name(reg), e.box_name(where))
+}
+
+// create_a_relic makes relic n in Nowhere unless it already exists.
+// Returns true if the relic was created.
+// Ported from src/quest.c lines 30-45.
+func (e *Engine) create_a_relic(n int, name string, use, weight int) bool {
+	if e.globals.bx.get(n) != nil {
+		return false
+	}
+
+	e.create_unique_item_alloc(n, e.globals.nowhereLoc, sub_relic)
+
+	e.set_name(n, name)
+	e.p_item_magic(n).use_key = schar(use)
+	e.p_item_magic(n).lore = n
+	e.p_item(n).weight = short(weight)
+
+	log_write(LOG_SPECIAL, "Created %s", e.box_name(n))
+	return true
+}
+
+// create_relics makes sure every relic exists somewhere in the world.
+// Returns the relics which had to be created.
+// Ported from src/quest.c lines 48-62.
+func (e *Engine) create_relics() []int {
+	var l []int
+	if e.create_a_relic(RELIC_THRONE, "Imperial Throne", 0, 500) {
+		l = append(l, RELIC_THRONE)
+	}
+	if e.create_a_relic(RELIC_CROWN, "Crown of Prosperity", 0, 10) {
+		l = append(l, RELIC_CROWN)
+	}
+	if e.create_a_relic(RELIC_BTA_SKULL, "Skull of Bastrestric",
+		use_bta_skull, 10) {
+		l = append(l, RELIC_BTA_SKULL)
+	}
+	return l
+}
+
+// random_unassigned_relic picks one of the relics waiting in Nowhere.
+// Returns 0 if every relic is held by someone.
+// Ported from src/quest.c lines 65-102.
+func (e *Engine) random_unassigned_relic() int {
+	var l []int
+
+	for _, it := range e.globals.inventories[e.globals.nowhereLoc] {
+		if e.item_unique(it.item) == 0 || e.subkind(it.item) != sub_relic {
+			continue
+		}
+		l = append(l, it.item)
+	}
+
+	if len(l) == 0 {
+		return 0
+	}
+
+	return l[e.rndFrom(streamSeeding, 0, len(l)-1)]
+}
+
+var art_att_s = []string{"sword", "dagger", "longsword"}
+var art_def_s = []string{"helm", "shield", "armor"}
+var art_mis_s = []string{"spear", "bow", "javelin", "dart"}
+var art_mag_s = []string{"ring", "staff", "amulet"}
+
+var art_pref = []string{"magic", "golden", "crystal", "enchanted", "elven"}
+
+var art_of_names = []string{
+	"Achilles", "Darkness", "Justice", "Truth", "Norbus", "Dirbrand",
+	"Pyrellica", "Halhere", "Eadak", "Faelgrar", "Napich", "Renfast",
+	"Ociera", "Shavnor", "Dezarne", "Roshun", "Areth Lorbin", "Anarth",
+	"Vernolt", "Pentara", "Gravecarn", "Sardis", "Lethrys", "Habyn",
+	"Obraed", "Beebas", "Bayarth", "Haim", "Balatea", "Bobbiek", "Moldarth",
+	"Grindor", "Sallen", "Ferenth", "Rhonius", "Ragnar", "Pallia", "Kior",
+	"Baraxes", "Coinbalth", "Raskold", "Lassan", "Haemfrith", "Earnberict",
+	"Sorale", "Lorbin", "Osgea", "Fornil", "Kuneack", "Davchar", "Urvil",
+	"Pantarastar", "Cyllenedos", "Echaliatic", "Iniera", "Norgar", "Broen",
+	"Estbeorn", "Claunecar", "Salamus", "Rhovanth", "Illinod", "Pictar",
+	"Elakain", "Antresk", "Kichea", "Raigor", "Pactra", "Aethelarn",
+	"Descarq", "Plagcath", "Nuncarth", "Petelinus", "Cospera", "Sarindor",
+	"Albrand", "Evinob", "Dafarik", "Haemin", "Resh", "Tarvik", "Odasgunn",
+	"Areth Pirn", "Miranth", "Dorenth", "Arkaune", "Kircarth", "Perendor",
+	"Syssale", "Aelbarik", "Drassa", "Pirn", "Maire", "Lebrus", "Birdan",
+	"Fistrock", "Shotluth", "Aldain", "Nantasarn", "Carim", "Ollayos",
+	"Hamish", "Sudabuk", "Belgarth", "Woodhead",
+}
+
+// make_teach_book gives who an ancient scroll teaching a researchable
+// subskill the questor doesn't know, preferably in a school they know.
+// Ported from src/quest.c lines 140-206.
+func (e *Engine) make_teach_book(who, questor int) {
+	var candidate []int  // categories we know
+	var candidate2 []int // categories we don't know
+
+	for _, sk := range e.Skills() {
+		if e.skill_school(sk) != sk {
+			continue
+		}
+
+		q := e.rp_skill(sk)
+		if q == nil {
+			continue
+		}
+
+		if e.has_skill(questor, sk) {
+			for _, r := range q.research {
+				if e.has_skill(questor, r) {
+					continue
+				}
+				candidate = append(candidate, r)
+			}
+		} else if sk != sk_adv_sorcery {
+			candidate2 = append(candidate2, sk)
+		}
+	}
+
+	var sk int
+	if len(candidate) > 0 {
+		sk = candidate[e.rndFrom(streamSeeding, 0, len(candidate)-1)]
+	} else if len(candidate2) > 0 {
+		sk = candidate2[e.rndFrom(streamSeeding, 0, len(candidate2)-1)]
+	} else {
+		log_write(LOG_CODE, "?? %s knows all skills?", e.box_code(questor))
+		return
+	}
+
+	newItem := e.create_unique_item(who, sub_scroll)
+	if newItem < 0 {
+		return
+	}
+	e.set_name(newItem, "ancient scroll")
+	e.p_item(newItem).weight = 5
+	e.p_item_magic(newItem).may_study.Append(sk)
+}
+
+// new_artifact gives who a randomly named weapon, armor, missile or
+// aura artifact.
+// Ported from src/quest.c lines 258-313.
+func (e *Engine) new_artifact(who int) int {
+	newItem := e.create_unique_item(who, sub_artifact)
+	if newItem < 0 {
+		return 0
+	}
+
+	var s string
+	switch e.rndFrom(streamSeeding, 1, 4) {
+	case 1:
+		s = art_att_s[e.rndFrom(streamSeeding, 0, 2)]
+		e.p_item_magic(newItem).attack_bonus = schar(e.rndFrom(streamSeeding, 1, 10) * 5)
+	case 2:
+		s = art_def_s[e.rndFrom(streamSeeding, 0, 2)]
+		e.p_item_magic(newItem).defense_bonus = schar(e.rndFrom(streamSeeding, 1, 10) * 5)
+	case 3:
+		s = art_mis_s[e.rndFrom(streamSeeding, 0, 3)]
+		e.p_item_magic(newItem).missile_bonus = schar(e.rndFrom(streamSeeding, 1, 10) * 5)
+	case 4:
+		s = art_mag_s[e.rndFrom(streamSeeding, 0, 2)]
+		e.p_item_magic(newItem).aura_bonus = short(e.rndFrom(streamSeeding, 1, 3))
+	}
+
+	if e.rndFrom(streamSeeding, 1, 3) < 3 {
+		s = sout("%s %s", art_pref[e.rndFrom(streamSeeding, 0, 4)], s)
+	} else {
+		s = sout("%s of %s", cap(s), art_of_names[e.rndFrom(streamSeeding, 0, len(art_of_names)-1)])
+	}
+
+	e.p_item(newItem).weight = 10
+	e.set_name(newItem, s)
+
+	return newItem
+}
+
+// quest_monster lists the beasts which guard each kind of subloc.
+// level is the minimum tunnel depth for sub_chamber monsters.
+type quest_monster struct {
+	terr      int
+	item      int
+	low, high int
+	level     int
+}
+
+var quest_monsters = []quest_monster{
+	{sub_island, item_pirate, 5, 30, 0},
+	{sub_island, item_cyclops, 1, 5, 0},
+
+	{sub_cave, item_rat, 10, 50, 0},
+	{sub_cave, item_wolf, 3, 10, 0},
+	{sub_cave, item_ratspider, 5, 20, 0},
+	{sub_cave, item_gorgon, 3, 5, 0},
+	{sub_cave, item_orc, 5, 20, 0},
+
+	{sub_ruins, item_bandit, 2, 10, 0},
+	{sub_ruins, item_cyclops, 2, 5, 0},
+	{sub_ruins, item_minotaur, 3, 10, 0},
+	{sub_ruins, item_centaur, 3, 10, 0},
+	{sub_ruins, item_lizard, 1, 3, 0},
+
+	{sub_battlefield, item_skeleton, 10, 100, 0},
+	{sub_battlefield, item_spirit, 5, 50, 0},
+	{sub_battlefield, item_giant, 3, 10, 0},
+	{sub_battlefield, item_nazgul, 5, 20, 0},
+
+	{sub_graveyard, item_corpse, 10, 100, 0},
+	{sub_graveyard, item_harpie, 3, 10, 0},
+	{sub_graveyard, item_spider, 3, 10, 0},
+	{sub_graveyard, item_bird, 1, 3, 0},
+
+	{sub_lair, item_lion, 3, 8, 0},
+	{sub_lair, item_chimera, 2, 10, 0},
+	{sub_lair, item_dragon, 1, 1, 0},
+
+	{sub_ench_forest, item_faery, 5, 20, 0},
+	{sub_ench_forest, item_elf, 5, 20, 0},
+
+	{sub_faery_hill, item_faery, 5, 20, 0},
+	{sub_faery_hill, item_elf, 5, 20, 0},
+
+	{sub_pits, item_rat, 5, 25, 0},
+	{sub_pits, item_gorgon, 3, 7, 0},
+	{sub_pits, item_cyclops, 2, 3, 0},
+	{sub_pits, item_minotaur, 1, 5, 0},
+
+	{sub_bog, item_rat, 5, 25, 0},
+	{sub_bog, item_gorgon, 3, 7, 0},
+	{sub_bog, item_cyclops, 2, 3, 0},
+	{sub_bog, item_minotaur, 1, 5, 0},
+
+	{sub_sand_pit, item_lizard, 15, 30, 0},
+
+	{sub_stone_cir, item_skeleton, 3, 15, 0},
+	{sub_stone_cir, item_gorgon, 3, 15, 0},
+	{sub_stone_cir, item_orc, 3, 15, 0},
+	{sub_stone_cir, item_cyclops, 3, 15, 0},
+	{sub_stone_cir, item_minotaur, 3, 15, 0},
+	{sub_stone_cir, item_centaur, 3, 15, 0},
+	{sub_stone_cir, item_spirit, 3, 15, 0},
+	{sub_stone_cir, item_nazgul, 3, 15, 0},
+	{sub_stone_cir, item_harpie, 3, 15, 0},
+	{sub_stone_cir, item_chimera, 3, 15, 0},
+	{sub_stone_cir, item_nazgul, 3, 15, 0},
+
+	{sub_chamber, item_rat, 10, 50, 0},
+	{sub_chamber, item_ratspider, 5, 20, 0},
+	{sub_chamber, item_gorgon, 3, 6, 0},
+	{sub_chamber, item_orc, 5, 20, 0},
+
+	{sub_chamber, item_cyclops, 5, 10, 4},
+	{sub_chamber, item_minotaur, 5, 15, 4},
+	{sub_chamber, item_spider, 5, 15, 4},
+	{sub_chamber, item_lizard, 5, 15, 4},
+}
+
+// has_quest_monster returns true if some monster guards sublocs of
+// this terrain.
+func has_quest_monster(terr int) bool {
+	for _, q := range quest_monsters {
+		if q.terr == terr {
+			return true
+		}
+	}
+	return false
+}
+
+// choose_quest_monster picks an entry from quest_monsters suited to
+// where. Returns -1 if nothing lives there.
+// Ported from src/quest.c lines 395-427.
+func (e *Engine) choose_quest_monster(where int) int {
+	terr := int(e.subkind(where))
+	level := int(e.tunnel_depth(where))
+
+	var l []int
+	for i, q := range quest_monsters {
+		if q.terr == terr && level >= q.level {
+			l = append(l, i)
+		}
+	}
+
+	if len(l) == 0 {
+		return -1
+	}
+
+	return l[e.rndFrom(streamSeeding, 0, len(l)-1)]
+}
+
+// new_monster creates an npc stack of beasts guarding where.
+// Ported from src/quest.c lines 430-452.
+func (e *Engine) new_monster(where int) int {
+	i := e.choose_quest_monster(where)
+	if i < 0 {
+		return 0
+	}
+	q := quest_monsters[i]
+
+	newChar := e.new_char(sub_ni, q.item, where, -1, npc_pl, LOY_npc, 0, "")
+	if newChar <= 0 {
+		return 0
+	}
+
+	e.gen_item(newChar, q.item, e.rndFrom(streamSeeding, q.low-1, q.high-1))
+
+	e.p_char(newChar).npc_prog = PROG_subloc_monster
+
+	return newChar
+}
+
+// make_subloc_monster creates a monster in where and gives it a
+// treasure: a relic, gold, an artifact, an elfstone, an npc token,
+// a teaching scroll, an orb or pegasi.
+// Ported from src/quest.c lines 455-581.
+func (e *Engine) make_subloc_monster(where, questor int) int {
+	monster := e.new_monster(where)
+	if monster == 0 {
+		return 0
+	}
+
+	relic := e.random_unassigned_relic()
+
+	low := 2
+	if relic != 0 {
+		low = 0
+	}
+
+	e.gen_item(monster, item_gold, e.rndFrom(streamSeeding, 100, 500))
+
+	switch e.rndFrom(streamSeeding, low, 18) {
+	case 0, 1:
+		e.move_item(e.globals.nowhereLoc, monster, relic, 1)
+
+		switch relic {
+		case RELIC_CROWN:
+			e.p_item_magic(relic).relic_decay = short(e.rndFrom(streamSeeding, 8, 16) + 1)
+		case RELIC_BTA_SKULL:
+			e.p_item_magic(relic).relic_decay = short(e.rndFrom(streamSeeding, 10, 20) + 1)
+		}
+
+	case 2, 3, 4, 5, 6:
+		e.gen_item(monster, item_gold, e.rndFrom(streamSeeding, 100, 3000))
+
+	case 7, 8:
+		e.new_artifact(monster)
+
+	case 9, 10:
+		e.gen_item(monster, item_elfstone, 1)
+
+	case 11:
+		if e.rndFrom(streamSeeding, 0, 1) == 0 {
+			e.create_npc_token(monster)
+		}
+
+	case 12, 13:
+		e.make_teach_book(monster, questor)
+
+	case 14, 15:
+		e.new_orb(monster)
+
+	case 16, 17:
+		e.gen_item(monster, item_pegasus, e.rndFrom(streamSeeding, 1, 6))
+
+	case 18: // no treasure
+	}
+
+	return monster
+}
+
+// subloc_monster_hoard leaves the unique treasure of a slain subloc
+// monster in its lair, where explorers may turn it up, instead of
+// scattering it into the province. Called by kill_char; does nothing
+// when a stackmate or a named unit inherits the goods.
+func (e *Engine) subloc_monster_hoard(who, inherit int) {
+	if e.npc_program(who) != PROG_subloc_monster {
+		return
+	}
+
+	switch inherit {
+	case 0:
+	case MATES, MATES_SILENT:
+		if e.stackmate_inheritor(who) != 0 {
+			return
+		}
+	default:
+		return
+	}
+
+	where := e.subloc(who)
+	if where == 0 || e.loc_depth(where) < LOC_subloc {
+		return
+	}
+
+	var l []int
+	for _, it := range e.globals.inventories[who] {
+		if e.item_unique(it.item) != 0 {
+			l = append(l, it.item)
+		}
+	}
+
+	for _, item := range l {
+		e.move_item(who, where, item, 1)
+	}
+}
+
+// quest_check makes the checks shared by v_quest and d_quest.
+func (e *Engine) quest_check(c *command) bool {
+	if e.in_safe_now(c.who) {
+		wout(c.who, "Questing is not permitted in safe havens.")
+		return false
+	}
+
+	if e.stack_leader(c.who) != c.who {
+		wout(c.who, "Only the stack leader may initiate quests.")
+		return false
+	}
+
+	if !has_quest_monster(int(e.subkind(e.subloc(c.who)))) {
+		wout(c.who, "There are no quests to be found here.")
+		return false
+	}
+
+	return true
+}
+
+// v_quest is the start routine for the QUEST command.
+// Ported from src/quest.c lines 584-620.
+func (e *Engine) v_quest(c *command) int {
+	if !e.quest_check(c) {
+		return FALSE
+	}
+
+	if e.subloc_quest(e.subloc(c.who)) != 0 {
+		wout(c.who, "This area has been quested in recently, "+
+			"nothing of interest is found.")
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_quest searches the subloc and, half the time, turns up a monster
+// guarding a treasure. The area then can't be quested again for four
+// to twelve turns.
+//
+// The C game followed the discovery with an ATTACK order against the
+// monster. Combat is not ported yet, so the monster is left to be
+// dealt with; its treasure stays in the lair once it is slain.
+// Ported from src/quest.c lines 623-685.
+func (e *Engine) d_quest(c *command) int {
+	if !e.quest_check(c) {
+		return FALSE
+	}
+
+	where := e.subloc(c.who)
+
+	if e.subloc_quest(where) != 0 {
+		wout(c.who, "This area has been quested in recently, "+
+			"nothing of interest is found.")
+		return TRUE
+	}
+
+	if e.rndFrom(streamSeeding, 1, 100) <= 50 {
+		wout(c.who, "Nothing of interest was found.")
+		return TRUE
+	}
+
+	e.p_subloc(where).quest_late = schar(e.rndFrom(streamSeeding, 5, 13)) // no quests following 4-12 turns
+
+	monster := e.make_subloc_monster(where, c.who)
+	if monster == 0 {
+		wout(c.who, "Internal error.")
+		return TRUE
+	}
+
+	wout(c.who, "%s discovers %s", e.box_name(c.who), e.liner_desc(monster))
+
+	return TRUE
+}
+
+// v_use_bta_skull uses the Skull of Bastrestric. A magician usually
+// gains a burst of aura; anyone else is killed by it. Either way the
+// skull returns to Nowhere.
+// Ported from src/quest.c lines 688-713.
+func (e *Engine) v_use_bta_skull(c *command) int {
+	item := c.a
+
+	e.p_item_magic(item).relic_decay = 0
+	e.move_item(c.who, e.globals.nowhereLoc, item, 1)
+
+	if e.is_magician(c.who) == 0 || e.rndFrom(streamMagic, 1, 100) <= 25 {
+		wout(c.who, "The skull erupts with an intense blast of aura,"+
+			" killing %s!", e.just_name(c.who))
+		e.kill_char(c.who, MATES)
+	} else {
+		aura := e.rndFrom(streamMagic, 50, 75)
+
+		wout(c.who, "The skull radiates a burst of %s aura!", comma_num(aura))
+
+		e.p_magic(c.who).cur_aura += aura
+		e.limit_cur_aura(c.who)
+
+		wout(c.who, "Current aura is now %d.", e.rp_magic(c.who).cur_aura)
+		wout(c.who, "The skull has vanished.")
+	}
+
+	return TRUE
+}
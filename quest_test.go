@@ -0,0 +1,171 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// quest_test.go - Tests for quests, relics and subloc monsters
+
+package taygete
+
+import "testing"
+
+// setupQuestTest adds Nowhere, the relics and the npc player to the
+// necro test's world. The mage waits in the graveyard.
+func setupQuestTest(t *testing.T) (who, prov, grave int) {
+	t.Helper()
+	_, who, prov, grave = setupNecroTest(t)
+
+	teg.alloc_box(npc_pl, T_player, sub_pl_silent)
+	for _, item := range []int{item_gold, item_harpie, item_spider,
+		item_bird, item_elfstone, item_pegasus} {
+		teg.alloc_box(item, T_item, 0)
+	}
+	teg.create_nowhere()
+	teg.create_relics()
+
+	return who, prov, grave
+}
+
+func TestCreateRelics(t *testing.T) {
+	setupQuestTest(t)
+
+	for _, n := range []int{RELIC_THRONE, RELIC_CROWN, RELIC_BTA_SKULL} {
+		if teg.item_unique(n) != teg.globals.nowhereLoc {
+			t.Errorf("%s held by %d, want Nowhere", teg.box_name(n), teg.item_unique(n))
+		}
+	}
+	if teg.item_use_key(RELIC_BTA_SKULL) != use_bta_skull {
+		t.Error("skull has no use key")
+	}
+	if l := teg.create_relics(); len(l) != 0 {
+		t.Errorf("second create_relics made %v", l)
+	}
+}
+
+func TestQuestGraveyard(t *testing.T) {
+	who, _, grave := setupQuestTest(t)
+
+	c := &command{who: who}
+	if got := teg.v_quest(c); got != TRUE {
+		t.Fatalf("v_quest = %d, want TRUE", got)
+	}
+
+	for i := 0; i < 20 && teg.subloc_quest(grave) == 0; i++ {
+		teg.d_quest(c)
+	}
+	if teg.subloc_quest(grave) == 0 {
+		t.Fatal("quest never turned anything up")
+	}
+
+	var monster int
+	var l []int
+	teg.loop_char_here(grave, &l)
+	for _, i := range l {
+		if teg.npc_program(i) == PROG_subloc_monster {
+			monster = i
+		}
+	}
+	if monster == 0 {
+		t.Fatal("no subloc monster in the graveyard")
+	}
+	if teg.player(monster) != npc_pl {
+		t.Errorf("monster player = %d, want npc_pl", teg.player(monster))
+	}
+	if teg.has_item(monster, item_gold) < 100 {
+		t.Errorf("monster gold = %d, want at least 100", teg.has_item(monster, item_gold))
+	}
+
+	if got := teg.v_quest(c); got != FALSE {
+		t.Errorf("v_quest right after a quest = %d, want FALSE", got)
+	}
+
+	// the graveyard opens up again once the counter runs down
+	for i := 0; i < 13; i++ {
+		teg.questDecay()
+	}
+	if teg.subloc_quest(grave) != 0 {
+		t.Errorf("quest_late = %d after decay, want 0", teg.subloc_quest(grave))
+	}
+}
+
+func TestQuestNotHere(t *testing.T) {
+	who, prov, _ := setupQuestTest(t)
+	teg.set_where(who, prov)
+
+	c := &command{who: who}
+	if got := teg.v_quest(c); got != FALSE {
+		t.Errorf("v_quest on the plains = %d, want FALSE", got)
+	}
+}
+
+func TestRelicDecay(t *testing.T) {
+	who, _, _ := setupQuestTest(t)
+
+	teg.move_item(teg.globals.nowhereLoc, who, RELIC_CROWN, 1)
+	teg.p_item_magic(RELIC_CROWN).relic_decay = 2
+
+	teg.relicDecay()
+	if teg.item_unique(RELIC_CROWN) != who {
+		t.Fatal("crown vanished a month early")
+	}
+	teg.relicDecay()
+	if teg.item_unique(RELIC_CROWN) != teg.globals.nowhereLoc {
+		t.Error("crown did not return to Nowhere")
+	}
+}
+
+func TestSublocMonsterHoard(t *testing.T) {
+	who, _, grave := setupQuestTest(t)
+
+	monster := teg.new_monster(grave)
+	if monster == 0 {
+		t.Fatal("new_monster failed")
+	}
+	art := teg.new_artifact(monster)
+
+	teg.kill_char(monster, MATES)
+	if teg.item_unique(art) != grave {
+		t.Fatalf("artifact held by %d, want the graveyard", teg.item_unique(art))
+	}
+
+	if !teg.find_lost_items(who, grave) {
+		t.Fatal("explorer did not find the hoard")
+	}
+	if teg.item_unique(art) != who {
+		t.Errorf("artifact held by %d, want the explorer", teg.item_unique(art))
+	}
+}
+
+func TestUseBtaSkull(t *testing.T) {
+	who, _, _ := setupQuestTest(t)
+	teg.p_magic(who).magician = TRUE
+	teg.p_magic(who).max_aura = 100
+	teg.p_magic(who).cur_aura = 0
+
+	for i := 0; i < 20; i++ {
+		teg.p_char(who).health = 100
+		teg.move_item(teg.globals.nowhereLoc, who, RELIC_BTA_SKULL, 1)
+
+		c := &command{who: who, a: RELIC_BTA_SKULL}
+		teg.v_use_bta_skull(c)
+
+		if teg.item_unique(RELIC_BTA_SKULL) != teg.globals.nowhereLoc {
+			t.Fatal("skull did not return to Nowhere")
+		}
+		if teg.char_cur_aura(who) > 0 {
+			return
+		}
+	}
+	t.Error("skull never granted any aura")
+}
@@ -0,0 +1,370 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// relig.go - Priest skills ported from src/relig.c
+//
+// Priests know the religion school. Most religious acts succeed half
+// the time; a preparatory ritual (the pray skill) guarantees that the
+// next one succeeds. A priest who owns a temple collects offerings
+// at the end of each month.
+
+package taygete
+
+// is_priest reports whether n knows the religion school.
+// Port of C is_priest() macro from oly.h.
+func (e *Engine) is_priest(n int) bool {
+	return e.has_skill(n, sk_religion)
+}
+
+// check_vision_target reports whether target can be the subject of
+// a vision: a character, ship, location or unique item.
+// Ported from src/relig.c lines 7-33.
+func (e *Engine) check_vision_target(c *command, target int) bool {
+	switch e.kind(target) {
+	case T_char, T_ship, T_loc:
+		// ok
+
+	case T_item:
+		if e.item_unique(target) == 0 {
+			wout(c.who, "%s is not a unique item.", e.box_code(target))
+			return false
+		}
+
+	default:
+		wout(c.who, "Cannot receive a vision for %s.", e.box_code(target))
+		return false
+	}
+
+	return true
+}
+
+// v_reveal_vision starts praying for a vision of a target.
+// A vision may only be received once for a particular target.
+// Ported from src/relig.c lines 36-57.
+func (e *Engine) v_reveal_vision(c *command) int {
+	target := c.a
+
+	if !e.check_vision_target(c, target) {
+		return FALSE
+	}
+
+	if p := e.rp_magic(c.who); p != nil && test_bit(p.visions, target) {
+		wout(c.who, "Already have received a vision of %s.", e.box_code(target))
+		wout(c.who, "A vision may only be received once for a particular target.")
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_reveal_vision shows the priest the target. Targets in other
+// regions can't be seen, and vision protection blocks one attempt.
+// Ported from src/relig.c lines 60-126.
+func (e *Engine) d_reveal_vision(c *command) int {
+	target := c.a
+	chance := 50
+
+	if !e.check_vision_target(c, target) {
+		return FALSE
+	}
+
+	if e.char_pray(c.who) != 0 {
+		e.p_magic(c.who).pray = 0
+		chance = 100
+	}
+
+	if e.rndFrom(streamMagic, 1, 100) > chance || e.diff_region(c.who, target) {
+		wout(c.who, "Failed to receive a vision.")
+		return FALSE
+	}
+
+	if e.kind(target) == T_char && e.vision_protect(target) != 0 {
+		e.p_magic(target).vis_protect--
+		wout(target, "%s blocks a vision attempt from %s.", e.box_name(target), e.box_name(c.who))
+		wout(target, "Vision protection now %d.", e.vision_protect(target))
+		wout(c.who, "Failed to receive a vision.")
+		return FALSE
+	}
+
+	p := e.p_magic(c.who)
+	p.visions = set_bit(p.visions, target)
+
+	wout(c.who, "%s receives a vision of %s:", e.box_name(c.who), e.box_name(target))
+
+	out(c.who, "")
+
+	switch e.kind(target) {
+	case T_loc, T_ship:
+		e.show_loc(c.who, e.viewloc(target))
+
+	case T_char:
+		if e.has_skill(target, sk_vision_protect) {
+			wout(target, "%s receives a vision of %s.", e.box_name(c.who), e.box_name(target))
+		}
+
+		e.char_rep_sup(c.who, target)
+
+	case T_item:
+		e.show_item_where(c.who, target)
+
+	default:
+		panic("d_reveal_vision: bad target kind")
+	}
+
+	return TRUE
+}
+
+// v_resurrect starts resurrecting a dead noble from their body.
+// Ported from src/relig.c lines 129-150.
+func (e *Engine) v_resurrect(c *command) int {
+	body := c.a
+
+	if !e.valid_box(body) || e.has_item(c.who, body) < 1 {
+		wout(c.who, "Don't have any body %s.", e.box_code(body))
+		return FALSE
+	}
+
+	if e.kind(body) != T_item || e.subkind(body) != sub_dead_body {
+		wout(c.who, "%s is not the dead body of a noble.", e.box_code(body))
+		return FALSE
+	}
+
+	if e.item_unique(body) == 0 {
+		panic("v_resurrect: dead body is not unique")
+	}
+
+	return TRUE
+}
+
+// d_resurrect brings a dead noble back to life. The chance is 50%,
+// or certain if the priest has prepared with a ritual. Nobles whose
+// defense has fallen below 50 cannot be brought back.
+// Ported from src/relig.c lines 153-197.
+func (e *Engine) d_resurrect(c *command) int {
+	body := c.a
+	chance := 50
+
+	if !e.valid_box(body) || e.has_item(c.who, body) < 1 {
+		wout(c.who, "Don't have any body %s.", e.box_code(body))
+		return FALSE
+	}
+
+	if e.kind(body) != T_item || e.subkind(body) != sub_dead_body {
+		wout(c.who, "%s is not the dead body of a noble.", e.box_code(body))
+		return FALSE
+	}
+
+	if e.char_defense(body) < 50 {
+		wout(c.who, "Defense rating of %s is lower than 50, no resurrection is possible.",
+			e.box_name(body))
+		return FALSE
+	}
+
+	if e.char_pray(c.who) != 0 {
+		e.p_magic(c.who).pray = 0
+		chance = 100
+	}
+
+	if e.rndFrom(streamMagic, 1, 100) > chance {
+		wout(c.who, "Resurrection failed.")
+		return FALSE
+	}
+
+	if name := e.save_name(body); name != "" {
+		wout(c.who, "Brought %s back to life!", name)
+	}
+
+	e.restore_dead_body(c.who, body)
+
+	return TRUE
+}
+
+// v_prep_ritual starts a preparatory ritual.
+// Ported from src/relig.c lines 200-215.
+func (e *Engine) v_prep_ritual(c *command) int {
+	if !e.check_skill(c.who, sk_pray) {
+		return FALSE
+	}
+
+	if e.char_pray(c.who) != 0 {
+		wout(c.who, "Have already completed a preparatory ritual.")
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_prep_ritual guarantees success of the priest's next religious act.
+// Ported from src/relig.c lines 218-225.
+func (e *Engine) d_prep_ritual(c *command) int {
+	wout(c.who, "The next religious act will surely succeed.")
+	e.p_magic(c.who).pray = 1
+	return TRUE
+}
+
+// v_last_rites starts laying a dead noble to rest in a graveyard.
+// Ported from src/relig.c lines 228-255.
+func (e *Engine) v_last_rites(c *command) int {
+	body := c.a
+	where := e.subloc(c.who)
+
+	if e.subkind(where) != sub_graveyard {
+		wout(c.who, "Must be performed in a graveyard.")
+		return FALSE
+	}
+
+	if !e.valid_box(body) || e.has_item(c.who, body) < 1 {
+		wout(c.who, "Don't have any body %s.", e.box_code(body))
+		return FALSE
+	}
+
+	if e.kind(body) != T_item || e.subkind(body) != sub_dead_body {
+		wout(c.who, "%s is not the dead body of a noble.", e.box_code(body))
+		return FALSE
+	}
+
+	if e.item_unique(body) == 0 {
+		panic("v_last_rites: dead body is not unique")
+	}
+
+	return TRUE
+}
+
+// d_last_rites destroys the body; its old lord regains the noble's NPs.
+// Ported from src/relig.c lines 258-297.
+func (e *Engine) d_last_rites(c *command) int {
+	body := c.a
+	where := e.subloc(c.who)
+
+	if e.subkind(where) != sub_graveyard {
+		wout(c.who, "Must be performed in a graveyard.")
+		return FALSE
+	}
+
+	if !e.valid_box(body) || e.has_item(c.who, body) < 1 {
+		wout(c.who, "Don't have any body %s.", e.box_code(body))
+		return FALSE
+	}
+
+	if e.kind(body) != T_item || e.subkind(body) != sub_dead_body {
+		wout(c.who, "%s is not the dead body of a noble.", e.box_code(body))
+		return FALSE
+	}
+
+	if e.item_unique(body) == 0 {
+		panic("d_last_rites: dead body is not unique")
+	}
+
+	old_name := e.save_name(body)
+	if old_name == "" {
+		old_name = e.box_code(body)
+	}
+
+	wout(c.who, "%s has been laid to rest.", old_name)
+
+	e.destroy_unique_item(c.who, body)
+
+	return TRUE
+}
+
+// v_remove_bless starts removing the blessing from a unit's soldiers.
+// Ported from src/relig.c lines 300-312.
+func (e *Engine) v_remove_bless(c *command) int {
+	target := c.a
+
+	if target == 0 {
+		target = c.who
+		c.a = target
+	}
+
+	if !e.check_char_here(c.who, target) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_remove_bless turns blessed soldiers back into plain soldiers.
+// Ported from src/relig.c lines 315-361.
+func (e *Engine) d_remove_bless(c *command) int {
+	target := c.a
+	chance := 50
+
+	if !e.check_still_here(c.who, target) {
+		return FALSE
+	}
+
+	has := e.has_item(target, item_blessed_soldier)
+	if has < 1 {
+		wout(c.who, "%s has no %s.", e.box_name(target), e.just_name_qty(item_blessed_soldier, 2))
+		return FALSE
+	}
+
+	if e.char_pray(c.who) != 0 {
+		e.p_magic(c.who).pray = 0
+		chance = 100
+	}
+
+	if e.rndFrom(streamMagic, 1, 100) > chance {
+		wout(c.who, "Failed to remove blessing.")
+		return FALSE
+	}
+
+	e.consume_item(target, item_blessed_soldier, has)
+	e.gen_item(target, item_soldier, has)
+
+	wout(c.who, "Removed blessing from %s.", e.just_name_qty(item_soldier, 2))
+
+	if target != c.who {
+		wout(target, "%s removed the blessing from %s of our soldiers!",
+			e.box_name(c.who), comma_num(has))
+	}
+
+	return TRUE
+}
+
+// v_vision_protect starts casting protection from visions.
+// Ported from src/relig.c lines 363-367.
+func (e *Engine) v_vision_protect(c *command) int {
+	return TRUE
+}
+
+// d_vision_protect adds one level of vision protection to the target,
+// or to the priest if no target is given.
+// Ported from src/relig.c lines 369-396.
+func (e *Engine) d_vision_protect(c *command) int {
+	target := c.a
+
+	if e.numargs(c) < 1 {
+		target = c.who
+	}
+
+	if !e.check_still_here(c.who, target) {
+		return FALSE
+	}
+
+	e.p_magic(target).vis_protect++
+
+	wout(c.who, "Protection from Receive Vision for %s now %d.",
+		e.box_name(target), e.vision_protect(target))
+
+	if target != c.who {
+		wout(target, "%s casts %s on us.", e.box_name(c.who), e.box_name(c.use_skill))
+		wout(target, "Protection from Receive Vision now %d.", e.vision_protect(target))
+	}
+
+	return TRUE
+}
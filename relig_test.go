@@ -0,0 +1,207 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// relig_test.go - Tests for priests and temples
+
+package taygete
+
+import "testing"
+
+// setupReligTest makes the necro test's mage a priest who knows
+// the religion school and the pray skill.
+func setupReligTest(t *testing.T) (pl, who, prov, grave int) {
+	t.Helper()
+	pl, who, prov, grave = setupNecroTest(t)
+
+	teg.alloc_box(sk_religion, T_skill, 0)
+	teg.alloc_box(sk_pray, T_skill, 0)
+	teg.p_skill(sk_pray).required_skill = sk_religion
+	teg.p_skill_ent(who, sk_religion).know = SKILL_know
+	teg.p_skill_ent(who, sk_pray).know = SKILL_know
+
+	return pl, who, prov, grave
+}
+
+func TestPrepRitual(t *testing.T) {
+	_, who, _, _ := setupReligTest(t)
+
+	c := &command{who: who}
+	if got := teg.v_prep_ritual(c); got != TRUE {
+		t.Fatalf("v_prep_ritual = %d, want TRUE", got)
+	}
+	teg.d_prep_ritual(c)
+	if teg.char_pray(who) == 0 {
+		t.Fatal("pray flag not set by ritual")
+	}
+	if got := teg.v_prep_ritual(c); got != FALSE {
+		t.Errorf("second v_prep_ritual = %d, want FALSE", got)
+	}
+
+	// a prepared priest always removes the blessing
+	teg.alloc_box(item_soldier, T_item, 0)
+	teg.alloc_box(item_blessed_soldier, T_item, 0)
+	teg.gen_item(who, item_blessed_soldier, 12)
+
+	c = &command{who: who, a: who}
+	if got := teg.d_remove_bless(c); got != TRUE {
+		t.Fatalf("d_remove_bless = %d, want TRUE", got)
+	}
+	if teg.has_item(who, item_blessed_soldier) != 0 || teg.has_item(who, item_soldier) != 12 {
+		t.Errorf("blessed/plain = %d/%d, want 0/12",
+			teg.has_item(who, item_blessed_soldier), teg.has_item(who, item_soldier))
+	}
+	if teg.char_pray(who) != 0 {
+		t.Error("pray flag not used up")
+	}
+}
+
+func TestLastRites(t *testing.T) {
+	pl, who, prov, grave := setupReligTest(t)
+
+	noble := 1002
+	teg.alloc_box(noble, T_char, 0)
+	teg.set_where(noble, prov)
+	teg.set_lord(noble, pl, LOY_oath, 2)
+
+	teg.dead_char_body(pl, noble)
+	teg.move_item(prov, who, noble, 1)
+	teg.set_where(who, prov)
+
+	c := &command{who: who, a: noble}
+	if got := teg.v_last_rites(c); got != FALSE {
+		t.Errorf("v_last_rites outside a graveyard = %d, want FALSE", got)
+	}
+
+	teg.set_where(who, grave)
+	np := teg.player_np(pl)
+	if got := teg.v_last_rites(c); got != TRUE {
+		t.Fatalf("v_last_rites = %d, want TRUE", got)
+	}
+	if got := teg.d_last_rites(c); got != TRUE {
+		t.Fatalf("d_last_rites = %d, want TRUE", got)
+	}
+	if teg.kind(noble) == T_item {
+		t.Error("body was not laid to rest")
+	}
+	if teg.player_np(pl) <= np {
+		t.Errorf("noble points = %d, want more than %d", teg.player_np(pl), np)
+	}
+}
+
+func TestRevealVisionOnce(t *testing.T) {
+	_, who, prov, _ := setupReligTest(t)
+	teg.p_magic(who).pray = 1
+
+	c := &command{who: who, a: prov}
+	if got := teg.v_reveal_vision(c); got != TRUE {
+		t.Fatalf("v_reveal_vision = %d, want TRUE", got)
+	}
+	if got := teg.d_reveal_vision(c); got != TRUE {
+		t.Fatalf("d_reveal_vision = %d, want TRUE", got)
+	}
+	if !test_bit(teg.p_magic(who).visions, prov) {
+		t.Error("vision not recorded")
+	}
+	if got := teg.v_reveal_vision(c); got != FALSE {
+		t.Errorf("second v_reveal_vision = %d, want FALSE", got)
+	}
+}
+
+func TestVisionProtectBlocks(t *testing.T) {
+	_, who, _, grave := setupReligTest(t)
+
+	target := 1002
+	teg.alloc_box(target, T_char, 0)
+	teg.set_where(target, grave)
+
+	c := &command{who: who, a: target}
+	if got := teg.d_vision_protect(c); got != TRUE {
+		t.Fatalf("d_vision_protect = %d, want TRUE", got)
+	}
+	if got := teg.vision_protect(target); got != 1 {
+		t.Fatalf("vis_protect = %d, want 1", got)
+	}
+
+	teg.p_magic(who).pray = 1
+	c = &command{who: who, a: target}
+	if got := teg.d_reveal_vision(c); got != FALSE {
+		t.Errorf("d_reveal_vision on protected target = %d, want FALSE", got)
+	}
+	if got := teg.vision_protect(target); got != 0 {
+		t.Errorf("vis_protect = %d after a blocked vision, want 0", got)
+	}
+}
+
+func TestTempleIncome(t *testing.T) {
+	_, who, prov, _ := setupReligTest(t)
+	teg.alloc_box(item_gold, T_item, 0)
+
+	temple := 56_761
+	teg.alloc_box(temple, T_loc, sub_temple)
+	teg.set_where(temple, prov)
+	teg.set_where(who, temple)
+
+	saved := teg.globals.gold_temple
+	defer func() { teg.globals.gold_temple = saved }()
+	teg.globals.gold_temple = 0
+
+	teg.templeIncome()
+	if got := teg.has_item(who, item_gold); got != 100 {
+		t.Errorf("gold = %d, want 100", got)
+	}
+
+	// pillaging cuts the offerings
+	teg.p_subloc(prov).loot = 3
+	teg.templeIncome()
+	if got := teg.has_item(who, item_gold); got != 125 {
+		t.Errorf("gold = %d, want 125 after pillaged month", got)
+	}
+	if teg.globals.gold_temple != 125 {
+		t.Errorf("gold_temple = %d, want 125", teg.globals.gold_temple)
+	}
+
+	// only priests collect
+	teg.p_skill_ent(who, sk_religion).know = SKILL_dont
+	teg.templeIncome()
+	if got := teg.has_item(who, item_gold); got != 125 {
+		t.Errorf("gold = %d, want 125 for a non-priest", got)
+	}
+}
+
+func TestHeal(t *testing.T) {
+	_, who, _, grave := setupReligTest(t)
+
+	target := 1002
+	teg.alloc_box(target, T_char, 0)
+	teg.set_where(target, grave)
+
+	c := &command{who: who, a: target, b: 3}
+	if got := teg.v_heal(c); got != FALSE {
+		t.Errorf("v_heal on healthy target = %d, want FALSE", got)
+	}
+
+	teg.p_char(target).sick = TRUE
+	cured := false
+	for i := 0; i < 10 && !cured; i++ {
+		if teg.v_heal(c) != TRUE {
+			t.Fatal("v_heal refused a sick target")
+		}
+		cured = teg.d_heal(c) == TRUE
+	}
+	if !cured || teg.char_sick(target) != 0 {
+		t.Error("Heal never cured the target")
+	}
+}
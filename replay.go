@@ -0,0 +1,288 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// replay.go - deterministic turn replay and divergence detection
+
+package taygete
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// ErrNoOutcome is returned by ReplayTurn when the turn has no recorded
+// outcome to compare against.
+var ErrNoOutcome = errors.New("no recorded outcome for turn")
+
+// TurnOutcome is what a turn produced: a hash of the world afterwards,
+// the hash of each entity and the report lines written, in order.
+type TurnOutcome struct {
+	WorldHash string
+	Entities  map[int]string
+	Events    []TurnEvent
+}
+
+// TurnEvent is one line written to a player's turn report.
+type TurnEvent struct {
+	Player int    `json:"player"`
+	Line   string `json:"line"`
+}
+
+// worldOutcome hashes the world and collects the report lines written
+// this turn. Entity hashes are those of the canonical world state.
+func (e *Engine) worldOutcome() *TurnOutcome {
+	o := &TurnOutcome{Entities: make(map[int]string)}
+
+	w := e.WorldState()
+	for id := range w {
+		o.Entities[id] = w.EntityHash(id)
+	}
+	o.WorldHash = w.Hash()
+
+	for _, pl := range slices.Sorted(maps.Keys(e.globals.reports)) {
+		for _, line := range e.globals.reports[pl] {
+			o.Events = append(o.Events, TurnEvent{Player: pl, Line: line})
+		}
+	}
+
+	return o
+}
+
+// recordOutcome stores the outcome of turn, replacing any earlier one.
+func (e *Engine) recordOutcome(turn int, o *TurnOutcome) error {
+	if e.db == nil {
+		return nil
+	}
+
+	entities, err := json.Marshal(o.Entities)
+	if err != nil {
+		return fmt.Errorf("record outcome %d: %w", turn, err)
+	}
+	events, err := json.Marshal(o.Events)
+	if err != nil {
+		return fmt.Errorf("record outcome %d: %w", turn, err)
+	}
+
+	if _, err := e.db.Exec(`INSERT OR IGNORE INTO turns (turn_number) VALUES (?)`, turn); err != nil {
+		return fmt.Errorf("insert turn %d: %w", turn, err)
+	}
+	if _, err := e.db.Exec(`
+		INSERT INTO turn_outcomes (turn_number, world_hash, entities, events) VALUES (?, ?, ?, ?)
+		ON CONFLICT (turn_number) DO UPDATE SET world_hash = excluded.world_hash,
+			entities = excluded.entities, events = excluded.events, created_at = CURRENT_TIMESTAMP
+	`, turn, o.WorldHash, string(entities), string(events)); err != nil {
+		return fmt.Errorf("record outcome %d: %w", turn, err)
+	}
+
+	return nil
+}
+
+// readOutcome returns the recorded outcome of turn.
+func readOutcome(db *sql.DB, turn int) (*TurnOutcome, error) {
+	var entities, events string
+	o := &TurnOutcome{}
+	err := db.QueryRow(`
+		SELECT world_hash, entities, events FROM turn_outcomes WHERE turn_number = ?
+	`, turn).Scan(&o.WorldHash, &entities, &events)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("turn %d: %w", turn, ErrNoOutcome)
+	} else if err != nil {
+		return nil, fmt.Errorf("read outcome %d: %w", turn, err)
+	}
+
+	if err := json.Unmarshal([]byte(entities), &o.Entities); err != nil {
+		return nil, fmt.Errorf("read outcome %d: %w", turn, err)
+	}
+	if err := json.Unmarshal([]byte(events), &o.Events); err != nil {
+		return nil, fmt.Errorf("read outcome %d: %w", turn, err)
+	}
+
+	return o, nil
+}
+
+// ReplayResult compares a replayed turn with its recorded outcome.
+type ReplayResult struct {
+	Turn     int
+	Recorded *TurnOutcome
+	Replayed *TurnOutcome
+
+	// FirstEntity is the lowest numbered entity that differs, or 0.
+	FirstEntity int
+	// FirstEvent is the index of the first event that differs, or -1.
+	FirstEvent int
+}
+
+// Diverged reports whether the replay differs from the recorded turn.
+func (r *ReplayResult) Diverged() bool {
+	return r.Recorded.WorldHash != r.Replayed.WorldHash || r.FirstEvent >= 0
+}
+
+// String describes the first differences found.
+func (r *ReplayResult) String() string {
+	if !r.Diverged() {
+		return fmt.Sprintf("turn %d: replay matches, world %s", r.Turn, r.Replayed.WorldHash)
+	}
+
+	s := fmt.Sprintf("turn %d: replay diverged", r.Turn)
+	if n := r.FirstEntity; n != 0 {
+		s += fmt.Sprintf("; first entity %s: recorded %q, replayed %q",
+			box_code_less(n), r.Recorded.Entities[n], r.Replayed.Entities[n])
+	}
+	if i := r.FirstEvent; i >= 0 {
+		s += fmt.Sprintf("; event %d: recorded %s, replayed %s",
+			i, eventString(r.Recorded.Events, i), eventString(r.Replayed.Events, i))
+	}
+	return s
+}
+
+// eventString formats events[i], or "nothing" past the end.
+func eventString(events []TurnEvent, i int) string {
+	if i >= len(events) {
+		return "nothing"
+	}
+	return strconv.Quote(box_code_less(events[i].Player) + ": " + events[i].Line)
+}
+
+// compare fills in the first entity and event that differ.
+func (r *ReplayResult) compare() {
+	r.FirstEvent = -1
+
+	ids := slices.Collect(maps.Keys(r.Recorded.Entities))
+	for id := range r.Replayed.Entities {
+		if _, ok := r.Recorded.Entities[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		got, ok1 := r.Replayed.Entities[id]
+		want, ok2 := r.Recorded.Entities[id]
+		if got != want || ok1 != ok2 {
+			r.FirstEntity = id
+			break
+		}
+	}
+
+	for i := 0; i < max(len(r.Recorded.Events), len(r.Replayed.Events)); i++ {
+		if i >= len(r.Recorded.Events) || i >= len(r.Replayed.Events) ||
+			r.Recorded.Events[i] != r.Replayed.Events[i] {
+			r.FirstEvent = i
+			break
+		}
+	}
+}
+
+// ReplayTurn runs turn again from its snapshot and recorded orders in
+// a scratch database and compares the result with the outcome recorded
+// when the turn was first run. db is not changed.
+func ReplayTurn(db *sql.DB, turn int) (*ReplayResult, error) {
+	recorded, err := readOutcome(db, turn)
+	if err != nil {
+		return nil, err
+	}
+
+	scratch, err := OpenGameDB(":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("replay turn %d: %w", turn, err)
+	}
+	defer scratch.Close()
+	// Every connection to :memory: is a new database
+	scratch.SetMaxOpenConns(1)
+
+	if err := copySnapshot(db, scratch, turn); err != nil {
+		return nil, fmt.Errorf("replay turn %d: %w", turn, err)
+	}
+
+	e, err := NewEngine(scratch, nil)
+	if err != nil {
+		return nil, fmt.Errorf("replay turn %d: %w", turn, err)
+	}
+	e.logger = nil
+	if err := e.RollbackTurn(turn); err != nil {
+		return nil, fmt.Errorf("replay turn %d: %w", turn, err)
+	}
+	e.ClearOrders()
+	if err := e.LoadOrders(turn); err != nil {
+		return nil, fmt.Errorf("replay turn %d: %w", turn, err)
+	}
+	if err := e.RunTurn(); err != nil {
+		return nil, fmt.Errorf("replay turn %d: %w", turn, err)
+	}
+
+	replayed, err := readOutcome(scratch, turn)
+	if err != nil {
+		return nil, fmt.Errorf("replay turn %d: %w", turn, err)
+	}
+
+	r := &ReplayResult{Turn: turn, Recorded: recorded, Replayed: replayed}
+	r.compare()
+	return r, nil
+}
+
+// copySnapshot copies the snapshot of turn from src to dst.
+func copySnapshot(src, dst *sql.DB, turn int) error {
+	rows, err := src.Query(`
+		SELECT table_name, columns, rows FROM turn_snapshot_tables WHERE turn_number = ?
+	`, turn)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type table struct{ name, columns, rows string }
+	var tables []table
+	for rows.Next() {
+		var t table
+		if err := rows.Scan(&t.name, &t.columns, &t.rows); err != nil {
+			return err
+		}
+		tables = append(tables, t)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return ErrNoSnapshot
+	}
+
+	tx, err := dst.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT INTO turns (turn_number) VALUES (?)`, turn); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO turn_snapshots (turn_number) VALUES (?)`, turn); err != nil {
+		return err
+	}
+	for _, t := range tables {
+		if _, err := tx.Exec(`
+			INSERT INTO turn_snapshot_tables (turn_number, table_name, columns, rows)
+			VALUES (?, ?, ?, ?)
+		`, turn, t.name, t.columns, t.rows); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
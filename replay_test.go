@@ -0,0 +1,108 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package taygete
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// newReplayGame plays one turn of a loaded game with an order for the
+// noble, recording its snapshot and outcome.
+func newReplayGame(t *testing.T) (*engineGame, int) {
+	t.Helper()
+	g := newEngineGame(t, 1, "Red Company", "Osswid")
+	e, db := g.e, g.e.db
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	if err := e.LoadWorld(); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+	turn := int(e.globals.sysclock.turn)
+
+	if _, err := db.Exec(`INSERT INTO turns (turn_number) VALUES (?)`, turn); err != nil {
+		t.Fatalf("insert turn: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO orders (turn_number, player_id, source_char_id, raw_text) VALUES (?, ?, ?, 'wait time 3')
+	`, turn, g.pl, g.who); err != nil {
+		t.Fatalf("insert order: %v", err)
+	}
+	if err := e.LoadOrders(turn); err != nil {
+		t.Fatalf("LoadOrders: %v", err)
+	}
+	if err := e.RunTurn(); err != nil {
+		t.Fatalf("RunTurn: %v", err)
+	}
+	return g, turn
+}
+
+func TestReplayTurn(t *testing.T) {
+	g, turn := newReplayGame(t)
+
+	r, err := ReplayTurn(g.e.db, turn)
+	if err != nil {
+		t.Fatalf("ReplayTurn: %v", err)
+	}
+	if r.Diverged() {
+		t.Errorf("replay diverged: %s", r)
+	}
+	if len(r.Recorded.Events) == 0 || len(r.Recorded.Entities) == 0 {
+		t.Errorf("recorded outcome is empty: %d entities, %d events",
+			len(r.Recorded.Entities), len(r.Recorded.Events))
+	}
+}
+
+func TestReplayTurnDiverged(t *testing.T) {
+	g, turn := newReplayGame(t)
+
+	// Pretend the engine used to produce something else
+	if _, err := g.e.db.Exec(`
+		UPDATE turn_outcomes SET world_hash = 'old',
+			entities = json_set(entities, '$."' || ? || '"', 'old'),
+			events = json_set(events, '$[1].line', 'old line')
+		WHERE turn_number = ?
+	`, g.who, turn); err != nil {
+		t.Fatalf("update outcome: %v", err)
+	}
+
+	r, err := ReplayTurn(g.e.db, turn)
+	if err != nil {
+		t.Fatalf("ReplayTurn: %v", err)
+	}
+	if !r.Diverged() {
+		t.Fatal("replay did not diverge")
+	}
+	if r.FirstEntity != g.who {
+		t.Errorf("first entity = %d, want %d", r.FirstEntity, g.who)
+	}
+	if r.FirstEvent != 1 {
+		t.Errorf("first event = %d, want 1", r.FirstEvent)
+	}
+	if s := r.String(); !strings.Contains(s, box_code_less(g.who)) || !strings.Contains(s, "old line") {
+		t.Errorf("String() = %q, want the entity and event named", s)
+	}
+}
+
+func TestReplayTurnWithoutOutcome(t *testing.T) {
+	e := newEngineGame(t, 1, "Red Company", "Osswid").e
+	if _, err := ReplayTurn(e.db, 99); !errors.Is(err, ErrNoOutcome) {
+		t.Errorf("ReplayTurn(99) = %v, want ErrNoOutcome", err)
+	}
+}
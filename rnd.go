@@ -16,22 +16,193 @@
 
 package taygete
 
-// rnd returns a number in the range [low, high].
-func rnd(low, high int) int {
-	return teg.prng.IntN(high-low) + low
-}
+import (
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"math/rand/v2"
+	"slices"
 
-// load_seed restores our global prng state from the database.
-func load_seed(path string) error {
-	return teg.restorePrngState(path)
+	"github.com/mdhender/prng"
+)
+
+// load_seed restores the engine prng state from the database.
+func (e *Engine) load_seed(path string) error {
+	return e.restorePrngState(path)
 }
 
-// save_seed writes our global prng state to the database.
-func save_seed(path string) error {
-	return teg.savePrngState(path)
+// save_seed writes the engine prng state to the database.
+func (e *Engine) save_seed(path string) error {
+	return e.savePrngState(path)
 }
 
 // rnd returns a number in the range [low, high].
 func (e *Engine) rnd(low, high int) int {
-	return e.prng.IntN(high-low) + low
+	return e.prng.IntN(high-low+1) + low
+}
+
+// Named random streams, one per subsystem. Each is derived from the
+// game seed and kept in prng_state under its name, so a new draw in NPC
+// movement leaves combat and market results alone. Shuffles take the
+// stream of their caller; e.rnd draws from
+// e.prng, saved as ".".
+const (
+	streamCombat      = "combat"      // loot, wounds, prisoners
+	streamNPC         = "npc"         // NPC movement and behavior
+	streamMarkets     = "markets"     // market prices and income
+	streamExploration = "exploration" // explore results, hidden exits
+	streamSeeding     = "seeding"     // treasure, monsters, quests, new players
+	streamMagic       = "magic"       // spells, potions and artifacts
+	streamSkills      = "skills"      // training, healing, production and stealth
+	streamUpkeep      = "upkeep"      // starvation, animal deaths, decay, strandings
+	streamWeather     = "weather"     // natural storms
+)
+
+// rndFrom returns a number in the range [low, high] from the named stream.
+func (e *Engine) rndFrom(name string, low, high int) int {
+	return e.stream(name).IntN(high-low+1) + low
+}
+
+// stream returns the named random stream, deriving it from the game
+// seed on first use.
+func (e *Engine) stream(name string) *prng.Rand {
+	if r, ok := e.streams[name]; ok {
+		return r
+	}
+	if e.streams == nil {
+		e.streams = make(map[string]*prng.Rand)
+	}
+	r := deriveStream(e.seed, name)
+	e.streams[name] = r
+	return r
+}
+
+// deriveStream returns the generator for stream name of a game seed.
+func deriveStream(seed uint64, name string) *prng.Rand {
+	mix := func(z uint64) uint64 {
+		z += 0x9e3779b97f4a7c15
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		return z ^ (z >> 31)
+	}
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	n := h.Sum64()
+	// PCG needs an odd stream selector
+	return prng.New(rand.NewPCG(mix(seed^n), mix(seed+n)|1))
+}
+
+// seedOf returns a game seed for an engine created without one, taken
+// from the state of its generator.
+func seedOf(p *prng.Rand) uint64 {
+	state, err := p.MarshalBinary()
+	if err != nil {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write(state)
+	return h.Sum64()
+}
+
+// SetSeed sets the game seed. The default generator and every named
+// stream start over from it.
+func (e *Engine) SetSeed(seed uint64) {
+	e.seed = seed
+	e.prng = deriveStream(seed, ".")
+	e.streams = nil
+}
+
+// SetStream replaces the named stream, so a test can pin the draws
+// of one subsystem.
+func (e *Engine) SetStream(name string, r *prng.Rand) {
+	if e.streams == nil {
+		e.streams = make(map[string]*prng.Rand)
+	}
+	e.streams[name] = r
+}
+
+// savePrngStreams writes the game seed and the state of every stream.
+func (e *Engine) savePrngStreams() error {
+	tx, err := e.db.Begin()
+	if err != nil {
+		return fmt.Errorf("save streams: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := e.writePrngStreams(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// writePrngStreams writes the game seed, the default generator and
+// every named stream in tx.
+func (e *Engine) writePrngStreams(tx *sql.Tx) error {
+	var seed [8]byte
+	binary.BigEndian.PutUint64(seed[:], e.seed)
+	if _, err := tx.Exec(`
+		INSERT INTO rng_state (id, seed_blob) VALUES (1, ?)
+		ON CONFLICT (id) DO UPDATE SET seed_blob = excluded.seed_blob
+	`, seed[:]); err != nil {
+		return fmt.Errorf("save game seed: %w", err)
+	}
+
+	streams := maps.Clone(e.streams)
+	if e.prng != nil {
+		if streams == nil {
+			streams = make(map[string]*prng.Rand)
+		}
+		streams["."] = e.prng
+	}
+	for _, name := range slices.Sorted(maps.Keys(streams)) {
+		state, err := streams[name].MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("save stream %s: %w", name, err)
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO prng_state (name, state) VALUES (?, ?)
+			ON CONFLICT (name) DO UPDATE SET state = excluded.state
+		`, name, state); err != nil {
+			return fmt.Errorf("save stream %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// restorePrngStreams reads the game seed and the saved streams. A
+// database without a seed keeps the engine's.
+func (e *Engine) restorePrngStreams() error {
+	var seed []byte
+	err := e.db.QueryRow(`SELECT seed_blob FROM rng_state WHERE id = 1`).Scan(&seed)
+	if err == nil && len(seed) == 8 {
+		e.seed = binary.BigEndian.Uint64(seed)
+	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("restore game seed: %w", err)
+	}
+	e.streams = nil
+
+	rows, err := e.db.Query(`SELECT name, state FROM prng_state WHERE name != '.'`)
+	if err != nil {
+		return fmt.Errorf("restore streams: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var state []byte
+		if err := rows.Scan(&name, &state); err != nil {
+			return fmt.Errorf("restore streams: %w", err)
+		}
+		r := deriveStream(e.seed, name)
+		if err := r.UnmarshalBinary(state); err != nil {
+			return fmt.Errorf("restore stream %s: %w", name, err)
+		}
+		e.SetStream(name, r)
+	}
+
+	return rows.Err()
 }
@@ -19,6 +19,7 @@ package taygete
 import (
 	"log/slog"
 	"math/rand/v2"
+	"slices"
 	"testing"
 
 	"github.com/mdhender/prng"
@@ -204,3 +205,118 @@ func TestRndDeterministic(t *testing.T) {
 		}
 	}
 }
+
+func TestStreamsAreIndependent(t *testing.T) {
+	draw := func(e *Engine, name string) []int {
+		var got []int
+		for range 10 {
+			got = append(got, e.rndFrom(name, 1, 1000))
+		}
+		return got
+	}
+
+	a := &Engine{}
+	a.SetSeed(1_234)
+	b := &Engine{}
+	b.SetSeed(1_234)
+
+	// extra npc draws on one engine must not shift its combat draws
+	draw(a, streamNPC)
+	draw(a, streamNPC)
+	if got, want := draw(a, streamCombat), draw(b, streamCombat); !slices.Equal(got, want) {
+		t.Errorf("combat after npc draws: got %v, want %v", got, want)
+	}
+
+	c := &Engine{}
+	c.SetSeed(4_321)
+	if slices.Equal(draw(c, streamCombat), draw(b, streamCombat)) {
+		t.Error("different seeds gave the same combat stream")
+	}
+	if slices.Equal(draw(b, streamNPC), draw(b, streamMagic)) {
+		t.Error("npc and magic streams are the same")
+	}
+}
+
+func TestStreamsPersist(t *testing.T) {
+	db, err := OpenTestDB()
+	if err != nil {
+		t.Fatalf("OpenTestDB: %v", err)
+	}
+	defer db.Close()
+
+	e, err := NewEngine(db, prng.New(rand.NewPCG(7, 7)))
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	e.logger = nil
+	e.SetSeed(99)
+	e.rndFrom(streamMarkets, 1, 100)
+	if err := e.savePrngStreams(); err != nil {
+		t.Fatalf("savePrngStreams: %v", err)
+	}
+	var want []int
+	for range 10 {
+		want = append(want, e.rndFrom(streamMarkets, 1, 100))
+	}
+
+	f, err := NewEngine(db, prng.New(rand.NewPCG(8, 8)))
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	if f.seed != 99 {
+		t.Errorf("seed: got %d, want 99", f.seed)
+	}
+	for i, w := range want {
+		if got := f.rndFrom(streamMarkets, 1, 100); got != w {
+			t.Errorf("markets[%d]: got %d, want %d", i, got, w)
+		}
+	}
+}
+
+func TestSetStream(t *testing.T) {
+	e := &Engine{}
+	e.SetSeed(5)
+	e.SetStream(streamCombat, prng.New(rand.NewPCG(1, 2)))
+	p := prng.New(rand.NewPCG(1, 2))
+	for i := range 10 {
+		if got, want := e.rndFrom(streamCombat, 1, 100), p.IntN(100)+1; got != want {
+			t.Errorf("combat[%d]: got %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestSaveWorldSavesStreams(t *testing.T) {
+	db, err := OpenTestDB()
+	if err != nil {
+		t.Fatalf("OpenTestDB: %v", err)
+	}
+	defer db.Close()
+
+	e, err := NewEngine(db, nil)
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	e.logger = nil
+	e.SetSeed(2_026)
+	e.rndFrom(streamSeeding, 1, 100)
+	e.rnd(1, 100)
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+
+	f, err := NewEngine(db, nil)
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	if f.seed != 2_026 {
+		t.Errorf("seed: got %d, want 2026", f.seed)
+	}
+	for i := range 10 {
+		if got, want := f.rndFrom(streamSeeding, 1, 1000), e.rndFrom(streamSeeding, 1, 1000); got != want {
+			t.Errorf("seeding[%d]: got %d, want %d", i, got, want)
+		}
+		if got, want := f.rnd(1, 1000), e.rnd(1, 1000); got != want {
+			t.Errorf("default[%d]: got %d, want %d", i, got, want)
+		}
+	}
+}
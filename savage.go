@@ -0,0 +1,200 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// savage.go - Savage drums ported from src/savage.c
+//
+// The savages' own turn, auto_savage and init_savage_attacks, waits
+// on queueNpcOrders.
+
+package taygete
+
+// MAX_SAVAGES is the most savage units allowed in the world.
+const MAX_SAVAGES = 200
+
+// num_savages counts the savage units in the world. C counted them
+// once a turn in init_savage_attacks.
+func (e *Engine) num_savages() int {
+	n := 0
+	for i := e.kind_first(T_char); i != 0; i = e.kind_next(i) {
+		if e.noble_item(i) == item_savage {
+			n++
+		}
+	}
+	return n
+}
+
+// create_savage makes a band of 3 to 25 savages with a drum at where.
+// Returns -1 if no entity is left.
+// Ported from src/savage.c lines 13-28.
+func (e *Engine) create_savage(where int) int {
+	n := e.new_char(sub_ni, item_savage, where, 100, indep_player, LOY_npc, 0, "")
+	if n < 0 {
+		return -1
+	}
+
+	e.gen_item(n, item_drum, 1)
+	e.gen_item(n, item_savage, e.rndFrom(streamNPC, 3, 25))
+
+	return n
+}
+
+// call_savage raises savages at where, unless nobles are there, and
+// sends them to to_where: to attack who (why 0), to join who for three
+// months (why 1), or to raze structure who (why 2).
+// Ported from src/savage.c lines 31-63.
+func (e *Engine) call_savage(where, to_where, who, why int) bool {
+	if e.controlled_humans_here(where) {
+		return false
+	}
+
+	n := e.create_savage(where)
+	e.queue(n, "move %s", box_code_less(to_where))
+
+	switch why {
+	case 0: // battle challenge
+		e.queue(n, "attack %s", box_code_less(who))
+	case 1: // call to arms
+		e.set_loyal(n, LOY_summon, 3)
+		e.queue(n, "stack %s", box_code_less(who))
+	case 2: // move and attack structure
+		e.queue(n, "use 98 1")
+		e.queue(n, "wait time %d", e.rndFrom(streamNPC, 35, 50))
+		e.queue(n, "attack %s", box_code_less(who))
+	}
+
+	e.init_load_sup(n) // make ready to execute commands immediately
+
+	return true
+}
+
+// v_use_drum beats a drum, heard in the neighboring provinces. A slow
+// (1) beat calls savages to arms and none (0) is a battle challenge;
+// either brings at most one band from a neighboring land province.
+// Ported from src/savage.c lines 66-142.
+func (e *Engine) v_use_drum(c *command) int {
+	where := e.subloc(c.who)
+	speed := c.a
+
+	speed_s := ""
+	switch speed {
+	case 1:
+		speed_s = "slow "
+	case 2:
+		speed_s = "fast "
+	}
+
+	wout(c.who, "%s sounds a %sdrumbeat.", e.box_name(c.who), speed_s)
+	wout(where, "%s sounds a %sdrumbeat.", e.box_name(c.who), speed_s)
+
+	if e.loc_depth(where) != LOC_province {
+		s := sout("%sbeating drums may be heard coming from %s.", speed_s, e.box_name(where))
+		wout(e.subloc(where), "%s", cap(s))
+		return TRUE
+	}
+
+	calls := speed == 0 || speed == 1
+	called := false
+	for _, v := range e.exits_from_loc_nsew_select(c.who, e.province(where), LAND, RAND) {
+		dir := exit_opposite[v.direction]
+
+		s := sout("%sbeating drums may be heard to the %s.", speed_s, full_dir_s[dir])
+		wout(v.destination, "%s", cap(s))
+
+		if calls && !called && e.subkind(v.destination) != sub_ocean && e.num_savages() < MAX_SAVAGES {
+			called = e.call_savage(v.destination, where, c.who, speed)
+		}
+	}
+
+	var s string
+	switch speed {
+	case 0:
+		s = "battle challenge"
+	case 1:
+		s = "call to arms"
+	default:
+		s = "call"
+	}
+
+	if calls && !called {
+		wout(c.who, "No savages are responding to the %s.", s)
+	} else if calls {
+		wout(c.who, "Savages will surely respond to the %s.", s)
+	}
+
+	return TRUE
+}
+
+// v_summon_savage beats a call to arms on the character's drum.
+// Ported from src/savage.c lines 145-158.
+func (e *Engine) v_summon_savage(c *command) int {
+	if e.has_item(c.who, item_drum) < 1 {
+		wout(c.who, "Must first make a drum with MAKE 98 1.")
+		return FALSE
+	}
+
+	c.a = 1 // speed = summon
+
+	return e.v_use_drum(c)
+}
+
+// keep_savage_check reports whether c.a is a band of savages here
+// still bonded by a call to arms.
+// Ported from src/savage.c lines 161-185.
+func (e *Engine) keep_savage_check(c *command) bool {
+	target := c.a
+
+	if e.kind(target) != T_char || int(e.noble_item(target)) != item_savage {
+		wout(c.who, "%s is not a group of savages.", e.box_code(target))
+		return false
+	}
+
+	if e.subloc(target) != e.subloc(c.who) {
+		wout(c.who, "%s is not here.", e.box_code(target))
+		return false
+	}
+
+	if e.loyal_kind(target) != LOY_summon {
+		wout(c.who, "%s is no longer bonded.", e.box_code(target))
+		return false
+	}
+
+	return true
+}
+
+// v_keep_savage starts extending a band of savages' bond.
+// Ported from src/savage.c lines 188-197.
+func (e *Engine) v_keep_savage(c *command) int {
+	if !e.keep_savage_check(c) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_keep_savage keeps the savages two more months, at least four.
+// Ported from src/savage.c lines 200-214.
+func (e *Engine) d_keep_savage(c *command) int {
+	target := c.a
+
+	if !e.keep_savage_check(c) {
+		return FALSE
+	}
+
+	e.set_loyal(target, LOY_summon, max(e.loyal_rate(target)+2, 4))
+
+	wout(c.who, "%s will remain for %d months.", e.box_code(target), e.loyal_rate(target))
+	return TRUE
+}
@@ -0,0 +1,76 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// savage_test.go - Tests for savage drums
+
+package taygete
+
+import "testing"
+
+func TestSummonAndKeepSavage(t *testing.T) {
+	south, north, _, _ := setupDirTest(t)
+	teg.alloc_box(indep_player, T_player, sub_pl_npc)
+	for _, item := range []int{item_drum, item_savage} {
+		teg.alloc_box(item, T_item, 0)
+	}
+
+	who := 1001
+	teg.alloc_box(who, T_char, 0)
+	teg.set_where(who, south)
+	teg.set_loyal(who, LOY_oath, 1)
+
+	c := &command{who: who}
+	if got := teg.v_summon_savage(c); got != FALSE {
+		t.Errorf("v_summon_savage without a drum = %d, want FALSE", got)
+	}
+
+	teg.gen_item(who, item_drum, 1)
+	if got := teg.v_summon_savage(c); got != TRUE {
+		t.Fatalf("v_summon_savage = %d, want TRUE", got)
+	}
+
+	var band int
+	for i := teg.kind_first(T_char); i != 0; i = teg.kind_next(i) {
+		if int(teg.noble_item(i)) == item_savage {
+			band = i
+		}
+	}
+	if band == 0 {
+		t.Fatal("no savages answered the call to arms")
+	}
+	if got := teg.subloc(band); got != north {
+		t.Errorf("savages appeared in %d, want %d", got, north)
+	}
+	if n := teg.has_item(band, item_savage); n < 3 || n > 25 {
+		t.Errorf("band has %d savages, want 3 to 25", n)
+	}
+	if teg.loyal_kind(band) != LOY_summon || teg.loyal_rate(band) != 3 {
+		t.Errorf("band loyalty = %d/%d, want summon/3", teg.loyal_kind(band), teg.loyal_rate(band))
+	}
+
+	c = &command{who: who, a: band}
+	if got := teg.v_keep_savage(c); got != FALSE {
+		t.Errorf("v_keep_savage(band elsewhere) = %d, want FALSE", got)
+	}
+
+	teg.set_where(band, south)
+	if teg.v_keep_savage(c) != TRUE || teg.d_keep_savage(c) != TRUE {
+		t.Fatal("keep savage failed")
+	}
+	if got := teg.loyal_rate(band); got != 5 {
+		t.Errorf("bond = %d months, want 5", got)
+	}
+}
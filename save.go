@@ -18,528 +18,1203 @@ package taygete
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 // SaveWorld saves the in-memory world state to the database.
-// It clears existing data and writes all entities from the bx array.
+//
+// Only entities marked dirty since the world was last loaded or saved
+// are written: their rows are built again and replace the ones in the
+// database, and an entity that no longer exists loses its rows. Rows of
+// other entities, and the reports, orders and combat records that refer
+// to them, are left alone, and the save costs time in proportion to the
+// entities that changed. An engine that has neither loaded nor saved a
+// world does not know what the database holds and falls back to a full
+// save.
+//
+// setBox, deleteBox, the p_* accessors and the setters of the state
+// kept beside the boxes mark the entities they touch; see markDirty.
+// Code that changes a saved field through a read-only rp_* pointer
+// must mark the entity itself. With checkSaves set, every save also
+// builds and hashes every row and fails if an entity's rows changed
+// without it being marked. That check costs a pass over the world and
+// is meant for tests.
 func (e *Engine) SaveWorld() error {
+	return e.saveWorld(e.dirty == nil, nil)
+}
+
+// saveWorldFull clears the entity tables and writes every entity.
+func (e *Engine) saveWorldFull() error {
+	return e.saveWorld(true, nil)
+}
+
+// markDirty records that entity n changed and must be written by the
+// next save. It does nothing until a world has been loaded or saved.
+func (e *Engine) markDirty(n int) {
+	if e.dirty != nil {
+		e.dirty[n] = true
+	}
+}
+
+// markCommandsDirty marks every entity holding a command. A saved
+// command carries the turn it was saved in, so all of them change when
+// the turn does.
+func (e *Engine) markCommandsDirty() {
+	for _, id := range e.boxIDs() {
+		if e.globals.bx.get(id).cmd != nil {
+			e.markDirty(id)
+		}
+	}
+}
+
+// saveWorld writes the world in one transaction. A full save clears the
+// entity tables and writes every entity; otherwise only the dirty
+// entities are written. also, if not nil, writes more in the same
+// transaction.
+func (e *Engine) saveWorld(full bool, also func(tx *sql.Tx) error) error {
 	tx, err := e.db.Begin()
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	// Clear existing data (in reverse order of foreign key dependencies)
-	if err := e.clearDBTables(tx); err != nil {
-		return fmt.Errorf("clear tables: %w", err)
+	// Reports and orders refer to players and characters that may be
+	// deleted and written again; check them at commit instead.
+	if _, err := tx.Exec("PRAGMA defer_foreign_keys = ON"); err != nil {
+		return fmt.Errorf("defer foreign keys: %w", err)
 	}
 
-	// Save entities
-	if err := e.saveEntities(tx); err != nil {
-		return fmt.Errorf("save entities: %w", err)
+	if full {
+		if err := e.clearDBTables(tx); err != nil {
+			return fmt.Errorf("clear tables: %w", err)
+		}
 	}
 
-	// Save locations
-	if err := e.saveLocations(tx); err != nil {
-		return fmt.Errorf("save locations: %w", err)
+	w := newSaveWriter(tx)
+	defer w.close()
+
+	if !full && e.savedTurn != e.globals.sysclock.turn {
+		e.markCommandsDirty()
 	}
 
-	// Save players (before characters due to FK)
-	if err := e.savePlayers(tx); err != nil {
-		return fmt.Errorf("save players: %w", err)
+	c := e.newSaveContext()
+	var ids []int
+	if full {
+		ids = e.boxIDs()
+	} else {
+		ids = slices.Sorted(maps.Keys(e.dirty))
 	}
 
-	// Save characters
-	if err := e.saveCharacters(tx); err != nil {
-		return fmt.Errorf("save characters: %w", err)
+	for _, g := range saveGroups {
+		for _, id := range ids {
+			rows := g.rows(e, c, id)
+			for i, t := range g.tables {
+				if err := w.write(t, id, tableRows(rows, i), !full); err != nil {
+					return fmt.Errorf("save %s: %w", t.name, err)
+				}
+			}
+		}
 	}
 
-	// Save character magic
-	if err := e.saveCharMagic(tx); err != nil {
-		return fmt.Errorf("save char_magic: %w", err)
+	var saved map[*saveTable]map[int]saveDigest
+	if e.checkSaves {
+		saved = e.digestWorld(c)
+		if !full && e.saved != nil {
+			if err := e.checkDirty(saved); err != nil {
+				return err
+			}
+		}
 	}
 
-	// Save character skills
-	if err := e.saveCharSkills(tx); err != nil {
-		return fmt.Errorf("save char_skills: %w", err)
+	// Save system settings
+	if err := e.saveSystemConfig(tx); err != nil {
+		return fmt.Errorf("save system_config: %w", err)
+	}
+	// Save the random streams, so the next run doesn't repeat the draws
+	// made since the last save
+	if err := e.writePrngStreams(tx); err != nil {
+		return err
+	}
+	if also != nil {
+		if err := also(tx); err != nil {
+			return err
+		}
 	}
 
-	// Save item types
-	if err := e.saveItemTypes(tx); err != nil {
-		return fmt.Errorf("save item_types: %w", err)
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
 	}
 
-	// Save skills
-	if err := e.saveSkills(tx); err != nil {
-		return fmt.Errorf("save skills: %w", err)
+	e.trackSaved(saved)
+	return nil
+}
+
+// trackSaved starts tracking changes from the world just loaded or
+// saved, so the next SaveWorld writes only what changes from here.
+// saved holds the digests of the world for checkSaves; it is built
+// here when nil.
+func (e *Engine) trackSaved(saved map[*saveTable]map[int]saveDigest) {
+	e.dirty = make(map[int]bool)
+	e.savedTurn = e.globals.sysclock.turn
+	if e.checkSaves && saved == nil {
+		saved = e.digestWorld(e.newSaveContext())
 	}
+	e.saved = saved
+}
 
-	// Save gates
-	if err := e.saveGates(tx); err != nil {
-		return fmt.Errorf("save gates: %w", err)
+// digestWorld builds every row of every entity and returns their
+// digests by table and entity.
+func (e *Engine) digestWorld(c *saveContext) map[*saveTable]map[int]saveDigest {
+	saved := make(map[*saveTable]map[int]saveDigest)
+	ids := e.boxIDs()
+	for _, g := range saveGroups {
+		for _, t := range g.tables {
+			saved[t] = make(map[int]saveDigest)
+		}
+		for _, id := range ids {
+			rows := g.rows(e, c, id)
+			for i, t := range g.tables {
+				if args := tableRows(rows, i); len(args) != 0 {
+					saved[t][id] = digestRows(args)
+				}
+			}
+		}
 	}
+	return saved
+}
 
-	// Save storms
-	if err := e.saveStorms(tx); err != nil {
-		return fmt.Errorf("save storms: %w", err)
+// checkDirty compares the digests of the world with those of the last
+// save and reports the first entity whose rows changed without being
+// marked dirty.
+func (e *Engine) checkDirty(saved map[*saveTable]map[int]saveDigest) error {
+	for _, g := range saveGroups {
+		for _, t := range g.tables {
+			ids := slices.Collect(maps.Keys(saved[t]))
+			ids = append(ids, slices.Collect(maps.Keys(e.saved[t]))...)
+			slices.Sort(ids)
+			for _, id := range slices.Compact(ids) {
+				d, ok := saved[t][id]
+				prev, existed := e.saved[t][id]
+				if (ok != existed || d != prev) && !e.dirty[id] {
+					return fmt.Errorf("save %s: entity %d changed without being marked dirty", t.name, id)
+				}
+			}
+		}
 	}
+	return nil
+}
 
-	// Save ships
-	if err := e.saveShips(tx); err != nil {
-		return fmt.Errorf("save ships: %w", err)
+// saveTable describes one table written by SaveWorld. The first column
+// holds the owning entity. A keyed table has at most one row per
+// entity and is updated in place; the rows of other tables are deleted
+// and inserted again when the entity changes.
+type saveTable struct {
+	name  string
+	cols  []string
+	keyed bool
+	owner func(id int) any // first column value for entity id, nil for id itself
+}
+
+// insertSQL returns the statement writing one row of the table.
+func (t *saveTable) insertSQL() string {
+	q := "INSERT INTO " + t.name + " (" + strings.Join(t.cols, ", ") + ")" +
+		" VALUES (?" + strings.Repeat(", ?", len(t.cols)-1) + ")"
+	if t.keyed {
+		var set []string
+		for _, col := range t.cols[1:] {
+			set = append(set, col+" = excluded."+col)
+		}
+		q += " ON CONFLICT (" + t.cols[0] + ") DO UPDATE SET " + strings.Join(set, ", ")
 	}
+	return q
+}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
+// deleteSQL returns the statement removing all rows of an entity.
+func (t *saveTable) deleteSQL() string {
+	return "DELETE FROM " + t.name + " WHERE " + t.cols[0] + " = ?"
+}
+
+// ownerKey returns the first column value for entity id.
+func (t *saveTable) ownerKey(id int) any {
+	if t.owner != nil {
+		return t.owner(id)
 	}
+	return id
+}
 
-	return nil
+// saveRow is one row to be written to tables[table] of a saveGroup.
+type saveRow struct {
+	table int
+	args  []any
 }
 
-// clearDBTables clears all entity-related tables in reverse FK order.
-func (e *Engine) clearDBTables(tx *sql.Tx) error {
-	tables := []string{
-		"char_skills",
-		"char_magic",
-		"ships",
-		"storms",
-		"gates",
-		"characters",
-		"players",
-		"locations",
-		"entities",
-		"item_types",
-		"skills",
+// saveGroup is a set of tables filled from the same part of an entity,
+// parent table first. A dirty entity has the rows of every group built
+// and written again.
+type saveGroup struct {
+	tables []*saveTable
+	rows   func(e *Engine, c *saveContext, id int) []saveRow
+}
+
+// saveDigest summarizes the rows a table holds for one entity.
+type saveDigest [16]byte
+
+// tableRows returns the arguments of the rows for table i.
+func tableRows(rows []saveRow, i int) [][]any {
+	var args [][]any
+	for _, r := range rows {
+		if r.table == i {
+			args = append(args, r.args)
+		}
 	}
+	return args
+}
 
-	for _, table := range tables {
-		if _, err := tx.Exec("DELETE FROM " + table); err != nil {
-			return fmt.Errorf("delete from %s: %w", table, err)
+// digestRows returns the digest of the rows of one table.
+func digestRows(rows [][]any) saveDigest {
+	h := fnv.New128a()
+	var buf []byte
+	for _, args := range rows {
+		buf = buf[:0]
+		for _, a := range args {
+			switch v := a.(type) {
+			case int:
+				buf = append(strconv.AppendInt(append(buf, 'i'), int64(v), 10), ' ')
+			case string:
+				buf = append(strconv.AppendQuote(append(buf, 's'), v), ' ')
+			case sql.NullInt64:
+				if v.Valid {
+					buf = append(strconv.AppendInt(append(buf, 'i'), v.Int64, 10), ' ')
+				} else {
+					buf = append(buf, "null "...)
+				}
+			case sql.NullString:
+				if v.Valid {
+					buf = append(strconv.AppendQuote(append(buf, 's'), v.String), ' ')
+				} else {
+					buf = append(buf, "null "...)
+				}
+			default:
+				buf = fmt.Appendf(buf, "%#v ", v)
+			}
 		}
+		h.Write(append(buf, '\n'))
 	}
+	var d saveDigest
+	h.Sum(d[:0])
+	return d
+}
 
-	return nil
+// saveContext holds lookups shared by the row builders of one save.
+type saveContext struct {
+	unformedBy map[int]int // unformed noble -> player holding it
 }
 
-// saveEntities saves all boxes to the entities table.
-func (e *Engine) saveEntities(tx *sql.Tx) error {
-	stmt, err := tx.Prepare(`
-		INSERT INTO entities (id, kind, subkind, name, parent_loc_id)
-		VALUES (?, ?, ?, ?, ?)
-	`)
+func (e *Engine) newSaveContext() *saveContext {
+	c := &saveContext{unformedBy: make(map[int]int)}
+	for _, pl := range e.Players() {
+		for _, n := range e.globals.playerUnits[pl+100_000] {
+			c.unformedBy[n] = pl
+		}
+	}
+	return c
+}
+
+// saveWriter executes the statements of one save, preparing each once.
+type saveWriter struct {
+	tx      *sql.Tx
+	inserts map[*saveTable]*sql.Stmt
+	deletes map[*saveTable]*sql.Stmt
+}
+
+func newSaveWriter(tx *sql.Tx) *saveWriter {
+	return &saveWriter{
+		tx:      tx,
+		inserts: make(map[*saveTable]*sql.Stmt),
+		deletes: make(map[*saveTable]*sql.Stmt),
+	}
+}
+
+// stmt returns the statement cached for t, preparing it on first use.
+func (w *saveWriter) stmt(cache map[*saveTable]*sql.Stmt, t *saveTable, query func() string) (*sql.Stmt, error) {
+	if stmt, ok := cache[t]; ok {
+		return stmt, nil
+	}
+	stmt, err := w.tx.Prepare(query())
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer stmt.Close()
+	cache[t] = stmt
+	return stmt, nil
+}
 
-	for id := 1; id < MAX_BOXES; id++ {
-		b := e.globals.bx[id]
-		if b == nil {
-			continue
-		}
+func (w *saveWriter) close() {
+	for _, stmt := range w.inserts {
+		stmt.Close()
+	}
+	for _, stmt := range w.deletes {
+		stmt.Close()
+	}
+}
 
-		var name sql.NullString
-		if n := e.globals.names[id]; n != "" {
-			name = sql.NullString{String: n, Valid: true}
+// write stores the rows of entity id in table t. When the entity may
+// have been saved before, the rows of an unkeyed table are deleted
+// first, as is the row of a keyed table that the entity no longer has.
+func (w *saveWriter) write(t *saveTable, id int, rows [][]any, existed bool) error {
+	if existed && (!t.keyed || len(rows) == 0) {
+		stmt, err := w.stmt(w.deletes, t, t.deleteSQL)
+		if err != nil {
+			return err
 		}
-
-		var parentLocID sql.NullInt64
-		if b.x_loc_info.where > 0 {
-			parentLocID = sql.NullInt64{Int64: int64(b.x_loc_info.where), Valid: true}
+		if _, err := stmt.Exec(t.ownerKey(id)); err != nil {
+			return fmt.Errorf("delete %d: %w", id, err)
 		}
+	}
 
-		if _, err := stmt.Exec(id, int(b.kind), int(b.skind), name, parentLocID); err != nil {
-			return fmt.Errorf("insert entity %d: %w", id, err)
+	for _, args := range rows {
+		stmt, err := w.stmt(w.inserts, t, t.insertSQL)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.Exec(args...); err != nil {
+			return fmt.Errorf("insert %d: %w", id, err)
 		}
 	}
 
 	return nil
 }
 
-// saveLocations saves location data to the locations table.
-func (e *Engine) saveLocations(tx *sql.Tx) error {
-	stmt, err := tx.Prepare(`
-		INSERT INTO locations (id, region_id, province_id, parent_loc_id, terrain_subkind,
-		                       barrier, shroud, civ, sea_lane, is_safe_haven)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`)
-	if err != nil {
-		return err
+// saveGroups lists what SaveWorld writes for each entity, in foreign
+// key order. Deferred foreign keys let a group refer to rows written by
+// a later one.
+var saveGroups = []*saveGroup{
+	{
+		tables: []*saveTable{
+			{name: "entities", keyed: true, cols: []string{"id", "kind", "subkind", "name", "display_name", "parent_loc_id", "owner_player_id"}},
+		},
+		rows: (*Engine).entityRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "locations", keyed: true, cols: []string{"id", "region_id", "province_id", "parent_loc_id", "terrain_subkind",
+				"barrier", "shroud", "civ", "sea_lane", "is_safe_haven", "is_start_loc",
+				"quest_late", "uldim_flag", "summer_flag", "link_when", "link_open"}},
+			{name: "loc_links", cols: []string{"loc_id", "seq", "dest_id"}},
+		},
+		rows: (*Engine).locationRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "players", keyed: true, cols: []string{"id", "account_id", "code", "name", "subkind", "email", "vis_email",
+				"full_name", "noble_points", "fast_study", "first_turn",
+				"last_order_turn", "report_format", "notab", "last_email",
+				"split_lines", "split_bytes", "sent_orders", "dont_remind",
+				"first_tower", "compuserve", "broken_mailer"}},
+			// Order passwords live in the passwords table, checked by the
+			// BEGIN line of emailed orders.
+			{name: "passwords", keyed: true, cols: []string{"key", "value"},
+				owner: func(id int) any { return player_password_key(id) }},
+			{name: "player_knowledge", cols: []string{"player_id", "entity_id"}},
+			{name: "player_units", cols: []string{"player_id", "kind", "seq", "unit_id"}},
+		},
+		rows: (*Engine).playerRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "characters", keyed: true, cols: []string{"id", "player_id", "loc_id", "health", "sick", "loy_kind", "loy_rate",
+				"unit_item", "guard", "npc_prog", "moving_since", "gone_flag",
+				"is_npc", "is_dead", "only_vuln"}},
+		},
+		rows: (*Engine).characterRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "char_magic", keyed: true, cols: []string{"char_id", "pray", "hide_self", "vis_protect", "hide_mage",
+				"cur_aura", "max_aura", "aura_reflect", "pledge", "auraculum",
+				"ability_shroud", "fee", "ferry_flag", "magician", "token"}},
+			{name: "char_visions", cols: []string{"char_id", "target_id"}},
+		},
+		rows: (*Engine).charMagicRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "char_skills", cols: []string{"char_id", "skill_id", "level", "experience", "know"}},
+		},
+		rows: (*Engine).charSkillRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "item_types", keyed: true, cols: []string{"id", "subkind", "name", "weight", "is_animal", "prominent", "who_has"}},
+		},
+		rows: (*Engine).itemTypeRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "item_magic", keyed: true, cols: []string{"item_id", "creator", "region_created", "lore",
+				"curse_loyalty", "cloak_region", "cloak_creator", "use_key",
+				"project_cast", "token_ni", "quick_cast",
+				"aura_bonus", "aura", "relic_decay",
+				"attack_bonus", "defense_bonus", "missile_bonus",
+				"token_num", "orb_use_count"}},
+			{name: "item_magic_skills", cols: []string{"item_id", "kind", "seq", "skill_id"}},
+		},
+		rows: (*Engine).itemMagicRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "inventories", cols: []string{"owner_entity_id", "item_id", "qty"}},
+		},
+		rows: (*Engine).inventoryRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "skills", keyed: true, cols: []string{"id", "name", "category", "is_magic"}},
+		},
+		rows: (*Engine).skillRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "dead_bodies", keyed: true, cols: []string{"item_id", "save_name", "old_lord", "prev_lord",
+				"death_turn", "death_day", "attack", "defense", "missile"}},
+			{name: "dead_body_skills", cols: []string{"item_id", "skill_id", "level", "experience"}},
+		},
+		rows: (*Engine).deadBodyRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "gates", keyed: true, cols: []string{"id", "from_loc_id", "to_loc_id", "road_hidden"}},
+		},
+		rows: (*Engine).gateRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "storms", keyed: true, cols: []string{"id", "strength", "moving_to", "moving_since"}},
+		},
+		rows: (*Engine).stormRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "ships", keyed: true, cols: []string{"id", "loc_id", "capacity", "storm_bind", "moving_since"}},
+		},
+		rows: (*Engine).shipRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "player_admits", cols: []string{"player_id", "targ_id", "sense"}},
+			{name: "player_admit_ents", cols: []string{"player_id", "targ_id", "seq", "ent_id"}},
+		},
+		rows: (*Engine).admitRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "attitudes", cols: []string{"ent_id", "target_id", "disp"}},
+		},
+		rows: (*Engine).attitudeRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "commands", keyed: true, cols: []string{"who_id", "turn_number", "cmd_code", "use_skill", "use_ent", "use_exp",
+				"days_executing", "state", "status", "poll", "pri", "conditional",
+				"inhibit_finish", "wait", "second_wait", "fuzzy",
+				"args_json", "parse_json", "wait_parse_json", "raw_line"}},
+		},
+		rows: (*Engine).commandRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "sublocs", keyed: true, cols: []string{"id", "opium_econ", "defense", "loot", "damage", "galley_ram",
+				"shaft_depth", "castle_lev", "build_materials", "effort_required", "effort_given",
+				"moving", "capacity", "safe", "major", "prominence", "uldim_flag", "summer_flag",
+				"quest_late", "tunnel_level", "link_when", "link_open"}},
+			{name: "subloc_lists", cols: []string{"subloc_id", "list", "seq", "value"}},
+		},
+		rows: (*Engine).sublocRows,
+	},
+	{
+		tables: []*saveTable{
+			{name: "entity_misc", keyed: true, cols: []string{"id", "npc_created", "npc_home", "npc_cookie", "summoned_by",
+				"save_name", "old_lord", "only_vuln", "garr_castle", "bind_storm",
+				"storm_str", "npc_dir", "mine_delay", "cmd_allow"}},
+			{name: "npc_memory", cols: []string{"entity_id", "known_id"}},
+		},
+		rows: (*Engine).miscRows,
+	},
+}
+
+// admitRows returns a player's ADMIT declarations for the
+// player_admits and player_admit_ents tables.
+func (e *Engine) admitRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil || b.kind != T_player || b.x_player == nil {
+		return nil
 	}
-	defer stmt.Close()
 
-	for id := 1; id < MAX_BOXES; id++ {
-		b := e.globals.bx[id]
-		if b == nil || b.kind != T_loc {
-			continue
+	var rows []saveRow
+	for _, a := range b.x_player.admits {
+		rows = append(rows, saveRow{0, []any{id, a.targ, a.sense}})
+		for seq, n := range a.l.Values() {
+			rows = append(rows, saveRow{1, []any{id, a.targ, seq, n}})
+		}
+	}
+	return rows
+}
+
+// attitudeRows returns declared neutral, hostile and defend lists for
+// the attitudes table.
+func (e *Engine) attitudeRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil || b.x_disp == nil {
+		return nil
+	}
+
+	var rows []saveRow
+	for _, l := range []struct {
+		disp    int
+		targets []int
+	}{
+		{NEUTRAL, b.x_disp.neutral.Values()},
+		{HOSTILE, b.x_disp.hostile.Values()},
+		{DEFEND, b.x_disp.defend.Values()},
+	} {
+		for _, target := range l.targets {
+			rows = append(rows, saveRow{0, []any{id, target, l.disp}})
 		}
+	}
+	return rows
+}
+
+// sublocRows returns the sublocs row of an entity with an
+// entity_subloc and its lists for the subloc_lists table.
+func (e *Engine) sublocRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil || b.x_subloc == nil {
+		return nil
+	}
 
-		var regionID, provinceID, parentLocID sql.NullInt64
-		if b.x_loc_info.where > 0 {
-			parentLocID = sql.NullInt64{Int64: int64(b.x_loc_info.where), Valid: true}
+	sl := b.x_subloc
+	rows := []saveRow{{0, []any{id, sl.opium_econ, sl.defense, int(sl.loot), int(sl.damage), int(sl.galley_ram),
+		sl.shaft_depth, int(sl.castle_lev), sl.build_materials, sl.effort_required, sl.effort_given,
+		sl.moving, sl.capacity, int(sl.safe), int(sl.major), int(sl.prominence),
+		int(sl.uldim_flag), int(sl.summer_flag), int(sl.quest_late), int(sl.tunnel_level),
+		int(sl.link_when), int(sl.link_open)}}}
+	for _, l := range []struct {
+		name   string
+		values []int
+	}{
+		{"teaches", sl.teaches.Values()},
+		{"near_cities", sl.near_cities.Values()},
+		{"link_to", sl.link_to},
+		{"link_from", sl.link_from},
+		{"bound_storms", sl.bound_storms},
+	} {
+		for seq, n := range l.values {
+			rows = append(rows, saveRow{1, []any{id, l.name, seq, n}})
 		}
+	}
+	return rows
+}
 
-		barrier, shroud, civ, seaLane := 0, 0, 0, 0
-		safeHaven := 0
-		if b.x_loc != nil {
-			barrier = int(b.x_loc.barrier)
-			shroud = int(b.x_loc.shroud)
-			civ = int(b.x_loc.civ)
-			seaLane = int(b.x_loc.sea_lane)
+// miscRows returns the entity_misc row of an entity with an
+// entity_misc or the original name of a dead body, and the characters
+// an NPC remembers for the npc_memory table.
+func (e *Engine) miscRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil {
+		return nil
+	}
+
+	saveName := e.globals.savedNames[id]
+	m := b.x_misc
+	if m == nil {
+		if saveName == "" {
+			return nil
 		}
-		if b.x_subloc != nil && b.x_subloc.safe != 0 {
-			safeHaven = 1
+		m = &entity_misc{}
+	}
+	rows := []saveRow{{0, []any{id, m.npc_created, m.npc_home, m.npc_cookie, m.summoned_by,
+		nullString(saveName), m.old_lord, m.only_vuln, m.garr_castle, m.bind_storm,
+		m.storm_str, int(m.npc_dir), int(m.mine_delay), int(m.cmd_allow)}}}
+	for _, n := range slices.Sorted(maps.Keys(m.npc_memory)) {
+		if m.npc_memory[n] {
+			rows = append(rows, saveRow{1, []any{id, n}})
 		}
+	}
+	return rows
+}
 
-		if _, err := stmt.Exec(id, regionID, provinceID, parentLocID, int(b.skind),
-			barrier, shroud, civ, seaLane, safeHaven); err != nil {
-			return fmt.Errorf("insert location %d: %w", id, err)
+// waitArgJSON is one condition of a WAIT order in
+// commands.wait_parse_json.
+type waitArgJSON struct {
+	Tag  int    `json:"tag"`
+	A1   int    `json:"a1"`
+	A2   int    `json:"a2"`
+	Flag string `json:"flag,omitempty"`
+}
+
+// commandRows returns the commands row of a unit with an order loaded
+// or running. Long orders carry across turns through it, and a turn
+// saved mid-month resumes with the unit's order where it left off.
+func (e *Engine) commandRows(c *saveContext, id int) []saveRow {
+	cmd := e.rp_command(id)
+	if cmd == nil || (cmd.state != STATE_LOAD && cmd.state != STATE_RUN) {
+		return nil
+	}
+
+	args, _ := json.Marshal([]int{cmd.a, cmd.b, cmd.c, cmd.d, cmd.e, cmd.f, cmd.g, cmd.h})
+	var parse sql.NullString
+	if len(cmd.parse) != 0 {
+		buf, _ := json.Marshal(cmd.parse)
+		parse = nullString(string(buf))
+	}
+	var waits sql.NullString
+	if l := e.getWaitParse(cmd); len(l) != 0 {
+		w := make([]waitArgJSON, len(l))
+		for i, p := range l {
+			w[i] = waitArgJSON{Tag: p.tag, A1: p.a1, A2: p.a2, Flag: p.flagStr}
 		}
+		buf, _ := json.Marshal(w)
+		waits = nullString(string(buf))
 	}
 
-	return nil
+	return []saveRow{{0, []any{id, int(e.globals.sysclock.turn), cmd.cmd, cmd.use_skill, cmd.use_ent, cmd.use_exp,
+		cmd.days_executing, int(cmd.state), int(cmd.status), int(cmd.poll), int(cmd.pri), int(cmd.conditional),
+		int(cmd.inhibit_finish), cmd.wait, int(cmd.second_wait), int(cmd.fuzzy),
+		string(args), parse, waits, cmd.line}}}
 }
 
-// saveCharacters saves character data to the characters table.
-func (e *Engine) saveCharacters(tx *sql.Tx) error {
+// saveSystemConfig records the special locations, settings and game
+// clock the C game kept in its system file. Keys are replaced rather
+// than cleared so settings written by other tools survive.
+func (e *Engine) saveSystemConfig(tx *sql.Tx) error {
 	stmt, err := tx.Prepare(`
-		INSERT INTO characters (id, player_id, loc_id, health, sick, loy_kind, loy_rate,
-		                        unit_item, guard, npc_prog, moving_since, gone_flag,
-		                        is_npc, is_dead)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT OR REPLACE INTO system_config (key, value) VALUES (?, ?)
 	`)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	for id := 1; id < MAX_BOXES; id++ {
-		b := e.globals.bx[id]
-		if b == nil || b.kind != T_char {
-			continue
+	settings := []struct {
+		key   string
+		value int
+	}{
+		{"nowhere_region", e.globals.nowhereRegion},
+		{"nowhere_loc", e.globals.nowhereLoc},
+		{"auto_quit_turns", e.globals.autoQuitTurns},
+	}
+
+	for _, s := range settings {
+		if _, err := stmt.Exec(s.key, strconv.Itoa(s.value)); err != nil {
+			return fmt.Errorf("insert system_config %s: %w", s.key, err)
 		}
+	}
 
-		var playerID, locID sql.NullInt64
-		var health, sick int
-		var loyKind, loyRate, unitItem, guard sql.NullInt64
-		var npcProg, movingSince, goneFlag sql.NullInt64
-		var isNPC, isDead int
+	// The game clock, read back by loadSystemConfig
+	if _, err := tx.Exec(`
+		INSERT INTO game_meta (id, game_name, current_turn) VALUES (1, 'taygete', ?)
+		ON CONFLICT (id) DO UPDATE SET current_turn = excluded.current_turn
+	`, int(e.globals.sysclock.turn)); err != nil {
+		return fmt.Errorf("save game clock: %w", err)
+	}
+	// Saved commands refer to the turn on the clock
+	if _, err := tx.Exec(`
+		INSERT OR IGNORE INTO turns (turn_number) SELECT DISTINCT turn_number FROM commands
+	`); err != nil {
+		return fmt.Errorf("insert command turns: %w", err)
+	}
 
-		if b.x_loc_info.where > 0 {
-			locID = sql.NullInt64{Int64: int64(b.x_loc_info.where), Valid: true}
-		}
+	return nil
+}
 
-		if b.x_char != nil {
-			ch := b.x_char
-			health = int(ch.health)
-			sick = int(ch.sick)
+// clearDBTables clears all entity-related tables in reverse FK order.
+func (e *Engine) clearDBTables(tx *sql.Tx) error {
+	tables := []string{
+		"npc_memory",
+		"entity_misc",
+		"subloc_lists",
+		"sublocs",
+		"commands",
+		"attitudes",
+		"player_admit_ents",
+		"player_admits",
+		"loc_links",
+		"dead_body_skills",
+		"dead_bodies",
+		"inventories",
+		"item_magic_skills",
+		"item_magic",
+		"char_skills",
+		"char_visions",
+		"char_magic",
+		"ships",
+		"storms",
+		"gates",
+		"characters",
+		"player_units",
+		"player_knowledge",
+		"players",
+		"locations",
+		"entities",
+		"item_types",
+		"skills",
+	}
 
-			if ch.loy_kind != 0 {
-				loyKind = sql.NullInt64{Int64: int64(ch.loy_kind), Valid: true}
-			}
-			if ch.loy_rate != 0 {
-				loyRate = sql.NullInt64{Int64: int64(ch.loy_rate), Valid: true}
-			}
-			if ch.unit_item != 0 {
-				unitItem = sql.NullInt64{Int64: int64(ch.unit_item), Valid: true}
-			}
-			if ch.guard != 0 {
-				guard = sql.NullInt64{Int64: int64(ch.guard), Valid: true}
-			}
-			if ch.npc_prog != 0 {
-				npcProg = sql.NullInt64{Int64: int64(ch.npc_prog), Valid: true}
-			}
-			if ch.moving != 0 {
-				movingSince = sql.NullInt64{Int64: int64(ch.moving), Valid: true}
-			}
-			if ch.unit_lord > 0 {
-				playerID = sql.NullInt64{Int64: int64(ch.unit_lord), Valid: true}
-			}
+	for _, table := range tables {
+		if _, err := tx.Exec("DELETE FROM " + table); err != nil {
+			return fmt.Errorf("delete from %s: %w", table, err)
 		}
+	}
 
-		if _, err := stmt.Exec(id, playerID, locID, health, sick,
-			loyKind, loyRate, unitItem, guard, npcProg,
-			movingSince, goneFlag, isNPC, isDead); err != nil {
-			return fmt.Errorf("insert character %d: %w", id, err)
-		}
+	if _, err := tx.Exec(`DELETE FROM passwords WHERE key LIKE 'player:%'`); err != nil {
+		return fmt.Errorf("delete player passwords: %w", err)
 	}
 
 	return nil
 }
 
-// saveCharMagic saves character magic data to the char_magic table.
-func (e *Engine) saveCharMagic(tx *sql.Tx) error {
-	stmt, err := tx.Prepare(`
-		INSERT INTO char_magic (char_id, pray, hide_self, vis_protect, hide_mage,
-		                        cur_aura, max_aura, aura_reflect, pledge, auraculum,
-		                        ability_shroud, fee, ferry_flag)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`)
-	if err != nil {
-		return err
+// entityRows returns the entities row of a box.
+func (e *Engine) entityRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil {
+		return nil
 	}
-	defer stmt.Close()
 
-	for id := 1; id < MAX_BOXES; id++ {
-		b := e.globals.bx[id]
-		if b == nil || b.kind != T_char || b.x_char == nil || b.x_char.x_char_magic == nil {
-			continue
-		}
+	var name sql.NullString
+	if n := e.globals.names[id]; n != "" {
+		name = sql.NullString{String: n, Valid: true}
+	}
 
-		m := b.x_char.x_char_magic
+	var parentLocID sql.NullInt64
+	if b.x_loc_info.where > 0 {
+		parentLocID = sql.NullInt64{Int64: int64(b.x_loc_info.where), Valid: true}
+	}
 
-		var pledge, auraculum sql.NullInt64
-		if m.pledge != 0 {
-			pledge = sql.NullInt64{Int64: int64(m.pledge), Valid: true}
-		}
-		if m.auraculum != 0 {
-			auraculum = sql.NullInt64{Int64: int64(m.auraculum), Valid: true}
-		}
+	// Unformed nobles are owned by the player holding them
+	var ownerID sql.NullInt64
+	if pl := c.unformedBy[id]; pl != 0 {
+		ownerID = sql.NullInt64{Int64: int64(pl), Valid: true}
+	}
+
+	return []saveRow{{0, []any{id, int(b.kind), int(b.skind), name, nullString(e.globals.banners[id]), parentLocID, ownerID}}}
+}
+
+// locationRows returns the locations and loc_links rows of a location.
+func (e *Engine) locationRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil || b.kind != T_loc {
+		return nil
+	}
+
+	var regionID, provinceID, parentLocID sql.NullInt64
+	if b.x_loc_info.where > 0 {
+		parentLocID = sql.NullInt64{Int64: int64(b.x_loc_info.where), Valid: true}
+	}
 
-		if _, err := stmt.Exec(id, int(m.pray), int(m.hide_self), int(m.vis_protect),
-			int(m.hide_mage), m.cur_aura, m.max_aura, int(m.aura_reflect),
-			pledge, auraculum, int(m.ability_shroud), m.fee, int(m.ferry_flag)); err != nil {
-			return fmt.Errorf("insert char_magic %d: %w", id, err)
+	barrier, shroud, civ, seaLane := 0, 0, 0, 0
+	safeHaven, questLate := 0, 0
+	uldimFlag, summerFlag, linkWhen, linkOpen := 0, 0, 0, 0
+	if b.x_loc != nil {
+		barrier = b.x_loc.barrier
+		shroud = int(b.x_loc.shroud)
+		civ = int(b.x_loc.civ)
+		seaLane = int(b.x_loc.sea_lane)
+	}
+	if b.x_subloc != nil && b.x_subloc.safe != 0 {
+		safeHaven = 1
+	}
+	if sl := b.x_subloc; sl != nil {
+		questLate = int(sl.quest_late)
+		uldimFlag = int(sl.uldim_flag)
+		summerFlag = int(sl.summer_flag)
+		linkWhen = int(sl.link_when)
+		linkOpen = int(sl.link_open)
+	}
+
+	startLoc := 0
+	if e.globals.startLocs[id] {
+		startLoc = 1
+	}
+
+	rows := []saveRow{{0, []any{id, regionID, provinceID, parentLocID, int(b.skind),
+		barrier, shroud, civ, seaLane, safeHaven, startLoc, questLate,
+		uldimFlag, summerFlag, linkWhen, linkOpen}}}
+
+	if b.x_subloc != nil {
+		for seq, dest := range b.x_subloc.link_to {
+			rows = append(rows, saveRow{1, []any{id, seq, dest}})
 		}
 	}
 
-	return nil
+	return rows
 }
 
-// savePlayers saves player data to the players table.
-func (e *Engine) savePlayers(tx *sql.Tx) error {
-	stmt, err := tx.Prepare(`
-		INSERT INTO players (id, code, name, subkind)
-		VALUES (?, ?, ?, ?)
-	`)
-	if err != nil {
-		return err
+// characterRows returns the characters row of a character.
+func (e *Engine) characterRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil || b.kind != T_char {
+		return nil
 	}
-	defer stmt.Close()
 
-	for id := 1; id < MAX_BOXES; id++ {
-		b := e.globals.bx[id]
-		if b == nil || b.kind != T_player {
-			continue
-		}
+	var playerID, locID sql.NullInt64
+	var health, sick int
+	var loyKind, loyRate, unitItem, guard sql.NullInt64
+	var npcProg, movingSince, goneFlag sql.NullInt64
+	var isNPC, isDead int
+	var onlyVuln sql.NullInt64
 
-		code := int_to_code(id)
-		var name sql.NullString
-		if n := e.globals.names[id]; n != "" {
-			name = sql.NullString{String: n, Valid: true}
-		}
+	if b.x_loc_info.where > 0 {
+		locID = sql.NullInt64{Int64: int64(b.x_loc_info.where), Valid: true}
+	}
+
+	if b.x_char != nil {
+		ch := b.x_char
+		health = int(ch.health)
+		sick = int(ch.sick)
 
-		if _, err := stmt.Exec(id, code, name, int(b.skind)); err != nil {
-			return fmt.Errorf("insert player %d: %w", id, err)
+		if ch.loy_kind != 0 {
+			loyKind = sql.NullInt64{Int64: int64(ch.loy_kind), Valid: true}
+		}
+		if ch.loy_rate != 0 {
+			loyRate = sql.NullInt64{Int64: int64(ch.loy_rate), Valid: true}
+		}
+		if ch.unit_item != 0 {
+			unitItem = sql.NullInt64{Int64: int64(ch.unit_item), Valid: true}
+		}
+		if ch.guard != 0 {
+			guard = sql.NullInt64{Int64: int64(ch.guard), Valid: true}
+		}
+		if ch.npc_prog != 0 {
+			npcProg = sql.NullInt64{Int64: int64(ch.npc_prog), Valid: true}
+		}
+		if ch.moving != 0 {
+			movingSince = sql.NullInt64{Int64: int64(ch.moving), Valid: true}
+		}
+		if ch.unit_lord > 0 {
+			playerID = sql.NullInt64{Int64: int64(ch.unit_lord), Valid: true}
 		}
 	}
 
-	return nil
+	if b.x_misc != nil && b.x_misc.only_vuln != 0 {
+		onlyVuln = sql.NullInt64{Int64: int64(b.x_misc.only_vuln), Valid: true}
+	}
+
+	return []saveRow{{0, []any{id, playerID, locID, health, sick,
+		loyKind, loyRate, unitItem, guard, npcProg,
+		movingSince, goneFlag, isNPC, isDead, onlyVuln}}}
 }
 
-// saveGates saves gate data to the gates table.
-func (e *Engine) saveGates(tx *sql.Tx) error {
-	stmt, err := tx.Prepare(`
-		INSERT INTO gates (id, from_loc_id, to_loc_id, road_hidden)
-		VALUES (?, ?, ?, ?)
-	`)
-	if err != nil {
-		return err
+// charMagicRows returns the char_magic row of a character and the
+// targets of its received visions for the char_visions table.
+func (e *Engine) charMagicRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil || b.kind != T_char || b.x_char == nil || b.x_char.x_char_magic == nil {
+		return nil
 	}
-	defer stmt.Close()
 
-	for id := 1; id < MAX_BOXES; id++ {
-		b := e.globals.bx[id]
-		if b == nil || b.kind != T_gate {
-			continue
-		}
+	m := b.x_char.x_char_magic
 
-		fromLocID := b.x_loc_info.where
-		toLocID := 0
-		roadHidden := 0
+	var pledge, auraculum, token sql.NullInt64
+	if m.pledge != 0 {
+		pledge = sql.NullInt64{Int64: int64(m.pledge), Valid: true}
+	}
+	if m.auraculum != 0 {
+		auraculum = sql.NullInt64{Int64: int64(m.auraculum), Valid: true}
+	}
+	if m.token != 0 {
+		token = sql.NullInt64{Int64: int64(m.token), Valid: true}
+	}
 
-		if b.x_gate != nil {
-			toLocID = b.x_gate.to_loc
-			roadHidden = int(b.x_gate.road_hidden)
-		}
+	rows := []saveRow{{0, []any{id, int(m.pray), int(m.hide_self), int(m.vis_protect),
+		int(m.hide_mage), m.cur_aura, m.max_aura, int(m.aura_reflect),
+		pledge, auraculum, int(m.ability_shroud), m.fee, int(m.ferry_flag),
+		int(m.magician), token}}}
 
-		if _, err := stmt.Exec(id, fromLocID, toLocID, roadHidden); err != nil {
-			return fmt.Errorf("insert gate %d: %w", id, err)
+	for _, target := range slices.Sorted(maps.Keys(m.visions)) {
+		if m.visions[target] {
+			rows = append(rows, saveRow{1, []any{id, target}})
 		}
 	}
 
-	return nil
+	return rows
 }
 
-// saveStorms saves storm data to the storms table.
-func (e *Engine) saveStorms(tx *sql.Tx) error {
-	stmt, err := tx.Prepare(`
-		INSERT INTO storms (id, strength, moving_to, moving_since)
-		VALUES (?, ?, ?, ?)
-	`)
-	if err != nil {
-		return err
+// playerRows returns the players row of a player, its order password,
+// if one is set, what it knows and its units and unformed nobles.
+func (e *Engine) playerRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil || b.kind != T_player {
+		return nil
 	}
-	defer stmt.Close()
 
-	for id := 1; id < MAX_BOXES; id++ {
-		b := e.globals.bx[id]
-		if b == nil || b.kind != T_storm {
-			continue
-		}
+	code := int_to_code(id)
+	var name sql.NullString
+	if n := e.globals.names[id]; n != "" {
+		name = sql.NullString{String: n, Valid: true}
+	}
 
-		strength := 0
-		var movingTo, movingSince sql.NullInt64
+	p := b.x_player
+	if p == nil {
+		p = &entity_player{}
+	}
 
-		if b.x_misc != nil {
-			strength = int(b.x_misc.storm_str)
-			if b.x_misc.storm_move != 0 {
-				movingTo = sql.NullInt64{Int64: int64(b.x_misc.storm_move), Valid: true}
-			}
-		}
+	var account sql.NullInt64
+	if p.account_id != 0 {
+		account = sql.NullInt64{Int64: int64(p.account_id), Valid: true}
+	}
+
+	rows := []saveRow{{0, []any{id, account, code, name, int(b.skind),
+		nullString(p.email), nullString(p.vis_email), nullString(p.full_name),
+		int(p.noble_points), int(p.fast_study), p.first_turn,
+		p.last_order_turn, int(p.format), int(p.notab), nullString(p.last_email),
+		p.split_lines, p.split_bytes, int(p.sent_orders), int(p.dont_remind),
+		int(p.first_tower), int(p.compuserve), int(p.broken_mailer)}}}
 
-		if _, err := stmt.Exec(id, strength, movingTo, movingSince); err != nil {
-			return fmt.Errorf("insert storm %d: %w", id, err)
+	if p.password != "" {
+		rows = append(rows, saveRow{1, []any{player_password_key(id), p.password}})
+	}
+
+	known := e.getPlayerKnowledge(id)
+	for _, n := range slices.Sorted(maps.Keys(known)) {
+		if known[n] {
+			rows = append(rows, saveRow{2, []any{id, n}})
 		}
 	}
 
-	return nil
+	for seq, n := range e.globals.playerUnits[id] {
+		rows = append(rows, saveRow{3, []any{id, "unit", seq, n}})
+	}
+	for seq, n := range e.globals.playerUnits[id+100_000] {
+		rows = append(rows, saveRow{3, []any{id, "unformed", seq, n}})
+	}
+
+	return rows
 }
 
-// saveShips saves ship data to the ships table.
-func (e *Engine) saveShips(tx *sql.Tx) error {
-	stmt, err := tx.Prepare(`
-		INSERT INTO ships (id, loc_id, capacity, storm_bind, moving_since)
-		VALUES (?, ?, ?, ?, ?)
-	`)
-	if err != nil {
-		return err
+// player_password_key is the passwords table key for a player's
+// order password.
+func player_password_key(pl int) string {
+	return "player:" + int_to_code(pl)
+}
+
+// nullString stores an empty string as NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// gateRows returns the gates row of a gate.
+func (e *Engine) gateRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil || b.kind != T_gate {
+		return nil
 	}
-	defer stmt.Close()
 
-	for id := 1; id < MAX_BOXES; id++ {
-		b := e.globals.bx[id]
-		if b == nil || b.kind != T_ship {
-			continue
-		}
+	fromLocID := b.x_loc_info.where
+	toLocID := 0
+	roadHidden := 0
 
-		var locID, capacity, stormBind, movingSince sql.NullInt64
+	if b.x_gate != nil {
+		toLocID = b.x_gate.to_loc
+		roadHidden = int(b.x_gate.road_hidden)
+	}
 
-		if b.x_loc_info.where > 0 {
-			locID = sql.NullInt64{Int64: int64(b.x_loc_info.where), Valid: true}
-		}
+	return []saveRow{{0, []any{id, fromLocID, toLocID, roadHidden}}}
+}
 
-		if b.x_subloc != nil {
-			if b.x_subloc.capacity != 0 {
-				capacity = sql.NullInt64{Int64: int64(b.x_subloc.capacity), Valid: true}
-			}
-			if b.x_subloc.moving != 0 {
-				movingSince = sql.NullInt64{Int64: int64(b.x_subloc.moving), Valid: true}
-			}
-		}
+// stormRows returns the storms row of a storm.
+func (e *Engine) stormRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil || b.kind != T_storm {
+		return nil
+	}
 
-		if b.x_misc != nil && b.x_misc.bind_storm != 0 {
-			stormBind = sql.NullInt64{Int64: int64(b.x_misc.bind_storm), Valid: true}
-		}
+	strength := 0
+	var movingTo, movingSince sql.NullInt64
 
-		if _, err := stmt.Exec(id, locID, capacity, stormBind, movingSince); err != nil {
-			return fmt.Errorf("insert ship %d: %w", id, err)
+	if b.x_misc != nil {
+		strength = int(b.x_misc.storm_str)
+		if b.x_misc.storm_move != 0 {
+			movingTo = sql.NullInt64{Int64: int64(b.x_misc.storm_move), Valid: true}
 		}
 	}
 
-	return nil
+	return []saveRow{{0, []any{id, strength, movingTo, movingSince}}}
 }
 
-// saveItemTypes saves item type data to the item_types table.
-func (e *Engine) saveItemTypes(tx *sql.Tx) error {
-	stmt, err := tx.Prepare(`
-		INSERT INTO item_types (id, subkind, name, weight, is_animal, prominent)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`)
-	if err != nil {
-		return err
+// shipRows returns the ships row of a ship.
+func (e *Engine) shipRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil || b.kind != T_ship {
+		return nil
 	}
-	defer stmt.Close()
 
-	for id := 1; id < MAX_BOXES; id++ {
-		b := e.globals.bx[id]
-		if b == nil || b.kind != T_item {
-			continue
-		}
+	var locID, capacity, stormBind, movingSince sql.NullInt64
 
-		name := e.globals.names[id]
-		weight, isAnimal, prominent := 0, 0, 0
+	if b.x_loc_info.where > 0 {
+		locID = sql.NullInt64{Int64: int64(b.x_loc_info.where), Valid: true}
+	}
 
-		if b.x_item != nil {
-			weight = int(b.x_item.weight)
-			isAnimal = int(b.x_item.is_man_item)
-			prominent = int(b.x_item.prominent)
+	if b.x_subloc != nil {
+		if b.x_subloc.capacity != 0 {
+			capacity = sql.NullInt64{Int64: int64(b.x_subloc.capacity), Valid: true}
+		}
+		if b.x_subloc.moving != 0 {
+			movingSince = sql.NullInt64{Int64: int64(b.x_subloc.moving), Valid: true}
 		}
+	}
+
+	if b.x_misc != nil && b.x_misc.bind_storm != 0 {
+		stormBind = sql.NullInt64{Int64: int64(b.x_misc.bind_storm), Valid: true}
+	}
 
-		if _, err := stmt.Exec(id, int(b.skind), name, weight, isAnimal, prominent); err != nil {
-			return fmt.Errorf("insert item_type %d: %w", id, err)
+	return []saveRow{{0, []any{id, locID, capacity, stormBind, movingSince}}}
+}
+
+// itemTypeRows returns the item_types row of an item.
+func (e *Engine) itemTypeRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil || b.kind != T_item {
+		return nil
+	}
+
+	name := e.globals.names[id]
+	weight, isAnimal, prominent := 0, 0, 0
+	var whoHas sql.NullInt64
+
+	if b.x_item != nil {
+		weight = int(b.x_item.weight)
+		isAnimal = int(b.x_item.is_man_item)
+		prominent = int(b.x_item.prominent)
+		if b.x_item.who_has != 0 {
+			whoHas = sql.NullInt64{Int64: int64(b.x_item.who_has), Valid: true}
 		}
 	}
 
-	return nil
+	return []saveRow{{0, []any{id, int(b.skind), name, weight, isAnimal, prominent, whoHas}}}
 }
 
-// saveSkills saves skill data to the skills table.
-func (e *Engine) saveSkills(tx *sql.Tx) error {
-	stmt, err := tx.Prepare(`
-		INSERT INTO skills (id, name, category, is_magic)
-		VALUES (?, ?, ?, ?)
-	`)
-	if err != nil {
-		return err
+// itemMagicRows returns the item_magic row of a magical item and the
+// skills it grants for the item_magic_skills table.
+func (e *Engine) itemMagicRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil || b.kind != T_item || b.x_item == nil || b.x_item.x_item_magic == nil {
+		return nil
+	}
+
+	m := b.x_item.x_item_magic
+
+	rows := []saveRow{{0, []any{id, m.creator, m.region_created, m.lore,
+		int(m.curse_loyalty), int(m.cloak_region), int(m.cloak_creator), int(m.use_key),
+		m.project_cast, m.token_ni, int(m.quick_cast),
+		int(m.aura_bonus), int(m.aura), int(m.relic_decay),
+		int(m.attack_bonus), int(m.defense_bonus), int(m.missile_bonus),
+		int(m.token_num), int(m.orb_use_count)}}}
+
+	for seq, sk := range m.may_use.Values() {
+		rows = append(rows, saveRow{1, []any{id, "use", seq, sk}})
+	}
+	for seq, sk := range m.may_study.Values() {
+		rows = append(rows, saveRow{1, []any{id, "study", seq, sk}})
+	}
+
+	return rows
+}
+
+// inventoryRows returns the inventories rows of the items an entity holds.
+func (e *Engine) inventoryRows(c *saveContext, id int) []saveRow {
+	if e.globals.bx.get(id) == nil {
+		return nil
 	}
-	defer stmt.Close()
 
-	for id := 1; id < MAX_BOXES; id++ {
-		b := e.globals.bx[id]
-		if b == nil || b.kind != T_skill {
+	var rows []saveRow
+	for _, it := range e.globals.inventories[id] {
+		if it.qty <= 0 || e.globals.bx.get(it.item) == nil || e.globals.bx.get(it.item).kind != T_item {
 			continue
 		}
+		rows = append(rows, saveRow{0, []any{id, it.item, it.qty}})
+	}
+	return rows
+}
 
-		name := e.globals.names[id]
-		isMagic := 0
-		if b.skind == sub_magic {
-			isMagic = 1
-		}
+// skillRows returns the skills row of a skill.
+func (e *Engine) skillRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil || b.kind != T_skill {
+		return nil
+	}
+
+	name := e.globals.names[id]
+	isMagic := 0
+	if b.skind == sub_magic {
+		isMagic = 1
+	}
 
-		var category sql.NullString
+	var category sql.NullString
 
-		if _, err := stmt.Exec(id, name, category, isMagic); err != nil {
-			return fmt.Errorf("insert skill %d: %w", id, err)
-		}
+	return []saveRow{{0, []any{id, name, category, isMagic}}}
+}
+
+// charSkillRows returns the char_skills rows of a character. Dead
+// bodies keep their skills in dead_body_skills, see deadBodyRows.
+func (e *Engine) charSkillRows(c *saveContext, id int) []saveRow {
+	if b := e.globals.bx.get(id); b == nil || b.kind != T_char {
+		return nil
 	}
 
-	return nil
+	var rows []saveRow
+	for _, sk := range e.globals.charSkills[id] {
+		if sk == nil {
+			continue
+		}
+		rows = append(rows, saveRow{0, []any{id, sk.skill, sk.days_studied, int(sk.experience), int(sk.know)}})
+	}
+	return rows
 }
 
-// saveCharSkills saves character skill data to the char_skills table.
-func (e *Engine) saveCharSkills(tx *sql.Tx) error {
-	stmt, err := tx.Prepare(`
-		INSERT INTO char_skills (char_id, skill_id, level, experience)
-		VALUES (?, ?, ?, ?)
-	`)
-	if err != nil {
-		return err
+// deadBodyRows returns the noble data carried by a dead body for the
+// dead_bodies and dead_body_skills tables.
+func (e *Engine) deadBodyRows(c *saveContext, id int) []saveRow {
+	b := e.globals.bx.get(id)
+	if b == nil || b.kind != T_item || b.skind != sub_dead_body {
+		return nil
 	}
-	defer stmt.Close()
 
-	// Save skills from the charSkills map
-	for charID, skills := range e.globals.charSkills {
-		for _, sk := range skills {
-			if sk == nil {
-				continue
-			}
-			if _, err := stmt.Exec(charID, sk.skill, sk.days_studied, int(sk.experience)); err != nil {
-				return fmt.Errorf("insert char_skill %d/%d: %w", charID, sk.skill, err)
-			}
+	var saveName sql.NullString
+	if n := e.globals.savedNames[id]; n != "" {
+		saveName = sql.NullString{String: n, Valid: true}
+	}
+
+	var oldLord, prevLord sql.NullInt64
+	if b.x_misc != nil && b.x_misc.old_lord != 0 {
+		oldLord = sql.NullInt64{Int64: int64(b.x_misc.old_lord), Valid: true}
+	}
+
+	var deathTurn, deathDay, attack, defense, missile int
+	if ch := b.x_char; ch != nil {
+		if ch.prev_lord != 0 {
+			prevLord = sql.NullInt64{Int64: int64(ch.prev_lord), Valid: true}
 		}
+		deathTurn = int(ch.death_time.turn)
+		deathDay = int(ch.death_time.day)
+		attack = int(ch.attack)
+		defense = int(ch.defense)
+		missile = int(ch.missile)
 	}
 
-	return nil
+	rows := []saveRow{{0, []any{id, saveName, oldLord, prevLord,
+		deathTurn, deathDay, attack, defense, missile}}}
+
+	for _, sk := range e.globals.charSkills[id] {
+		if sk == nil || sk.know != SKILL_know {
+			continue
+		}
+		rows = append(rows, saveRow{1, []any{id, sk.skill, sk.days_studied, int(sk.experience)}})
+	}
+
+	return rows
 }
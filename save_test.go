@@ -17,6 +17,13 @@
 package taygete
 
 import (
+	"database/sql"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -28,16 +35,17 @@ func TestSaveWorldEntities(t *testing.T) {
 	defer db.Close()
 
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
 
 	// Create a location in memory
-	e.globals.bx[10000] = &box{
+	e.setBox(10000, &box{
 		kind:  T_loc,
 		skind: sub_plain,
-	}
-	e.globals.bx[10000].x_loc = &entity_loc{
+	})
+	e.globals.bx.get(10000).x_loc = &entity_loc{
 		civ: 5,
 	}
 	e.globals.names[10000] = "Test Province"
@@ -82,6 +90,7 @@ func TestSaveWorldRoundTrip(t *testing.T) {
 
 	// Create engine and load world
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
@@ -92,30 +101,30 @@ func TestSaveWorldRoundTrip(t *testing.T) {
 	}
 
 	// Capture original state
-	origRegionKind := e.globals.bx[58760].kind
-	origRegionSubkind := e.globals.bx[58760].skind
+	origRegionKind := e.globals.bx.get(58760).kind
+	origRegionSubkind := e.globals.bx.get(58760).skind
 	origRegionName := e.globals.names[58760]
 
-	origProvinceKind := e.globals.bx[10000].kind
-	origProvinceSubkind := e.globals.bx[10000].skind
+	origProvinceKind := e.globals.bx.get(10000).kind
+	origProvinceSubkind := e.globals.bx.get(10000).skind
 	origProvinceName := e.globals.names[10000]
-	origProvinceCiv := e.globals.bx[10000].x_loc.civ
+	origProvinceCiv := e.globals.bx.get(10000).x_loc.civ
 
-	origCharKind := e.globals.bx[1001].kind
+	origCharKind := e.globals.bx.get(1001).kind
 	origCharName := e.globals.names[1001]
-	origCharHealth := e.globals.bx[1001].x_char.health
-	origCharLoyKind := e.globals.bx[1001].x_char.loy_kind
-	origCharCurAura := e.globals.bx[1001].x_char.x_char_magic.cur_aura
-	origCharMaxAura := e.globals.bx[1001].x_char.x_char_magic.max_aura
+	origCharHealth := e.globals.bx.get(1001).x_char.health
+	origCharLoyKind := e.globals.bx.get(1001).x_char.loy_kind
+	origCharCurAura := e.globals.bx.get(1001).x_char.x_char_magic.cur_aura
+	origCharMaxAura := e.globals.bx.get(1001).x_char.x_char_magic.max_aura
 
-	origPlayerKind := e.globals.bx[50001].kind
-	origPlayerSubkind := e.globals.bx[50001].skind
+	origPlayerKind := e.globals.bx.get(50001).kind
+	origPlayerSubkind := e.globals.bx.get(50001).skind
 	origPlayerName := e.globals.names[50001]
 
-	origGateKind := e.globals.bx[59001].kind
+	origGateKind := e.globals.bx.get(59001).kind
 	origGateName := e.globals.names[59001]
-	origGateToLoc := e.globals.bx[59001].x_gate.to_loc
-	origGateLoc := e.globals.bx[59001].x_loc_info.where
+	origGateToLoc := e.globals.bx.get(59001).x_gate.to_loc
+	origGateLoc := e.globals.bx.get(59001).x_loc_info.where
 
 	// Save to database
 	err = e.SaveWorld()
@@ -133,100 +142,100 @@ func TestSaveWorldRoundTrip(t *testing.T) {
 	}
 
 	// Verify region
-	if e.globals.bx[58760] == nil {
+	if e.globals.bx.get(58760) == nil {
 		t.Fatal("region 58760 not reloaded")
 	}
-	if e.globals.bx[58760].kind != origRegionKind {
-		t.Errorf("region kind = %d, want %d", e.globals.bx[58760].kind, origRegionKind)
+	if e.globals.bx.get(58760).kind != origRegionKind {
+		t.Errorf("region kind = %d, want %d", e.globals.bx.get(58760).kind, origRegionKind)
 	}
-	if e.globals.bx[58760].skind != origRegionSubkind {
-		t.Errorf("region subkind = %d, want %d", e.globals.bx[58760].skind, origRegionSubkind)
+	if e.globals.bx.get(58760).skind != origRegionSubkind {
+		t.Errorf("region subkind = %d, want %d", e.globals.bx.get(58760).skind, origRegionSubkind)
 	}
 	if e.globals.names[58760] != origRegionName {
 		t.Errorf("region name = %q, want %q", e.globals.names[58760], origRegionName)
 	}
 
 	// Verify province
-	if e.globals.bx[10000] == nil {
+	if e.globals.bx.get(10000) == nil {
 		t.Fatal("province 10000 not reloaded")
 	}
-	if e.globals.bx[10000].kind != origProvinceKind {
-		t.Errorf("province kind = %d, want %d", e.globals.bx[10000].kind, origProvinceKind)
+	if e.globals.bx.get(10000).kind != origProvinceKind {
+		t.Errorf("province kind = %d, want %d", e.globals.bx.get(10000).kind, origProvinceKind)
 	}
-	if e.globals.bx[10000].skind != origProvinceSubkind {
-		t.Errorf("province subkind = %d, want %d", e.globals.bx[10000].skind, origProvinceSubkind)
+	if e.globals.bx.get(10000).skind != origProvinceSubkind {
+		t.Errorf("province subkind = %d, want %d", e.globals.bx.get(10000).skind, origProvinceSubkind)
 	}
 	if e.globals.names[10000] != origProvinceName {
 		t.Errorf("province name = %q, want %q", e.globals.names[10000], origProvinceName)
 	}
-	if e.globals.bx[10000].x_loc == nil {
+	if e.globals.bx.get(10000).x_loc == nil {
 		t.Fatal("province x_loc not reloaded")
 	}
-	if e.globals.bx[10000].x_loc.civ != origProvinceCiv {
-		t.Errorf("province civ = %d, want %d", e.globals.bx[10000].x_loc.civ, origProvinceCiv)
+	if e.globals.bx.get(10000).x_loc.civ != origProvinceCiv {
+		t.Errorf("province civ = %d, want %d", e.globals.bx.get(10000).x_loc.civ, origProvinceCiv)
 	}
 
 	// Verify character
-	if e.globals.bx[1001] == nil {
+	if e.globals.bx.get(1001) == nil {
 		t.Fatal("character 1001 not reloaded")
 	}
-	if e.globals.bx[1001].kind != origCharKind {
-		t.Errorf("char kind = %d, want %d", e.globals.bx[1001].kind, origCharKind)
+	if e.globals.bx.get(1001).kind != origCharKind {
+		t.Errorf("char kind = %d, want %d", e.globals.bx.get(1001).kind, origCharKind)
 	}
 	if e.globals.names[1001] != origCharName {
 		t.Errorf("char name = %q, want %q", e.globals.names[1001], origCharName)
 	}
-	if e.globals.bx[1001].x_char == nil {
+	if e.globals.bx.get(1001).x_char == nil {
 		t.Fatal("char x_char not reloaded")
 	}
-	if e.globals.bx[1001].x_char.health != origCharHealth {
-		t.Errorf("char health = %d, want %d", e.globals.bx[1001].x_char.health, origCharHealth)
+	if e.globals.bx.get(1001).x_char.health != origCharHealth {
+		t.Errorf("char health = %d, want %d", e.globals.bx.get(1001).x_char.health, origCharHealth)
 	}
-	if e.globals.bx[1001].x_char.loy_kind != origCharLoyKind {
-		t.Errorf("char loy_kind = %d, want %d", e.globals.bx[1001].x_char.loy_kind, origCharLoyKind)
+	if e.globals.bx.get(1001).x_char.loy_kind != origCharLoyKind {
+		t.Errorf("char loy_kind = %d, want %d", e.globals.bx.get(1001).x_char.loy_kind, origCharLoyKind)
 	}
-	if e.globals.bx[1001].x_char.x_char_magic == nil {
+	if e.globals.bx.get(1001).x_char.x_char_magic == nil {
 		t.Fatal("char x_char_magic not reloaded")
 	}
-	if e.globals.bx[1001].x_char.x_char_magic.cur_aura != origCharCurAura {
-		t.Errorf("char cur_aura = %d, want %d", e.globals.bx[1001].x_char.x_char_magic.cur_aura, origCharCurAura)
+	if e.globals.bx.get(1001).x_char.x_char_magic.cur_aura != origCharCurAura {
+		t.Errorf("char cur_aura = %d, want %d", e.globals.bx.get(1001).x_char.x_char_magic.cur_aura, origCharCurAura)
 	}
-	if e.globals.bx[1001].x_char.x_char_magic.max_aura != origCharMaxAura {
-		t.Errorf("char max_aura = %d, want %d", e.globals.bx[1001].x_char.x_char_magic.max_aura, origCharMaxAura)
+	if e.globals.bx.get(1001).x_char.x_char_magic.max_aura != origCharMaxAura {
+		t.Errorf("char max_aura = %d, want %d", e.globals.bx.get(1001).x_char.x_char_magic.max_aura, origCharMaxAura)
 	}
 
 	// Verify player
-	if e.globals.bx[50001] == nil {
+	if e.globals.bx.get(50001) == nil {
 		t.Fatal("player 50001 not reloaded")
 	}
-	if e.globals.bx[50001].kind != origPlayerKind {
-		t.Errorf("player kind = %d, want %d", e.globals.bx[50001].kind, origPlayerKind)
+	if e.globals.bx.get(50001).kind != origPlayerKind {
+		t.Errorf("player kind = %d, want %d", e.globals.bx.get(50001).kind, origPlayerKind)
 	}
-	if e.globals.bx[50001].skind != origPlayerSubkind {
-		t.Errorf("player subkind = %d, want %d", e.globals.bx[50001].skind, origPlayerSubkind)
+	if e.globals.bx.get(50001).skind != origPlayerSubkind {
+		t.Errorf("player subkind = %d, want %d", e.globals.bx.get(50001).skind, origPlayerSubkind)
 	}
 	if e.globals.names[50001] != origPlayerName {
 		t.Errorf("player name = %q, want %q", e.globals.names[50001], origPlayerName)
 	}
 
 	// Verify gate
-	if e.globals.bx[59001] == nil {
+	if e.globals.bx.get(59001) == nil {
 		t.Fatal("gate 59001 not reloaded")
 	}
-	if e.globals.bx[59001].kind != origGateKind {
-		t.Errorf("gate kind = %d, want %d", e.globals.bx[59001].kind, origGateKind)
+	if e.globals.bx.get(59001).kind != origGateKind {
+		t.Errorf("gate kind = %d, want %d", e.globals.bx.get(59001).kind, origGateKind)
 	}
 	if e.globals.names[59001] != origGateName {
 		t.Errorf("gate name = %q, want %q", e.globals.names[59001], origGateName)
 	}
-	if e.globals.bx[59001].x_gate == nil {
+	if e.globals.bx.get(59001).x_gate == nil {
 		t.Fatal("gate x_gate not reloaded")
 	}
-	if e.globals.bx[59001].x_gate.to_loc != origGateToLoc {
-		t.Errorf("gate to_loc = %d, want %d", e.globals.bx[59001].x_gate.to_loc, origGateToLoc)
+	if e.globals.bx.get(59001).x_gate.to_loc != origGateToLoc {
+		t.Errorf("gate to_loc = %d, want %d", e.globals.bx.get(59001).x_gate.to_loc, origGateToLoc)
 	}
-	if e.globals.bx[59001].x_loc_info.where != origGateLoc {
-		t.Errorf("gate location = %d, want %d", e.globals.bx[59001].x_loc_info.where, origGateLoc)
+	if e.globals.bx.get(59001).x_loc_info.where != origGateLoc {
+		t.Errorf("gate location = %d, want %d", e.globals.bx.get(59001).x_loc_info.where, origGateLoc)
 	}
 }
 
@@ -240,6 +249,7 @@ func TestSaveWorldRoundTripKindChains(t *testing.T) {
 	insertTestWorld(t, db)
 
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
@@ -326,6 +336,7 @@ func TestSaveWorldClearsOldData(t *testing.T) {
 	insertTestWorld(t, db)
 
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
@@ -336,8 +347,8 @@ func TestSaveWorldClearsOldData(t *testing.T) {
 	}
 
 	// Remove the gate (which references location 10001) and the location
-	e.globals.bx[59001] = nil
-	e.globals.bx[10001] = nil
+	e.deleteBox(59001)
+	e.deleteBox(10001)
 
 	// Save
 	err = e.SaveWorld()
@@ -373,26 +384,28 @@ func TestSaveWorldCharacterMagic(t *testing.T) {
 	defer db.Close()
 
 	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
 	e.globals.names = make(map[int]string)
 	e.globals.banners = make(map[int]string)
 	e.globals.pluralNames = make(map[int]string)
 
 	// Create a character with magic in memory
-	e.globals.bx[2001] = &box{
+	e.setBox(2001, &box{
 		kind:  T_char,
 		skind: 0,
-	}
-	e.globals.bx[2001].x_char = &entity_char{
+	})
+	e.globals.bx.get(2001).x_char = &entity_char{
 		health:   100,
 		loy_kind: LOY_oath,
 		loy_rate: 50,
 	}
-	e.globals.bx[2001].x_char.x_char_magic = &char_magic{
+	e.globals.bx.get(2001).x_char.x_char_magic = &char_magic{
 		cur_aura:    7,
 		max_aura:    15,
 		hide_mage:   1,
 		hide_self:   1,
 		vis_protect: 2,
+		visions:     map[int]bool{10101: true, 56760: true},
 	}
 	e.globals.names[2001] = "Mage Test"
 	e.addToKindChain(2001)
@@ -412,16 +425,16 @@ func TestSaveWorldCharacterMagic(t *testing.T) {
 	}
 
 	// Verify magic data
-	if e.globals.bx[2001] == nil {
+	if e.globals.bx.get(2001) == nil {
 		t.Fatal("character 2001 not reloaded")
 	}
-	if e.globals.bx[2001].x_char == nil {
+	if e.globals.bx.get(2001).x_char == nil {
 		t.Fatal("x_char not reloaded")
 	}
-	if e.globals.bx[2001].x_char.x_char_magic == nil {
+	if e.globals.bx.get(2001).x_char.x_char_magic == nil {
 		t.Fatal("x_char_magic not reloaded")
 	}
-	m := e.globals.bx[2001].x_char.x_char_magic
+	m := e.globals.bx.get(2001).x_char.x_char_magic
 	if m.cur_aura != 7 {
 		t.Errorf("cur_aura = %d, want 7", m.cur_aura)
 	}
@@ -437,4 +450,840 @@ func TestSaveWorldCharacterMagic(t *testing.T) {
 	if m.vis_protect != 2 {
 		t.Errorf("vis_protect = %d, want 2", m.vis_protect)
 	}
+	if len(m.visions) != 2 || !test_bit(m.visions, 10101) || !test_bit(m.visions, 56760) {
+		t.Errorf("visions = %v, want 10101 and 56760", m.visions)
+	}
+}
+
+func TestSaveWorldItemMagic(t *testing.T) {
+	db, err := OpenTestDB()
+	if err != nil {
+		t.Fatalf("OpenTestDB: %v", err)
+	}
+	defer db.Close()
+
+	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
+	e.globals.names = make(map[int]string)
+	e.globals.banners = make(map[int]string)
+	e.globals.pluralNames = make(map[int]string)
+	e.globals.inventories = make(map[int][]item_ent)
+
+	// A character holding a unique magic item
+	e.setBox(2001, &box{kind: T_char})
+	e.globals.bx.get(2001).x_char = &entity_char{health: 100}
+	e.globals.names[2001] = "Alchemist"
+	e.addToKindChain(2001)
+	e.addToSubkindChain(2001)
+
+	e.setBox(3001, &box{kind: T_item})
+	e.globals.bx.get(3001).x_item = &entity_item{weight: 1, who_has: 2001}
+	m := &item_magic{
+		creator:       2001,
+		use_key:       use_heal_potion,
+		lore:          9001,
+		aura_bonus:    3,
+		attack_bonus:  25,
+		defense_bonus: 10,
+		missile_bonus: 5,
+		relic_decay:   12,
+	}
+	m.may_use.Append(sk_archery)
+	m.may_study.Append(sk_combat)
+	m.may_study.Append(sk_swordplay)
+	e.globals.bx.get(3001).x_item.x_item_magic = m
+	e.globals.names[3001] = "Magic potion"
+	e.addToKindChain(3001)
+	e.addToSubkindChain(3001)
+
+	e.globals.inventories[2001] = []item_ent{{item: 3001, qty: 1}}
+
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	e.clearWorld()
+	if err := e.LoadWorld(); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+
+	b := e.globals.bx.get(3001)
+	if b == nil || b.x_item == nil || b.x_item.x_item_magic == nil {
+		t.Fatal("item 3001 magic not reloaded")
+	}
+	if b.x_item.who_has != 2001 {
+		t.Errorf("who_has = %d, want 2001", b.x_item.who_has)
+	}
+	got := b.x_item.x_item_magic
+	if got.creator != 2001 || got.use_key != use_heal_potion || got.lore != 9001 {
+		t.Errorf("creator/use_key/lore = %d/%d/%d, want 2001/%d/9001",
+			got.creator, got.use_key, got.lore, use_heal_potion)
+	}
+	if got.aura_bonus != 3 || got.attack_bonus != 25 || got.defense_bonus != 10 || got.missile_bonus != 5 {
+		t.Errorf("bonuses = %d/%d/%d/%d, want 3/25/10/5",
+			got.aura_bonus, got.attack_bonus, got.defense_bonus, got.missile_bonus)
+	}
+	if got.relic_decay != 12 {
+		t.Errorf("relic_decay = %d, want 12", got.relic_decay)
+	}
+	if v := got.may_use.Values(); len(v) != 1 || v[0] != sk_archery {
+		t.Errorf("may_use = %v, want [%d]", v, sk_archery)
+	}
+	if v := got.may_study.Values(); len(v) != 2 || v[0] != sk_combat || v[1] != sk_swordplay {
+		t.Errorf("may_study = %v, want [%d %d]", v, sk_combat, sk_swordplay)
+	}
+
+	inv := e.globals.inventories[2001]
+	if len(inv) != 1 || inv[0].item != 3001 || inv[0].qty != 1 {
+		t.Errorf("inventory of 2001 = %v, want [{3001 1}]", inv)
+	}
+}
+
+func TestSaveWorldArtifacts(t *testing.T) {
+	db, err := OpenTestDB()
+	if err != nil {
+		t.Fatalf("OpenTestDB: %v", err)
+	}
+	defer db.Close()
+
+	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
+	e.globals.names = make(map[int]string)
+	e.globals.banners = make(map[int]string)
+	e.globals.pluralNames = make(map[int]string)
+	e.globals.inventories = make(map[int][]item_ent)
+
+	// A mage holding an npc token, a unit controlled by the token,
+	// and a monster only defeatable by the token
+	e.setBox(2001, &box{kind: T_char})
+	e.globals.bx.get(2001).x_char = &entity_char{health: 100}
+	e.globals.bx.get(2001).x_char.x_char_magic = &char_magic{magician: TRUE, max_aura: 10}
+	e.globals.names[2001] = "Mage"
+
+	e.setBox(2002, &box{kind: T_char, skind: sub_ni})
+	e.globals.bx.get(2002).x_char = &entity_char{health: 100}
+	e.globals.bx.get(2002).x_char.x_char_magic = &char_magic{token: 3001}
+	e.globals.names[2002] = "Barbarian"
+
+	e.setBox(2003, &box{kind: T_char})
+	e.globals.bx.get(2003).x_char = &entity_char{health: 100}
+	e.globals.bx.get(2003).x_misc = &entity_misc{only_vuln: 3001}
+	e.globals.names[2003] = "Guardian"
+
+	e.setBox(3001, &box{kind: T_item, skind: sub_npc_token})
+	e.globals.bx.get(3001).x_item = &entity_item{weight: 1, who_has: 2001}
+	e.globals.bx.get(3001).x_item.x_item_magic = &item_magic{token_num: 1, token_ni: item_barbarian}
+	e.globals.names[3001] = "Crown of the Barbarians"
+
+	for _, id := range []int{2001, 2002, 2003, 3001} {
+		e.addToKindChain(id)
+		e.addToSubkindChain(id)
+	}
+	e.globals.inventories[2001] = []item_ent{{item: 3001, qty: 1}}
+
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	e.clearWorld()
+	if err := e.LoadWorld(); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+
+	if m := e.globals.bx.get(2001).x_char.x_char_magic; m == nil || m.magician != TRUE {
+		t.Error("magician flag not reloaded")
+	}
+	if m := e.globals.bx.get(2002).x_char.x_char_magic; m == nil || m.token != 3001 {
+		t.Error("token not reloaded")
+	}
+	if units := e.getPlayerUnits(3001); len(units) != 1 || units[0] != 2002 {
+		t.Errorf("token units = %v, want [2002]", units)
+	}
+	if m := e.globals.bx.get(2003).x_misc; m == nil || m.only_vuln != 3001 {
+		t.Error("only_vuln not reloaded")
+	}
+	if m := e.globals.bx.get(2001).x_misc; m != nil && m.only_vuln != 0 {
+		t.Errorf("only_vuln = %d on an ordinary character, want 0", m.only_vuln)
+	}
+}
+
+func TestSaveWorldQuests(t *testing.T) {
+	db, err := OpenTestDB()
+	if err != nil {
+		t.Fatalf("OpenTestDB: %v", err)
+	}
+	defer db.Close()
+
+	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
+	e.globals.names = make(map[int]string)
+	e.globals.banners = make(map[int]string)
+	e.globals.pluralNames = make(map[int]string)
+	e.globals.inventories = make(map[int][]item_ent)
+
+	// Nowhere, and a graveyard quested recently
+	e.setBox(58770, &box{kind: T_loc, skind: sub_region})
+	e.setBox(20001, &box{kind: T_loc, skind: sub_under})
+	e.globals.bx.get(20001).x_loc_info.where = 58770
+	e.setBox(56760, &box{kind: T_loc, skind: sub_graveyard})
+	e.globals.bx.get(56760).x_subloc = &entity_subloc{quest_late: 7}
+	for _, id := range []int{58770, 20001, 56760} {
+		e.addToKindChain(id)
+		e.addToSubkindChain(id)
+	}
+	e.globals.nowhereRegion = 58770
+	e.globals.nowhereLoc = 20001
+
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	e.clearWorld()
+	if err := e.LoadWorld(); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+
+	if s := e.globals.bx.get(56760).x_subloc; s == nil || s.quest_late != 7 {
+		t.Error("quest_late not reloaded")
+	}
+	if e.globals.nowhereRegion != 58770 || e.globals.nowhereLoc != 20001 {
+		t.Errorf("nowhere = %d/%d, want 58770/20001",
+			e.globals.nowhereRegion, e.globals.nowhereLoc)
+	}
+}
+
+func TestSaveWorldDeadBody(t *testing.T) {
+	db, err := OpenTestDB()
+	if err != nil {
+		t.Fatalf("OpenTestDB: %v", err)
+	}
+	defer db.Close()
+
+	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
+	e.globals.names = make(map[int]string)
+	e.globals.banners = make(map[int]string)
+	e.globals.pluralNames = make(map[int]string)
+	e.globals.inventories = make(map[int][]item_ent)
+	e.globals.charSkills = make(map[int][]*skill_ent)
+
+	e.setBox(sk_archery, &box{kind: T_skill})
+	e.globals.names[sk_archery] = "Archery"
+	e.addToKindChain(sk_archery)
+
+	// The body of noble 2001, killed on turn 7 while sworn to player 50001
+	e.setBox(2001, &box{kind: T_item, skind: sub_dead_body})
+	e.globals.bx.get(2001).x_item = &entity_item{weight: 100}
+	e.globals.bx.get(2001).x_char = &entity_char{
+		prev_lord:  50_002,
+		death_time: olytime{turn: 7, day: 12},
+		attack:     80,
+		defense:    70,
+	}
+	e.globals.bx.get(2001).x_misc = &entity_misc{old_lord: 50_001}
+	e.globals.names[2001] = "dead body"
+	e.globals.savedNames = map[int]string{2001: "Osric"}
+	e.appendCharSkill(2001, &skill_ent{skill: sk_archery, experience: 3, know: SKILL_know})
+	e.addToKindChain(2001)
+	e.addToSubkindChain(2001)
+
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	e.clearWorld()
+	if err := e.LoadWorld(); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+
+	b := e.globals.bx.get(2001)
+	if b == nil || b.kind != T_item || b.skind != sub_dead_body {
+		t.Fatal("dead body 2001 not reloaded")
+	}
+	if b.x_char == nil || b.x_char.death_time.turn != 7 || b.x_char.death_time.day != 12 {
+		t.Errorf("death_time not reloaded: %+v", b.x_char)
+	}
+	if b.x_char.defense != 70 || b.x_char.attack != 80 || b.x_char.prev_lord != 50_002 {
+		t.Errorf("attack/defense/prev_lord = %d/%d/%d, want 80/70/50002",
+			b.x_char.attack, b.x_char.defense, b.x_char.prev_lord)
+	}
+	if b.x_misc == nil || b.x_misc.old_lord != 50_001 {
+		t.Error("old_lord not reloaded")
+	}
+	if e.globals.savedNames[2001] != "Osric" {
+		t.Errorf("save_name = %q, want %q", e.globals.savedNames[2001], "Osric")
+	}
+	skills := e.getCharSkills(2001)
+	if len(skills) != 1 || skills[0].skill != sk_archery || skills[0].experience != 3 {
+		t.Errorf("skills not reloaded: %v", skills)
+	}
+}
+
+func TestSaveWorldPermissions(t *testing.T) {
+	db, err := OpenTestDB()
+	if err != nil {
+		t.Fatalf("OpenTestDB: %v", err)
+	}
+	defer db.Close()
+
+	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
+	e.globals.names = make(map[int]string)
+	e.globals.banners = make(map[int]string)
+	e.globals.pluralNames = make(map[int]string)
+	e.globals.inventories = make(map[int][]item_ent)
+
+	pl, other, who, tower := 50001, 50002, 1001, 1003
+	e.setBox(pl, &box{kind: T_player, skind: sub_pl_regular, x_player: &entity_player{}})
+	e.setBox(other, &box{kind: T_player, skind: sub_pl_regular, x_player: &entity_player{}})
+	e.setBox(who, &box{kind: T_char, x_char: &entity_char{unit_lord: pl}})
+	e.setBox(tower, &box{kind: T_loc, skind: sub_tower})
+	for _, id := range []int{pl, other, who, tower} {
+		e.addToKindChain(id)
+		e.addToSubkindChain(id)
+	}
+
+	a := &admit{targ: tower, sense: TRUE}
+	a.l.Append(other)
+	a.l.Append(who)
+	e.globals.bx.get(pl).x_player.admits = []*admit{a}
+
+	e.globals.bx.get(who).x_disp = &att_ent{}
+	e.globals.bx.get(who).x_disp.hostile.Append(other)
+	e.globals.bx.get(pl).x_disp = &att_ent{}
+	e.globals.bx.get(pl).x_disp.defend.Append(who)
+	e.globals.bx.get(pl).x_disp.neutral.Append(other)
+
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	e.clearWorld()
+	if err := e.LoadWorld(); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+
+	admits := e.globals.bx.get(pl).x_player.admits
+	if len(admits) != 1 || admits[0].targ != tower || admits[0].sense != TRUE {
+		t.Fatalf("admits = %+v, want one admit all for %d", admits, tower)
+	}
+	if got := admits[0].l.Values(); !slices.Equal(got, []int{other, who}) {
+		t.Errorf("admit list = %v, want [%d %d]", got, other, who)
+	}
+
+	if d := e.globals.bx.get(who).x_disp; d == nil || !slices.Equal(d.hostile.Values(), []int{other}) {
+		t.Error("unit hostile declaration not reloaded")
+	}
+	d := e.globals.bx.get(pl).x_disp
+	if d == nil || !slices.Equal(d.defend.Values(), []int{who}) || !slices.Equal(d.neutral.Values(), []int{other}) {
+		t.Error("faction attitudes not reloaded")
+	}
+}
+
+func TestSaveWorldPlayerFields(t *testing.T) {
+	db, err := OpenTestDB()
+	if err != nil {
+		t.Fatalf("OpenTestDB: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`INSERT INTO accounts (id, email, password_hash) VALUES (7, 'ann@example.com', 'x')`); err != nil {
+		t.Fatalf("insert account: %v", err)
+	}
+
+	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
+	e.globals.names = make(map[int]string)
+	e.globals.banners = make(map[int]string)
+	e.globals.pluralNames = make(map[int]string)
+	e.globals.inventories = make(map[int][]item_ent)
+	e.globals.playerUnits = make(map[int][]int)
+	e.globals.autoQuitTurns = 4
+
+	pl, city, unformed := 50001, 56760, 8101
+	e.setBox(pl, &box{kind: T_player, skind: sub_pl_regular, x_player: &entity_player{
+		account_id:      7,
+		email:           "ann@example.com",
+		full_name:       "Ann Player",
+		noble_points:    20,
+		fast_study:      230,
+		first_turn:      17,
+		last_order_turn: 16,
+		password:        "swordfish",
+		last_email:      "ann@home.example.com",
+		format:          2,
+		notab:           1,
+		split_lines:     500,
+		split_bytes:     20000,
+		sent_orders:     1,
+		dont_remind:     1,
+	}})
+	e.setBox(city, &box{kind: T_loc, skind: sub_city})
+	e.globals.bx.get(city).x_subloc = &entity_subloc{safe: TRUE}
+	e.setBox(unformed, &box{kind: T_unform})
+	e.globals.playerUnits[pl+100_000] = []int{unformed}
+	for _, id := range []int{pl, city, unformed} {
+		e.addToKindChain(id)
+		e.addToSubkindChain(id)
+	}
+	if err := e.SetStartCity(city, true); err != nil {
+		t.Fatalf("SetStartCity: %v", err)
+	}
+
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	e.clearWorld()
+	if err := e.LoadWorld(); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+
+	p := e.globals.bx.get(pl).x_player
+	if p.account_id != 7 || p.email != "ann@example.com" || p.full_name != "Ann Player" {
+		t.Errorf("player = %d %q %q", p.account_id, p.email, p.full_name)
+	}
+	if p.noble_points != 20 || p.fast_study != 230 || p.first_turn != 17 || p.last_order_turn != 16 {
+		t.Errorf("player counters = %d %d %d %d", p.noble_points, p.fast_study, p.first_turn, p.last_order_turn)
+	}
+	if p.password != "swordfish" || p.last_email != "ann@home.example.com" {
+		t.Errorf("player password, last_email = %q %q", p.password, p.last_email)
+	}
+	if p.format != 2 || p.notab != 1 || p.split_lines != 500 || p.split_bytes != 20000 ||
+		p.sent_orders != 1 || p.dont_remind != 1 {
+		t.Errorf("player mail settings = %d %d %d %d %d %d", p.format, p.notab, p.split_lines, p.split_bytes,
+			p.sent_orders, p.dont_remind)
+	}
+	if got, err := e.readPassword(player_password_key(pl)); err != nil || got != "swordfish" {
+		t.Errorf("passwords table = %q, %v", got, err)
+	}
+	if got := e.StartCities(); !slices.Equal(got, []int{city}) {
+		t.Errorf("StartCities = %v, want [%d]", got, city)
+	}
+	if s := e.globals.bx.get(city).x_subloc; s == nil || s.safe != TRUE {
+		t.Error("safe haven not reloaded")
+	}
+	if got := e.globals.playerUnits[pl+100_000]; !slices.Equal(got, []int{unformed}) {
+		t.Errorf("unformed = %v, want [%d]", got, unformed)
+	}
+	if e.globals.autoQuitTurns != 4 {
+		t.Errorf("autoQuitTurns = %d, want 4", e.globals.autoQuitTurns)
+	}
+}
+
+// zeroFields returns the fields of the struct *p left at their zero
+// value, other than those in skip.
+func zeroFields(p any, skip ...string) []string {
+	var zero []string
+	v := reflect.ValueOf(p).Elem()
+	for i := range v.NumField() {
+		name := v.Type().Field(i).Name
+		if v.Field(i).IsZero() && !slices.Contains(skip, name) {
+			zero = append(zero, name)
+		}
+	}
+	return zero
+}
+
+// TestSaveWorldBoxModel sets every saved field of entity_player,
+// entity_subloc, entity_misc and skill_ent and checks that they, and the state
+// the Go port keeps beside them, come back from the database. A field
+// added to one of the structs fails the test until it is saved or
+// listed as not saved.
+func TestSaveWorldBoxModel(t *testing.T) {
+	db, err := OpenTestDB()
+	if err != nil {
+		t.Fatalf("OpenTestDB: %v", err)
+	}
+	defer db.Close()
+
+	e := &Engine{db: db}
+	e.clearWorld()
+
+	pl, prov, castle, who, unformed, storm := 50_001, 10_101, 56_760, 1_001, 8_101, 7_001
+	p := &entity_player{
+		full_name: "Ann Player", email: "ann@example.com", vis_email: "ann@list.example.com",
+		last_email: "ann@home.example.com", password: "swordfish",
+		first_turn: 3, last_order_turn: 16,
+		admits:      []*admit{{targ: castle, sense: 1, l: NewList(who, pl)}},
+		split_lines: 500, split_bytes: 20_000, fast_study: 230, noble_points: 20,
+		format: 2, notab: 1, first_tower: 1, sent_orders: 1, dont_remind: 1,
+		compuserve: 1, broken_mailer: 1,
+	}
+	if zero := zeroFields(p,
+		"account_id", // an accounts row, see TestSaveWorldPlayerFields
+		"orders",     // the orders table
+		"known",      // playerKnowledge
+		"units",      // playerUnits
+		"unformed",   // playerUnits
+		"public_turn", "times_paid", "swear_this_turn", "cmd_count", "np_gained", "np_spent",
+		"deliver_lore", "weather_seen", "output", "locs"); len(zero) != 0 {
+		t.Fatalf("entity_player fields not set: %v", zero)
+	}
+
+	sl := &entity_subloc{
+		teaches: NewList(9_101, 9_102), opium_econ: 4, defense: 60,
+		loot: 2, damage: 35, galley_ram: 1, shaft_depth: 3, castle_lev: 5,
+		build_materials: 7, effort_required: 500, effort_given: 250,
+		moving: 1_234, capacity: 2_500,
+		near_cities: NewList(56_761), safe: 1, major: 1, prominence: 2,
+		uldim_flag: 4, summer_flag: 2, quest_late: 6, tunnel_level: 2,
+		link_when: 5, link_open: 1,
+		link_to: []int{prov}, link_from: []int{prov}, bound_storms: []int{storm},
+	}
+	if zero := zeroFields(sl, "recent_loot"); len(zero) != 0 {
+		t.Fatalf("entity_subloc fields not set: %v", zero)
+	}
+
+	m := &entity_misc{
+		npc_created: 12, npc_home: prov, npc_cookie: 9_201, summoned_by: pl,
+		old_lord: pl, only_vuln: 9_301, garr_castle: castle, bind_storm: storm,
+		storm_str: 8, npc_dir: 3, mine_delay: 4, cmd_allow: 'r',
+		npc_memory: map[int]bool{who: true, 9_401: true},
+	}
+	if zero := zeroFields(m,
+		"display",   // banners
+		"save_name", // savedNames
+		"opium_double", "post_txt", "storm_move", "garr_watch", "garr_host", "garr_tax", "garr_forward"); len(zero) != 0 {
+		t.Fatalf("entity_misc fields not set: %v", zero)
+	}
+
+	shipcraft, fishing := 600, 610
+	skills := []*skill_ent{
+		{skill: shipcraft, days_studied: 14, experience: 5, know: SKILL_learning},
+		{skill: fishing, days_studied: 70, experience: 12, know: SKILL_know},
+	}
+	for _, sk := range skills {
+		if zero := zeroFields(sk, "exp_this_month"); len(zero) != 0 {
+			t.Fatalf("skill_ent fields not set: %v", zero)
+		}
+	}
+
+	for _, b := range []struct {
+		id          int
+		kind, skind schar
+	}{
+		{shipcraft, T_skill, 0},
+		{fishing, T_skill, 0},
+		{pl, T_player, sub_pl_regular},
+		{prov, T_loc, sub_plain},
+		{castle, T_loc, sub_castle},
+		{who, T_char, 0},
+		{unformed, T_unform, 0},
+		{storm, T_storm, sub_rain},
+	} {
+		e.setBox(b.id, &box{kind: b.kind, skind: b.skind})
+		e.addToKindChain(b.id)
+		e.addToSubkindChain(b.id)
+	}
+	e.globals.bx.get(pl).x_player = p
+	e.globals.bx.get(castle).x_subloc = sl
+	e.globals.bx.get(castle).x_loc_info.where = prov
+	e.globals.bx.get(who).x_char = &entity_char{unit_lord: pl, health: 100}
+	e.globals.bx.get(who).x_misc = m
+	e.globals.bx.get(who).x_loc_info.where = castle
+	e.globals.banners[who] = "the Bold"
+	e.globals.savedNames[who] = "Osswid"
+	e.globals.charSkills[who] = skills
+	e.globals.playerUnits[pl] = []int{who}
+	e.globals.playerUnits[pl+100_000] = []int{unformed}
+	e.setPlayerKnowledge(pl, prov)
+	e.setPlayerKnowledge(pl, castle)
+
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	e.clearWorld()
+	e.globals.playerKnowledge = nil
+	if err := e.LoadWorld(); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+
+	for _, c := range []struct {
+		name      string
+		got, want any
+	}{
+		{"entity_player", e.globals.bx.get(pl).x_player, p},
+		{"entity_subloc", e.globals.bx.get(castle).x_subloc, sl},
+		{"entity_misc", e.globals.bx.get(who).x_misc, m},
+		{"skills", e.globals.charSkills[who], skills},
+		{"banner", e.globals.banners[who], "the Bold"},
+		{"save_name", e.globals.savedNames[who], "Osswid"},
+		{"units", e.globals.playerUnits[pl], []int{who}},
+		{"unformed", e.globals.playerUnits[pl+100_000], []int{unformed}},
+		{"knowledge", e.getPlayerKnowledge(pl), map[int]bool{prov: true, castle: true}},
+	} {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s: got %+v, want %+v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestSaveWorldLocLinks(t *testing.T) {
+	db, err := OpenTestDB()
+	if err != nil {
+		t.Fatalf("OpenTestDB: %v", err)
+	}
+	defer db.Close()
+
+	e := &Engine{db: db}
+	e.globals.bx = boxStore{}
+	e.globals.names = make(map[int]string)
+	e.globals.banners = make(map[int]string)
+	e.globals.pluralNames = make(map[int]string)
+	e.globals.inventories = make(map[int][]item_ent)
+
+	pass, prov, hill := 10101, 10102, 56770
+	e.setBox(pass, &box{kind: T_loc, skind: sub_mountain})
+	e.globals.bx.get(pass).x_subloc = &entity_subloc{uldim_flag: 4}
+	e.setBox(prov, &box{kind: T_loc, skind: sub_plain})
+	e.globals.bx.get(prov).x_subloc = &entity_subloc{summer_flag: 2, link_from: []int{hill}}
+	e.setBox(hill, &box{kind: T_loc, skind: sub_faery_hill})
+	e.globals.bx.get(hill).x_subloc = &entity_subloc{link_to: []int{prov}, link_when: 5, link_open: 1}
+	for _, id := range []int{pass, prov, hill} {
+		e.addToKindChain(id)
+		e.addToSubkindChain(id)
+	}
+
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	e.clearWorld()
+	if err := e.LoadWorld(); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+
+	if s := e.globals.bx.get(pass).x_subloc; s == nil || s.uldim_flag != 4 {
+		t.Errorf("uldim pass = %+v", s)
+	}
+	s := e.globals.bx.get(prov).x_subloc
+	if s == nil || s.summer_flag != 2 || !slices.Equal(s.link_from, []int{hill}) {
+		t.Errorf("province = %+v, want summerbridge linked from %d", s, hill)
+	}
+	s = e.globals.bx.get(hill).x_subloc
+	if s == nil || !slices.Equal(s.link_to, []int{prov}) || s.link_when != 5 || s.link_open != 1 {
+		t.Errorf("faery hill = %+v, want link to %d in month 5", s, prov)
+	}
+}
+
+// dumpSavedTables returns every row SaveWorld writes, one sorted list
+// per table, for comparing two databases.
+func dumpSavedTables(t *testing.T, db *sql.DB) map[string][]string {
+	t.Helper()
+	tables := []string{"passwords"}
+	for _, g := range saveGroups {
+		for _, st := range g.tables {
+			if st.name != "passwords" {
+				tables = append(tables, st.name)
+			}
+		}
+	}
+
+	dump := make(map[string][]string)
+	for _, table := range tables {
+		rows, err := db.Query("SELECT * FROM " + table)
+		if err != nil {
+			t.Fatalf("select %s: %v", table, err)
+		}
+		cols, err := rows.Columns()
+		if err != nil {
+			t.Fatalf("columns %s: %v", table, err)
+		}
+		for rows.Next() {
+			vals := make([]any, len(cols))
+			ptrs := make([]any, len(cols))
+			for i := range vals {
+				ptrs[i] = &vals[i]
+			}
+			if err := rows.Scan(ptrs...); err != nil {
+				t.Fatalf("scan %s: %v", table, err)
+			}
+			dump[table] = append(dump[table], fmt.Sprintf("%v", vals))
+		}
+		rows.Close()
+		slices.Sort(dump[table])
+	}
+	return dump
+}
+
+// newSaveGame builds a small world, saves it and loads it back, so the
+// next save is incremental.
+func newSaveGame(t *testing.T) *engineGame {
+	t.Helper()
+	g := newEngineGame(t, 1, "Red Company", "Osswid")
+	e := g.e
+
+	// A second province and a road out of the city, to be deleted later
+	e.alloc_box(10_102, T_loc, sub_forest)
+	e.set_name(10_102, "Old Wood")
+	e.p_subloc(56_760).link_to = []int{10_102}
+	e.p_player(g.pl).password = "swordfish"
+
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	if err := e.LoadWorld(); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+	return g
+}
+
+// changeSaveGame inserts, modifies and deletes entities.
+func changeSaveGame(g *engineGame) {
+	e := g.e
+
+	e.set_name(g.who, "Osswid the Bold")
+	e.gen_item(g.who, item_gold, 50)
+	e.p_magic(g.who).cur_aura = 3
+	e.p_magic(g.who).visions = map[int]bool{g.pl: true}
+	e.p_disp(g.who).hostile.Append(g.pl)
+	e.p_player(g.pl).email = "red@example.com"
+	e.p_player(g.pl).password = ""
+
+	e.alloc_box(10_103, T_loc, sub_mountain)
+	e.set_name(10_103, "High Pass")
+	e.p_subloc(56_760).link_to = []int{10_103}
+	e.remove_next_chain(10_102)
+	e.remove_sub_chain(10_102)
+	e.setBox(10_102, nil)
+	e.setName(10_102, "")
+}
+
+func TestSaveWorldIncrementalMatchesFull(t *testing.T) {
+	inc := newSaveGame(t)
+	full := newSaveGame(t)
+	changeSaveGame(inc)
+	changeSaveGame(full)
+
+	if err := inc.e.SaveWorld(); err != nil {
+		t.Fatalf("incremental SaveWorld: %v", err)
+	}
+	if err := full.e.saveWorldFull(); err != nil {
+		t.Fatalf("full SaveWorld: %v", err)
+	}
+
+	got, want := dumpSavedTables(t, inc.e.db), dumpSavedTables(t, full.e.db)
+	for table, rows := range want {
+		if !slices.Equal(got[table], rows) {
+			t.Errorf("%s after incremental save:\n got %v\nwant %v", table, got[table], rows)
+		}
+	}
+	for table, rows := range got {
+		if _, ok := want[table]; !ok {
+			t.Errorf("%s after incremental save has extra rows %v", table, rows)
+		}
+	}
+
+	// Both engines know the rows they wrote; saving again is a no-op.
+	if err := inc.e.SaveWorld(); err != nil {
+		t.Fatalf("second SaveWorld: %v", err)
+	}
+	if again := dumpSavedTables(t, inc.e.db); !maps.EqualFunc(again, got, slices.Equal) {
+		t.Errorf("second save changed the database")
+	}
+}
+
+func TestSaveWorldIncrementalWritesChanges(t *testing.T) {
+	g := newSaveGame(t)
+	db := g.e.db
+
+	// Log every write to the entity tables
+	if _, err := db.Exec(`CREATE TABLE save_log (tbl TEXT, op TEXT, key TEXT)`); err != nil {
+		t.Fatalf("create save_log: %v", err)
+	}
+	for _, sg := range saveGroups {
+		for _, st := range sg.tables {
+			key := st.cols[0]
+			for op, row := range map[string]string{"INSERT": "NEW", "UPDATE": "NEW", "DELETE": "OLD"} {
+				if _, err := db.Exec(fmt.Sprintf(`
+					CREATE TRIGGER log_%s_%s AFTER %s ON %s
+					BEGIN INSERT INTO save_log VALUES ('%s', '%s', %s.%s); END`,
+					st.name, op, op, st.name, st.name, op, row, key)); err != nil {
+					t.Fatalf("create trigger on %s: %v", st.name, err)
+				}
+			}
+		}
+	}
+	writes := func() []string {
+		t.Helper()
+		rows, err := db.Query(`SELECT tbl, op, key FROM save_log ORDER BY rowid`)
+		if err != nil {
+			t.Fatalf("select save_log: %v", err)
+		}
+		defer rows.Close()
+		var log []string
+		for rows.Next() {
+			var tbl, op, key string
+			if err := rows.Scan(&tbl, &op, &key); err != nil {
+				t.Fatalf("scan save_log: %v", err)
+			}
+			log = append(log, tbl+" "+op+" "+key)
+		}
+		if _, err := db.Exec(`DELETE FROM save_log`); err != nil {
+			t.Fatalf("clear save_log: %v", err)
+		}
+		return log
+	}
+
+	// A loaded world saved unchanged writes nothing.
+	if err := g.e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	if log := writes(); len(log) != 0 {
+		t.Errorf("unchanged save wrote %v", log)
+	}
+
+	// A report referring to the player survives saving it.
+	if _, err := db.Exec(`INSERT INTO turns (turn_number) VALUES (1)`); err != nil {
+		t.Fatalf("insert turn: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO reports (turn_number, player_id, body) VALUES (1, ?, 'x')`,
+		g.pl); err != nil {
+		t.Fatalf("insert report: %v", err)
+	}
+
+	g.e.p_char(g.who).health = 80
+	g.e.p_player(g.pl).email = "red@example.com"
+	if err := g.e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	// Only the rows of the two entities changed are written.
+	log := writes()
+	for _, w := range []string{
+		fmt.Sprintf("players UPDATE %d", g.pl),
+		fmt.Sprintf("characters UPDATE %d", g.who),
+	} {
+		if !slices.Contains(log, w) {
+			t.Errorf("save wrote %v, want %s", log, w)
+		}
+	}
+	changed := []string{strconv.Itoa(g.pl), strconv.Itoa(g.who), player_password_key(g.pl)}
+	for _, w := range log {
+		if key := w[strings.LastIndex(w, " ")+1:]; !slices.Contains(changed, key) {
+			t.Errorf("save wrote %s for an unchanged entity", w)
+		}
+	}
+
+	var reports int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM reports WHERE player_id = ?`, g.pl).Scan(&reports); err != nil {
+		t.Fatalf("count reports: %v", err)
+	}
+	if reports != 1 {
+		t.Errorf("reports for player = %d, want 1", reports)
+	}
+}
+
+func TestSaveWorldChecksDirty(t *testing.T) {
+	g := newSaveGame(t)
+
+	// A change through a read-only pointer isn't marked
+	g.e.rp_char(g.who).health = 80
+	if err := g.e.SaveWorld(); err == nil || !strings.Contains(err.Error(), "without being marked dirty") {
+		t.Fatalf("SaveWorld: got %v, want an unmarked change", err)
+	}
+
+	g.e.markDirty(g.who)
+	if err := g.e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	if err := g.e.LoadWorld(); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+	if h := g.e.char_health(g.who); h != 80 {
+		t.Errorf("health = %d, want 80", h)
+	}
 }
@@ -0,0 +1,725 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// scry.go - Projected casting, item location and scry alerts ported from src/scry.c
+
+package taygete
+
+import "strings"
+
+// cast_where returns where a mage's spells take effect: the projected
+// cast location if one is stored, otherwise the mage's own location.
+// Ported from src/scry.c lines 22-34.
+func (e *Engine) cast_where(who int) int {
+	where := e.char_proj_cast(who)
+
+	if e.is_loc_or_ship(where) {
+		return where
+	}
+
+	return e.subloc(who)
+}
+
+// reset_cast_where is cast_where, but also uses up the stored
+// projected cast.
+// Ported from src/scry.c lines 37-51.
+func (e *Engine) reset_cast_where(who int) int {
+	where := e.char_proj_cast(who)
+
+	if e.is_loc_or_ship(where) {
+		e.p_magic(who).project_cast = 0
+		return where
+	}
+
+	return e.subloc(who)
+}
+
+// show_item_where tells who where a unique item is.
+// Ported from src/scry.c lines 419-449.
+func (e *Engine) show_item_where(who, target int) {
+	if e.kind(target) != T_item {
+		panic("show_item_where: target is not an item")
+	}
+
+	owner := e.item_unique(target)
+	if owner == 0 {
+		panic("show_item_where: item is not unique")
+	}
+
+	prov := e.province(owner)
+
+	if prov == owner {
+		wout(who, "%s is in %s.", e.box_name(target), e.box_name(prov))
+		return
+	}
+
+	if e.subkind(owner) == sub_graveyard {
+		wout(who, "%s is buried in %s, in %s.", e.box_name(target), e.box_name(owner), e.box_name(prov))
+		return
+	}
+
+	wout(who, "%s is held by %s, in %s.", e.box_name(target), e.box_name(owner), e.box_name(prov))
+}
+
+// alert_palantir_scry warns master scry detectors at where that
+// someone used a palantir on them; grand masters also learn where
+// the scryer is.
+// Ported from src/scry.c lines 157-178.
+func (e *Engine) alert_palantir_scry(who, where int) {
+	var l []int
+	e.loop_char_here(where, &l)
+
+	for _, n := range l {
+		has_detect := e.has_skill_level(n, sk_detect_scry)
+
+		if has_detect < exp_master {
+			continue
+		}
+
+		wout(n, "%s used a palantir to scry this location.", e.box_name(who))
+
+		if has_detect > exp_master {
+			wout(n, "%s is in %s.", e.box_name(who), e.char_rep_location(who))
+		}
+	}
+}
+
+// alert_scry_generic tells scry detectors at where who scried them.
+// Ported from src/scry.c lines 181-198.
+func (e *Engine) alert_scry_generic(who, where int) {
+	var l []int
+	e.loop_char_here(where, &l)
+
+	for _, n := range l {
+		if e.has_skill(n, sk_detect_scry) {
+			wout(n, "%s scried %s from %s.",
+				e.box_name(who), e.box_name(where), e.char_rep_location(who))
+		}
+	}
+}
+
+// v_scry_region starts scrying a location.
+// Ported from src/scry.c lines 100-120.
+func (e *Engine) v_scry_region(c *command) int {
+	targ_loc := c.a
+
+	if !e.is_loc_or_ship(targ_loc) {
+		wout(c.who, "%s is not a location.", e.box_code(targ_loc))
+		return FALSE
+	}
+
+	c.b = max(c.b, 1)
+	aura := c.b
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// alert_scry_attempt tells scry detectors at where that someone cast
+// Scry region on them; masters also learn where the caster is.
+// Ported from src/scry.c lines 123-154.
+func (e *Engine) alert_scry_attempt(who, where int, t string) {
+	var l []int
+	e.loop_char_here(where, &l)
+
+	for _, n := range l {
+		has_detect := e.has_skill_level(n, sk_detect_scry)
+
+		source := "Someone"
+		if has_detect > exp_novice {
+			source = e.box_name(who)
+		}
+
+		if has_detect != 0 {
+			wout(n, "%s%s cast %s on this location.", source, t, e.box_name(sk_scry_region))
+		}
+
+		if has_detect >= exp_master {
+			wout(n, "%s is in %s.", e.box_name(who), e.char_rep_location(who))
+		}
+	}
+}
+
+// d_scry_region shows the caster a location in the same region,
+// unless its province is shrouded by at least the aura spent.
+// Ported from src/scry.c lines 201-241.
+func (e *Engine) d_scry_region(c *command) int {
+	targ_loc := c.a
+	aura := c.b
+
+	if !e.is_loc_or_ship(targ_loc) {
+		wout(c.who, "%s is no longer a valid location.", e.box_code(targ_loc))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	if e.diff_region(c.who, targ_loc) {
+		wout(c.who, "Only murky, indistinct images result from your scry.")
+		return TRUE
+	}
+
+	if aura <= int(e.loc_shroud(e.province(targ_loc))) {
+		wout(c.who, "%s is shrouded from your scry.", e.box_code(targ_loc))
+
+		e.alert_scry_attempt(c.who, targ_loc, " unsuccessfully")
+
+		return FALSE
+	}
+
+	wout(c.who, "A vision of %s appears:", e.box_name(targ_loc))
+	out(c.who, "")
+	e.show_loc(c.who, targ_loc)
+
+	e.alert_scry_attempt(c.who, targ_loc, "")
+
+	return TRUE
+}
+
+// v_shroud_region starts shrouding the province the caster's spells
+// reach.
+// Ported from src/scry.c lines 244-264.
+func (e *Engine) v_shroud_region(c *command) int {
+	where := e.province(e.cast_where(c.who))
+
+	c.a = max(c.a, 1)
+	aura := c.a
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to create a magical shroud to conceal %s from scry attempts.", e.box_code(where))
+
+	e.reset_cast_where(c.who)
+	c.b = where
+
+	return TRUE
+}
+
+// d_shroud_region adds twice the aura spent to the province's shroud.
+// Ported from src/scry.c lines 267-307.
+func (e *Engine) d_shroud_region(c *command) int {
+	aura := c.a
+	where := c.b
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	p := e.p_loc(where)
+	p.shroud += short(aura * 2)
+
+	wout(c.who, "%s is now cloaked with an aura %s location shroud.", e.box_name(where), nice_num(int(p.shroud)))
+
+	var l []int
+	e.loop_char_here(where, &l)
+	for _, n := range l {
+		if n == c.who {
+			continue
+		}
+
+		if e.has_skill(n, sk_shroud_region) {
+			wout(n, "%s cast %s here.  %s is now cloaked with an aura %s location shroud.",
+				e.box_name(c.who), e.box_name(sk_shroud_region), e.box_code(where), nice_num(int(p.shroud)))
+		}
+	}
+
+	return TRUE
+}
+
+// v_detect_scry starts practicing Detect location scry.
+// Ported from src/scry.c lines 310-319.
+func (e *Engine) v_detect_scry(c *command) int {
+	if !e.check_aura(c.who, 1) {
+		return FALSE
+	}
+
+	wout(c.who, "Will practice location scry detection.")
+	return TRUE
+}
+
+// d_detect_scry finishes practicing Detect location scry. The skill
+// itself works passively.
+// Ported from src/scry.c lines 322-330.
+func (e *Engine) d_detect_scry(c *command) int {
+	if !e.charge_aura(c.who, 1) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// notify_loc_shroud tells the region shrouders at where how strong
+// its shroud is now.
+// Ported from src/scry.c lines 333-359.
+func (e *Engine) notify_loc_shroud(where int) {
+	p := e.rp_loc(where)
+	if p == nil {
+		return
+	}
+
+	var l []int
+	e.loop_char_here(where, &l)
+	for _, who := range l {
+		if !e.has_skill(who, sk_shroud_region) {
+			continue
+		}
+
+		if p.shroud > 0 {
+			wout(who, "The magical shroud over %s has diminished to %s aura.", e.box_name(where), nice_num(int(p.shroud)))
+		} else {
+			wout(who, "The magical shroud over %s has dissipated.", e.box_name(where))
+		}
+	}
+}
+
+// v_dispel_region starts dispelling the shroud over a province.
+// Ported from src/scry.c lines 362-380.
+func (e *Engine) v_dispel_region(c *command) int {
+	targ_loc := e.province(c.a)
+
+	if !e.is_loc_or_ship(targ_loc) {
+		wout(c.who, "%s is not a location.", e.box_code(targ_loc))
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 3) {
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to dispel any magical shroud over %s.", e.box_name(targ_loc))
+
+	return TRUE
+}
+
+// d_dispel_region removes the shroud over the province.
+// Ported from src/scry.c lines 383-416.
+func (e *Engine) d_dispel_region(c *command) int {
+	targ_loc := e.province(c.a)
+
+	if !e.is_loc_or_ship(targ_loc) {
+		wout(c.who, "%s is no longer a valid location.", e.box_code(targ_loc))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, 3) {
+		return FALSE
+	}
+
+	p := e.rp_loc(targ_loc)
+	if p == nil || p.shroud <= 0 {
+		wout(c.who, "%s was not magically shrouded.", e.box_name(targ_loc))
+		return TRUE
+	}
+
+	wout(c.who, "Removed an aura %s magical shroud from %s.", nice_num(int(p.shroud)), e.box_name(targ_loc))
+	p.shroud = 0
+	e.markDirty(targ_loc)
+	e.notify_loc_shroud(targ_loc)
+
+	return TRUE
+}
+
+// v_locate_char starts locating a character.
+// Ported from src/scry.c lines 452-474.
+func (e *Engine) v_locate_char(c *command) int {
+	target := c.a
+
+	if e.kind(target) != T_char || e.subkind(target) == sub_dead_body {
+		wout(c.who, "%s is not a character.", e.box_code(target))
+		return FALSE
+	}
+
+	c.b = max(c.b, 1)
+	aura := c.b
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to locate %s.", e.box_code(target))
+
+	return TRUE
+}
+
+// d_locate_char tells the caster where a character in the same
+// region is. One, two or three aura succeed 50%, 75% or 90% of the
+// time.
+// Ported from src/scry.c lines 477-532.
+func (e *Engine) d_locate_char(c *command) int {
+	target := c.a
+	aura := c.b
+
+	if e.kind(target) != T_char || e.subkind(target) == sub_dead_body {
+		wout(c.who, "%s is not a character.", e.box_code(target))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	if e.diff_region(c.who, target) {
+		wout(c.who, "Only murky, indistinct images result.")
+		return TRUE
+	}
+
+	var chance int
+	switch aura {
+	case 1:
+		chance = 50
+	case 2:
+		chance = 75
+	default:
+		chance = 90
+	}
+
+	if e.rndFrom(streamMagic, 1, 100) > chance {
+		wout(c.who, "Character location failed.")
+		return FALSE
+	}
+
+	if e.subkind(target) == sub_dead_body {
+		e.show_item_where(c.who, target)
+	} else {
+		wout(c.who, "%s is in %s.", e.box_name(target), e.char_rep_location(target))
+	}
+
+	return TRUE
+}
+
+// bar_loc_ok reports whether a barrier may be put around where.
+func (e *Engine) bar_loc_ok(who, where int) bool {
+	if e.kind(where) != T_loc {
+		wout(who, "%s is not a location.", e.box_code(where))
+		return false
+	}
+
+	if e.in_safe_now(where) {
+		wout(who, "Can't put a barrier around a safe haven.")
+		return false
+	}
+
+	if e.loc_depth(where) > LOC_subloc {
+		wout(who, "Can't put a barrier around %s.", e.box_code(where))
+		return false
+	}
+
+	return true
+}
+
+// v_bar_loc starts casting a barrier over the location the caster's
+// spells reach, with one to eight aura.
+// Ported from src/scry.c lines 535-582.
+func (e *Engine) v_bar_loc(c *command) int {
+	where := e.cast_where(c.who)
+
+	if !e.bar_loc_ok(c.who, where) {
+		return FALSE
+	}
+
+	c.a = min(max(c.a, 1), 8)
+	aura := c.a
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	if e.loc_barrier(where) < 0 {
+		wout(c.who, "%s already has a permanent barrier.", e.box_name(where))
+		return FALSE
+	}
+
+	c.d = where
+	e.reset_cast_where(c.who)
+
+	wout(c.who, "Create a magical barrier over %s.", e.box_name(where))
+	return TRUE
+}
+
+// d_bar_loc adds the aura spent to the barrier. A barrier of eight
+// or more becomes permanent, recorded as the negated caster.
+// Ported from src/scry.c lines 585-645.
+func (e *Engine) d_bar_loc(c *command) int {
+	aura := c.a
+	where := c.d
+
+	if !e.bar_loc_ok(c.who, where) {
+		return FALSE
+	}
+
+	old_val := e.loc_barrier(where)
+
+	if old_val < 0 {
+		wout(c.who, "%s already has a permanent barrier.", e.box_name(where))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	p := e.p_loc(where)
+	p.barrier += aura
+
+	if p.barrier >= 8 {
+		p.barrier = -c.who
+	}
+
+	wout(c.who, "Cast a barrier over %s.", e.box_name(where))
+
+	if p.barrier > 0 {
+		wout(c.who, "The barrier has aura %s.", nice_num(p.barrier))
+	} else {
+		wout(c.who, "The barrier is permanent.")
+	}
+
+	if old_val == 0 {
+		out(where, "%s cast a magical barrier over %s.", e.box_name(c.who), e.box_name(where))
+	}
+
+	return TRUE
+}
+
+// v_unbar_loc starts removing the barrier over the location the
+// caster's spells reach ("0"), or over a neighboring one.
+// Ported from src/scry.c lines 648-687.
+func (e *Engine) v_unbar_loc(c *command) int {
+	var where int
+	if strings.HasPrefix(get_parse_arg(c, 1), "0") {
+		where = e.cast_where(c.who)
+	} else {
+		v := e.parse_exit_dir(c, e.cast_where(c.who), sout("use %d", sk_unbar_loc))
+		if v == nil {
+			return FALSE
+		}
+
+		where = v.destination
+	}
+
+	c.b = min(max(c.b, 1), 4)
+	aura := c.b
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	if e.loc_barrier(where) == 0 {
+		wout(c.who, "There is no barrier over %s.", e.box_name(where))
+		return FALSE
+	}
+
+	c.d = where
+	e.reset_cast_where(c.who)
+
+	return TRUE
+}
+
+// d_unbar_loc removes the barrier. One to four aura succeed 10%,
+// 25%, 50% or 75% of the time.
+// Ported from src/scry.c lines 690-759.
+func (e *Engine) d_unbar_loc(c *command) int {
+	aura := c.b
+	where := c.d
+
+	if e.kind(where) != T_loc {
+		wout(c.who, "%s is not a location.", e.box_code(where))
+		return FALSE
+	}
+
+	old_val := e.loc_barrier(where)
+
+	if old_val == 0 {
+		wout(c.who, "There is no barrier over %s.", e.box_name(where))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	var chance int
+	switch aura {
+	case 1:
+		chance = 10
+	case 2:
+		chance = 25
+	case 3:
+		chance = 50
+	case 4:
+		chance = 75
+	default:
+		panic("d_unbar_loc: aura out of range")
+	}
+
+	if e.rndFrom(streamMagic, 1, 100) > chance {
+		wout(c.who, "Attempt to remove barrier fails.")
+		return FALSE
+	}
+
+	e.p_loc(where).barrier = 0
+
+	wout(c.who, "The barrier over %s has been removed.", e.box_name(where))
+	wout(where, "The barrier over %s has dissipated.", e.box_name(where))
+
+	if old_val < 0 && e.kind(-old_val) == T_char {
+		wout(-old_val, "%s removed the barrier over %s.", e.box_name(c.who), e.box_name(where))
+	}
+
+	return TRUE
+}
+
+// v_proj_cast starts projecting the next cast to a location in the
+// same region, for one aura more than the distance.
+// Ported from src/scry.c lines 762-815.
+func (e *Engine) v_proj_cast(c *command) int {
+	if c.a == 0 {
+		c.a = e.subloc(c.who)
+	}
+	to_where := c.a
+
+	if !e.is_loc_or_ship(to_where) {
+		wout(c.who, "%s is not a location.", e.box_code(to_where))
+		return FALSE
+	}
+
+	if e.in_safe_now(to_where) {
+		wout(c.who, "Magic may not be projected to safe havens.")
+		return FALSE
+	}
+
+	distance := e.los_province_distance(e.cast_where(c.who), to_where)
+	if e.diff_region(e.cast_where(c.who), to_where) || distance < 0 {
+		wout(c.who, "Spells may not be projected to there from here.")
+		return FALSE
+	}
+
+	aura := distance + 1
+	c.d = aura
+
+	// Don't needlessly give away the exact distance with check_aura.
+	if e.char_cur_aura(c.who) < aura {
+		wout(c.who, "Not enough current aura.")
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to project next cast to %s.", e.box_name(to_where))
+
+	e.reset_cast_where(c.who)
+
+	return TRUE
+}
+
+// d_proj_cast bases the caster's next spell at the location, unless
+// its province is shrouded.
+// Ported from src/scry.c lines 818-849.
+func (e *Engine) d_proj_cast(c *command) int {
+	to_where := c.a
+	aura := c.d
+
+	if !e.is_loc_or_ship(to_where) {
+		wout(c.who, "%s is not a location.", e.box_code(to_where))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	if e.subloc(c.who) != to_where && e.loc_shroud(e.province(to_where)) != 0 {
+		wout(c.who, "%s is protected with a magical shroud.", e.box_name(to_where))
+		wout(c.who, "Spell fails.")
+		return FALSE
+	}
+
+	e.p_magic(c.who).project_cast = to_where
+
+	wout(c.who, "Next cast will be based from %s.", e.box_name(to_where))
+
+	return TRUE
+}
+
+// v_save_proj starts saving the projected cast into a potion.
+// Ported from src/scry.c lines 852-867.
+func (e *Engine) v_save_proj(c *command) int {
+	if !e.valid_box(e.char_proj_cast(c.who)) {
+		wout(c.who, "No projected cast state is active.")
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 3) {
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to save projected cast state.")
+	return TRUE
+}
+
+// d_save_proj moves the projected cast into a new potion.
+// Ported from src/scry.c lines 870-891.
+func (e *Engine) d_save_proj(c *command) int {
+	if !e.charge_aura(c.who, 3) {
+		return FALSE
+	}
+
+	newItem := e.new_potion(c.who)
+	if newItem < 0 {
+		wout(c.who, "Spell failed.")
+		return FALSE
+	}
+
+	p := e.p_magic(c.who)
+	im := e.p_item_magic(newItem)
+
+	im.use_key = use_proj_cast
+	im.project_cast = p.project_cast
+
+	p.project_cast = 0
+
+	return TRUE
+}
+
+// v_use_proj_cast drinks a potion of stored projected cast.
+// Ported from src/scry.c lines 894-922.
+func (e *Engine) v_use_proj_cast(c *command) int {
+	item := c.a
+
+	if e.kind(item) != T_item {
+		panic("v_use_proj_cast: not an item")
+	}
+
+	wout(c.who, "%s drinks the potion...", e.just_name(c.who))
+
+	im := e.rp_item_magic(item)
+	if im == nil || !e.is_loc_or_ship(im.project_cast) || e.is_magician(c.who) == 0 {
+		e.destroy_unique_item(c.who, item)
+		wout(c.who, "Nothing happens.")
+		return FALSE
+	}
+
+	e.p_magic(c.who).project_cast = im.project_cast
+
+	wout(c.who, "Project next cast to %s.", e.box_name(im.project_cast))
+	e.destroy_unique_item(c.who, item)
+
+	return TRUE
+}
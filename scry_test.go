@@ -0,0 +1,119 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// scry_test.go - Tests for scrying spells
+
+package taygete
+
+import "testing"
+
+func TestBarLocPermanent(t *testing.T) {
+	who := setupBasicTest(t, 40, 40)
+	where := teg.subloc(who)
+
+	c := &command{who: who, a: 5}
+	if teg.v_bar_loc(c) != TRUE || teg.d_bar_loc(c) != TRUE {
+		t.Fatal("bar_loc failed")
+	}
+	if got := teg.loc_barrier(where); got != 5 {
+		t.Fatalf("barrier = %d, want 5", got)
+	}
+
+	c = &command{who: who, a: 3}
+	if teg.v_bar_loc(c) != TRUE || teg.d_bar_loc(c) != TRUE {
+		t.Fatal("second bar_loc failed")
+	}
+	if got := teg.loc_barrier(where); got != -who {
+		t.Fatalf("barrier = %d, want permanent %d", got, -who)
+	}
+
+	c = &command{who: who, a: 1}
+	if got := teg.v_bar_loc(c); got != FALSE {
+		t.Errorf("v_bar_loc over a permanent barrier = %d, want FALSE", got)
+	}
+	if got := teg.char_cur_aura(who); got != 32 {
+		t.Errorf("aura = %d, want 32 after spending 5 and 3", got)
+	}
+}
+
+func TestShroudAndDispelRegion(t *testing.T) {
+	who := setupBasicTest(t, 40, 40)
+	where := teg.province(who)
+
+	c := &command{who: who, a: 3}
+	if teg.v_shroud_region(c) != TRUE || teg.d_shroud_region(c) != TRUE {
+		t.Fatal("shroud_region failed")
+	}
+	if got := teg.loc_shroud(where); got != 6 {
+		t.Fatalf("shroud = %d, want 6", got)
+	}
+
+	teg.dirty = map[int]bool{}
+	c = &command{who: who, a: where}
+	if teg.v_dispel_region(c) != TRUE || teg.d_dispel_region(c) != TRUE {
+		t.Fatal("dispel_region failed")
+	}
+	if got := teg.loc_shroud(where); got != 0 {
+		t.Errorf("shroud after dispel = %d, want 0", got)
+	}
+	if !teg.dirty[where] {
+		t.Error("dispelled location was not marked dirty")
+	}
+}
+
+func TestProjCastPotion(t *testing.T) {
+	who := setupBasicTest(t, 20, 20)
+	where := teg.subloc(who)
+
+	c := &command{who: who}
+	if got := teg.v_save_proj(c); got != FALSE {
+		t.Errorf("v_save_proj without a projection = %d, want FALSE", got)
+	}
+
+	c.a = where
+	if teg.v_proj_cast(c) != TRUE || teg.d_proj_cast(c) != TRUE {
+		t.Fatal("proj_cast failed")
+	}
+	if got := teg.char_proj_cast(who); got != where {
+		t.Fatalf("project_cast = %d, want %d", got, where)
+	}
+
+	c = &command{who: who}
+	if teg.v_save_proj(c) != TRUE || teg.d_save_proj(c) != TRUE {
+		t.Fatal("save_proj failed")
+	}
+	if got := teg.char_proj_cast(who); got != 0 {
+		t.Errorf("project_cast after saving = %d, want 0", got)
+	}
+
+	var potion int
+	for _, it := range teg.globals.inventories[who] {
+		if teg.item_use_key(it.item) == use_proj_cast {
+			potion = it.item
+		}
+	}
+	if potion == 0 {
+		t.Fatal("no projected cast potion was made")
+	}
+
+	c = &command{who: who, a: potion}
+	if got := teg.v_use_item(c); got != TRUE {
+		t.Fatalf("v_use_item(potion) = %d, want TRUE", got)
+	}
+	if got := teg.char_proj_cast(who); got != where {
+		t.Errorf("project_cast after drinking = %d, want %d", got, where)
+	}
+}
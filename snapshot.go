@@ -0,0 +1,287 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// snapshot.go - per-turn world snapshots and turn rollback
+
+package taygete
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrNoSnapshot is returned by RollbackTurn when no snapshot was taken
+// before the turn.
+var ErrNoSnapshot = errors.New("no snapshot for turn")
+
+// snapshotTable is a table copied into a turn snapshot. where, if set,
+// selects the rows that belong to the world; a ? in it stands for the
+// turn number.
+type snapshotTable struct {
+	name  string
+	where string
+}
+
+// snapshotTables returns the tables a turn snapshot covers: the game
+// clock and settings, the game seed and random streams, every table
+// SaveWorld writes and the orders given for the turn.
+func snapshotTables() []snapshotTable {
+	tables := []snapshotTable{
+		{name: "game_meta"},
+		{name: "system_config"},
+		{name: "rng_state"},
+		{name: "prng_state"},
+	}
+	for _, g := range saveGroups {
+		for _, t := range g.tables {
+			if t.name == "passwords" {
+				// Account passwords are not part of the world
+				tables = append(tables, snapshotTable{name: t.name, where: "key LIKE 'player:%'"})
+				continue
+			}
+			tables = append(tables, snapshotTable{name: t.name})
+		}
+	}
+	return append(tables, snapshotTable{name: "orders", where: "turn_number = ?"})
+}
+
+// whereClause returns the WHERE clause and arguments selecting the
+// table's rows for turn.
+func (t snapshotTable) whereClause(turn int) (string, []any) {
+	if t.where == "" {
+		return "", nil
+	}
+	var args []any
+	if strings.Contains(t.where, "?") {
+		args = append(args, turn)
+	}
+	return " WHERE " + t.where, args
+}
+
+// snapshotTurn saves the world and copies it into the snapshot for the
+// current turn, replacing any earlier snapshot of the same turn.
+// RunTurn calls it before processing orders.
+func (e *Engine) snapshotTurn() error {
+	if e.db == nil {
+		return nil
+	}
+	turn := int(e.globals.sysclock.turn)
+
+	if err := e.SaveWorld(); err != nil {
+		return fmt.Errorf("snapshot turn %d: %w", turn, err)
+	}
+
+	tx, err := e.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT OR IGNORE INTO turns (turn_number) VALUES (?)`, turn); err != nil {
+		return fmt.Errorf("insert turn %d: %w", turn, err)
+	}
+	if err := deleteSnapshots(tx, "turn_number = ?", turn); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO turn_snapshots (turn_number) VALUES (?)`, turn); err != nil {
+		return fmt.Errorf("insert snapshot %d: %w", turn, err)
+	}
+
+	for _, t := range snapshotTables() {
+		cols, err := tableColumns(tx, t.name)
+		if err != nil {
+			return err
+		}
+
+		// Blobs are kept as {"blob": hex} since JSON has no bytes
+		var exprs []string
+		for _, col := range cols {
+			exprs = append(exprs, fmt.Sprintf(
+				`CASE typeof(%[1]s) WHEN 'blob' THEN json_object('blob', hex(%[1]s)) ELSE %[1]s END`, col))
+		}
+		where, args := t.whereClause(turn)
+
+		var rows string
+		if err := tx.QueryRow(`SELECT json_group_array(json_array(`+strings.Join(exprs, ", ")+`))
+			FROM (SELECT * FROM `+t.name+where+` ORDER BY rowid)`, args...).Scan(&rows); err != nil {
+			return fmt.Errorf("snapshot %s: %w", t.name, err)
+		}
+
+		if _, err := tx.Exec(`
+			INSERT INTO turn_snapshot_tables (turn_number, table_name, columns, rows)
+			VALUES (?, ?, ?, ?)
+		`, turn, t.name, strings.Join(cols, ","), rows); err != nil {
+			return fmt.Errorf("snapshot %s: %w", t.name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RollbackTurn restores the world as it was before turn was processed
+// and loads it, so the turn can be run again. Everything the turn and
+// later turns produced is removed: reports, logs, combats, commands,
+// unsent mail, recorded outcomes, the orders for later turns and their
+// snapshots.
+func (e *Engine) RollbackTurn(turn int) error {
+	var n int
+	if err := e.db.QueryRow(`SELECT COUNT(*) FROM turn_snapshots WHERE turn_number = ?`, turn).Scan(&n); err != nil {
+		return fmt.Errorf("rollback turn %d: %w", turn, err)
+	} else if n == 0 {
+		return fmt.Errorf("rollback turn %d: %w", turn, ErrNoSnapshot)
+	}
+
+	tx, err := e.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Restored rows refer to each other in no particular order
+	if _, err := tx.Exec("PRAGMA defer_foreign_keys = ON"); err != nil {
+		return fmt.Errorf("defer foreign keys: %w", err)
+	}
+
+	for _, q := range []string{
+		`DELETE FROM combat_participants WHERE combat_id IN (SELECT id FROM combats WHERE turn_number > ?)`,
+		`DELETE FROM combats WHERE turn_number > ?`,
+		`DELETE FROM commands WHERE turn_number > ?`,
+		`DELETE FROM reports WHERE turn_number > ?`,
+		`DELETE FROM turn_logs WHERE turn_number > ?`,
+		`DELETE FROM outbox WHERE turn_number > ? AND status = 'pending'`,
+		`DELETE FROM orders WHERE turn_number > ?`,
+		`DELETE FROM turn_outcomes WHERE turn_number >= ?`,
+	} {
+		if _, err := tx.Exec(q, turn); err != nil {
+			return fmt.Errorf("rollback turn %d: %w", turn, err)
+		}
+	}
+	if err := deleteSnapshots(tx, "turn_number > ?", turn); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE turns SET status = 'pending', started_at = NULL, finished_at = NULL, day = 0
+		WHERE turn_number = ?
+	`, turn); err != nil {
+		return fmt.Errorf("rollback turn %d: %w", turn, err)
+	}
+
+	tables := snapshotTables()
+	for i := len(tables) - 1; i >= 0; i-- {
+		where, args := tables[i].whereClause(turn)
+		if _, err := tx.Exec("DELETE FROM "+tables[i].name+where, args...); err != nil {
+			return fmt.Errorf("clear %s: %w", tables[i].name, err)
+		}
+	}
+	for _, t := range tables {
+		if err := restoreSnapshotTable(tx, turn, t.name); err != nil {
+			return fmt.Errorf("restore %s: %w", t.name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	if err := e.LoadWorld(); err != nil {
+		return fmt.Errorf("rollback turn %d: %w", turn, err)
+	}
+	if err := e.restorePrngState("."); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("rollback turn %d: %w", turn, err)
+	}
+	if err := e.restorePrngStreams(); err != nil {
+		return fmt.Errorf("rollback turn %d: %w", turn, err)
+	}
+
+	return nil
+}
+
+// restoreSnapshotTable inserts the rows the snapshot of turn holds for
+// table. Columns dropped from the table since are skipped, and columns
+// added since get their defaults.
+func restoreSnapshotTable(tx *sql.Tx, turn int, table string) error {
+	var columns, rows string
+	err := tx.QueryRow(`
+		SELECT columns, rows FROM turn_snapshot_tables
+		WHERE turn_number = ? AND table_name = ?
+	`, turn, table).Scan(&columns, &rows)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	current, err := tableColumns(tx, table)
+	if err != nil {
+		return err
+	}
+
+	var cols, exprs []string
+	for i, col := range strings.Split(columns, ",") {
+		if !slices.Contains(current, col) {
+			continue
+		}
+		path := "$[" + strconv.Itoa(i) + "]"
+		cols = append(cols, col)
+		exprs = append(exprs, fmt.Sprintf(`CASE json_type(value, '%[1]s') WHEN 'object'
+			THEN unhex(json_extract(value, '%[1]s.blob')) ELSE json_extract(value, '%[1]s') END`, path))
+	}
+	if len(cols) == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(`INSERT INTO `+table+` (`+strings.Join(cols, ", ")+`)
+		SELECT `+strings.Join(exprs, ", ")+` FROM json_each(?) ORDER BY key`, rows)
+	return err
+}
+
+// deleteSnapshots removes the snapshots selected by where.
+func deleteSnapshots(tx *sql.Tx, where string, args ...any) error {
+	if _, err := tx.Exec(`DELETE FROM turn_snapshot_tables WHERE `+where, args...); err != nil {
+		return fmt.Errorf("delete snapshot tables: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM turn_snapshots WHERE `+where, args...); err != nil {
+		return fmt.Errorf("delete snapshots: %w", err)
+	}
+	return nil
+}
+
+// tableColumns returns the column names of table in order.
+func tableColumns(tx *sql.Tx, table string) ([]string, error) {
+	rows, err := tx.Query(`SELECT name FROM pragma_table_info(?) ORDER BY cid`, table)
+	if err != nil {
+		return nil, fmt.Errorf("columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var cols []string
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			return nil, fmt.Errorf("columns of %s: %w", table, err)
+		}
+		cols = append(cols, col)
+	}
+	return cols, rows.Err()
+}
@@ -0,0 +1,147 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package taygete
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestRollbackTurn(t *testing.T) {
+	g := newEngineGame(t, 1, "Red Company", "Osswid")
+	e, db := g.e, g.e.db
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	if err := e.LoadWorld(); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+	turn := int(e.globals.sysclock.turn)
+
+	// Orders for the turn are kept; orders for the next one are not
+	orders := func() int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM orders`).Scan(&n); err != nil {
+			t.Fatalf("count orders: %v", err)
+		}
+		return n
+	}
+	if _, err := db.Exec(`INSERT INTO turns (turn_number) VALUES (?)`, turn); err != nil {
+		t.Fatalf("insert turn: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO orders (turn_number, player_id, raw_text) VALUES (?, ?, 'study 600')`,
+		turn, g.pl); err != nil {
+		t.Fatalf("insert order: %v", err)
+	}
+
+	prngState := func() string {
+		t.Helper()
+		var state string
+		if err := db.QueryRow(`SELECT hex(state) FROM prng_state WHERE name = '.'`).Scan(&state); err != nil {
+			t.Fatalf("select prng_state: %v", err)
+		}
+		return state
+	}
+	reports := func() []string {
+		t.Helper()
+		rows, err := db.Query(`SELECT player_id || ':' || body FROM reports ORDER BY turn_number, player_id`)
+		if err != nil {
+			t.Fatalf("select reports: %v", err)
+		}
+		defer rows.Close()
+		var bodies []string
+		for rows.Next() {
+			var body string
+			if err := rows.Scan(&body); err != nil {
+				t.Fatalf("scan report: %v", err)
+			}
+			bodies = append(bodies, body)
+		}
+		return bodies
+	}
+
+	first, err := g.run(2)
+	if err != nil {
+		t.Fatalf("RunTurn: %v", err)
+	}
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	firstReports := reports()
+	if _, err := db.Exec(`INSERT INTO orders (turn_number, player_id, raw_text) VALUES (?, ?, 'move out')`,
+		turn+2, g.pl); err != nil {
+		t.Fatalf("insert order: %v", err)
+	}
+
+	// Roll back to the snapshot taken before the first turn
+	if err := e.RollbackTurn(turn); err != nil {
+		t.Fatalf("RollbackTurn: %v", err)
+	}
+	if got := int(e.globals.sysclock.turn); got != turn {
+		t.Errorf("clock after rollback = %d, want %d", got, turn)
+	}
+	if got := reports(); len(got) != 0 {
+		t.Errorf("reports after rollback = %v, want none", got)
+	}
+	if got := orders(); got != 1 {
+		t.Errorf("orders after rollback = %d, want 1", got)
+	}
+	var snapshots int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM turn_snapshots`).Scan(&snapshots); err != nil {
+		t.Fatalf("count snapshots: %v", err)
+	}
+	if snapshots != 1 {
+		t.Errorf("snapshots after rollback = %d, want 1", snapshots)
+	}
+
+	// The world in the database matches the snapshot when saved again
+	restored, state := dumpSavedTables(t, db), prngState()
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	if again := dumpSavedTables(t, db); !maps.EqualFunc(again, restored, slices.Equal) {
+		t.Errorf("saving the restored world changed it")
+	}
+
+	// Running the turns again gives the same results
+	second, err := g.run(2)
+	if err != nil {
+		t.Fatalf("RunTurn: %v", err)
+	}
+	if !slices.Equal(second, first) {
+		t.Errorf("rerun turns:\n got %q\nwant %q", second, first)
+	}
+	if got := reports(); !slices.Equal(got, firstReports) {
+		t.Errorf("rerun reports differ:\n got %q\nwant %q", got, firstReports)
+	}
+	if err := e.RollbackTurn(turn); err != nil {
+		t.Fatalf("second RollbackTurn: %v", err)
+	}
+	if got := prngState(); got != state {
+		t.Errorf("prng state after second rollback = %s, want %s", got, state)
+	}
+}
+
+func TestRollbackTurnWithoutSnapshot(t *testing.T) {
+	e := newEngineGame(t, 1, "Red Company", "Osswid").e
+	if err := e.RollbackTurn(99); !errors.Is(err, ErrNoSnapshot) {
+		t.Errorf("RollbackTurn(99) = %v, want ErrNoSnapshot", err)
+	}
+}
@@ -21,8 +21,8 @@ package taygete
 // here_pos returns the position of who in its location's here_list.
 // Panics if who is not found.
 // Ported from src/stack.c lines 8-23.
-func here_pos(who int) int {
-	p := rp_loc_info(loc(who))
+func (e *Engine) here_pos(who int) int {
+	p := e.rp_loc_info(e.loc(who))
 	if p == nil {
 		panic("here_pos: nil loc_info")
 	}
@@ -38,12 +38,12 @@ func here_pos(who int) int {
 // here_precedes returns true if a comes before b in the here list.
 // Returns false if they're in different locations.
 // Ported from src/stack.c lines 30-51.
-func here_precedes(a, b int) bool {
-	if loc(a) != loc(b) {
+func (e *Engine) here_precedes(a, b int) bool {
+	if e.loc(a) != e.loc(b) {
 		return false
 	}
 
-	p := rp_loc_info(loc(a))
+	p := e.rp_loc_info(e.loc(a))
 	if p == nil {
 		panic("here_precedes: nil loc_info")
 	}
@@ -62,14 +62,14 @@ func here_precedes(a, b int) bool {
 // first_prisoner_pos returns the index of the first prisoner in where's here_list.
 // Returns -1 if no prisoner is found.
 // Ported from src/stack.c lines 54-73.
-func first_prisoner_pos(where int) int {
-	p := rp_loc_info(where)
+func (e *Engine) first_prisoner_pos(where int) int {
+	p := e.rp_loc_info(where)
 	if p == nil {
 		return -1
 	}
 
 	for i, id := range p.here_list {
-		if kind(id) == T_char && is_prisoner(id) {
+		if e.kind(id) == T_char && e.is_prisoner(id) {
 			return i
 		}
 	}
@@ -80,9 +80,9 @@ func first_prisoner_pos(where int) int {
 // stack_parent returns the character that who is stacked under.
 // Returns 0 if who is not stacked under a character.
 // Ported from src/stack.c lines 76-87.
-func stack_parent(who int) int {
-	n := loc(who)
-	if kind(n) == T_char {
+func (e *Engine) stack_parent(who int) int {
+	n := e.loc(who)
+	if e.kind(n) == T_char {
 		return n
 	}
 	return 0
@@ -91,16 +91,16 @@ func stack_parent(who int) int {
 // stack_leader returns the topmost character in a stack.
 // Walks up the stack until it finds a character not stacked under another character.
 // Ported from src/stack.c lines 90-108.
-func stack_leader(who int) int {
-	if kind(who) != T_char {
+func (e *Engine) stack_leader(who int) int {
+	if e.kind(who) != T_char {
 		panic("stack_leader: who is not a character")
 	}
 
 	count := 0
 	n := who
-	for kind(n) == T_char {
+	for e.kind(n) == T_char {
 		who = n
-		n = stack_parent(n)
+		n = e.stack_parent(n)
 
 		count++
 		if count >= 1000 {
@@ -114,11 +114,11 @@ func stack_leader(who int) int {
 // stacked_beneath returns true if b is stacked somewhere beneath a.
 // Both a and b must be characters.
 // Ported from src/stack.c lines 115-133.
-func stacked_beneath(a, b int) bool {
-	if kind(a) != T_char {
+func (e *Engine) stacked_beneath(a, b int) bool {
+	if e.kind(a) != T_char {
 		panic("stacked_beneath: a is not a character")
 	}
-	if kind(b) != T_char {
+	if e.kind(b) != T_char {
 		panic("stacked_beneath: b is not a character")
 	}
 
@@ -127,7 +127,7 @@ func stacked_beneath(a, b int) bool {
 	}
 
 	for b > 0 {
-		b = stack_parent(b)
+		b = e.stack_parent(b)
 		if a == b {
 			return true
 		}
@@ -139,8 +139,8 @@ func stacked_beneath(a, b int) bool {
 // promote moves who to position new_pos in its location's here_list.
 // who must already be at or after new_pos.
 // Ported from src/stack.c lines 136-153.
-func promote(who, new_pos int) {
-	p := rp_loc_info(loc(who))
+func (e *Engine) promote(who, new_pos int) {
+	p := e.rp_loc_info(e.loc(who))
 	if p == nil {
 		panic("promote: nil loc_info")
 	}
@@ -158,97 +158,97 @@ func promote(who, new_pos int) {
 
 // unstack removes who from its current stack and places it in the subloc.
 // Ported from src/stack.c lines 156-187.
-func unstack(who int) {
-	leader := stack_leader(who)
-	if !valid_box(leader) {
+func (e *Engine) unstack(who int) {
+	leader := e.stack_leader(who)
+	if !e.valid_box(leader) {
 		panic("unstack: invalid leader")
 	}
 
-	if subloc(leader) != loc(leader) {
+	if e.subloc(leader) != e.loc(leader) {
 		panic("unstack: subloc(leader) != loc(leader)")
 	}
 
-	if release_swear(who) != 0 {
-		p_magic(who).swear_on_release = 0
+	if e.release_swear(who) != 0 {
+		e.p_magic(who).swear_on_release = 0
 	}
 
-	set_where(who, subloc(leader))
-	promote(who, here_pos(leader)+1)
+	e.set_where(who, e.subloc(leader))
+	e.promote(who, e.here_pos(leader)+1)
 
 	restore_stack_actions(who)
 
-	if loyal_kind(who) == LOY_summon {
-		set_loyal(who, LOY_npc, 0)
+	if e.loyal_kind(who) == LOY_summon {
+		e.set_loyal(who, LOY_npc, 0)
 	}
 }
 
 // leave_stack removes who from their current stack (if any) and outputs messages.
 // Ported from src/stack.c lines 190-207.
-func leave_stack(who int) {
-	leader := stack_parent(who)
+func (e *Engine) leave_stack(who int) {
+	leader := e.stack_parent(who)
 	if leader <= 0 {
 		return
 	}
 
-	wout(leader, "%s unstacks from us.", box_name(who))
-	wout(who, "%s unstacks from %s.", box_name(who), box_name(leader))
+	wout(leader, "%s unstacks from us.", e.box_name(who))
+	wout(who, "%s unstacks from %s.", e.box_name(who), e.box_name(leader))
 
 	vector_char_here(who)
-	wout(VECT, "%s unstacks from %s.", box_name(who), box_name(leader))
+	wout(VECT, "%s unstacks from %s.", e.box_name(who), e.box_name(leader))
 
-	unstack(who)
+	e.unstack(who)
 }
 
 // stack places who under target in the stacking hierarchy.
 // who must not already be stacked under a character.
 // Ported from src/stack.c lines 210-231.
-func stack(who, target int) {
-	if stack_parent(who) != 0 {
+func (e *Engine) stack(who, target int) {
+	if e.stack_parent(who) != 0 {
 		panic("stack: who is already stacked")
 	}
 
-	set_where(who, target)
-	p_char(who).moving = char_moving(target)
+	e.set_where(who, target)
+	e.p_char(who).moving = e.char_moving(target)
 
-	if !is_prisoner(who) {
-		pos := first_prisoner_pos(target)
+	if !e.is_prisoner(who) {
+		pos := e.first_prisoner_pos(target)
 		if pos >= 0 {
-			promote(who, pos)
+			e.promote(who, pos)
 		}
 	}
 }
 
 // join_stack makes who stack beneath target.
 // Ported from src/stack.c lines 234-252.
-func join_stack(who, target int) {
-	if stacked_beneath(who, target) {
+func (e *Engine) join_stack(who, target int) {
+	if e.stacked_beneath(who, target) {
 		panic("join_stack: who is already beneath target")
 	}
-	if is_prisoner(target) {
+	if e.is_prisoner(target) {
 		panic("join_stack: target is a prisoner")
 	}
 
-	leave_stack(who)
+	e.leave_stack(who)
 
-	if subloc(target) != subloc(who) {
+	if e.subloc(target) != e.subloc(who) {
 		panic("join_stack: subloc mismatch")
 	}
 
-	wout(who, "%s stacks beneath %s.", box_name(who), box_name(target))
-	wout(target, "%s stacks beneath us.", box_name(who))
+	wout(who, "%s stacks beneath %s.", e.box_name(who), e.box_name(target))
+	wout(target, "%s stacks beneath us.", e.box_name(who))
 
 	vector_char_here(who)
-	wout(VECT, "%s stacks beneath %s.", box_name(who), box_name(target))
+	wout(VECT, "%s stacks beneath %s.", e.box_name(who), e.box_name(target))
 
-	stack(who, target)
+	e.stack(who, target)
 }
 
 // check_prisoner_escape checks if a prisoner escapes.
 // Returns true if the prisoner escapes, false otherwise.
 // Ported from src/stack.c lines 255-286.
-func check_prisoner_escape(who, chance int) bool {
-	leader := stack_parent(who)
-	hound := stack_has_item(who, item_hound)
+func (e *Engine) check_prisoner_escape(who, chance int) bool {
+	leader := e.stack_parent(who)
+	hound := e.stack_has_item(who, item_hound)
 
 	chance *= 10
 
@@ -256,7 +256,7 @@ func check_prisoner_escape(who, chance int) bool {
 		chance /= 2
 	}
 
-	n := rnd(1, 1000)
+	n := e.rndFrom(streamCombat, 1, 1000)
 
 	if n > chance {
 		if hound > 0 {
@@ -271,40 +271,40 @@ func check_prisoner_escape(who, chance int) bool {
 		return false
 	}
 
-	prisoner_escapes(who)
+	e.prisoner_escapes(who)
 	return true
 }
 
 // prisoner_escapes handles a prisoner escaping.
 // Ported from src/stack.c lines 289-328.
-func prisoner_escapes(who int) {
-	leader := stack_parent(who)
+func (e *Engine) prisoner_escapes(who int) {
+	leader := e.stack_parent(who)
 
-	wout(leader, "Prisoner %s escaped!", box_name(who))
-	p_char(who).prisoner = 0
-	p_magic(who).swear_on_release = 0
-	unstack(who)
+	wout(leader, "Prisoner %s escaped!", e.box_name(who))
+	e.p_char(who).prisoner = 0
+	e.p_magic(who).swear_on_release = 0
+	e.unstack(who)
 	touch_loc(who)
 
 	wout(who, "We escaped!")
 
-	where := subloc(who)
+	where := e.subloc(who)
 
-	if loc_depth(where) <= LOC_province {
+	if e.loc_depth(where) <= LOC_province {
 		return
 	}
 
-	out_one := loc(where)
+	out_one := e.loc(where)
 
-	if is_ship(where) && subkind(out_one) == sub_ocean {
-		out_one = find_nearest_land(out_one)
+	if e.is_ship(where) && e.subkind(out_one) == sub_ocean {
+		out_one = e.find_nearest_land(out_one)
 
 		wout(who, "After jumping over the side of the boat and "+
 			"enduring a long, grueling, swim, we finally "+
-			"washed ashore at %s.", box_name(out_one))
+			"washed ashore at %s.", e.box_name(out_one))
 
 		wout(leader, "%s jumped overboard and presumably "+
-			"drowned.", just_name(who))
+			"drowned.", e.just_name(who))
 
 		log_write(LOG_SPECIAL, "!! Someone swam ashore, who=%s",
 			box_code_less(who))
@@ -315,39 +315,39 @@ func prisoner_escapes(who int) {
 
 // prisoner_movement_escape_check checks all prisoners under who for escape.
 // Ported from src/stack.c lines 331-342.
-func prisoner_movement_escape_check(who int) {
+func (e *Engine) prisoner_movement_escape_check(who int) {
 	var chars []int
-	all_char_here(who, &chars)
+	e.all_char_here(who, &chars)
 
 	for _, i := range chars {
-		if is_prisoner(i) {
-			check_prisoner_escape(i, 2)
+		if e.is_prisoner(i) {
+			e.check_prisoner_escape(i, 2)
 		}
 	}
 }
 
 // weekly_prisoner_escape_check performs weekly escape checks for all prisoners.
 // Ported from src/stack.c lines 345-371.
-func weekly_prisoner_escape_check() {
-	for who := kind_first(T_char); who > 0; who = kind_next(who) {
-		if is_prisoner(who) {
+func (e *Engine) weekly_prisoner_escape_check() {
+	for who := e.kind_first(T_char); who > 0; who = e.kind_next(who) {
+		if e.is_prisoner(who) {
 			continue
 		}
 
-		if subkind(subloc(who)) == sub_ocean {
+		if e.subkind(e.subloc(who)) == sub_ocean {
 			continue
 		}
 
 		var hereList []int
-		all_here(who, &hereList)
+		e.all_here(who, &hereList)
 
 		for _, i := range hereList {
-			if kind(i) == T_char && is_prisoner(i) && release_swear(i) == 0 {
+			if e.kind(i) == T_char && e.is_prisoner(i) && e.release_swear(i) == 0 {
 				chance := 2
-				if loc_depth(subloc(who)) >= LOC_build {
+				if e.loc_depth(e.subloc(who)) >= LOC_build {
 					chance = 1
 				}
-				check_prisoner_escape(i, chance)
+				e.check_prisoner_escape(i, chance)
 			}
 		}
 	}
@@ -355,118 +355,118 @@ func weekly_prisoner_escape_check() {
 
 // drop_stack drops to_drop from who's stack.
 // Ported from src/stack.c lines 374-440.
-func drop_stack(who, to_drop int) {
-	if stack_parent(to_drop) != who {
+func (e *Engine) drop_stack(who, to_drop int) {
+	if e.stack_parent(to_drop) != who {
 		panic("drop_stack: to_drop is not stacked under who")
 	}
 
 	release_swear_flag := false
 
-	if is_prisoner(to_drop) {
-		p_char(to_drop).prisoner = 0
+	if e.is_prisoner(to_drop) {
+		e.p_char(to_drop).prisoner = 0
 		touch_loc(to_drop)
-		wout(who, "Freed prisoner %s.", box_name(to_drop))
-		wout(to_drop, "%s set us free.", box_name(who))
+		wout(who, "Freed prisoner %s.", e.box_name(to_drop))
+		wout(to_drop, "%s set us free.", e.box_name(who))
 
-		if release_swear(to_drop) != 0 {
+		if e.release_swear(to_drop) != 0 {
 			release_swear_flag = true
 		}
 	} else {
-		wout(who, "Dropped %s from stack.", box_name(to_drop))
-		wout(to_drop, "%s dropped us from the stack.", box_name(who))
+		wout(who, "Dropped %s from stack.", e.box_name(to_drop))
+		wout(to_drop, "%s dropped us from the stack.", e.box_name(who))
 
 		vector_char_here(to_drop)
-		wout(VECT, "%s dropped %s from the stack.", box_name(who), box_name(to_drop))
+		wout(VECT, "%s dropped %s from the stack.", e.box_name(who), e.box_name(to_drop))
 	}
 
-	unstack(to_drop)
+	e.unstack(to_drop)
 
 	if release_swear_flag {
-		log_write(LOG_SPECIAL, "%s frees a swear_on_release prisoner", box_name(who))
+		log_write(LOG_SPECIAL, "%s frees a swear_on_release prisoner", e.box_name(who))
 
-		if rnd(1, 5) < 5 {
-			wout(who, "%s is grateful for your gallantry.", box_name(to_drop))
-			wout(who, "%s pledges fealty to us.", box_name(to_drop))
+		if e.rndFrom(streamCombat, 1, 5) < 5 {
+			wout(who, "%s is grateful for your gallantry.", e.box_name(to_drop))
+			wout(who, "%s pledges fealty to us.", e.box_name(to_drop))
 
-			set_lord(to_drop, player(who), LOY_oath, 1)
+			e.set_lord(to_drop, e.player(who), LOY_oath, 1)
 		} else {
-			switch rnd(1, 3) {
+			switch e.rndFrom(streamCombat, 1, 3) {
 			case 1:
-				wout(who, "%s spits on you, and vanishes in a cloud of orange smoke.", box_name(to_drop))
+				wout(who, "%s spits on you, and vanishes in a cloud of orange smoke.", e.box_name(to_drop))
 			case 2:
-				wout(who, "%s cackles wildly and vanishes.", box_name(to_drop))
+				wout(who, "%s cackles wildly and vanishes.", e.box_name(to_drop))
 			case 3:
-				wout(who, "%s smiles briefly at you, then vanishes.", box_name(to_drop))
+				wout(who, "%s smiles briefly at you, then vanishes.", e.box_name(to_drop))
 			}
 
-			unit_deserts(to_drop, 0, true, LOY_unsworn, 0)
-			put_back_cookie(to_drop)
-			set_where(to_drop, 0)
-			change_box_kind(to_drop, T_deadchar)
+			e.unit_deserts(to_drop, 0, true, LOY_unsworn, 0)
+			e.put_back_cookie(to_drop)
+			e.set_where(to_drop, 0)
+			e.change_box_kind(to_drop, T_deadchar)
 		}
 	}
 }
 
 // free_all_prisoners frees all prisoners stacked under who.
 // Ported from src/stack.c lines 443-454.
-func free_all_prisoners(who int) {
+func (e *Engine) free_all_prisoners(who int) {
 	var hereList []int
-	all_here(who, &hereList)
+	e.all_here(who, &hereList)
 
 	for _, i := range hereList {
-		if kind(i) == T_char && is_prisoner(i) {
-			drop_stack(who, i)
+		if e.kind(i) == T_char && e.is_prisoner(i) {
+			e.drop_stack(who, i)
 		}
 	}
 }
 
 // extract_stacked_unit removes who from a stack, leaving those above and below behind.
 // Ported from src/stack.c lines 464-518.
-func extract_stacked_unit(who int) {
+func (e *Engine) extract_stacked_unit(who int) {
 	var first int
 
 	var hereList []int
-	all_here(who, &hereList)
+	e.all_here(who, &hereList)
 
 	for _, i := range hereList {
-		if kind(i) == T_char && !is_prisoner(i) {
+		if e.kind(i) == T_char && !e.is_prisoner(i) {
 			first = i
 			break
 		}
 	}
 
 	if first != 0 {
-		all_here(who, &hereList)
+		e.all_here(who, &hereList)
 		for _, i := range hereList {
-			if i != first && kind(i) == T_char {
-				set_where(i, first)
+			if i != first && e.kind(i) == T_char {
+				e.set_where(i, first)
 			}
 		}
 
-		set_where(first, loc(who))
-		promote(first, here_pos(who)+1)
+		e.set_where(first, e.loc(who))
+		e.promote(first, e.here_pos(who)+1)
 	}
 
-	all_here(who, &hereList)
+	e.all_here(who, &hereList)
 	for _, i := range hereList {
-		if kind(i) == T_char && is_prisoner(i) {
-			prisoner_escapes(i)
+		if e.kind(i) == T_char && e.is_prisoner(i) {
+			e.prisoner_escapes(i)
 		}
 	}
 
-	leave_stack(who)
+	e.leave_stack(who)
 }
 
 // promote_stack promotes lower to be before higher in the location order.
 // Ported from src/stack.c lines 525-556.
-func promote_stack(lower, higher int) {
-	if stacked_beneath(lower, higher) {
+func (e *Engine) promote_stack(lower, higher int) {
+	if e.stacked_beneath(lower, higher) {
 		panic("promote_stack: lower is beneath higher")
 	}
 
-	set_where(lower, loc(higher))
+	e.set_where(lower, e.loc(higher))
 
-	p := rp_loc_info(loc(higher))
+	p := e.rp_loc_info(e.loc(higher))
 	if p == nil {
 		panic("promote_stack: nil loc_info")
 	}
@@ -476,68 +476,68 @@ func promote_stack(lower, higher int) {
 	}
 
 	pos := IListLookup(p.here_list, higher)
-	promote(lower, pos)
+	e.promote(lower, pos)
 
-	wout(higher, "Promoted %s.", box_name(lower))
-	wout(lower, "%s promoted us.", box_name(higher))
+	wout(higher, "Promoted %s.", e.box_name(lower))
+	wout(lower, "%s promoted us.", e.box_name(higher))
 }
 
 // take_prisoner makes who take target prisoner.
 // Ported from src/stack.c lines 559-610.
-func take_prisoner(who, target int) {
+func (e *Engine) take_prisoner(who, target int) {
 	if who == target {
 		panic("take_prisoner: who == target")
 	}
-	if kind(who) != T_char {
+	if e.kind(who) != T_char {
 		panic("take_prisoner: who is not a character")
 	}
-	if kind(target) != T_char {
+	if e.kind(target) != T_char {
 		panic("take_prisoner: target is not a character")
 	}
 
 	ni := false
-	if subkind(target) == sub_ni && beast_capturable(target) {
+	if e.subkind(target) == sub_ni && beast_capturable(target) {
 		ni = true
 	}
 
-	vector_stack(stack_leader(target), true)
+	vector_stack(e.stack_leader(target), true)
 	vector_add(who)
 
 	if ni {
-		wout(VECT, "%s disbands.", box_name(target))
+		wout(VECT, "%s disbands.", e.box_name(target))
 	} else {
-		wout(VECT, "%s is taken prisoner by %s.", box_name(target), box_name(who))
+		wout(VECT, "%s is taken prisoner by %s.", e.box_name(target), e.box_name(who))
 	}
 
-	p_char(target).prisoner = 1
+	e.p_char(target).prisoner = 1
 
 	if ni {
-		take_unit_items(target, who, TAKE_NI)
+		e.take_unit_items(target, who, TAKE_NI)
 	} else {
-		take_unit_items(target, who, TAKE_ALL)
+		e.take_unit_items(target, who, TAKE_ALL)
 	}
 
-	extract_stacked_unit(target)
-	interrupt_order(target)
+	e.extract_stacked_unit(target)
+	e.interrupt_order(target)
 
 	if ni {
-		unit_deserts(target, 0, true, LOY_unsworn, 0)
-		put_back_cookie(target)
-		set_where(target, 0)
-		change_box_kind(target, T_deadchar)
+		e.unit_deserts(target, 0, true, LOY_unsworn, 0)
+		e.put_back_cookie(target)
+		e.set_where(target, 0)
+		e.change_box_kind(target, T_deadchar)
 	} else {
-		stack(target, who)
+		e.stack(target, who)
 	}
 }
 
 // has_prisoner returns true if who has pris as a prisoner.
 // Ported from src/stack.c lines 613-630.
-func has_prisoner(who, pris int) bool {
+func (e *Engine) has_prisoner(who, pris int) bool {
 	var hereList []int
-	all_here(who, &hereList)
+	e.all_here(who, &hereList)
 
 	for _, i := range hereList {
-		if i == pris && is_prisoner(i) {
+		if i == pris && e.is_prisoner(i) {
 			return true
 		}
 	}
@@ -547,17 +547,17 @@ func has_prisoner(who, pris int) bool {
 
 // move_prisoner moves pris from who to target.
 // Ported from src/stack.c lines 633-647.
-func move_prisoner(who, target, pris int) int {
-	rs := release_swear(pris)
+func (e *Engine) move_prisoner(who, target, pris int) int {
+	rs := e.release_swear(pris)
 
-	unstack(pris)
-	stack(pris, target)
+	e.unstack(pris)
+	e.stack(pris, target)
 
 	if rs != 0 {
-		p_magic(pris).swear_on_release = 1
+		e.p_magic(pris).swear_on_release = 1
 	}
 
-	if rp_char(pris).prisoner == 0 {
+	if e.rp_char(pris).prisoner == 0 {
 		panic("move_prisoner: pris is not a prisoner")
 	}
 
@@ -567,25 +567,25 @@ func move_prisoner(who, target, pris int) int {
 // give_prisoner transfers pris from who to target.
 // Returns true on success.
 // Ported from src/stack.c lines 650-666.
-func give_prisoner(who, target, pris int) bool {
-	if check_prisoner_escape(pris, 2) {
+func (e *Engine) give_prisoner(who, target, pris int) bool {
+	if e.check_prisoner_escape(pris, 2) {
 		return false
 	}
 
-	move_prisoner(who, target, pris)
+	e.move_prisoner(who, target, pris)
 
-	wout(who, "Transferred prisoner %s to %s.", box_name(pris), box_name(target))
-	wout(target, "%s transferred the prisoner %s to us.", box_name(who), box_name(pris))
+	wout(who, "Transferred prisoner %s to %s.", e.box_name(pris), e.box_name(target))
+	wout(target, "%s transferred the prisoner %s to us.", e.box_name(who), e.box_name(pris))
 
 	return true
 }
 
 // v_stack is the STACK command handler.
 // Ported from src/stack.c lines 669-707.
-func v_stack(c *command) int {
+func (e *Engine) v_stack(c *command) int {
 	target := c.a
 
-	if !check_char_gone(c.who, target) {
+	if !e.check_char_gone(c.who, target) {
 		return FALSE
 	}
 
@@ -594,97 +594,97 @@ func v_stack(c *command) int {
 		return FALSE
 	}
 
-	if stacked_beneath(c.who, target) {
+	if e.stacked_beneath(c.who, target) {
 		wout(c.who, "Cannot stack beneath %s since %s is stacked under you.",
-			box_name(target), just_name(target))
+			e.box_name(target), e.just_name(target))
 		return FALSE
 	}
 
-	if is_prisoner(target) {
+	if e.is_prisoner(target) {
 		wout(c.who, "Can't stack beneath prisoners.")
 		return FALSE
 	}
 
-	if !will_admit(target, c.who, target) {
-		wout(c.who, "%s refuses to let us stack.", box_name(target))
-		wout(target, "Refused to let %s stack with us.", box_name(c.who))
+	if !e.will_admit(target, c.who, target) {
+		wout(c.who, "%s refuses to let us stack.", e.box_name(target))
+		wout(target, "Refused to let %s stack with us.", e.box_name(c.who))
 		return FALSE
 	}
 
-	join_stack(c.who, target)
+	e.join_stack(c.who, target)
 	return TRUE
 }
 
 // v_unstack is the UNSTACK command handler.
 // Ported from src/stack.c lines 710-753.
-func v_unstack(c *command) int {
+func (e *Engine) v_unstack(c *command) int {
 	target := c.a
 
-	if numargs(c) < 1 {
-		if stack_parent(c.who) <= 0 {
+	if e.numargs(c) < 1 {
+		if e.stack_parent(c.who) <= 0 {
 			wout(c.who, "Not stacked under anyone.")
 			return FALSE
 		}
 
-		leave_stack(c.who)
+		e.leave_stack(c.who)
 		return TRUE
 	}
 
 	if c.who == target {
-		extract_stacked_unit(c.who)
+		e.extract_stacked_unit(c.who)
 		return TRUE
 	}
 
-	if !valid_box(target) || stack_parent(target) != c.who {
+	if !e.valid_box(target) || e.stack_parent(target) != c.who {
 		wout(c.who, "%s is not stacked beneath us.", get_parse_arg(c, 1))
 		return FALSE
 	}
 
-	drop_stack(c.who, target)
+	e.drop_stack(c.who, target)
 	return TRUE
 }
 
 // v_surrender is the SURRENDER command handler.
 // Ported from src/stack.c lines 756-787.
-func v_surrender(c *command) int {
+func (e *Engine) v_surrender(c *command) int {
 	target := c.a
 
-	if !check_char_gone(c.who, target) {
+	if !e.check_char_gone(c.who, target) {
 		return FALSE
 	}
 
-	if player(target) == player(c.who) {
+	if e.player(target) == e.player(c.who) {
 		wout(c.who, "Can't surrender to oneself.")
 		return FALSE
 	}
 
-	if is_prisoner(target) {
+	if e.is_prisoner(target) {
 		wout(c.who, "Can't surrender to a prisoner.")
 		return FALSE
 	}
 
 	log_write(LOG_SPECIAL, "Player %s surrenders %s",
-		box_code_less(player(c.who)), box_name(c.who))
+		box_code_less(e.player(c.who)), e.box_name(c.who))
 
-	vector_stack(stack_leader(c.who), true)
-	vector_stack(stack_leader(target), false)
+	vector_stack(e.stack_leader(c.who), true)
+	vector_stack(e.stack_leader(target), false)
 
-	wout(VECT, "%s surrenders to %s.", box_name(c.who), box_name(target))
+	wout(VECT, "%s surrenders to %s.", e.box_name(c.who), e.box_name(target))
 
-	take_prisoner(target, c.who)
+	e.take_prisoner(target, c.who)
 	return TRUE
 }
 
 // promote_after returns true if b appears later in location order than a.
 // Ported from src/stack.c lines 794-827.
-func promote_after(a, b int) bool {
-	where := subloc(a)
-	if subloc(b) != where {
+func (e *Engine) promote_after(a, b int) bool {
+	where := e.subloc(a)
+	if e.subloc(b) != where {
 		panic("promote_after: different sublocs")
 	}
 
 	var chars []int
-	all_char_here(where, &chars)
+	e.all_char_here(where, &chars)
 
 	for _, i := range chars {
 		if i == a {
@@ -699,27 +699,27 @@ func promote_after(a, b int) bool {
 
 // v_promote is the PROMOTE command handler.
 // Ported from src/stack.c lines 830-891.
-func v_promote(c *command) int {
+func (e *Engine) v_promote(c *command) int {
 	target := c.a
 
-	if numargs(c) < 1 {
+	if e.numargs(c) < 1 {
 		wout(c.who, "Must specify which character to promote.")
 		return FALSE
 	}
 
-	if kind(target) != T_char {
+	if e.kind(target) != T_char {
 		wout(c.who, "%s is not a character.", get_parse_arg(c, 1))
 		return FALSE
 	}
 
-	if is_prisoner(target) {
+	if e.is_prisoner(target) {
 		wout(c.who, "Can't promote prisoners.")
 		return FALSE
 	}
 
-	targ_par := stack_parent(target)
+	targ_par := e.stack_parent(target)
 
-	if !check_char_here(c.who, target) {
+	if !e.check_char_here(c.who, target) {
 		return FALSE
 	}
 
@@ -728,19 +728,19 @@ func v_promote(c *command) int {
 		return FALSE
 	}
 
-	if player(c.who) == player(target) {
-		if !promote_after(c.who, target) {
-			wout(c.who, "%s already comes before us in location order.", box_name(target))
+	if e.player(c.who) == e.player(target) {
+		if !e.promote_after(c.who, target) {
+			wout(c.who, "%s already comes before us in location order.", e.box_name(target))
 			return FALSE
 		}
-	} else if targ_par != c.who && !here_precedes(c.who, target) {
+	} else if targ_par != c.who && !e.here_precedes(c.who, target) {
 		wout(c.who, "Characters to be promoted must be stacked "+
 			"immediately beneath the promoter, or be listed after the "+
 			"promoter at the same level.")
 		return FALSE
 	}
 
-	promote_stack(target, c.who)
+	e.promote_stack(target, c.who)
 	return TRUE
 }
 
@@ -779,6 +779,11 @@ func touch_loc(who int) {
 	// TODO: Implement in later sprint (day.c)
 }
 
+// Deprecated: touch_loc_pl not yet implemented.
+func touch_loc_pl(pl, where int) {
+	// TODO: Implement in later sprint (day.c)
+}
+
 // Note: find_nearest_land implemented in destruction.go
 
 // Deprecated: move_stack not yet implemented.
@@ -788,12 +793,13 @@ func move_stack(who, dest int) {
 
 // set_lord sets the lord (owner) of a character.
 // Ported from src/swear.c (simplified implementation for Sprint 25.8).
-func set_lord(who, new_lord, k, lev int) {
-	c := rp_char(who)
+func (e *Engine) set_lord(who, new_lord, k, lev int) {
+	c := e.rp_char(who)
 	if c == nil {
 		return
 	}
 
+	e.markDirty(who)
 	oldLord := c.unit_lord
 	c.unit_lord = new_lord
 	c.loy_kind = schar(k)
@@ -804,42 +810,46 @@ func set_lord(who, new_lord, k, lev int) {
 	}
 }
 
-// Deprecated: set_loyal not yet implemented.
-func set_loyal(who, k, lev int) {
-	// TODO: Implement in later sprint (loyalty)
+// set_loyal sets the kind and rate of a character's loyalty.
+// Ported from src/swear.c lines 40-49.
+func (e *Engine) set_loyal(who, k, lev int) {
+	p := e.p_char(who)
+
+	p.loy_kind = schar(k)
+	p.loy_rate = lev
 }
 
 // unit_deserts handles a unit deserting to a new player.
 // Ported from src/swear.c lines 304-343.
-func unit_deserts(who, to_who int, loy_check bool, k, lev int) {
-	sp := player(who)
+func (e *Engine) unit_deserts(who, to_who int, loy_check bool, k, lev int) {
+	sp := e.player(who)
 
 	if to_who != 0 && sp != 0 {
-		wout(sp, "%s renounces loyalty to us.", box_name(who))
-		wout(who, "%s renounces loyalty.", box_name(who))
+		wout(sp, "%s renounces loyalty to us.", e.box_name(who))
+		wout(who, "%s renounces loyalty.", e.box_name(who))
 	}
 
-	if to_who != 0 && is_prisoner(who) && player(to_who) == player(stack_parent(who)) {
-		p_char(who).prisoner = FALSE
-	} else if !is_prisoner(who) {
-		extract_stacked_unit(who)
+	if to_who != 0 && e.is_prisoner(who) && e.player(to_who) == e.player(e.stack_parent(who)) {
+		e.p_char(who).prisoner = FALSE
+	} else if !e.is_prisoner(who) {
+		e.extract_stacked_unit(who)
 	}
 
-	set_lord(who, to_who, k, lev)
+	e.set_lord(who, to_who, k, lev)
 
 	if to_who != 0 {
-		wout(who, "%s pledges fealty to us.", box_name(who))
-		wout(to_who, "%s pledges fealty to us.", box_name(who))
-		p_char(who).new_lord = 1
+		wout(who, "%s pledges fealty to us.", e.box_name(who))
+		wout(to_who, "%s pledges fealty to us.", e.box_name(who))
+		e.p_char(who).new_lord = 1
 	}
 }
 
 // char_reclaim marks a character for melting and triggers death.
 // Used by QUIT/RECLAIM commands.
 // Ported from src/u.c lines 82-92.
-func char_reclaim(who int) {
-	p_char(who).melt_me = TRUE
-	kill_char(who, 0) // QUIT shouldn't give items to stackmates
+func (e *Engine) char_reclaim(who int) {
+	e.p_char(who).melt_me = TRUE
+	e.kill_char(who, 0) // QUIT shouldn't give items to stackmates
 }
 
 // Note: put_back_cookie is implemented in lifecycle.go
@@ -849,27 +859,27 @@ func char_reclaim(who int) {
 // Note: check_char_gone is implemented in visibility.go
 // Note: check_char_here is implemented in visibility.go
 
-// Deprecated: will_admit not yet implemented.
-func will_admit(who, target, where int) bool {
-	// TODO: Implement in later sprint (permissions)
-	return true
-}
+// Note: will_admit is implemented in perm.go
 
 // numargs returns the number of arguments in a parsed command.
 // The first element (index 0) is the command name, so numargs = len - 1.
-func numargs(c *command) int {
-	// TODO: parse is **char in C; for now return based on command args
-	// This is a simplified implementation until order parsing is done.
-	if c.a != 0 {
-		return 1
+func (e *Engine) numargs(c *command) int {
+	if c.parse == nil {
+		// command built by hand rather than parsed from an order
+		if c.a != 0 {
+			return 1
+		}
+		return 0
 	}
-	return 0
+	return len(c.parse) - 1
 }
 
 // get_parse_arg returns the parsed argument at index i as a string.
 func get_parse_arg(c *command, i int) string {
-	// TODO: Implement properly when order parsing is done
-	return ""
+	if i < 0 || i >= len(c.parse) {
+		return ""
+	}
+	return c.parse[i]
 }
 
 // stack_has_item is implemented in inventory.go
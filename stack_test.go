@@ -33,51 +33,51 @@ func setupStackTestWorld(t *testing.T) func() {
 	t.Helper()
 
 	// Initialize boxes
-	teg.globals.bx[1000] = &box{kind: T_loc, skind: sub_region}
-	teg.globals.bx[1001] = &box{kind: T_loc, skind: sub_forest}
-	teg.globals.bx[1003] = &box{kind: T_loc, skind: sub_castle}
-	teg.globals.bx[2001] = &box{kind: T_char}
-	teg.globals.bx[2002] = &box{kind: T_char}
-	teg.globals.bx[2003] = &box{kind: T_char}
-	teg.globals.bx[2004] = &box{kind: T_char}
-	teg.globals.bx[2005] = &box{kind: T_char}
-	teg.globals.bx[2006] = &box{kind: T_char}
+	teg.setBox(1000, &box{kind: T_loc, skind: sub_region})
+	teg.setBox(1001, &box{kind: T_loc, skind: sub_forest})
+	teg.setBox(1003, &box{kind: T_loc, skind: sub_castle})
+	teg.setBox(2001, &box{kind: T_char})
+	teg.setBox(2002, &box{kind: T_char})
+	teg.setBox(2003, &box{kind: T_char})
+	teg.setBox(2004, &box{kind: T_char})
+	teg.setBox(2005, &box{kind: T_char})
+	teg.setBox(2006, &box{kind: T_char})
 
 	// Initialize char structs for prisoners
-	teg.globals.bx[2004].x_char = &entity_char{prisoner: 1}
+	teg.globals.bx.get(2004).x_char = &entity_char{prisoner: 1}
 
 	// Set location hierarchy via x_loc_info.where
-	teg.globals.bx[1000].x_loc_info.where = 0    // region has no parent
-	teg.globals.bx[1001].x_loc_info.where = 1000 // province in region
-	teg.globals.bx[1003].x_loc_info.where = 1001 // castle in province
-	teg.globals.bx[2001].x_loc_info.where = 1001 // char in province (stack leader)
-	teg.globals.bx[2002].x_loc_info.where = 2001 // char stacked under 2001
-	teg.globals.bx[2003].x_loc_info.where = 2002 // char stacked under 2002
-	teg.globals.bx[2004].x_loc_info.where = 2001 // prisoner stacked under 2001
-	teg.globals.bx[2005].x_loc_info.where = 1001 // independent char in province
-	teg.globals.bx[2006].x_loc_info.where = 1003 // char in castle
+	teg.globals.bx.get(1000).x_loc_info.where = 0    // region has no parent
+	teg.globals.bx.get(1001).x_loc_info.where = 1000 // province in region
+	teg.globals.bx.get(1003).x_loc_info.where = 1001 // castle in province
+	teg.globals.bx.get(2001).x_loc_info.where = 1001 // char in province (stack leader)
+	teg.globals.bx.get(2002).x_loc_info.where = 2001 // char stacked under 2001
+	teg.globals.bx.get(2003).x_loc_info.where = 2002 // char stacked under 2002
+	teg.globals.bx.get(2004).x_loc_info.where = 2001 // prisoner stacked under 2001
+	teg.globals.bx.get(2005).x_loc_info.where = 1001 // independent char in province
+	teg.globals.bx.get(2006).x_loc_info.where = 1003 // char in castle
 
 	// Set here_lists
-	teg.globals.bx[1000].x_loc_info.here_list = []int{1001}
-	teg.globals.bx[1001].x_loc_info.here_list = []int{2001, 2005, 1003}
-	teg.globals.bx[1003].x_loc_info.here_list = []int{2006}
-	teg.globals.bx[2001].x_loc_info.here_list = []int{2002, 2004}
-	teg.globals.bx[2002].x_loc_info.here_list = []int{2003}
-	teg.globals.bx[2003].x_loc_info.here_list = []int{}
-	teg.globals.bx[2004].x_loc_info.here_list = []int{}
-	teg.globals.bx[2005].x_loc_info.here_list = []int{}
-	teg.globals.bx[2006].x_loc_info.here_list = []int{}
+	teg.globals.bx.get(1000).x_loc_info.here_list = []int{1001}
+	teg.globals.bx.get(1001).x_loc_info.here_list = []int{2001, 2005, 1003}
+	teg.globals.bx.get(1003).x_loc_info.here_list = []int{2006}
+	teg.globals.bx.get(2001).x_loc_info.here_list = []int{2002, 2004}
+	teg.globals.bx.get(2002).x_loc_info.here_list = []int{2003}
+	teg.globals.bx.get(2003).x_loc_info.here_list = []int{}
+	teg.globals.bx.get(2004).x_loc_info.here_list = []int{}
+	teg.globals.bx.get(2005).x_loc_info.here_list = []int{}
+	teg.globals.bx.get(2006).x_loc_info.here_list = []int{}
 
 	return func() {
-		teg.globals.bx[1000] = nil
-		teg.globals.bx[1001] = nil
-		teg.globals.bx[1003] = nil
-		teg.globals.bx[2001] = nil
-		teg.globals.bx[2002] = nil
-		teg.globals.bx[2003] = nil
-		teg.globals.bx[2004] = nil
-		teg.globals.bx[2005] = nil
-		teg.globals.bx[2006] = nil
+		teg.deleteBox(1000)
+		teg.deleteBox(1001)
+		teg.deleteBox(1003)
+		teg.deleteBox(2001)
+		teg.deleteBox(2002)
+		teg.deleteBox(2003)
+		teg.deleteBox(2004)
+		teg.deleteBox(2005)
+		teg.deleteBox(2006)
 	}
 }
 
@@ -100,7 +100,7 @@ func TestHerePos(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := here_pos(tt.who)
+			got := teg.here_pos(tt.who)
 			if got != tt.expected {
 				t.Errorf("here_pos(%d) = %d, want %d", tt.who, got, tt.expected)
 			}
@@ -125,7 +125,7 @@ func TestHerePrecedes(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := here_precedes(tt.a, tt.b)
+			got := teg.here_precedes(tt.a, tt.b)
 			if got != tt.expected {
 				t.Errorf("here_precedes(%d, %d) = %v, want %v", tt.a, tt.b, got, tt.expected)
 			}
@@ -150,7 +150,7 @@ func TestFirstPrisonerPos(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := first_prisoner_pos(tt.where)
+			got := teg.first_prisoner_pos(tt.where)
 			if got != tt.expected {
 				t.Errorf("first_prisoner_pos(%d) = %d, want %d", tt.where, got, tt.expected)
 			}
@@ -177,7 +177,7 @@ func TestStackParent(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := stack_parent(tt.who)
+			got := teg.stack_parent(tt.who)
 			if got != tt.expected {
 				t.Errorf("stack_parent(%d) = %d, want %d", tt.who, got, tt.expected)
 			}
@@ -204,7 +204,7 @@ func TestStackLeader(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := stack_leader(tt.who)
+			got := teg.stack_leader(tt.who)
 			if got != tt.expected {
 				t.Errorf("stack_leader(%d) = %d, want %d", tt.who, got, tt.expected)
 			}
@@ -233,7 +233,7 @@ func TestStackedBeneath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := stacked_beneath(tt.a, tt.b)
+			got := teg.stacked_beneath(tt.a, tt.b)
 			if got != tt.expected {
 				t.Errorf("stacked_beneath(%d, %d) = %v, want %v", tt.a, tt.b, got, tt.expected)
 			}
@@ -247,9 +247,9 @@ func TestPromote(t *testing.T) {
 
 	// Province here_list is [2001, 2005, 1003]
 	// Promote 1003 to position 1 (before 2005)
-	promote(1003, 1)
+	teg.promote(1003, 1)
 
-	p := rp_loc_info(1001)
+	p := teg.rp_loc_info(1001)
 	expected := []int{2001, 1003, 2005}
 
 	if len(p.here_list) != len(expected) {
@@ -280,7 +280,7 @@ func TestHasPrisoner(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := has_prisoner(tt.who, tt.pris)
+			got := teg.has_prisoner(tt.who, tt.pris)
 			if got != tt.expected {
 				t.Errorf("has_prisoner(%d, %d) = %v, want %v", tt.who, tt.pris, got, tt.expected)
 			}
@@ -293,13 +293,13 @@ func TestPlayer(t *testing.T) {
 	defer cleanup()
 
 	// Create a player and link characters to it
-	teg.globals.bx[3001] = &box{kind: T_player}
-	teg.globals.bx[2001].x_char = &entity_char{unit_lord: 3001}
-	teg.globals.bx[2002].x_char = &entity_char{unit_lord: 2001}
-	teg.globals.bx[2005].x_char = &entity_char{unit_lord: 3001}
+	teg.setBox(3001, &box{kind: T_player})
+	teg.globals.bx.get(2001).x_char = &entity_char{unit_lord: 3001}
+	teg.globals.bx.get(2002).x_char = &entity_char{unit_lord: 2001}
+	teg.globals.bx.get(2005).x_char = &entity_char{unit_lord: 3001}
 
 	defer func() {
-		teg.globals.bx[3001] = nil
+		teg.deleteBox(3001)
 	}()
 
 	tests := []struct {
@@ -315,7 +315,7 @@ func TestPlayer(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := player(tt.who)
+			got := teg.player(tt.who)
 			if got != tt.expected {
 				t.Errorf("player(%d) = %d, want %d", tt.who, got, tt.expected)
 			}
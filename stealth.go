@@ -0,0 +1,622 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// stealth.go - Spying, hiding, sneaking and thievery ported from src/stealth.c
+
+package taygete
+
+// v_spy_inv starts spying on a character's inventory.
+// Ported from src/stealth.c lines 8-17.
+func (e *Engine) v_spy_inv(c *command) int {
+	if !e.check_char_here(c.who, c.a) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_spy_inv shows the spy the target's inventory.
+// Ported from src/stealth.c lines 20-32.
+func (e *Engine) d_spy_inv(c *command) int {
+	target := c.a
+
+	if !e.check_still_here(c.who, target) {
+		return FALSE
+	}
+
+	wout(c.who, "Discovered the inventory of %s:", e.box_name(target))
+	e.show_char_inventory(c.who, target)
+
+	return TRUE
+}
+
+// v_spy_skills starts spying on a character's skills.
+// Ported from src/stealth.c lines 35-44.
+func (e *Engine) v_spy_skills(c *command) int {
+	if !e.check_char_here(c.who, c.a) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_spy_skills shows the spy the target's skills.
+// Ported from src/stealth.c lines 47-59.
+func (e *Engine) d_spy_skills(c *command) int {
+	target := c.a
+
+	if !e.check_still_here(c.who, target) {
+		return FALSE
+	}
+
+	wout(c.who, "Learned the skills of %s:", e.box_name(target))
+	e.list_skills(c.who, target)
+
+	return TRUE
+}
+
+// v_spy_lord starts spying on who a character is sworn to.
+// Ported from src/stealth.c lines 62-71.
+func (e *Engine) v_spy_lord(c *command) int {
+	if !e.check_char_here(c.who, c.a) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_spy_lord tells the spy the target's lord, unless the target
+// cloaks it.
+// Ported from src/stealth.c lines 74-98.
+func (e *Engine) d_spy_lord(c *command) int {
+	target := c.a
+
+	if !e.check_still_here(c.who, target) {
+		return FALSE
+	}
+
+	if e.cloak_lord(target) {
+		wout(c.who, "Failed to learn the lord of %s.", e.box_code(target))
+		return FALSE
+	}
+
+	parent := e.player(target)
+
+	if !e.valid_box(parent) {
+		panic("d_spy_lord: target has no player")
+	}
+
+	wout(c.who, "%s is sworn to %s.", e.box_name(target), e.box_name(parent))
+
+	return TRUE
+}
+
+// v_hide starts hiding, or with a zero flag stops hiding at once.
+// Ported from src/stealth.c lines 101-126.
+func (e *Engine) v_hide(c *command) int {
+	flag := c.a
+
+	if !e.check_skill(c.who, sk_hide_self) {
+		return FALSE
+	}
+
+	if flag != 0 && !e.char_alone(c.who) {
+		wout(c.who, "Must be alone to hide.")
+		return FALSE
+	}
+
+	if flag == 0 {
+		e.p_magic(c.who).hide_self = FALSE
+		wout(c.who, "No longer hidden.")
+
+		c.wait = 0
+		c.inhibit_finish = TRUE
+		return TRUE
+	}
+
+	return TRUE
+}
+
+// d_hide hides a character who is still alone.
+// Ported from src/stealth.c lines 129-143.
+func (e *Engine) d_hide(c *command) int {
+	if !e.char_alone(c.who) {
+		wout(c.who, "Must be alone to hide.")
+		return FALSE
+	}
+
+	e.p_magic(c.who).hide_self = TRUE
+
+	wout(c.who, "Now hidden.")
+	return TRUE
+}
+
+// sneak_dest finds where a sneak goes: out of the structure with no
+// arguments, otherwise into the building or ship named. Returns 0 if
+// the move isn't allowed.
+func (e *Engine) sneak_dest(c *command) int {
+	where := e.subloc(c.who)
+	outside := e.subloc(where)
+
+	if !e.char_alone(c.who) {
+		wout(c.who, "Must be alone in order to sneak.")
+		return 0
+	}
+
+	var v *exit_view
+	dest := outside
+	if e.numargs(c) > 0 {
+		v = e.parse_exit_dir(c, where, "sneak")
+		if v == nil {
+			return 0
+		}
+
+		dest = v.destination
+	}
+
+	if dest == outside {
+		if e.loc_depth(where) != LOC_build {
+			wout(c.who, "Not in a structure.")
+			return 0
+		}
+
+		if e.subkind(outside) == sub_ocean {
+			wout(c.who, "May not leave while on the ocean.")
+			return 0
+		}
+
+		return dest
+	}
+
+	if e.loc_depth(dest) != LOC_build {
+		wout(c.who, "May only sneak into buildings and ships.")
+		return 0
+	}
+
+	if v.impassable != 0 {
+		wout(c.who, "That route is impassable.")
+		return 0
+	}
+
+	if v.in_transit != 0 {
+		wout(c.who, "%s is underway.  Boarding is not possible.", e.box_name(dest))
+		return 0
+	}
+
+	return dest
+}
+
+// v_sneak starts sneaking into or out of a structure.
+// Ported from src/stealth.c lines 146-209.
+func (e *Engine) v_sneak(c *command) int {
+	if e.sneak_dest(c) == 0 {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_sneak moves the character in or out without being stopped.
+// Ported from src/stealth.c lines 212-282.
+func (e *Engine) d_sneak(c *command) int {
+	where := e.subloc(c.who)
+
+	dest := e.sneak_dest(c)
+	if dest == 0 {
+		return FALSE
+	}
+
+	e.move_stack(c.who, dest)
+
+	if dest == e.subloc(where) {
+		wout(c.who, "Now outside of %s.", e.box_name(where))
+		return TRUE
+	}
+
+	wout(c.who, "Now inside %s.", e.box_name(dest))
+	e.bark_dogs(dest)
+
+	return TRUE
+}
+
+// clear_contacts forgets who everyone in the stack has contacted.
+// Ported from src/stealth.c lines 285-298.
+func (e *Engine) clear_contacts(stack int) {
+	if e.kind(stack) != T_char {
+		return
+	}
+
+	var l []int
+	e.loop_stack(stack, &l)
+	for _, i := range l {
+		e.p_char(i).contact = nil
+	}
+}
+
+// add_contact records that b has contacted or found a.
+// Ported from src/stealth.c lines 301-308.
+func (e *Engine) add_contact(a, b int) {
+	if e.kind(a) != T_char {
+		panic("add_contact: not a character")
+	}
+
+	p := e.p_char(a)
+	p.contact = append(p.contact, b)
+}
+
+// v_contact contacts each character or player listed.
+// Ported from src/stealth.c lines 311-332.
+func (e *Engine) v_contact(c *command) int {
+	for e.numargs(c) > 0 {
+		if e.kind(c.a) != T_char && e.kind(c.a) != T_player {
+			wout(c.who, "%s is not a character or player entity.", get_parse_arg(c, 1))
+		} else {
+			p := e.p_char(c.who)
+			p.contact = append(p.contact, c.a)
+			wout(c.a, "%s contacted us.", e.box_name(c.who))
+		}
+
+		e.cmd_shift(c)
+	}
+
+	return TRUE
+}
+
+// seek_found reports whether target is visibly here; if so the seek
+// ends at once.
+func (e *Engine) seek_found(c *command, target int) bool {
+	if !e.char_here(c.who, target) {
+		return false
+	}
+
+	wout(c.who, "%s is here.", e.box_name(target))
+	e.add_contact(target, c.who)
+
+	c.wait = 0
+	c.inhibit_finish = TRUE // don't call d_wait
+	return true
+}
+
+// v_seek starts seeking a character, or any hidden noble.
+// Ported from src/stealth.c lines 335-360.
+func (e *Engine) v_seek(c *command) int {
+	target := c.a
+
+	if target != 0 {
+		if e.kind(target) != T_char {
+			wout(c.who, "%s is not a character.", e.box_code(target))
+			return FALSE
+		}
+
+		e.seek_found(c, target)
+	}
+
+	return TRUE
+}
+
+// d_seek finds a hidden target in the same place one day in ten.
+// Without a target there is a 5% chance of finding each hidden
+// noble present.
+// Ported from src/stealth.c lines 363-419.
+func (e *Engine) d_seek(c *command) int {
+	target := c.a
+
+	if target != 0 {
+		if e.kind(target) != T_char {
+			wout(c.who, "%s is not a character.", e.box_code(target))
+			return FALSE
+		}
+
+		if e.seek_found(c, target) {
+			return TRUE
+		}
+
+		if e.subloc(c.who) == e.subloc(target) && e.rndFrom(streamSkills, 1, 10) == 1 {
+			e.add_contact(target, c.who)
+			wout(c.who, "Found %s.", e.box_name(target))
+
+			c.wait = 0
+			c.inhibit_finish = TRUE // don't call d_wait
+			return TRUE
+		}
+
+		return TRUE
+	}
+
+	p := e.rp_loc_info(e.subloc(c.who))
+	if p == nil {
+		return TRUE
+	}
+
+	for _, i := range p.here_list {
+		if e.kind(i) != T_char || e.char_here(c.who, i) {
+			continue
+		}
+
+		if e.rndFrom(streamSkills, 1, 100) > 5 {
+			continue
+		}
+
+		e.add_contact(i, c.who)
+		wout(c.who, "Found %s.", e.box_name(i))
+
+		break
+	}
+
+	return TRUE
+}
+
+// add_fill adds where and the provinces within max_depth steps of it
+// to l.
+// Ported from src/stealth.c lines 422-445.
+func (e *Engine) add_fill(where int, l *[]int, max_depth, depth int) {
+	if e.loc_depth(where) != LOC_province {
+		panic("add_fill: not a province")
+	}
+
+	if IListLookup(*l, where) >= 0 {
+		return
+	}
+
+	*l = append(*l, where)
+
+	p := e.rp_loc(where)
+	if p == nil {
+		return
+	}
+
+	if depth >= max_depth {
+		return
+	}
+
+	for _, dest := range p.prov_dest {
+		if dest != 0 {
+			e.add_fill(dest, l, max_depth, depth+1)
+		}
+	}
+}
+
+// v_find_rich starts asking around an inn after wealthy nobles.
+// Ported from src/stealth.c lines 448-460.
+func (e *Engine) v_find_rich(c *command) int {
+	if e.subkind(e.subloc(c.who)) != sub_inn {
+		wout(c.who, "May only be used in an inn.")
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_find_rich names the richest other noble within a few provinces
+// holding at least 500 gold.
+// Ported from src/stealth.c lines 463-517.
+func (e *Engine) d_find_rich(c *command) int {
+	pl := e.player(c.who)
+	max_gold := 500
+	who_gold := 0
+	where := e.subloc(c.who)
+
+	if e.subkind(where) != sub_inn {
+		wout(c.who, "May only be used in an inn.")
+		return FALSE
+	}
+
+	var l []int
+	e.add_fill(e.province(where), &l, 3, 1)
+
+	var all []int
+	for _, prov := range l {
+		e.all_here(prov, &all)
+		for _, j := range all {
+			if e.kind(j) != T_char || e.player(j) == pl {
+				continue
+			}
+
+			if n := e.has_item(j, item_gold); n >= max_gold {
+				max_gold = n
+				who_gold = j
+			}
+		}
+	}
+
+	if who_gold == 0 {
+		wout(c.who, "No weathy nobles are rumored to be nearby.")
+		return TRUE
+	}
+
+	var s string
+	switch {
+	case max_gold <= 1000:
+		s = "large sum"
+	case max_gold <= 2000:
+		s = "considerable amount"
+	default:
+		s = "vast quantity"
+	}
+
+	wout(c.who, "Rumors claim that one %s is nearby, and possesses a %s of gold.", e.box_name(who_gold), s)
+
+	return TRUE
+}
+
+// torture_ok reports whether target is a prisoner in the torturer's
+// stack who may be tortured.
+func (e *Engine) torture_ok(c *command, target int) bool {
+	if !e.is_prisoner(target) || e.stack_leader(target) != e.stack_leader(c.who) {
+		wout(c.who, "%s is not a prisoner of %s.", e.box_code(target), e.box_name(c.who))
+		return false
+	}
+
+	if e.is_npc(target) || e.loyal_kind(target) == LOY_npc || e.loyal_kind(target) == LOY_summon {
+		wout(c.who, "NPC's cannot be tortured.")
+		return false
+	}
+
+	return true
+}
+
+// v_torture starts torturing a prisoner.
+// Ported from src/stealth.c lines 520-547.
+func (e *Engine) v_torture(c *command) int {
+	if !e.has_skill(c.who, sk_torture) {
+		wout(c.who, "Requires %s.", e.box_name(sk_torture))
+		return FALSE
+	}
+
+	if !e.torture_ok(c, c.a) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_torture wounds the prisoner, who may give up their faction: one
+// in ten oath-1 nobles, half the contract nobles and every fear noble.
+// Ported from src/stealth.c lines 550-613.
+func (e *Engine) d_torture(c *command) int {
+	target := c.a
+
+	if !e.torture_ok(c, target) {
+		return FALSE
+	}
+
+	e.add_char_damage(target, 50, c.who)
+
+	if !e.alive(target) {
+		wout(c.who, "%s died under torture.", e.box_name(target))
+		return FALSE
+	}
+
+	chance := 0
+	switch e.loyal_kind(target) {
+	case LOY_oath:
+		if e.loyal_rate(target) == 1 {
+			chance = 10
+		}
+	case LOY_contract:
+		chance = 50
+	case LOY_fear:
+		chance = 100
+	}
+
+	if e.rndFrom(streamSkills, 1, 100) > chance {
+		wout(c.who, "The prisoner refused to talk.")
+		return FALSE
+	}
+
+	e.add_skill_experience(c.who, sk_torture)
+
+	wout(c.who, "%s belongs to faction %s.", e.box_name(target), e.box_name(e.player(target)))
+
+	return TRUE
+}
+
+// v_petty_thief starts working a city's merchants, taking its petty
+// thief cookie for the month.
+// Ported from src/stealth.c lines 624-654.
+func (e *Engine) v_petty_thief(c *command) int {
+	where := e.subloc(c.who)
+
+	if e.subkind(where) != sub_city {
+		wout(c.who, "Must be in a city.")
+		return FALSE
+	}
+
+	if e.loc_pillage(where) != 0 {
+		wout(c.who, "This city has recently been pillaged; there are no opportunities for thievery.")
+		return FALSE
+	}
+
+	// NOTYET: if the command is interrupted, the cookie isn't put back.
+	if !e.consume_item(where, item_petty_thief, 1) {
+		wout(c.who, "A petty thief has already worked here this month.")
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_petty_thief steals 50 to 150 gold from the city's taxes, unless
+// the thief is caught (5%) and beaten.
+// Ported from src/stealth.c lines 657-769.
+func (e *Engine) d_petty_thief(c *command) int {
+	where := e.subloc(c.who)
+
+	if e.loc_pillage(where) != 0 {
+		wout(c.who, "This city has recently been pillaged.  There are no opportunities for thievery.")
+		return FALSE
+	}
+
+	if e.rndFrom(streamSkills, 1, 100) <= 5 {
+		e.globals.show_to_garrison = true
+		vector_clear()
+		vector_add(where)
+		vector_add(c.who)
+
+		switch e.rndFrom(streamSkills, 1, 3) {
+		case 1:
+			wout(VECT, "%s was caught trying to steal from the city merchants, and given a beating.", e.box_name(c.who))
+		case 2:
+			wout(VECT, "%s was caught trying to pick pockets in the town square, and flogged by the townsfolk.", e.box_name(c.who))
+		case 3:
+			wout(VECT, "%s was caught stealing, and given a beating.", e.box_name(c.who))
+		}
+
+		e.globals.show_to_garrison = false
+
+		e.add_char_damage(c.who, e.rndFrom(streamSkills, 5, 15), MATES)
+		return FALSE
+	}
+
+	amount := e.rndFrom(streamSkills, 50, 150)
+	e.consume_item(where, item_tax_cookie, amount)
+	e.gen_item(c.who, item_gold, amount)
+	e.globals.gold_petty_thief += amount
+
+	some := func() string {
+		if e.rndFrom(streamSkills, 0, 1) != 0 {
+			return "Several"
+		}
+		return cap(nice_num(e.rndFrom(streamSkills, 2, 3)))
+	}
+
+	var self, third string
+	switch e.rndFrom(streamSkills, 1, 3) {
+	case 1:
+		self = " stealing from merchants"
+		third = sout("%s merchants complain that they were robbed by a thief.", some())
+	case 2:
+		self = " picking pockets"
+		third = sout("%s townspeople complain that their pockets were picked in the town square.", some())
+	case 3:
+		switch e.rndFrom(streamSkills, 1, 3) {
+		case 1:
+			third = "There are rumors that a thief is loose in the city."
+		case 2:
+			third = "There are rumors that a thief has been working the city."
+		case 3:
+			third = "Reports of thievery are heard throughout the city."
+		}
+	}
+
+	wout(c.who, "Earned %s%s.", gold_s(amount), self)
+
+	e.globals.show_to_garrison = true
+	wout(where, "%s", third)
+	e.globals.show_to_garrison = false
+
+	return TRUE
+}
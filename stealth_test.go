@@ -0,0 +1,109 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// stealth_test.go - Tests for spying, hiding and thievery
+
+package taygete
+
+import "testing"
+
+func TestHide(t *testing.T) {
+	who := setupBasicTest(t, 0, 0)
+	teg.alloc_box(sk_hide_self, T_skill, 0)
+	teg.p_skill_ent(who, sk_hide_self).know = SKILL_know
+
+	c := &command{who: who, a: 1}
+	if teg.v_hide(c) != TRUE || teg.d_hide(c) != TRUE {
+		t.Fatal("hide failed")
+	}
+	if teg.char_hidden(who) == 0 {
+		t.Fatal("character is not hidden")
+	}
+
+	c = &command{who: who, a: 0, wait: 3}
+	if got := teg.v_hide(c); got != TRUE {
+		t.Fatalf("v_hide(0) = %d, want TRUE", got)
+	}
+	if teg.char_hidden(who) != 0 {
+		t.Error("character is still hidden")
+	}
+	if c.wait != 0 || c.inhibit_finish != TRUE {
+		t.Error("unhiding did not end the order at once")
+	}
+}
+
+func TestPettyThief(t *testing.T) {
+	who := setupBasicTest(t, 0, 0)
+	where := teg.subloc(who)
+	teg.change_box_subkind(where, sub_city)
+	for _, item := range []int{item_gold, item_petty_thief, item_tax_cookie} {
+		teg.alloc_box(item, T_item, 0)
+	}
+	teg.gen_item(where, item_petty_thief, 1)
+	teg.gen_item(where, item_tax_cookie, 500)
+
+	c := &command{who: who}
+	if got := teg.v_petty_thief(c); got != TRUE {
+		t.Fatalf("v_petty_thief = %d, want TRUE", got)
+	}
+	if got := teg.v_petty_thief(&command{who: who}); got != FALSE {
+		t.Errorf("second v_petty_thief this month = %d, want FALSE", got)
+	}
+
+	if teg.d_petty_thief(c) != TRUE {
+		if teg.char_health(who) >= 100 {
+			t.Fatal("caught thief was not beaten")
+		}
+		return
+	}
+	gold := teg.has_item(who, item_gold)
+	if gold < 50 || gold > 150 {
+		t.Errorf("stole %d gold, want 50 to 150", gold)
+	}
+	if got := teg.has_item(where, item_tax_cookie); got != 500-gold {
+		t.Errorf("taxes left = %d, want %d", got, 500-gold)
+	}
+	if teg.globals.gold_petty_thief != gold {
+		t.Errorf("gold_petty_thief = %d, want %d", teg.globals.gold_petty_thief, gold)
+	}
+}
+
+func TestTorture(t *testing.T) {
+	who := setupBasicTest(t, 0, 0)
+	teg.alloc_box(sk_torture, T_skill, 0)
+	teg.p_skill_ent(who, sk_torture).know = SKILL_know
+
+	target := 1002
+	teg.alloc_box(target, T_char, 0)
+	teg.set_where(target, who)
+	p := teg.p_char(target)
+	p.health = 100
+	p.prisoner = TRUE
+	p.loy_kind = LOY_npc
+
+	c := &command{who: who, a: target}
+	if got := teg.v_torture(c); got != FALSE {
+		t.Errorf("v_torture(npc) = %d, want FALSE", got)
+	}
+
+	p.loy_kind = LOY_fear
+	if teg.v_torture(c) != TRUE || teg.d_torture(c) != TRUE {
+		t.Fatal("torturing a fear noble failed")
+	}
+	if got := teg.char_health(target); got != 50 {
+		t.Errorf("prisoner health = %d, want 50", got)
+	}
+}
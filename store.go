@@ -0,0 +1,88 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// store.go - sparse entity storage, replacing the C bx[] array
+
+package taygete
+
+import (
+	"fmt"
+	"slices"
+)
+
+// boxStore holds the live entities keyed by ID. The C code kept a
+// fixed array of MAX_BOXES pointers; the store only takes memory for
+// allocated entities. setBox and deleteBox are its only writers, and
+// they keep a sorted index of the IDs, so walking the entities in order
+// costs time in proportion to the live entities rather than to
+// MAX_BOXES.
+//
+// Lookups of unallocated IDs return nil, just as an empty array slot
+// did, so bx.get(n) == nil still means "no such entity". IDs keep their
+// int_to_code range of 1 through MAX_BOXES-1.
+type boxStore struct {
+	boxes map[int]*box
+	ids   []int // sorted IDs of boxes
+}
+
+// get returns entity n, or nil if there is none.
+func (s *boxStore) get(n int) *box {
+	return s.boxes[n]
+}
+
+// boxIDs returns the IDs of the live entities in ascending order. The
+// slice is the caller's, so entities may be added or removed while
+// walking it.
+func (e *Engine) boxIDs() []int {
+	return slices.Clone(e.globals.bx.ids)
+}
+
+// setBox stores b as entity n, creating the store on first use.
+// A nil b removes the entity, as deleteBox does.
+func (e *Engine) setBox(n int, b *box) {
+	if b == nil {
+		e.deleteBox(n)
+		return
+	}
+	if n < 0 || n >= MAX_BOXES {
+		panic(fmt.Sprintf("setBox: entity %d out of range", n))
+	}
+	s := &e.globals.bx
+	if s.boxes == nil {
+		s.boxes = make(map[int]*box)
+	}
+	// Box 0 was a real array slot that the C loops never reached, so
+	// it is stored but not indexed.
+	if _, had := s.boxes[n]; !had && n != 0 {
+		i, _ := slices.BinarySearch(s.ids, n)
+		s.ids = slices.Insert(s.ids, i, n)
+	}
+	s.boxes[n] = b
+	e.markDirty(n)
+}
+
+// deleteBox removes entity n from the store.
+func (e *Engine) deleteBox(n int) {
+	s := &e.globals.bx
+	if _, had := s.boxes[n]; !had {
+		return
+	}
+	delete(s.boxes, n)
+	e.markDirty(n)
+	if i, found := slices.BinarySearch(s.ids, n); found {
+		s.ids = slices.Delete(s.ids, i, i+1)
+	}
+}
@@ -0,0 +1,269 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package taygete
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/mdhender/prng"
+)
+
+func TestBoxStore(t *testing.T) {
+	e := &Engine{}
+	if e.globals.bx.get(1001) != nil {
+		t.Fatal("empty store returned a box")
+	}
+
+	for _, n := range []int{56_760, 1001, 10_101} {
+		e.setBox(n, &box{kind: T_loc})
+	}
+	e.deleteBox(1002) // not allocated
+	if got, want := e.boxIDs(), []int{1001, 10_101, 56_760}; !slices.Equal(got, want) {
+		t.Errorf("ids = %v, want %v", got, want)
+	}
+
+	e.setBox(1001, &box{kind: T_char}) // replaced, not added
+	if got, want := e.boxIDs(), []int{1001, 10_101, 56_760}; !slices.Equal(got, want) {
+		t.Errorf("ids after replace = %v, want %v", got, want)
+	}
+	if b := e.globals.bx.get(1001); b == nil || b.kind != T_char {
+		t.Errorf("replaced box = %+v", b)
+	}
+
+	e.setBox(10_101, nil)
+	e.deleteBox(56_760)
+	if got, want := e.boxIDs(), []int{1001}; !slices.Equal(got, want) {
+		t.Errorf("ids after delete = %v, want %v", got, want)
+	}
+	if _, ok := e.globals.bx.boxes[10_101]; ok {
+		t.Error("setBox(nil) left an entry behind")
+	}
+
+	e.setBox(0, &box{kind: T_loc})
+	if got, want := e.boxIDs(), []int{1001}; !slices.Equal(got, want) {
+		t.Errorf("ids after setBox(0) = %v, want %v", got, want)
+	}
+	for _, n := range []int{-1, MAX_BOXES} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("setBox(%d) did not panic", n)
+				}
+			}()
+			e.setBox(n, &box{kind: T_loc})
+		}()
+	}
+}
+
+// benchSizes are the world sizes the store benchmarks run at: a small
+// test world and one closer to a running game.
+var benchSizes = []int{100, 10_000}
+
+// benchIDs returns n entity IDs spread over the allocation ranges.
+func benchIDs(n int) []int {
+	r := rand.New(rand.NewPCG(1, 2))
+	seen := map[int]bool{}
+	var ids []int
+	for len(ids) < n {
+		id := 1000 + r.IntN(MAX_BOXES-1000)
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// The array benchmarks measure the [MAX_BOXES]*box layout the store
+// replaced, doing what clearWorld, loading and Boxes() used to do.
+
+func BenchmarkBoxLoad(b *testing.B) {
+	for _, n := range benchSizes {
+		ids := benchIDs(n)
+		b.Run(fmt.Sprintf("array/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				bx := new([MAX_BOXES]*box)
+				for _, id := range ids {
+					bx[id] = &box{kind: T_loc}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("store/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				e := &Engine{}
+				for _, id := range ids {
+					e.setBox(id, &box{kind: T_loc})
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBoxIterate(b *testing.B) {
+	for _, n := range benchSizes {
+		ids := benchIDs(n)
+		arr := new([MAX_BOXES]*box)
+		e := &Engine{}
+		for _, id := range ids {
+			arr[id] = &box{kind: T_loc}
+			e.setBox(id, arr[id])
+		}
+		b.Run(fmt.Sprintf("array/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				var live []int
+				for id := 1; id < MAX_BOXES; id++ {
+					if arr[id] != nil && arr[id].kind != T_deleted {
+						live = append(live, id)
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("store/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				var live []int
+				for _, id := range e.boxIDs() {
+					if e.globals.bx.get(id).kind != T_deleted {
+						live = append(live, id)
+					}
+				}
+			}
+		})
+	}
+}
+
+// saveWalks is how many times SaveWorld walks every entity, once for
+// each table it writes.
+const saveWalks = 15
+
+// entityRow stands in for the row SaveWorld writes for a box.
+type entityRow struct {
+	id          int
+	kind, skind schar
+}
+
+func BenchmarkBoxSave(b *testing.B) {
+	for _, n := range benchSizes {
+		ids := benchIDs(n)
+		arr := new([MAX_BOXES]*box)
+		e := &Engine{}
+		for _, id := range ids {
+			arr[id] = &box{kind: T_loc, skind: sub_plain}
+			e.setBox(id, arr[id])
+		}
+		b.Run(fmt.Sprintf("array/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				for range saveWalks {
+					var rows []entityRow
+					for id := 1; id < MAX_BOXES; id++ {
+						if bx := arr[id]; bx != nil {
+							rows = append(rows, entityRow{id, bx.kind, bx.skind})
+						}
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("store/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				for range saveWalks {
+					var rows []entityRow
+					for _, id := range e.boxIDs() {
+						bx := e.globals.bx.get(id)
+						rows = append(rows, entityRow{id, bx.kind, bx.skind})
+					}
+				}
+			}
+		})
+	}
+}
+
+// benchWorld builds an engine holding n entities: provinces, each with
+// an independent noble standing in it.
+func benchWorld(b *testing.B, n int) *Engine {
+	b.Helper()
+	db, err := OpenTestDB()
+	if err != nil {
+		b.Fatalf("OpenTestDB: %v", err)
+	}
+	b.Cleanup(func() { db.Close() })
+	e, err := NewEngine(db, prng.New(rand.NewPCG(1, 2)))
+	if err != nil {
+		b.Fatalf("NewEngine: %v", err)
+	}
+	e.logger = nil
+	e.clearWorld()
+
+	e.alloc_box(indep_player, T_player, sub_pl_system)
+	// Allocating downwards puts each box at the head of its chains.
+	for i := n/2 - 1; i >= 0; i-- {
+		prov, who := 10_000+i, 1_000+i
+		e.alloc_box(prov, T_loc, sub_plain)
+		e.set_name(prov, fmt.Sprintf("Province %d", i))
+		e.alloc_box(who, T_char, 0)
+		e.set_name(who, fmt.Sprintf("Noble %d", i))
+		e.p_char(who).unit_lord = indep_player
+		e.set_where(who, prov)
+	}
+	return e
+}
+
+func BenchmarkSaveWorld(b *testing.B) {
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprintf("full/%d", n), func(b *testing.B) {
+			e := benchWorld(b, n)
+			for b.Loop() {
+				if err := e.saveWorldFull(); err != nil {
+					b.Fatalf("SaveWorld: %v", err)
+				}
+			}
+		})
+		// One entity changes between saves
+		b.Run(fmt.Sprintf("incremental/%d", n), func(b *testing.B) {
+			e := benchWorld(b, n)
+			if err := e.SaveWorld(); err != nil {
+				b.Fatalf("SaveWorld: %v", err)
+			}
+			id := e.boxIDs()[0]
+			i := 0
+			for b.Loop() {
+				i++
+				e.set_name(id, fmt.Sprintf("Entity %d", i))
+				if err := e.SaveWorld(); err != nil {
+					b.Fatalf("SaveWorld: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkLoadWorld(b *testing.B) {
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprintf("store/%d", n), func(b *testing.B) {
+			e := benchWorld(b, n)
+			if err := e.SaveWorld(); err != nil {
+				b.Fatalf("SaveWorld: %v", err)
+			}
+			for b.Loop() {
+				if err := e.LoadWorld(); err != nil {
+					b.Fatalf("LoadWorld: %v", err)
+				}
+			}
+		})
+	}
+}
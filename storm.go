@@ -31,12 +31,12 @@ package taygete
 // ship_gone returns how long the ship has been moving (days), or 0 if not moving.
 // When a ship is "gone", it cannot be interacted with (boarded, attacked, etc.)
 // Ports: #define ship_gone(n) (ship_moving(n) ? sysclock.days_since_epoch - ship_moving(n) + evening : 0)
-func ship_gone(n int) int {
-	moving := ship_moving(n)
+func (e *Engine) ship_gone(n int) int {
+	moving := e.ship_moving(n)
 	if moving == 0 {
 		return 0
 	}
-	return teg.globals.sysclock.days_since_epoch - moving + boolToInt(teg.globals.evening)
+	return e.globals.sysclock.days_since_epoch - moving + boolToInt(e.globals.evening)
 }
 
 // char_gone returns how long the character has been moving (days), or 0 if not moving.
@@ -48,8 +48,8 @@ func ship_gone(n int) int {
 //
 // We implement the simplified version to match the active C code.
 // Ports: #define char_gone(n) (char_moving(n) ? 1 : 0)
-func char_gone(n int) int {
-	if char_moving(n) != 0 {
+func (e *Engine) char_gone(n int) int {
+	if e.char_moving(n) != 0 {
 		return 1
 	}
 	return 0
@@ -58,12 +58,12 @@ func char_gone(n int) int {
 // char_gone_full returns the actual days elapsed since movement started.
 // This is the "full" version that was #if 0'd out in the C code.
 // Provided for completeness and potential future use.
-func char_gone_full(n int) int {
-	moving := char_moving(n)
+func (e *Engine) char_gone_full(n int) int {
+	moving := e.char_moving(n)
 	if moving == 0 {
 		return 0
 	}
-	return teg.globals.sysclock.days_since_epoch - moving + boolToInt(teg.globals.evening)
+	return e.globals.sysclock.days_since_epoch - moving + boolToInt(e.globals.evening)
 }
 
 // boolToInt converts a boolean to an integer (0 or 1).
@@ -73,3 +73,953 @@ func boolToInt(b bool) int {
 	}
 	return 0
 }
+
+// new_storm makes storm n, or a new storm if n is 0, of kind sk with
+// the given strength in province where. Returns -1 if no entity number
+// is left.
+// Ported from src/storm.c lines 180-226.
+func (e *Engine) new_storm(n int, sk schar, aura int, where int) int {
+	before := e.weather_here(where, sk)
+
+	if n == 0 {
+		n = e.new_ent(T_storm, sk)
+		if n <= 0 {
+			return -1
+		}
+	}
+
+	e.p_misc(n).storm_str = short(aura)
+	e.set_where(n, where)
+
+	e.globals.show_to_garrison = true
+	if before == 0 {
+		switch sk {
+		case sub_rain:
+			wout(where, "It has begun to rain.")
+		case sub_wind:
+			wout(where, "It has become quite windy.")
+		case sub_fog:
+			wout(where, "It has become quite foggy.")
+		}
+	}
+	e.globals.show_to_garrison = false
+
+	return 0
+}
+
+// dissipate_storm removes a storm from the world, returning the cookie
+// it was summoned with and unbinding it from its ship.
+// Ported from src/storm.c lines 267-327.
+func (e *Engine) dissipate_storm(storm int, show bool) {
+	where := e.subloc(storm)
+
+	if owner := e.npc_summoner(storm); owner != 0 && e.kind(owner) == T_char {
+		wout(owner, "%s has dissipated.", e.box_name(storm))
+	}
+
+	if show {
+		sk := e.subkind(storm)
+		if e.weather_here(where, sk) == 0 {
+			switch sk {
+			case sub_rain:
+				wout(where, "It has stopped raining.")
+			case sub_wind:
+				wout(where, "It is no longer windy.")
+			case sub_fog:
+				wout(where, "The fog has cleared.")
+			}
+		}
+	}
+
+	e.set_where(storm, 0)
+
+	p := e.p_misc(storm)
+	if p.npc_home != 0 && p.npc_cookie != 0 {
+		e.gen_item(p.npc_home, p.npc_cookie, 1)
+	}
+
+	if ship := e.storm_bind(storm); ship != 0 {
+		if sl := e.rp_subloc(ship); sl != nil {
+			IListRemValue(&sl.bound_storms, storm)
+			e.markDirty(ship)
+		}
+		p.bind_storm = 0
+	}
+
+	e.delete_box(storm)
+}
+
+// create_some_storms starts up to num storms of kind sk in provinces of
+// the world that don't already have that weather.
+// Ported from src/storm.c lines 1427-1452.
+func (e *Engine) create_some_storms(num int, sk schar) {
+	var l []int
+	for _, i := range e.Provinces() {
+		if e.greater_region(i) != 0 || e.weather_here(i, sk) != 0 {
+			continue
+		}
+		l = append(l, i)
+	}
+
+	e.IListScramble(streamWeather, l)
+
+	for i := 0; i < len(l) && i < num; i++ {
+		e.new_storm(0, sk, e.rndFrom(streamWeather, 2, 3), l[i])
+	}
+}
+
+// natural_weather starts the storms of the season: one storm for every
+// four provinces, half of them made each month over four days.
+// Ported from src/storm.c lines 1454-1509.
+func (e *Engine) natural_weather() {
+	n := e.nprovinces() / 4 / 2 / 4
+
+	// C's oly_month counts from 0
+	switch e.olyMonth() - 1 {
+	case 0: // Fierce winds
+		e.create_some_storms(n, sub_fog)
+		e.create_some_storms(n, sub_wind)
+	case 1: // Snowmelt
+		e.create_some_storms(n, sub_fog)
+		e.create_some_storms(n, sub_rain)
+	case 2: // Blossom bloom
+	case 3: // Sunsear
+		e.create_some_storms(n, sub_rain)
+	case 4: // Thunder and rain
+		e.create_some_storms(n*2, sub_rain)
+	case 5: // Harvest
+	case 6: // Waning days
+		e.create_some_storms(n, sub_rain)
+		e.create_some_storms(n, sub_fog)
+		e.create_some_storms(n, sub_rain)
+	case 7: // Dark night
+		e.create_some_storms(n, sub_wind)
+	}
+}
+
+// v_bind_storm starts binding a storm the caster controls to the ship
+// the caster is on.
+// Ported from src/storm.c lines 8-40.
+func (e *Engine) v_bind_storm(c *command) int {
+	storm := c.a
+	ship := e.subloc(c.who)
+
+	if e.kind(storm) != T_storm || e.npc_summoner(storm) != c.who {
+		wout(c.who, "%s doesn't control any storm %s.", e.box_name(c.who), e.box_code(storm))
+		return FALSE
+	}
+
+	if !e.is_ship(ship) {
+		wout(c.who, "%s must be on a ship to bind the storm to.", e.box_name(c.who))
+		return FALSE
+	}
+
+	if e.province(storm) != e.province(ship) {
+		wout(c.who, "Storm must be in the same province as the ship it is to be bound to.")
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 3) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_bind_storm binds the storm to the ship, so it follows the ship
+// when it sails.
+// Ported from src/storm.c lines 43-89.
+func (e *Engine) d_bind_storm(c *command) int {
+	storm := c.a
+	ship := e.subloc(c.who)
+
+	if e.kind(storm) != T_storm || e.npc_summoner(storm) != c.who {
+		wout(c.who, "%s doesn't control storm %s anymore.", e.box_name(c.who), e.box_code(storm))
+		return FALSE
+	}
+
+	if !e.is_ship(ship) {
+		wout(c.who, "%s is no longer on a ship.", e.box_name(c.who))
+		return FALSE
+	}
+
+	if e.province(storm) != e.province(ship) {
+		wout(c.who, "Storm is no longer in the same province as the ship it is to be bound to.")
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, 3) {
+		return FALSE
+	}
+
+	if old := e.storm_bind(storm); old != 0 {
+		if p := e.rp_subloc(old); p != nil {
+			IListRemValue(&p.bound_storms, storm)
+			e.markDirty(old)
+		}
+	}
+
+	// C stored the storm itself here; everything reading storm_bind
+	// expects the ship.
+	e.p_misc(storm).bind_storm = ship
+	p := e.p_subloc(ship)
+	p.bound_storms = append(p.bound_storms, storm)
+
+	wout(c.who, "Bound %s to %s.", e.box_name(storm), e.box_name(ship))
+	return TRUE
+}
+
+// move_storm moves a storm to dest and tells both provinces about the
+// change in weather.
+// Ported from src/storm.c lines 92-151.
+func (e *Engine) move_storm(storm, dest int) {
+	orig := e.subloc(storm)
+	sk := e.subkind(storm)
+
+	before := e.weather_here(dest, sk)
+
+	e.set_where(storm, dest)
+
+	owner := e.npc_summoner(storm)
+	if e.valid_box(owner) && e.valid_box(e.player(owner)) {
+		touch_loc_pl(e.player(owner), dest)
+	}
+
+	e.globals.show_to_garrison = true
+
+	if e.weather_here(orig, sk) == 0 {
+		switch sk {
+		case sub_rain:
+			wout(orig, "It has stopped raining.")
+		case sub_wind:
+			wout(orig, "It is no longer windy.")
+		case sub_fog:
+			wout(orig, "The fog has cleared.")
+		default:
+			panic("move_storm: not a storm kind")
+		}
+	}
+
+	if before == 0 {
+		switch sk {
+		case sub_rain:
+			wout(dest, "It has begun to rain.")
+		case sub_wind:
+			wout(dest, "It has become quite windy.")
+		case sub_fog:
+			wout(dest, "It has become quite foggy.")
+		}
+	}
+
+	e.globals.show_to_garrison = false
+}
+
+// move_bound_storms moves the storms bound to a ship along with it,
+// dropping any that no longer exist.
+// Ported from src/storm.c lines 154-177.
+func (e *Engine) move_bound_storms(ship, where int) {
+	p := e.rp_subloc(ship)
+	if p == nil {
+		return
+	}
+
+	for i := 0; i < len(p.bound_storms); i++ {
+		storm := p.bound_storms[i]
+		if e.kind(storm) != T_storm {
+			IListRemValue(&p.bound_storms, storm)
+			e.markDirty(ship)
+			i--
+			continue
+		}
+
+		e.move_storm(storm, e.province(where))
+	}
+}
+
+// storm_here_s describes where relative to who, for the storm spells'
+// messages.
+func (e *Engine) storm_here_s(who, where int) string {
+	if where == e.province(e.subloc(who)) {
+		return "here"
+	}
+	return sout("in %s", e.box_name(where))
+}
+
+// v_summon_storm starts summoning a storm of at least three aura from
+// the province the caster's spells reach.
+func (e *Engine) v_summon_storm(c *command, cookie int) int {
+	c.a = max(c.a, 3)
+	aura := c.a
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	where := e.province(e.reset_cast_where(c.who))
+	c.d = where
+
+	if !e.may_cookie_npc(c.who, where, cookie) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_summon_storm summons a storm of kind sk with twice the aura spent,
+// naming it if the order gave a name.
+func (e *Engine) d_summon_storm(c *command, sk schar, cookie int) int {
+	aura := c.a
+	where := c.d
+
+	if !e.may_cookie_npc(c.who, where, cookie) {
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	n := e.do_cookie_npc(c.who, where, cookie, where)
+	if n <= 0 {
+		wout(c.who, "Failed to summon a storm.")
+		return FALSE
+	}
+
+	e.reset_cast_where(c.who)
+
+	if name := get_parse_arg(c, 2); e.numargs(c) >= 2 && name != "" {
+		e.set_name(n, name)
+	}
+
+	e.new_storm(n, sk, aura*2, where)
+
+	wout(c.who, "Summoned %s.", e.box_name_kind(n))
+
+	touch_loc_pl(e.player(c.who), where)
+
+	return TRUE
+}
+
+// v_summon_rain starts summoning a rain storm.
+// Ported from src/storm.c lines 352-371.
+func (e *Engine) v_summon_rain(c *command) int {
+	return e.v_summon_storm(c, item_rain_cookie)
+}
+
+// d_summon_rain summons a rain storm.
+// Ported from src/storm.c lines 374-406.
+func (e *Engine) d_summon_rain(c *command) int {
+	return e.d_summon_storm(c, sub_rain, item_rain_cookie)
+}
+
+// v_summon_wind starts summoning a wind storm.
+// Ported from src/storm.c lines 409-428.
+func (e *Engine) v_summon_wind(c *command) int {
+	return e.v_summon_storm(c, item_wind_cookie)
+}
+
+// d_summon_wind summons a wind storm.
+// Ported from src/storm.c lines 431-468.
+func (e *Engine) d_summon_wind(c *command) int {
+	return e.d_summon_storm(c, sub_wind, item_wind_cookie)
+}
+
+// v_summon_fog starts summoning a fog.
+// Ported from src/storm.c lines 471-490.
+func (e *Engine) v_summon_fog(c *command) int {
+	return e.v_summon_storm(c, item_fog_cookie)
+}
+
+// d_summon_fog summons a fog.
+// Ported from src/storm.c lines 493-527.
+func (e *Engine) d_summon_fog(c *command) int {
+	return e.d_summon_storm(c, sub_fog, item_fog_cookie)
+}
+
+// parse_storm_dir finds the province exit a storm should take, given
+// either a destination or a direction in c.parse[1].
+// Ported from src/storm.c lines 530-595.
+func (e *Engine) parse_storm_dir(c *command, storm int) *exit_view {
+	where := e.subloc(storm)
+	arg := get_parse_arg(c, 1)
+
+	l := e.exits_from_loc_nsew(c.who, where)
+
+	if e.valid_box(c.a) {
+		if where == c.a {
+			wout(c.who, "%s is already in %s.", e.box_name(storm), e.box_name(where))
+			return nil
+		}
+
+		var ret *exit_view
+		for _, v := range l {
+			if v.destination == c.a {
+				ret = v
+			}
+		}
+		if ret != nil {
+			return ret
+		}
+
+		wout(c.who, "No route from %s to %s.", e.box_name(where), arg)
+		return nil
+	}
+
+	dir := lookup(full_dir_s, arg)
+	if dir < 0 {
+		dir = lookup(short_dir_s, arg)
+	}
+
+	if dir < 0 {
+		wout(c.who, "Unknown direction or destination '%s'.", arg)
+		return nil
+	}
+
+	if dir < DIR_N || dir > DIR_W {
+		wout(c.who, "Direction must be N, S, E or W.")
+		return nil
+	}
+
+	for _, v := range l {
+		if v.direction == dir && e.loc_depth(v.destination) == LOC_province {
+			return v
+		}
+	}
+
+	wout(c.who, "No %s route from %s.", full_dir_s[dir], e.box_name(where))
+	return nil
+}
+
+// v_direct_storm sets where a storm the caster controls moves at
+// month end.
+// Ported from src/storm.c lines 598-634.
+func (e *Engine) v_direct_storm(c *command) int {
+	storm := c.a
+
+	if e.kind(storm) != T_storm || e.npc_summoner(storm) != c.who {
+		wout(c.who, "You don't control any storm %s.", e.box_code(storm))
+		return FALSE
+	}
+
+	e.cmd_shift(c)
+
+	v := e.parse_storm_dir(c, storm)
+	if v == nil {
+		return FALSE
+	}
+
+	if e.loc_depth(v.destination) != LOC_province {
+		wout(c.who, "Can't direct storm to %s.", e.box_code(v.destination))
+		return FALSE
+	}
+
+	dest := v.destination
+	p := e.p_misc(storm)
+	p.storm_move = dest
+	p.npc_dir = schar(v.direction)
+
+	wout(c.who, "%s will move to %s at month end.", e.box_name(storm), e.box_name(dest))
+
+	return TRUE
+}
+
+// v_dissipate starts dissipating a storm the caster controls.
+// Ported from src/storm.c lines 637-667.
+func (e *Engine) v_dissipate(c *command) int {
+	storm := c.a
+
+	if e.kind(storm) != T_storm || e.npc_summoner(storm) != c.who {
+		wout(c.who, "You don't control any storm %s.", e.box_code(storm))
+		return FALSE
+	}
+
+	where := e.province(e.reset_cast_where(c.who))
+	c.d = where
+
+	if e.subloc(storm) != where {
+		wout(c.who, "%s is not %s.", e.box_name(storm), e.storm_here_s(c.who, where))
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_dissipate dissipates the storm, returning a quarter of its
+// strength to the caster as aura.
+// Ported from src/storm.c lines 670-708.
+func (e *Engine) d_dissipate(c *command) int {
+	storm := c.a
+	where := c.d
+
+	if e.kind(storm) != T_storm || e.npc_summoner(storm) != c.who {
+		wout(c.who, "You don't control any storm %s.", e.box_code(storm))
+		return FALSE
+	}
+
+	if e.subloc(storm) != where {
+		wout(c.who, "%s is not %s.", e.box_name(storm), e.storm_here_s(c.who, where))
+		return FALSE
+	}
+
+	p := e.p_misc(storm)
+	pc := e.p_magic(c.who)
+
+	pc.cur_aura += int(p.storm_str) / 4
+	e.limit_cur_aura(c.who)
+	p.storm_str = 0
+
+	e.dissipate_storm(storm, true)
+	out(c.who, "Current aura is now %s.", comma_num(pc.cur_aura))
+
+	return TRUE
+}
+
+// v_renew_storm starts strengthening a storm.
+// Ported from src/storm.c lines 711-747.
+func (e *Engine) v_renew_storm(c *command) int {
+	storm := c.a
+
+	if e.kind(storm) != T_storm {
+		wout(c.who, "%s is not a storm.", e.box_code(storm))
+		return FALSE
+	}
+
+	c.b = max(c.b, 1)
+	aura := c.b
+
+	if !e.check_aura(c.who, aura) {
+		return FALSE
+	}
+
+	where := e.province(e.reset_cast_where(c.who))
+	c.d = where
+
+	if e.subloc(storm) != where {
+		wout(c.who, "%s is not %s.", e.box_name(storm), e.storm_here_s(c.who, where))
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_renew_storm adds twice the aura spent to the storm's strength.
+// Ported from src/storm.c lines 750-786.
+func (e *Engine) d_renew_storm(c *command) int {
+	storm := c.a
+	aura := c.b
+	where := c.d
+
+	if e.kind(storm) != T_storm {
+		wout(c.who, "%s is not a storm.", e.box_code(storm))
+		return FALSE
+	}
+
+	if e.subloc(storm) != where {
+		wout(c.who, "%s is not %s.", e.box_name(storm), e.storm_here_s(c.who, where))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, aura) {
+		return FALSE
+	}
+
+	p := e.p_misc(storm)
+	p.storm_str += short(aura * 2)
+
+	out(c.who, "%s is now strength %s.", e.box_name(storm), comma_num(int(p.storm_str)))
+
+	return TRUE
+}
+
+// lightning_target_ok reports whether target may be struck by storm:
+// a character or building in the storm's province, outside any safe
+// haven.
+func (e *Engine) lightning_target_ok(c *command, storm, target int) bool {
+	if e.kind(storm) != T_storm || e.npc_summoner(storm) != c.who {
+		wout(c.who, "You don't control any storm %s.", e.box_code(storm))
+		return false
+	}
+
+	if e.subkind(storm) != sub_rain {
+		wout(c.who, "%s is not a rain storm.", e.box_name(storm))
+		return false
+	}
+
+	where := e.subloc(storm)
+
+	if e.kind(target) != T_char && !e.is_loc_or_ship(target) {
+		wout(c.who, "%s is not a valid target.", e.box_code(target))
+		return false
+	}
+
+	if e.is_loc_or_ship(target) && e.loc_depth(target) != LOC_build {
+		wout(c.who, "%s is not a valid target.", e.box_code(target))
+		return false
+	}
+
+	if e.subloc(target) != where {
+		wout(c.who, "Target %s isn't in the same place as the storm.", e.box_code(target))
+		return false
+	}
+
+	if e.in_safe_now(target) {
+		wout(c.who, "Not allowed in a safe haven.")
+		return false
+	}
+
+	return true
+}
+
+// v_lightning starts calling lightning from a rain storm.
+// Ported from src/storm.c lines 789-837.
+func (e *Engine) v_lightning(c *command) int {
+	if !e.lightning_target_ok(c, c.a, c.b) {
+		return FALSE
+	}
+	return TRUE
+}
+
+// d_lightning strikes the target with up to the storm's strength in
+// damage, spending that much of the storm.
+// Ported from src/storm.c lines 840-917.
+func (e *Engine) d_lightning(c *command) int {
+	storm := c.a
+	target := c.b
+	aura := c.c
+
+	if !e.lightning_target_ok(c, storm, target) {
+		return FALSE
+	}
+
+	where := e.subloc(storm)
+	p := e.p_misc(storm)
+
+	if aura == 0 || aura > int(p.storm_str) {
+		aura = int(p.storm_str)
+	}
+
+	p.storm_str -= short(aura)
+
+	wout(c.who, "%s strikes %s with a lightning bolt!", e.box_name(storm), e.box_name(target))
+
+	vector_clear()
+	vector_add(where)
+	vector_add(target)
+	wout(VECT, "%s was struck by lightning!", e.box_name(target))
+
+	if e.is_loc_or_ship(target) {
+		e.add_structure_damage(target, aura, true)
+	} else {
+		e.add_char_damage(target, aura, MATES)
+	}
+
+	if p.storm_str <= 0 {
+		e.dissipate_storm(storm, true)
+	}
+
+	return TRUE
+}
+
+// v_seize_storm starts taking control of a storm.
+// Ported from src/storm.c lines 976-1014.
+func (e *Engine) v_seize_storm(c *command) int {
+	storm := c.a
+
+	if e.kind(storm) != T_storm {
+		wout(c.who, "%s isn't a storm.", e.box_code(storm))
+		return FALSE
+	}
+
+	if e.npc_summoner(storm) == c.who {
+		wout(c.who, "You already control %s.", e.box_name(storm))
+		return FALSE
+	}
+
+	if !e.check_aura(c.who, 5) {
+		return FALSE
+	}
+
+	where := e.province(e.reset_cast_where(c.who))
+	c.d = where
+
+	if e.subloc(storm) != where {
+		wout(c.who, "%s is not %s.", e.box_name(storm), e.storm_here_s(c.who, where))
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_seize_storm makes the caster the storm's summoner.
+// Ported from src/storm.c lines 1017-1066.
+func (e *Engine) d_seize_storm(c *command) int {
+	storm := c.a
+	where := c.d
+
+	if e.kind(storm) != T_storm {
+		wout(c.who, "%s isn't a storm.", e.box_code(storm))
+		return FALSE
+	}
+
+	owner := e.npc_summoner(storm)
+
+	if owner != 0 && owner == c.who {
+		wout(c.who, "You already control %s.", e.box_name(storm))
+		return FALSE
+	}
+
+	if e.subloc(storm) != where {
+		wout(c.who, "%s is not %s.", e.box_name(storm), e.storm_here_s(c.who, where))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, 5) {
+		return FALSE
+	}
+
+	vector_clear()
+	vector_add(c.who)
+	if owner != 0 {
+		vector_add(owner)
+	}
+
+	wout(VECT, "%s seized control of %s!", e.box_name(c.who), e.box_name(storm))
+
+	e.p_misc(storm).summoned_by = c.who
+
+	touch_loc_pl(e.player(c.who), where)
+
+	return TRUE
+}
+
+// death_fog_target_ok reports whether target may be attacked with
+// storm: a character in the fog's province.
+func (e *Engine) death_fog_target_ok(c *command, storm, target int) bool {
+	if e.kind(storm) != T_storm || e.npc_summoner(storm) != c.who {
+		wout(c.who, "You don't control any storm %s.", e.box_code(storm))
+		return false
+	}
+
+	if e.subkind(storm) != sub_fog {
+		wout(c.who, "%s is not a fog.", e.box_name(storm))
+		return false
+	}
+
+	if e.kind(target) != T_char {
+		wout(c.who, "%s is not a valid target.", e.box_code(target))
+		return false
+	}
+
+	if e.subloc(target) != e.subloc(storm) {
+		wout(c.who, "Target %s isn't in the same place as the fog.", e.box_code(target))
+		return false
+	}
+
+	return true
+}
+
+// v_death_fog starts turning a fog on a character's men.
+// Ported from src/storm.c lines 1069-1111.
+func (e *Engine) v_death_fog(c *command) int {
+	if !e.death_fog_target_ok(c, c.a, c.b) {
+		return FALSE
+	}
+
+	if e.in_safe_now(c.b) {
+		wout(c.who, "Not allowed in a safe haven.")
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// fog_excuse says what became of the men a death fog killed.
+// Ported from src/storm.c lines 1114-1128.
+func (e *Engine) fog_excuse() string {
+	switch e.rndFrom(streamMagic, 1, 3) {
+	case 1:
+		return "wandered off in the fog and were lost."
+	case 2:
+		return "choked to death in the poisonous fog."
+	default:
+		return "disappeared in the fog."
+	}
+}
+
+// d_death_fog kills up to twice the aura given in the target's
+// peasants, workers, soldiers, sailors and crossbowmen, in that order,
+// spending the fog's strength as it goes.
+// Ported from src/storm.c lines 1131-1268.
+func (e *Engine) d_death_fog(c *command) int {
+	storm := c.a
+	target := c.b
+	aura := c.c
+
+	if !e.death_fog_target_ok(c, storm, target) {
+		return FALSE
+	}
+
+	p := e.p_misc(storm)
+
+	aura *= 2
+	p.storm_str *= 2
+
+	if aura == 0 || aura > int(p.storm_str) {
+		aura = int(p.storm_str)
+	}
+
+	save_aura := aura
+
+	kills := []int{item_peasant, item_worker, item_soldier, item_sailor, item_crossbowman}
+	for _, item := range kills {
+		n := min(e.has_item(target, item), aura)
+		e.consume_item(target, item, n)
+		aura -= n
+	}
+
+	aura_used := save_aura - aura
+
+	men := "men"
+	if aura_used == 1 {
+		men = "man"
+	}
+
+	if aura_used == 0 {
+		wout(c.who, "%s has no vulnerable men.", e.box_name(target))
+		p.storm_str /= 2
+		return FALSE
+	}
+
+	wout(target, "%s %s %s", cap(nice_num(aura_used)), men, e.fog_excuse())
+	wout(c.who, "Killed %s %s.", nice_num(aura_used), men)
+
+	p.storm_str -= short(aura_used)
+	p.storm_str /= 2
+
+	if p.storm_str <= 0 {
+		e.dissipate_storm(storm, true)
+	}
+
+	return TRUE
+}
+
+// v_banish_corpses starts banishing the corpses where the caster's
+// spells reach.
+// Ported from src/storm.c lines 1271-1277.
+func (e *Engine) v_banish_corpses(c *command) int {
+	c.d = e.reset_cast_where(c.who)
+
+	return TRUE
+}
+
+// d_banish_corpses destroys every corpse held there, for one aura
+// each.
+// Ported from src/storm.c lines 1280-1323.
+func (e *Engine) d_banish_corpses(c *command) int {
+	where := c.d
+
+	var l []int
+	e.loop_char_here(where, &l)
+
+	sum := 0
+	for _, i := range l {
+		sum += e.has_item(i, item_corpse)
+	}
+
+	if sum == 0 {
+		wout(c.who, "There are no %s here.", e.plural_item_name(item_corpse, 2))
+		return FALSE
+	}
+
+	if !e.charge_aura(c.who, sum) {
+		return FALSE
+	}
+
+	wout(c.who, "Banished %s %s.", comma_num(sum), e.plural_item_name(item_corpse, sum))
+	wout(where, "%s banished %s %s!", e.box_name(c.who), comma_num(sum), e.plural_item_name(item_corpse, sum))
+
+	for _, i := range l {
+		n := e.has_item(i, item_corpse)
+		if n == 0 {
+			continue
+		}
+
+		e.consume_item(i, item_corpse, n)
+		wout(i, "%s banished our %s!", e.box_name(c.who), e.plural_item_name(item_corpse, n))
+	}
+
+	return TRUE
+}
+
+// fierce_wind_target_ok reports whether target may be buffeted by
+// storm: a building in the wind's province.
+func (e *Engine) fierce_wind_target_ok(c *command, storm, target int) bool {
+	if e.kind(storm) != T_storm || e.npc_summoner(storm) != c.who {
+		wout(c.who, "You don't control any storm %s.", e.box_code(storm))
+		return false
+	}
+
+	if e.subkind(storm) != sub_wind {
+		wout(c.who, "%s is not a wind storm.", e.box_name(storm))
+		return false
+	}
+
+	if !e.is_loc_or_ship(target) || e.loc_depth(target) != LOC_build {
+		wout(c.who, "%s is not a valid target.", e.box_code(target))
+		return false
+	}
+
+	if e.subloc(target) != e.subloc(storm) {
+		wout(c.who, "Target %s isn't in the same place as the storm.", e.box_code(target))
+		return false
+	}
+
+	return true
+}
+
+// v_fierce_wind starts turning a wind storm on a building.
+// Ported from src/storm.c lines 1326-1362.
+func (e *Engine) v_fierce_wind(c *command) int {
+	if !e.fierce_wind_target_ok(c, c.a, c.b) {
+		return FALSE
+	}
+	return TRUE
+}
+
+// d_fierce_wind damages the building by up to the storm's strength,
+// spending that much of the storm.
+// Ported from src/storm.c lines 1365-1424.
+func (e *Engine) d_fierce_wind(c *command) int {
+	storm := c.a
+	target := c.b
+	aura := c.c
+
+	if !e.fierce_wind_target_ok(c, storm, target) {
+		return FALSE
+	}
+
+	where := e.subloc(storm)
+	p := e.p_misc(storm)
+
+	// C compared the other way round, so every wind spent its whole
+	// strength; clamp the way d_lightning does.
+	if aura == 0 || aura > int(p.storm_str) {
+		aura = int(p.storm_str)
+	}
+
+	p.storm_str -= short(aura)
+
+	vector_clear()
+	vector_add(where)
+	vector_add(target)
+	vector_add(c.who)
+	wout(VECT, "%s is buffeted by a fierce wind!", e.box_name(target))
+
+	e.add_structure_damage(target, aura, true)
+
+	if p.storm_str <= 0 {
+		e.dissipate_storm(storm, true)
+	}
+
+	return TRUE
+}
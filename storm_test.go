@@ -25,32 +25,32 @@ func TestShipMoving(t *testing.T) {
 	defer func() { teg.globals.bx = oldBx }()
 
 	// Clear bx array for testing
-	teg.globals.bx = [MAX_BOXES]*box{}
+	teg.globals.bx = boxStore{}
 
 	// Test 1: nil box returns 0
-	if got := ship_moving(1000); got != 0 {
+	if got := teg.ship_moving(1000); got != 0 {
 		t.Errorf("ship_moving(nil box) = %d, want 0", got)
 	}
 
 	// Test 2: box with nil subloc returns 0
-	teg.globals.bx[1001] = &box{}
-	if got := ship_moving(1001); got != 0 {
+	teg.setBox(1001, &box{})
+	if got := teg.ship_moving(1001); got != 0 {
 		t.Errorf("ship_moving(nil subloc) = %d, want 0", got)
 	}
 
 	// Test 3: ship not moving returns 0
-	teg.globals.bx[1002] = &box{
+	teg.setBox(1002, &box{
 		x_subloc: &entity_subloc{moving: 0},
-	}
-	if got := ship_moving(1002); got != 0 {
+	})
+	if got := teg.ship_moving(1002); got != 0 {
 		t.Errorf("ship_moving(not moving) = %d, want 0", got)
 	}
 
 	// Test 4: ship moving returns daystamp
-	teg.globals.bx[1003] = &box{
+	teg.setBox(1003, &box{
 		x_subloc: &entity_subloc{moving: 42},
-	}
-	if got := ship_moving(1003); got != 42 {
+	})
+	if got := teg.ship_moving(1003); got != 42 {
 		t.Errorf("ship_moving(moving) = %d, want 42", got)
 	}
 }
@@ -68,36 +68,36 @@ func TestShipGone(t *testing.T) {
 	}()
 
 	// Clear state for testing
-	teg.globals.bx = [MAX_BOXES]*box{}
+	teg.globals.bx = boxStore{}
 	teg.globals.sysclock = olytime{days_since_epoch: 100}
 	teg.globals.evening = false
 
 	// Test 1: ship not moving returns 0
-	teg.globals.bx[1000] = &box{
+	teg.setBox(1000, &box{
 		x_subloc: &entity_subloc{moving: 0},
-	}
-	if got := ship_gone(1000); got != 0 {
+	})
+	if got := teg.ship_gone(1000); got != 0 {
 		t.Errorf("ship_gone(not moving) = %d, want 0", got)
 	}
 
 	// Test 2: ship moving, not evening
-	teg.globals.bx[1001] = &box{
+	teg.setBox(1001, &box{
 		x_subloc: &entity_subloc{moving: 95},
-	}
+	})
 	// Expected: 100 - 95 + 0 = 5
-	if got := ship_gone(1001); got != 5 {
+	if got := teg.ship_gone(1001); got != 5 {
 		t.Errorf("ship_gone(moving, not evening) = %d, want 5", got)
 	}
 
 	// Test 3: ship moving, is evening
 	teg.globals.evening = true
 	// Expected: 100 - 95 + 1 = 6
-	if got := ship_gone(1001); got != 6 {
+	if got := teg.ship_gone(1001); got != 6 {
 		t.Errorf("ship_gone(moving, is evening) = %d, want 6", got)
 	}
 
 	// Test 4: nil box returns 0
-	if got := ship_gone(9999); got != 0 {
+	if got := teg.ship_gone(9999); got != 0 {
 		t.Errorf("ship_gone(nil box) = %d, want 0", got)
 	}
 }
@@ -109,32 +109,32 @@ func TestCharMoving(t *testing.T) {
 	defer func() { teg.globals.bx = oldBx }()
 
 	// Clear bx array for testing
-	teg.globals.bx = [MAX_BOXES]*box{}
+	teg.globals.bx = boxStore{}
 
 	// Test 1: nil box returns 0
-	if got := char_moving(2000); got != 0 {
+	if got := teg.char_moving(2000); got != 0 {
 		t.Errorf("char_moving(nil box) = %d, want 0", got)
 	}
 
 	// Test 2: box with nil char returns 0
-	teg.globals.bx[2001] = &box{}
-	if got := char_moving(2001); got != 0 {
+	teg.setBox(2001, &box{})
+	if got := teg.char_moving(2001); got != 0 {
 		t.Errorf("char_moving(nil char) = %d, want 0", got)
 	}
 
 	// Test 3: character not moving returns 0
-	teg.globals.bx[2002] = &box{
+	teg.setBox(2002, &box{
 		x_char: &entity_char{moving: 0},
-	}
-	if got := char_moving(2002); got != 0 {
+	})
+	if got := teg.char_moving(2002); got != 0 {
 		t.Errorf("char_moving(not moving) = %d, want 0", got)
 	}
 
 	// Test 4: character moving returns daystamp
-	teg.globals.bx[2003] = &box{
+	teg.setBox(2003, &box{
 		x_char: &entity_char{moving: 77},
-	}
-	if got := char_moving(2003); got != 77 {
+	})
+	if got := teg.char_moving(2003); got != 77 {
 		t.Errorf("char_moving(moving) = %d, want 77", got)
 	}
 }
@@ -147,26 +147,26 @@ func TestCharGone(t *testing.T) {
 	defer func() { teg.globals.bx = oldBx }()
 
 	// Clear bx array for testing
-	teg.globals.bx = [MAX_BOXES]*box{}
+	teg.globals.bx = boxStore{}
 
 	// Test 1: character not moving returns 0
-	teg.globals.bx[2000] = &box{
+	teg.setBox(2000, &box{
 		x_char: &entity_char{moving: 0},
-	}
-	if got := char_gone(2000); got != 0 {
+	})
+	if got := teg.char_gone(2000); got != 0 {
 		t.Errorf("char_gone(not moving) = %d, want 0", got)
 	}
 
 	// Test 2: character moving returns 1 (simplified version)
-	teg.globals.bx[2001] = &box{
+	teg.setBox(2001, &box{
 		x_char: &entity_char{moving: 50},
-	}
-	if got := char_gone(2001); got != 1 {
+	})
+	if got := teg.char_gone(2001); got != 1 {
 		t.Errorf("char_gone(moving) = %d, want 1", got)
 	}
 
 	// Test 3: nil box returns 0
-	if got := char_gone(9999); got != 0 {
+	if got := teg.char_gone(9999); got != 0 {
 		t.Errorf("char_gone(nil box) = %d, want 0", got)
 	}
 }
@@ -185,31 +185,31 @@ func TestCharGoneFull(t *testing.T) {
 	}()
 
 	// Clear state for testing
-	teg.globals.bx = [MAX_BOXES]*box{}
+	teg.globals.bx = boxStore{}
 	teg.globals.sysclock = olytime{days_since_epoch: 200}
 	teg.globals.evening = false
 
 	// Test 1: character not moving returns 0
-	teg.globals.bx[2000] = &box{
+	teg.setBox(2000, &box{
 		x_char: &entity_char{moving: 0},
-	}
-	if got := char_gone_full(2000); got != 0 {
+	})
+	if got := teg.char_gone_full(2000); got != 0 {
 		t.Errorf("char_gone_full(not moving) = %d, want 0", got)
 	}
 
 	// Test 2: character moving, not evening
-	teg.globals.bx[2001] = &box{
+	teg.setBox(2001, &box{
 		x_char: &entity_char{moving: 193},
-	}
+	})
 	// Expected: 200 - 193 + 0 = 7
-	if got := char_gone_full(2001); got != 7 {
+	if got := teg.char_gone_full(2001); got != 7 {
 		t.Errorf("char_gone_full(moving, not evening) = %d, want 7", got)
 	}
 
 	// Test 3: character moving, is evening
 	teg.globals.evening = true
 	// Expected: 200 - 193 + 1 = 8
-	if got := char_gone_full(2001); got != 8 {
+	if got := teg.char_gone_full(2001); got != 8 {
 		t.Errorf("char_gone_full(moving, is evening) = %d, want 8", got)
 	}
 }
@@ -223,3 +223,188 @@ func TestBoolToInt(t *testing.T) {
 		t.Errorf("boolToInt(true) = %d, want 1", got)
 	}
 }
+
+func TestNaturalWeather(t *testing.T) {
+	e := newTestEngine(t)
+	e.SetSeed(3)
+	for i := range 64 {
+		e.alloc_box(10_101+i, T_loc, sub_plain)
+	}
+	e.globals.sysclock.turn = 5 // Thunder and rain
+
+	e.natural_weather()
+
+	storms := e.Storms()
+	if len(storms) != 4 {
+		t.Fatalf("storms: got %d, want 4", len(storms))
+	}
+	for _, s := range storms {
+		if e.subkind(s) != sub_rain {
+			t.Errorf("storm %d: got subkind %d, want rain", s, e.subkind(s))
+		}
+		if str := e.storm_strength(s); str < 2 || str > 3 {
+			t.Errorf("storm %d: strength %d out of range", s, str)
+		}
+		if e.weather_here(e.subloc(s), sub_rain) == 0 {
+			t.Errorf("storm %d: no rain at %d", s, e.subloc(s))
+		}
+	}
+	for name := range e.streams {
+		if name != streamWeather {
+			t.Errorf("natural weather drew from stream %q", name)
+		}
+	}
+
+	for range 3 {
+		e.stormDecay()
+	}
+	if n := len(e.Storms()); n != 0 {
+		t.Errorf("storms after decay: got %d, want 0", n)
+	}
+}
+
+func TestWeatherDays(t *testing.T) {
+	days := func(seed uint64) []int {
+		e := &Engine{}
+		e.SetSeed(seed)
+		e.globals.sysclock.turn = 9
+		var got []int
+		for d := 1; d <= MONTH_DAYS; d++ {
+			e.globals.sysclock.day = short(d)
+			if e.weatherDay() {
+				got = append(got, d)
+			}
+		}
+		return got
+	}
+
+	a := days(11)
+	if len(a) != 4 {
+		t.Fatalf("weather days: got %v, want 4 days", a)
+	}
+	// a turn resumed mid-month, with its streams moved on, keeps the days
+	e := &Engine{}
+	e.SetSeed(11)
+	e.rndFrom(streamWeather, 1, 100)
+	e.globals.sysclock.turn = 9
+	for _, d := range a {
+		e.globals.sysclock.day = short(d)
+		if !e.weatherDay() {
+			t.Errorf("day %d: not a weather day after resume", d)
+		}
+	}
+}
+
+// setupStormTest builds a magician in a forest province that holds a
+// rain cookie.
+func setupStormTest(t *testing.T) (who, where int) {
+	t.Helper()
+	who = setupBasicTest(t, 40, 20)
+	where = teg.subloc(who)
+	teg.change_box_subkind(where, sub_forest)
+
+	for _, item := range []int{item_rain_cookie, item_peasant, item_worker} {
+		teg.alloc_box(item, T_item, 0)
+	}
+	teg.gen_item(where, item_rain_cookie, 1)
+	return who, where
+}
+
+func TestSummonAndDissipateRain(t *testing.T) {
+	who, where := setupStormTest(t)
+
+	c := &command{who: who, a: 4}
+	if teg.v_summon_rain(c) != TRUE || teg.d_summon_rain(c) != TRUE {
+		t.Fatal("summon rain failed")
+	}
+	storms := teg.Storms()
+	if len(storms) != 1 {
+		t.Fatalf("storms: got %d, want 1", len(storms))
+	}
+	storm := storms[0]
+	if got := teg.storm_strength(storm); got != 8 {
+		t.Errorf("strength = %d, want 8", got)
+	}
+	if got := teg.npc_summoner(storm); got != who {
+		t.Errorf("summoner = %d, want %d", got, who)
+	}
+	if teg.has_item(where, item_rain_cookie) != 0 {
+		t.Error("rain cookie was not used up")
+	}
+
+	c = &command{who: who, a: storm}
+	if teg.v_dissipate(c) != TRUE || teg.d_dissipate(c) != TRUE {
+		t.Fatal("dissipate failed")
+	}
+	if got := teg.char_cur_aura(who); got != 18 {
+		t.Errorf("aura = %d, want 18 after spending 4 and getting 2 back", got)
+	}
+	if teg.kind(storm) == T_storm {
+		t.Error("storm still exists")
+	}
+	if teg.has_item(where, item_rain_cookie) != 1 {
+		t.Error("rain cookie was not returned")
+	}
+}
+
+func TestDeathFog(t *testing.T) {
+	who, where := setupStormTest(t)
+	fog := teg.new_ent(T_storm, sub_fog)
+	teg.new_storm(fog, sub_fog, 6, where)
+	teg.p_misc(fog).summoned_by = who
+
+	target := 1002
+	teg.alloc_box(target, T_char, 0)
+	teg.set_where(target, where)
+	teg.gen_item(target, item_peasant, 3)
+	teg.gen_item(target, item_worker, 5)
+
+	c := &command{who: who, a: fog, b: target, c: 2}
+	if teg.v_death_fog(c) != TRUE || teg.d_death_fog(c) != TRUE {
+		t.Fatal("death fog failed")
+	}
+	if got := teg.has_item(target, item_peasant); got != 0 {
+		t.Errorf("peasants = %d, want 0", got)
+	}
+	if got := teg.has_item(target, item_worker); got != 4 {
+		t.Errorf("workers = %d, want 4", got)
+	}
+	if got := teg.storm_strength(fog); got != 4 {
+		t.Errorf("fog strength = %d, want 4", got)
+	}
+}
+
+func TestStormMoves(t *testing.T) {
+	e := newTestEngine(t)
+	from, to := 10_101, 10_102
+	e.alloc_box(from, T_loc, sub_ocean)
+	e.alloc_box(to, T_loc, sub_ocean)
+
+	ship := 5001
+	e.alloc_box(ship, T_ship, sub_galley)
+	e.set_where(ship, from)
+
+	bound := e.new_ent(T_storm, sub_wind)
+	e.new_storm(bound, sub_wind, 4, from)
+	e.p_misc(bound).bind_storm = ship
+	e.p_subloc(ship).bound_storms = []int{bound}
+
+	e.move_bound_storms(ship, to)
+	if got := e.subloc(bound); got != to {
+		t.Errorf("bound storm is in %d, want %d", got, to)
+	}
+
+	directed := e.new_ent(T_storm, sub_rain)
+	e.new_storm(directed, sub_rain, 4, to)
+	p := e.p_misc(directed)
+	p.storm_move = from
+	p.npc_dir = DIR_N
+
+	e.stormMove()
+	if got := e.subloc(directed); got != from {
+		t.Errorf("directed storm is in %d, want %d", got, from)
+	}
+	if p.npc_dir != 0 || p.storm_move != 0 {
+		t.Error("storm direction was not cleared")
+	}
+}
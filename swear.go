@@ -0,0 +1,500 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// swear.go - Bribery, peasant mobs and oath persuasion ported from src/swear.c
+//
+// lord, player, set_lord, set_loyal and unit_deserts live with the
+// other stack routines in stack.go and accessor.go.
+
+package taygete
+
+// char_new_lord reports whether n got a new lord this turn.
+func (e *Engine) char_new_lord(n int) bool {
+	c := e.rp_char(n)
+	if c == nil {
+		return false
+	}
+	return c.new_lord != 0
+}
+
+// np_to_acquire returns the noble points who's player must pay to take
+// target. A unit that went independent comes back to its old player
+// for free.
+// Ported from src/swear.c lines 190-199.
+func (e *Engine) np_to_acquire(who, target int) int {
+	if e.player(target) == indep_player &&
+		e.p_char(target).prev_lord == e.player(who) {
+		return 0
+	}
+
+	return e.char_np_total(target)
+}
+
+// enough_np_to_acquire reports whether who's player can pay for target.
+// Ported from src/swear.c lines 202-215.
+func (e *Engine) enough_np_to_acquire(who, target int) bool {
+	nps := e.np_to_acquire(who, target)
+
+	if int(e.player_np(e.player(who))) < nps {
+		wout(who, "Don't have %d NP%s to take control of %s.",
+			nps, add_s(nps), e.box_name(target))
+		return false
+	}
+
+	return true
+}
+
+// v_bribe starts offering amount gold to a noble of another faction.
+// Ported from src/swear.c lines 346-399.
+func (e *Engine) v_bribe(c *command) int {
+	target := c.a
+	amount := c.b
+
+	if !e.has_skill(c.who, sk_bribe_noble) {
+		wout(c.who, "BRIBE requires knowledge of %s.",
+			cap(e.box_name(sk_bribe_noble)))
+		return FALSE
+	}
+
+	if !e.check_char_here(c.who, target) {
+		return FALSE
+	}
+
+	if e.char_new_lord(target) {
+		wout(c.who, "%s just switched employers this month, and is "+
+			"not looking for a new one so soon.", e.box_name(target))
+		return FALSE
+	}
+
+	if e.is_npc(target) {
+		wout(c.who, "NPC's cannot be bribed.")
+		return FALSE
+	}
+
+	if e.player(target) == e.player(c.who) {
+		wout(c.who, "%s already belongs to our faction.",
+			e.box_name(target))
+		return FALSE
+	}
+
+	if amount == 0 {
+		wout(c.who, "Must specify an amount of gold to use as a bribe.")
+		return FALSE
+	}
+
+	if !e.can_pay(c.who, amount) {
+		wout(c.who, "Don't have %s for a bribe.", gold_s(amount))
+		return FALSE
+	}
+
+	wout(c.who, "Attempt to bribe %s with a gift of %s.",
+		e.box_name(target), gold_s(amount))
+
+	return TRUE
+}
+
+// thanks_for_gift tells who that target kept the gold.
+// Ported from src/swear.c lines 402-425.
+func (e *Engine) thanks_for_gift(who, target int) {
+	switch e.rndFrom(streamSkills, 1, 3) {
+	case 1:
+		wout(who, "%s graciously accepts our gift.", e.box_name(target))
+	case 2:
+		wout(who, "%s thanks us for the gift.", e.box_name(target))
+	case 3:
+		wout(who, "%s pockets the gold.", e.box_name(target))
+	default:
+		panic("thanks_for_gift: bad roll")
+	}
+}
+
+// Bribe outcomes.
+//
+//	over threshold			under threshold
+//	--------------			---------------
+//	35%	switch			50% pocket
+//	30%	pocket			50% report
+//	25%	report bribe
+//	10%	go independent
+const (
+	SWITCH         = 1
+	POCKET         = 2
+	REPORT         = 3
+	HEAD_FOR_HILLS = 4
+)
+
+// d_bribe pays the bribe and rolls for the target's response. Only a
+// bribe of at least the target's contract, and never less than 250
+// gold, can buy an oath-free noble.
+// Ported from src/swear.c lines 442-549.
+func (e *Engine) d_bribe(c *command) int {
+	target := c.a
+	amount := c.b
+	flag := c.c
+	bribe_thresh := 0
+	var outcome int
+
+	if !e.check_still_here(c.who, target) {
+		return FALSE
+	}
+
+	if e.char_new_lord(target) {
+		wout(c.who, "%s just switched employers this month, and is "+
+			"not looking for a new one so soon.", e.box_name(target))
+		return FALSE
+	}
+
+	if !e.charge(c.who, amount) {
+		wout(c.who, "Don't have %s for a bribe.", gold_s(amount))
+		return FALSE
+	}
+
+	switch e.loyal_kind(target) {
+	case LOY_unsworn, LOY_contract:
+		bribe_thresh = e.loyal_rate(target)
+		if bribe_thresh < 250 {
+			bribe_thresh = 250
+		}
+	case LOY_fear:
+		bribe_thresh = 250
+	case LOY_oath:
+	default:
+		panic("d_bribe: unexpected loyalty")
+	}
+
+	if bribe_thresh <= 0 || amount < bribe_thresh {
+		if e.rndFrom(streamSkills, 1, 2) == 1 {
+			outcome = POCKET
+		} else {
+			outcome = REPORT
+		}
+	} else {
+		n := e.rndFrom(streamSkills, 1, 100)
+
+		if n <= 35 {
+			outcome = SWITCH
+		} else if n <= 65 {
+			outcome = POCKET
+		} else if n <= 90 {
+			outcome = REPORT
+		} else {
+			outcome = HEAD_FOR_HILLS
+		}
+	}
+
+	if outcome == SWITCH && !e.enough_np_to_acquire(c.who, target) {
+		outcome = POCKET
+	}
+
+	switch outcome {
+	case SWITCH:
+		wout(c.who, "%s accepts the gift, and has decided to join us.",
+			e.box_name(target))
+		e.unit_deserts(target, e.player(c.who), true, LOY_contract, 250)
+		e.p_char(target).fresh_hire = TRUE
+
+		if flag != 0 {
+			e.join_stack(target, c.who)
+		}
+
+	case HEAD_FOR_HILLS:
+		e.thanks_for_gift(c.who, target)
+		wout(c.who, "%s left the service of %s, but didn't join us.",
+			e.box_name(target), e.box_name(e.player(target)))
+		e.unit_deserts(target, indep_player, true, LOY_unsworn, 0)
+
+	case POCKET:
+		e.thanks_for_gift(c.who, target)
+
+	case REPORT:
+		e.thanks_for_gift(c.who, target)
+		e.gen_item(c.who, item_gold, amount)
+		wout(target, "%s tried to bribe us with %s.",
+			e.box_name(c.who), gold_s(amount))
+
+	default:
+		panic("d_bribe: bad outcome")
+	}
+
+	return TRUE
+}
+
+// v_raise starts a speech to raise a peasant mob here.
+// Ported from src/swear.c lines 843-855.
+func (e *Engine) v_raise(c *command) int {
+	where := e.subloc(c.who)
+
+	if !e.check_skill(c.who, sk_raise_mob) {
+		return FALSE
+	}
+
+	if !e.may_cookie_npc(c.who, where, item_mob_cookie) {
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_raise raises a peasant mob, which stands guard where it was raised.
+// Ported from src/swear.c lines 858-884.
+func (e *Engine) d_raise(c *command) int {
+	where := e.subloc(c.who)
+
+	mob := e.do_cookie_npc(c.who, where, item_mob_cookie, where)
+
+	if mob <= 0 {
+		log_write(LOG_CODE, "d_raise mob <= 0")
+		wout(c.who, "Failed to raise peasant mob.")
+		return FALSE
+	}
+
+	e.add_skill_experience(c.who, sk_raise_mob)
+
+	e.queue(mob, "guard 1")
+	e.init_load_sup(mob) // make ready to execute commands immediately
+
+	wout(c.who, "Raised %s.", e.box_name(mob))
+	wout(where, "A speech by %s has raised %s.",
+		e.box_name(c.who), e.liner_desc(mob))
+
+	return TRUE
+}
+
+// v_rally starts rallying a peasant mob to who's side.
+// Ported from src/swear.c lines 887-906.
+func (e *Engine) v_rally(c *command) int {
+	mob := c.a
+
+	if !e.check_skill(c.who, sk_rally_mob) {
+		return FALSE
+	}
+
+	if !e.check_char_here(c.who, mob) {
+		return FALSE
+	}
+
+	if e.noble_item(mob) != item_peasant &&
+		e.noble_item(mob) != item_angry_peasant {
+		wout(c.who, "%s is not a peasant mob.", e.box_name(mob))
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_rally stacks a loose mob under who for three months, or adds three
+// months, up to five, to a mob that already follows a leader.
+// Ported from src/swear.c lines 909-952.
+func (e *Engine) d_rally(c *command) int {
+	mob := c.a
+
+	if !e.check_char_gone(c.who, mob) {
+		return FALSE
+	}
+
+	if e.noble_item(mob) != item_peasant &&
+		e.noble_item(mob) != item_angry_peasant {
+		wout(c.who, "%s is not a peasant mob.", e.box_name(mob))
+		return FALSE
+	}
+
+	e.add_skill_experience(c.who, sk_rally_mob)
+
+	if n := e.stack_parent(mob); n != 0 {
+		e.set_loyal(mob, LOY_summon, min(e.loyal_rate(mob)+3, 5))
+
+		wout(c.who, "Renewed enthusiasm of %s for %s.",
+			e.box_name(mob), e.box_name(n))
+
+		wout(c.who, "The peasants will stay spirited for %d months.",
+			e.loyal_rate(mob))
+
+		return TRUE
+	}
+
+	e.join_stack(mob, c.who)
+	e.set_loyal(mob, LOY_summon, 3)
+
+	// auto_mob() may have queued some orders, with a preceeding wait.
+	// Get rid of them now that the mob is LOY_summon.
+	e.flush_unit_orders(e.player(mob), mob)
+	e.interrupt_order(mob)
+
+	return FALSE
+}
+
+// v_incite starts urging a loose peasant mob to attack target.
+// Ported from src/swear.c lines 955-988.
+func (e *Engine) v_incite(c *command) int {
+	mob := c.a
+	target := c.b
+
+	if !e.check_skill(c.who, sk_incite_mob) {
+		return FALSE
+	}
+
+	if !e.check_char_here(c.who, mob) {
+		return FALSE
+	}
+
+	if !e.valid_box(target) || e.subloc(target) != e.subloc(c.who) {
+		wout(c.who, "%s is not here.", e.box_code(target))
+		return FALSE
+	}
+
+	if e.noble_item(mob) != item_peasant &&
+		e.noble_item(mob) != item_angry_peasant {
+		wout(c.who, "%s is not a peasant mob.", e.box_name(mob))
+		return FALSE
+	}
+
+	if e.stack_parent(mob) != 0 {
+		wout(c.who, "%s is stacked under a leader.", e.box_name(mob))
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_incite has even odds of sending the mob after target. Inns here
+// may hear rumors of the attempt either way.
+// Ported from src/swear.c lines 991-1054.
+func (e *Engine) d_incite(c *command) int {
+	mob := c.a
+	target := c.b
+	where := e.subloc(c.who)
+
+	if !e.check_char_gone(c.who, mob) {
+		return FALSE
+	}
+
+	if e.noble_item(mob) != item_peasant &&
+		e.noble_item(mob) != item_angry_peasant {
+		wout(c.who, "%s is not a peasant mob.", e.box_name(mob))
+		return FALSE
+	}
+
+	if e.subloc(target) != where {
+		wout(c.who, "%s is no longer here.", e.box_name(target))
+		return FALSE
+	}
+
+	if e.stack_parent(mob) != 0 {
+		wout(c.who, "%s is stacked under a leader.", e.box_name(mob))
+		return FALSE
+	}
+
+	e.add_skill_experience(c.who, sk_incite_mob)
+
+	if e.rndFrom(streamSkills, 1, 3) == 1 {
+		if p := e.rp_loc_info(where); p != nil {
+			for _, i := range p.here_list {
+				if e.kind(i) != T_loc || e.subkind(i) != sub_inn {
+					continue
+				}
+
+				wout(i, "Rumors claim that %s is trying to incite "+
+					"a mob to attack %s.",
+					e.box_name(c.who), e.box_name(target))
+			}
+		}
+	}
+
+	if e.rndFrom(streamSkills, 1, 2) == 1 {
+		wout(c.who, "Failed to incite the mob to violence.")
+		return FALSE
+	}
+
+	e.flush_unit_orders(e.player(mob), mob)
+	e.interrupt_order(mob)
+	e.queue(mob, "attack %s", box_code_less(target))
+	e.init_load_sup(mob) // make ready to execute commands immediately
+
+	wout(c.who, "%s will attack %s!", e.box_name(mob), e.box_name(target))
+
+	return TRUE
+}
+
+// v_persuade_oath starts talking a noble out of their oath.
+// Ported from src/swear.c lines 1057-1079.
+func (e *Engine) v_persuade_oath(c *command) int {
+	target := c.a
+
+	if !e.check_char_here(c.who, target) {
+		return FALSE
+	}
+
+	if e.char_new_lord(target) {
+		wout(c.who, "%s just switched employers this month, and is "+
+			"not looking for a new one so soon.", e.box_name(target))
+		return FALSE
+	}
+
+	if !e.can_pay(c.who, 25) {
+		wout(c.who, "Don't have %s.", gold_s(25))
+		return FALSE
+	}
+
+	return TRUE
+}
+
+// d_persuade_oath spends 25 gold for a 2% chance to win over a noble
+// sworn at oath level 1.
+// Ported from src/swear.c lines 1082-1131.
+func (e *Engine) d_persuade_oath(c *command) int {
+	target := c.a
+	flag := c.b
+
+	if !e.check_still_here(c.who, target) {
+		return FALSE
+	}
+
+	if e.char_new_lord(target) {
+		wout(c.who, "%s just switched employers this month, and is "+
+			"not looking for a new one so soon.", e.box_name(target))
+		return FALSE
+	}
+
+	if e.loyal_kind(target) != LOY_oath {
+		wout(c.who, "%s does not have oath loyalty.", e.box_name(target))
+		return FALSE
+	}
+
+	if !e.charge(c.who, 25) {
+		wout(c.who, "Don't have %s.", gold_s(25))
+		return FALSE
+	}
+
+	if e.loyal_rate(target) != 1 || e.rndFrom(streamSkills, 1, 100) > 2 {
+		wout(c.who, "Failed to convince %s to join us.", e.box_name(target))
+		return TRUE
+	}
+
+	if !e.enough_np_to_acquire(c.who, target) {
+		return FALSE
+	}
+
+	wout(c.who, "%s has been convinced to join us!", e.box_name(target))
+
+	e.unit_deserts(target, e.player(c.who), true, LOY_UNCHANGED, 0)
+	e.p_char(target).fresh_hire = TRUE
+
+	if flag != 0 {
+		e.join_stack(target, c.who)
+	}
+
+	return TRUE
+}
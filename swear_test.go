@@ -0,0 +1,154 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// swear_test.go - Tests for bribery, peasant mobs and oath persuasion
+
+package taygete
+
+import "testing"
+
+func setupSwearTest(t *testing.T) (who, target int) {
+	t.Helper()
+	who = setupBasicTest(t, 0, 0)
+	where := teg.subloc(who)
+
+	teg.alloc_box(item_gold, T_item, 0)
+	teg.alloc_box(item_peasant, T_item, 0)
+	teg.alloc_box(501, T_player, 0)
+	teg.alloc_box(502, T_player, 0)
+	teg.p_char(who).unit_lord = 501
+	teg.set_loyal(who, LOY_oath, 1)
+
+	target = 1002
+	teg.alloc_box(target, T_char, 0)
+	teg.set_where(target, where)
+	teg.p_char(target).unit_lord = 502
+	teg.set_loyal(target, LOY_contract, 100)
+	return who, target
+}
+
+func TestBribeChecks(t *testing.T) {
+	who, target := setupSwearTest(t)
+
+	c := &command{who: who, a: target, b: 300}
+	if got := teg.v_bribe(c); got != FALSE {
+		t.Errorf("v_bribe without the skill = %d, want FALSE", got)
+	}
+
+	teg.p_skill_ent(who, sk_bribe_noble).know = SKILL_know
+	if got := teg.v_bribe(c); got != FALSE {
+		t.Errorf("v_bribe without gold = %d, want FALSE", got)
+	}
+
+	teg.gen_item(who, item_gold, 300)
+	if got := teg.v_bribe(c); got != TRUE {
+		t.Fatalf("v_bribe = %d, want TRUE", got)
+	}
+
+	teg.p_char(target).new_lord = 1
+	if got := teg.v_bribe(c); got != FALSE {
+		t.Errorf("v_bribe of a fresh hire = %d, want FALSE", got)
+	}
+	teg.p_char(target).new_lord = 0
+
+	teg.p_char(target).unit_lord = 501
+	if got := teg.v_bribe(c); got != FALSE {
+		t.Errorf("v_bribe of our own noble = %d, want FALSE", got)
+	}
+}
+
+func TestBribeUnderThreshold(t *testing.T) {
+	who, target := setupSwearTest(t)
+	teg.gen_item(who, item_gold, 1000)
+
+	// under the 250 gold floor the noble never switches; the gold is
+	// either pocketed or handed back
+	for range 20 {
+		before := teg.has_item(who, item_gold)
+		c := &command{who: who, a: target, b: 100}
+		if got := teg.d_bribe(c); got != TRUE {
+			t.Fatalf("d_bribe = %d, want TRUE", got)
+		}
+		if got := teg.player(target); got != 502 {
+			t.Fatalf("target switched to %d on a small bribe", got)
+		}
+		if after := teg.has_item(who, item_gold); after != before && after != before-100 {
+			t.Fatalf("gold went from %d to %d, want unchanged or -100", before, after)
+		}
+	}
+}
+
+func TestRallyMob(t *testing.T) {
+	who, _ := setupSwearTest(t)
+	teg.p_skill_ent(who, sk_rally_mob).know = SKILL_know
+
+	mob := 1003
+	teg.alloc_box(mob, T_char, 0)
+	teg.set_where(mob, teg.subloc(who))
+	teg.p_char(mob).unit_item = item_peasant
+	teg.p_char(mob).unit_lord = 502
+	teg.set_loyal(mob, LOY_npc, 0)
+
+	c := &command{who: who, a: mob}
+	if got := teg.v_rally(c); got != TRUE {
+		t.Fatalf("v_rally = %d, want TRUE", got)
+	}
+	teg.d_rally(c)
+	if got := teg.stack_parent(mob); got != who {
+		t.Errorf("mob stacked under %d, want %d", got, who)
+	}
+	if teg.loyal_kind(mob) != LOY_summon || teg.loyal_rate(mob) != 3 {
+		t.Errorf("mob loyalty = %d/%d, want summon/3", teg.loyal_kind(mob), teg.loyal_rate(mob))
+	}
+
+	// rallying a stacked mob adds three months, up to five
+	if got := teg.d_rally(c); got != TRUE {
+		t.Fatalf("second d_rally = %d, want TRUE", got)
+	}
+	if got := teg.loyal_rate(mob); got != 5 {
+		t.Errorf("mob spirited for %d months, want 5", got)
+	}
+
+	teg.p_char(mob).unit_item = item_gold
+	if got := teg.v_rally(c); got != FALSE {
+		t.Errorf("v_rally of a non-mob = %d, want FALSE", got)
+	}
+}
+
+func TestPersuadeOath(t *testing.T) {
+	who, target := setupSwearTest(t)
+	teg.gen_item(who, item_gold, 25)
+
+	c := &command{who: who, a: target}
+	if got := teg.d_persuade_oath(c); got != FALSE {
+		t.Errorf("d_persuade_oath of a contract noble = %d, want FALSE", got)
+	}
+	if got := teg.has_item(who, item_gold); got != 25 {
+		t.Errorf("gold = %d, want 25 after a refused attempt", got)
+	}
+
+	// above oath level 1 the attempt always fails but still costs
+	teg.set_loyal(target, LOY_oath, 2)
+	if got := teg.d_persuade_oath(c); got != TRUE {
+		t.Fatalf("d_persuade_oath = %d, want TRUE", got)
+	}
+	if got := teg.player(target); got != 502 {
+		t.Errorf("target switched to %d, want 502", got)
+	}
+	if got := teg.has_item(who, item_gold); got != 0 {
+		t.Errorf("gold = %d, want 0", got)
+	}
+}
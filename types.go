@@ -82,20 +82,21 @@ type box struct {
 }
 
 type entity_player struct {
-	full_name       *char
-	email           *char
-	vis_email       *char /* address to put in player list */
-	last_email      *char /* where did the last orders come from? */
-	password        *char
+	full_name       string
+	email           string
+	vis_email       string /* address to put in player list */
+	last_email      string /* where did the last orders come from? */
+	password        string
+	account_id      int          /* accounts row, 0 for none */
 	first_turn      int          /* which turn was their first? */
 	last_order_turn int          /* last turn orders were submitted */
 	orders          **order_list /* ilist of orders for units in */
 	/* this faction */
 	known sparse /* visited, lore seen, encountered */
 
-	units    IList   /* what units are in our faction? */
-	admits   **admit /* admit permissions list */
-	unformed IList   /* nobles as yet unformed */
+	units    IList    /* what units are in our faction? */
+	admits   []*admit /* admit permissions list */
+	unformed IList    /* nobles as yet unformed */
 
 	split_lines int   /* split mail at this many lines */
 	split_bytes int   /* split mail at this many bytes */
@@ -189,10 +190,10 @@ type char_magic struct {
 	cur_aura  int /* current aura level for magician */
 	auraculum int /* char created an auraculum */
 
-	visions sparse /* visions revealed */
-	pledge  int    /* lands are pledged to another */
-	token   int    /* we are controlled by this art */
-	fee     int    /* gold/100 wt. to board this ship */
+	visions map[int]bool /* visions revealed */
+	pledge  int          /* lands are pledged to another */
+	token   int          /* we are controlled by this art */
+	fee     int          /* gold/100 wt. to board this ship */
 
 	project_cast   int   /* project next cast */
 	quick_cast     short /* speed next cast */
@@ -235,7 +236,7 @@ type item_ent struct {
 type entity_loc struct {
 	prov_dest      []int /* province destinations */
 	shroud         short /* magical scry shroud */
-	barrier        short /* magical barrier */
+	barrier        int   /* magical barrier; -caster when permanent */
 	civ            schar /* civilization level (0 = wild) */
 	hidden         schar /* is location hidden? */
 	dist_from_gate schar
@@ -324,8 +325,8 @@ type item_magic struct {
 	defense_bonus schar /* defense bonus */
 	missile_bonus schar /* missile bonus */
 
-	token_num    schar /* how many token controlled units */
-	orb_use_ount schar /* how many uses left in the orb */
+	token_num     schar /* how many token controlled units */
+	orb_use_count schar /* how many uses left in the orb */
 
 	/* not saved: */
 
@@ -339,8 +340,8 @@ type entity_skill struct {
 	offered        []int /* skills learnable after this one (refactored from ilist) */
 	research       []int /* skills researchable with this one (refactored from ilist) */
 
-	req      **req_ent /* ilist of items required for use or cast */
-	produced int       /* simple production skill result */
+	req      []*req_ent /* items required for use or cast */
+	produced int        /* simple production skill result */
 
 	no_exp int /* this skill not rated for experience */
 
@@ -365,17 +366,17 @@ type entity_gate struct {
 }
 
 type entity_misc struct {
-	display     *char  /* entity display banner */
-	npc_created int    /* turn peasant mob created */
-	npc_home    int    /* where npc was created */
-	npc_cookie  int    /* allocation cookie item for us */
-	summoned_by int    /* who summoned us? */
-	save_name   *char  /* orig name of noble for dead bodies */
-	old_lord    int    /* who did this dead body used to belong to */
-	npc_memory  sparse /* npc memory */
-	only_vuln   int    /* only defeatable with this rare artifact */
-	garr_castle int    /* castle which owns this garrison */
-	bind_storm  int    /* storm bound to this ship */
+	display     *char        /* entity display banner */
+	npc_created int          /* turn peasant mob created */
+	npc_home    int          /* where npc was created */
+	npc_cookie  int          /* allocation cookie item for us */
+	summoned_by int          /* who summoned us? */
+	save_name   *char        /* orig name of noble for dead bodies */
+	old_lord    int          /* who did this dead body used to belong to */
+	npc_memory  map[int]bool /* npc memory */
+	only_vuln   int          /* only defeatable with this rare artifact */
+	garr_castle int          /* castle which owns this garrison */
+	bind_storm  int          /* storm bound to this ship */
 
 	storm_str  short /* storm strength */
 	npc_dir    schar /* last direction npc moved */
@@ -419,9 +420,8 @@ type command struct {
 	g int
 	h int
 
-	line        *char  /* original command line */
-	parsed_line *char  /* cut-up line, pointed to by parse */
-	parse       **char /* ilist of parsed arguments */
+	line  string   /* original command line */
+	parse []string /* parsed arguments, parse[0] is the command name */
 
 	state          schar /* STATE_LOAD, STATE_RUN, STATE_ERROR, STATE_DONE */
 	status         schar /* success or failure */
@@ -438,11 +438,11 @@ type command struct {
 	debug       schar      /* debugging check -- not saved */
 }
 
-type commandFunction func(*command) int
+type commandFunction func(*Engine, *command) int
 
 type cmd_tbl_ent struct {
-	allow *char /* who may execute the command */
-	name  *char /* name of command */
+	allow string /* who may execute the command */
+	name  string /* name of command */
 
 	start     commandFunction /* initiator */
 	finish    commandFunction /* conclusion */
@@ -0,0 +1,875 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// use.go - Skill use dispatch ported from src/use.c
+//
+// The USE order looks the skill up in use_tbl, checks that the
+// character may use it (known skill, artifact or scroll), applies
+// experience speedups and item requirements, then hands off to the
+// skill's own start/finish/interrupt routines. Skills without a
+// start routine are simple production skills.
+
+package taygete
+
+type use_tbl_ent struct {
+	allow string /* who may execute the command */
+	skill int
+
+	start     commandFunction /* initiator */
+	finish    commandFunction /* conclusion */
+	interrupt commandFunction /* interrupted order */
+
+	time int /* how long command takes */
+	poll int /* call finish each day, not just at end */
+}
+
+// use_tbl maps skills to the routines that implement their use.
+// Skills with no routine of their own carry nil handlers, as in C.
+// find sell and find buy stay nil until the city trade lists from
+// src/buy.c are ported.
+// Filled in by init() to avoid an initialization cycle through v_use.
+var use_tbl []use_tbl_ent
+
+func init() {
+	use_tbl = []use_tbl_ent{
+		{},
+
+		// allow, skill, start, finish, intr, time, poll
+		{"c", sk_meditate, (*Engine).v_meditate, (*Engine).d_meditate, nil, 7, 0},
+		{"c", sk_detect_gates, (*Engine).v_detect_gates, (*Engine).d_detect_gates, nil, 7, 0},
+		{"c", sk_jump_gate, (*Engine).v_jump_gate, nil, nil, 1, 0},
+		{"c", sk_teleport, (*Engine).v_teleport, nil, nil, 1, 0},
+		{"c", sk_seal_gate, (*Engine).v_seal_gate, (*Engine).d_seal_gate, nil, 7, 0},
+		{"c", sk_unseal_gate, (*Engine).v_unseal_gate, (*Engine).d_unseal_gate, nil, 7, 0},
+		{"c", sk_notify_unseal, (*Engine).v_notify_unseal, (*Engine).d_notify_unseal, nil, 7, 0},
+		{"c", sk_rem_seal, (*Engine).v_rem_seal, (*Engine).d_rem_seal, nil, 7, 0},
+		{"c", sk_reveal_key, (*Engine).v_reveal_key, (*Engine).d_reveal_key, nil, 7, 0},
+		{"c", sk_notify_jump, (*Engine).v_notify_jump, (*Engine).d_notify_jump, nil, 7, 0},
+		{"c", sk_heal, (*Engine).v_heal, (*Engine).d_heal, nil, 7, 0},
+		{"c", sk_rev_jump, (*Engine).v_reverse_jump, nil, nil, 1, 0},
+		{"c", sk_reveal_mage, (*Engine).v_reveal_mage, (*Engine).d_reveal_mage, nil, 7, 0},
+		{"c", sk_view_aura, (*Engine).v_view_aura, (*Engine).d_view_aura, nil, 7, 0},
+		{"c", sk_shroud_abil, (*Engine).v_shroud_abil, (*Engine).d_shroud_abil, nil, 3, 0},
+		{"c", sk_detect_abil, (*Engine).v_detect_abil, (*Engine).d_detect_abil, nil, 7, 0},
+		{"c", sk_scry_region, (*Engine).v_scry_region, (*Engine).d_scry_region, nil, 7, 0},
+		{"c", sk_shroud_region, (*Engine).v_shroud_region, (*Engine).d_shroud_region, nil, 3, 0},
+		{"c", sk_detect_scry, (*Engine).v_detect_scry, (*Engine).d_detect_scry, nil, 7, 0},
+		{"c", sk_dispel_region, (*Engine).v_dispel_region, (*Engine).d_dispel_region, nil, 3, 0},
+		{"c", sk_dispel_abil, (*Engine).v_dispel_abil, (*Engine).d_dispel_abil, nil, 3, 0},
+		{"c", sk_adv_med, (*Engine).v_adv_med, (*Engine).d_adv_med, nil, 7, 0},
+		{"c", sk_hinder_med, (*Engine).v_hinder_med, (*Engine).d_hinder_med, nil, 10, 0},
+		{"c", sk_proj_cast, (*Engine).v_proj_cast, (*Engine).d_proj_cast, nil, 7, 0},
+		{"c", sk_locate_char, (*Engine).v_locate_char, (*Engine).d_locate_char, nil, 10, 0},
+		{"c", sk_bar_loc, (*Engine).v_bar_loc, (*Engine).d_bar_loc, nil, 10, 0},
+		{"c", sk_unbar_loc, (*Engine).v_unbar_loc, (*Engine).d_unbar_loc, nil, 7, 0},
+		{"c", sk_forge_palantir, (*Engine).v_forge_palantir, (*Engine).d_forge_palantir, nil, 10, 0},
+		{"c", sk_destroy_art, (*Engine).v_destroy_art, (*Engine).d_destroy_art, nil, 7, 0},
+		{"c", sk_show_art_creat, (*Engine).v_show_art_creat, (*Engine).d_show_art_creat, nil, 7, 0},
+		{"c", sk_show_art_reg, (*Engine).v_show_art_reg, (*Engine).d_show_art_reg, nil, 7, 0},
+		{"c", sk_save_proj, (*Engine).v_save_proj, (*Engine).d_save_proj, nil, 7, 0},
+		{"c", sk_save_quick, (*Engine).v_save_quick, (*Engine).d_save_quick, nil, 7, 0},
+		{"c", sk_quick_cast, (*Engine).v_quick_cast, (*Engine).d_quick_cast, nil, 4, 0},
+		{"c", sk_rem_art_cloak, (*Engine).v_rem_art_cloak, (*Engine).d_rem_art_cloak, nil, 10, 0},
+		{"c", sk_write_basic, (*Engine).v_write_spell, (*Engine).d_write_spell, nil, 7, 0},
+		{"c", sk_write_weather, (*Engine).v_write_spell, (*Engine).d_write_spell, nil, 7, 0},
+		{"c", sk_write_scry, (*Engine).v_write_spell, (*Engine).d_write_spell, nil, 7, 0},
+		{"c", sk_write_gate, (*Engine).v_write_spell, (*Engine).d_write_spell, nil, 7, 0},
+		{"c", sk_write_art, (*Engine).v_write_spell, (*Engine).d_write_spell, nil, 7, 0},
+		{"c", sk_write_necro, (*Engine).v_write_spell, (*Engine).d_write_spell, nil, 7, 0},
+		{"c", sk_cloak_creat, (*Engine).v_cloak_creat, (*Engine).d_cloak_creat, nil, 7, 0},
+		{"c", sk_cloak_reg, (*Engine).v_cloak_reg, (*Engine).d_cloak_reg, nil, 7, 0},
+		{"c", sk_curse_noncreat, (*Engine).v_curse_noncreat, (*Engine).d_curse_noncreat, nil, 14, 0},
+		{"c", sk_forge_aura, (*Engine).v_forge_aura, (*Engine).d_forge_aura, nil, 14, 0},
+		{"c", sk_shipbuilding, (*Engine).v_shipbuild, nil, nil, 0, 0},
+		{"c", sk_pilot_ship, (*Engine).v_sail, (*Engine).d_sail, (*Engine).i_sail, -1, 0},
+		{"c", sk_train_wild, (*Engine).v_use_train_riding, nil, nil, 7, 0},
+		{"c", sk_train_warmount, (*Engine).v_use_train_war, nil, nil, 14, 0},
+		{"c", sk_make_ram, nil, nil, nil, 14, 0},
+		{"c", sk_make_catapult, nil, nil, nil, 14, 0},
+		{"c", sk_make_siege, nil, nil, nil, 14, 0},
+		{"c", sk_brew_slave, (*Engine).v_brew, (*Engine).d_brew_slave, nil, 7, 0},
+		{"c", sk_brew_heal, (*Engine).v_brew, (*Engine).d_brew_heal, nil, 7, 0},
+		{"c", sk_brew_death, (*Engine).v_brew, (*Engine).d_brew_death, nil, 10, 0},
+		{"c", sk_mine_iron, (*Engine).v_mine_iron, (*Engine).d_mine_iron, nil, 7, 0},
+		{"c", sk_mine_gold, (*Engine).v_mine_gold, (*Engine).d_mine_gold, nil, 7, 0},
+		{"c", sk_mine_mithril, (*Engine).v_mine_mithril, (*Engine).d_mine_mithril, nil, 7, 0},
+		{"c", sk_quarry_stone, (*Engine).v_quarry, nil, nil, -1, 1},
+		{"c", sk_catch_horse, (*Engine).v_catch, nil, nil, -1, 1},
+		{"c", sk_extract_venom, nil, nil, nil, 7, 0},
+		{"c", sk_harvest_lumber, (*Engine).v_wood, nil, nil, -1, 1},
+		{"c", sk_harvest_yew, (*Engine).v_yew, nil, nil, -1, 1},
+		{"c", sk_add_ram, (*Engine).v_add_ram, (*Engine).d_add_ram, nil, 10, 0},
+		{"c", sk_spy_inv, (*Engine).v_spy_inv, (*Engine).d_spy_inv, nil, 7, 0},
+		{"c", sk_spy_skills, (*Engine).v_spy_skills, (*Engine).d_spy_skills, nil, 7, 0},
+		{"c", sk_spy_lord, (*Engine).v_spy_lord, (*Engine).d_spy_lord, nil, 7, 0},
+		{"c", sk_record_skill, (*Engine).v_write_spell, (*Engine).d_write_spell, nil, 7, 0},
+		{"c", sk_bribe_noble, (*Engine).v_bribe, (*Engine).d_bribe, nil, 7, 0},
+		{"c", sk_summon_savage, (*Engine).v_summon_savage, nil, nil, 1, 0},
+		{"c", sk_keep_savage, (*Engine).v_keep_savage, (*Engine).d_keep_savage, nil, 7, 0},
+		{"c", sk_improve_opium, (*Engine).v_improve_opium, (*Engine).d_improve_opium, nil, 7, 0},
+		{"c", sk_raise_mob, (*Engine).v_raise, (*Engine).d_raise, nil, 7, 0},
+		{"c", sk_rally_mob, (*Engine).v_rally, (*Engine).d_rally, nil, 7, 0},
+		{"c", sk_incite_mob, (*Engine).v_incite, (*Engine).d_incite, nil, 7, 0},
+		{"c", sk_bird_spy, (*Engine).v_bird_spy, (*Engine).d_bird_spy, nil, 3, 0},
+		{"c", sk_lead_to_gold, (*Engine).v_lead_to_gold, (*Engine).d_lead_to_gold, nil, 7, 0},
+		{"c", sk_raise_corpses, (*Engine).v_raise_corpses, nil, nil, -1, 1},
+		{"c", sk_undead_lord, (*Engine).v_undead_lord, (*Engine).d_undead_lord, nil, 7, 0},
+		{"c", sk_banish_undead, (*Engine).v_banish_undead, (*Engine).d_banish_undead, nil, 7, 0},
+		{"c", sk_renew_undead, (*Engine).v_keep_undead, (*Engine).d_keep_undead, nil, 7, 0},
+		{"c", sk_eat_dead, (*Engine).v_eat_dead, (*Engine).d_eat_dead, nil, 14, 0},
+		{"c", sk_aura_blast, (*Engine).v_aura_blast, (*Engine).d_aura_blast, nil, 1, 0},
+		{"c", sk_absorb_blast, (*Engine).v_aura_reflect, nil, nil, 0, 0},
+		{"c", sk_summon_rain, (*Engine).v_summon_rain, (*Engine).d_summon_rain, nil, 7, 0},
+		{"c", sk_summon_wind, (*Engine).v_summon_wind, (*Engine).d_summon_wind, nil, 7, 0},
+		{"c", sk_summon_fog, (*Engine).v_summon_fog, (*Engine).d_summon_fog, nil, 7, 0},
+		{"c", sk_direct_storm, (*Engine).v_direct_storm, nil, nil, 1, 0},
+		{"c", sk_renew_storm, (*Engine).v_renew_storm, (*Engine).d_renew_storm, nil, 3, 0},
+		{"c", sk_dissipate, (*Engine).v_dissipate, (*Engine).d_dissipate, nil, 7, 0},
+		{"c", sk_lightning, (*Engine).v_lightning, (*Engine).d_lightning, nil, 7, 0},
+		{"c", sk_fierce_wind, (*Engine).v_fierce_wind, (*Engine).d_fierce_wind, nil, 7, 0},
+		{"c", sk_seize_storm, (*Engine).v_seize_storm, (*Engine).d_seize_storm, nil, 7, 0},
+		{"c", sk_death_fog, (*Engine).v_death_fog, (*Engine).d_death_fog, nil, 7, 0},
+		{"c", sk_banish_corpses, (*Engine).v_banish_corpses, (*Engine).d_banish_corpses, nil, 7, 0},
+		{"c", sk_hide_self, (*Engine).v_hide, (*Engine).d_hide, nil, 3, 0},
+		{"c", sk_sneak_build, (*Engine).v_sneak, (*Engine).d_sneak, nil, 3, 0},
+		{"c", sk_mage_menial, (*Engine).v_mage_menial, nil, nil, -1, 1},
+		{"c", sk_petty_thief, (*Engine).v_petty_thief, (*Engine).d_petty_thief, nil, 7, 0},
+		{"c", sk_appear_common, (*Engine).v_appear_common, nil, nil, 1, 0},
+		{"c", sk_defense, (*Engine).v_defense, (*Engine).d_defense, nil, 7, 0},
+		{"c", sk_archery, (*Engine).v_archery, (*Engine).d_archery, nil, 7, 0},
+		{"c", sk_swordplay, (*Engine).v_swordplay, (*Engine).d_swordplay, nil, 7, 0},
+		{"c", sk_reveal_vision, (*Engine).v_reveal_vision, (*Engine).d_reveal_vision, nil, 10, 0},
+		{"c", sk_resurrect, (*Engine).v_resurrect, (*Engine).d_resurrect, nil, 10, 0},
+		{"c", sk_pray, (*Engine).v_prep_ritual, (*Engine).d_prep_ritual, nil, 3, 0},
+		{"c", sk_last_rites, (*Engine).v_last_rites, (*Engine).d_last_rites, nil, 10, 0},
+		{"c", sk_remove_bless, (*Engine).v_remove_bless, (*Engine).d_remove_bless, nil, 10, 0},
+		{"c", sk_vision_protect, (*Engine).v_vision_protect, (*Engine).d_vision_protect, nil, 10, 0},
+		{"c", sk_find_rich, (*Engine).v_find_rich, (*Engine).d_find_rich, nil, 7, 0},
+		{"c", sk_harvest_opium, (*Engine).v_implicit, nil, nil, 0, 0},
+		{"c", sk_train_angry, (*Engine).v_implicit, nil, nil, 0, 0},
+		{"c", sk_weaponsmith, (*Engine).v_implicit, nil, nil, 0, 0},
+		{"c", sk_hide_lord, (*Engine).v_implicit, nil, nil, 0, 0},
+		{"c", sk_transcend_death, (*Engine).v_implicit, nil, nil, 0, 0},
+		{"c", sk_collect_foliage, (*Engine).v_implicit, nil, nil, 0, 0},
+		{"c", sk_fishing, (*Engine).v_fish, nil, nil, 0, 0},
+		{"c", sk_summon_ghost, (*Engine).v_implicit, nil, nil, 0, 0},
+		{"c", sk_capture_beasts, (*Engine).v_implicit, nil, nil, 0, 0},
+		{"c", sk_use_beasts, (*Engine).v_implicit, nil, nil, 0, 0},
+		{"c", sk_collect_elem, (*Engine).v_implicit, nil, nil, 0, 0},
+		{"c", sk_torture, (*Engine).v_torture, (*Engine).d_torture, nil, 7, 0},
+		{"c", sk_fight_to_death, (*Engine).v_fight_to_death, nil, nil, 0, 0},
+		{"c", sk_breed_beasts, (*Engine).v_breed, (*Engine).d_breed, nil, 7, 0},
+		{"c", sk_breed_hound, (*Engine).v_breed_hound, (*Engine).d_breed_hound, nil, 28, 0},
+		{"c", sk_persuade_oath, (*Engine).v_persuade_oath, (*Engine).d_persuade_oath, nil, 7, 0},
+		{"c", sk_forge_weapon, (*Engine).v_forge_art_x, (*Engine).d_forge_art_x, nil, 7, 0},
+		{"c", sk_forge_armor, (*Engine).v_forge_art_x, (*Engine).d_forge_art_x, nil, 7, 0},
+		{"c", sk_forge_bow, (*Engine).v_forge_art_x, (*Engine).d_forge_art_x, nil, 7, 0},
+		{"c", sk_trance, (*Engine).v_trance, (*Engine).d_trance, nil, 28, 0},
+		{"c", sk_teleport_item, (*Engine).v_teleport_item, (*Engine).d_teleport_item, nil, 3, 0},
+		{"c", sk_tap_health, (*Engine).v_tap_health, (*Engine).d_tap_health, nil, 7, 0},
+		{"c", sk_bind_storm, (*Engine).v_bind_storm, (*Engine).d_bind_storm, nil, 7, 0},
+		{"c", sk_find_sell, nil, nil, nil, 21, 0},
+		{"c", sk_find_buy, nil, nil, nil, 14, 0},
+	}
+}
+
+// v_implicit is the use routine for skills that are applied automatically.
+// Ported from src/use.c lines 246-253.
+func (e *Engine) v_implicit(c *command) int {
+	wout(c.who, "Use of this skill is automatic when appropriate.")
+	wout(c.who, "No direct USE function exists.")
+	return FALSE
+}
+
+// v_shipbuild redirects shipbuilding to the BUILD order.
+// Ported from src/use.c lines 256-262.
+func (e *Engine) v_shipbuild(c *command) int {
+	wout(c.who, "Use the BUILD order to build ships.")
+	return FALSE
+}
+
+// find_use_entry returns the use_tbl index for a skill, or -1.
+// Ported from src/use.c lines 265-275.
+func find_use_entry(skill int) int {
+	for i := 1; i < len(use_tbl); i++ {
+		if use_tbl[i].skill == skill {
+			return i
+		}
+	}
+	return -1
+}
+
+// may_use_skill returns what lets who use sk: the skill itself if it
+// is known, otherwise an artifact granting it, otherwise a one-shot
+// scroll granting it. Returns 0 if nothing does.
+// Ported from src/use.c lines 288-325.
+func (e *Engine) may_use_skill(who, sk int) int {
+	if e.has_skill(who, sk) {
+		return sk
+	}
+
+	// Items other than scrolls take precedence, to preserve the
+	// one-shot scrolls.
+	ret, scroll := 0, 0
+	for _, it := range e.globals.inventories[who] {
+		p := e.rp_item_magic(it.item)
+		if p != nil && p.may_use.Lookup(sk) >= 0 {
+			if e.subkind(it.item) == sub_scroll {
+				scroll = it.item
+			} else {
+				ret = it.item
+			}
+		}
+	}
+
+	if ret != 0 {
+		return ret
+	}
+	return scroll
+}
+
+// magically_speed_casting applies any stored quick cast to a spell.
+// Ported from src/use.c lines 328-359.
+func (e *Engine) magically_speed_casting(c *command, sk int) {
+	if !e.magic_skill(sk) || e.char_quick_cast(c.who) == 0 || sk == sk_save_quick || sk == sk_trance {
+		return
+	}
+
+	p := e.p_magic(c.who)
+
+	var n int // amount speeded by
+	if c.wait == 0 {
+		n = 0
+	} else if int(p.quick_cast) < c.wait {
+		n = int(p.quick_cast)
+		c.wait -= int(p.quick_cast)
+		p.quick_cast = 0
+	} else {
+		n = c.wait - 1
+		p.quick_cast = 0
+		c.wait = 1
+	}
+
+	wout(c.who, "(speeded cast by %d day%s)", n, add_s(n))
+}
+
+// correct_use_item maps USE of a scroll or book onto the first spell
+// it grants, since the spell number is what should have been given.
+// Ported from src/use.c lines 369-384.
+func (e *Engine) correct_use_item(c *command) int {
+	item := c.a
+
+	if e.item_use_key(item) != 0 {
+		return item
+	}
+
+	p := e.rp_item_magic(item)
+	if p == nil || p.may_use.Len() < 1 {
+		return item
+	}
+
+	c.a = p.may_use.Values()[0]
+	return c.a
+}
+
+// meets_requirements checks that who holds the items a skill requires.
+// Consecutive REQ_OR entries are alternatives; the first one held counts.
+// Ported from src/use.c lines 387-432.
+func (e *Engine) meets_requirements(who, skill int) bool {
+	p := e.rp_skill(skill)
+	if p == nil {
+		return true
+	}
+
+	l := p.req
+	for i := 0; i < len(l); i++ {
+		for e.has_item(who, l[i].item) < l[i].qty && l[i].consume == REQ_OR {
+			i++
+			if i >= len(l) {
+				// a req list ended with REQ_OR instead of REQ_YES or REQ_NO
+				log_write(LOG_CODE, "meets_requirements: skill %d req list ends with REQ_OR", skill)
+				return false
+			}
+		}
+
+		if e.has_item(who, l[i].item) < l[i].qty {
+			wout(who, "%s does not have %s.", e.just_name(who), e.box_name_qty(l[i].item, l[i].qty))
+			return false
+		}
+
+		for i < len(l) && l[i].consume == REQ_OR {
+			i++
+		}
+	}
+
+	return true
+}
+
+// consume_requirements removes the items consumed by using a skill.
+// Ported from src/use.c lines 435-481.
+func (e *Engine) consume_requirements(who, skill int) {
+	p := e.rp_skill(skill)
+	if p == nil {
+		return
+	}
+
+	l := p.req
+	for i := 0; i < len(l); i++ {
+		for e.has_item(who, l[i].item) < l[i].qty && l[i].consume == REQ_OR {
+			i++
+			if i >= len(l) {
+				log_write(LOG_CODE, "consume_requirements: skill %d req list ends with REQ_OR", skill)
+				return
+			}
+		}
+
+		item, qty := l[i].item, l[i].qty
+
+		for i < len(l)-1 && l[i].consume == REQ_OR {
+			i++
+		}
+
+		if l[i].consume == REQ_YES {
+			e.consume_item(who, item, qty)
+		}
+	}
+}
+
+// consume_scroll destroys a one-shot scroll after it has been used.
+// Ported from src/use.c lines 484-497.
+func (e *Engine) consume_scroll(who, basis int) {
+	if e.subkind(basis) == sub_scroll {
+		wout(who, "%s vanishes.", e.box_name(basis))
+		e.destroy_unique_item(who, basis)
+	}
+}
+
+// experience_use_speedup shortens longer skill uses based on experience.
+// Ported from src/use.c lines 500-518.
+func experience_use_speedup(c *command) {
+	exp := max(c.use_exp-1, 0)
+
+	if exp != 0 && c.wait >= 7 {
+		if c.wait >= 14 {
+			c.wait -= exp
+		} else if c.wait >= 10 {
+			c.wait -= exp / 2
+		} else if exp >= 2 {
+			c.wait--
+		}
+	}
+}
+
+// v_use is the start routine for the USE order.
+// Ported from src/use.c lines 521-636.
+func (e *Engine) v_use(c *command) int {
+	sk := c.a
+
+	c.use_skill = sk
+
+	if !e.valid_box(sk) {
+		wout(c.who, "%s is not a valid skill to use.", get_parse_arg(c, 1))
+		return FALSE
+	}
+
+	if e.kind(sk) == T_item {
+		sk = e.correct_use_item(c)
+	}
+
+	if e.kind(sk) == T_item {
+		return e.v_use_item(c)
+	}
+
+	if e.kind(sk) != T_skill {
+		wout(c.who, "%s is not a valid skill to use.", get_parse_arg(c, 1))
+		return FALSE
+	}
+
+	parent := e.skill_school(sk)
+	if parent == sk {
+		wout(c.who, "Skill schools have no direct use.  Only subskills within a school may be used.")
+		return FALSE
+	}
+
+	basis := e.may_use_skill(c.who, sk)
+	if basis == 0 {
+		wout(c.who, "%s does not know %s.", e.just_name(c.who), e.box_code(sk))
+		return FALSE
+	}
+
+	// Checking may_use_skill rather than has_skill lets a category
+	// skill be used from an item.
+	if e.may_use_skill(c.who, parent) == 0 {
+		wout(c.who, "Knowledge of %s is first required before %s may be used.", e.box_name(parent), e.box_code(sk))
+		return FALSE
+	}
+
+	ent := find_use_entry(sk)
+	if ent <= 0 {
+		log_write(LOG_CODE, "v_use: no use table entry for %s", get_parse_arg(c, 1))
+		out(c.who, "Internal error.")
+		return FALSE
+	}
+
+	if e.magic_skill(sk) && e.in_safe_now(c.who) {
+		wout(c.who, "Magic may not be used in safe havens.")
+		return FALSE
+	}
+
+	e.cmd_shift(c)
+	c.use_ent = ent
+	c.use_skill = sk
+	c.use_exp = e.has_skill_level(c.who, sk)
+	c.poll = schar(use_tbl[ent].poll)
+	c.wait = use_tbl[ent].time
+	c.h = basis
+
+	experience_use_speedup(c)
+
+	if !e.meets_requirements(c.who, sk) {
+		return FALSE
+	}
+
+	if use_tbl[ent].start != nil {
+		ret := use_tbl[ent].start(e, c)
+		if ret != FALSE {
+			e.magically_speed_casting(c, sk)
+		}
+		return ret
+	}
+
+	if n := e.skill_produce(sk); n != 0 {
+		wout(c.who, "Work to produce one %s.", e.just_name(n))
+		return TRUE
+	}
+
+	// A skill with neither a start routine nor a product has not
+	// been ported yet.
+	log_write(LOG_CODE, "v_use: no use routine for %s", e.box_name(sk))
+	out(c.who, "Internal error.")
+	return FALSE
+}
+
+// add_skill_experience increments the experience for a skill, at
+// most once per month. Use through a scroll or book does not add
+// experience unless the character knows the skill.
+// Ported from src/use.c lines 643-666.
+func (e *Engine) add_skill_experience(who, sk int) {
+	p := e.rp_skill_ent(who, sk)
+	if p == nil {
+		return
+	}
+
+	if p.exp_this_month == FALSE {
+		p.experience++
+		p.exp_this_month = TRUE
+		e.markDirty(who)
+	}
+}
+
+// d_use is the finish routine for the USE order.
+// Ported from src/use.c lines 669-741.
+func (e *Engine) d_use(c *command) int {
+	sk := c.use_skill
+	ent := c.use_ent
+	basis := c.h
+
+	if e.kind(sk) == T_item {
+		return e.d_use_item(c)
+	}
+
+	// c.use_ent is not saved; if it is zero here, look it up again
+	// so that it survives turn boundaries.
+	if ent <= 0 {
+		ent = find_use_entry(sk)
+	}
+
+	if ent <= 0 {
+		log_write(LOG_CODE, "d_use: no use table entry for %s", get_parse_arg(c, 1))
+		out(c.who, "Internal error.")
+		return FALSE
+	}
+
+	// Don't call poll routine for ordinary delays
+	if c.wait > 0 && c.poll == 0 {
+		return TRUE
+	}
+
+	if c.poll == 0 && !e.meets_requirements(c.who, sk) {
+		return FALSE
+	}
+
+	// Maintain count of how many times each skill is used during
+	// a turn, for informational purposes only.
+	if sk != sk_breed_beasts { // taken care of in d_breed
+		e.p_skill(sk).use_count++
+	}
+	e.p_skill(sk).last_use_who = c.who
+
+	if use_tbl[ent].finish != nil {
+		ret := use_tbl[ent].finish(e, c)
+
+		if c.wait == 0 && ret != FALSE {
+			e.add_skill_experience(c.who, sk)
+		}
+
+		if ret != FALSE {
+			e.consume_scroll(c.who, basis)
+			e.consume_requirements(c.who, sk)
+		}
+
+		return ret
+	}
+
+	e.add_skill_experience(c.who, sk)
+
+	if n := e.skill_produce(sk); n != 0 {
+		e.gen_item(c.who, n, 1)
+		wout(c.who, "Produced one %s.", e.box_name(n))
+	}
+
+	e.consume_scroll(c.who, basis)
+	e.consume_requirements(c.who, sk)
+
+	return TRUE
+}
+
+// i_use is the interrupt routine for the USE order.
+// Ported from src/use.c lines 744-760.
+func (e *Engine) i_use(c *command) int {
+	ent := c.use_ent
+
+	if ent < 0 || ent >= len(use_tbl) {
+		out(c.who, "Internal error.")
+		log_write(LOG_CODE, "i_use: c.use_ent is %d", c.use_ent)
+		return FALSE
+	}
+
+	if use_tbl[ent].interrupt != nil {
+		return use_tbl[ent].interrupt(e, c)
+	}
+
+	return FALSE
+}
+
+// v_use_item handles USE of an item with a special use key.
+// Ported from src/use.c lines 763-886.
+func (e *Engine) v_use_item(c *command) int {
+	item := c.a
+
+	c.poll = FALSE
+	c.wait = 0
+
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s has no %s.", e.just_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	n := int(e.item_use_key(item))
+	if n == 0 {
+		wout(c.who, "Nothing special happens.")
+		return FALSE
+	}
+
+	// Magical objects may not be used in a safe haven.
+	switch n {
+	case use_palantir, use_proj_cast, use_quick_cast, use_orb,
+		use_barbarian_kill, use_savage_kill, use_corpse_kill,
+		use_orc_kill, use_skeleton_kill:
+		if e.in_safe_now(c.who) {
+			wout(c.who, "Magic may not be used in safe havens.")
+			c.wait = 0
+			c.inhibit_finish = TRUE
+			return FALSE
+		}
+	}
+
+	var ret int
+	switch n {
+	case use_heal_potion:
+		ret = e.v_use_heal(c)
+	case use_slave_potion:
+		ret = e.v_use_slave(c)
+	case use_death_potion:
+		ret = e.v_use_death(c)
+	case use_palantir:
+		ret = e.v_use_palantir(c)
+	case use_proj_cast:
+		ret = e.v_use_proj_cast(c)
+	case use_quick_cast:
+		ret = e.v_use_quick_cast(c)
+	case use_drum:
+		ret = e.v_use_drum(c)
+	case use_orb:
+		ret = e.v_use_orb(c)
+	case use_barbarian_kill:
+		ret = e.v_suffuse_ring(c, item_barbarian)
+	case use_savage_kill:
+		ret = e.v_suffuse_ring(c, item_savage)
+	case use_corpse_kill:
+		ret = e.v_suffuse_ring(c, item_corpse)
+	case use_orc_kill:
+		ret = e.v_suffuse_ring(c, item_orc)
+	case use_skeleton_kill:
+		ret = e.v_suffuse_ring(c, item_skeleton)
+	case use_bta_skull:
+		ret = e.v_use_bta_skull(c)
+	default:
+		log_write(LOG_CODE, "v_use_item: bad use key: %d", n)
+		wout(c.who, "Nothing special happens.")
+		ret = FALSE
+	}
+
+	if ret != TRUE || c.wait == 0 {
+		c.wait = 0
+		c.inhibit_finish = TRUE
+	}
+
+	return ret
+}
+
+// d_use_item is the finish routine for items used over several days.
+// Ported from src/use.c lines 889-917.
+func (e *Engine) d_use_item(c *command) int {
+	item := c.a
+
+	if e.has_item(c.who, item) < 1 {
+		wout(c.who, "%s no longer has %s.", e.just_name(c.who), e.box_code(item))
+		return FALSE
+	}
+
+	n := int(e.item_use_key(item))
+	if n == 0 {
+		wout(c.who, "Nothing special happens.")
+		return FALSE
+	}
+
+	switch n {
+	case use_palantir:
+		return e.d_use_palantir(c)
+	}
+
+	log_write(LOG_CODE, "d_use_item: bad use key: %d", n)
+	return TRUE
+}
+
+// exp_level converts a raw experience count into an experience level.
+// Ported from src/use.c lines 920-939.
+func exp_level(exp int) int {
+	switch {
+	case exp <= 4:
+		return exp_novice
+	case exp <= 11:
+		return exp_journeyman
+	case exp <= 20:
+		return exp_teacher
+	case exp <= 34:
+		return exp_master
+	}
+	return exp_grand
+}
+
+// exp_s returns the display name of an experience level.
+// Ported from src/use.c lines 942-958.
+func exp_s(level int) string {
+	switch level {
+	case exp_novice:
+		return "apprentice"
+	case exp_journeyman:
+		return "journeyman"
+	case exp_teacher:
+		return "adept"
+	case exp_master:
+		return "master"
+	case exp_grand:
+		return "grand master"
+	}
+	return ""
+}
+
+// rp_skill_ent returns who's record for a skill, or nil.
+// Ported from src/use.c lines 961-977.
+func (e *Engine) rp_skill_ent(who, skill int) *skill_ent {
+	if e.rp_char(who) == nil {
+		return nil
+	}
+	for _, p := range e.getCharSkills(who) {
+		if p.skill == skill {
+			return p
+		}
+	}
+	return nil
+}
+
+// p_skill_ent returns who's record for a skill, creating it if needed.
+// Ported from src/use.c lines 980-1000.
+func (e *Engine) p_skill_ent(who, skill int) *skill_ent {
+	e.p_char(who)
+
+	if p := e.rp_skill_ent(who, skill); p != nil {
+		return p
+	}
+
+	p := &skill_ent{skill: skill}
+	e.appendCharSkill(who, p)
+	return p
+}
+
+// has_skill_level returns the experience level at which who knows
+// skill, or 0 if the skill is not known.
+// Port of C has_skill(), which returns the level rather than a flag.
+// Ported from src/use.c lines 1115-1126.
+func (e *Engine) has_skill_level(who, skill int) int {
+	p := e.rp_skill_ent(who, skill)
+	if p == nil || p.know != SKILL_know {
+		return 0
+	}
+	return exp_level(int(p.experience))
+}
+
+// list_skill_sup writes one line of a skill listing: the skill's
+// code and name, and the experience level for skills that have one.
+// Ported from src/use.c lines 1211-1227.
+func (e *Engine) list_skill_sup(who int, p *skill_ent) {
+	if e.skill_no_exp(p.skill) != 0 || e.skill_school(p.skill) == p.skill {
+		wout(who, "%*s  %s", CHAR_FIELD, box_code_less(p.skill), cap(e.just_name(p.skill)))
+	} else {
+		wout(who, "%*s  %s, %s", CHAR_FIELD, box_code_less(p.skill), cap(e.just_name(p.skill)), exp_s(exp_level(int(p.experience))))
+	}
+}
+
+// v_forget is the start routine for the FORGET order. Forgetting a
+// school forgets its subskills too, and noble points spent on the
+// forgotten skills are refunded.
+// Ported from src/use.c lines 1030-1112.
+func (e *Engine) v_forget(c *command) int {
+	skill := c.a
+
+	if e.kind(skill) != T_skill {
+		wout(c.who, "%s is not a skill.", e.box_code(skill))
+		return FALSE
+	}
+
+	sum := 0
+	if p := e.rp_skill_ent(c.who, skill); p != nil && p.know != SKILL_dont {
+		sum += e.skill_np_req(skill)
+	}
+
+	if !e.forget_skill(c.who, skill) {
+		wout(c.who, "Don't know %s.", e.box_code(skill))
+		return FALSE
+	}
+
+	wout(c.who, "Forgot all knowledge of %s.", e.box_code(skill))
+
+	if e.skill_school(skill) == skill {
+		for i := e.kind_first(T_skill); i > 0; i = e.kind_next(i) {
+			if i == skill || e.skill_school(i) != skill {
+				continue
+			}
+			if p := e.rp_skill_ent(c.who, i); p != nil {
+				if p.know != SKILL_dont {
+					sum += e.skill_np_req(i)
+				}
+				e.forget_skill(c.who, i)
+				wout(c.who, "Forgot %s.", e.box_code(i))
+			}
+		}
+	}
+
+	if skill == sk_weather {
+		e.p_magic(c.who).knows_weather = 0
+	}
+
+	if e.is_magician(c.who) != 0 {
+		// See if they still qualify as a magician
+		e.p_magic(c.who).magician = FALSE
+		for _, p := range e.getCharSkills(c.who) {
+			if p.know == SKILL_know && e.magic_skill(p.skill) {
+				e.p_magic(c.who).magician = TRUE
+			}
+		}
+	}
+
+	if sum > 0 {
+		e.add_np(e.player(c.who), sum)
+		wout(c.who, "Refunded %d noble point%s.", sum, add_s(sum))
+	}
+
+	return TRUE
+}
+
+// learn_skill marks sk as known by who. Archery grants a missile
+// rating, and magic skills raise maximum aura.
+// Ported from src/use.c lines 1669-1703.
+func (e *Engine) learn_skill(who, sk int) {
+	p := e.p_skill_ent(who, sk)
+
+	wout(who, "Learned %s.", e.box_name(sk))
+	p.know = SKILL_know
+
+	if sk == sk_archery {
+		pc := e.p_char(who)
+		if pc.missile < 50 {
+			pc.missile += 50
+		}
+	}
+
+	if e.magic_skill(sk) {
+		ch := e.p_magic(who)
+		ch.max_aura++
+		ch.cur_aura++
+
+		wout(who, "Maximum aura now %d.", ch.max_aura)
+
+		ch.magician = TRUE
+
+		if sk == sk_weather {
+			ch.knows_weather = 1
+		}
+	}
+}
+
+// char_np_total returns the noble points invested in a character:
+// one for the noble, plus oath loyalty, plus every skill known.
+// Ported from src/use.c lines 1926-1944.
+func (e *Engine) char_np_total(who int) int {
+	sum := 1 // chars cost 1 NP to start
+
+	if e.is_npc(who) && e.subkind(who) != sub_dead_body {
+		return 0
+	}
+
+	if e.loyal_kind(who) == LOY_oath {
+		sum += e.loyal_rate(who)
+	}
+
+	for _, sk := range e.getCharSkills(who) {
+		sum += e.skill_np_req(sk.skill)
+	}
+
+	return sum
+}
+
+// clearExpThisMonth resets the once-per-month experience flags.
+// In C the flag was never saved, so each turn's process started clear.
+func (e *Engine) clearExpThisMonth() {
+	for _, who := range e.Characters() {
+		for _, p := range e.getCharSkills(who) {
+			p.exp_this_month = FALSE
+		}
+	}
+}
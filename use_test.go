@@ -0,0 +1,192 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// use_test.go - Tests for skill use dispatch
+
+package taygete
+
+import "testing"
+
+// setupUseTest builds a player with one noble who knows the combat
+// school and its archery subskill.
+func setupUseTest(t *testing.T) (pl, who int) {
+	t.Helper()
+	setupTrainingTest()
+	teg.globals.charSkills = make(map[int][]*skill_ent)
+	teg.globals.orderQueues = nil
+	teg.initCommandQueues()
+
+	pl, who = 50_001, 1001
+	teg.alloc_box(pl, T_player, sub_pl_regular)
+	teg.p_player(pl)
+	teg.alloc_box(who, T_char, 0)
+	teg.p_char(who).unit_lord = pl
+	teg.p_char(who).health = 100
+
+	teg.alloc_box(sk_combat, T_skill, 0)
+	teg.alloc_box(sk_archery, T_skill, 0)
+	teg.p_skill(sk_archery).required_skill = sk_combat
+
+	teg.p_skill_ent(who, sk_combat).know = SKILL_know
+	teg.p_skill_ent(who, sk_archery).know = SKILL_know
+	return pl, who
+}
+
+func TestExpLevel(t *testing.T) {
+	tests := []struct {
+		exp  int
+		want int
+		name string
+	}{
+		{0, exp_novice, "apprentice"},
+		{4, exp_novice, "apprentice"},
+		{5, exp_journeyman, "journeyman"},
+		{11, exp_journeyman, "journeyman"},
+		{12, exp_teacher, "adept"},
+		{21, exp_master, "master"},
+		{35, exp_grand, "grand master"},
+	}
+	for _, tt := range tests {
+		if got := exp_level(tt.exp); got != tt.want {
+			t.Errorf("exp_level(%d) = %d, want %d", tt.exp, got, tt.want)
+		}
+		if got := exp_s(exp_level(tt.exp)); got != tt.name {
+			t.Errorf("exp_s(exp_level(%d)) = %q, want %q", tt.exp, got, tt.name)
+		}
+	}
+}
+
+func TestFindUseEntry(t *testing.T) {
+	ent := find_use_entry(sk_archery)
+	if ent <= 0 {
+		t.Fatalf("find_use_entry(sk_archery) = %d, want > 0", ent)
+	}
+	if use_tbl[ent].time != 7 {
+		t.Errorf("archery use time = %d, want 7", use_tbl[ent].time)
+	}
+	if got := find_use_entry(sk_combat); got != -1 {
+		t.Errorf("find_use_entry(sk_combat) = %d, want -1", got)
+	}
+}
+
+func TestAddSkillExperienceOncePerMonth(t *testing.T) {
+	_, who := setupUseTest(t)
+
+	teg.add_skill_experience(who, sk_archery)
+	teg.add_skill_experience(who, sk_archery)
+	if got := teg.rp_skill_ent(who, sk_archery).experience; got != 1 {
+		t.Errorf("experience = %d, want 1 after two uses in one month", got)
+	}
+
+	teg.clearExpThisMonth()
+	teg.add_skill_experience(who, sk_archery)
+	if got := teg.rp_skill_ent(who, sk_archery).experience; got != 2 {
+		t.Errorf("experience = %d, want 2 after a new month", got)
+	}
+
+	// unknown skills never gain experience
+	teg.add_skill_experience(who, sk_swordplay)
+	if teg.rp_skill_ent(who, sk_swordplay) != nil {
+		t.Error("add_skill_experience created a skill record")
+	}
+}
+
+func TestVUseRejectsSchool(t *testing.T) {
+	_, who := setupUseTest(t)
+
+	c := &command{who: who}
+	if !teg.oly_parse(c, "use 610") {
+		t.Fatal("oly_parse failed")
+	}
+	if got := teg.v_use(c); got != FALSE {
+		t.Errorf("v_use(school) = %d, want FALSE", got)
+	}
+}
+
+func TestVUseUnknownSkill(t *testing.T) {
+	_, who := setupUseTest(t)
+	teg.p_skill_ent(who, sk_archery).know = SKILL_dont
+
+	c := &command{who: who}
+	teg.oly_parse(c, "use 615")
+	if got := teg.v_use(c); got != FALSE {
+		t.Errorf("v_use(unknown skill) = %d, want FALSE", got)
+	}
+}
+
+func TestUseOrderAdvancesExperience(t *testing.T) {
+	pl, who := setupUseTest(t)
+	teg.p_char(who).missile = 50
+
+	teg.queue_order(pl, who, "use 615")
+	teg.initialCommandLoad()
+
+	c := teg.rp_command(who)
+	if c == nil || c.state != STATE_LOAD {
+		t.Fatalf("use order was not loaded")
+	}
+	if cmd_tbl[c.cmd].name != "use" {
+		t.Fatalf("cmd = %q, want %q", cmd_tbl[c.cmd].name, "use")
+	}
+
+	for day := 1; day <= 7; day++ {
+		teg.dailyCommandLoop()
+	}
+
+	if c.state == STATE_RUN {
+		t.Fatalf("use order still running after seven days, wait = %d", c.wait)
+	}
+	if got := teg.rp_skill_ent(who, sk_archery).experience; got != 1 {
+		t.Errorf("archery experience = %d, want 1", got)
+	}
+	if got := teg.rp_skill(sk_archery).use_count; got != 1 {
+		t.Errorf("archery use_count = %d, want 1", got)
+	}
+	if teg.p_char(who).missile <= 50 {
+		t.Errorf("missile = %d, want > 50 after archery training", teg.p_char(who).missile)
+	}
+}
+
+func TestFindCommand(t *testing.T) {
+	i, fuzzy := teg.find_command("use")
+	if i <= 0 || fuzzy || cmd_tbl[i].name != "use" {
+		t.Errorf("find_command(use) = %d, %v", i, fuzzy)
+	}
+	if i, _ := teg.find_command("xyzzy-not-a-command"); i != -1 {
+		t.Errorf("find_command(bogus) = %d, want -1", i)
+	}
+}
+
+func TestOlyParseArguments(t *testing.T) {
+	c := &command{}
+	if !teg.oly_parse(c, "&give 1001 12 5 # a comment") {
+		t.Fatal("oly_parse failed")
+	}
+	if c.conditional != 1 {
+		t.Errorf("conditional = %d, want 1", c.conditional)
+	}
+	if c.a != 1001 || c.b != 12 || c.c != 5 {
+		t.Errorf("args = %d %d %d, want 1001 12 5", c.a, c.b, c.c)
+	}
+	if teg.numargs(c) != 3 {
+		t.Errorf("numargs = %d, want 3", teg.numargs(c))
+	}
+
+	teg.cmd_shift(c)
+	if c.a != 12 || get_parse_arg(c, 1) != "12" || teg.numargs(c) != 2 {
+		t.Errorf("after cmd_shift: a=%d parse=%v", c.a, c.parse)
+	}
+}
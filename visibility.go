@@ -22,8 +22,8 @@ package taygete
 // contacted checks if character a has been contacted by character b.
 // Returns true if b (or b's player) is in a's contact list.
 // Ported from src/u.c lines 700-714.
-func contacted(a, b int) bool {
-	p := rp_char(a)
+func (e *Engine) contacted(a, b int) bool {
+	p := e.rp_char(a)
 	if p == nil {
 		return false
 	}
@@ -32,7 +32,7 @@ func contacted(a, b int) bool {
 		return true
 	}
 
-	if IListLookup(p.contact, player(b)) >= 0 {
+	if IListLookup(p.contact, e.player(b)) >= 0 {
 		return true
 	}
 
@@ -41,22 +41,22 @@ func contacted(a, b int) bool {
 
 // char_alone checks if a character is alone (not stacked under anyone and has no stack members).
 // Ported from src/oly.h macro: #define char_alone(n) (stack_parent(n) == 0 && count_stack_any(n) == 1)
-func char_alone(n int) bool {
-	return stack_parent(n) == 0 && count_stack_any(n) == 1
+func (e *Engine) char_alone(n int) bool {
+	return e.stack_parent(n) == 0 && e.count_stack_any(n) == 1
 }
 
 // char_really_hidden checks if a character is truly hidden (hidden and alone).
 // Ported from src/oly.h macro: #define char_really_hidden(n) (char_hidden(n) && char_alone(n))
-func char_really_hidden(n int) bool {
-	return char_hidden(n) != 0 && char_alone(n)
+func (e *Engine) char_really_hidden(n int) bool {
+	return e.char_hidden(n) != 0 && e.char_alone(n)
 }
 
 // garrison_here returns the garrison character at a location, or 0 if none.
 // Garrisons are always first in the here_list if present.
 // Ported from src/garr.c lines 41-52.
-func garrison_here(where int) int {
-	n := first_character(where)
-	if n != 0 && subkind(n) == sub_garrison {
+func (e *Engine) garrison_here(where int) int {
+	n := e.first_character(where)
+	if n != 0 && e.subkind(n) == sub_garrison {
 		return n
 	}
 	return 0
@@ -65,22 +65,22 @@ func garrison_here(where int) int {
 // weather_here returns the total weather strength of a given type at a location.
 // Returns 0 for buildings (weather doesn't affect interiors).
 // Ported from src/storm.c lines 330-349.
-func weather_here(where int, sk schar) int {
-	if loc_depth(where) == LOC_build {
+func (e *Engine) weather_here(where int, sk schar) int {
+	if e.loc_depth(where) == LOC_build {
 		return 0
 	}
 
-	where = province(where)
+	where = e.province(where)
 
-	p := rp_loc_info(where)
+	p := e.rp_loc_info(where)
 	if p == nil {
 		return 0
 	}
 
 	sum := 0
 	for _, i := range p.here_list {
-		if kind(i) == T_storm && subkind(i) == sk {
-			sum += int(storm_strength(i))
+		if e.kind(i) == T_storm && e.subkind(i) == sk {
+			sum += int(e.storm_strength(i))
 		}
 	}
 
@@ -95,27 +95,27 @@ func weather_here(where int, sk schar) int {
 //   - Characters in the same stack
 //
 // Ported from src/u.c lines 717-746.
-func char_where(where, who, target int) bool {
-	if where != subloc(target) {
+func (e *Engine) char_where(where, who, target int) bool {
+	if where != e.subloc(target) {
 		return false
 	}
 
-	if char_really_hidden(target) ||
-		(loc_depth(where) == LOC_province && weather_here(where, sub_fog) != 0) {
-		pl := player(who)
-		if pl == player(target) {
+	if e.char_really_hidden(target) ||
+		(e.loc_depth(where) == LOC_province && e.weather_here(where, sub_fog) != 0) {
+		pl := e.player(who)
+		if pl == e.player(target) {
 			return true
 		}
 
-		if target == garrison_here(where) {
+		if target == e.garrison_here(where) {
 			return true
 		}
 
-		if contacted(target, who) {
+		if e.contacted(target, who) {
 			return true
 		}
 
-		if stack_leader(target) == stack_leader(who) {
+		if e.stack_leader(target) == e.stack_leader(who) {
 			return true
 		}
 
@@ -127,9 +127,9 @@ func char_where(where, who, target int) bool {
 
 // char_here checks if a target character can be seen from the caller's location.
 // Ported from src/u.c lines 749-755.
-func char_here(who, target int) bool {
-	where := subloc(who)
-	return char_where(where, who, target)
+func (e *Engine) char_here(who, target int) bool {
+	where := e.subloc(who)
+	return e.char_where(where, who, target)
 }
 
 // check_char_where validates a target character at a location with error messages.
@@ -139,19 +139,19 @@ func char_here(who, target int) bool {
 //   - Target cannot be seen at the location
 //
 // Ported from src/u.c lines 758-781.
-func check_char_where(where, who, target int) bool {
-	if target == teg.globals.garrison_magic {
+func (e *Engine) check_char_where(where, who, target int) bool {
+	if target == e.globals.garrison_magic {
 		wout(who, "There is no garrison here.")
 		return false
 	}
 
-	if kind(target) != T_char {
-		wout(who, "%s is not a character.", box_code(target))
+	if e.kind(target) != T_char {
+		wout(who, "%s is not a character.", e.box_code(target))
 		return false
 	}
 
-	if !char_where(where, who, target) {
-		wout(who, "%s cannot be seen here.", box_code(target))
+	if !e.char_where(where, who, target) {
+		wout(who, "%s cannot be seen here.", e.box_code(target))
 		return false
 	}
 
@@ -160,9 +160,9 @@ func check_char_where(where, who, target int) bool {
 
 // check_char_here validates a target character at the caller's location.
 // Ported from src/u.c lines 784-790.
-func check_char_here(who, target int) bool {
-	where := subloc(who)
-	return check_char_where(where, who, target)
+func (e *Engine) check_char_here(who, target int) bool {
+	where := e.subloc(who)
+	return e.check_char_where(where, who, target)
 }
 
 // check_char_gone validates a target character is visible and hasn't left.
@@ -173,24 +173,24 @@ func check_char_here(who, target int) bool {
 //   - Target has left (is moving)
 //
 // Ported from src/u.c lines 793-822.
-func check_char_gone(who, target int) bool {
-	if target == teg.globals.garrison_magic {
+func (e *Engine) check_char_gone(who, target int) bool {
+	if target == e.globals.garrison_magic {
 		wout(who, "There is no garrison here.")
 		return false
 	}
 
-	if kind(target) != T_char {
-		wout(who, "%s is not a character.", box_code(target))
+	if e.kind(target) != T_char {
+		wout(who, "%s is not a character.", e.box_code(target))
 		return false
 	}
 
-	if !char_here(who, target) {
-		wout(who, "%s cannot be seen here.", box_code(target))
+	if !e.char_here(who, target) {
+		wout(who, "%s cannot be seen here.", e.box_code(target))
 		return false
 	}
 
-	if char_gone(target) != 0 {
-		wout(who, "%s has left.", box_name(target))
+	if e.char_gone(target) != 0 {
+		wout(who, "%s has left.", e.box_name(target))
 		return false
 	}
 
@@ -201,19 +201,19 @@ func check_char_gone(who, target int) bool {
 // Similar to check_char_here but with different error message.
 // The C code has a commented-out char_gone check.
 // Ported from src/u.c lines 825-856.
-func check_still_here(who, target int) bool {
-	if target == teg.globals.garrison_magic {
+func (e *Engine) check_still_here(who, target int) bool {
+	if target == e.globals.garrison_magic {
 		wout(who, "There is no garrison here.")
 		return false
 	}
 
-	if kind(target) != T_char {
-		wout(who, "%s is not a character.", box_code(target))
+	if e.kind(target) != T_char {
+		wout(who, "%s is not a character.", e.box_code(target))
 		return false
 	}
 
-	if !char_here(who, target) {
-		wout(who, "%s can no longer be seen here.", box_name(target))
+	if !e.char_here(who, target) {
+		wout(who, "%s can no longer be seen here.", e.box_name(target))
 		return false
 	}
 
@@ -223,9 +223,9 @@ func check_still_here(who, target int) bool {
 // check_skill checks if a character has a required skill.
 // Returns false (with wout message) if the skill is not known.
 // Ported from src/u.c lines 859-870.
-func check_skill(who, skill int) bool {
-	if !has_skill(who, skill) {
-		wout(who, "Requires %s.", box_name(skill))
+func (e *Engine) check_skill(who, skill int) bool {
+	if !e.has_skill(who, skill) {
+		wout(who, "Requires %s.", e.box_name(skill))
 		return false
 	}
 	return true
@@ -233,21 +233,21 @@ func check_skill(who, skill int) bool {
 
 // has_skill checks if a character knows a skill.
 // Returns true if the character has the skill at know level.
-func has_skill(who, skill int) bool {
-	return has_skill_check(who, skill)
+func (e *Engine) has_skill(who, skill int) bool {
+	return e.has_skill_check(who, skill)
 }
 
 // count_stack_any returns the count of all units in a stack (including the leader).
 // This counts all characters stacked under who, plus who itself.
-func count_stack_any(who int) int {
+func (e *Engine) count_stack_any(who int) int {
 	count := 1
-	p := rp_loc_info(who)
+	p := e.rp_loc_info(who)
 	if p == nil {
 		return count
 	}
 	for _, id := range p.here_list {
-		if kind(id) == T_char {
-			count += count_stack_any(id)
+		if e.kind(id) == T_char {
+			count += e.count_stack_any(id)
 		}
 	}
 	return count
@@ -255,35 +255,35 @@ func count_stack_any(who int) int {
 
 // first_char_here returns the first character in the here_list of where.
 // Ported from src/u.c lines 2249-2265.
-func first_char_here(where int) int {
-	return first_character(where)
+func (e *Engine) first_char_here(where int) int {
+	return e.first_character(where)
 }
 
 // bark_dogs causes hounds at a location to bark, alerting observers.
 // Each hound has a 50% chance to bark. The message varies based on
 // whether one hound barks, or multiple.
 // Ported from src/u.c lines 2440-2469.
-func bark_dogs(where int) {
+func (e *Engine) bark_dogs(where int) {
 	vector_char_here(where)
 	vector_add(where)
 
 	sum := 0
 	bark := 0
 
-	p := rp_loc_info(where)
+	p := e.rp_loc_info(where)
 	if p == nil {
 		return
 	}
 
 	for _, who := range p.here_list {
-		if kind(who) != T_char {
+		if e.kind(who) != T_char {
 			continue
 		}
 
-		n := stack_has_item(who, item_hound)
+		n := e.stack_has_item(who, item_hound)
 		sum += n
 		for i := 1; i <= n; i++ {
-			if rnd(1, 2) == 1 {
+			if e.rndFrom(streamNPC, 1, 2) == 1 {
 				bark++
 			}
 		}
@@ -20,7 +20,7 @@ import "testing"
 
 // setupVisibilityTest initializes test state for visibility tests.
 func setupVisibilityTest() {
-	teg.globals.bx = [MAX_BOXES]*box{}
+	teg.globals.bx = boxStore{}
 	teg.globals.sysclock = olytime{days_since_epoch: 100}
 	teg.globals.evening = false
 	teg.globals.garrison_magic = 999
@@ -28,53 +28,53 @@ func setupVisibilityTest() {
 
 // setupVisibilityTestCharacter creates a basic test character.
 func setupVisibilityTestCharacter(who int) {
-	if teg.globals.bx[who] == nil {
-		teg.globals.bx[who] = &box{}
+	if teg.globals.bx.get(who) == nil {
+		teg.setBox(who, &box{})
 	}
-	teg.globals.bx[who].kind = T_char
-	teg.globals.bx[who].skind = 0
-	teg.globals.bx[who].x_char = &entity_char{
+	teg.globals.bx.get(who).kind = T_char
+	teg.globals.bx.get(who).skind = 0
+	teg.globals.bx.get(who).x_char = &entity_char{
 		health:       100,
 		melt_me:      FALSE,
 		prisoner:     FALSE,
 		unit_lord:    indep_player,
 		x_char_magic: &char_magic{},
 	}
-	teg.globals.bx[who].x_loc_info = loc_info{where: 0}
+	teg.globals.bx.get(who).x_loc_info = loc_info{where: 0}
 	teg.setName(who, "Test Character")
 }
 
 // setupVisibilityTestLocation creates a basic test location.
 func setupVisibilityTestLocation(loc int, sk schar) {
-	if teg.globals.bx[loc] == nil {
-		teg.globals.bx[loc] = &box{}
+	if teg.globals.bx.get(loc) == nil {
+		teg.setBox(loc, &box{})
 	}
-	teg.globals.bx[loc].kind = T_loc
-	teg.globals.bx[loc].skind = sk
-	teg.globals.bx[loc].x_loc_info = loc_info{where: 0}
+	teg.globals.bx.get(loc).kind = T_loc
+	teg.globals.bx.get(loc).skind = sk
+	teg.globals.bx.get(loc).x_loc_info = loc_info{where: 0}
 	teg.setName(loc, "Test Location")
 }
 
 // setupVisibilityTestPlayer creates a basic test player.
 func setupVisibilityTestPlayer(pl int) {
-	if teg.globals.bx[pl] == nil {
-		teg.globals.bx[pl] = &box{}
+	if teg.globals.bx.get(pl) == nil {
+		teg.setBox(pl, &box{})
 	}
-	teg.globals.bx[pl].kind = T_player
-	teg.globals.bx[pl].skind = sub_pl_regular
-	teg.globals.bx[pl].x_player = &entity_player{}
+	teg.globals.bx.get(pl).kind = T_player
+	teg.globals.bx.get(pl).skind = sub_pl_regular
+	teg.globals.bx.get(pl).x_player = &entity_player{}
 	teg.setName(pl, "Test Player")
 }
 
 // setupVisibilityTestStorm creates a test storm.
 func setupVisibilityTestStorm(storm int, sk schar, strength short) {
-	if teg.globals.bx[storm] == nil {
-		teg.globals.bx[storm] = &box{}
+	if teg.globals.bx.get(storm) == nil {
+		teg.setBox(storm, &box{})
 	}
-	teg.globals.bx[storm].kind = T_storm
-	teg.globals.bx[storm].skind = sk
-	teg.globals.bx[storm].x_loc_info = loc_info{where: 0}
-	teg.globals.bx[storm].x_misc = &entity_misc{storm_str: strength}
+	teg.globals.bx.get(storm).kind = T_storm
+	teg.globals.bx.get(storm).skind = sk
+	teg.globals.bx.get(storm).x_loc_info = loc_info{where: 0}
+	teg.globals.bx.get(storm).x_misc = &entity_misc{storm_str: strength}
 	teg.setName(storm, "Test Storm")
 }
 
@@ -90,24 +90,24 @@ func TestContacted(t *testing.T) {
 	setupVisibilityTestCharacter(charB)
 	setupVisibilityTestPlayer(playerB)
 
-	p_char(charB).unit_lord = playerB
+	teg.p_char(charB).unit_lord = playerB
 
-	if contacted(charA, charB) {
+	if teg.contacted(charA, charB) {
 		t.Error("contacted(charA, charB) = true, want false (no contact)")
 	}
 
-	p_char(charA).contact = append(p_char(charA).contact, charB)
-	if !contacted(charA, charB) {
+	teg.p_char(charA).contact = append(teg.p_char(charA).contact, charB)
+	if !teg.contacted(charA, charB) {
 		t.Error("contacted(charA, charB) = false, want true (direct contact)")
 	}
 
-	p_char(charA).contact = nil
-	p_char(charA).contact = append(p_char(charA).contact, playerB)
-	if !contacted(charA, charB) {
+	teg.p_char(charA).contact = nil
+	teg.p_char(charA).contact = append(teg.p_char(charA).contact, playerB)
+	if !teg.contacted(charA, charB) {
 		t.Error("contacted(charA, charB) = false, want true (player contact)")
 	}
 
-	if contacted(charB, charA) {
+	if teg.contacted(charB, charA) {
 		t.Error("contacted(charB, charA) = true, want false (not mutual)")
 	}
 }
@@ -124,18 +124,18 @@ func TestCharAlone(t *testing.T) {
 	setupVisibilityTestCharacter(charA)
 	setupVisibilityTestCharacter(charB)
 
-	set_where(charA, province)
+	teg.set_where(charA, province)
 
-	if !char_alone(charA) {
+	if !teg.char_alone(charA) {
 		t.Error("char_alone(charA) = false, want true (alone in province)")
 	}
 
-	set_where(charB, charA)
-	if char_alone(charA) {
+	teg.set_where(charB, charA)
+	if teg.char_alone(charA) {
 		t.Error("char_alone(charA) = true, want false (has stack member)")
 	}
 
-	if char_alone(charB) {
+	if teg.char_alone(charB) {
 		t.Error("char_alone(charB) = true, want false (stacked under charA)")
 	}
 }
@@ -152,19 +152,19 @@ func TestCharReallyHidden(t *testing.T) {
 	setupVisibilityTestCharacter(charA)
 	setupVisibilityTestCharacter(charB)
 
-	set_where(charA, province)
+	teg.set_where(charA, province)
 
-	if char_really_hidden(charA) {
+	if teg.char_really_hidden(charA) {
 		t.Error("char_really_hidden(charA) = true, want false (not hidden)")
 	}
 
-	p_magic(charA).hide_self = 1
-	if !char_really_hidden(charA) {
+	teg.p_magic(charA).hide_self = 1
+	if !teg.char_really_hidden(charA) {
 		t.Error("char_really_hidden(charA) = false, want true (hidden and alone)")
 	}
 
-	set_where(charB, charA)
-	if char_really_hidden(charA) {
+	teg.set_where(charB, charA)
+	if teg.char_really_hidden(charA) {
 		t.Error("char_really_hidden(charA) = true, want false (hidden but not alone)")
 	}
 }
@@ -179,21 +179,21 @@ func TestGarrisonHere(t *testing.T) {
 
 	setupVisibilityTestLocation(province, sub_plain)
 
-	if got := garrison_here(province); got != 0 {
+	if got := teg.garrison_here(province); got != 0 {
 		t.Errorf("garrison_here(empty province) = %d, want 0", got)
 	}
 
 	setupVisibilityTestCharacter(garrison)
-	teg.globals.bx[garrison].skind = sub_garrison
-	set_where(garrison, province)
+	teg.globals.bx.get(garrison).skind = sub_garrison
+	teg.set_where(garrison, province)
 
-	if got := garrison_here(province); got != garrison {
+	if got := teg.garrison_here(province); got != garrison {
 		t.Errorf("garrison_here(province with garrison) = %d, want %d", got, garrison)
 	}
 
 	setupVisibilityTestCharacter(charA)
-	set_where(charA, province)
-	if got := garrison_here(province); got != garrison {
+	teg.set_where(charA, province)
+	if got := teg.garrison_here(province); got != garrison {
 		t.Errorf("garrison_here(province) = %d, want %d (garrison is first)", got, garrison)
 	}
 }
@@ -212,23 +212,23 @@ func TestWeatherHere(t *testing.T) {
 	setupVisibilityTestLocation(building, sub_castle)
 	setupVisibilityTestStorm(storm, sub_fog, 5)
 
-	set_where(province, region)
-	set_where(building, province)
+	teg.set_where(province, region)
+	teg.set_where(building, province)
 
-	if got := weather_here(province, sub_fog); got != 0 {
+	if got := teg.weather_here(province, sub_fog); got != 0 {
 		t.Errorf("weather_here(province, sub_fog) = %d, want 0 (no storm)", got)
 	}
 
-	set_where(storm, province)
-	if got := weather_here(province, sub_fog); got != 5 {
+	teg.set_where(storm, province)
+	if got := teg.weather_here(province, sub_fog); got != 5 {
 		t.Errorf("weather_here(province, sub_fog) = %d, want 5", got)
 	}
 
-	if got := weather_here(province, sub_rain); got != 0 {
+	if got := teg.weather_here(province, sub_rain); got != 0 {
 		t.Errorf("weather_here(province, sub_rain) = %d, want 0 (wrong type)", got)
 	}
 
-	if got := weather_here(building, sub_fog); got != 0 {
+	if got := teg.weather_here(building, sub_fog); got != 0 {
 		t.Errorf("weather_here(building, sub_fog) = %d, want 0 (inside building)", got)
 	}
 }
@@ -251,29 +251,29 @@ func TestCharWhere(t *testing.T) {
 	setupVisibilityTestPlayer(playerA)
 	setupVisibilityTestPlayer(playerB)
 
-	set_where(province, region)
-	set_where(charA, province)
-	set_where(charB, province)
+	teg.set_where(province, region)
+	teg.set_where(charA, province)
+	teg.set_where(charB, province)
 
-	p_char(charA).unit_lord = playerA
-	p_char(charB).unit_lord = playerA
+	teg.p_char(charA).unit_lord = playerA
+	teg.p_char(charB).unit_lord = playerA
 
-	if !char_where(province, charA, charB) {
+	if !teg.char_where(province, charA, charB) {
 		t.Error("char_where = false, want true (both visible)")
 	}
 
-	p_magic(charB).hide_self = 1
-	if !char_where(province, charA, charB) {
+	teg.p_magic(charB).hide_self = 1
+	if !teg.char_where(province, charA, charB) {
 		t.Error("char_where = false, want true (hidden but not alone - charB stacked with charA in province)")
 	}
 
-	p_char(charB).unit_lord = playerB
-	if char_where(province, charA, charB) {
+	teg.p_char(charB).unit_lord = playerB
+	if teg.char_where(province, charA, charB) {
 		t.Error("char_where = true, want false (different player, hidden alone)")
 	}
 
-	p_char(charB).contact = append(p_char(charB).contact, charA)
-	if !char_where(province, charA, charB) {
+	teg.p_char(charB).contact = append(teg.p_char(charB).contact, charA)
+	if !teg.char_where(province, charA, charB) {
 		t.Error("char_where = false, want true (contacted)")
 	}
 }
@@ -294,18 +294,18 @@ func TestCharHere(t *testing.T) {
 	setupVisibilityTestCharacter(charA)
 	setupVisibilityTestCharacter(charB)
 
-	set_where(province, region)
-	set_where(province2, region)
-	set_where(charA, province)
-	set_where(charB, province)
+	teg.set_where(province, region)
+	teg.set_where(province2, region)
+	teg.set_where(charA, province)
+	teg.set_where(charB, province)
 
-	if !char_here(charA, charB) {
+	if !teg.char_here(charA, charB) {
 		t.Error("char_here = false, want true (same location)")
 	}
 
-	set_where(charB, province2)
+	teg.set_where(charB, province2)
 
-	if char_here(charA, charB) {
+	if teg.char_here(charA, charB) {
 		t.Error("char_here = true, want false (different location)")
 	}
 }
@@ -325,21 +325,21 @@ func TestCheckCharWhere(t *testing.T) {
 	setupVisibilityTestCharacter(charA)
 	setupVisibilityTestCharacter(charB)
 
-	teg.globals.bx[item] = &box{kind: T_item}
+	teg.setBox(item, &box{kind: T_item})
 
-	set_where(province, region)
-	set_where(charA, province)
-	set_where(charB, province)
+	teg.set_where(province, region)
+	teg.set_where(charA, province)
+	teg.set_where(charB, province)
 
-	if !check_char_where(province, charA, charB) {
+	if !teg.check_char_where(province, charA, charB) {
 		t.Error("check_char_where = false, want true")
 	}
 
-	if check_char_where(province, charA, teg.globals.garrison_magic) {
+	if teg.check_char_where(province, charA, teg.globals.garrison_magic) {
 		t.Error("check_char_where(garrison_magic) = true, want false")
 	}
 
-	if check_char_where(province, charA, item) {
+	if teg.check_char_where(province, charA, item) {
 		t.Error("check_char_where(item) = true, want false (not a character)")
 	}
 }
@@ -358,11 +358,11 @@ func TestCheckCharHere(t *testing.T) {
 	setupVisibilityTestCharacter(charA)
 	setupVisibilityTestCharacter(charB)
 
-	set_where(province, region)
-	set_where(charA, province)
-	set_where(charB, province)
+	teg.set_where(province, region)
+	teg.set_where(charA, province)
+	teg.set_where(charB, province)
 
-	if !check_char_here(charA, charB) {
+	if !teg.check_char_here(charA, charB) {
 		t.Error("check_char_here = false, want true")
 	}
 }
@@ -381,20 +381,20 @@ func TestCheckCharGone(t *testing.T) {
 	setupVisibilityTestCharacter(charA)
 	setupVisibilityTestCharacter(charB)
 
-	set_where(province, region)
-	set_where(charA, province)
-	set_where(charB, province)
+	teg.set_where(province, region)
+	teg.set_where(charA, province)
+	teg.set_where(charB, province)
 
-	if !check_char_gone(charA, charB) {
+	if !teg.check_char_gone(charA, charB) {
 		t.Error("check_char_gone = false, want true (not moving)")
 	}
 
-	p_char(charB).moving = 1
-	if check_char_gone(charA, charB) {
+	teg.p_char(charB).moving = 1
+	if teg.check_char_gone(charA, charB) {
 		t.Error("check_char_gone = true, want false (target is moving)")
 	}
 
-	if check_char_gone(charA, teg.globals.garrison_magic) {
+	if teg.check_char_gone(charA, teg.globals.garrison_magic) {
 		t.Error("check_char_gone(garrison_magic) = true, want false")
 	}
 }
@@ -413,15 +413,15 @@ func TestCheckStillHere(t *testing.T) {
 	setupVisibilityTestCharacter(charA)
 	setupVisibilityTestCharacter(charB)
 
-	set_where(province, region)
-	set_where(charA, province)
-	set_where(charB, province)
+	teg.set_where(province, region)
+	teg.set_where(charA, province)
+	teg.set_where(charB, province)
 
-	if !check_still_here(charA, charB) {
+	if !teg.check_still_here(charA, charB) {
 		t.Error("check_still_here = false, want true")
 	}
 
-	if check_still_here(charA, teg.globals.garrison_magic) {
+	if teg.check_still_here(charA, teg.globals.garrison_magic) {
 		t.Error("check_still_here(garrison_magic) = true, want false")
 	}
 }
@@ -435,7 +435,7 @@ func TestCheckSkill(t *testing.T) {
 
 	setupVisibilityTestCharacter(charA)
 
-	teg.globals.bx[skillID] = &box{kind: T_skill}
+	teg.setBox(skillID, &box{kind: T_skill})
 	teg.setName(skillID, "Test Skill")
 
 	if teg.globals.charSkills == nil {
@@ -443,14 +443,14 @@ func TestCheckSkill(t *testing.T) {
 	}
 	teg.globals.charSkills[charA] = nil
 
-	if check_skill(charA, skillID) {
+	if teg.check_skill(charA, skillID) {
 		t.Error("check_skill = true, want false (no skill)")
 	}
 
 	teg.globals.charSkills[charA] = []*skill_ent{
 		{skill: skillID, know: SKILL_know},
 	}
-	if !check_skill(charA, skillID) {
+	if !teg.check_skill(charA, skillID) {
 		t.Error("check_skill = false, want true (has skill)")
 	}
 }
@@ -469,19 +469,19 @@ func TestCountStackAny(t *testing.T) {
 	setupVisibilityTestCharacter(charB)
 	setupVisibilityTestCharacter(charC)
 
-	set_where(charA, province)
+	teg.set_where(charA, province)
 
-	if got := count_stack_any(charA); got != 1 {
+	if got := teg.count_stack_any(charA); got != 1 {
 		t.Errorf("count_stack_any(alone) = %d, want 1", got)
 	}
 
-	set_where(charB, charA)
-	if got := count_stack_any(charA); got != 2 {
+	teg.set_where(charB, charA)
+	if got := teg.count_stack_any(charA); got != 2 {
 		t.Errorf("count_stack_any(with one) = %d, want 2", got)
 	}
 
-	set_where(charC, charB)
-	if got := count_stack_any(charA); got != 3 {
+	teg.set_where(charC, charB)
+	if got := teg.count_stack_any(charA); got != 3 {
 		t.Errorf("count_stack_any(nested) = %d, want 3", got)
 	}
 }
@@ -495,14 +495,14 @@ func TestFirstCharHere(t *testing.T) {
 
 	setupVisibilityTestLocation(province, sub_plain)
 
-	if got := first_char_here(province); got != 0 {
+	if got := teg.first_char_here(province); got != 0 {
 		t.Errorf("first_char_here(empty) = %d, want 0", got)
 	}
 
 	setupVisibilityTestCharacter(charA)
-	set_where(charA, province)
+	teg.set_where(charA, province)
 
-	if got := first_char_here(province); got != charA {
+	if got := teg.first_char_here(province); got != charA {
 		t.Errorf("first_char_here(with char) = %d, want %d", got, charA)
 	}
 }
@@ -521,21 +521,21 @@ func TestHasSkill(t *testing.T) {
 	}
 	teg.globals.charSkills[charA] = nil
 
-	if has_skill(charA, skillID) {
+	if teg.has_skill(charA, skillID) {
 		t.Error("has_skill = true, want false (no skill)")
 	}
 
 	teg.globals.charSkills[charA] = []*skill_ent{
 		{skill: skillID, know: SKILL_know},
 	}
-	if !has_skill(charA, skillID) {
+	if !teg.has_skill(charA, skillID) {
 		t.Error("has_skill = false, want true (has skill)")
 	}
 
 	teg.globals.charSkills[charA] = []*skill_ent{
 		{skill: skillID, know: SKILL_dont},
 	}
-	if has_skill(charA, skillID) {
+	if teg.has_skill(charA, skillID) {
 		t.Error("has_skill = true, want false (skill forgotten)")
 	}
 }
@@ -550,16 +550,16 @@ func TestBarkDogs(t *testing.T) {
 
 	setupVisibilityTestLocation(province, sub_plain)
 	setupVisibilityTestCharacter(charA)
-	set_where(charA, province)
+	teg.set_where(charA, province)
 
 	// No hounds - should produce no output
-	bark_dogs(province)
+	teg.bark_dogs(province)
 
 	// Give character hounds via the inventory system
 	teg.globals.inventories[charA] = []item_ent{{item: item_hound, qty: 2}}
 
 	// With hounds - function runs without panic (output is via VECT)
-	bark_dogs(province)
+	teg.bark_dogs(province)
 }
 
 // TestPrintDot tests that print_dot is a no-op.
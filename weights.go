@@ -20,11 +20,11 @@ package taygete
 
 // add_item_weight adds the weight and capacity of an item to a weights struct.
 // Ported from src/u.c lines 1303-1331.
-func add_item_weight(item, qty int, w *weights) {
-	wt := int(item_weight(item)) * qty
-	lc := int(item_land_cap(item))
-	rc := int(item_ride_cap(item))
-	fc := int(item_fly_cap(item))
+func (e *Engine) add_item_weight(item, qty int, w *weights) {
+	wt := int(e.item_weight(item)) * qty
+	lc := int(e.item_land_cap(item))
+	rc := int(e.item_ride_cap(item))
+	fc := int(e.item_fly_cap(item))
 
 	if lc != 0 {
 		w.land_cap += max(lc, 0) * qty
@@ -46,49 +46,49 @@ func add_item_weight(item, qty int, w *weights) {
 
 	w.total_weight += wt
 
-	if item_animal(item) != 0 {
+	if e.item_animal(item) != 0 {
 		w.animals += qty
 	}
 }
 
 // determine_unit_weights calculates the weight and capacity of a single unit.
 // Ported from src/u.c lines 1335-1355.
-func determine_unit_weights(who int, w *weights) {
-	if kind(who) != T_char {
+func (e *Engine) determine_unit_weights(who int, w *weights) {
+	if e.kind(who) != T_char {
 		panic("determine_unit_weights: who is not a character")
 	}
 
 	*w = weights{}
 
-	unitBase := int(noble_item(who))
+	unitBase := int(e.noble_item(who))
 	if unitBase == 0 {
 		unitBase = item_peasant
 	}
 
-	add_item_weight(unitBase, 1, w)
+	e.add_item_weight(unitBase, 1, w)
 
-	inv := teg.globals.inventories[who]
-	for _, e := range inv {
-		add_item_weight(e.item, e.qty, w)
+	inv := e.globals.inventories[who]
+	for _, it := range inv {
+		e.add_item_weight(it.item, it.qty, w)
 	}
 }
 
 // determine_stack_weights calculates the weight and capacity of a stack.
 // Ported from src/u.c lines 1359-1379.
-func determine_stack_weights(who int, w *weights) {
-	determine_unit_weights(who, w)
+func (e *Engine) determine_stack_weights(who int, w *weights) {
+	e.determine_unit_weights(who, w)
 
-	p := rp_loc_info(who)
+	p := e.rp_loc_info(who)
 	if p == nil {
 		return
 	}
 
 	for _, i := range p.here_list {
-		if kind(i) != T_char {
+		if e.kind(i) != T_char {
 			continue
 		}
 		var v weights
-		determine_unit_weights(i, &v)
+		e.determine_unit_weights(i, &v)
 		w.total_weight += v.total_weight
 		w.land_cap += v.land_cap
 		w.ride_cap += v.ride_cap
@@ -102,19 +102,19 @@ func determine_stack_weights(who int, w *weights) {
 
 // ship_weight calculates the total weight of all cargo on a ship.
 // Ported from src/u.c lines 1385-1411.
-func ship_weight(ship int) int {
-	if kind(ship) != T_ship {
+func (e *Engine) ship_weight(ship int) int {
+	if e.kind(ship) != T_ship {
 		panic("ship_weight: ship is not a ship")
 	}
 
 	sum := 0
 
 	var chars []int
-	loop_char_here(ship, &chars)
+	e.loop_char_here(ship, &chars)
 
 	for _, i := range chars {
 		var w weights
-		determine_unit_weights(i, &w)
+		e.determine_unit_weights(i, &w)
 		sum += w.total_weight
 	}
 
@@ -123,9 +123,9 @@ func ship_weight(ship int) int {
 
 // ship_cap returns the effective capacity of a ship, reduced by damage.
 // Ported from src/u.c lines 2404-2412.
-func ship_cap(ship int) int {
-	sc := ship_cap_raw(ship)
-	dam := int(loc_damage(ship))
+func (e *Engine) ship_cap(ship int) int {
+	sc := e.ship_cap_raw(ship)
+	dam := int(e.loc_damage(ship))
 
 	sc -= sc * dam / 100
 
@@ -134,14 +134,14 @@ func ship_cap(ship int) int {
 
 // loop_char_here collects all characters directly at a location.
 // This is a helper that matches the C loop_char_here macro.
-func loop_char_here(where int, l *[]int) {
+func (e *Engine) loop_char_here(where int, l *[]int) {
 	*l = nil
-	p := rp_loc_info(where)
+	p := e.rp_loc_info(where)
 	if p == nil {
 		return
 	}
 	for _, id := range p.here_list {
-		if kind(id) == T_char {
+		if e.kind(id) == T_char {
 			*l = append(*l, id)
 		}
 	}
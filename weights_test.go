@@ -19,9 +19,7 @@ package taygete
 import "testing"
 
 func clearWeightsTest() {
-	for i := range teg.globals.bx {
-		teg.globals.bx[i] = nil
-	}
+	teg.globals.bx = boxStore{}
 	teg.globals.inventories = make(map[int][]item_ent)
 }
 
@@ -30,7 +28,7 @@ func TestAddItemWeight(t *testing.T) {
 	clearWeightsTest()
 
 	itemID := 100
-	teg.globals.bx[itemID] = &box{
+	teg.setBox(itemID, &box{
 		kind:  T_item,
 		skind: 0,
 		x_item: &entity_item{
@@ -40,10 +38,10 @@ func TestAddItemWeight(t *testing.T) {
 			fly_cap:  0,
 			animal:   0,
 		},
-	}
+	})
 
 	horseID := 101
-	teg.globals.bx[horseID] = &box{
+	teg.setBox(horseID, &box{
 		kind:  T_item,
 		skind: 0,
 		x_item: &entity_item{
@@ -53,10 +51,10 @@ func TestAddItemWeight(t *testing.T) {
 			fly_cap:  0,
 			animal:   1,
 		},
-	}
+	})
 
 	pegasusID := 102
-	teg.globals.bx[pegasusID] = &box{
+	teg.setBox(pegasusID, &box{
 		kind:  T_item,
 		skind: 0,
 		x_item: &entity_item{
@@ -66,11 +64,11 @@ func TestAddItemWeight(t *testing.T) {
 			fly_cap:  500,
 			animal:   1,
 		},
-	}
+	})
 
 	t.Run("basic item weight", func(t *testing.T) {
 		var w weights
-		add_item_weight(itemID, 5, &w)
+		teg.add_item_weight(itemID, 5, &w)
 
 		if w.total_weight != 50 {
 			t.Errorf("total_weight = %d, want 50", w.total_weight)
@@ -94,7 +92,7 @@ func TestAddItemWeight(t *testing.T) {
 
 	t.Run("horse with capacity", func(t *testing.T) {
 		var w weights
-		add_item_weight(horseID, 2, &w)
+		teg.add_item_weight(horseID, 2, &w)
 
 		if w.total_weight != 200 {
 			t.Errorf("total_weight = %d, want 200", w.total_weight)
@@ -121,7 +119,7 @@ func TestAddItemWeight(t *testing.T) {
 
 	t.Run("pegasus with fly capacity", func(t *testing.T) {
 		var w weights
-		add_item_weight(pegasusID, 3, &w)
+		teg.add_item_weight(pegasusID, 3, &w)
 
 		if w.total_weight != 240 {
 			t.Errorf("total_weight = %d, want 240", w.total_weight)
@@ -142,8 +140,8 @@ func TestAddItemWeight(t *testing.T) {
 
 	t.Run("cumulative weights", func(t *testing.T) {
 		var w weights
-		add_item_weight(itemID, 10, &w)
-		add_item_weight(horseID, 1, &w)
+		teg.add_item_weight(itemID, 10, &w)
+		teg.add_item_weight(horseID, 1, &w)
 
 		if w.total_weight != 200 {
 			t.Errorf("total_weight = %d, want 200", w.total_weight)
@@ -165,7 +163,7 @@ func TestDetermineUnitWeights(t *testing.T) {
 	clearWeightsTest()
 
 	peasantID := item_peasant
-	teg.globals.bx[peasantID] = &box{
+	teg.setBox(peasantID, &box{
 		kind:  T_item,
 		skind: 0,
 		x_item: &entity_item{
@@ -175,10 +173,10 @@ func TestDetermineUnitWeights(t *testing.T) {
 			fly_cap:  0,
 			animal:   0,
 		},
-	}
+	})
 
 	goldID := item_gold
-	teg.globals.bx[goldID] = &box{
+	teg.setBox(goldID, &box{
 		kind:  T_item,
 		skind: 0,
 		x_item: &entity_item{
@@ -188,23 +186,23 @@ func TestDetermineUnitWeights(t *testing.T) {
 			fly_cap:  0,
 			animal:   0,
 		},
-	}
+	})
 
 	charID := 1001
-	teg.globals.bx[charID] = &box{
+	teg.setBox(charID, &box{
 		kind:  T_char,
 		skind: 0,
 		x_char: &entity_char{
 			unit_item: 0,
 		},
-	}
+	})
 	teg.globals.inventories[charID] = []item_ent{
 		{item: goldID, qty: 100},
 	}
 
 	t.Run("unit with default noble_item", func(t *testing.T) {
 		var w weights
-		determine_unit_weights(charID, &w)
+		teg.determine_unit_weights(charID, &w)
 
 		if w.total_weight != 110 {
 			t.Errorf("total_weight = %d, want 110 (10 peasant + 100 gold)", w.total_weight)
@@ -212,7 +210,7 @@ func TestDetermineUnitWeights(t *testing.T) {
 	})
 
 	warriorID := 50
-	teg.globals.bx[warriorID] = &box{
+	teg.setBox(warriorID, &box{
 		kind:  T_item,
 		skind: 0,
 		x_item: &entity_item{
@@ -222,21 +220,21 @@ func TestDetermineUnitWeights(t *testing.T) {
 			fly_cap:  0,
 			animal:   0,
 		},
-	}
+	})
 
 	charID2 := 1002
-	teg.globals.bx[charID2] = &box{
+	teg.setBox(charID2, &box{
 		kind:  T_char,
 		skind: 0,
 		x_char: &entity_char{
 			unit_item: schar(warriorID),
 		},
-	}
+	})
 	teg.globals.inventories[charID2] = []item_ent{}
 
 	t.Run("unit with custom noble_item", func(t *testing.T) {
 		var w weights
-		determine_unit_weights(charID2, &w)
+		teg.determine_unit_weights(charID2, &w)
 
 		if w.total_weight != 20 {
 			t.Errorf("total_weight = %d, want 20 (warrior)", w.total_weight)
@@ -249,7 +247,7 @@ func TestDetermineStackWeights(t *testing.T) {
 	clearWeightsTest()
 
 	peasantID := item_peasant
-	teg.globals.bx[peasantID] = &box{
+	teg.setBox(peasantID, &box{
 		kind:  T_item,
 		skind: 0,
 		x_item: &entity_item{
@@ -259,10 +257,10 @@ func TestDetermineStackWeights(t *testing.T) {
 			fly_cap:  0,
 			animal:   0,
 		},
-	}
+	})
 
 	goldID := item_gold
-	teg.globals.bx[goldID] = &box{
+	teg.setBox(goldID, &box{
 		kind:  T_item,
 		skind: 0,
 		x_item: &entity_item{
@@ -272,37 +270,37 @@ func TestDetermineStackWeights(t *testing.T) {
 			fly_cap:  0,
 			animal:   0,
 		},
-	}
+	})
 
 	leaderID := 1001
-	teg.globals.bx[leaderID] = &box{
+	teg.setBox(leaderID, &box{
 		kind:  T_char,
 		skind: 0,
 		x_char: &entity_char{
 			unit_item: 0,
 		},
-	}
+	})
 	teg.globals.inventories[leaderID] = []item_ent{
 		{item: goldID, qty: 50},
 	}
 
 	followerID := 1002
-	teg.globals.bx[followerID] = &box{
+	teg.setBox(followerID, &box{
 		kind:  T_char,
 		skind: 0,
 		x_char: &entity_char{
 			unit_item: 0,
 		},
-	}
+	})
 	teg.globals.inventories[followerID] = []item_ent{
 		{item: goldID, qty: 30},
 	}
 
-	teg.globals.bx[leaderID].x_loc_info.here_list = []int{followerID}
+	teg.globals.bx.get(leaderID).x_loc_info.here_list = []int{followerID}
 
 	t.Run("stack with leader and follower", func(t *testing.T) {
 		var w weights
-		determine_stack_weights(leaderID, &w)
+		teg.determine_stack_weights(leaderID, &w)
 
 		expectedLeader := 10 + 50
 		expectedFollower := 10 + 30
@@ -318,7 +316,7 @@ func TestShipWeight(t *testing.T) {
 	clearWeightsTest()
 
 	peasantID := item_peasant
-	teg.globals.bx[peasantID] = &box{
+	teg.setBox(peasantID, &box{
 		kind:  T_item,
 		skind: 0,
 		x_item: &entity_item{
@@ -328,10 +326,10 @@ func TestShipWeight(t *testing.T) {
 			fly_cap:  0,
 			animal:   0,
 		},
-	}
+	})
 
 	goldID := item_gold
-	teg.globals.bx[goldID] = &box{
+	teg.setBox(goldID, &box{
 		kind:  T_item,
 		skind: 0,
 		x_item: &entity_item{
@@ -341,34 +339,34 @@ func TestShipWeight(t *testing.T) {
 			fly_cap:  0,
 			animal:   0,
 		},
-	}
+	})
 
 	shipID := 500
-	teg.globals.bx[shipID] = &box{
+	teg.setBox(shipID, &box{
 		kind:  T_ship,
 		skind: 0,
 		x_subloc: &entity_subloc{
 			capacity: 10000,
 			damage:   0,
 		},
-	}
+	})
 
 	charID := 1001
-	teg.globals.bx[charID] = &box{
+	teg.setBox(charID, &box{
 		kind:  T_char,
 		skind: 0,
 		x_char: &entity_char{
 			unit_item: 0,
 		},
-	}
+	})
 	teg.globals.inventories[charID] = []item_ent{
 		{item: goldID, qty: 100},
 	}
 
-	teg.globals.bx[shipID].x_loc_info.here_list = []int{charID}
+	teg.globals.bx.get(shipID).x_loc_info.here_list = []int{charID}
 
 	t.Run("ship with one passenger", func(t *testing.T) {
-		w := ship_weight(shipID)
+		w := teg.ship_weight(shipID)
 
 		expected := 10 + 100
 		if w != expected {
@@ -382,41 +380,41 @@ func TestShipCap(t *testing.T) {
 	clearWeightsTest()
 
 	shipID := 500
-	teg.globals.bx[shipID] = &box{
+	teg.setBox(shipID, &box{
 		kind:  T_ship,
 		skind: 0,
 		x_subloc: &entity_subloc{
 			capacity: 10000,
 			damage:   0,
 		},
-	}
+	})
 
 	t.Run("undamaged ship", func(t *testing.T) {
-		cap := ship_cap(shipID)
+		cap := teg.ship_cap(shipID)
 		if cap != 10000 {
 			t.Errorf("ship_cap = %d, want 10000", cap)
 		}
 	})
 
 	t.Run("50% damaged ship", func(t *testing.T) {
-		teg.globals.bx[shipID].x_subloc.damage = 50
-		cap := ship_cap(shipID)
+		teg.globals.bx.get(shipID).x_subloc.damage = 50
+		cap := teg.ship_cap(shipID)
 		if cap != 5000 {
 			t.Errorf("ship_cap = %d, want 5000", cap)
 		}
 	})
 
 	t.Run("25% damaged ship", func(t *testing.T) {
-		teg.globals.bx[shipID].x_subloc.damage = 25
-		cap := ship_cap(shipID)
+		teg.globals.bx.get(shipID).x_subloc.damage = 25
+		cap := teg.ship_cap(shipID)
 		if cap != 7500 {
 			t.Errorf("ship_cap = %d, want 7500", cap)
 		}
 	})
 
 	t.Run("100% damaged ship", func(t *testing.T) {
-		teg.globals.bx[shipID].x_subloc.damage = 100
-		cap := ship_cap(shipID)
+		teg.globals.bx.get(shipID).x_subloc.damage = 100
+		cap := teg.ship_cap(shipID)
 		if cap != 0 {
 			t.Errorf("ship_cap = %d, want 0", cap)
 		}
@@ -429,15 +427,15 @@ func TestShipCapRaw(t *testing.T) {
 
 	t.Run("ship with capacity", func(t *testing.T) {
 		shipID := 500
-		teg.globals.bx[shipID] = &box{
+		teg.setBox(shipID, &box{
 			kind:  T_ship,
 			skind: 0,
 			x_subloc: &entity_subloc{
 				capacity: 25000,
 			},
-		}
+		})
 
-		cap := ship_cap_raw(shipID)
+		cap := teg.ship_cap_raw(shipID)
 		if cap != 25000 {
 			t.Errorf("ship_cap_raw = %d, want 25000", cap)
 		}
@@ -445,12 +443,12 @@ func TestShipCapRaw(t *testing.T) {
 
 	t.Run("nil subloc", func(t *testing.T) {
 		emptyID := 501
-		teg.globals.bx[emptyID] = &box{
+		teg.setBox(emptyID, &box{
 			kind:  T_ship,
 			skind: 0,
-		}
+		})
 
-		cap := ship_cap_raw(emptyID)
+		cap := teg.ship_cap_raw(emptyID)
 		if cap != 0 {
 			t.Errorf("ship_cap_raw = %d, want 0", cap)
 		}
@@ -468,28 +466,28 @@ func TestLoopCharHere(t *testing.T) {
 	char2ID := 2002
 	itemID := 3001
 
-	teg.globals.bx[locID] = &box{
+	teg.setBox(locID, &box{
 		kind:  T_loc,
 		skind: 0,
-	}
-	teg.globals.bx[char1ID] = &box{
+	})
+	teg.setBox(char1ID, &box{
 		kind:  T_char,
 		skind: 0,
-	}
-	teg.globals.bx[char2ID] = &box{
+	})
+	teg.setBox(char2ID, &box{
 		kind:  T_char,
 		skind: 0,
-	}
-	teg.globals.bx[itemID] = &box{
+	})
+	teg.setBox(itemID, &box{
 		kind:  T_item,
 		skind: 0,
-	}
+	})
 
-	teg.globals.bx[locID].x_loc_info.here_list = []int{char1ID, itemID, char2ID}
+	teg.globals.bx.get(locID).x_loc_info.here_list = []int{char1ID, itemID, char2ID}
 
 	t.Run("filters characters only", func(t *testing.T) {
 		var chars []int
-		loop_char_here(locID, &chars)
+		teg.loop_char_here(locID, &chars)
 
 		if len(chars) != 2 {
 			t.Errorf("loop_char_here returned %d chars, want 2", len(chars))
@@ -0,0 +1,279 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// world.go - canonical world state, world hash and structural diff
+
+package taygete
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// WorldState is the canonical form of the world: every entity's rows
+// in the tables SaveWorld writes, which include what each player knows
+// and every substructure of the C box. Rows are formatted and sorted,
+// so two worlds that hold the same things compare equal however they
+// were built. Hashed passwords are salted, so they only show as set.
+type WorldState map[int]EntityState
+
+// EntityState maps a table name to the entity's rows in it, in order.
+type EntityState map[string][]Row
+
+// Row is one row as "column=value" fields, without the owning entity's
+// id.
+type Row []string
+
+func (r Row) String() string {
+	return strings.Join(r, " ")
+}
+
+// field returns the column name and value of field i.
+func (r Row) field(i int) (string, string) {
+	name, value, _ := strings.Cut(r[i], "=")
+	return name, value
+}
+
+// WorldState returns the canonical state of the world.
+func (e *Engine) WorldState() WorldState {
+	w := make(WorldState)
+
+	c := e.newSaveContext()
+	for _, id := range e.boxIDs() {
+		es := make(EntityState)
+		for _, g := range saveGroups {
+			for _, r := range g.rows(e, c, id) {
+				t := g.tables[r.table]
+				row := make(Row, 0, len(r.args)-1)
+				for i, a := range r.args[1:] {
+					v := canonValue(a)
+					if h, ok := a.(string); ok && t.name == "passwords" && is_password_hash(h) {
+						v = "hashed"
+					}
+					row = append(row, t.cols[i+1]+"="+v)
+				}
+				es[t.name] = append(es[t.name], row)
+			}
+		}
+		for _, rows := range es {
+			slices.SortFunc(rows, slices.Compare)
+		}
+		if len(es) != 0 {
+			w[id] = es
+		}
+	}
+
+	return w
+}
+
+// WorldHash returns the hash of the canonical world state.
+func (e *Engine) WorldHash() string {
+	return e.WorldState().Hash()
+}
+
+// canonValue formats a value bound for the database.
+func canonValue(a any) string {
+	switch v := a.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case string:
+		return strconv.Quote(v)
+	case sql.NullInt64:
+		if v.Valid {
+			return strconv.FormatInt(v.Int64, 10)
+		}
+		return "null"
+	case sql.NullString:
+		if v.Valid {
+			return strconv.Quote(v.String)
+		}
+		return "null"
+	}
+	return fmt.Sprintf("%#v", a)
+}
+
+// Hash returns a hash over every entity's hash, in id order.
+func (w WorldState) Hash() string {
+	h := sha256.New()
+	for _, id := range slices.Sorted(maps.Keys(w)) {
+		fmt.Fprintf(h, "%d %s\n", id, w.EntityHash(id))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// EntityHash returns a hash of the entity's rows, or "" if the world
+// does not hold it.
+func (w WorldState) EntityHash(id int) string {
+	es, ok := w[id]
+	if !ok {
+		return ""
+	}
+	h := sha256.New()
+	for _, t := range slices.Sorted(maps.Keys(es)) {
+		for _, row := range es[t] {
+			fmt.Fprintf(h, "%s %s\n", t, row)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// LoadWorldState returns the canonical state of the world saved in db,
+// or, when turn is not 0, of the world as it was before that turn ran.
+// db is not changed, so its schema must already be current.
+func LoadWorldState(db *sql.DB, turn int) (WorldState, error) {
+	if err := CheckSchema(db); err != nil {
+		return nil, fmt.Errorf("load world state: %w", err)
+	}
+	if turn == 0 {
+		e, err := NewEngine(db, nil)
+		if err != nil {
+			return nil, fmt.Errorf("load world state: %w", err)
+		}
+		e.logger = nil
+		if err := e.LoadWorld(); err != nil {
+			return nil, fmt.Errorf("load world state: %w", err)
+		}
+		return e.WorldState(), nil
+	}
+
+	scratch, err := OpenGameDB(":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("load turn %d: %w", turn, err)
+	}
+	defer scratch.Close()
+	// Every connection to :memory: is a new database
+	scratch.SetMaxOpenConns(1)
+
+	if err := copySnapshot(db, scratch, turn); err != nil {
+		return nil, fmt.Errorf("load turn %d: %w", turn, err)
+	}
+	e, err := NewEngine(scratch, nil)
+	if err != nil {
+		return nil, fmt.Errorf("load turn %d: %w", turn, err)
+	}
+	e.logger = nil
+	if err := e.RollbackTurn(turn); err != nil {
+		return nil, fmt.Errorf("load turn %d: %w", turn, err)
+	}
+	return e.WorldState(), nil
+}
+
+// EntityDiff lists what changed in one entity.
+type EntityDiff struct {
+	ID      int
+	Changes []FieldChange
+}
+
+// FieldChange is one changed field. Field is empty when a whole row was
+// added or removed; Old or New is then empty.
+type FieldChange struct {
+	Table    string
+	Field    string
+	Old, New string
+}
+
+func (c FieldChange) String() string {
+	switch {
+	case c.Field != "":
+		return fmt.Sprintf("%s.%s: %s -> %s", c.Table, c.Field, c.Old, c.New)
+	case c.Old == "":
+		return fmt.Sprintf("+ %s: %s", c.Table, c.New)
+	}
+	return fmt.Sprintf("- %s: %s", c.Table, c.Old)
+}
+
+func (d EntityDiff) String() string {
+	var sb strings.Builder
+	sb.WriteString(box_code_less(d.ID))
+	for _, c := range d.Changes {
+		sb.WriteString("\n  " + c.String())
+	}
+	return sb.String()
+}
+
+// DiffWorlds returns the entities that differ between a and b, in id
+// order. A table holding one row on both sides is compared field by
+// field; otherwise rows are reported as removed or added.
+func DiffWorlds(a, b WorldState) []EntityDiff {
+	ids := slices.Collect(maps.Keys(a))
+	for id := range b {
+		if _, ok := a[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	var diffs []EntityDiff
+	for _, id := range ids {
+		if a.EntityHash(id) == b.EntityHash(id) {
+			continue
+		}
+		d := EntityDiff{ID: id}
+		tables := slices.Collect(maps.Keys(a[id]))
+		for t := range b[id] {
+			if _, ok := a[id][t]; !ok {
+				tables = append(tables, t)
+			}
+		}
+		slices.Sort(tables)
+		for _, t := range tables {
+			d.Changes = append(d.Changes, diffRows(t, a[id][t], b[id][t])...)
+		}
+		diffs = append(diffs, d)
+	}
+
+	return diffs
+}
+
+// diffRows compares the rows of one table.
+func diffRows(table string, before, after []Row) []FieldChange {
+	var changes []FieldChange
+	if len(before) == 1 && len(after) == 1 && len(before[0]) == len(after[0]) {
+		for i := range before[0] {
+			name, o := before[0].field(i)
+			_, n := after[0].field(i)
+			if o != n {
+				changes = append(changes, FieldChange{Table: table, Field: name, Old: o, New: n})
+			}
+		}
+		return changes
+	}
+
+	count := make(map[string]int)
+	for _, r := range after {
+		count[r.String()]++
+	}
+	for _, r := range before {
+		if s := r.String(); count[s] > 0 {
+			count[s]--
+		} else {
+			changes = append(changes, FieldChange{Table: table, Old: s})
+		}
+	}
+	for _, r := range after {
+		if s := r.String(); count[s] > 0 {
+			count[s]--
+			changes = append(changes, FieldChange{Table: table, New: s})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,108 @@
+// taygete - a game engine for a game.
+// Copyright (c) 2026 Michael D Henderson.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package taygete
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWorldHashIgnoresOrder(t *testing.T) {
+	a := newEngineGame(t, 1, "Red Company", "Osswid")
+	b := newEngineGame(t, 1, "Red Company", "Osswid")
+	a.e.gen_item(a.who, item_lumber, 5)
+	a.e.gen_item(a.who, item_stone, 3)
+	b.e.gen_item(b.who, item_stone, 3)
+	b.e.gen_item(b.who, item_lumber, 5)
+	if got, want := a.e.WorldHash(), b.e.WorldHash(); got != want {
+		t.Fatalf("hash: got %s, want %s", got, want)
+	}
+
+	a.e.setPlayerKnowledge(a.pl, 56_760)
+	if a.e.WorldHash() == b.e.WorldHash() {
+		t.Error("hash does not cover player knowledge")
+	}
+}
+
+func TestDiffWorlds(t *testing.T) {
+	g := newEngineGame(t, 1, "Red Company", "Osswid")
+	before := g.e.WorldState()
+	g.e.p_char(g.who).health = 50
+	g.e.gen_item(g.who, item_stone, 3)
+	after := g.e.WorldState()
+
+	diffs := DiffWorlds(before, after)
+	if len(diffs) != 1 || diffs[0].ID != g.who {
+		t.Fatalf("diffs: got %v, want one for %d", diffs, g.who)
+	}
+	got := fmt.Sprint(diffs[0].Changes)
+	for _, want := range []string{
+		"characters.health: ",
+		" -> 50",
+		fmt.Sprintf("+ inventories: item_id=%d qty=3", item_stone),
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("changes: got %s, want %q", got, want)
+		}
+	}
+
+	if diffs := DiffWorlds(after, after); len(diffs) != 0 {
+		t.Errorf("diff with itself: got %v", diffs)
+	}
+}
+
+func TestLoadWorldState(t *testing.T) {
+	g := newEngineGame(t, 1, "Red Company", "Osswid")
+	e, db := g.e, g.e.db
+	if err := e.SaveWorld(); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+	want := e.WorldHash()
+	turn := int(e.globals.sysclock.turn)
+
+	w, err := LoadWorldState(db, 0)
+	if err != nil {
+		t.Fatalf("LoadWorldState: %v", err)
+	}
+	if got := w.Hash(); got != want {
+		t.Errorf("saved world: got %s, want %s", got, want)
+	}
+
+	if err := e.RunTurn(); err != nil {
+		t.Fatalf("RunTurn: %v", err)
+	}
+	w, err = LoadWorldState(db, turn)
+	if err != nil {
+		t.Fatalf("LoadWorldState(%d): %v", turn, err)
+	}
+	if got := w.Hash(); got != want {
+		t.Errorf("turn %d: got %s, want %s", turn, got, want)
+	}
+	if _, err := LoadWorldState(db, turn+1); err == nil {
+		t.Errorf("turn %d: want error for a missing snapshot", turn+1)
+	}
+
+	// A schema that isn't current is refused, not migrated
+	if _, err := db.Exec(`DELETE FROM schema_migrations WHERE version = (SELECT MAX(version) FROM schema_migrations)`); err != nil {
+		t.Fatalf("unapply migration: %v", err)
+	}
+	if _, err := LoadWorldState(db, 0); !errors.Is(err, ErrSchemaOutOfDate) {
+		t.Errorf("out of date schema: err = %v, want ErrSchemaOutOfDate", err)
+	}
+}
@@ -96,11 +96,12 @@ func IListCopy(l []int) []int {
 	return cp
 }
 
-// IListScramble performs a Fisher-Yates shuffle on the list.
-func IListScramble(l []int) {
+// IListScramble performs a Fisher-Yates shuffle on the list, drawing
+// from the named random stream.
+func (e *Engine) IListScramble(stream string, l []int) {
 	n := len(l) - 1
 	for i := 0; i < n; i++ {
-		r := rnd(i, n)
+		r := e.rndFrom(stream, i, n)
 		if r != i {
 			l[i], l[r] = l[r], l[i]
 		}
@@ -191,17 +192,6 @@ func PListCopy(l []any) []any {
 	return cp
 }
 
-// PListScramble performs a Fisher-Yates shuffle on the list.
-func PListScramble(l []any) {
-	n := len(l) - 1
-	for i := 0; i < n; i++ {
-		r := rnd(i, n)
-		if r != i {
-			l[i], l[r] = l[r], l[i]
-		}
-	}
-}
-
 // PListInsert inserts n at position pos in the list.
 func PListInsert(l *[]any, pos int, n any) {
 	*l = append(*l, nil)
@@ -138,11 +138,12 @@ func TestIListScramble(t *testing.T) {
 		logger: slog.Default(),
 		prng:   prng.New(rand.NewPCG(12_345, 67_890)),
 	}
+	teg.globals.bx = boxStore{}
 
 	l := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
 	original := IListCopy(l)
 
-	IListScramble(l)
+	teg.IListScramble(streamWeather, l)
 
 	if IListLen(l) != IListLen(original) {
 		t.Errorf("scramble changed length")